@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/easyspace-ai/luckdb/server/internal/application/basearchive"
 	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/base/entity"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/base/repository"
@@ -22,6 +23,13 @@ type BaseService struct {
 	repo       repository.BaseRepository
 	spaceRepo  spaceRepository.SpaceRepository // 用于检查父空间是否存在
 	dbProvider database.DBProvider             // ✅ 数据库提供者（Schema管理）
+	duplicator BaseDuplicator                  // 复制表格、字段、视图与记录
+}
+
+// BaseDuplicator 复制Base（由 basearchive.Service 实现）
+type BaseDuplicator interface {
+	PrepareDuplicate(ctx context.Context, req basearchive.DuplicateRequest) (*basearchive.DuplicatePlan, error)
+	Duplicate(ctx context.Context, plan *basearchive.DuplicatePlan) (*basearchive.ImportResult, error)
 }
 
 // NewBaseService 创建Base服务
@@ -33,6 +41,11 @@ func NewBaseService(repo repository.BaseRepository, spaceRepo spaceRepository.Sp
 	}
 }
 
+// SetBaseDuplicator 设置Base复制器（复制器依赖本服务创建Base，创建后注入）
+func (s *BaseService) SetBaseDuplicator(duplicator BaseDuplicator) {
+	s.duplicator = duplicator
+}
+
 // CreateBase 创建Base（严格遵守：返回AppError）
 // ✅ 完全动态表架构：创建Base时创建独立Schema
 // 严格按照旧系统实现：teable-develop/apps/nestjs-backend/src/features/base/base.service.ts
@@ -243,45 +256,41 @@ func (s *BaseService) DeleteBase(ctx context.Context, baseID string) error {
 }
 
 // DuplicateBase 复制Base
+// 在原空间新建Base并复制所有表格、字段、视图，WithRecords 时同时复制记录；
+// 关联、查找、汇总与公式引用指向副本中的表格与字段。任一步骤失败时删除已创建的Base
 func (s *BaseService) DuplicateBase(ctx context.Context, baseID string, req *dto.DuplicateBaseRequest) (*dto.BaseResponse, error) {
-	// 1. 获取原始Base
-	originalBase, err := s.repo.FindByID(ctx, baseID)
-	if err != nil {
-		return nil, errors.ErrBaseNotFound.WithDetails(fmt.Sprintf("Base不存在: %s", baseID))
-	}
-
-	// 2. 权限检查
-	// 检查用户是否有权限复制Base
+	// 1. 权限检查
+	// 基本权限检查：用户必须有Base的读取权限（由路由的权限中间件校验）
 	userID, exists := authctx.UserFrom(ctx)
 	if !exists {
 		return nil, errors.ErrUnauthorized.WithDetails("用户未认证")
 	}
-
-	// 基本权限检查：用户必须有Base的读取权限
-	// TODO: 集成PermissionService进行更细粒度的权限检查
-	// 当前实现：允许所有认证用户复制Base
-
-	// 3. 创建新的Base
-	newBaseReq := &dto.CreateBaseRequest{
-		SpaceID: originalBase.SpaceID,
-		Name:    req.Name,
-		Icon:    originalBase.Icon,
+	if s.duplicator == nil {
+		return nil, errors.ErrInternalServer.WithDetails("Base复制服务未初始化")
 	}
 
-	newBase, err := s.CreateBase(ctx, *newBaseReq, userID)
+	// 2. 校验原始Base并复制
+	plan, err := s.duplicator.PrepareDuplicate(ctx, basearchive.DuplicateRequest{
+		BaseID:      baseID,
+		Name:        req.Name,
+		WithRecords: req.WithRecords,
+		UserID:      userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.duplicator.Duplicate(ctx, plan)
 	if err != nil {
 		return nil, err
 	}
-
-	// 4. 复制Tables
-	// TODO: 实现Table复制功能
-	// 需要添加TableService依赖来复制Tables和Fields
 
 	logger.Info("✅ Base复制成功",
 		logger.String("original_base_id", baseID),
-		logger.String("new_base_id", newBase.ID))
+		logger.String("new_base_id", result.BaseID),
+		logger.Int("tables", result.Tables),
+		logger.Int("records", result.Records))
 
-	return newBase, nil
+	return s.GetBase(ctx, result.BaseID)
 }
 
 // ListBases 获取Base列表（严格遵守：返回AppError）
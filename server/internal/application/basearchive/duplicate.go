@@ -0,0 +1,314 @@
+package basearchive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/task"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// TaskTypeDuplicateBase 复制 Base 的任务类型
+const TaskTypeDuplicateBase = "duplicate_base"
+
+// 复制与导入的阶段
+const (
+	StageExporting = "exporting" // 把原 Base 导出到临时归档
+	StageSchema    = "schema"    // 创建表格、字段与视图
+	StageRecords   = "records"   // 写入记录
+	StageComputing = "computing" // 计算虚拟字段
+)
+
+// DuplicateRequest 复制 Base 请求
+type DuplicateRequest struct {
+	BaseID      string `json:"baseId"`
+	Name        string `json:"name"`        // 新 Base 名称，为空时为 "<原名称> 副本"
+	WithRecords bool   `json:"withRecords"` // 同时复制记录
+	UserID      string `json:"-"`           // 复制者，成为新 Base 与记录的创建者
+}
+
+// DuplicatePlan 复制计划：原 Base 所在空间与待复制的记录数
+type DuplicatePlan struct {
+	Request      DuplicateRequest
+	SpaceID      string
+	TotalRecords int64
+}
+
+// duplicateResult 复制进度与结果（保存在 task_run.snapshot）
+type duplicateResult struct {
+	Stage         string   `json:"stage"`
+	TotalRecords  int64    `json:"totalRecords"`
+	CopiedRecords int      `json:"copiedRecords"`
+	BaseID        string   `json:"baseId,omitempty"`
+	Warnings      []string `json:"warnings,omitempty"`
+}
+
+// DuplicateTaskStatus 复制任务状态
+type DuplicateTaskStatus struct {
+	ID            string      `json:"id"`
+	Type          string      `json:"type"`
+	Status        task.Status `json:"status"`
+	SourceBaseID  string      `json:"sourceBaseId"`
+	Name          string      `json:"name"`
+	WithRecords   bool        `json:"withRecords"`
+	Stage         string      `json:"stage,omitempty"`
+	Progress      float64     `json:"progress"` // 0-100
+	TotalRecords  int64       `json:"totalRecords"`
+	CopiedRecords int         `json:"copiedRecords"`
+	BaseID        string      `json:"baseId,omitempty"` // 新 Base，完成后返回
+	Warnings      []string    `json:"warnings,omitempty"`
+	Error         string      `json:"error,omitempty"`
+	Spent         *int        `json:"spent,omitempty"` // 耗时（毫秒）
+	CreatedBy     string      `json:"createdBy"`
+	CreatedTime   time.Time   `json:"createdTime"`
+	StartedTime   *time.Time  `json:"startedTime,omitempty"`
+}
+
+// PrepareDuplicate 校验原 Base 并统计待复制的记录数
+func (s *Service) PrepareDuplicate(ctx context.Context, req DuplicateRequest) (*DuplicatePlan, error) {
+	base, err := s.findBase(ctx, req.BaseID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		req.Name = base.Name + " 副本"
+	}
+
+	plan := &DuplicatePlan{Request: req, SpaceID: base.SpaceID}
+	if !req.WithRecords {
+		return plan, nil
+	}
+	tables, err := s.tableRepo.GetByBaseID(ctx, req.BaseID)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取表格列表失败: %v", err))
+	}
+	for _, table := range tables {
+		count, err := s.recordRepo.CountByTableID(ctx, table.ID().String())
+		if err != nil {
+			return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("统计记录数失败: %v", err))
+		}
+		plan.TotalRecords += count
+	}
+	return plan, nil
+}
+
+// ShouldDuplicateInBackground 记录数超过上限时应转为后台任务
+func (s *Service) ShouldDuplicateInBackground(plan *DuplicatePlan) bool {
+	return plan.TotalRecords > s.config.BackgroundRecordLimit
+}
+
+// Duplicate 在原空间复制 Base：表格、字段、视图与（可选的）记录
+//
+// 先把原 Base 导出到临时归档再导入，关联、查找、汇总与公式引用随之改写为新 ID。
+// 附件文件与原 Base 共用存储，记录中的附件原样保留。任一步骤失败时删除已创建的 Base
+func (s *Service) Duplicate(ctx context.Context, plan *DuplicatePlan) (*ImportResult, error) {
+	return s.duplicate(ctx, plan, nil)
+}
+
+func (s *Service) duplicate(ctx context.Context, plan *DuplicatePlan, progress func(stage string, records int)) (*ImportResult, error) {
+	file, err := os.CreateTemp("", "luckdb-duplicate-*")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	if progress != nil {
+		progress(StageExporting, 0)
+	}
+	if _, err := s.exportBase(ctx, plan.Request.BaseID, file, exportOptions{records: plan.Request.WithRecords}); err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("读取临时文件失败: %w", err)
+	}
+
+	result, err := s.importArchive(ctx, file, info.Size(), ImportRequest{
+		SpaceID: plan.SpaceID,
+		Name:    plan.Request.Name,
+		UserID:  plan.Request.UserID,
+	}, progress)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Base复制完成",
+		logger.String("source_base_id", plan.Request.BaseID),
+		logger.String("base_id", result.BaseID),
+		logger.Int("records", result.Records))
+	return result, nil
+}
+
+// StartDuplicateTask 创建复制任务并在后台复制，立即返回任务状态
+func (s *Service) StartDuplicateTask(ctx context.Context, plan *DuplicatePlan) (*DuplicateTaskStatus, error) {
+	t := &task.Task{
+		ID:        utils.GenerateIDWithPrefix(utils.TaskIDPrefix),
+		Type:      TaskTypeDuplicateBase,
+		Status:    task.StatusRunning,
+		Snapshot:  marshalJSON(plan.Request),
+		CreatedBy: plan.Request.UserID,
+	}
+	if err := s.tasks.CreateTask(ctx, t); err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("创建复制任务失败: %v", err))
+	}
+
+	now := time.Now()
+	result := &duplicateResult{Stage: StageExporting, TotalRecords: plan.TotalRecords}
+	run := &task.Run{
+		ID:          utils.GenerateIDWithPrefix(utils.TaskRunIDPrefix),
+		TaskID:      t.ID,
+		Status:      task.StatusRunning,
+		Snapshot:    marshalJSON(result),
+		StartedTime: &now,
+	}
+	if err := s.tasks.CreateRun(ctx, run); err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("创建复制任务运行记录失败: %v", err))
+	}
+
+	status := buildDuplicateStatus(t, run)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.runDuplicateTask(s.ctx, plan, t, run, result)
+	}()
+
+	logger.Info("Base复制任务已开始",
+		logger.String("task_id", t.ID),
+		logger.String("source_base_id", plan.Request.BaseID),
+		logger.Int64("records", plan.TotalRecords))
+	return status, nil
+}
+
+// runDuplicateTask 执行复制并把各阶段进度保存到任务运行记录
+func (s *Service) runDuplicateTask(ctx context.Context, plan *DuplicatePlan, t *task.Task, run *task.Run, result *duplicateResult) {
+	started := time.Now()
+	saveRun := func(ctx context.Context, status task.Status, runErr error) {
+		spent := int(time.Since(started).Milliseconds())
+		run.Status = status
+		run.Snapshot = marshalJSON(result)
+		run.Spent = &spent
+		if runErr != nil {
+			run.ErrorMsg = runErr.Error()
+		}
+		if err := s.tasks.UpdateRun(ctx, run); err != nil {
+			logger.Warn("保存复制进度失败", logger.String("task_id", t.ID), logger.ErrorField(err))
+		}
+	}
+
+	imported, runErr := s.duplicate(ctx, plan, func(stage string, records int) {
+		result.Stage = stage
+		result.CopiedRecords = records
+		saveRun(ctx, task.StatusRunning, nil)
+	})
+	if imported != nil {
+		result.BaseID = imported.BaseID
+		result.CopiedRecords = imported.Records
+		result.Warnings = imported.Warnings
+	}
+
+	status := task.StatusCompleted
+	if runErr != nil {
+		status = task.StatusFailed
+	}
+	// 服务停止时 ctx 已取消，最终状态使用独立的上下文保存
+	saveCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	saveRun(saveCtx, status, runErr)
+
+	t.Status = status
+	if err := s.tasks.UpdateTask(saveCtx, t); err != nil {
+		logger.Warn("更新复制任务状态失败", logger.String("task_id", t.ID), logger.ErrorField(err))
+	}
+
+	logger.Info("Base复制任务结束",
+		logger.String("task_id", t.ID),
+		logger.String("status", string(status)),
+		logger.Int("copied_records", result.CopiedRecords),
+		logger.Duration("elapsed", time.Since(started)))
+}
+
+// GetDuplicateTask 获取复制任务状态（只能查看自己发起的任务）
+func (s *Service) GetDuplicateTask(ctx context.Context, taskID, userID string) (*DuplicateTaskStatus, error) {
+	t, err := s.tasks.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取复制任务失败: %v", err))
+	}
+	if t == nil || t.CreatedBy != userID || t.Type != TaskTypeDuplicateBase {
+		return nil, pkgerrors.ErrNotFound.WithDetails(map[string]interface{}{
+			"resource": "duplicate_task",
+			"id":       taskID,
+		})
+	}
+
+	run, err := s.tasks.FindLatestRun(ctx, taskID)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取复制任务运行记录失败: %v", err))
+	}
+	return buildDuplicateStatus(t, run), nil
+}
+
+// buildDuplicateStatus 由任务与最近一次运行组装任务状态
+//
+// 进度按阶段估算：导出与结构各占 10%，记录写入占 70%，计算虚拟字段占最后 10%
+func buildDuplicateStatus(t *task.Task, run *task.Run) *DuplicateTaskStatus {
+	status := &DuplicateTaskStatus{
+		ID:          t.ID,
+		Type:        t.Type,
+		Status:      t.Status,
+		CreatedBy:   t.CreatedBy,
+		CreatedTime: t.CreatedTime,
+	}
+
+	var req DuplicateRequest
+	if t.Snapshot != "" && json.Unmarshal([]byte(t.Snapshot), &req) == nil {
+		status.SourceBaseID = req.BaseID
+		status.Name = req.Name
+		status.WithRecords = req.WithRecords
+	}
+
+	if run == nil {
+		return status
+	}
+	status.Error = run.ErrorMsg
+	status.Spent = run.Spent
+	status.StartedTime = run.StartedTime
+
+	var result duplicateResult
+	if run.Snapshot != "" && json.Unmarshal([]byte(run.Snapshot), &result) == nil {
+		status.Stage = result.Stage
+		status.TotalRecords = result.TotalRecords
+		status.CopiedRecords = result.CopiedRecords
+		status.BaseID = result.BaseID
+		status.Warnings = result.Warnings
+		switch result.Stage {
+		case StageSchema:
+			status.Progress = 10
+		case StageRecords:
+			status.Progress = 20
+			if result.TotalRecords > 0 {
+				status.Progress += min(float64(result.CopiedRecords)*70/float64(result.TotalRecords), 70)
+			}
+		case StageComputing:
+			status.Progress = 90
+		}
+	}
+	if t.Status == task.StatusCompleted {
+		status.Progress = 100
+	}
+	return status
+}
+
+func marshalJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,211 @@
+package basearchive
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+	"github.com/easyspace-ai/luckdb/server/pkg/authctx"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// LinkMode 复制表格时指向其他表格的关联字段的处理方式
+type LinkMode string
+
+const (
+	LinkModeKeep LinkMode = "keep" // 保留关联，指向原来的表格并在该表格中新建对称字段
+	LinkModeDrop LinkMode = "drop" // 不复制关联字段及依赖它的查找、汇总等字段
+)
+
+// ParseLinkMode 解析关联处理方式，为空时保留关联
+func ParseLinkMode(s string) (LinkMode, error) {
+	switch LinkMode(s) {
+	case "", LinkModeKeep:
+		return LinkModeKeep, nil
+	case LinkModeDrop:
+		return LinkModeDrop, nil
+	}
+	return "", fmt.Errorf("不支持的关联处理方式: %s", s)
+}
+
+// TableDuplicateRequest 复制表格请求
+type TableDuplicateRequest struct {
+	TableID     string
+	Name        string
+	WithFields  bool // 复制字段；不复制字段时视图与记录一并不复制
+	WithViews   bool
+	WithRecords bool
+	LinkMode    LinkMode
+	UserID      string
+}
+
+// TableDuplicateResult 复制表格结果
+type TableDuplicateResult struct {
+	TableID  string   `json:"tableId"`
+	Fields   int      `json:"fields"`
+	Views    int      `json:"views"`
+	Records  int      `json:"records"`
+	Warnings []string `json:"warnings,omitempty"` // 未能复制的字段或视图
+}
+
+// DuplicateTable 在同一 Base 中复制表格
+//
+// 字段、视图与记录使用新 ID：表格内的自关联指向副本，查找、汇总与公式引用改写为副本中的字段；
+// 指向其他表格的关联按 LinkMode 保留（在对方表格中新建对称字段，记录仍关联原来的记录）或丢弃。
+// 任一步骤失败时删除已创建的表格与对方表格中新建的对称字段
+func (s *Service) DuplicateTable(ctx context.Context, req TableDuplicateRequest) (*TableDuplicateResult, error) {
+	table, err := s.tableRepo.GetByID(ctx, req.TableID)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找表格失败: %v", err))
+	}
+	if table == nil {
+		return nil, pkgerrors.ErrTableNotFound.WithDetails(req.TableID)
+	}
+	base, err := s.findBase(ctx, table.BaseID())
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.CreateTemp("", "luckdb-duplicate-table-*")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	opts := exportOptions{records: req.WithFields && req.WithRecords}
+	if _, err := s.export(ctx, base, []*tableEntity.Table{table}, file, opts); err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("读取临时文件失败: %w", err)
+	}
+	files, manifest, err := s.openArchive(file, info.Size())
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &manifest.Tables[0]
+	if !req.WithFields {
+		spec.Fields = nil
+	}
+	if !req.WithFields || !req.WithViews {
+		spec.Views = nil
+	}
+	job := newImportJob(manifest, files, req.UserID, &ImportResult{BaseID: base.ID, Name: base.Name})
+	if req.LinkMode == LinkModeDrop {
+		// 丢弃的关联字段仍登记为归档字段但不映射，依赖它们的计算字段因依赖无法满足而跳过
+		for _, field := range dropExternalLinks(spec) {
+			job.specs[field.ID] = field
+		}
+	} else if err := s.keepExternalLinks(ctx, job, spec); err != nil {
+		return nil, err
+	}
+
+	ctx = authctx.WithUser(ctx, req.UserID)
+	created, err := s.tables.CreateEmptyTable(ctx, base.ID, req.Name, spec.Description, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+	job.ids.tables[spec.ID] = created.ID
+	job.result.Tables++
+	for _, field := range spec.Fields {
+		job.specs[field.ID] = field
+	}
+
+	err = s.mapRecordIDs(job)
+	if err == nil {
+		err = s.importContent(ctx, job)
+	}
+	if err != nil {
+		s.dropDuplicatedTable(context.WithoutCancel(ctx), job, spec, created.ID)
+		return nil, err
+	}
+
+	logger.Info("表格复制完成",
+		logger.String("source_table_id", req.TableID),
+		logger.String("table_id", created.ID),
+		logger.String("link_mode", string(req.LinkMode)),
+		logger.Int("records", job.result.Records),
+		logger.Int("warnings", len(job.result.Warnings)))
+	return &TableDuplicateResult{
+		TableID:  created.ID,
+		Fields:   job.result.Fields,
+		Views:    job.result.Views,
+		Records:  job.result.Records,
+		Warnings: job.result.Warnings,
+	}, nil
+}
+
+// dropExternalLinks 去掉指向其他表格的关联字段，返回被去掉的字段
+func dropExternalLinks(spec *TableSpec) []FieldSpec {
+	var dropped []FieldSpec
+	fields := spec.Fields[:0]
+	for _, field := range spec.Fields {
+		if field.Type == fieldValueObject.TypeLink && field.Options != nil && field.Options.Link != nil &&
+			field.Options.Link.LinkedTableID != spec.ID {
+			dropped = append(dropped, field)
+			continue
+		}
+		fields = append(fields, field)
+	}
+	spec.Fields = fields
+	return dropped
+}
+
+// keepExternalLinks 关联指向的其他表格映射为自身：关联、查找与汇总中引用的对方字段与视图保持不变
+func (s *Service) keepExternalLinks(ctx context.Context, job *importJob, spec *TableSpec) error {
+	for _, field := range spec.Fields {
+		if field.Type != fieldValueObject.TypeLink || field.Options == nil || field.Options.Link == nil {
+			continue
+		}
+		foreignID := field.Options.Link.LinkedTableID
+		if foreignID == spec.ID || job.external[foreignID] {
+			continue
+		}
+		job.external[foreignID] = true
+		job.ids.tables[foreignID] = foreignID
+
+		fields, err := s.fieldRepo.FindByTableID(ctx, foreignID)
+		if err != nil {
+			return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取关联表格的字段失败: %v", err))
+		}
+		for _, f := range fields {
+			job.ids.fields[f.ID().String()] = f.ID().String()
+		}
+		views, err := s.viewRepo.FindByTableID(ctx, foreignID)
+		if err != nil {
+			return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取关联表格的视图失败: %v", err))
+		}
+		for _, v := range views {
+			job.ids.views[v.ID()] = v.ID()
+		}
+	}
+	return nil
+}
+
+// dropDuplicatedTable 复制失败时删除副本：先删除指向其他表格的关联字段（连同对方表格中的对称字段），再删除表格
+func (s *Service) dropDuplicatedTable(ctx context.Context, job *importJob, spec *TableSpec, tableID string) {
+	for _, field := range spec.Fields {
+		if field.Type != fieldValueObject.TypeLink || field.Options == nil || field.Options.Link == nil ||
+			!job.external[field.Options.Link.LinkedTableID] {
+			continue
+		}
+		if id, ok := job.ids.fields[field.ID]; ok {
+			if err := s.fields.DeleteField(ctx, id); err != nil {
+				logger.Warn("复制表格失败后删除关联字段失败",
+					logger.String("field_id", id),
+					logger.ErrorField(err))
+			}
+		}
+	}
+	if err := s.tables.DeleteTable(ctx, tableID); err != nil {
+		logger.Warn("复制表格失败后删除表格失败",
+			logger.String("table_id", tableID),
+			logger.ErrorField(err))
+	}
+}
@@ -0,0 +1,346 @@
+package basearchive
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	baseEntity "github.com/easyspace-ai/luckdb/server/internal/domain/base/entity"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// FileName 归档文件名（同时校验 Base 是否存在，流式响应写出前调用）
+func (s *Service) FileName(ctx context.Context, baseID string) (string, error) {
+	base, err := s.findBase(ctx, baseID)
+	if err != nil {
+		return "", err
+	}
+	return archiveFileName(base.Name), nil
+}
+
+// exportOptions 导出内容选项
+type exportOptions struct {
+	records         bool // 写出记录
+	attachmentFiles bool // 写出附件文件与附件索引
+}
+
+// Export 把 Base 导出为 zip 归档写入 w
+//
+// 记录与附件先于清单写出：清单中的记录数与附件索引在写出记录时才能确定
+func (s *Service) Export(ctx context.Context, baseID string, w io.Writer) (*Manifest, error) {
+	return s.exportBase(ctx, baseID, w, exportOptions{records: true, attachmentFiles: true})
+}
+
+// exportBase 导出 Base 的所有表格
+func (s *Service) exportBase(ctx context.Context, baseID string, w io.Writer, opts exportOptions) (*Manifest, error) {
+	base, err := s.findBase(ctx, baseID)
+	if err != nil {
+		return nil, err
+	}
+	tables, err := s.tableRepo.GetByBaseID(ctx, baseID)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取表格列表失败: %v", err))
+	}
+	return s.export(ctx, base, tables, w, opts)
+}
+
+// export 导出 Base 中的指定表格
+func (s *Service) export(ctx context.Context, base *baseEntity.Base, tables []*tableEntity.Table, w io.Writer, opts exportOptions) (*Manifest, error) {
+	var err error
+
+	manifest := &Manifest{
+		Version:     FormatVersion,
+		ExportedAt:  time.Now().UTC(),
+		Base:        BaseSpec{ID: base.ID, Name: base.Name, Icon: base.Icon},
+		Tables:      make([]TableSpec, 0, len(tables)),
+		Links:       []LinkSpec{},
+		Attachments: []AttachmentSpec{},
+	}
+
+	zw := zip.NewWriter(w)
+	index := newAttachmentIndex()
+	for _, table := range tables {
+		spec, fields, err := s.exportSchema(ctx, table)
+		if err != nil {
+			return nil, err
+		}
+		if opts.records {
+			spec.RecordCount, err = s.exportRecords(ctx, zw, spec.ID, fields, index)
+			if err != nil {
+				return nil, err
+			}
+		}
+		manifest.Tables = append(manifest.Tables, *spec)
+		manifest.Links = append(manifest.Links, linkSpecs(spec)...)
+	}
+
+	if opts.attachmentFiles {
+		if manifest.Attachments, err = s.exportAttachments(ctx, zw, index); err != nil {
+			return nil, err
+		}
+	}
+
+	entry, err := zw.Create(manifestFile)
+	if err != nil {
+		return nil, err
+	}
+	encoder := json.NewEncoder(entry)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// findBase 查找 Base，不存在时返回 NotFound
+func (s *Service) findBase(ctx context.Context, baseID string) (*baseEntity.Base, error) {
+	base, err := s.baseRepo.FindByID(ctx, baseID)
+	if err == gorm.ErrRecordNotFound || (err == nil && base == nil) {
+		return nil, pkgerrors.ErrNotFound.WithDetails(map[string]interface{}{
+			"resource": "base",
+			"id":       baseID,
+		})
+	}
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找Base失败: %v", err))
+	}
+	return base, nil
+}
+
+// exportSchema 导出表格的字段与视图
+func (s *Service) exportSchema(ctx context.Context, table *tableEntity.Table) (*TableSpec, []*fieldEntity.Field, error) {
+	tableID := table.ID().String()
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取字段列表失败: %v", err))
+	}
+	views, err := s.viewRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取视图列表失败: %v", err))
+	}
+
+	spec := &TableSpec{
+		ID:     tableID,
+		Name:   table.Name().String(),
+		Fields: make([]FieldSpec, 0, len(fields)),
+		Views:  make([]ViewSpec, 0, len(views)),
+	}
+	if table.Description() != nil {
+		spec.Description = *table.Description()
+	}
+
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].Order() < fields[j].Order() })
+	primaryID := ""
+	for _, field := range fields {
+		if !field.IsVirtual() {
+			primaryID = field.ID().String()
+			break
+		}
+	}
+	for _, field := range fields {
+		fieldSpec := FieldSpec{
+			ID:        field.ID().String(),
+			Name:      field.Name().String(),
+			Type:      field.Type().String(),
+			Required:  field.IsRequired(),
+			Unique:    field.IsUnique(),
+			NotNull:   field.NotNull(),
+			IsPrimary: field.ID().String() == primaryID,
+			Options:   field.Options(),
+		}
+		if field.Description() != nil {
+			fieldSpec.Description = *field.Description()
+		}
+		spec.Fields = append(spec.Fields, fieldSpec)
+	}
+
+	sort.SliceStable(views, func(i, j int) bool { return views[i].Order() < views[j].Order() })
+	for _, view := range views {
+		viewSpec := ViewSpec{
+			ID:          view.ID(),
+			Name:        view.Name(),
+			Type:        string(view.ViewType()),
+			Description: view.Description(),
+			Filter:      view.Filter(),
+			Sort:        view.Sort(),
+			Group:       view.Group(),
+			Options:     view.Options(),
+			Order:       view.Order(),
+		}
+		if view.ColumnMeta() != nil {
+			viewSpec.ColumnMeta = view.ColumnMeta().Columns
+		}
+		spec.Views = append(spec.Views, viewSpec)
+	}
+	return spec, fields, nil
+}
+
+// exportRecords 按键集分页逐页写出表格记录，返回记录数
+func (s *Service) exportRecords(ctx context.Context, zw *zip.Writer, tableID string, fields []*fieldEntity.Field, index *attachmentIndex) (int, error) {
+	entry, err := zw.Create(recordsEntry(tableID))
+	if err != nil {
+		return 0, err
+	}
+	buffered := bufio.NewWriter(entry)
+	encoder := json.NewEncoder(buffered)
+
+	var attachmentFields []string
+	for _, field := range fields {
+		if field.Type().String() == fieldValueObject.TypeAttachment {
+			attachmentFields = append(attachmentFields, field.ID().String())
+		}
+	}
+
+	filter := recordRepo.RecordFilter{TableID: &tableID, Limit: s.config.PageSize}
+	count := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+		page, err := s.recordRepo.ListPage(ctx, filter)
+		if err != nil {
+			if appErr, ok := pkgerrors.IsAppError(err); ok {
+				return count, appErr
+			}
+			return count, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查询记录失败: %v", err))
+		}
+		for _, record := range page.Records {
+			data := record.Data().ToMap()
+			for _, fieldID := range attachmentFields {
+				index.collect(data[fieldID])
+			}
+			if err := encoder.Encode(recordLine{
+				ID:          record.ID().String(),
+				Fields:      data,
+				CreatedTime: record.CreatedAt(),
+			}); err != nil {
+				return count, err
+			}
+			count++
+		}
+		if page.NextCursor == nil || len(page.Records) == 0 {
+			break
+		}
+		filter.Cursor = page.NextCursor
+	}
+	return count, buffered.Flush()
+}
+
+// exportAttachments 把记录中引用的附件文件写入归档，存储中缺失的文件只记录索引
+func (s *Service) exportAttachments(ctx context.Context, zw *zip.Writer, index *attachmentIndex) ([]AttachmentSpec, error) {
+	specs := make([]AttachmentSpec, 0, len(index.order))
+	for _, path := range index.order {
+		spec := index.items[path]
+		if s.storage == nil {
+			spec.Missing = true
+			specs = append(specs, spec)
+			continue
+		}
+		download, err := s.storage.Download(ctx, path)
+		if err != nil {
+			logger.Warn("导出归档时附件文件不存在，已跳过",
+				logger.String("path", path),
+				logger.ErrorField(err))
+			spec.Missing = true
+			specs = append(specs, spec)
+			continue
+		}
+		entry, err := zw.Create(attachmentsDir + path)
+		if err == nil {
+			spec.Size, err = io.Copy(entry, download.Reader)
+		}
+		download.Reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("写出附件 %s 失败: %w", path, err)
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// linkSpecs 表格中的关联关系
+func linkSpecs(table *TableSpec) []LinkSpec {
+	var links []LinkSpec
+	for _, field := range table.Fields {
+		if field.Type != fieldValueObject.TypeLink || field.Options == nil || field.Options.Link == nil {
+			continue
+		}
+		links = append(links, LinkSpec{
+			FieldID:          field.ID,
+			TableID:          table.ID,
+			LinkedTableID:    field.Options.Link.LinkedTableID,
+			SymmetricFieldID: field.Options.Link.SymmetricFieldID,
+			Relationship:     field.Options.Link.Relationship,
+		})
+	}
+	return links
+}
+
+// attachmentIndex 按首次出现顺序收集记录中引用的附件（按存储路径去重）
+type attachmentIndex struct {
+	order []string
+	items map[string]AttachmentSpec
+}
+
+func newAttachmentIndex() *attachmentIndex {
+	return &attachmentIndex{items: make(map[string]AttachmentSpec)}
+}
+
+// collect 收集附件单元格中的附件，单元格为附件对象数组
+func (idx *attachmentIndex) collect(value interface{}) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return
+	}
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path, _ := m["path"].(string)
+		if path == "" || strings.Contains(path, "..") {
+			continue
+		}
+		if _, exists := idx.items[path]; exists {
+			continue
+		}
+		spec := AttachmentSpec{Path: path}
+		spec.Name, _ = m["name"].(string)
+		spec.MimeType, _ = m["mimetype"].(string)
+		spec.Token, _ = m["token"].(string)
+		if size, ok := m["size"].(float64); ok {
+			spec.Size = int64(size)
+		}
+		idx.order = append(idx.order, path)
+		idx.items[path] = spec
+	}
+}
+
+// archiveFileName 归档文件名：Base 名称去掉路径分隔符等不安全字符
+func archiveFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < 0x20 {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" {
+		name = "base"
+	}
+	return name + archiveFileSuffix
+}
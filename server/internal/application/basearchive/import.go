@@ -0,0 +1,731 @@
+package basearchive
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	tableService "github.com/easyspace-ai/luckdb/server/internal/domain/table/service"
+	"github.com/easyspace-ai/luckdb/server/internal/events"
+	"github.com/easyspace-ai/luckdb/server/pkg/authctx"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// referenceTypes 引用其他字段的计算字段，在所引用字段创建后才能创建
+var referenceTypes = map[string]bool{
+	fieldValueObject.TypeLookup:  true,
+	fieldValueObject.TypeRollup:  true,
+	fieldValueObject.TypeCount:   true,
+	fieldValueObject.TypeFormula: true,
+}
+
+// derivedTypes 值由系统计算的字段，导入记录时不写入归档中的值
+var derivedTypes = map[string]bool{
+	fieldValueObject.TypeLookup:           true,
+	fieldValueObject.TypeRollup:           true,
+	fieldValueObject.TypeCount:            true,
+	fieldValueObject.TypeFormula:          true,
+	fieldValueObject.TypeAutoNumber:       true,
+	fieldValueObject.TypeCreatedTime:      true,
+	fieldValueObject.TypeCreatedBy:        true,
+	fieldValueObject.TypeLastModifiedTime: true,
+	fieldValueObject.TypeLastModifiedBy:   true,
+	fieldValueObject.TypeButton:           true,
+}
+
+// ImportRequest 归档导入请求
+type ImportRequest struct {
+	SpaceID string // 目标空间
+	Name    string // 新 Base 名称，为空时沿用归档中的名称
+	UserID  string // 导入者，成为新 Base 与记录的创建者
+}
+
+// ImportResult 归档导入结果
+type ImportResult struct {
+	BaseID      string   `json:"baseId"`
+	Name        string   `json:"name"`
+	Tables      int      `json:"tables"`
+	Fields      int      `json:"fields"`
+	Views       int      `json:"views"`
+	Records     int      `json:"records"`
+	Attachments int      `json:"attachments"`
+	Warnings    []string `json:"warnings,omitempty"` // 未能还原的字段、视图或附件
+}
+
+func (r *ImportResult) warn(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// importJob 一次导入的上下文
+type importJob struct {
+	manifest *Manifest
+	files    map[string]*zip.File
+	ids      *idMap
+	specs    map[string]FieldSpec // 归档字段ID -> 字段结构
+	external map[string]bool      // 不在归档中、关联保留指向原表格的表格（复制表格时）
+	partners map[string]bool      // 随关联字段自动创建的对称字段（归档字段ID），单元格由外键同步写入
+	userID   string
+	result   *ImportResult
+	progress func(stage string, records int) // 可选：阶段与已写入记录数
+}
+
+// report 报告导入进度
+func (job *importJob) report(stage string) {
+	if job.progress != nil {
+		job.progress(stage, job.result.Records)
+	}
+}
+
+// Import 从归档在目标空间下新建 Base
+//
+// 依次创建表格、普通字段、关联字段（对称字段由字段服务自动创建后改回归档中的名称）、计算字段、视图、
+// 附件与记录。任一步骤失败时删除已创建的 Base；无法还原的单个字段或视图记入 Warnings 后继续
+func (s *Service) Import(ctx context.Context, r io.ReaderAt, size int64, req ImportRequest) (*ImportResult, error) {
+	return s.importArchive(ctx, r, size, req, nil)
+}
+
+func (s *Service) importArchive(ctx context.Context, r io.ReaderAt, size int64, req ImportRequest, progress func(stage string, records int)) (*ImportResult, error) {
+	files, manifest, err := s.openArchive(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = authctx.WithUser(ctx, req.UserID)
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = manifest.Base.Name
+	}
+	base, err := s.bases.CreateBase(ctx, dto.CreateBaseRequest{
+		Name:    name,
+		Icon:    manifest.Base.Icon,
+		SpaceID: req.SpaceID,
+	}, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	job := newImportJob(manifest, files, req.UserID, &ImportResult{BaseID: base.ID, Name: base.Name})
+	job.progress = progress
+	if err := s.runImport(ctx, job, base.ID); err != nil {
+		// 导入被取消时 ctx 已失效，删除使用不随之取消的上下文
+		if delErr := s.bases.DeleteBase(context.WithoutCancel(ctx), base.ID); delErr != nil {
+			logger.Warn("归档导入失败后删除Base失败",
+				logger.String("base_id", base.ID),
+				logger.ErrorField(delErr))
+		}
+		return nil, err
+	}
+
+	logger.Info("归档导入完成",
+		logger.String("base_id", base.ID),
+		logger.String("source_base_id", manifest.Base.ID),
+		logger.Int("tables", job.result.Tables),
+		logger.Int("records", job.result.Records),
+		logger.Int("warnings", len(job.result.Warnings)))
+	return job.result, nil
+}
+
+func newImportJob(manifest *Manifest, files map[string]*zip.File, userID string, result *ImportResult) *importJob {
+	return &importJob{
+		manifest: manifest,
+		files:    files,
+		ids:      newIDMap(),
+		specs:    make(map[string]FieldSpec),
+		external: make(map[string]bool),
+		partners: make(map[string]bool),
+		userID:   userID,
+		result:   result,
+	}
+}
+
+// openArchive 读取归档文件索引与清单
+func (s *Service) openArchive(r io.ReaderAt, size int64) (map[string]*zip.File, *Manifest, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, nil, pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("无效的归档文件: %v", err))
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+	manifest, err := s.readManifest(files)
+	if err != nil {
+		return nil, nil, err
+	}
+	return files, manifest, nil
+}
+
+func (s *Service) runImport(ctx context.Context, job *importJob, baseID string) error {
+	// 记录ID先行映射：关联单元格与视图过滤值引用其他表格的记录
+	if err := s.mapRecordIDs(job); err != nil {
+		return err
+	}
+	job.report(StageSchema)
+	for _, table := range job.manifest.Tables {
+		created, err := s.tables.CreateEmptyTable(ctx, baseID, table.Name, table.Description, job.userID)
+		if err != nil {
+			return err
+		}
+		job.ids.tables[table.ID] = created.ID
+		job.result.Tables++
+		for _, field := range table.Fields {
+			job.specs[field.ID] = field
+		}
+	}
+	return s.importContent(ctx, job)
+}
+
+// importContent 在已创建的表格中依次创建字段、视图、附件与记录
+func (s *Service) importContent(ctx context.Context, job *importJob) error {
+	if err := s.importFields(ctx, job); err != nil {
+		return err
+	}
+	s.importViews(ctx, job)
+	if err := s.importAttachments(ctx, job); err != nil {
+		return err
+	}
+	job.report(StageRecords)
+	if err := s.importRecords(ctx, job); err != nil {
+		return err
+	}
+	if err := s.deriveLinks(ctx, job); err != nil {
+		return err
+	}
+	job.report(StageComputing)
+	s.afterImport(ctx, job)
+	return nil
+}
+
+// readManifest 读取并校验清单
+func (s *Service) readManifest(files map[string]*zip.File) (*Manifest, error) {
+	file := files[manifestFile]
+	if file == nil {
+		return nil, pkgerrors.ErrBadRequest.WithDetails("归档中缺少 " + manifestFile)
+	}
+	if int64(file.UncompressedSize64) > s.config.MaxManifestSize {
+		return nil, pkgerrors.ErrBadRequest.WithDetails("归档清单过大")
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return nil, pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("读取归档清单失败: %v", err))
+	}
+	defer rc.Close()
+
+	var manifest Manifest
+	if err := json.NewDecoder(io.LimitReader(rc, s.config.MaxManifestSize)).Decode(&manifest); err != nil {
+		return nil, pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("解析归档清单失败: %v", err))
+	}
+	if manifest.Version < 1 || manifest.Version > FormatVersion {
+		return nil, pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("不支持的归档版本: %d", manifest.Version))
+	}
+	if strings.TrimSpace(manifest.Base.Name) == "" {
+		manifest.Base.Name = "Imported Base"
+	}
+	return &manifest, nil
+}
+
+// importFields 按依赖顺序创建字段
+func (s *Service) importFields(ctx context.Context, job *importJob) error {
+	// 1. 普通字段（保持归档中的顺序，主字段仍为第一个）
+	for _, table := range job.manifest.Tables {
+		for _, spec := range table.Fields {
+			if spec.Type == fieldValueObject.TypeLink || referenceTypes[spec.Type] {
+				continue
+			}
+			options, err := job.ids.remapFieldOptions(spec.Options)
+			if err != nil {
+				return err
+			}
+			if _, err := s.createField(ctx, job, table, spec, options); err != nil {
+				return err
+			}
+		}
+	}
+
+	// 2. 关联字段：对称字段随主字段自动创建，之后改回归档中的名称与设置
+	for _, table := range job.manifest.Tables {
+		for _, spec := range table.Fields {
+			if spec.Type != fieldValueObject.TypeLink {
+				continue
+			}
+			if err := s.importLinkField(ctx, job, table, spec); err != nil {
+				return err
+			}
+		}
+	}
+
+	// 3. 计算字段：多轮创建，直到剩余字段的依赖都无法满足
+	type pendingField struct {
+		table TableSpec
+		spec  FieldSpec
+	}
+	var pending []pendingField
+	archived := make(map[string]bool, len(job.specs))
+	for id := range job.specs {
+		archived[id] = true
+	}
+	for _, table := range job.manifest.Tables {
+		for _, spec := range table.Fields {
+			if referenceTypes[spec.Type] {
+				pending = append(pending, pendingField{table: table, spec: spec})
+			}
+		}
+	}
+	for len(pending) > 0 {
+		var next []pendingField
+		for _, item := range pending {
+			ready := true
+			for _, dep := range fieldDependencies(item.spec, archived) {
+				if _, ok := job.ids.fields[dep]; !ok {
+					ready = false
+					break
+				}
+			}
+			if !ready {
+				next = append(next, item)
+				continue
+			}
+			options, err := job.ids.remapFieldOptions(item.spec.Options)
+			if err == nil {
+				_, err = s.createField(ctx, job, item.table, item.spec, options)
+			}
+			if err != nil {
+				job.result.warn("字段 %s.%s 创建失败，已跳过: %v", item.table.Name, item.spec.Name, err)
+			}
+		}
+		if len(next) == len(pending) {
+			for _, item := range next {
+				job.result.warn("字段 %s.%s 引用的字段未能导入，已跳过", item.table.Name, item.spec.Name)
+			}
+			break
+		}
+		pending = next
+	}
+	return nil
+}
+
+// importLinkField 创建关联字段，并映射自动创建的对称字段
+func (s *Service) importLinkField(ctx context.Context, job *importJob, table TableSpec, spec FieldSpec) error {
+	if _, done := job.ids.fields[spec.ID]; done {
+		return nil // 已作为其他关联字段的对称字段创建
+	}
+	if spec.Options == nil || spec.Options.Link == nil {
+		job.result.warn("关联字段 %s.%s 缺少关联配置，已跳过", table.Name, spec.Name)
+		return nil
+	}
+	link := spec.Options.Link
+	if _, ok := job.ids.tables[link.LinkedTableID]; !ok {
+		job.result.warn("关联字段 %s.%s 指向归档外的表格，已跳过", table.Name, spec.Name)
+		return nil
+	}
+	external := job.external[link.LinkedTableID]
+
+	options, err := job.ids.remapFieldOptions(spec.Options)
+	if err != nil {
+		return err
+	}
+	partner, partnerArchived := job.specs[link.SymmetricFieldID]
+	_, partnerCreated := job.ids.fields[link.SymmetricFieldID]
+	// 对称字段不在归档中（或已单独创建）时按单向关联创建，避免多出归档中没有的字段；
+	// 关联保留指向原表格时在原表格中新建对称字段
+	options.Link.IsSymmetric = link.IsSymmetric && (external || (partnerArchived && !partnerCreated))
+
+	field, err := s.createField(ctx, job, table, spec, options)
+	if err != nil {
+		return err
+	}
+	if !options.Link.IsSymmetric || external {
+		return nil
+	}
+
+	symmetricID := ""
+	if field.Options() != nil && field.Options().Link != nil {
+		symmetricID = field.Options().Link.SymmetricFieldID
+	}
+	if symmetricID == "" {
+		job.result.warn("关联字段 %s.%s 的对称字段未能自动创建", table.Name, spec.Name)
+		return nil
+	}
+	job.ids.fields[partner.ID] = symmetricID
+	job.partners[partner.ID] = true
+	job.result.Fields++
+
+	update := dto.UpdateFieldRequest{
+		Name:     &partner.Name,
+		Required: &partner.Required,
+		Unique:   &partner.Unique,
+	}
+	if partner.Description != "" {
+		update.Description = &partner.Description
+	}
+	if _, err := s.fields.UpdateField(ctx, symmetricID, update); err != nil {
+		job.result.warn("对称字段 %s 还原名称失败: %v", partner.Name, err)
+	}
+	return nil
+}
+
+// createField 创建字段并记录 ID 映射
+func (s *Service) createField(ctx context.Context, job *importJob, table TableSpec, spec FieldSpec, options *fieldValueObject.FieldOptions) (*fieldEntity.Field, error) {
+	field, err := s.fields.CreateFieldWithOptions(ctx, job.ids.tables[table.ID], spec.Name, spec.Type,
+		options, spec.Required, spec.Unique, spec.NotNull, job.userID)
+	if err != nil {
+		return nil, err
+	}
+	newID := field.ID().String()
+	job.ids.fields[spec.ID] = newID
+	job.result.Fields++
+
+	if spec.Description != "" {
+		description := spec.Description
+		if _, err := s.fields.UpdateField(ctx, newID, dto.UpdateFieldRequest{Description: &description}); err != nil {
+			job.result.warn("字段 %s.%s 还原描述失败: %v", table.Name, spec.Name, err)
+		}
+	}
+	return field, nil
+}
+
+// importViews 按归档顺序创建视图，视图中引用的字段改写为新 ID
+func (s *Service) importViews(ctx context.Context, job *importJob) {
+	for _, table := range job.manifest.Tables {
+		for _, view := range table.Views {
+			req := dto.CreateViewRequest{
+				TableID:     job.ids.tables[table.ID],
+				Name:        view.Name,
+				Description: view.Description,
+				Type:        view.Type,
+				Sort:        job.ids.remapSort(view.Sort),
+				Group:       job.ids.remapGroup(view.Group),
+				ColumnMeta:  job.ids.remapColumnMeta(view.ColumnMeta),
+			}
+			if filter := job.ids.remapFilter(view.Filter); filter != nil && (len(filter.Filters) > 0 || len(filter.Groups) > 0) {
+				req.Filter = toMap(filter)
+			}
+			if view.Options != nil {
+				req.Options, _ = job.ids.remapValue(view.Options).(map[string]interface{})
+			}
+			created, err := s.views.CreateView(ctx, req, job.userID)
+			if err != nil {
+				job.result.warn("视图 %s.%s 创建失败，已跳过: %v", table.Name, view.Name, err)
+				continue
+			}
+			job.ids.views[view.ID] = created.ID
+			job.result.Views++
+		}
+	}
+}
+
+// importAttachments 把附件文件保存到存储中的原路径并登记附件，已存在的文件与登记保持不变
+func (s *Service) importAttachments(ctx context.Context, job *importJob) error {
+	for _, spec := range job.manifest.Attachments {
+		if spec.Missing {
+			job.result.warn("附件 %s 在导出时已缺失", spec.Name)
+			continue
+		}
+		file := job.files[attachmentsDir+spec.Path]
+		if file == nil || !safePath(spec.Path) {
+			job.result.warn("附件 %s 不在归档中，已跳过", spec.Name)
+			continue
+		}
+
+		if s.storage != nil {
+			exists, err := s.storage.Exists(ctx, spec.Path)
+			if err != nil || !exists {
+				if err := s.uploadAttachment(ctx, file, spec); err != nil {
+					return err
+				}
+			}
+		}
+		if s.attachments != nil {
+			if item, err := s.attachments.GetAttachmentByPath(ctx, spec.Path); err != nil || item == nil {
+				item := attachment.NewAttachmentItem(spec.Name, spec.Path, spec.Token, spec.MimeType, spec.Size)
+				if err := s.attachments.CreateAttachment(ctx, item); err != nil {
+					job.result.warn("附件 %s 登记失败: %v", spec.Name, err)
+				}
+			}
+		}
+		job.result.Attachments++
+	}
+	return nil
+}
+
+func (s *Service) uploadAttachment(ctx context.Context, file *zip.File, spec AttachmentSpec) error {
+	rc, err := file.Open()
+	if err != nil {
+		return pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("读取附件 %s 失败: %v", spec.Path, err))
+	}
+	defer rc.Close()
+	if _, err := s.storage.Upload(ctx, attachment.UploadRequest{
+		Path:        spec.Path,
+		Reader:      rc,
+		Size:        int64(file.UncompressedSize64),
+		ContentType: spec.MimeType,
+		Options:     attachment.UploadOptions{CreateDir: true},
+	}); err != nil {
+		return fmt.Errorf("保存附件 %s 失败: %w", spec.Path, err)
+	}
+	return nil
+}
+
+// mapRecordIDs 为归档中的所有记录生成新 ID
+func (s *Service) mapRecordIDs(job *importJob) error {
+	for _, table := range job.manifest.Tables {
+		err := s.scanRecords(job, table.ID, func(line *recordLine) error {
+			job.ids.records[line.ID] = utils.GenerateRecordID()
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importRecords 逐表分批写入记录：计算字段的值不写入，关联单元格改写为新记录ID
+func (s *Service) importRecords(ctx context.Context, job *importJob) error {
+	for _, table := range job.manifest.Tables {
+		tableID := job.ids.tables[table.ID]
+		var batch []*recordEntity.Record
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			if err := s.writer.BatchCreateRecords(ctx, tableID, batch); err != nil {
+				return err
+			}
+			job.result.Records += len(batch)
+			batch = batch[:0]
+			job.report(StageRecords)
+			return nil
+		}
+
+		err := s.scanRecords(job, table.ID, func(line *recordLine) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			record, err := s.buildRecord(job, tableID, line)
+			if err != nil {
+				return err
+			}
+			batch = append(batch, record)
+			if len(batch) >= s.config.ChunkSize {
+				return flush()
+			}
+			return nil
+		})
+		if err == nil {
+			err = flush()
+		}
+		if err != nil {
+			return fmt.Errorf("导入表格 %s 的记录失败: %w", table.Name, err)
+		}
+	}
+	return nil
+}
+
+// buildRecord 归档记录转为新表格中的记录
+func (s *Service) buildRecord(job *importJob, tableID string, line *recordLine) (*recordEntity.Record, error) {
+	values := make(map[string]interface{}, len(line.Fields))
+	for oldID, value := range line.Fields {
+		newID, ok := job.ids.fields[oldID]
+		spec := job.specs[oldID]
+		if !ok || derivedTypes[spec.Type] || value == nil {
+			continue
+		}
+		if spec.Type == fieldValueObject.TypeLink {
+			if s.linker != nil && job.partners[oldID] {
+				continue // 由关联字段的外键同步写入
+			}
+			value = job.remapLinkValue(spec, value)
+		}
+		values[newID] = value
+	}
+	data, err := recordValueObject.NewRecordData(values)
+	if err != nil {
+		return nil, err
+	}
+	version, err := recordValueObject.NewRecordVersion(1)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	createdAt := line.CreatedTime
+	if createdAt.IsZero() {
+		createdAt = now
+	}
+	return recordEntity.ReconstructRecord(
+		recordValueObject.NewRecordID(job.ids.records[line.ID]),
+		tableID, data, version, job.userID, job.userID, createdAt, now, nil,
+	), nil
+}
+
+// remapLinkValue 改写关联单元格：指向保留关联的原表格时记录ID不变，否则改写为新记录ID
+func (job *importJob) remapLinkValue(spec FieldSpec, value interface{}) interface{} {
+	if spec.Options != nil && spec.Options.Link != nil && job.external[spec.Options.Link.LinkedTableID] {
+		return value
+	}
+	return job.ids.remapLinkValue(value)
+}
+
+// deriveLinks 所有记录写入后按关联字段写入外键（junction 表或外键列）并同步对称字段的单元格
+//
+// 对称字段一侧的单元格在写入记录时已跳过，由这里按关联字段一侧补齐，两侧只处理一次
+func (s *Service) deriveLinks(ctx context.Context, job *importJob) error {
+	if s.linker == nil {
+		return nil
+	}
+	for _, table := range job.manifest.Tables {
+		var links []FieldSpec
+		for _, spec := range table.Fields {
+			if _, ok := job.ids.fields[spec.ID]; ok && spec.Type == fieldValueObject.TypeLink && !job.partners[spec.ID] {
+				links = append(links, spec)
+			}
+		}
+		if len(links) == 0 {
+			continue
+		}
+
+		tableID := job.ids.tables[table.ID]
+		var batch []tableService.LinkCellContext
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			if _, err := s.linker.GetDerivateByLink(ctx, tableID, batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+			return nil
+		}
+		err := s.scanRecords(job, table.ID, func(line *recordLine) error {
+			for _, spec := range links {
+				value := line.Fields[spec.ID]
+				if value == nil {
+					continue
+				}
+				value = job.remapLinkValue(spec, value)
+				if items, ok := value.([]interface{}); value == nil || (ok && len(items) == 0) {
+					continue
+				}
+				batch = append(batch, tableService.LinkCellContext{
+					RecordID: job.ids.records[line.ID],
+					FieldID:  job.ids.fields[spec.ID],
+					NewValue: value,
+				})
+			}
+			if len(batch) >= s.config.ChunkSize {
+				return flush()
+			}
+			return nil
+		})
+		if err == nil {
+			err = flush()
+		}
+		if err != nil {
+			return fmt.Errorf("写入表格 %s 的关联失败: %w", table.Name, err)
+		}
+	}
+	return nil
+}
+
+// scanRecords 逐行读取表格的记录文件
+func (s *Service) scanRecords(job *importJob, tableID string, fn func(line *recordLine) error) error {
+	file := job.files[recordsEntry(tableID)]
+	if file == nil {
+		return nil
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("读取记录文件失败: %v", err))
+	}
+	defer rc.Close()
+
+	decoder := json.NewDecoder(rc)
+	for decoder.More() {
+		var line recordLine
+		if err := decoder.Decode(&line); err != nil {
+			return pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("解析记录文件 %s 失败: %v", file.Name, err))
+		}
+		if line.ID == "" {
+			continue
+		}
+		if err := fn(&line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// afterImport 所有记录写入后计算虚拟字段并发布记录创建事件（关联的记录此时才全部存在）
+func (s *Service) afterImport(ctx context.Context, job *importJob) {
+	if s.calculator == nil && s.businessEvents == nil {
+		return
+	}
+	for _, table := range job.manifest.Tables {
+		tableID := job.ids.tables[table.ID]
+		fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+		if err != nil {
+			logger.Warn("归档导入后获取字段失败，跳过虚拟字段计算",
+				logger.String("table_id", tableID),
+				logger.ErrorField(err))
+			continue
+		}
+		computed := false
+		for _, field := range fields {
+			if field.IsComputed() {
+				computed = true
+				break
+			}
+		}
+
+		filter := recordRepo.RecordFilter{TableID: &tableID, Limit: s.config.PageSize}
+		for {
+			page, err := s.recordRepo.ListPage(ctx, filter)
+			if err != nil {
+				logger.Warn("归档导入后查询记录失败",
+					logger.String("table_id", tableID),
+					logger.ErrorField(err))
+				break
+			}
+			for _, record := range page.Records {
+				if computed && s.calculator != nil {
+					if err := s.calculator.CalculateRecordFieldsWithFields(ctx, record, fields); err != nil {
+						logger.Warn("导入记录虚拟字段计算失败（不影响导入）",
+							logger.String("record_id", record.ID().String()),
+							logger.ErrorField(err))
+					}
+				}
+				if s.businessEvents != nil {
+					if err := s.businessEvents.PublishRecordEvent(ctx, events.BusinessEventTypeRecordCreate, tableID,
+						record.ID().String(), record.Data().ToMap(), job.userID, record.Version().Value()); err != nil {
+						logger.Warn("发布导入记录事件失败",
+							logger.String("record_id", record.ID().String()),
+							logger.ErrorField(err))
+					}
+				}
+			}
+			if page.NextCursor == nil || len(page.Records) == 0 {
+				break
+			}
+			filter.Cursor = page.NextCursor
+		}
+	}
+}
+
+// safePath 附件路径必须是存储内的相对路径
+func safePath(p string) bool {
+	return p != "" && !strings.HasPrefix(p, "/") && path.Clean(p) == p && !strings.HasPrefix(p, "../") && p != ".."
+}
@@ -0,0 +1,106 @@
+package basearchive
+
+import (
+	"time"
+
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	viewValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+)
+
+// FormatVersion 归档格式版本，导入时拒绝更高版本的归档
+const FormatVersion = 1
+
+// 归档内的文件布局
+const (
+	manifestFile      = "manifest.json"
+	recordsDir        = "records/"     // records/<tableId>.jsonl，每行一条记录
+	attachmentsDir    = "attachments/" // attachments/<存储路径>，附件文件原样保存
+	archiveFileSuffix = ".luckdb.zip"
+)
+
+// Manifest 归档清单：Base 的完整结构（表格、字段、视图、关联关系）与附件索引
+//
+// 清单中的 ID 均为导出时的原始 ID，导入时全部重新生成并改写引用
+type Manifest struct {
+	Version     int              `json:"version"`
+	ExportedAt  time.Time        `json:"exportedAt"`
+	Base        BaseSpec         `json:"base"`
+	Tables      []TableSpec      `json:"tables"`
+	Links       []LinkSpec       `json:"links"`
+	Attachments []AttachmentSpec `json:"attachments"`
+}
+
+// BaseSpec Base 基本信息
+type BaseSpec struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Icon string `json:"icon,omitempty"`
+}
+
+// TableSpec 表格结构
+type TableSpec struct {
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Fields      []FieldSpec `json:"fields"`
+	Views       []ViewSpec  `json:"views"`
+	RecordCount int         `json:"recordCount"`
+}
+
+// FieldSpec 字段结构，Options 保存完整的字段选项（与数据库中的格式一致）
+type FieldSpec struct {
+	ID          string                         `json:"id"`
+	Name        string                         `json:"name"`
+	Type        string                         `json:"type"`
+	Description string                         `json:"description,omitempty"`
+	Required    bool                           `json:"required,omitempty"`
+	Unique      bool                           `json:"unique,omitempty"`
+	NotNull     bool                           `json:"notNull,omitempty"`
+	IsPrimary   bool                           `json:"isPrimary,omitempty"`
+	Options     *fieldValueObject.FieldOptions `json:"options,omitempty"`
+}
+
+// ViewSpec 视图结构
+type ViewSpec struct {
+	ID          string                       `json:"id"`
+	Name        string                       `json:"name"`
+	Type        string                       `json:"type"`
+	Description string                       `json:"description,omitempty"`
+	Filter      *viewValueObject.Filter      `json:"filter,omitempty"`
+	Sort        *viewValueObject.Sort        `json:"sort,omitempty"`
+	Group       *viewValueObject.Group       `json:"group,omitempty"`
+	ColumnMeta  []viewValueObject.ColumnMeta `json:"columnMeta,omitempty"`
+	Options     map[string]interface{}       `json:"options,omitempty"`
+	Order       float64                      `json:"order"`
+}
+
+// LinkSpec 关联关系（冗余自字段选项，便于不解析字段选项即可了解表间关系）
+type LinkSpec struct {
+	FieldID          string `json:"fieldId"`
+	TableID          string `json:"tableId"`
+	LinkedTableID    string `json:"linkedTableId"`
+	SymmetricFieldID string `json:"symmetricFieldId,omitempty"`
+	Relationship     string `json:"relationship"`
+}
+
+// AttachmentSpec 附件索引，文件内容位于 attachments/<Path>
+type AttachmentSpec struct {
+	Path     string `json:"path"`
+	Name     string `json:"name"`
+	MimeType string `json:"mimetype,omitempty"`
+	Size     int64  `json:"size"`
+	Token    string `json:"token,omitempty"`
+	Missing  bool   `json:"missing,omitempty"` // 导出时存储中已不存在该文件
+}
+
+// recordLine 记录文件中的一行
+type recordLine struct {
+	ID          string                 `json:"id"`
+	Fields      map[string]interface{} `json:"fields"`
+	CreatedTime time.Time              `json:"createdTime"`
+}
+
+// recordsEntry 表格记录文件在归档中的路径
+func recordsEntry(tableID string) string {
+	return recordsDir + tableID + ".jsonl"
+}
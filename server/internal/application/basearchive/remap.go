@@ -0,0 +1,302 @@
+package basearchive
+
+import (
+	"encoding/json"
+	"regexp"
+
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	viewValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+)
+
+// formulaRefPattern 公式中的字段引用：{字段ID} 或 {字段名称}
+var formulaRefPattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// idMap 归档中的原始 ID 到新 ID 的映射
+type idMap struct {
+	tables  map[string]string
+	fields  map[string]string
+	views   map[string]string
+	records map[string]string
+}
+
+func newIDMap() *idMap {
+	return &idMap{
+		tables:  make(map[string]string),
+		fields:  make(map[string]string),
+		views:   make(map[string]string),
+		records: make(map[string]string),
+	}
+}
+
+// rewriteFormula 把公式中的 {字段ID} 改写为新 ID，按名称引用或未映射的引用保持不变
+func (m *idMap) rewriteFormula(expression string) string {
+	return formulaRefPattern.ReplaceAllStringFunc(expression, func(ref string) string {
+		if id, ok := m.fields[ref[1:len(ref)-1]]; ok {
+			return "{" + id + "}"
+		}
+		return ref
+	})
+}
+
+// fieldDependencies 计算字段依赖的归档字段（依赖全部创建后才能创建该字段）
+//
+// 查找、汇总引用的关联表字段也算作依赖：被引用字段本身可能是尚未创建的计算字段
+func fieldDependencies(spec FieldSpec, archived map[string]bool) []string {
+	options := spec.Options
+	if options == nil {
+		return nil
+	}
+	var deps []string
+	add := func(ids ...string) {
+		for _, id := range ids {
+			if archived[id] {
+				deps = append(deps, id)
+			}
+		}
+	}
+	switch spec.Type {
+	case fieldValueObject.TypeLookup:
+		if options.Lookup != nil {
+			add(options.Lookup.LinkFieldID, options.Lookup.LookupFieldID)
+		}
+	case fieldValueObject.TypeRollup:
+		if options.Rollup != nil {
+			add(options.Rollup.LinkFieldID, options.Rollup.RollupFieldID)
+		}
+	case fieldValueObject.TypeCount:
+		if options.Count != nil {
+			add(options.Count.LinkFieldID)
+		}
+	case fieldValueObject.TypeFormula:
+		if options.Formula != nil {
+			for _, match := range formulaRefPattern.FindAllStringSubmatch(options.Formula.Expression, -1) {
+				add(match[1])
+			}
+		}
+	}
+	return deps
+}
+
+// remapFieldOptions 深拷贝字段选项并把其中的表格、字段引用改写为新 ID
+//
+// 关联字段的对称字段ID置空（由字段服务自动创建对称字段），未映射的视图过滤（视图尚未创建）一并清除
+func (m *idMap) remapFieldOptions(options *fieldValueObject.FieldOptions) (*fieldValueObject.FieldOptions, error) {
+	if options == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(options)
+	if err != nil {
+		return nil, err
+	}
+	var copied fieldValueObject.FieldOptions
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return nil, err
+	}
+
+	if link := copied.Link; link != nil {
+		link.LinkedTableID = m.tables[link.LinkedTableID]
+		link.SymmetricFieldID = ""
+		link.BaseID = ""
+		link.LookupFieldID = m.fields[link.LookupFieldID]
+		if link.FilterByViewID != nil {
+			if id, ok := m.views[*link.FilterByViewID]; ok {
+				link.FilterByViewID = &id
+			} else {
+				link.FilterByViewID = nil
+			}
+		}
+		link.VisibleFieldIDs = m.mapFieldIDs(link.VisibleFieldIDs)
+		if link.Filter != nil {
+			conditions := link.Filter.Conditions[:0]
+			for _, condition := range link.Filter.Conditions {
+				if id, ok := m.fields[condition.FieldID]; ok {
+					condition.FieldID = id
+					condition.Value = m.remapValue(condition.Value)
+					conditions = append(conditions, condition)
+				}
+			}
+			link.Filter.Conditions = conditions
+		}
+	}
+	if lookup := copied.Lookup; lookup != nil {
+		lookup.LinkFieldID = m.fields[lookup.LinkFieldID]
+		lookup.LookupFieldID = m.fields[lookup.LookupFieldID]
+	}
+	if rollup := copied.Rollup; rollup != nil {
+		rollup.LinkFieldID = m.fields[rollup.LinkFieldID]
+		rollup.RollupFieldID = m.fields[rollup.RollupFieldID]
+	}
+	if count := copied.Count; count != nil {
+		count.LinkFieldID = m.fields[count.LinkFieldID]
+	}
+	if formula := copied.Formula; formula != nil {
+		formula.Expression = m.rewriteFormula(formula.Expression)
+	}
+	return &copied, nil
+}
+
+// mapFieldIDs 映射字段ID列表，丢弃未映射的字段
+func (m *idMap) mapFieldIDs(ids []string) []string {
+	if ids == nil {
+		return nil
+	}
+	mapped := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if newID, ok := m.fields[id]; ok {
+			mapped = append(mapped, newID)
+		}
+	}
+	return mapped
+}
+
+// remapFilter 映射视图过滤条件中的字段，丢弃引用未映射字段的条件
+func (m *idMap) remapFilter(filter *viewValueObject.Filter) *viewValueObject.Filter {
+	if filter == nil {
+		return nil
+	}
+	remapped := &viewValueObject.Filter{Operator: filter.Operator, Filters: []viewValueObject.FilterItem{}}
+	for _, item := range filter.Filters {
+		if id, ok := m.fields[item.FieldID]; ok {
+			item.FieldID = id
+			item.Value = m.remapValue(item.Value)
+			remapped.Filters = append(remapped.Filters, item)
+		}
+	}
+	for _, group := range filter.Groups {
+		if sub := m.remapFilter(group); len(sub.Filters) > 0 || len(sub.Groups) > 0 {
+			remapped.Groups = append(remapped.Groups, sub)
+		}
+	}
+	return remapped
+}
+
+// remapSort 映射排序项中的字段，返回视图请求使用的 map 列表
+func (m *idMap) remapSort(sort *viewValueObject.Sort) []map[string]interface{} {
+	if sort == nil {
+		return nil
+	}
+	var items []map[string]interface{}
+	for _, item := range sort.SortItems {
+		if id, ok := m.fields[item.FieldID]; ok {
+			items = append(items, map[string]interface{}{"fieldId": id, "order": string(item.Order)})
+		}
+	}
+	return items
+}
+
+// remapGroup 映射分组项中的字段
+func (m *idMap) remapGroup(group *viewValueObject.Group) []map[string]interface{} {
+	if group == nil {
+		return nil
+	}
+	var items []map[string]interface{}
+	for _, item := range group.GroupItems {
+		if id, ok := m.fields[item.FieldID]; ok {
+			items = append(items, map[string]interface{}{"fieldId": id, "order": string(item.Order)})
+		}
+	}
+	return items
+}
+
+// remapColumnMeta 映射列配置中的字段
+func (m *idMap) remapColumnMeta(columns []viewValueObject.ColumnMeta) []map[string]interface{} {
+	var items []map[string]interface{}
+	for _, column := range columns {
+		if id, ok := m.fields[column.FieldID]; ok {
+			items = append(items, map[string]interface{}{
+				"fieldId": id,
+				"width":   column.Width,
+				"visible": column.Visible,
+				"order":   column.Order,
+			})
+		}
+	}
+	return items
+}
+
+// remapValue 递归改写任意 JSON 值中等于归档字段、视图或记录ID的字符串
+//
+// 用于视图选项（看板分组字段、画廊封面字段等）与过滤值（关联记录ID）这类结构不固定的数据
+func (m *idMap) remapValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		for _, ids := range []map[string]string{m.fields, m.views, m.records} {
+			if id, ok := ids[v]; ok {
+				return id
+			}
+		}
+		return v
+	case []interface{}:
+		remapped := make([]interface{}, len(v))
+		for i, item := range v {
+			remapped[i] = m.remapValue(item)
+		}
+		return remapped
+	case []string:
+		remapped := make([]string, len(v))
+		for i, item := range v {
+			remapped[i], _ = m.remapValue(item).(string)
+		}
+		return remapped
+	case map[string]interface{}:
+		remapped := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			remapped[key] = m.remapValue(item)
+		}
+		return remapped
+	}
+	return value
+}
+
+// remapLinkValue 改写关联单元格中的记录ID，丢弃指向归档外记录的关联
+//
+// 单元格为 {id, title} 对象或其数组，也兼容直接保存记录ID的写法
+func (m *idMap) remapLinkValue(value interface{}) interface{} {
+	remapOne := func(item interface{}) (interface{}, bool) {
+		switch v := item.(type) {
+		case string:
+			id, ok := m.records[v]
+			return id, ok
+		case map[string]interface{}:
+			oldID, _ := v["id"].(string)
+			id, ok := m.records[oldID]
+			if !ok {
+				return nil, false
+			}
+			remapped := make(map[string]interface{}, len(v))
+			for key, val := range v {
+				remapped[key] = val
+			}
+			remapped["id"] = id
+			return remapped, true
+		}
+		return nil, false
+	}
+
+	if items, ok := value.([]interface{}); ok {
+		remapped := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			if v, ok := remapOne(item); ok {
+				remapped = append(remapped, v)
+			}
+		}
+		return remapped
+	}
+	if v, ok := remapOne(value); ok {
+		return v
+	}
+	return nil
+}
+
+// toMap 结构体经 JSON 转为 map（视图请求使用 map 形式的过滤、排序等参数）
+func toMap(v interface{}) map[string]interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var m map[string]interface{}
+	if json.Unmarshal(data, &m) != nil {
+		return nil
+	}
+	return m
+}
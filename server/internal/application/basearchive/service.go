@@ -0,0 +1,172 @@
+package basearchive
+
+import (
+	"context"
+	"sync"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	baseRepo "github.com/easyspace-ai/luckdb/server/internal/domain/base/repository"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	tableService "github.com/easyspace-ai/luckdb/server/internal/domain/table/service"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/task"
+	viewRepo "github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/events"
+)
+
+// BaseCreator 新建 Base，导入失败时删除已创建的 Base（由 BaseService 实现）
+type BaseCreator interface {
+	CreateBase(ctx context.Context, req dto.CreateBaseRequest, userID string) (*dto.BaseResponse, error)
+	DeleteBase(ctx context.Context, baseID string) error
+}
+
+// TableCreator 新建不带默认字段与视图的空表格，复制表格失败时删除已创建的表格（由 TableService 实现）
+type TableCreator interface {
+	CreateEmptyTable(ctx context.Context, baseID, name, description, userID string) (*dto.TableResponse, error)
+	DeleteTable(ctx context.Context, tableID string) error
+}
+
+// FieldCreator 按完整选项新建、修改与删除字段（由 FieldService 实现）
+type FieldCreator interface {
+	CreateFieldWithOptions(ctx context.Context, tableID, name, fieldType string, options *fieldValueObject.FieldOptions, required, unique, notNull bool, userID string) (*fieldEntity.Field, error)
+	UpdateField(ctx context.Context, fieldID string, req dto.UpdateFieldRequest) (*dto.FieldResponse, error)
+	DeleteField(ctx context.Context, fieldID string) error
+}
+
+// ViewCreator 新建视图（由 ViewService 实现）
+type ViewCreator interface {
+	CreateView(ctx context.Context, req dto.CreateViewRequest, userID string) (*dto.ViewResponse, error)
+}
+
+// RecordWriter 批量写入记录（由 BatchService 实现）
+type RecordWriter interface {
+	BatchCreateRecords(ctx context.Context, tableID string, records []*recordEntity.Record) error
+}
+
+// RecordCalculator 计算记录的虚拟字段（由 CalculationService 实现）
+type RecordCalculator interface {
+	CalculateRecordFieldsWithFields(ctx context.Context, record *recordEntity.Record, fields []*fieldEntity.Field) error
+}
+
+// LinkDeriver 写入关联单元格的外键并同步对称字段（由 table/service.LinkService 实现）
+type LinkDeriver interface {
+	GetDerivateByLink(ctx context.Context, tableID string, cellContexts []tableService.LinkCellContext) (*tableService.LinkDerivation, error)
+}
+
+// Config 归档配置
+type Config struct {
+	PageSize              int   // 导出时每页查询的记录数
+	ChunkSize             int   // 导入时每批写入的记录数
+	MaxManifestSize       int64 // 清单文件的最大字节数
+	BackgroundRecordLimit int64 // 复制记录数超过该值的 Base 时转为后台任务
+}
+
+// DefaultConfig 默认归档配置
+func DefaultConfig() *Config {
+	return &Config{
+		PageSize:              1000,
+		ChunkSize:             500,
+		MaxManifestSize:       64 << 20,
+		BackgroundRecordLimit: 10000,
+	}
+}
+
+// Service Base 归档服务
+//
+// 导出：把 Base 的表格、字段（含完整选项）、视图、关联关系写入 manifest.json，记录按表逐页写入
+// records/<tableId>.jsonl，附件文件写入 attachments/ 目录，整体打包为一个 zip 流式写出。
+// 导入：在目标空间下新建 Base，所有表格、字段、视图与记录使用新 ID，关联、对称、查找、汇总、
+// 计数与公式字段中的引用改写为新 ID。
+// 复制：导出到临时归档后在原空间导入，记录多的 Base 在后台复制，进度记录在 task / task_run 中
+type Service struct {
+	config      *Config
+	baseRepo    baseRepo.BaseRepository
+	tableRepo   tableRepo.TableRepository
+	fieldRepo   fieldRepo.FieldRepository
+	viewRepo    viewRepo.ViewRepository
+	recordRepo  recordRepo.RecordRepository
+	storage     attachment.StorageProvider
+	attachments attachment.Repository
+	tasks       task.Repository
+
+	bases  BaseCreator
+	tables TableCreator
+	fields FieldCreator
+	views  ViewCreator
+	writer RecordWriter
+
+	businessEvents events.BusinessEventPublisher // 可选：发布记录创建事件（实时推送、搜索索引）
+	calculator     RecordCalculator              // 可选：导入后计算虚拟字段
+	linker         LinkDeriver                   // 可选：导入后写入关联外键
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewService 创建 Base 归档服务
+func NewService(
+	config *Config,
+	baseRepo baseRepo.BaseRepository,
+	tableRepo tableRepo.TableRepository,
+	fieldRepo fieldRepo.FieldRepository,
+	viewRepo viewRepo.ViewRepository,
+	recordRepo recordRepo.RecordRepository,
+	storage attachment.StorageProvider,
+	attachments attachment.Repository,
+	tasks task.Repository,
+	bases BaseCreator,
+	tables TableCreator,
+	fields FieldCreator,
+	views ViewCreator,
+	writer RecordWriter,
+) *Service {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Service{
+		config:      config,
+		baseRepo:    baseRepo,
+		tableRepo:   tableRepo,
+		fieldRepo:   fieldRepo,
+		viewRepo:    viewRepo,
+		recordRepo:  recordRepo,
+		storage:     storage,
+		attachments: attachments,
+		tasks:       tasks,
+		bases:       bases,
+		tables:      tables,
+		fields:      fields,
+		views:       views,
+		writer:      writer,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// Stop 停止服务，中断进行中的复制任务并等待其结束（已创建的 Base 会被删除）
+func (s *Service) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// SetBusinessEventPublisher 设置业务事件发布器
+func (s *Service) SetBusinessEventPublisher(publisher events.BusinessEventPublisher) {
+	s.businessEvents = publisher
+}
+
+// SetLinkDeriver 设置关联外键写入器
+func (s *Service) SetLinkDeriver(linker LinkDeriver) {
+	s.linker = linker
+}
+
+// SetRecordCalculator 设置虚拟字段计算器
+func (s *Service) SetRecordCalculator(calculator RecordCalculator) {
+	s.calculator = calculator
+}
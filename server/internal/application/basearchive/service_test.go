@@ -0,0 +1,844 @@
+package basearchive
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	baseEntity "github.com/easyspace-ai/luckdb/server/internal/domain/base/entity"
+	baseRepo "github.com/easyspace-ai/luckdb/server/internal/domain/base/repository"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	tableService "github.com/easyspace-ai/luckdb/server/internal/domain/table/service"
+	tableValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/table/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/task"
+	viewEntity "github.com/easyspace-ai/luckdb/server/internal/domain/view/entity"
+	viewRepo "github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
+	viewValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+func init() {
+	if logger.Logger == nil {
+		logger.Init(logger.LoggerConfig{
+			Level:      "error",
+			Format:     "console",
+			OutputPath: "stdout",
+		})
+	}
+}
+
+// fakeWorld 内存中的一个实例：同时充当仓储（导出读取）与应用服务（导入写入）
+type fakeWorld struct {
+	t       *testing.T
+	bases   map[string]*baseEntity.Base
+	tables  []*tableEntity.Table
+	fields  map[string][]*fieldEntity.Field
+	views   map[string][]*viewEntity.View
+	records map[string][]*recordEntity.Record
+	files   map[string][]byte
+	items   map[string]*attachment.AttachmentItem
+	tasks   *fakeTaskRepo
+
+	viewRequests  []dto.CreateViewRequest
+	deleted       []string
+	deletedTables []string
+	deletedFields []string
+	derived       []tableService.LinkCellContext
+	nextID        int
+}
+
+func newFakeWorld(t *testing.T) *fakeWorld {
+	return &fakeWorld{
+		t:       t,
+		bases:   map[string]*baseEntity.Base{},
+		fields:  map[string][]*fieldEntity.Field{},
+		views:   map[string][]*viewEntity.View{},
+		records: map[string][]*recordEntity.Record{},
+		files:   map[string][]byte{},
+		items:   map[string]*attachment.AttachmentItem{},
+		tasks:   &fakeTaskRepo{tasks: map[string]*task.Task{}, runs: map[string]*task.Run{}},
+	}
+}
+
+func (w *fakeWorld) id(prefix string) string {
+	w.nextID++
+	return fmt.Sprintf("%s_new%d", prefix, w.nextID)
+}
+
+func (w *fakeWorld) service() *Service {
+	config := DefaultConfig()
+	config.PageSize = 1
+	config.ChunkSize = 1
+	return NewService(config, &fakeBaseRepo{fakeWorld: w}, &fakeTableRepo{fakeWorld: w}, &fakeFieldRepo{fakeWorld: w},
+		&fakeViewRepo{fakeWorld: w}, &fakeRecordRepo{fakeWorld: w}, &fakeStorage{fakeWorld: w}, &fakeAttachmentRepo{fakeWorld: w},
+		w.tasks, &fakeBases{w}, &fakeTables{w}, &fakeFields{w}, &fakeViews{w}, &fakeWriter{w})
+}
+
+func (w *fakeWorld) addTable(id, baseID, name string) {
+	tableName, err := tableValueObject.NewTableName(name)
+	require.NoError(w.t, err)
+	w.tables = append(w.tables, tableEntity.ReconstructTable(tableValueObject.NewTableID(id), baseID, tableName,
+		nil, nil, nil, "usr_1", time.Now(), time.Now(), nil, 1))
+}
+
+func (w *fakeWorld) addField(tableID, id, name, fieldType string, options *fieldValueObject.FieldOptions) *fieldEntity.Field {
+	ft, err := fieldValueObject.NewFieldType(fieldType)
+	require.NoError(w.t, err)
+	fieldName, err := fieldValueObject.NewFieldName(name)
+	require.NoError(w.t, err)
+	dbFieldName, err := fieldValueObject.NewDBFieldNameFromString(id)
+	require.NoError(w.t, err)
+	if options == nil {
+		options = fieldValueObject.NewFieldOptions()
+	}
+	field := fieldEntity.ReconstructField(fieldValueObject.NewFieldID(id), tableID, fieldName, ft, dbFieldName,
+		"TEXT", options, float64(len(w.fields[tableID])), 1, "usr_1", time.Now(), time.Now())
+	w.fields[tableID] = append(w.fields[tableID], field)
+	return field
+}
+
+func (w *fakeWorld) addRecord(tableID, id string, data map[string]interface{}) {
+	recordData, err := recordValueObject.NewRecordData(data)
+	require.NoError(w.t, err)
+	version, err := recordValueObject.NewRecordVersion(1)
+	require.NoError(w.t, err)
+	w.records[tableID] = append(w.records[tableID], recordEntity.ReconstructRecord(recordValueObject.NewRecordID(id),
+		tableID, recordData, version, "usr_1", "usr_1", time.Now(), time.Now(), nil))
+}
+
+func (w *fakeWorld) findField(id string) *fieldEntity.Field {
+	for _, fields := range w.fields {
+		for _, field := range fields {
+			if field.ID().String() == id {
+				return field
+			}
+		}
+	}
+	return nil
+}
+
+func (w *fakeWorld) fieldByName(tableID, name string) *fieldEntity.Field {
+	for _, field := range w.fields[tableID] {
+		if field.Name().String() == name {
+			return field
+		}
+	}
+	return nil
+}
+
+type fakeBaseRepo struct {
+	baseRepo.BaseRepository
+	*fakeWorld
+}
+
+func (r *fakeBaseRepo) FindByID(ctx context.Context, id string) (*baseEntity.Base, error) {
+	return r.bases[id], nil
+}
+
+type fakeTableRepo struct {
+	tableRepo.TableRepository
+	*fakeWorld
+}
+
+func (r *fakeTableRepo) GetByBaseID(ctx context.Context, baseID string) ([]*tableEntity.Table, error) {
+	var tables []*tableEntity.Table
+	for _, table := range r.tables {
+		if table.BaseID() == baseID {
+			tables = append(tables, table)
+		}
+	}
+	return tables, nil
+}
+
+func (r *fakeTableRepo) GetByID(ctx context.Context, id string) (*tableEntity.Table, error) {
+	for _, table := range r.tables {
+		if table.ID().String() == id {
+			return table, nil
+		}
+	}
+	return nil, nil
+}
+
+type fakeFieldRepo struct {
+	fieldRepo.FieldRepository
+	*fakeWorld
+}
+
+func (r *fakeFieldRepo) FindByTableID(ctx context.Context, tableID string) ([]*fieldEntity.Field, error) {
+	return append([]*fieldEntity.Field(nil), r.fields[tableID]...), nil
+}
+
+type fakeViewRepo struct {
+	viewRepo.ViewRepository
+	*fakeWorld
+}
+
+func (r *fakeViewRepo) FindByTableID(ctx context.Context, tableID string) ([]*viewEntity.View, error) {
+	return r.views[tableID], nil
+}
+
+// fakeRecordRepo 按记录下标作为游标分页
+type fakeRecordRepo struct {
+	recordRepo.RecordRepository
+	*fakeWorld
+}
+
+func (r *fakeRecordRepo) ListPage(ctx context.Context, filter recordRepo.RecordFilter) (*recordRepo.RecordPage, error) {
+	records := r.records[*filter.TableID]
+	start := 0
+	if filter.Cursor != nil {
+		fmt.Sscanf(filter.Cursor.ID, "%d", &start)
+	}
+	end := min(start+filter.Limit, len(records))
+	page := &recordRepo.RecordPage{Records: records[start:end], Total: int64(len(records))}
+	if end < len(records) {
+		page.NextCursor = &recordValueObject.RecordCursor{ID: fmt.Sprint(end)}
+	}
+	return page, nil
+}
+
+func (r *fakeRecordRepo) CountByTableID(ctx context.Context, tableID string) (int64, error) {
+	return int64(len(r.records[tableID])), nil
+}
+
+type fakeStorage struct {
+	attachment.StorageProvider
+	*fakeWorld
+}
+
+func (s *fakeStorage) Upload(ctx context.Context, req attachment.UploadRequest) (*attachment.UploadResult, error) {
+	data, err := io.ReadAll(req.Reader)
+	if err != nil {
+		return nil, err
+	}
+	s.files[req.Path] = data
+	return &attachment.UploadResult{Path: req.Path, Size: int64(len(data))}, nil
+}
+
+func (s *fakeStorage) Download(ctx context.Context, path string) (*attachment.DownloadResult, error) {
+	data, ok := s.files[path]
+	if !ok {
+		return nil, fmt.Errorf("文件不存在: %s", path)
+	}
+	return &attachment.DownloadResult{Reader: io.NopCloser(bytes.NewReader(data)), Size: int64(len(data))}, nil
+}
+
+func (s *fakeStorage) Exists(ctx context.Context, path string) (bool, error) {
+	_, ok := s.files[path]
+	return ok, nil
+}
+
+type fakeAttachmentRepo struct {
+	attachment.Repository
+	*fakeWorld
+}
+
+func (r *fakeAttachmentRepo) CreateAttachment(ctx context.Context, item *attachment.AttachmentItem) error {
+	r.items[item.Path] = item
+	return nil
+}
+
+func (r *fakeAttachmentRepo) GetAttachmentByPath(ctx context.Context, path string) (*attachment.AttachmentItem, error) {
+	if item, ok := r.items[path]; ok {
+		return item, nil
+	}
+	return nil, fmt.Errorf("not found")
+}
+
+type fakeBases struct{ *fakeWorld }
+
+func (b *fakeBases) CreateBase(ctx context.Context, req dto.CreateBaseRequest, userID string) (*dto.BaseResponse, error) {
+	base := &baseEntity.Base{ID: b.id("bse"), Name: req.Name, Icon: req.Icon, SpaceID: req.SpaceID}
+	b.bases[base.ID] = base
+	return &dto.BaseResponse{ID: base.ID, Name: base.Name, SpaceID: base.SpaceID}, nil
+}
+
+func (b *fakeBases) DeleteBase(ctx context.Context, baseID string) error {
+	b.deleted = append(b.deleted, baseID)
+	return nil
+}
+
+type fakeTables struct{ *fakeWorld }
+
+func (f *fakeTables) DeleteTable(ctx context.Context, tableID string) error {
+	f.deletedTables = append(f.deletedTables, tableID)
+	return nil
+}
+
+func (f *fakeTables) CreateEmptyTable(ctx context.Context, baseID, name, description, userID string) (*dto.TableResponse, error) {
+	id := f.id("tbl")
+	f.addTable(id, baseID, name)
+	return &dto.TableResponse{ID: id, Name: name, BaseID: baseID}, nil
+}
+
+// fakeFields 与 FieldService 一致：对称关联字段随主字段自动创建，名称为“<关联表>列表”
+type fakeFields struct{ *fakeWorld }
+
+func (f *fakeFields) CreateFieldWithOptions(ctx context.Context, tableID, name, fieldType string, options *fieldValueObject.FieldOptions, required, unique, notNull bool, userID string) (*fieldEntity.Field, error) {
+	if options != nil && options.Formula != nil {
+		for _, match := range formulaRefPattern.FindAllStringSubmatch(options.Formula.Expression, -1) {
+			if strings.HasPrefix(match[1], "fld_") && f.findField(match[1]) == nil {
+				return nil, fmt.Errorf("公式引用的字段不存在: %s", match[1])
+			}
+		}
+	}
+	field := f.addField(tableID, f.id("fld"), name, fieldType, options)
+	if link := options.Link; link != nil && link.IsSymmetric {
+		symmetric := f.addField(link.LinkedTableID, f.id("fld"), "自动列表", fieldValueObject.TypeLink,
+			&fieldValueObject.FieldOptions{Link: &fieldValueObject.LinkOptions{
+				LinkedTableID:    tableID,
+				SymmetricFieldID: field.ID().String(),
+				IsSymmetric:      true,
+			}})
+		link.SymmetricFieldID = symmetric.ID().String()
+	}
+	return field, nil
+}
+
+func (f *fakeFields) UpdateField(ctx context.Context, fieldID string, req dto.UpdateFieldRequest) (*dto.FieldResponse, error) {
+	field := f.findField(fieldID)
+	if field == nil {
+		return nil, fmt.Errorf("字段不存在: %s", fieldID)
+	}
+	if req.Name != nil {
+		name, err := fieldValueObject.NewFieldName(*req.Name)
+		if err != nil {
+			return nil, err
+		}
+		require.NoError(f.t, field.Rename(name))
+	}
+	if req.Description != nil {
+		require.NoError(f.t, field.UpdateDescription(*req.Description))
+	}
+	return &dto.FieldResponse{ID: fieldID}, nil
+}
+
+func (f *fakeFields) DeleteField(ctx context.Context, fieldID string) error {
+	f.deletedFields = append(f.deletedFields, fieldID)
+	return nil
+}
+
+type fakeViews struct{ *fakeWorld }
+
+func (v *fakeViews) CreateView(ctx context.Context, req dto.CreateViewRequest, userID string) (*dto.ViewResponse, error) {
+	v.viewRequests = append(v.viewRequests, req)
+	return &dto.ViewResponse{ID: v.id("viw"), TableID: req.TableID, Name: req.Name}, nil
+}
+
+type fakeWriter struct{ *fakeWorld }
+
+func (w *fakeWriter) BatchCreateRecords(ctx context.Context, tableID string, records []*recordEntity.Record) error {
+	w.records[tableID] = append(w.records[tableID], records...)
+	return nil
+}
+
+type fakeLinker struct{ *fakeWorld }
+
+func (l *fakeLinker) GetDerivateByLink(ctx context.Context, tableID string, cellContexts []tableService.LinkCellContext) (*tableService.LinkDerivation, error) {
+	l.derived = append(l.derived, cellContexts...)
+	return &tableService.LinkDerivation{}, nil
+}
+
+// fakeTaskRepo 后台复制与测试并发读写，按值保存
+type fakeTaskRepo struct {
+	mu    sync.Mutex
+	tasks map[string]*task.Task
+	runs  map[string]*task.Run
+}
+
+func (r *fakeTaskRepo) CreateTask(ctx context.Context, t *task.Task) error {
+	return r.UpdateTask(ctx, t)
+}
+
+func (r *fakeTaskRepo) UpdateTask(ctx context.Context, t *task.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *t
+	r.tasks[t.ID] = &copied
+	return nil
+}
+
+func (r *fakeTaskRepo) FindTaskByID(ctx context.Context, id string) (*task.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if t, ok := r.tasks[id]; ok {
+		copied := *t
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeTaskRepo) CreateRun(ctx context.Context, run *task.Run) error {
+	return r.UpdateRun(ctx, run)
+}
+
+func (r *fakeTaskRepo) UpdateRun(ctx context.Context, run *task.Run) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *run
+	r.runs[run.TaskID] = &copied
+	return nil
+}
+
+func (r *fakeTaskRepo) FindLatestRun(ctx context.Context, taskID string) (*task.Run, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if run, ok := r.runs[taskID]; ok {
+		copied := *run
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+// newSourceWorld 项目表与成员表双向关联，项目表带查找、计数与公式字段，成员表带附件
+func newSourceWorld(t *testing.T) *fakeWorld {
+	w := newFakeWorld(t)
+	w.bases["bse_src"] = &baseEntity.Base{ID: "bse_src", Name: "研发/管理", Icon: "🚀", SpaceID: "spc_src"}
+	w.addTable("tbl_a", "bse_src", "项目")
+	w.addTable("tbl_b", "bse_src", "成员")
+
+	w.addField("tbl_a", "fld_a_name", "名称", fieldValueObject.TypeSingleLineText, nil)
+	w.addField("tbl_a", "fld_a_link", "成员", fieldValueObject.TypeLink, &fieldValueObject.FieldOptions{
+		Link: &fieldValueObject.LinkOptions{
+			LinkedTableID:    "tbl_b",
+			SymmetricFieldID: "fld_b_link",
+			Relationship:     "manyMany",
+			IsSymmetric:      true,
+			AllowMultiple:    true,
+			LookupFieldID:    "fld_b_name",
+			FilterByViewID:   strPtr("viw_b"),
+		},
+	})
+	w.addField("tbl_a", "fld_a_lookup", "成员姓名", fieldValueObject.TypeLookup, &fieldValueObject.FieldOptions{
+		Lookup: &fieldValueObject.LookupOptions{LinkFieldID: "fld_a_link", LookupFieldID: "fld_b_name"},
+	})
+	w.addField("tbl_a", "fld_a_formula", "摘要", fieldValueObject.TypeFormula, &fieldValueObject.FieldOptions{
+		Formula: &fieldValueObject.FormulaOptions{Expression: `CONCATENATE({fld_a_name}, "-", {fld_a_count}, {名称})`},
+	})
+	w.addField("tbl_a", "fld_a_count", "成员数", fieldValueObject.TypeCount, &fieldValueObject.FieldOptions{
+		Count: &fieldValueObject.CountOptions{LinkFieldID: "fld_a_link"},
+	})
+
+	w.addField("tbl_b", "fld_b_name", "姓名", fieldValueObject.TypeSingleLineText, nil)
+	w.addField("tbl_b", "fld_b_file", "头像", fieldValueObject.TypeAttachment, nil)
+	w.addField("tbl_b", "fld_b_link", "参与项目", fieldValueObject.TypeLink, &fieldValueObject.FieldOptions{
+		Link: &fieldValueObject.LinkOptions{
+			LinkedTableID:    "tbl_a",
+			SymmetricFieldID: "fld_a_link",
+			Relationship:     "manyMany",
+			IsSymmetric:      true,
+			AllowMultiple:    true,
+		},
+	})
+
+	filter, err := viewValueObject.NewFilter(map[string]interface{}{
+		"operator": "and",
+		"filters": []interface{}{
+			map[string]interface{}{"fieldId": "fld_a_name", "operator": "isNotEmpty"},
+			map[string]interface{}{"fieldId": "fld_a_link", "operator": "contains", "value": []interface{}{"rec_b1"}},
+		},
+	})
+	require.NoError(t, err)
+	sort, err := viewValueObject.NewSort([]map[string]interface{}{{"fieldId": "fld_a_formula", "order": "desc"}})
+	require.NoError(t, err)
+	columnMeta, err := viewValueObject.NewColumnMetaList([]map[string]interface{}{
+		{"fieldId": "fld_a_name", "width": 200, "visible": true, "order": 1},
+		{"fieldId": "fld_a_count", "visible": false, "order": 2},
+	})
+	require.NoError(t, err)
+	w.views["tbl_a"] = []*viewEntity.View{viewEntity.ReconstructView("viw_a", "进行中", "", "tbl_a",
+		viewValueObject.ViewTypeGrid, filter, sort, nil, columnMeta, map[string]interface{}{"coverFieldId": "fld_a_name"},
+		0, 1, false, false, nil, nil, "usr_1", time.Now(), time.Now(), nil)}
+
+	w.addRecord("tbl_a", "rec_a1", map[string]interface{}{
+		"fld_a_name":    "官网改版",
+		"fld_a_link":    []interface{}{map[string]interface{}{"id": "rec_b1", "title": "张三"}, map[string]interface{}{"id": "rec_gone"}},
+		"fld_a_lookup":  []interface{}{"张三"},
+		"fld_a_formula": "官网改版-1",
+	})
+	w.addRecord("tbl_b", "rec_b1", map[string]interface{}{
+		"fld_b_name": "张三",
+		"fld_b_file": []interface{}{map[string]interface{}{
+			"name": "avatar.png", "path": "2024/avatar.png", "token": "tok_1", "mimetype": "image/png", "size": float64(3),
+		}},
+		"fld_b_link": []interface{}{map[string]interface{}{"id": "rec_a1", "title": "官网改版"}},
+	})
+	w.files["2024/avatar.png"] = []byte("png")
+	return w
+}
+
+func strPtr(s string) *string { return &s }
+
+func exportArchive(t *testing.T, w *fakeWorld) ([]byte, *Manifest) {
+	var buf bytes.Buffer
+	manifest, err := w.service().Export(context.Background(), "bse_src", &buf)
+	require.NoError(t, err)
+	return buf.Bytes(), manifest
+}
+
+func TestExport_Archive(t *testing.T) {
+	source := newSourceWorld(t)
+	name, err := source.service().FileName(context.Background(), "bse_src")
+	require.NoError(t, err)
+	assert.Equal(t, "研发_管理.luckdb.zip", name)
+
+	data, manifest := exportArchive(t, source)
+	assert.Equal(t, FormatVersion, manifest.Version)
+	require.Len(t, manifest.Tables, 2)
+	assert.Equal(t, 1, manifest.Tables[0].RecordCount)
+	assert.True(t, manifest.Tables[0].Fields[0].IsPrimary)
+	assert.Len(t, manifest.Links, 2)
+	require.Len(t, manifest.Attachments, 1)
+	assert.Equal(t, AttachmentSpec{Path: "2024/avatar.png", Name: "avatar.png", MimeType: "image/png", Size: 3, Token: "tok_1"},
+		manifest.Attachments[0])
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	var names []string
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	assert.Equal(t, []string{"records/tbl_a.jsonl", "records/tbl_b.jsonl", "attachments/2024/avatar.png", "manifest.json"}, names)
+
+	_, err = source.service().FileName(context.Background(), "bse_missing")
+	assert.Error(t, err)
+}
+
+func TestImport_RoundTrip(t *testing.T) {
+	data, _ := exportArchive(t, newSourceWorld(t))
+
+	target := newFakeWorld(t)
+	result, err := target.service().Import(context.Background(), bytes.NewReader(data), int64(len(data)), ImportRequest{
+		SpaceID: "spc_dst",
+		UserID:  "usr_2",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "研发/管理", result.Name)
+	assert.Equal(t, 2, result.Tables)
+	assert.Equal(t, 8, result.Fields)
+	assert.Equal(t, 1, result.Views)
+	assert.Equal(t, 2, result.Records)
+	assert.Equal(t, 1, result.Attachments)
+	assert.Empty(t, result.Warnings)
+	assert.Equal(t, "spc_dst", target.bases[result.BaseID].SpaceID)
+
+	require.Len(t, target.tables, 2)
+	tableA, tableB := target.tables[0].ID().String(), target.tables[1].ID().String()
+	assert.Equal(t, "项目", target.tables[0].Name().String())
+
+	// 字段：普通字段在前，主字段不变；对称字段改回归档中的名称
+	var namesA []string
+	for _, field := range target.fields[tableA] {
+		namesA = append(namesA, field.Name().String())
+	}
+	assert.Equal(t, []string{"名称", "成员", "成员姓名", "成员数", "摘要"}, namesA)
+	assert.Equal(t, "名称", target.fields[tableA][0].Name().String())
+
+	name := target.fieldByName(tableA, "名称").ID().String()
+	link := target.fieldByName(tableA, "成员")
+	back := target.fieldByName(tableB, "参与项目")
+	require.NotNil(t, back, "对称字段改回原名")
+	assert.Nil(t, target.fieldByName(tableB, "自动列表"))
+	memberName := target.fieldByName(tableB, "姓名").ID().String()
+
+	linkOptions := link.Options().Link
+	assert.Equal(t, tableB, linkOptions.LinkedTableID)
+	assert.Equal(t, back.ID().String(), linkOptions.SymmetricFieldID)
+	assert.Equal(t, memberName, linkOptions.LookupFieldID)
+	assert.Nil(t, linkOptions.FilterByViewID)
+
+	lookup := target.fieldByName(tableA, "成员姓名").Options().Lookup
+	assert.Equal(t, link.ID().String(), lookup.LinkFieldID)
+	assert.Equal(t, memberName, lookup.LookupFieldID)
+	count := target.fieldByName(tableA, "成员数")
+	assert.Equal(t, link.ID().String(), count.Options().Count.LinkFieldID)
+	assert.Equal(t, fmt.Sprintf(`CONCATENATE({%s}, "-", {%s}, {名称})`, name, count.ID()),
+		target.fieldByName(tableA, "摘要").Options().Formula.Expression)
+
+	// 视图
+	require.Len(t, target.viewRequests, 1)
+	view := target.viewRequests[0]
+	assert.Equal(t, tableA, view.TableID)
+	assert.Equal(t, []map[string]interface{}{{"fieldId": target.fieldByName(tableA, "摘要").ID().String(), "order": "desc"}}, view.Sort)
+	assert.Equal(t, name, view.ColumnMeta[0]["fieldId"])
+	assert.Equal(t, 200, view.ColumnMeta[0]["width"])
+	assert.Equal(t, map[string]interface{}{"coverFieldId": name}, view.Options)
+	filters := view.Filter["filters"].([]interface{})
+	require.Len(t, filters, 2)
+	assert.Equal(t, link.ID().String(), filters[1].(map[string]interface{})["fieldId"])
+
+	// 记录：新ID，关联单元格改写，计算字段的值不写入
+	require.Len(t, target.records[tableA], 1)
+	require.Len(t, target.records[tableB], 1)
+	recordA, recordB := target.records[tableA][0], target.records[tableB][0]
+	assert.NotEqual(t, "rec_a1", recordA.ID().String())
+	assert.Equal(t, "usr_2", recordA.CreatedBy())
+	dataA, dataB := recordA.Data().ToMap(), recordB.Data().ToMap()
+	assert.Equal(t, "官网改版", dataA[name])
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": recordB.ID().String(), "title": "张三"}}, dataA[link.ID().String()],
+		"指向归档外记录的关联被丢弃")
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": recordA.ID().String(), "title": "官网改版"}}, dataB[back.ID().String()])
+	assert.Len(t, dataA, 2)
+	assert.Equal(t, []interface{}{recordB.ID().String()}, filters[1].(map[string]interface{})["value"])
+
+	// 附件：文件保存到原路径并登记
+	assert.Equal(t, []byte("png"), target.files["2024/avatar.png"])
+	require.NotNil(t, target.items["2024/avatar.png"])
+	assert.Equal(t, "tok_1", target.items["2024/avatar.png"].Token)
+}
+
+func TestImport_NameOverrideAndWarnings(t *testing.T) {
+	source := newSourceWorld(t)
+	// 只导出项目表：成员表不在归档中，关联与依赖它的计算字段无法还原
+	source.tables = source.tables[:1]
+	data, _ := exportArchive(t, source)
+
+	target := newFakeWorld(t)
+	result, err := target.service().Import(context.Background(), bytes.NewReader(data), int64(len(data)), ImportRequest{
+		SpaceID: "spc_dst",
+		Name:    "副本",
+		UserID:  "usr_2",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "副本", result.Name)
+	assert.Equal(t, 1, result.Fields)
+	assert.Len(t, result.Warnings, 4)
+	assert.Contains(t, result.Warnings[0], "归档外的表格")
+	assert.Len(t, target.records[target.tables[0].ID().String()][0].Data().ToMap(), 1, "只写入名称")
+}
+
+func TestImport_InvalidArchive(t *testing.T) {
+	service := newFakeWorld(t).service()
+	ctx := context.Background()
+
+	_, err := service.Import(ctx, bytes.NewReader([]byte("not a zip")), 9, ImportRequest{SpaceID: "spc", UserID: "usr"})
+	assert.Error(t, err)
+
+	build := func(manifest interface{}) []byte {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		if manifest != nil {
+			entry, err := zw.Create(manifestFile)
+			require.NoError(t, err)
+			require.NoError(t, json.NewEncoder(entry).Encode(manifest))
+		}
+		require.NoError(t, zw.Close())
+		return buf.Bytes()
+	}
+	for _, data := range [][]byte{build(nil), build(Manifest{Version: FormatVersion + 1})} {
+		_, err := service.Import(ctx, bytes.NewReader(data), int64(len(data)), ImportRequest{SpaceID: "spc", UserID: "usr"})
+		assert.Error(t, err)
+	}
+}
+
+func TestImport_DeletesBaseOnFailure(t *testing.T) {
+	data, _ := exportArchive(t, newSourceWorld(t))
+
+	target := newFakeWorld(t)
+	service := target.service()
+	service.writer = failingWriter{}
+	_, err := service.Import(context.Background(), bytes.NewReader(data), int64(len(data)), ImportRequest{
+		SpaceID: "spc_dst",
+		UserID:  "usr_2",
+	})
+	require.Error(t, err)
+	require.Len(t, target.deleted, 1)
+	assert.Contains(t, target.bases, target.deleted[0])
+}
+
+type failingWriter struct{}
+
+func (failingWriter) BatchCreateRecords(ctx context.Context, tableID string, records []*recordEntity.Record) error {
+	return fmt.Errorf("写入失败")
+}
+
+func TestDuplicate_SchemaOnly(t *testing.T) {
+	world := newSourceWorld(t)
+	service := world.service()
+	ctx := context.Background()
+
+	plan, err := service.PrepareDuplicate(ctx, DuplicateRequest{BaseID: "bse_src", UserID: "usr_2"})
+	require.NoError(t, err)
+	assert.Equal(t, "研发/管理 副本", plan.Request.Name)
+	assert.Equal(t, "spc_src", plan.SpaceID)
+	assert.Zero(t, plan.TotalRecords)
+	assert.False(t, service.ShouldDuplicateInBackground(plan))
+
+	result, err := service.Duplicate(ctx, plan)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Tables)
+	assert.Equal(t, 8, result.Fields)
+	assert.Zero(t, result.Records)
+	assert.Zero(t, result.Attachments)
+	assert.Equal(t, "spc_src", world.bases[result.BaseID].SpaceID)
+
+	// 新表格中的关联、查找与公式指向副本，而不是原表格
+	require.Len(t, world.tables, 4)
+	tableA, tableB := world.tables[2].ID().String(), world.tables[3].ID().String()
+	assert.Equal(t, result.BaseID, world.tables[2].BaseID())
+	link := world.fieldByName(tableA, "成员")
+	assert.Equal(t, tableB, link.Options().Link.LinkedTableID)
+	assert.Equal(t, world.fieldByName(tableB, "参与项目").ID().String(), link.Options().Link.SymmetricFieldID)
+	assert.Equal(t, link.ID().String(), world.fieldByName(tableA, "成员姓名").Options().Lookup.LinkFieldID)
+	assert.NotContains(t, world.fieldByName(tableA, "摘要").Options().Formula.Expression, "fld_a_")
+	assert.Empty(t, world.records[tableA])
+	assert.Len(t, world.records["tbl_a"], 1, "原表格的记录不变")
+}
+
+func TestDuplicate_BackgroundTask(t *testing.T) {
+	world := newSourceWorld(t)
+	service := world.service()
+	service.config.BackgroundRecordLimit = 1
+	ctx := context.Background()
+
+	plan, err := service.PrepareDuplicate(ctx, DuplicateRequest{BaseID: "bse_src", Name: "模板", WithRecords: true, UserID: "usr_2"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, plan.TotalRecords)
+	require.True(t, service.ShouldDuplicateInBackground(plan))
+
+	status, err := service.StartDuplicateTask(ctx, plan)
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusRunning, status.Status)
+	assert.Equal(t, "bse_src", status.SourceBaseID)
+
+	require.Eventually(t, func() bool {
+		status, err = service.GetDuplicateTask(ctx, status.ID, "usr_2")
+		return err == nil && status.Status.IsFinished()
+	}, 5*time.Second, 10*time.Millisecond)
+	service.Stop()
+
+	assert.Equal(t, task.StatusCompleted, status.Status, status.Error)
+	assert.Equal(t, float64(100), status.Progress)
+	assert.Equal(t, 2, status.CopiedRecords)
+	require.NotEmpty(t, status.BaseID)
+	assert.Equal(t, "模板", world.bases[status.BaseID].Name)
+
+	tableA := world.tables[2].ID().String()
+	require.Len(t, world.records[tableA], 1)
+	data := world.records[tableA][0].Data().ToMap()
+	linked := data[world.fieldByName(tableA, "成员").ID().String()].([]interface{})
+	require.Len(t, linked, 1)
+	assert.Equal(t, world.records[world.tables[3].ID().String()][0].ID().String(), linked[0].(map[string]interface{})["id"])
+
+	_, err = service.GetDuplicateTask(ctx, status.ID, "usr_other")
+	assert.Error(t, err, "只能查看自己发起的任务")
+}
+
+func TestDuplicate_MissingBase(t *testing.T) {
+	_, err := newSourceWorld(t).service().PrepareDuplicate(context.Background(), DuplicateRequest{BaseID: "bse_missing"})
+	assert.Error(t, err)
+}
+
+func TestDuplicateTable_KeepLinks(t *testing.T) {
+	world := newSourceWorld(t)
+	world.views["tbl_b"] = []*viewEntity.View{viewEntity.ReconstructView("viw_b", "全部", "", "tbl_b",
+		viewValueObject.ViewTypeGrid, nil, nil, nil, nil, nil, 0, 1, false, false, nil, nil, "usr_1", time.Now(), time.Now(), nil)}
+	service := world.service()
+	service.SetLinkDeriver(&fakeLinker{world})
+
+	result, err := service.DuplicateTable(context.Background(), TableDuplicateRequest{
+		TableID:     "tbl_a",
+		Name:        "项目 副本",
+		WithFields:  true,
+		WithViews:   true,
+		WithRecords: true,
+		LinkMode:    LinkModeKeep,
+		UserID:      "usr_2",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, result.Warnings)
+	assert.Equal(t, 5, result.Fields)
+	assert.Equal(t, 1, result.Views)
+	assert.Equal(t, 1, result.Records)
+
+	copied := result.TableID
+	assert.Equal(t, "bse_src", world.tables[len(world.tables)-1].BaseID())
+	link := world.fieldByName(copied, "成员")
+	linkOptions := link.Options().Link
+	assert.Equal(t, "tbl_b", linkOptions.LinkedTableID, "关联仍指向原来的成员表")
+	assert.Equal(t, "fld_b_name", linkOptions.LookupFieldID)
+	require.NotNil(t, linkOptions.FilterByViewID)
+	assert.Equal(t, "viw_b", *linkOptions.FilterByViewID)
+	symmetric := world.fieldByName("tbl_b", "自动列表")
+	require.NotNil(t, symmetric, "成员表中新建对称字段")
+	assert.Equal(t, symmetric.ID().String(), linkOptions.SymmetricFieldID)
+	require.NotNil(t, world.fieldByName("tbl_b", "参与项目"), "原对称字段不变")
+
+	lookup := world.fieldByName(copied, "成员姓名").Options().Lookup
+	assert.Equal(t, link.ID().String(), lookup.LinkFieldID)
+	assert.Equal(t, "fld_b_name", lookup.LookupFieldID)
+	count := world.fieldByName(copied, "成员数")
+	assert.Equal(t, fmt.Sprintf(`CONCATENATE({%s}, "-", {%s}, {名称})`, world.fieldByName(copied, "名称").ID(), count.ID()),
+		world.fieldByName(copied, "摘要").Options().Formula.Expression)
+
+	// 记录关联原来的成员记录，外键与对称字段由关联写入器补齐
+	require.Len(t, world.records[copied], 1)
+	record := world.records[copied][0]
+	assert.NotEqual(t, "rec_a1", record.ID().String())
+	cell := record.Data().ToMap()[link.ID().String()].([]interface{})
+	assert.Equal(t, "rec_b1", cell[0].(map[string]interface{})["id"])
+	require.Len(t, world.derived, 1)
+	assert.Equal(t, record.ID().String(), world.derived[0].RecordID)
+	assert.Equal(t, link.ID().String(), world.derived[0].FieldID)
+
+	view := world.viewRequests[len(world.viewRequests)-1]
+	assert.Equal(t, copied, view.TableID)
+	filters := view.Filter["filters"].([]interface{})
+	assert.Equal(t, []interface{}{"rec_b1"}, filters[1].(map[string]interface{})["value"])
+}
+
+func TestDuplicateTable_DropLinks(t *testing.T) {
+	world := newSourceWorld(t)
+	result, err := world.service().DuplicateTable(context.Background(), TableDuplicateRequest{
+		TableID:     "tbl_a",
+		Name:        "项目 副本",
+		WithFields:  true,
+		WithRecords: true,
+		LinkMode:    LinkModeDrop,
+		UserID:      "usr_2",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Fields, "只保留名称，依赖关联的查找、计数与公式一并跳过")
+	assert.Len(t, result.Warnings, 3)
+	assert.Zero(t, result.Views)
+	assert.Nil(t, world.fieldByName("tbl_b", "自动列表"))
+	require.Len(t, world.records[result.TableID], 1)
+	assert.Len(t, world.records[result.TableID][0].Data().ToMap(), 1)
+}
+
+func TestDuplicateTable_RollbackOnFailure(t *testing.T) {
+	world := newSourceWorld(t)
+	service := world.service()
+	service.writer = failingWriter{}
+
+	_, err := service.DuplicateTable(context.Background(), TableDuplicateRequest{
+		TableID:     "tbl_a",
+		Name:        "项目 副本",
+		WithFields:  true,
+		WithRecords: true,
+		UserID:      "usr_2",
+	})
+	require.Error(t, err)
+	require.Len(t, world.deletedTables, 1)
+	require.Len(t, world.deletedFields, 1, "删除指向成员表的关联字段（连同对称字段）")
+	assert.Equal(t, fieldValueObject.TypeLink, world.findField(world.deletedFields[0]).Type().String())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -15,6 +16,7 @@ import (
 	"github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
 	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	pkgDatabase "github.com/easyspace-ai/luckdb/server/pkg/database"
 	"gorm.io/gorm"
 	"github.com/easyspace-ai/luckdb/server/pkg/logger"
 )
@@ -138,8 +140,9 @@ func (s *BatchService) BatchUpdateRecordsWithStrategy(
 }
 
 // batchUpdateInTransaction 在事务中批量更新（全部成功或全部回滚）
+// 调用方已开启事务时加入该事务（gorm 以 SAVEPOINT 嵌套），随外层事务一起提交或回滚
 func (s *BatchService) batchUpdateInTransaction(ctx context.Context, updatesByTable map[string][]RecordUpdate) error {
-	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	return pkgDatabase.WithTx(ctx, s.db).WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// 临时保存原始 db，使用事务 db
 		originalDB := s.db
 		s.db = tx
@@ -562,15 +565,18 @@ func (s *BatchService) executeBatchUpdate(
 		return nil
 	}
 
+	// CASE 表达式的参数没有类型信息，PostgreSQL 会推断为 text，需要显式转换为列类型
+	columnType := s.columnType(ctx, fullTableName, dbFieldName)
+	jsonColumn := columnType == "jsonb" || columnType == "json"
+
 	// 构建批量 UPDATE SQL（使用 CASE WHEN）
 	recordIDs := make([]string, 0, len(recordValues))
 	caseClauses := make([]string, 0, len(recordValues))
 	args := make([]interface{}, 0, len(recordValues)*2)
 
 	for recordID, value := range recordValues {
-		recordIDs = append(recordIDs, recordID)
-		// 根据字段类型转换值
-		convertedValue, err := s.convertFieldValue(value, field)
+		// 根据字段类型转换值（转换失败的记录不参与更新，避免被 CASE 置为 NULL）
+		convertedValue, err := s.convertFieldValue(value, field, jsonColumn)
 		if err != nil {
 			logger.Warn("字段值转换失败，跳过",
 				logger.String("table_id", tableID),
@@ -579,6 +585,18 @@ func (s *BatchService) executeBatchUpdate(
 				logger.ErrorField(err))
 			continue
 		}
+		// 约束字段：空值按 NULL 写入，非空字段为空时拦截
+		if field.NotNull() || field.IsUnique() {
+			if pkgDatabase.IsBlankValue(convertedValue) {
+				convertedValue = nil
+			}
+			if convertedValue == nil && field.NotNull() {
+				return pkgDatabase.NotNullViolationError(field, map[string]interface{}{
+					"record_id": recordID,
+				})
+			}
+		}
+		recordIDs = append(recordIDs, recordID)
 		caseClauses = append(caseClauses, fmt.Sprintf("WHEN __id = $%d THEN $%d", len(args)+1, len(args)+2))
 		args = append(args, recordID, convertedValue)
 	}
@@ -587,27 +605,7 @@ func (s *BatchService) executeBatchUpdate(
 		return nil
 	}
 
-	// 构建 WHERE 子句的占位符（从 args 长度之后开始）
-	wherePlaceholders := make([]string, len(recordIDs))
-	for i := range recordIDs {
-		wherePlaceholders[i] = fmt.Sprintf("$%d", len(args)+i+1)
-	}
-
-	// 构建完整的 UPDATE SQL
-	updateSQL := fmt.Sprintf(`
-		UPDATE %s 
-		SET %s = CASE 
-			%s
-		END,
-		__last_modified_time = CURRENT_TIMESTAMP,
-		__version = __version + 1
-		WHERE __id IN (%s)
-	`, 
-		s.quoteIdentifier(fullTableName),
-		s.quoteIdentifier(dbFieldName),
-		strings.Join(caseClauses, " "),
-		strings.Join(wherePlaceholders, ", "),
-	)
+	updateSQL := s.batchUpdateSQL(fullTableName, dbFieldName, columnType, caseClauses, len(args), len(recordIDs))
 
 	// 添加 recordIDs 到参数列表
 	for _, recordID := range recordIDs {
@@ -621,6 +619,9 @@ func (s *BatchService) executeBatchUpdate(
 			logger.String("field_id", fieldID),
 			logger.String("db_field_name", dbFieldName),
 			logger.ErrorField(err))
+		if pkgDatabase.IsConstraintError(err) {
+			return pkgDatabase.HandleBatchConstraintError(err, tableID, s.fieldRepo, ctx)
+		}
 		return fmt.Errorf("批量更新字段失败: %w", err)
 	}
 
@@ -632,30 +633,102 @@ func (s *BatchService) executeBatchUpdate(
 	return nil
 }
 
-// convertFieldValue 根据字段类型转换值
-func (s *BatchService) convertFieldValue(value interface{}, field *fieldEntity.Field) (interface{}, error) {
-	fieldType := field.Type().String()
+// batchUpdateSQL 构建按记录ID批量更新单列的 UPDATE 语句
+//
+// caseClauses 占用前 argCount 个参数，WHERE __id IN 的 idCount 个占位符紧随其后；
+// columnType 非空时把 CASE 结果显式转换为列类型
+func (s *BatchService) batchUpdateSQL(fullTableName, column, columnType string, caseClauses []string, argCount, idCount int) string {
+	caseExpr := fmt.Sprintf("CASE %s END", strings.Join(caseClauses, " "))
+	if columnType != "" {
+		caseExpr = fmt.Sprintf("CAST(%s AS %s)", caseExpr, columnType)
+	}
+
+	wherePlaceholders := make([]string, idCount)
+	for i := range wherePlaceholders {
+		wherePlaceholders[i] = fmt.Sprintf("$%d", argCount+i+1)
+	}
+
+	return fmt.Sprintf(`
+		UPDATE %s 
+		SET %s = %s,
+		__last_modified_time = CURRENT_TIMESTAMP,
+		__version = __version + 1
+		WHERE __id IN (%s)
+	`,
+		s.quoteTableName(fullTableName),
+		s.quoteIdentifier(column),
+		caseExpr,
+		strings.Join(wherePlaceholders, ", "),
+	)
+}
 
-	switch fieldType {
+// convertFieldValue 根据字段类型转换值
+//
+// 值统一转换为文本，由 executeBatchUpdate 中的 CAST 转换为列类型；
+// JSONB 列（以及关联、多选字段）的值序列化为 JSON
+func (s *BatchService) convertFieldValue(value interface{}, field *fieldEntity.Field, jsonColumn bool) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	switch field.Type().String() {
 	case "link", "multipleSelects", "multipleRecordLinks":
-		// JSONB 类型，需要序列化为 JSON
-		if value == nil {
-			return nil, nil
-		}
+		jsonColumn = true
+	}
+	if jsonColumn {
 		jsonBytes, err := json.Marshal(value)
 		if err != nil {
 			return nil, fmt.Errorf("序列化 JSON 失败: %w", err)
 		}
 		return string(jsonBytes), nil
-	case "date", "dateTime":
-		// 日期类型，保持原样
-		return value, nil
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case int, int32, int64, json.Number:
+		return fmt.Sprintf("%v", v), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
 	default:
-		// 其他类型，直接返回
-		return value, nil
+		// 数组、对象等 JSONB 类型，需要序列化为 JSON
+		jsonBytes, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("序列化 JSON 失败: %w", err)
+		}
+		return string(jsonBytes), nil
 	}
 }
 
+// columnType 查询物理列的类型（如 numeric、timestamp with time zone、jsonb）
+//
+// 非 PostgreSQL 或查询失败时返回空字符串，由数据库自行推断
+func (s *BatchService) columnType(ctx context.Context, fullTableName, column string) string {
+	if s.dbProvider == nil || s.dbProvider.DriverName() != "postgres" {
+		return ""
+	}
+
+	var columnType string
+	err := s.db.WithContext(ctx).Raw(
+		`SELECT format_type(a.atttypid, a.atttypmod) FROM pg_attribute a
+		WHERE a.attrelid = to_regclass(?) AND a.attname = ? AND NOT a.attisdropped`,
+		s.quoteTableName(fullTableName), column,
+	).Scan(&columnType).Error
+	if err != nil {
+		logger.Warn("查询列类型失败",
+			logger.String("table", fullTableName),
+			logger.String("column", column),
+			logger.ErrorField(err))
+		return ""
+	}
+	return columnType
+}
+
 // quoteIdentifier 引用标识符（防止 SQL 注入）
 func (s *BatchService) quoteIdentifier(identifier string) string {
 	// PostgreSQL 使用双引号
@@ -663,6 +736,15 @@ func (s *BatchService) quoteIdentifier(identifier string) string {
 	return fmt.Sprintf(`"%s"`, cleaned)
 }
 
+// quoteTableName 引用完整表名（schema.table 分段引用）
+func (s *BatchService) quoteTableName(fullTableName string) string {
+	parts := strings.Split(fullTableName, ".")
+	for i, part := range parts {
+		parts[i] = s.quoteIdentifier(part)
+	}
+	return strings.Join(parts, ".")
+}
+
 
 // groupUpdatesByTable 按表分组更新
 func (s *BatchService) groupUpdatesByTable(updates []RecordUpdate) map[string][]RecordUpdate {
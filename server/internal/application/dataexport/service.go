@@ -0,0 +1,621 @@
+package dataexport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldService "github.com/easyspace-ai/luckdb/server/internal/domain/fields/service"
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/task"
+	viewRepo "github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
+	viewValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// 任务类型
+const (
+	TaskTypeCSVExport  = "export_csv"
+	TaskTypeXLSXExport = "export_xlsx"
+)
+
+// attachmentReadPath 附件没有可用链接时的读取地址（与附件读取路由一致）
+const attachmentReadPath = "/api/attachments/read/"
+
+// Config 导出配置
+type Config struct {
+	PageSize       int // 每页查询的记录数
+	StreamRowLimit int // 超过该行数的导出转为后台任务
+	MaxRows        int // 单次导出的最大行数
+}
+
+// DefaultConfig 默认导出配置
+func DefaultConfig() *Config {
+	return &Config{
+		PageSize:       1000,
+		StreamRowLimit: 50000,
+		MaxRows:        1000000,
+	}
+}
+
+// Service 数据导出服务
+//
+// 按表格或视图（过滤、排序与隐藏列）以键集分页逐页查询记录，单元格渲染为显示文本后逐行写出 CSV 或 XLSX，
+// 不在内存中保留整个表格。大表在后台导出到临时文件，再经 StorageProvider 保存，进度记录在 task / task_run 中
+type Service struct {
+	config     *Config
+	recordRepo recordRepo.RecordRepository
+	fieldRepo  fieldRepo.FieldRepository
+	tableRepo  tableRepo.TableRepository
+	viewRepo   viewRepo.ViewRepository
+	tasks      task.Repository
+	storage    attachment.StorageProvider
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewService 创建数据导出服务
+func NewService(
+	config *Config,
+	recordRepo recordRepo.RecordRepository,
+	fieldRepo fieldRepo.FieldRepository,
+	tableRepo tableRepo.TableRepository,
+	viewRepo viewRepo.ViewRepository,
+	tasks task.Repository,
+	storage attachment.StorageProvider,
+) *Service {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Service{
+		config:     config,
+		recordRepo: recordRepo,
+		fieldRepo:  fieldRepo,
+		tableRepo:  tableRepo,
+		viewRepo:   viewRepo,
+		tasks:      tasks,
+		storage:    storage,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Stop 停止服务，中断进行中的导出并等待其结束
+func (s *Service) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// ExportRequest 导出请求
+type ExportRequest struct {
+	TableID               string `json:"tableId"`
+	ViewID                string `json:"viewId,omitempty"`
+	Format                Format `json:"format"`
+	IncludeAttachmentURLs bool   `json:"includeAttachmentUrls,omitempty"` // 附件单元格附带文件链接
+	BaseURL               string `json:"baseUrl,omitempty"`               // 相对链接的前缀（如 https://example.com），为空时保留相对链接
+}
+
+// Plan 导出计划：目标表格、导出列与查询条件
+type Plan struct {
+	Request   ExportRequest
+	FileName  string
+	TotalRows int64
+
+	sheetName string
+	fields    []*fieldEntity.Field
+	filter    recordRepo.RecordFilter
+}
+
+// ShouldRunInBackground 行数超过流式导出上限时应转为后台任务
+func (s *Service) ShouldRunInBackground(plan *Plan) bool {
+	return plan.TotalRows > int64(s.config.StreamRowLimit)
+}
+
+// exportSnapshot 导出任务的参数（保存在 task.snapshot）
+type exportSnapshot struct {
+	ExportRequest
+	FileName string `json:"fileName"`
+}
+
+// exportResult 导出进度与结果（保存在 task_run.snapshot）
+type exportResult struct {
+	TotalRows    int64  `json:"totalRows"`
+	ExportedRows int    `json:"exportedRows"`
+	Path         string `json:"path,omitempty"` // 结果文件在存储中的路径
+	Size         int64  `json:"size,omitempty"`
+}
+
+// TaskStatus 导出任务状态
+type TaskStatus struct {
+	ID           string      `json:"id"`
+	Type         string      `json:"type"`
+	Status       task.Status `json:"status"`
+	TableID      string      `json:"tableId"`
+	ViewID       string      `json:"viewId,omitempty"`
+	Format       Format      `json:"format"`
+	FileName     string      `json:"fileName"`
+	Progress     float64     `json:"progress"` // 0-100
+	TotalRows    int64       `json:"totalRows"`
+	ExportedRows int         `json:"exportedRows"`
+	Size         int64       `json:"size,omitempty"` // 结果文件大小（字节）
+	DownloadURL  string      `json:"downloadUrl,omitempty"`
+	Error        string      `json:"error,omitempty"`
+	Spent        *int        `json:"spent,omitempty"` // 耗时（毫秒）
+	CreatedBy    string      `json:"createdBy"`
+	CreatedTime  time.Time   `json:"createdTime"`
+	StartedTime  *time.Time  `json:"startedTime,omitempty"`
+}
+
+// Prepare 解析表格、视图与导出列，统计导出行数
+func (s *Service) Prepare(ctx context.Context, req ExportRequest) (*Plan, error) {
+	table, err := s.tableRepo.GetByID(ctx, req.TableID)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取表格失败: %v", err))
+	}
+	if table == nil {
+		return nil, pkgerrors.ErrTableNotFound.WithDetails(req.TableID)
+	}
+
+	fields, err := s.fieldRepo.FindByTableID(ctx, req.TableID)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取字段列表失败: %v", err))
+	}
+
+	plan := &Plan{
+		Request:   req,
+		sheetName: table.Name().String(),
+		filter:    recordRepo.RecordFilter{TableID: &req.TableID},
+	}
+	name := table.Name().String()
+
+	if req.ViewID != "" {
+		view, err := s.viewRepo.FindByID(ctx, req.ViewID)
+		if err != nil {
+			return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找视图失败: %v", err))
+		}
+		if view == nil || view.IsDeleted() || view.TableID() != req.TableID {
+			return nil, pkgerrors.ErrViewNotFound.WithDetails(req.ViewID)
+		}
+		plan.filter.ApplyView(view.Filter(), view.Sort(), view.Group())
+		fields = visibleFields(fields, view.ColumnMeta())
+		name += "-" + view.Name()
+		plan.sheetName = view.Name()
+	}
+	if len(fields) == 0 {
+		return nil, pkgerrors.ErrBadRequest.WithDetails("没有可导出的列")
+	}
+	plan.fields = fields
+	plan.FileName = fileName(name, req.Format)
+
+	plan.filter.Projection = make([]string, len(fields))
+	for i, field := range fields {
+		plan.filter.Projection[i] = field.ID().String()
+	}
+
+	countFilter := plan.filter
+	countFilter.Limit = 1
+	page, err := s.recordRepo.ListPage(ctx, countFilter)
+	if err != nil {
+		return nil, queryError(err)
+	}
+	plan.TotalRows = page.Total
+	if plan.TotalRows > int64(s.config.MaxRows) {
+		return nil, pkgerrors.ErrBadRequest.WithDetails(
+			fmt.Sprintf("导出行数 %d 超过上限 %d，请通过视图过滤后分批导出", plan.TotalRows, s.config.MaxRows))
+	}
+	return plan, nil
+}
+
+// Write 逐页查询并写出导出文件，返回写出的数据行数。
+// w 实现 Flush() 时（如 HTTP 响应）每页写完即推送给客户端
+func (s *Service) Write(ctx context.Context, plan *Plan, w io.Writer) (int, error) {
+	return s.write(ctx, plan, w, nil)
+}
+
+// write 写出导出文件，每页写完后回调已写出的行数
+func (s *Service) write(ctx context.Context, plan *Plan, w io.Writer, progress func(written int)) (int, error) {
+	writer, err := NewRowWriter(plan.Request.Format, w, plan.sheetName)
+	if err != nil {
+		return 0, err
+	}
+
+	headers := make([]string, len(plan.fields))
+	for i, field := range plan.fields {
+		headers[i] = field.Name().String()
+	}
+	if err := writer.WriteRow(headers); err != nil {
+		return 0, err
+	}
+
+	filter := plan.filter
+	filter.Limit = s.config.PageSize
+	written := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+		page, err := s.recordRepo.ListPage(ctx, filter)
+		if err != nil {
+			return written, queryError(err)
+		}
+		for _, record := range page.Records {
+			if err := writer.WriteRow(s.rowCells(plan, record)); err != nil {
+				return written, err
+			}
+			written++
+		}
+		if err := writer.Flush(); err != nil {
+			return written, err
+		}
+		if f, ok := w.(interface{ Flush() }); ok {
+			f.Flush()
+		}
+		if progress != nil {
+			progress(written)
+		}
+		if page.NextCursor == nil || len(page.Records) == 0 {
+			break
+		}
+		filter.Cursor = page.NextCursor
+	}
+	return written, writer.Close()
+}
+
+// rowCells 记录按导出列渲染为显示文本
+func (s *Service) rowCells(plan *Plan, record *recordEntity.Record) []string {
+	data := record.Data().ToMap()
+	cells := make([]string, len(plan.fields))
+	for i, field := range plan.fields {
+		value := data[field.ID().String()]
+		if plan.Request.IncludeAttachmentURLs && field.Type().String() == fieldValueObject.TypeAttachment {
+			cells[i] = attachmentText(value, plan.Request.BaseURL)
+			continue
+		}
+		cells[i] = fieldService.FormatCellText(field, value)
+	}
+	return cells
+}
+
+// StartTask 创建导出任务并在后台导出，结果文件经 StorageProvider 保存，立即返回任务状态
+func (s *Service) StartTask(ctx context.Context, plan *Plan, userID string) (*TaskStatus, error) {
+	taskType := TaskTypeCSVExport
+	if plan.Request.Format == FormatXLSX {
+		taskType = TaskTypeXLSXExport
+	}
+
+	t := &task.Task{
+		ID:        utils.GenerateIDWithPrefix(utils.TaskIDPrefix),
+		Type:      taskType,
+		Status:    task.StatusRunning,
+		Snapshot:  marshalJSON(exportSnapshot{ExportRequest: plan.Request, FileName: plan.FileName}),
+		CreatedBy: userID,
+	}
+	if err := s.tasks.CreateTask(ctx, t); err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("创建导出任务失败: %v", err))
+	}
+
+	now := time.Now()
+	result := &exportResult{TotalRows: plan.TotalRows}
+	run := &task.Run{
+		ID:          utils.GenerateIDWithPrefix(utils.TaskRunIDPrefix),
+		TaskID:      t.ID,
+		Status:      task.StatusRunning,
+		Snapshot:    marshalJSON(result),
+		StartedTime: &now,
+	}
+	if err := s.tasks.CreateRun(ctx, run); err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("创建导出任务运行记录失败: %v", err))
+	}
+
+	status := buildTaskStatus(t, run)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.runTask(s.ctx, plan, t, run, result)
+	}()
+
+	logger.Info("导出任务已开始",
+		logger.String("task_id", t.ID),
+		logger.String("table_id", plan.Request.TableID),
+		logger.String("view_id", plan.Request.ViewID),
+		logger.Int64("rows", plan.TotalRows))
+	return status, nil
+}
+
+// runTask 导出到临时文件并上传到存储
+func (s *Service) runTask(ctx context.Context, plan *Plan, t *task.Task, run *task.Run, result *exportResult) {
+	started := time.Now()
+	saveRun := func(ctx context.Context, status task.Status, runErr error) {
+		spent := int(time.Since(started).Milliseconds())
+		run.Status = status
+		run.Snapshot = marshalJSON(result)
+		run.Spent = &spent
+		if runErr != nil {
+			run.ErrorMsg = runErr.Error()
+		}
+		if err := s.tasks.UpdateRun(ctx, run); err != nil {
+			logger.Warn("保存导出进度失败", logger.String("task_id", t.ID), logger.ErrorField(err))
+		}
+	}
+
+	runErr := s.exportToStorage(ctx, plan, t, result, func(written int) {
+		result.ExportedRows = written
+		saveRun(ctx, task.StatusRunning, nil)
+	})
+
+	status := task.StatusCompleted
+	if runErr != nil {
+		status = task.StatusFailed
+	}
+	// 服务停止时 ctx 已取消，最终状态使用独立的上下文保存
+	saveCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	saveRun(saveCtx, status, runErr)
+
+	t.Status = status
+	if err := s.tasks.UpdateTask(saveCtx, t); err != nil {
+		logger.Warn("更新导出任务状态失败", logger.String("task_id", t.ID), logger.ErrorField(err))
+	}
+
+	logger.Info("导出任务结束",
+		logger.String("task_id", t.ID),
+		logger.String("status", string(status)),
+		logger.Int("exported_rows", result.ExportedRows),
+		logger.Int64("size", result.Size),
+		logger.Duration("elapsed", time.Since(started)))
+}
+
+// exportToStorage 写出到临时文件后上传，路径为 exports/{taskId}/{fileName}
+func (s *Service) exportToStorage(ctx context.Context, plan *Plan, t *task.Task, result *exportResult, progress func(int)) error {
+	file, err := os.CreateTemp("", "luckdb-export-*")
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	written, err := s.write(ctx, plan, file, progress)
+	result.ExportedRows = written
+	if err != nil {
+		return fmt.Errorf("导出失败: %w", err)
+	}
+
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("读取临时文件失败: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("读取临时文件失败: %w", err)
+	}
+
+	storagePath := path.Join("exports", t.ID, plan.FileName)
+	if _, err := s.storage.Upload(ctx, attachment.UploadRequest{
+		Path:        storagePath,
+		Reader:      file,
+		Size:        size,
+		ContentType: plan.Request.Format.ContentType(),
+		Metadata: map[string]string{
+			"task_id":    t.ID,
+			"table_id":   plan.Request.TableID,
+			"created_by": t.CreatedBy,
+		},
+		Options: attachment.UploadOptions{Overwrite: true, CreateDir: true},
+	}); err != nil {
+		return fmt.Errorf("保存导出文件失败: %w", err)
+	}
+	result.Path = storagePath
+	result.Size = size
+	return nil
+}
+
+// GetTask 获取导出任务状态（只能查看自己发起的任务）
+func (s *Service) GetTask(ctx context.Context, taskID, userID string) (*TaskStatus, error) {
+	t, run, err := s.findTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return buildTaskStatus(t, run), nil
+}
+
+// OpenResult 打开已完成导出任务的结果文件，调用方负责关闭 Reader
+func (s *Service) OpenResult(ctx context.Context, taskID, userID string) (*attachment.DownloadResult, string, error) {
+	t, run, err := s.findTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var result exportResult
+	if t.Status != task.StatusCompleted || run == nil ||
+		json.Unmarshal([]byte(run.Snapshot), &result) != nil || result.Path == "" {
+		return nil, "", pkgerrors.ErrBadRequest.WithDetails("导出任务尚未完成")
+	}
+
+	download, err := s.storage.Download(ctx, result.Path)
+	if err != nil {
+		return nil, "", pkgerrors.ErrNotFound.WithDetails(map[string]interface{}{
+			"resource": "export_file",
+			"id":       taskID,
+		})
+	}
+	return download, path.Base(result.Path), nil
+}
+
+// findTask 查找导出任务与最近一次运行，非本人发起或不是导出任务时视为不存在
+func (s *Service) findTask(ctx context.Context, taskID, userID string) (*task.Task, *task.Run, error) {
+	t, err := s.tasks.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取导出任务失败: %v", err))
+	}
+	if t == nil || t.CreatedBy != userID || !strings.HasPrefix(t.Type, "export_") {
+		return nil, nil, pkgerrors.ErrNotFound.WithDetails(map[string]interface{}{
+			"resource": "export_task",
+			"id":       taskID,
+		})
+	}
+
+	run, err := s.tasks.FindLatestRun(ctx, taskID)
+	if err != nil {
+		return nil, nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取导出任务运行记录失败: %v", err))
+	}
+	return t, run, nil
+}
+
+// buildTaskStatus 由任务与最近一次运行组装任务状态
+func buildTaskStatus(t *task.Task, run *task.Run) *TaskStatus {
+	status := &TaskStatus{
+		ID:          t.ID,
+		Type:        t.Type,
+		Status:      t.Status,
+		CreatedBy:   t.CreatedBy,
+		CreatedTime: t.CreatedTime,
+	}
+
+	var snapshot exportSnapshot
+	if t.Snapshot != "" && json.Unmarshal([]byte(t.Snapshot), &snapshot) == nil {
+		status.TableID = snapshot.TableID
+		status.ViewID = snapshot.ViewID
+		status.Format = snapshot.Format
+		status.FileName = snapshot.FileName
+	}
+
+	if run == nil {
+		return status
+	}
+	status.Error = run.ErrorMsg
+	status.Spent = run.Spent
+	status.StartedTime = run.StartedTime
+
+	var result exportResult
+	if run.Snapshot != "" && json.Unmarshal([]byte(run.Snapshot), &result) == nil {
+		status.TotalRows = result.TotalRows
+		status.ExportedRows = result.ExportedRows
+		status.Size = result.Size
+		if result.TotalRows > 0 {
+			status.Progress = min(float64(result.ExportedRows)*100/float64(result.TotalRows), 100)
+		}
+	}
+	if t.Status == task.StatusCompleted {
+		status.Progress = 100
+	}
+	return status
+}
+
+// visibleFields 视图中可见的字段，按列顺序排列（没有列配置的字段可见，排在最后）
+func visibleFields(fields []*fieldEntity.Field, columnMeta *viewValueObject.ColumnMetaList) []*fieldEntity.Field {
+	if columnMeta.IsEmpty() {
+		return fields
+	}
+
+	columns := make(map[string]viewValueObject.ColumnMeta, len(columnMeta.Columns))
+	for _, col := range columnMeta.Columns {
+		columns[col.FieldID] = col
+	}
+
+	visible := make([]*fieldEntity.Field, 0, len(fields))
+	for _, field := range fields {
+		if col, ok := columns[field.ID().String()]; !ok || col.Visible {
+			visible = append(visible, field)
+		}
+	}
+	sort.SliceStable(visible, func(i, j int) bool {
+		a, aok := columns[visible[i].ID().String()]
+		b, bok := columns[visible[j].ID().String()]
+		if aok != bok {
+			return aok
+		}
+		return aok && a.Order < b.Order
+	})
+	return visible
+}
+
+// attachmentText 附件单元格：文件名附带链接，多个附件以 ", " 连接
+func attachmentText(value interface{}, baseURL string) string {
+	items, ok := value.([]interface{})
+	if !ok {
+		items = []interface{}{value}
+	}
+
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			if item != nil {
+				parts = append(parts, fmt.Sprintf("%v", item))
+			}
+			continue
+		}
+		name, _ := m["name"].(string)
+		url := ""
+		for _, key := range []string{"presigned_url", "presignedUrl", "url"} {
+			if s, ok := m[key].(string); ok && s != "" {
+				url = s
+				break
+			}
+		}
+		if p, ok := m["path"].(string); ok && url == "" && p != "" {
+			url = attachmentReadPath + strings.TrimPrefix(p, "/")
+		}
+		if strings.HasPrefix(url, "/") && baseURL != "" {
+			url = strings.TrimSuffix(baseURL, "/") + url
+		}
+
+		switch {
+		case name != "" && url != "":
+			parts = append(parts, fmt.Sprintf("%s (%s)", name, url))
+		case url != "":
+			parts = append(parts, url)
+		case name != "":
+			parts = append(parts, name)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// fileName 导出文件名：去掉文件名中不允许的字符
+func fileName(name string, format Format) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`\/:*?"<>|`, r) || r < 0x20 {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" {
+		name = "export"
+	}
+	return name + "." + string(format)
+}
+
+// queryError 查询记录失败时的错误（AppError 原样返回）
+func queryError(err error) error {
+	if appErr, ok := pkgerrors.IsAppError(err); ok {
+		return appErr
+	}
+	return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查询记录失败: %v", err))
+}
+
+func marshalJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,335 @@
+package dataexport
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	tableValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/table/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/task"
+	viewEntity "github.com/easyspace-ai/luckdb/server/internal/domain/view/entity"
+	viewRepo "github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
+	viewValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+func init() {
+	if logger.Logger == nil {
+		logger.Init(logger.LoggerConfig{
+			Level:      "error",
+			Format:     "console",
+			OutputPath: "stdout",
+		})
+	}
+}
+
+type fakeTableRepo struct {
+	tableRepo.TableRepository
+	tables map[string]*tableEntity.Table
+}
+
+func (r *fakeTableRepo) GetByID(ctx context.Context, id string) (*tableEntity.Table, error) {
+	return r.tables[id], nil
+}
+
+type fakeFieldRepo struct {
+	fieldRepo.FieldRepository
+	fields []*fieldEntity.Field
+}
+
+func (r *fakeFieldRepo) FindByTableID(ctx context.Context, tableID string) ([]*fieldEntity.Field, error) {
+	return r.fields, nil
+}
+
+type fakeViewRepo struct {
+	viewRepo.ViewRepository
+	views map[string]*viewEntity.View
+}
+
+func (r *fakeViewRepo) FindByID(ctx context.Context, id string) (*viewEntity.View, error) {
+	return r.views[id], nil
+}
+
+// fakeRecordRepo 按记录下标作为游标分页，记录查询条件
+type fakeRecordRepo struct {
+	recordRepo.RecordRepository
+	mu      sync.Mutex
+	records []*recordEntity.Record
+	filters []recordRepo.RecordFilter
+}
+
+func (r *fakeRecordRepo) ListPage(ctx context.Context, filter recordRepo.RecordFilter) (*recordRepo.RecordPage, error) {
+	r.mu.Lock()
+	r.filters = append(r.filters, filter)
+	r.mu.Unlock()
+
+	start := 0
+	if filter.Cursor != nil {
+		fmt.Sscanf(filter.Cursor.ID, "%d", &start)
+	}
+	end := min(start+filter.Limit, len(r.records))
+	page := &recordRepo.RecordPage{Records: r.records[start:end], Total: int64(len(r.records))}
+	if end < len(r.records) {
+		page.NextCursor = &recordValueObject.RecordCursor{ID: fmt.Sprint(end)}
+	}
+	return page, nil
+}
+
+type fakeTaskRepo struct {
+	mu    sync.Mutex
+	tasks map[string]task.Task
+	runs  map[string]task.Run
+}
+
+func (r *fakeTaskRepo) CreateTask(ctx context.Context, t *task.Task) error {
+	return r.UpdateTask(ctx, t)
+}
+
+func (r *fakeTaskRepo) UpdateTask(ctx context.Context, t *task.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tasks[t.ID] = *t
+	return nil
+}
+
+func (r *fakeTaskRepo) FindTaskByID(ctx context.Context, id string) (*task.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if t, ok := r.tasks[id]; ok {
+		return &t, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeTaskRepo) CreateRun(ctx context.Context, run *task.Run) error {
+	return r.UpdateRun(ctx, run)
+}
+
+func (r *fakeTaskRepo) UpdateRun(ctx context.Context, run *task.Run) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs[run.TaskID] = *run
+	return nil
+}
+
+func (r *fakeTaskRepo) FindLatestRun(ctx context.Context, taskID string) (*task.Run, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if run, ok := r.runs[taskID]; ok {
+		return &run, nil
+	}
+	return nil, nil
+}
+
+// fakeStorage 内存存储
+type fakeStorage struct {
+	attachment.StorageProvider
+	files map[string][]byte
+}
+
+func (s *fakeStorage) Upload(ctx context.Context, req attachment.UploadRequest) (*attachment.UploadResult, error) {
+	data, err := io.ReadAll(req.Reader)
+	if err != nil {
+		return nil, err
+	}
+	s.files[req.Path] = data
+	return &attachment.UploadResult{Path: req.Path, Size: int64(len(data))}, nil
+}
+
+func (s *fakeStorage) Download(ctx context.Context, path string) (*attachment.DownloadResult, error) {
+	data, ok := s.files[path]
+	if !ok {
+		return nil, fmt.Errorf("文件不存在: %s", path)
+	}
+	return &attachment.DownloadResult{Reader: io.NopCloser(bytes.NewReader(data)), Size: int64(len(data))}, nil
+}
+
+func newTestField(t *testing.T, id, name, fieldType string, options *fieldValueObject.FieldOptions) *fieldEntity.Field {
+	ft, err := fieldValueObject.NewFieldType(fieldType)
+	require.NoError(t, err)
+	fieldName, err := fieldValueObject.NewFieldName(name)
+	require.NoError(t, err)
+	dbFieldName, err := fieldValueObject.NewDBFieldNameFromString(id)
+	require.NoError(t, err)
+	if options == nil {
+		options = fieldValueObject.NewFieldOptions()
+	}
+	return fieldEntity.ReconstructField(
+		fieldValueObject.NewFieldID(id), "tbl_1", fieldName, ft, dbFieldName, "TEXT",
+		options, 0, 1, "usr_1", time.Now(), time.Now(),
+	)
+}
+
+func newTestRecord(t *testing.T, id string, data map[string]interface{}) *recordEntity.Record {
+	recordData, err := recordValueObject.NewRecordData(data)
+	require.NoError(t, err)
+	version, err := recordValueObject.NewRecordVersion(1)
+	require.NoError(t, err)
+	return recordEntity.ReconstructRecord(recordValueObject.NewRecordID(id), "tbl_1", recordData, version,
+		"usr_1", "usr_1", time.Now(), time.Now(), nil)
+}
+
+func newTestService(t *testing.T, rows int) (*Service, *fakeRecordRepo, *fakeStorage) {
+	tableName, err := tableValueObject.NewTableName("客户")
+	require.NoError(t, err)
+	table := tableEntity.ReconstructTable(tableValueObject.NewTableID("tbl_1"), "bse_1", tableName,
+		nil, nil, nil, "usr_1", time.Now(), time.Now(), nil, 1)
+
+	fields := []*fieldEntity.Field{
+		newTestField(t, "fld_name", "名称", fieldValueObject.TypeSingleLineText, nil),
+		newTestField(t, "fld_level", "等级", fieldValueObject.TypeSingleSelect,
+			fieldValueObject.NewFieldOptions().WithSelect([]fieldValueObject.SelectChoice{{ID: "cho_a", Name: "A 级"}})),
+		newTestField(t, "fld_files", "附件", fieldValueObject.TypeAttachment, nil),
+		newTestField(t, "fld_note", "备注", fieldValueObject.TypeLongText, nil),
+	}
+
+	records := &fakeRecordRepo{}
+	for i := 0; i < rows; i++ {
+		records.records = append(records.records, newTestRecord(t, fmt.Sprintf("rec_%d", i), map[string]interface{}{
+			"fld_name":  fmt.Sprintf("客户%d", i),
+			"fld_level": "cho_a",
+			"fld_files": []interface{}{map[string]interface{}{"name": "合同.pdf", "path": "2024/contract.pdf"}},
+			"fld_note":  "=HYPERLINK()",
+		}))
+	}
+
+	columnMeta, err := viewValueObject.NewColumnMetaList([]map[string]interface{}{
+		{"fieldId": "fld_files", "visible": true, "order": 1},
+		{"fieldId": "fld_name", "visible": true, "order": 2},
+		{"fieldId": "fld_note", "visible": false, "order": 3},
+	})
+	require.NoError(t, err)
+	filter, err := viewValueObject.NewFilter(map[string]interface{}{
+		"operator": "and",
+		"filters":  []interface{}{map[string]interface{}{"fieldId": "fld_level", "operator": "is", "value": "cho_a"}},
+	})
+	require.NoError(t, err)
+	sort, err := viewValueObject.NewSort([]map[string]interface{}{{"fieldId": "fld_name", "order": "desc"}})
+	require.NoError(t, err)
+	view := viewEntity.ReconstructView("viw_1", "VIP", "", "tbl_1", viewValueObject.ViewTypeGrid,
+		filter, sort, nil, columnMeta, nil, 0, 1, false, false, nil, nil, "usr_1", time.Now(), time.Now(), nil)
+
+	storage := &fakeStorage{files: map[string][]byte{}}
+	config := DefaultConfig()
+	config.PageSize = 2
+	config.StreamRowLimit = 3
+	service := NewService(config, records, &fakeFieldRepo{fields: fields},
+		&fakeTableRepo{tables: map[string]*tableEntity.Table{"tbl_1": table}},
+		&fakeViewRepo{views: map[string]*viewEntity.View{"viw_1": view}},
+		&fakeTaskRepo{tasks: map[string]task.Task{}, runs: map[string]task.Run{}}, storage)
+	return service, records, storage
+}
+
+func TestExport_TableCSV(t *testing.T) {
+	service, records, _ := newTestService(t, 3)
+	ctx := context.Background()
+
+	plan, err := service.Prepare(ctx, ExportRequest{TableID: "tbl_1", Format: FormatCSV})
+	require.NoError(t, err)
+	assert.Equal(t, "客户.csv", plan.FileName)
+	assert.Equal(t, int64(3), plan.TotalRows)
+	assert.False(t, service.ShouldRunInBackground(plan))
+
+	var buf bytes.Buffer
+	rows, err := service.Write(ctx, plan, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, 3, rows)
+	assert.Len(t, records.filters, 3, "统计一次，分两页查询")
+	assert.Equal(t, "2", records.filters[2].Cursor.ID)
+
+	lines, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(buf.String(), "\uFEFF"))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, lines, 4)
+	assert.Equal(t, []string{"名称", "等级", "附件", "备注"}, lines[0])
+	assert.Equal(t, []string{"客户0", "A 级", "合同.pdf", "'=HYPERLINK()"}, lines[1], "显示文本，公式被转义")
+}
+
+func TestExport_ViewColumnsAndAttachmentURLs(t *testing.T) {
+	service, records, _ := newTestService(t, 1)
+	ctx := context.Background()
+
+	plan, err := service.Prepare(ctx, ExportRequest{
+		TableID:               "tbl_1",
+		ViewID:                "viw_1",
+		Format:                FormatCSV,
+		IncludeAttachmentURLs: true,
+		BaseURL:               "https://example.com/",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "客户-VIP.csv", plan.FileName)
+
+	filter := records.filters[0]
+	require.NotNil(t, filter.Filter)
+	assert.Equal(t, "fld_level", filter.Filter.Filters[0].FieldID)
+	assert.Equal(t, []viewValueObject.SortItem{{FieldID: "fld_name", Order: "desc"}}, filter.Sort)
+	assert.Equal(t, []string{"fld_files", "fld_name", "fld_level"}, filter.Projection, "隐藏列不导出，无列配置的字段排在最后")
+
+	var buf bytes.Buffer
+	_, err = service.Write(ctx, plan, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, "\uFEFF附件,名称,等级\n"+
+		"合同.pdf (https://example.com/api/attachments/read/2024/contract.pdf),客户0,A 级\n", buf.String())
+
+	_, err = service.Prepare(ctx, ExportRequest{TableID: "tbl_1", ViewID: "viw_x"})
+	assert.Error(t, err)
+	_, err = service.Prepare(ctx, ExportRequest{TableID: "tbl_x"})
+	assert.Error(t, err)
+}
+
+func TestExport_BackgroundTask(t *testing.T) {
+	service, _, storage := newTestService(t, 5)
+	ctx := context.Background()
+
+	plan, err := service.Prepare(ctx, ExportRequest{TableID: "tbl_1", Format: FormatXLSX})
+	require.NoError(t, err)
+	assert.True(t, service.ShouldRunInBackground(plan))
+
+	status, err := service.StartTask(ctx, plan, "usr_1")
+	require.NoError(t, err)
+	assert.Equal(t, TaskTypeXLSXExport, status.Type)
+	assert.Equal(t, task.StatusRunning, status.Status)
+	service.wg.Wait()
+
+	got, err := service.GetTask(ctx, status.ID, "usr_1")
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusCompleted, got.Status)
+	assert.Equal(t, 5, got.ExportedRows)
+	assert.Equal(t, float64(100), got.Progress)
+	assert.Equal(t, "客户.xlsx", got.FileName)
+	assert.Contains(t, storage.files, "exports/"+status.ID+"/客户.xlsx")
+
+	result, fileName, err := service.OpenResult(ctx, status.ID, "usr_1")
+	require.NoError(t, err)
+	defer result.Reader.Close()
+	assert.Equal(t, "客户.xlsx", fileName)
+	assert.Equal(t, int64(got.Size), result.Size)
+
+	_, err = service.GetTask(ctx, status.ID, "usr_2")
+	assert.Error(t, err, "只能查看自己发起的任务")
+}
+
+func TestExport_MaxRows(t *testing.T) {
+	service, _, _ := newTestService(t, 3)
+	service.config.MaxRows = 2
+
+	_, err := service.Prepare(context.Background(), ExportRequest{TableID: "tbl_1", Format: FormatCSV})
+	assert.Error(t, err)
+}
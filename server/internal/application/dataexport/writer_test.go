@@ -0,0 +1,69 @@
+package dataexport
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dataimport"
+)
+
+func TestCSVWriter_BOMAndFormulaEscaping(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewRowWriter(FormatCSV, &buf, "")
+	require.NoError(t, err)
+	require.NoError(t, w.WriteRow([]string{"名称", "金额"}))
+	require.NoError(t, w.WriteRow([]string{"=SUM(A1)", "-12.5"}))
+	require.NoError(t, w.WriteRow([]string{"@cmd", "a,b"}))
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, "\uFEFF名称,金额\n'=SUM(A1),-12.5\n'@cmd,\"a,b\"\n", buf.String())
+}
+
+func TestXLSXWriter_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewRowWriter(FormatXLSX, &buf, "客户/列表")
+	require.NoError(t, err)
+	require.NoError(t, w.WriteRow([]string{"名称", "备注", "金额"}))
+	require.NoError(t, w.WriteRow([]string{"张三", "", "100"}))
+	require.NoError(t, w.Flush())
+	require.NoError(t, w.WriteRow([]string{"<李四> & 王五", " 前后空格 ", "=1+1"}))
+	require.NoError(t, w.Close())
+
+	workbook, err := dataimport.ParseXLSX(buf.Bytes(), dataimport.DefaultConfig().MaxRows)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"客户_列表"}, workbook.SheetNames())
+
+	sheet, err := workbook.Sheet("客户_列表", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"名称", "备注", "金额"}, sheet.Headers)
+	assert.Equal(t, [][]string{
+		{"张三", "", "100"},
+		{"<李四> & 王五", " 前后空格 ", "=1+1"},
+	}, sheet.Rows, "内联字符串不会被当作公式")
+}
+
+func TestXLSXSheetNameAndColumnName(t *testing.T) {
+	assert.Equal(t, "Sheet1", xlsxSheetName("  "))
+	assert.Equal(t, strings.Repeat("表", 31), xlsxSheetName(strings.Repeat("表", 40)))
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "BA", columnName(52))
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("")
+	require.NoError(t, err)
+	assert.Equal(t, FormatCSV, format)
+
+	format, err = ParseFormat("XLSX")
+	require.NoError(t, err)
+	assert.Equal(t, FormatXLSX, format)
+
+	_, err = ParseFormat("pdf")
+	assert.Error(t, err)
+}
@@ -0,0 +1,129 @@
+package dataimport
+
+import (
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/validation"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+const (
+	// maxSingleLineLength 超过该长度（字符数）的文本推断为长文本
+	maxSingleLineLength = 255
+	// maxSelectChoices 推断为单选时允许的最大不同取值数
+	maxSelectChoices = 20
+	// minSelectSamples 推断为单选所需的最少非空样本数
+	minSelectSamples = 10
+)
+
+// checkboxValues 可识别的布尔写法及其取值
+var checkboxValues = map[string]bool{
+	"true": true, "false": false, "yes": true, "no": false, "y": true, "n": false,
+	"是": true, "否": false, "对": true, "错": false, "√": true, "×": false,
+}
+
+// InferFieldType 根据列的样本值推断字段类型
+//
+// 依次判断：复选框、数字、日期（含时间时为日期时间）、邮箱、链接、长文本、单选（取值少且重复多），
+// 否则为单行文本。空值不参与判断
+func InferFieldType(values []string) string {
+	samples := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			samples = append(samples, v)
+		}
+	}
+	if len(samples) == 0 {
+		return valueobject.TypeSingleLineText
+	}
+
+	switch {
+	case all(samples, isCheckbox):
+		return valueobject.TypeCheckbox
+	case all(samples, isNumber):
+		return valueobject.TypeNumber
+	case all(samples, isDate):
+		if all(samples, func(v string) bool { return !strings.Contains(v, ":") }) {
+			return valueobject.TypeDate
+		}
+		return valueobject.TypeDateTime
+	case all(samples, isEmail):
+		return valueobject.TypeEmail
+	case all(samples, isURL):
+		return valueobject.TypeURL
+	}
+
+	for _, v := range values {
+		if strings.Contains(v, "\n") || utf8.RuneCountInString(v) > maxSingleLineLength {
+			return valueobject.TypeLongText
+		}
+	}
+
+	if len(samples) >= minSelectSamples {
+		distinct := make(map[string]struct{})
+		for _, v := range samples {
+			distinct[v] = struct{}{}
+		}
+		if len(distinct) <= maxSelectChoices && len(distinct)*2 <= len(samples) {
+			return valueobject.TypeSingleSelect
+		}
+	}
+
+	return valueobject.TypeSingleLineText
+}
+
+func all(values []string, fn func(string) bool) bool {
+	for _, v := range values {
+		if !fn(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func isCheckbox(v string) bool {
+	_, ok := parseCheckbox(v)
+	return ok
+}
+
+// parseCheckbox 解析布尔写法
+func parseCheckbox(v string) (bool, bool) {
+	b, ok := checkboxValues[strings.ToLower(strings.TrimSpace(v))]
+	return b, ok
+}
+
+// isNumber 数字（允许千分位、货币符号与百分号），排除以 0 开头的编号（如邮编、工号）
+func isNumber(v string) bool {
+	if len(v) > 1 && v[0] == '0' && v[1] != '.' {
+		return false
+	}
+	_, ok := validation.CoerceNumber(v)
+	return ok
+}
+
+// isDate 日期字符串（纯数字不视为时间戳）
+func isDate(v string) bool {
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return false
+	}
+	_, ok := validation.CoerceDate(v)
+	return ok
+}
+
+func isEmail(v string) bool {
+	addr, err := mail.ParseAddress(v)
+	return err == nil && addr.Address == v
+}
+
+func isURL(v string) bool {
+	if strings.IndexFunc(v, unicode.IsSpace) >= 0 {
+		return false
+	}
+	u, err := url.Parse(v)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
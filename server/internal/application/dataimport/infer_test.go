@@ -0,0 +1,47 @@
+package dataimport
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+func TestInferFieldType(t *testing.T) {
+	cases := []struct {
+		name   string
+		values []string
+		want   string
+	}{
+		{"空列", []string{"", " "}, valueobject.TypeSingleLineText},
+		{"数字", []string{"1,200", "3.5", "", "-2", "50%"}, valueobject.TypeNumber},
+		{"编号不是数字", []string{"001", "002"}, valueobject.TypeSingleLineText},
+		{"复选框", []string{"是", "否", "TRUE"}, valueobject.TypeCheckbox},
+		{"日期", []string{"2024-01-02", "2024/03/04", "2024年1月2日"}, valueobject.TypeDate},
+		{"日期时间", []string{"2024-01-02 10:00", "2024-01-03"}, valueobject.TypeDateTime},
+		{"邮箱", []string{"a@example.com", "b@example.com"}, valueobject.TypeEmail},
+		{"链接", []string{"https://example.com/a", "http://example.com"}, valueobject.TypeURL},
+		{"长文本", []string{"第一行\n第二行", "短"}, valueobject.TypeLongText},
+		{"超长文本", []string{strings.Repeat("长", maxSingleLineLength+1)}, valueobject.TypeLongText},
+		{"单选", strings.Split("进行中,已完成,进行中,待办,已完成,进行中,待办,进行中,已完成,进行中", ","), valueobject.TypeSingleSelect},
+		{"取值多不是单选", []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}, valueobject.TypeSingleLineText},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, InferFieldType(tc.values))
+		})
+	}
+}
+
+func TestFieldNamesFromHeaders(t *testing.T) {
+	names := fieldNamesFromHeaders([]string{"金额(元)", "金额_元_", "E-mail", "", strings.Repeat("名", 30)})
+
+	assert.Equal(t, "金额_元_", names[0])
+	assert.Equal(t, "金额_元__2", names[1])
+	assert.Equal(t, "E-mail", names[2])
+	assert.Equal(t, "列4", names[3])
+	assert.Equal(t, strings.Repeat("名", 21), names[4], "按字节截断到 64 以内")
+}
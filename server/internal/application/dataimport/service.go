@@ -0,0 +1,1046 @@
+package dataimport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	tableValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/table/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/task"
+	"github.com/easyspace-ai/luckdb/server/internal/events"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// 任务类型
+const (
+	TaskTypeCSVImport  = "import_csv"
+	TaskTypeXLSXImport = "import_xlsx"
+)
+
+// defaultTableName 未指定表格名称且无法从文件名得到时使用的名称
+const defaultTableName = "导入的表格"
+
+// creatableFieldTypes 新建表格时允许的字段类型（无需额外配置即可创建）
+var creatableFieldTypes = map[string]bool{
+	fieldValueObject.TypeSingleLineText: true,
+	fieldValueObject.TypeLongText:       true,
+	fieldValueObject.TypeNumber:         true,
+	fieldValueObject.TypeCheckbox:       true,
+	fieldValueObject.TypeDate:           true,
+	fieldValueObject.TypeDateTime:       true,
+	fieldValueObject.TypeEmail:          true,
+	fieldValueObject.TypeURL:            true,
+	fieldValueObject.TypePhone:          true,
+	fieldValueObject.TypeSingleSelect:   true,
+	fieldValueObject.TypeMultipleSelect: true,
+	fieldValueObject.TypeRating:         true,
+	fieldValueObject.TypePercent:        true,
+	fieldValueObject.TypeCurrency:       true,
+}
+
+// TableCreator 新建表格（由 TableService 实现）
+type TableCreator interface {
+	CreateTable(ctx context.Context, req dto.CreateTableRequest, userID string) (*dto.TableResponse, error)
+}
+
+// RecordWriter 批量写入记录（由 BatchService 实现）
+type RecordWriter interface {
+	BatchCreateRecords(ctx context.Context, tableID string, records []*recordEntity.Record) error
+}
+
+// Typecaster 宽松类型转换与验证（由 TypecastService 实现）
+type Typecaster interface {
+	TypecastRecordValues(ctx context.Context, tableID string, data map[string]interface{}) (map[string]interface{}, error)
+	ValidateAndTypecastRecord(ctx context.Context, tableID string, data map[string]interface{}, typecast bool) (map[string]interface{}, error)
+}
+
+// RecordCalculator 计算记录的虚拟字段（由 CalculationService 实现）
+type RecordCalculator interface {
+	CalculateRecordFieldsWithFields(ctx context.Context, record *recordEntity.Record, fields []*fieldEntity.Field) error
+}
+
+// RecordRules 表级记录校验规则（由 RecordRuleService 实现）
+type RecordRules interface {
+	AuthorizeSkip(ctx context.Context, tableID, userID string) error
+	RecordCheck(ctx context.Context, tableID string) (func(data map[string]interface{}) error, error)
+}
+
+// Config 导入配置
+type Config struct {
+	ChunkSize   int // 每批写入的记录数
+	PreviewRows int // 预览返回的数据行数
+	SampleRows  int // 推断字段类型时采样的行数
+	MaxRows     int // 单次导入的最大数据行数
+	MaxErrors   int // 报告中保留的错误条数
+}
+
+// DefaultConfig 默认导入配置
+func DefaultConfig() *Config {
+	return &Config{
+		ChunkSize:   500,
+		PreviewRows: 20,
+		SampleRows:  500,
+		MaxRows:     100000,
+		MaxErrors:   1000,
+	}
+}
+
+// Service 数据导入服务
+//
+// 解析上传的文件并预览，导入到新建表格或按列映射导入到已有表格。数据行按批经 BatchService 写入，
+// 单元格按 typecast 语义宽松转换；进度与逐行错误报告保存在 task / task_run 中
+type Service struct {
+	config     *Config
+	tables     TableCreator
+	tableRepo  tableRepo.TableRepository
+	fieldRepo  fieldRepo.FieldRepository
+	typecaster Typecaster
+	writer     RecordWriter
+	tasks      task.Repository
+
+	businessEvents events.BusinessEventPublisher // 可选：发布记录创建事件（实时推送、搜索索引）
+	calculator     RecordCalculator              // 可选：计算已有表格中的虚拟字段
+	rules          RecordRules                   // 可选：导入到已有表格时校验表级记录规则
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewService 创建数据导入服务
+func NewService(
+	config *Config,
+	tables TableCreator,
+	tableRepo tableRepo.TableRepository,
+	fieldRepo fieldRepo.FieldRepository,
+	typecaster Typecaster,
+	writer RecordWriter,
+	tasks task.Repository,
+) *Service {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Service{
+		config:     config,
+		tables:     tables,
+		tableRepo:  tableRepo,
+		fieldRepo:  fieldRepo,
+		typecaster: typecaster,
+		writer:     writer,
+		tasks:      tasks,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// SetBusinessEventPublisher 设置业务事件发布器
+func (s *Service) SetBusinessEventPublisher(publisher events.BusinessEventPublisher) {
+	s.businessEvents = publisher
+}
+
+// SetRecordCalculator 设置虚拟字段计算器
+func (s *Service) SetRecordCalculator(calculator RecordCalculator) {
+	s.calculator = calculator
+}
+
+// SetRecordRules 设置表级记录校验规则
+func (s *Service) SetRecordRules(rules RecordRules) {
+	s.rules = rules
+}
+
+// Stop 停止服务，中断进行中的导入并等待其结束
+func (s *Service) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// ColumnMapping 导入列的映射
+type ColumnMapping struct {
+	Index   int    `json:"index"`             // 列序号（从 0 开始）
+	Name    string `json:"name,omitempty"`    // 新建表格：字段名称，默认取列名
+	Type    string `json:"type,omitempty"`    // 新建表格：字段类型，默认按数据推断
+	FieldID string `json:"fieldId,omitempty"` // 已有表格：目标字段ID，为空时不导入该列
+}
+
+// ImportRequest 导入请求
+//
+// TableID 为空时新建表格（Columns 为空时导入全部列）；否则导入到该表格
+// （Columns 为空时按列名匹配同名字段）
+type ImportRequest struct {
+	BaseID    string          `json:"baseId"`
+	TableID   string          `json:"tableId,omitempty"`
+	TableName string          `json:"tableName,omitempty"`
+	FileName  string          `json:"fileName,omitempty"`
+	Columns   []ColumnMapping `json:"columns,omitempty"`
+	Options   ParseOptions    `json:"options"`
+
+	SkipRecordRules bool `json:"skipRecordRules,omitempty"` // 跳过表级记录校验规则（仅管理员，用于迁移历史数据）
+}
+
+// PreviewColumn 预览中的列
+type PreviewColumn struct {
+	Index     int    `json:"index"`
+	Name      string `json:"name"`      // 列名
+	FieldName string `json:"fieldName"` // 新建表格时的字段名称
+	Type      string `json:"type"`      // 推断的字段类型
+}
+
+// Preview 文件预览
+type Preview struct {
+	Encoding  string          `json:"encoding,omitempty"`
+	Delimiter string          `json:"delimiter,omitempty"`
+	Columns   []PreviewColumn `json:"columns"`
+	Rows      [][]string      `json:"rows"`
+	TotalRows int             `json:"totalRows"`
+}
+
+// SheetPreview 工作表预览
+type SheetPreview struct {
+	Name      string `json:"name"`
+	HeaderRow int    `json:"headerRow"` // 识别出的表头行号，0 表示无表头
+	*Preview
+}
+
+// WorkbookPreview XLSX 文件预览
+type WorkbookPreview struct {
+	Sheets []SheetPreview `json:"sheets"`
+}
+
+// SheetImport 一个工作表的导入设置
+//
+// TableID 为空时新建表格（名称默认取工作表名称），否则导入到该表格；列映射规则同 ImportRequest
+type SheetImport struct {
+	Name      string          `json:"name"`
+	TableID   string          `json:"tableId,omitempty"`
+	TableName string          `json:"tableName,omitempty"`
+	Columns   []ColumnMapping `json:"columns,omitempty"`
+	HeaderRow *int            `json:"headerRow,omitempty"` // 表头行号（从 1 开始，0 表示无表头），默认自动识别
+}
+
+// XLSXImportRequest XLSX 导入请求（Sheets 为空时把所有非空工作表各导入为新表格）
+type XLSXImportRequest struct {
+	BaseID   string        `json:"baseId"`
+	FileName string        `json:"fileName,omitempty"`
+	Sheets   []SheetImport `json:"sheets,omitempty"`
+
+	SkipRecordRules bool `json:"skipRecordRules,omitempty"` // 跳过表级记录校验规则（仅管理员）
+}
+
+// SheetTask 工作表的导入任务（启动失败时 Error 非空）
+type SheetTask struct {
+	Sheet string      `json:"sheet"`
+	Task  *TaskStatus `json:"task,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+// RowError 导入报告中的一条错误
+type RowError struct {
+	Row     int    `json:"row"`              // 文件中的行号（从 1 开始）
+	Column  string `json:"column,omitempty"` // 非空时为单元格错误：该行已导入，此单元格被忽略
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message"`
+}
+
+// Report 导入进度与结果报告（保存在 task_run.snapshot）
+type Report struct {
+	TotalRows       int        `json:"totalRows"`
+	ProcessedRows   int        `json:"processedRows"`
+	SuccessRows     int        `json:"successRows"`
+	FailedRows      int        `json:"failedRows"`
+	SkippedRows     int        `json:"skippedRows"` // 空行
+	Warnings        []string   `json:"warnings,omitempty"`
+	Errors          []RowError `json:"errors"`
+	ErrorsTruncated bool       `json:"errorsTruncated,omitempty"`
+}
+
+// addError 记录错误，超过 maxErrors 条后不再保留，仅标记截断
+func (r *Report) addError(maxErrors int, rowErr RowError) {
+	if len(r.Errors) >= maxErrors {
+		r.ErrorsTruncated = true
+		return
+	}
+	r.Errors = append(r.Errors, rowErr)
+}
+
+// importSnapshot 导入任务的参数（保存在 task.snapshot）
+type importSnapshot struct {
+	BaseID    string          `json:"baseId"`
+	TableID   string          `json:"tableId,omitempty"`
+	TableName string          `json:"tableName,omitempty"`
+	FileName  string          `json:"fileName,omitempty"`
+	Sheet     string          `json:"sheet,omitempty"`
+	HeaderRow int             `json:"headerRow,omitempty"`
+	Encoding  string          `json:"encoding,omitempty"`
+	Delimiter string          `json:"delimiter,omitempty"`
+	Columns   []ColumnMapping `json:"columns,omitempty"`
+}
+
+// TaskStatus 导入任务状态
+type TaskStatus struct {
+	ID          string      `json:"id"`
+	Type        string      `json:"type"`
+	Status      task.Status `json:"status"`
+	BaseID      string      `json:"baseId"`
+	TableID     string      `json:"tableId,omitempty"`
+	FileName    string      `json:"fileName,omitempty"`
+	Sheet       string      `json:"sheet,omitempty"`
+	Progress    float64     `json:"progress"` // 0-100
+	Report      *Report     `json:"report,omitempty"`
+	Error       string      `json:"error,omitempty"`
+	Spent       *int        `json:"spent,omitempty"` // 耗时（毫秒）
+	CreatedBy   string      `json:"createdBy"`
+	CreatedTime time.Time   `json:"createdTime"`
+	StartedTime *time.Time  `json:"startedTime,omitempty"`
+}
+
+// boundColumn 已绑定到字段的导入列
+type boundColumn struct {
+	index int
+	name  string
+	field *fieldEntity.Field
+}
+
+// importJob 一次导入的执行上下文
+type importJob struct {
+	task    *task.Task
+	run     *task.Run
+	tableID string
+	userID  string
+	sheet   *Sheet
+	columns []boundColumn
+	fields  []*fieldEntity.Field
+	report  *Report
+
+	checkRules func(data map[string]interface{}) error // 表级记录校验，nil 表示不校验
+}
+
+// PreviewCSV 解析 CSV 文件，返回识别出的编码、分隔符、列（含推断的字段类型）与前若干行
+func (s *Service) PreviewCSV(data []byte, opts ParseOptions) (*Preview, error) {
+	file, err := ParseCSV(data, opts)
+	if err != nil {
+		return nil, err
+	}
+	preview := s.previewSheet(file.Sheet)
+	preview.Encoding = file.Encoding
+	preview.Delimiter = file.Delimiter
+	return preview, nil
+}
+
+// previewSheet 生成表格数据的预览
+func (s *Service) previewSheet(sheet *Sheet) *Preview {
+	rows := sheet.Rows[:min(len(sheet.Rows), s.config.PreviewRows)]
+	names := fieldNamesFromHeaders(sheet.Headers)
+	columns := make([]PreviewColumn, len(sheet.Headers))
+	for i, header := range sheet.Headers {
+		columns[i] = PreviewColumn{
+			Index:     i,
+			Name:      header,
+			FieldName: names[i],
+			Type:      s.inferColumnType(sheet, i),
+		}
+	}
+	return &Preview{Columns: columns, Rows: rows, TotalRows: len(sheet.Rows)}
+}
+
+// inferColumnType 列的字段类型：优先使用列类型提示，否则按前 SampleRows 行推断
+func (s *Service) inferColumnType(sheet *Sheet, index int) string {
+	if index < len(sheet.ColumnTypes) && sheet.ColumnTypes[index] != "" {
+		return sheet.ColumnTypes[index]
+	}
+	n := min(len(sheet.Rows), s.config.SampleRows)
+	values := make([]string, n)
+	for i := 0; i < n; i++ {
+		values[i] = sheet.Rows[i][index]
+	}
+	return InferFieldType(values)
+}
+
+// ImportCSV 解析 CSV 文件并在后台导入，立即返回导入任务
+func (s *Service) ImportCSV(ctx context.Context, req ImportRequest, data []byte, userID string) (*TaskStatus, error) {
+	file, err := ParseCSV(data, req.Options)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := importSnapshot{Encoding: file.Encoding, Delimiter: file.Delimiter}
+	return s.startImport(ctx, TaskTypeCSVImport, req, file.Sheet, snapshot, userID)
+}
+
+// PreviewXLSX 解析 XLSX 文件，返回每个工作表识别出的表头行、列（含推断的字段类型）与前若干行
+func (s *Service) PreviewXLSX(data []byte) (*WorkbookPreview, error) {
+	workbook, err := ParseXLSX(data, s.config.MaxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	names := workbook.SheetNames()
+	preview := &WorkbookPreview{Sheets: make([]SheetPreview, 0, len(names))}
+	for _, name := range names {
+		sheet, err := workbook.Sheet(name, nil)
+		if err != nil {
+			return nil, err
+		}
+		preview.Sheets = append(preview.Sheets, SheetPreview{
+			Name:      name,
+			HeaderRow: sheet.HeaderRow,
+			Preview:   s.previewSheet(sheet),
+		})
+	}
+	return preview, nil
+}
+
+// ImportXLSX 解析 XLSX 文件，每个工作表创建一个导入任务并在后台导入
+//
+// 工作表之间互不影响：某个工作表启动失败时记录在其结果中，其余工作表照常导入；全部失败时返回第一个错误
+func (s *Service) ImportXLSX(ctx context.Context, req XLSXImportRequest, data []byte, userID string) ([]SheetTask, error) {
+	workbook, err := ParseXLSX(data, s.config.MaxRows)
+	if err != nil {
+		return nil, err
+	}
+
+	sheets := req.Sheets
+	if len(sheets) == 0 {
+		for _, name := range workbook.nonEmptySheetNames() {
+			sheets = append(sheets, SheetImport{Name: name})
+		}
+		if len(sheets) == 0 {
+			return nil, pkgerrors.ErrBadRequest.WithDetails("文件中没有可导入的工作表")
+		}
+	}
+
+	// 先校验工作表名称，避免部分工作表已开始导入后才发现请求有误
+	known := make(map[string]bool)
+	for _, name := range workbook.SheetNames() {
+		known[name] = true
+	}
+	seen := make(map[string]bool, len(sheets))
+	for _, item := range sheets {
+		if !known[item.Name] {
+			return nil, pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("工作表不存在: %s", item.Name))
+		}
+		if seen[item.Name] {
+			return nil, pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("工作表 %s 重复", item.Name))
+		}
+		seen[item.Name] = true
+	}
+
+	results := make([]SheetTask, len(sheets))
+	var firstErr error
+	for i, item := range sheets {
+		results[i].Sheet = item.Name
+		results[i].Task, err = s.importSheet(ctx, req, workbook, item, userID)
+		if err != nil {
+			results[i].Error = errorMessage(err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	for _, result := range results {
+		if result.Task != nil {
+			return results, nil
+		}
+	}
+	return nil, firstErr
+}
+
+// importSheet 启动单个工作表的导入
+func (s *Service) importSheet(ctx context.Context, req XLSXImportRequest, workbook *Workbook, item SheetImport, userID string) (*TaskStatus, error) {
+	sheet, err := workbook.Sheet(item.Name, item.HeaderRow)
+	if err != nil {
+		return nil, err
+	}
+	if len(sheet.Headers) == 0 {
+		return nil, pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("工作表 %s 为空", item.Name))
+	}
+
+	tableName := item.TableName
+	if tableName == "" {
+		tableName = item.Name
+	}
+	snapshot := importSnapshot{Sheet: item.Name, HeaderRow: sheet.HeaderRow}
+	return s.startImport(ctx, TaskTypeXLSXImport, ImportRequest{
+		BaseID:    req.BaseID,
+		TableID:   item.TableID,
+		TableName: tableName,
+		FileName:  req.FileName,
+		Columns:   item.Columns,
+
+		SkipRecordRules: req.SkipRecordRules,
+	}, sheet, snapshot, userID)
+}
+
+// startImport 创建导入任务、准备目标表格与列映射，然后在后台写入数据
+func (s *Service) startImport(ctx context.Context, taskType string, req ImportRequest, sheet *Sheet, snapshot importSnapshot, userID string) (*TaskStatus, error) {
+	if len(sheet.Rows) > s.config.MaxRows {
+		return nil, pkgerrors.ErrBadRequest.WithDetails(
+			fmt.Sprintf("数据行数 %d 超过单次导入上限 %d", len(sheet.Rows), s.config.MaxRows))
+	}
+
+	snapshot.BaseID = req.BaseID
+	snapshot.TableID = req.TableID
+	snapshot.TableName = req.TableName
+	snapshot.FileName = req.FileName
+	snapshot.Columns = req.Columns
+
+	t := &task.Task{
+		ID:        utils.GenerateIDWithPrefix(utils.TaskIDPrefix),
+		Type:      taskType,
+		Status:    task.StatusPending,
+		Snapshot:  marshalJSON(snapshot),
+		CreatedBy: userID,
+	}
+	if err := s.tasks.CreateTask(ctx, t); err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("创建导入任务失败: %v", err))
+	}
+
+	job, err := s.prepareJob(ctx, t, req, sheet, userID)
+	if err != nil {
+		t.Status = task.StatusFailed
+		if updateErr := s.tasks.UpdateTask(ctx, t); updateErr != nil {
+			logger.Warn("更新导入任务状态失败", logger.String("task_id", t.ID), logger.ErrorField(updateErr))
+		}
+		return nil, err
+	}
+
+	// 新建的表格写回任务参数
+	snapshot.TableID = job.tableID
+	t.Snapshot = marshalJSON(snapshot)
+	t.Status = task.StatusRunning
+	if err := s.tasks.UpdateTask(ctx, t); err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("更新导入任务失败: %v", err))
+	}
+
+	now := time.Now()
+	job.run = &task.Run{
+		ID:          utils.GenerateIDWithPrefix(utils.TaskRunIDPrefix),
+		TaskID:      t.ID,
+		Status:      task.StatusRunning,
+		Snapshot:    marshalJSON(job.report),
+		StartedTime: &now,
+	}
+	if err := s.tasks.CreateRun(ctx, job.run); err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("创建导入任务运行记录失败: %v", err))
+	}
+
+	status := buildTaskStatus(t, job.run)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.runImport(s.ctx, job)
+	}()
+
+	logger.Info("导入任务已开始",
+		logger.String("task_id", t.ID),
+		logger.String("table_id", job.tableID),
+		logger.Int("rows", len(sheet.Rows)),
+		logger.Int("columns", len(job.columns)))
+	return status, nil
+}
+
+// prepareJob 确定目标表格并把导入列绑定到字段
+func (s *Service) prepareJob(ctx context.Context, t *task.Task, req ImportRequest, sheet *Sheet, userID string) (*importJob, error) {
+	for _, mapping := range req.Columns {
+		if mapping.Index < 0 || mapping.Index >= len(sheet.Headers) {
+			return nil, pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("列序号 %d 超出范围（共 %d 列）", mapping.Index, len(sheet.Headers)))
+		}
+	}
+
+	job := &importJob{
+		task:   t,
+		userID: userID,
+		sheet:  sheet,
+		report: &Report{TotalRows: len(sheet.Rows), Errors: []RowError{}},
+	}
+
+	var err error
+	if req.TableID == "" {
+		err = s.prepareNewTable(ctx, job, req)
+	} else {
+		err = s.prepareExistingTable(ctx, job, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(job.columns) == 0 {
+		return nil, pkgerrors.ErrBadRequest.WithDetails("没有可导入的列")
+	}
+
+	// 导入到已有表格时按表级记录校验规则逐行校验，管理员可跳过
+	if req.TableID != "" && s.rules != nil {
+		if req.SkipRecordRules {
+			if err := s.rules.AuthorizeSkip(ctx, job.tableID, userID); err != nil {
+				return nil, err
+			}
+		} else if job.checkRules, err = s.rules.RecordCheck(ctx, job.tableID); err != nil {
+			return nil, err
+		}
+	}
+	return job, nil
+}
+
+// prepareNewTable 按列新建表格（字段类型默认按数据推断）
+func (s *Service) prepareNewTable(ctx context.Context, job *importJob, req ImportRequest) error {
+	mappings := req.Columns
+	if len(mappings) == 0 {
+		mappings = make([]ColumnMapping, len(job.sheet.Headers))
+		for i := range mappings {
+			mappings[i] = ColumnMapping{Index: i}
+		}
+	}
+
+	names := make([]string, len(mappings))
+	for i, mapping := range mappings {
+		names[i] = mapping.Name
+		if names[i] == "" {
+			names[i] = job.sheet.Headers[mapping.Index]
+		}
+	}
+	names = fieldNamesFromHeaders(names)
+
+	fieldConfigs := make([]dto.FieldConfigDTO, len(mappings))
+	for i, mapping := range mappings {
+		fieldType := mapping.Type
+		if fieldType == "" {
+			fieldType = s.inferColumnType(job.sheet, mapping.Index)
+		}
+		if !creatableFieldTypes[fieldType] {
+			return pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("列 %s 的字段类型 %s 不支持导入时创建", job.sheet.Headers[mapping.Index], fieldType))
+		}
+		fieldConfigs[i] = dto.FieldConfigDTO{Name: names[i], Type: fieldType, IsPrimary: i == 0}
+	}
+
+	table, err := s.tables.CreateTable(ctx, dto.CreateTableRequest{
+		Name:   tableNameFor(req),
+		BaseID: req.BaseID,
+		Fields: fieldConfigs,
+	}, job.userID)
+	if err != nil {
+		return err
+	}
+	job.tableID = table.ID
+
+	if job.fields, err = s.fieldRepo.FindByTableID(ctx, table.ID); err != nil {
+		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取字段列表失败: %v", err))
+	}
+	fieldsByName := make(map[string]*fieldEntity.Field, len(job.fields))
+	for _, field := range job.fields {
+		fieldsByName[field.Name().String()] = field
+	}
+
+	for i, mapping := range mappings {
+		header := job.sheet.Headers[mapping.Index]
+		field, ok := fieldsByName[names[i]]
+		if !ok {
+			job.report.Warnings = append(job.report.Warnings, fmt.Sprintf("列 %s 的字段创建失败，未导入", header))
+			continue
+		}
+		job.columns = append(job.columns, boundColumn{index: mapping.Index, name: header, field: field})
+	}
+	return nil
+}
+
+// prepareExistingTable 把列映射到已有表格的字段（未指定映射时按列名匹配同名字段）
+func (s *Service) prepareExistingTable(ctx context.Context, job *importJob, req ImportRequest) error {
+	table, err := s.tableRepo.GetByID(ctx, req.TableID)
+	if err != nil {
+		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取表格失败: %v", err))
+	}
+	if table == nil || table.BaseID() != req.BaseID {
+		return pkgerrors.ErrNotFound.WithDetails(map[string]interface{}{
+			"resource": "table",
+			"id":       req.TableID,
+		})
+	}
+	job.tableID = req.TableID
+
+	if job.fields, err = s.fieldRepo.FindByTableID(ctx, req.TableID); err != nil {
+		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取字段列表失败: %v", err))
+	}
+
+	if len(req.Columns) == 0 {
+		fieldsByName := make(map[string]*fieldEntity.Field, len(job.fields))
+		for _, field := range job.fields {
+			if !field.IsComputed() {
+				fieldsByName[strings.ToLower(field.Name().String())] = field
+			}
+		}
+		for i, header := range job.sheet.Headers {
+			if field, ok := fieldsByName[strings.ToLower(header)]; ok {
+				job.columns = append(job.columns, boundColumn{index: i, name: header, field: field})
+			}
+		}
+		return nil
+	}
+
+	fieldsByID := make(map[string]*fieldEntity.Field, len(job.fields))
+	for _, field := range job.fields {
+		fieldsByID[field.ID().String()] = field
+	}
+	mapped := make(map[string]bool, len(req.Columns))
+	for _, mapping := range req.Columns {
+		if mapping.FieldID == "" {
+			continue
+		}
+		field, ok := fieldsByID[mapping.FieldID]
+		if !ok {
+			return pkgerrors.ErrFieldNotFound.WithDetails(map[string]interface{}{
+				"field_id": mapping.FieldID,
+				"table_id": req.TableID,
+			})
+		}
+		if field.IsComputed() {
+			return pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("字段 %s 为计算字段，不能导入", field.Name().String()))
+		}
+		if mapped[mapping.FieldID] {
+			return pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("字段 %s 被多个列映射", field.Name().String()))
+		}
+		mapped[mapping.FieldID] = true
+		job.columns = append(job.columns, boundColumn{index: mapping.Index, name: job.sheet.Headers[mapping.Index], field: field})
+	}
+	return nil
+}
+
+// runImport 按批写入数据行并持久化进度
+func (s *Service) runImport(ctx context.Context, job *importJob) {
+	started := time.Now()
+	report := job.report
+	rows := job.sheet.Rows
+
+	var runErr error
+	for start := 0; start < len(rows); start += s.config.ChunkSize {
+		if ctx.Err() != nil {
+			runErr = fmt.Errorf("导入被中断: %w", ctx.Err())
+			break
+		}
+		end := min(start+s.config.ChunkSize, len(rows))
+		s.importChunk(ctx, job, start, end)
+		report.ProcessedRows = end
+		if end < len(rows) {
+			s.saveProgress(ctx, job, task.StatusRunning, started, nil)
+		}
+	}
+
+	status := task.StatusCompleted
+	if runErr != nil {
+		status = task.StatusFailed
+	}
+	// 服务停止时 ctx 已取消，最终状态使用独立的上下文保存
+	saveCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	s.saveProgress(saveCtx, job, status, started, runErr)
+
+	job.task.Status = status
+	if err := s.tasks.UpdateTask(saveCtx, job.task); err != nil {
+		logger.Warn("更新导入任务状态失败", logger.String("task_id", job.task.ID), logger.ErrorField(err))
+	}
+
+	logger.Info("导入任务结束",
+		logger.String("task_id", job.task.ID),
+		logger.String("table_id", job.tableID),
+		logger.String("status", string(status)),
+		logger.Int("success_rows", report.SuccessRows),
+		logger.Int("failed_rows", report.FailedRows),
+		logger.Duration("elapsed", time.Since(started)))
+}
+
+// importChunk 转换并写入 [start, end) 行
+func (s *Service) importChunk(ctx context.Context, job *importJob, start, end int) {
+	records := make([]*recordEntity.Record, 0, end-start)
+	rowNumbers := make([]int, 0, end-start)
+	for i := start; i < end; i++ {
+		rowNumber := job.sheet.RowNumber(i)
+		record, err := s.buildRecord(ctx, job, rowNumber, job.sheet.Rows[i])
+		if err != nil {
+			job.report.FailedRows++
+			job.report.addError(s.config.MaxErrors, RowError{Row: rowNumber, Message: errorMessage(err)})
+			continue
+		}
+		if record == nil {
+			job.report.SkippedRows++
+			continue
+		}
+		records = append(records, record)
+		rowNumbers = append(rowNumbers, rowNumber)
+	}
+	if len(records) == 0 {
+		return
+	}
+
+	written := records
+	if err := s.writer.BatchCreateRecords(ctx, job.tableID, records); err != nil {
+		// 整批失败时逐条写入以定位失败的行（按记录ID保存，已写入的记录不会重复）
+		logger.Warn("批量写入导入数据失败，改为逐条写入",
+			logger.String("task_id", job.task.ID),
+			logger.Int("batch_size", len(records)),
+			logger.ErrorField(err))
+		written = written[:0:0]
+		for i, record := range records {
+			if err := s.writer.BatchCreateRecords(ctx, job.tableID, []*recordEntity.Record{record}); err != nil {
+				job.report.FailedRows++
+				job.report.addError(s.config.MaxErrors, RowError{Row: rowNumbers[i], Message: errorMessage(err)})
+				continue
+			}
+			written = append(written, record)
+		}
+	}
+	job.report.SuccessRows += len(written)
+	s.afterWrite(ctx, job, written)
+}
+
+// buildRecord 把一行数据按 typecast 语义转换为记录，空行返回 nil。
+// 无法转换的单元格被忽略并记入报告，整行都无法转换时返回错误
+func (s *Service) buildRecord(ctx context.Context, job *importJob, rowNumber int, row []string) (*recordEntity.Record, error) {
+	data := make(map[string]interface{}, len(job.columns))
+	cells := make(map[string]boundColumn, len(job.columns))
+	for _, col := range job.columns {
+		raw := strings.TrimSpace(row[col.index])
+		if raw == "" {
+			continue
+		}
+		fieldID := col.field.ID().String()
+		data[fieldID] = cellValue(col.field, raw)
+		cells[fieldID] = col
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	converted, err := s.typecaster.TypecastRecordValues(ctx, job.tableID, data)
+	if err != nil {
+		return nil, err
+	}
+	validated, err := s.typecaster.ValidateAndTypecastRecord(ctx, job.tableID, converted, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(validated) == 0 {
+		return nil, fmt.Errorf("没有可转换为字段类型的单元格")
+	}
+	if job.checkRules != nil {
+		if err := job.checkRules(validated); err != nil {
+			return nil, err
+		}
+	}
+
+	for fieldID, col := range cells {
+		if _, ok := validated[fieldID]; !ok {
+			job.report.addError(s.config.MaxErrors, RowError{
+				Row:     rowNumber,
+				Column:  col.name,
+				Value:   strings.TrimSpace(row[col.index]),
+				Message: fmt.Sprintf("无法转换为 %s 类型，已忽略", col.field.Type().String()),
+			})
+		}
+	}
+
+	recordData, err := recordValueObject.NewRecordData(validated)
+	if err != nil {
+		return nil, err
+	}
+	return recordEntity.NewRecord(job.tableID, recordData, job.userID)
+}
+
+// afterWrite 计算虚拟字段并发布记录创建事件
+func (s *Service) afterWrite(ctx context.Context, job *importJob, records []*recordEntity.Record) {
+	if s.calculator != nil && hasComputedField(job.fields) {
+		for _, record := range records {
+			if err := s.calculator.CalculateRecordFieldsWithFields(ctx, record, job.fields); err != nil {
+				logger.Warn("导入记录虚拟字段计算失败（不影响导入）",
+					logger.String("record_id", record.ID().String()),
+					logger.ErrorField(err))
+			}
+		}
+	}
+
+	if s.businessEvents == nil {
+		return
+	}
+	for _, record := range records {
+		if err := s.businessEvents.PublishRecordEvent(ctx, events.BusinessEventTypeRecordCreate, job.tableID,
+			record.ID().String(), record.Data().ToMap(), job.userID, record.Version().Value()); err != nil {
+			logger.Warn("发布导入记录事件失败",
+				logger.String("record_id", record.ID().String()),
+				logger.ErrorField(err))
+		}
+	}
+}
+
+// saveProgress 保存运行进度与报告
+func (s *Service) saveProgress(ctx context.Context, job *importJob, status task.Status, started time.Time, runErr error) {
+	spent := int(time.Since(started).Milliseconds())
+	job.run.Status = status
+	job.run.Snapshot = marshalJSON(job.report)
+	job.run.Spent = &spent
+	if runErr != nil {
+		job.run.ErrorMsg = runErr.Error()
+	}
+	if err := s.tasks.UpdateRun(ctx, job.run); err != nil {
+		logger.Warn("保存导入进度失败",
+			logger.String("task_id", job.task.ID),
+			logger.ErrorField(err))
+	}
+}
+
+// GetTask 获取导入任务状态（只能查看自己发起的任务）
+func (s *Service) GetTask(ctx context.Context, taskID, userID string) (*TaskStatus, error) {
+	t, err := s.tasks.FindTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取导入任务失败: %v", err))
+	}
+	if t == nil || t.CreatedBy != userID || !strings.HasPrefix(t.Type, "import_") {
+		return nil, pkgerrors.ErrNotFound.WithDetails(map[string]interface{}{
+			"resource": "import_task",
+			"id":       taskID,
+		})
+	}
+
+	run, err := s.tasks.FindLatestRun(ctx, taskID)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取导入任务运行记录失败: %v", err))
+	}
+	return buildTaskStatus(t, run), nil
+}
+
+// buildTaskStatus 由任务与最近一次运行组装任务状态
+func buildTaskStatus(t *task.Task, run *task.Run) *TaskStatus {
+	status := &TaskStatus{
+		ID:          t.ID,
+		Type:        t.Type,
+		Status:      t.Status,
+		CreatedBy:   t.CreatedBy,
+		CreatedTime: t.CreatedTime,
+	}
+
+	var snapshot importSnapshot
+	if t.Snapshot != "" && json.Unmarshal([]byte(t.Snapshot), &snapshot) == nil {
+		status.BaseID = snapshot.BaseID
+		status.TableID = snapshot.TableID
+		status.FileName = snapshot.FileName
+		status.Sheet = snapshot.Sheet
+	}
+
+	if run == nil {
+		return status
+	}
+	status.Error = run.ErrorMsg
+	status.Spent = run.Spent
+	status.StartedTime = run.StartedTime
+
+	var report Report
+	if run.Snapshot != "" && json.Unmarshal([]byte(run.Snapshot), &report) == nil {
+		status.Report = &report
+		if report.TotalRows > 0 {
+			status.Progress = float64(report.ProcessedRows) * 100 / float64(report.TotalRows)
+		}
+	}
+	if t.Status == task.StatusCompleted {
+		status.Progress = 100
+	}
+	return status
+}
+
+// cellValue 单元格文本转为交给 typecast 的值（布尔写法直接转换，其余由 typecast 处理）
+func cellValue(field *fieldEntity.Field, raw string) interface{} {
+	if field.Type().String() == fieldValueObject.TypeCheckbox {
+		if b, ok := parseCheckbox(raw); ok {
+			return b
+		}
+	}
+	return raw
+}
+
+// fieldNamesFromHeaders 由列名生成合法且不重复的字段名称：
+// 非法字符替换为下划线，超长截断，仍不合法时使用“列N”
+func fieldNamesFromHeaders(headers []string) []string {
+	names := make([]string, len(headers))
+	seen := make(map[string]bool, len(headers))
+	for i, header := range headers {
+		name := sanitizeFieldName(header)
+		if _, err := fieldValueObject.NewFieldName(name); err != nil {
+			name = fmt.Sprintf("列%d", i+1)
+		}
+		base := name
+		for n := 2; seen[strings.ToLower(name)]; n++ {
+			suffix := fmt.Sprintf("_%d", n)
+			name = truncateBytes(base, fieldValueObject.MaxFieldNameLength-len(suffix)) + suffix
+		}
+		seen[strings.ToLower(name)] = true
+		names[i] = name
+	}
+	return names
+}
+
+func sanitizeFieldName(header string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) || r == '_' || r == ' ' || r == '-' {
+			return r
+		}
+		return '_'
+	}, strings.TrimSpace(header))
+	return strings.TrimSpace(truncateBytes(name, fieldValueObject.MaxFieldNameLength))
+}
+
+// truncateBytes 按字节数截断，不截断多字节字符
+func truncateBytes(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	for limit > 0 && !utf8.RuneStart(s[limit]) {
+		limit--
+	}
+	return s[:limit]
+}
+
+// tableNameFor 新建表格的名称：请求指定的名称，其次为文件名（去掉扩展名）
+func tableNameFor(req ImportRequest) string {
+	candidates := []string{req.TableName, strings.TrimSuffix(req.FileName, filepath.Ext(req.FileName))}
+	for _, name := range candidates {
+		if _, err := tableValueObject.NewTableName(name); err == nil {
+			return strings.TrimSpace(name)
+		}
+	}
+	return defaultTableName
+}
+
+func hasComputedField(fields []*fieldEntity.Field) bool {
+	for _, field := range fields {
+		if field.IsComputed() {
+			return true
+		}
+	}
+	return false
+}
+
+// errorMessage 报告中的错误信息（AppError 带上详情）
+func errorMessage(err error) string {
+	if appErr, ok := pkgerrors.IsAppError(err); ok && appErr.Details != nil {
+		return fmt.Sprintf("%s: %v", appErr.Message, appErr.Details)
+	}
+	return err.Error()
+}
+
+func marshalJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,405 @@
+package dataimport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	tableValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/table/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/task"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+func init() {
+	if logger.Logger == nil {
+		logger.Init(logger.LoggerConfig{
+			Level:      "error",
+			Format:     "console",
+			OutputPath: "stdout",
+		})
+	}
+}
+
+type fakeTableRepo struct {
+	tableRepo.TableRepository
+	tables map[string]*tableEntity.Table
+}
+
+func (r *fakeTableRepo) GetByID(ctx context.Context, id string) (*tableEntity.Table, error) {
+	return r.tables[id], nil
+}
+
+type fakeFieldRepo struct {
+	fieldRepo.FieldRepository
+	fields  []*fieldEntity.Field
+	byTable map[string][]*fieldEntity.Field // 新建表格的字段
+}
+
+func (r *fakeFieldRepo) FindByTableID(ctx context.Context, tableID string) ([]*fieldEntity.Field, error) {
+	if fields, ok := r.byTable[tableID]; ok {
+		return fields, nil
+	}
+	return r.fields, nil
+}
+
+// fakeTableCreator 新建表格并按字段配置生成字段
+type fakeTableCreator struct {
+	t         *testing.T
+	fieldRepo *fakeFieldRepo
+	requests  []dto.CreateTableRequest
+}
+
+func (c *fakeTableCreator) CreateTable(ctx context.Context, req dto.CreateTableRequest, userID string) (*dto.TableResponse, error) {
+	c.requests = append(c.requests, req)
+	tableID := fmt.Sprintf("tbl_new%d", len(c.requests))
+	fields := make([]*fieldEntity.Field, len(req.Fields))
+	for i, config := range req.Fields {
+		fields[i] = newTestField(c.t, fmt.Sprintf("%s_fld%d", tableID, i), config.Name, config.Type)
+	}
+	c.fieldRepo.byTable[tableID] = fields
+	return &dto.TableResponse{ID: tableID, Name: req.Name, BaseID: req.BaseID}, nil
+}
+
+// fakeTypecaster 数字字段的非数字取值在验证时被丢弃，"bad" 整行转换失败
+type fakeTypecaster struct{}
+
+func (fakeTypecaster) TypecastRecordValues(ctx context.Context, tableID string, data map[string]interface{}) (map[string]interface{}, error) {
+	for _, v := range data {
+		if v == "bad" {
+			return nil, errors.New("选项不存在")
+		}
+	}
+	return data, nil
+}
+
+func (fakeTypecaster) ValidateAndTypecastRecord(ctx context.Context, tableID string, data map[string]interface{}, typecast bool) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		if k == "fld_amount" && v == "abc" {
+			continue
+		}
+		result[k] = v
+	}
+	return result, nil
+}
+
+// fakeWriter 包含名称 "重复" 的记录写入失败
+type fakeWriter struct {
+	mu      sync.Mutex
+	written []*recordEntity.Record
+}
+
+func (w *fakeWriter) BatchCreateRecords(ctx context.Context, tableID string, records []*recordEntity.Record) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, record := range records {
+		if record.Data().ToMap()["fld_name"] == "重复" {
+			return errors.New("唯一约束冲突")
+		}
+	}
+	w.written = append(w.written, records...)
+	return nil
+}
+
+// fakeRecordRules 名称为 "黑名单" 的记录不满足规则，只有 usr_admin 可以跳过
+type fakeRecordRules struct{}
+
+func (fakeRecordRules) AuthorizeSkip(ctx context.Context, tableID, userID string) error {
+	if userID != "usr_admin" {
+		return errors.New("只有管理员可以跳过记录校验规则")
+	}
+	return nil
+}
+
+func (fakeRecordRules) RecordCheck(ctx context.Context, tableID string) (func(data map[string]interface{}) error, error) {
+	return func(data map[string]interface{}) error {
+		if data["fld_name"] == "黑名单" {
+			return errors.New("名称不能是黑名单")
+		}
+		return nil
+	}, nil
+}
+
+type fakeTaskRepo struct {
+	mu    sync.Mutex
+	tasks map[string]task.Task
+	runs  map[string]task.Run
+}
+
+func newFakeTaskRepo() *fakeTaskRepo {
+	return &fakeTaskRepo{tasks: map[string]task.Task{}, runs: map[string]task.Run{}}
+}
+
+func (r *fakeTaskRepo) CreateTask(ctx context.Context, t *task.Task) error {
+	return r.UpdateTask(ctx, t)
+}
+
+func (r *fakeTaskRepo) UpdateTask(ctx context.Context, t *task.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tasks[t.ID] = *t
+	return nil
+}
+
+func (r *fakeTaskRepo) FindTaskByID(ctx context.Context, id string) (*task.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if t, ok := r.tasks[id]; ok {
+		return &t, nil
+	}
+	return nil, nil
+}
+
+func (r *fakeTaskRepo) CreateRun(ctx context.Context, run *task.Run) error {
+	return r.UpdateRun(ctx, run)
+}
+
+func (r *fakeTaskRepo) UpdateRun(ctx context.Context, run *task.Run) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs[run.TaskID] = *run
+	return nil
+}
+
+func (r *fakeTaskRepo) FindLatestRun(ctx context.Context, taskID string) (*task.Run, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if run, ok := r.runs[taskID]; ok {
+		return &run, nil
+	}
+	return nil, nil
+}
+
+func newTestField(t *testing.T, id, name, fieldType string) *fieldEntity.Field {
+	ft, err := fieldValueObject.NewFieldType(fieldType)
+	require.NoError(t, err)
+	fieldName, err := fieldValueObject.NewFieldName(name)
+	require.NoError(t, err)
+	dbFieldName, err := fieldValueObject.NewDBFieldNameFromString(id)
+	require.NoError(t, err)
+	return fieldEntity.ReconstructField(
+		fieldValueObject.NewFieldID(id), "tbl_1", fieldName, ft, dbFieldName, "TEXT",
+		fieldValueObject.NewFieldOptions(), 0, 1, "usr_1", time.Now(), time.Now(),
+	)
+}
+
+func newTestService(t *testing.T) (*Service, *fakeWriter, *fakeTaskRepo) {
+	tableName, err := tableValueObject.NewTableName("客户")
+	require.NoError(t, err)
+	table := tableEntity.ReconstructTable(tableValueObject.NewTableID("tbl_1"), "bse_1", tableName,
+		nil, nil, nil, "usr_1", time.Now(), time.Now(), nil, 1)
+
+	writer := &fakeWriter{}
+	tasks := newFakeTaskRepo()
+	fields := &fakeFieldRepo{
+		fields: []*fieldEntity.Field{
+			newTestField(t, "fld_name", "名称", fieldValueObject.TypeSingleLineText),
+			newTestField(t, "fld_amount", "金额", fieldValueObject.TypeNumber),
+			newTestField(t, "fld_vip", "VIP", fieldValueObject.TypeCheckbox),
+		},
+		byTable: map[string][]*fieldEntity.Field{},
+	}
+	config := DefaultConfig()
+	config.ChunkSize = 2
+	service := NewService(config, &fakeTableCreator{t: t, fieldRepo: fields},
+		&fakeTableRepo{tables: map[string]*tableEntity.Table{"tbl_1": table}},
+		fields, fakeTypecaster{}, writer, tasks)
+	return service, writer, tasks
+}
+
+func TestImportCSV_ExistingTableByHeader(t *testing.T) {
+	service, writer, tasks := newTestService(t)
+	data := []byte("名称,金额,vip,未知列\n张三,100,是,x\n重复,1,否,\n,,,\n李四,abc,,\n坏行,bad,,\n")
+
+	status, err := service.ImportCSV(context.Background(), ImportRequest{
+		BaseID:   "bse_1",
+		TableID:  "tbl_1",
+		FileName: "客户.csv",
+	}, data, "usr_1")
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusRunning, status.Status)
+	assert.Equal(t, "tbl_1", status.TableID)
+	service.wg.Wait()
+
+	require.Len(t, writer.written, 2)
+	first := writer.written[0].Data().ToMap()
+	assert.Equal(t, "张三", first["fld_name"])
+	assert.Equal(t, true, first["fld_vip"], "布尔写法在 typecast 前转换")
+	assert.NotContains(t, first, "未知列")
+
+	got, err := service.GetTask(context.Background(), status.ID, "usr_1")
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusCompleted, got.Status)
+	assert.Equal(t, float64(100), got.Progress)
+	require.NotNil(t, got.Report)
+	assert.Equal(t, 5, got.Report.TotalRows)
+	assert.Equal(t, 5, got.Report.ProcessedRows)
+	assert.Equal(t, 2, got.Report.SuccessRows)
+	assert.Equal(t, 2, got.Report.FailedRows)
+	assert.Equal(t, 1, got.Report.SkippedRows)
+
+	rowErrors := map[int]RowError{}
+	for _, rowErr := range got.Report.Errors {
+		rowErrors[rowErr.Row] = rowErr
+	}
+	assert.Contains(t, rowErrors[3].Message, "唯一约束冲突", "整批失败后逐条写入定位到失败的行")
+	assert.Equal(t, "金额", rowErrors[5].Column, "无法转换的单元格被忽略")
+	assert.Equal(t, "abc", rowErrors[5].Value)
+	assert.Contains(t, rowErrors[6].Message, "选项不存在")
+
+	var snapshot importSnapshot
+	require.NoError(t, json.Unmarshal([]byte(tasks.tasks[status.ID].Snapshot), &snapshot))
+	assert.Equal(t, EncodingUTF8, snapshot.Encoding)
+	assert.Equal(t, ",", snapshot.Delimiter)
+
+	_, err = service.GetTask(context.Background(), status.ID, "usr_2")
+	assert.Error(t, err, "只能查看自己发起的任务")
+}
+
+func TestImportCSV_ColumnMapping(t *testing.T) {
+	service, writer, _ := newTestService(t)
+	data := []byte("客户名,数量\n王五,3\n")
+
+	status, err := service.ImportCSV(context.Background(), ImportRequest{
+		BaseID:  "bse_1",
+		TableID: "tbl_1",
+		Columns: []ColumnMapping{{Index: 0, FieldID: "fld_name"}, {Index: 1, FieldID: "fld_amount"}},
+	}, data, "usr_1")
+	require.NoError(t, err)
+	service.wg.Wait()
+
+	require.Len(t, writer.written, 1)
+	assert.Equal(t, map[string]interface{}{"fld_name": "王五", "fld_amount": "3"}, writer.written[0].Data().ToMap())
+	got, err := service.GetTask(context.Background(), status.ID, "usr_1")
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusCompleted, got.Status)
+
+	_, err = service.ImportCSV(context.Background(), ImportRequest{
+		BaseID:  "bse_1",
+		TableID: "tbl_1",
+		Columns: []ColumnMapping{{Index: 0, FieldID: "fld_missing"}},
+	}, data, "usr_1")
+	assert.Error(t, err)
+
+	_, err = service.ImportCSV(context.Background(), ImportRequest{BaseID: "bse_2", TableID: "tbl_1"}, data, "usr_1")
+	assert.Error(t, err, "表格不属于该 Base")
+
+	_, err = service.ImportCSV(context.Background(), ImportRequest{BaseID: "bse_1", TableID: "tbl_1"}, data, "usr_1")
+	assert.Error(t, err, "没有同名字段可导入")
+}
+
+func TestImportCSV_RecordRules(t *testing.T) {
+	service, writer, _ := newTestService(t)
+	service.SetRecordRules(fakeRecordRules{})
+	data := []byte("名称,金额\n张三,1\n黑名单,2\n")
+
+	status, err := service.ImportCSV(context.Background(), ImportRequest{BaseID: "bse_1", TableID: "tbl_1"}, data, "usr_1")
+	require.NoError(t, err)
+	service.wg.Wait()
+
+	require.Len(t, writer.written, 1)
+	got, err := service.GetTask(context.Background(), status.ID, "usr_1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.Report.FailedRows)
+	require.Len(t, got.Report.Errors, 1)
+	assert.Equal(t, 3, got.Report.Errors[0].Row)
+	assert.Contains(t, got.Report.Errors[0].Message, "名称不能是黑名单")
+
+	_, err = service.ImportCSV(context.Background(), ImportRequest{BaseID: "bse_1", TableID: "tbl_1", SkipRecordRules: true}, data, "usr_1")
+	assert.Error(t, err, "非管理员不能跳过规则")
+
+	_, err = service.ImportCSV(context.Background(), ImportRequest{BaseID: "bse_1", TableID: "tbl_1", SkipRecordRules: true}, data, "usr_admin")
+	require.NoError(t, err)
+	service.wg.Wait()
+	assert.Len(t, writer.written, 3, "管理员跳过规则后全部导入")
+}
+
+func TestPreviewCSV(t *testing.T) {
+	service, _, _ := newTestService(t)
+	preview, err := service.PreviewCSV([]byte("名称,金额(元)\n甲,1\n乙,2\n"), ParseOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, preview.TotalRows)
+	assert.Equal(t, [][]string{{"甲", "1"}, {"乙", "2"}}, preview.Rows)
+	require.Len(t, preview.Columns, 2)
+	assert.Equal(t, PreviewColumn{Index: 1, Name: "金额(元)", FieldName: "金额_元_", Type: fieldValueObject.TypeNumber}, preview.Columns[1])
+}
+
+func TestImportXLSX_MultipleSheets(t *testing.T) {
+	service, writer, _ := newTestService(t)
+	data := buildXLSX(t, []testSheet{
+		{name: "客户", rows: `
+<row r="1"><c r="A1" t="inlineStr"><is><t>客户名单</t></is></c></row>
+<row r="2"><c r="A2" t="inlineStr"><is><t>名称</t></is></c><c r="B2" t="inlineStr"><is><t>金额</t></is></c></row>
+<row r="3"><c r="A3" t="inlineStr"><is><t>张三</t></is></c><c r="B3"><v>100</v></c></row>`},
+		{name: "订单", rows: `
+<row r="1"><c r="A1" t="inlineStr"><is><t>下单日期</t></is></c><c r="B1" t="inlineStr"><is><t>折扣</t></is></c></row>
+<row r="2"><c r="A2" s="1"><v>45355</v></c><c r="B2" s="2"><v>0.15</v></c></row>
+<row r="3"><c r="A3" s="1"><v>45356</v></c><c r="B3" s="2"><v>0.2</v></c></row>`},
+		{name: "空"},
+	}, nil)
+
+	preview, err := service.PreviewXLSX(data)
+	require.NoError(t, err)
+	require.Len(t, preview.Sheets, 3)
+	assert.Equal(t, 2, preview.Sheets[0].HeaderRow)
+	assert.Equal(t, fieldValueObject.TypePercent, preview.Sheets[1].Columns[1].Type)
+
+	_, err = service.ImportXLSX(context.Background(), XLSXImportRequest{
+		BaseID: "bse_1",
+		Sheets: []SheetImport{{Name: "客户", TableID: "tbl_1"}, {Name: "不存在"}},
+	}, data, "usr_1")
+	assert.Error(t, err, "工作表不存在时不启动任何导入")
+	assert.Empty(t, writer.written)
+
+	results, err := service.ImportXLSX(context.Background(), XLSXImportRequest{
+		BaseID:   "bse_1",
+		FileName: "业务.xlsx",
+		Sheets:   []SheetImport{{Name: "客户", TableID: "tbl_1"}, {Name: "订单"}, {Name: "空"}},
+	}, data, "usr_1")
+	require.NoError(t, err)
+	service.wg.Wait()
+
+	require.Len(t, results, 3)
+	require.NotNil(t, results[0].Task)
+	assert.Equal(t, "客户", results[0].Task.Sheet)
+	assert.Equal(t, TaskTypeXLSXImport, results[0].Task.Type)
+	require.NotNil(t, results[1].Task)
+	assert.Equal(t, "tbl_new1", results[1].Task.TableID)
+	assert.Nil(t, results[2].Task)
+	assert.NotEmpty(t, results[2].Error)
+
+	creator := service.tables.(*fakeTableCreator)
+	require.Len(t, creator.requests, 1)
+	assert.Equal(t, "订单", creator.requests[0].Name, "新建表格默认取工作表名称")
+	assert.Equal(t, fieldValueObject.TypeDate, creator.requests[0].Fields[0].Type)
+	assert.Equal(t, fieldValueObject.TypePercent, creator.requests[0].Fields[1].Type)
+
+	byTable := map[string][]map[string]interface{}{}
+	for _, record := range writer.written {
+		byTable[record.TableID()] = append(byTable[record.TableID()], record.Data().ToMap())
+	}
+	assert.Equal(t, []map[string]interface{}{{"fld_name": "张三", "fld_amount": "100"}}, byTable["tbl_1"])
+	require.Len(t, byTable["tbl_new1"], 2)
+	assert.Equal(t, map[string]interface{}{"tbl_new1_fld0": "2024-03-04", "tbl_new1_fld1": "15%"}, byTable["tbl_new1"][0],
+		"日期与百分比交给 typecast 转换")
+
+	got, err := service.GetTask(context.Background(), results[0].Task.ID, "usr_1")
+	require.NoError(t, err)
+	assert.Equal(t, task.StatusCompleted, got.Status)
+	assert.Equal(t, 1, got.Report.SuccessRows)
+}
@@ -0,0 +1,289 @@
+package dataimport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/unicode"
+
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// 文件编码
+const (
+	EncodingUTF8    = "utf-8"
+	EncodingGBK     = "gbk"
+	EncodingUTF16LE = "utf-16le"
+	EncodingUTF16BE = "utf-16be"
+)
+
+// sniffLines 嗅探分隔符时采样的行数
+const sniffLines = 20
+
+// candidateDelimiters 可识别的分隔符（同分时靠前者优先）
+var candidateDelimiters = []rune{',', '\t', ';', '|'}
+
+// ParseOptions 文件解析选项（为空时自动识别）
+type ParseOptions struct {
+	Encoding  string `json:"encoding,omitempty"`  // utf-8、gbk（含 gb2312/gb18030）、utf-16le、utf-16be
+	Delimiter string `json:"delimiter,omitempty"` // 单个字符，"\t" 表示制表符
+	HasHeader *bool  `json:"hasHeader,omitempty"` // 第一行是否为表头，默认 true
+}
+
+func (o ParseOptions) hasHeader() bool {
+	return o.HasHeader == nil || *o.HasHeader
+}
+
+// Sheet 解析后的表格数据
+type Sheet struct {
+	Name        string
+	Headers     []string   // 列名（已去重，空表头以“列N”补齐）
+	Rows        [][]string // 数据行，列数与 Headers 一致
+	FirstRow    int        // 第一行数据在文件中的行号（从 1 开始），用于错误报告
+	HeaderRow   int        // 表头在文件中的行号，0 表示无表头
+	ColumnTypes []string   // 可选：按单元格格式得到的列类型提示（XLSX 日期、百分比），为空时按数据推断
+}
+
+// RowNumber 第 i 行数据在文件中的行号
+func (s *Sheet) RowNumber(i int) int {
+	return s.FirstRow + i
+}
+
+// CSVFile 解析后的 CSV 文件
+type CSVFile struct {
+	Sheet     *Sheet
+	Encoding  string
+	Delimiter string
+}
+
+// ParseCSV 解析 CSV 文件：识别编码（BOM、UTF-8，否则按 GBK）与分隔符（逗号、制表符、分号、竖线）
+func ParseCSV(data []byte, opts ParseOptions) (*CSVFile, error) {
+	text, enc, err := decodeText(data, opts.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	delimiter, err := resolveDelimiter(text, opts.Delimiter)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var records [][]string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("CSV 解析失败: %v", err))
+		}
+		records = append(records, record)
+	}
+
+	sheet := NewSheet("", records, opts.hasHeader())
+	if len(sheet.Headers) == 0 {
+		return nil, pkgerrors.ErrBadRequest.WithDetails("文件内容为空")
+	}
+
+	return &CSVFile{
+		Sheet:     sheet,
+		Encoding:  enc,
+		Delimiter: string(delimiter),
+	}, nil
+}
+
+// NewSheet 由原始行构建表格数据：补齐列数、生成列名并去掉末尾的空行
+func NewSheet(name string, records [][]string, hasHeader bool) *Sheet {
+	for len(records) > 0 && isBlankRow(records[len(records)-1]) {
+		records = records[:len(records)-1]
+	}
+
+	sheet := &Sheet{Name: name, FirstRow: 1}
+	if len(records) == 0 {
+		return sheet
+	}
+
+	width := 0
+	for _, record := range records {
+		width = max(width, len(record))
+	}
+
+	var header []string
+	if hasHeader {
+		header = records[0]
+		records = records[1:]
+		sheet.FirstRow = 2
+		sheet.HeaderRow = 1
+	}
+	sheet.Headers = buildHeaders(header, width)
+
+	sheet.Rows = make([][]string, len(records))
+	for i, record := range records {
+		row := make([]string, width)
+		copy(row, record)
+		sheet.Rows[i] = row
+	}
+	return sheet
+}
+
+// buildHeaders 生成列名：去除首尾空白，空列名以“列N”代替，重名时追加序号
+func buildHeaders(header []string, width int) []string {
+	headers := make([]string, width)
+	seen := make(map[string]int, width)
+	for i := range headers {
+		name := ""
+		if i < len(header) {
+			name = strings.TrimSpace(header[i])
+		}
+		if name == "" {
+			name = fmt.Sprintf("列%d", i+1)
+		}
+		if n := seen[name]; n > 0 {
+			seen[name] = n + 1
+			name = fmt.Sprintf("%s_%d", name, n+1)
+		}
+		seen[name]++
+		headers[i] = name
+	}
+	return headers
+}
+
+func isBlankRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// decodeText 按指定编码（为空时自动识别）将文件内容解码为 UTF-8 文本，返回识别出的编码
+func decodeText(data []byte, enc string) (string, string, error) {
+	if enc == "" {
+		enc = detectEncoding(data)
+	}
+
+	var decoder encoding.Encoding
+	switch normalizeEncoding(enc) {
+	case "utf8":
+		data = bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+		if !utf8.Valid(data) {
+			return "", "", pkgerrors.ErrBadRequest.WithDetails("文件不是有效的 UTF-8 编码")
+		}
+		return string(data), EncodingUTF8, nil
+	case "gbk", "gb2312", "gb18030", "cp936":
+		// GB18030 兼容 GBK 与 GB2312
+		decoder, enc = simplifiedchinese.GB18030, EncodingGBK
+	case "utf16le":
+		decoder, enc = unicode.UTF16(unicode.LittleEndian, unicode.UseBOM), EncodingUTF16LE
+	case "utf16be":
+		decoder, enc = unicode.UTF16(unicode.BigEndian, unicode.UseBOM), EncodingUTF16BE
+	default:
+		return "", "", pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("不支持的文件编码: %s", enc))
+	}
+
+	decoded, err := decoder.NewDecoder().Bytes(data)
+	if err != nil {
+		return "", "", pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("文件解码失败（%s）: %v", enc, err))
+	}
+	return string(bytes.TrimPrefix(decoded, []byte("\uFEFF"))), enc, nil
+}
+
+// detectEncoding 识别文件编码：BOM 优先，其次合法 UTF-8，否则视为 GBK
+func detectEncoding(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		return EncodingUTF8
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		return EncodingUTF16LE
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		return EncodingUTF16BE
+	case utf8.Valid(data):
+		return EncodingUTF8
+	default:
+		return EncodingGBK
+	}
+}
+
+func normalizeEncoding(enc string) string {
+	return strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(enc)))
+}
+
+// resolveDelimiter 解析指定的分隔符，为空时从文本嗅探
+func resolveDelimiter(text, delimiter string) (rune, error) {
+	if delimiter == "" {
+		return sniffDelimiter(text), nil
+	}
+	if delimiter == `\t` {
+		return '\t', nil
+	}
+	r, size := utf8.DecodeRuneInString(delimiter)
+	if size != len(delimiter) || r == '"' || r == '\r' || r == '\n' || r == utf8.RuneError {
+		return 0, pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("无效的分隔符: %q", delimiter))
+	}
+	return r, nil
+}
+
+// sniffDelimiter 嗅探分隔符：统计采样行中每个候选字符在引号外出现的次数，
+// 各行次数一致的行数最多者胜出，其次比较首行出现次数；都不出现时使用逗号
+func sniffDelimiter(text string) rune {
+	best, bestScore := ',', 0
+	for _, candidate := range candidateDelimiters {
+		counts := countPerLine(text, candidate, sniffLines)
+		if len(counts) == 0 || counts[0] == 0 {
+			continue
+		}
+		consistent := 0
+		for _, n := range counts {
+			if n == counts[0] {
+				consistent++
+			}
+		}
+		if score := consistent*1000 + counts[0]; score > bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+	return best
+}
+
+// countPerLine 统计前 limit 行（引号内的换行不计为新行）中 delimiter 在引号外出现的次数，跳过空行
+func countPerLine(text string, delimiter rune, limit int) []int {
+	counts := make([]int, 0, limit)
+	count, inQuotes, blank := 0, false, true
+	for _, r := range text {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			blank = false
+		case r == '\n' && !inQuotes:
+			if !blank {
+				counts = append(counts, count)
+				if len(counts) >= limit {
+					return counts
+				}
+			}
+			count, blank = 0, true
+		case r == delimiter && !inQuotes:
+			count++
+			blank = false
+		case r != '\r' && r != ' ':
+			blank = false
+		}
+	}
+	if !blank {
+		counts = append(counts, count)
+	}
+	return counts
+}
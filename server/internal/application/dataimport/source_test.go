@@ -0,0 +1,68 @@
+package dataimport
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+func TestParseCSV_DetectsGBKAndSemicolon(t *testing.T) {
+	text := "姓名;城市;备注\n张三;北京;\"含;分号\"\n李四;上海;\n"
+	data, err := simplifiedchinese.GBK.NewEncoder().Bytes([]byte(text))
+	require.NoError(t, err)
+
+	file, err := ParseCSV(data, ParseOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, EncodingGBK, file.Encoding)
+	assert.Equal(t, ";", file.Delimiter)
+	assert.Equal(t, []string{"姓名", "城市", "备注"}, file.Sheet.Headers)
+	assert.Equal(t, [][]string{{"张三", "北京", "含;分号"}, {"李四", "上海", ""}}, file.Sheet.Rows)
+	assert.Equal(t, 2, file.Sheet.RowNumber(0))
+}
+
+func TestParseCSV_UTF8BOMAndTab(t *testing.T) {
+	data := []byte("\xEF\xBB\xBFa\tb\n1\t\"多\n行\"\n")
+
+	file, err := ParseCSV(data, ParseOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, EncodingUTF8, file.Encoding)
+	assert.Equal(t, "\t", file.Delimiter)
+	assert.Equal(t, []string{"a", "b"}, file.Sheet.Headers)
+	assert.Equal(t, [][]string{{"1", "多\n行"}}, file.Sheet.Rows)
+}
+
+func TestParseCSV_Options(t *testing.T) {
+	noHeader := false
+	file, err := ParseCSV([]byte("1|2|3\n4|5\n"), ParseOptions{Delimiter: "|", HasHeader: &noHeader})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"列1", "列2", "列3"}, file.Sheet.Headers)
+	assert.Equal(t, [][]string{{"1", "2", "3"}, {"4", "5", ""}}, file.Sheet.Rows)
+	assert.Equal(t, 1, file.Sheet.RowNumber(0))
+
+	_, err = ParseCSV([]byte("a,b\n"), ParseOptions{Encoding: "latin1"})
+	assert.Error(t, err)
+	_, err = ParseCSV([]byte("\n\n"), ParseOptions{})
+	assert.Error(t, err, "空文件")
+}
+
+func TestNewSheet_Headers(t *testing.T) {
+	sheet := NewSheet("Sheet1", [][]string{
+		{" 名称 ", "", "名称"},
+		{"a", "b", "c", "d"},
+		{"", " ", ""},
+	}, true)
+
+	assert.Equal(t, []string{"名称", "列2", "名称_2", "列4"}, sheet.Headers)
+	assert.Equal(t, [][]string{{"a", "b", "c", "d"}}, sheet.Rows, "末尾空行被去掉")
+}
+
+func TestSniffDelimiter(t *testing.T) {
+	assert.Equal(t, ',', sniffDelimiter("a,b;c\n1,2;3\n"))
+	assert.Equal(t, ';', sniffDelimiter("a;b;c\n\"1,5\";2;3\n4;5;6\n"))
+	assert.Equal(t, ',', sniffDelimiter("单列\n值\n"))
+}
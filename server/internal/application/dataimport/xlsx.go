@@ -0,0 +1,680 @@
+package dataimport
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+const (
+	// xlsxRowBytes 按导入行数上限估算 XML 部件大小上限时每行允许的字节数
+	xlsxRowBytes = 2 << 10
+	// minXLSXPartSize XML 部件解压后大小上限的下限（行数上限很小时仍可读取样式、共享字符串等部件）
+	minXLSXPartSize = 8 << 20
+	// headerScanRows 识别表头时检查的非空行数
+	headerScanRows = 10
+	// headerWidthRows 估算表格宽度时检查的非空行数
+	headerWidthRows = 20
+)
+
+// xlsxCellKind 单元格的值类型（数字按单元格格式区分日期与百分比）
+type xlsxCellKind int
+
+const (
+	xlsxText xlsxCellKind = iota
+	xlsxNumber
+	xlsxBool
+	xlsxDate
+	xlsxDateTime
+	xlsxPercent
+)
+
+type xlsxCell struct {
+	value string
+	kind  xlsxCellKind
+}
+
+// xlsxSheet 工作表的原始单元格（行号从 1 开始，rows[i] 为第 i+1 行）
+type xlsxSheet struct {
+	name string
+	rows [][]xlsxCell
+}
+
+// Workbook 解析后的 XLSX 工作簿
+//
+// 单元格统一转为文本：日期格式的数字转为 "2006-01-02" 或 "2006-01-02 15:04:05"，
+// 百分比格式的数字转为 "12.5%"，交给 typecast 按字段类型转换
+type Workbook struct {
+	sheets []*xlsxSheet
+}
+
+// SheetNames 工作表名称（按工作簿中的顺序）
+func (w *Workbook) SheetNames() []string {
+	names := make([]string, len(w.sheets))
+	for i, sheet := range w.sheets {
+		names[i] = sheet.name
+	}
+	return names
+}
+
+// nonEmptySheetNames 含有数据的工作表名称
+func (w *Workbook) nonEmptySheetNames() []string {
+	var names []string
+	for _, sheet := range w.sheets {
+		for _, row := range sheet.rows {
+			if len(row) > 0 {
+				names = append(names, sheet.name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// Sheet 按名称取工作表数据。headerRow 为表头所在行号（从 1 开始，0 表示无表头），为 nil 时自动识别
+func (w *Workbook) Sheet(name string, headerRow *int) (*Sheet, error) {
+	for _, sheet := range w.sheets {
+		if sheet.name == name {
+			return sheet.toSheet(headerRow)
+		}
+	}
+	return nil, pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("工作表不存在: %s", name))
+}
+
+// toSheet 去掉表头之前的行（标题、说明等），生成表格数据与列类型提示
+func (s *xlsxSheet) toSheet(headerRow *int) (*Sheet, error) {
+	header := -1
+	start := firstNonBlankRow(s.rows)
+	if headerRow == nil {
+		header = detectHeaderRow(s.rows)
+	} else if *headerRow > 0 {
+		if *headerRow > len(s.rows) {
+			return nil, pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("工作表 %s 的表头行 %d 超出范围（共 %d 行）", s.name, *headerRow, len(s.rows)))
+		}
+		header = *headerRow - 1
+	} else if *headerRow < 0 {
+		return nil, pkgerrors.ErrBadRequest.WithDetails("表头行号不能为负数")
+	}
+	if header >= 0 {
+		start = header
+	}
+
+	rows := s.rows[start:]
+	records := make([][]string, len(rows))
+	for i, row := range rows {
+		records[i] = make([]string, len(row))
+		for j, cell := range row {
+			records[i][j] = cell.value
+		}
+	}
+
+	sheet := NewSheet(s.name, records, header >= 0)
+	sheet.FirstRow += start
+	if sheet.HeaderRow > 0 {
+		sheet.HeaderRow += start
+	}
+
+	dataRows := rows
+	if header >= 0 && len(dataRows) > 0 {
+		dataRows = dataRows[1:]
+	}
+	sheet.ColumnTypes = make([]string, len(sheet.Headers))
+	for i := range sheet.ColumnTypes {
+		sheet.ColumnTypes[i] = columnTypeHint(dataRows, i)
+	}
+	return sheet, nil
+}
+
+// firstNonBlankRow 第一个非空行的下标（全部为空时返回 0）
+func firstNonBlankRow(rows [][]xlsxCell) int {
+	for i, row := range rows {
+		if len(row) > 0 {
+			return i
+		}
+	}
+	return 0
+}
+
+// detectHeaderRow 识别表头：在前 headerScanRows 个非空行中，取第一个全部为文本、
+// 且非空单元格数不少于表格宽度一半（多列时至少 2 个）的行，以跳过标题与说明行。
+// 找不到时返回 -1（无表头）
+func detectHeaderRow(rows [][]xlsxCell) int {
+	width, scanned := 0, 0
+	for _, row := range rows {
+		if n := nonEmptyCells(row); n > 0 {
+			width = max(width, n)
+			if scanned++; scanned >= headerWidthRows {
+				break
+			}
+		}
+	}
+	if width == 0 {
+		return -1
+	}
+	minCells := max((width+1)/2, min(width, 2))
+
+	scanned = 0
+	for i, row := range rows {
+		n := nonEmptyCells(row)
+		if n == 0 {
+			continue
+		}
+		if n >= minCells && allText(row) {
+			return i
+		}
+		if scanned++; scanned >= headerScanRows {
+			break
+		}
+	}
+	return -1
+}
+
+func nonEmptyCells(row []xlsxCell) int {
+	n := 0
+	for _, cell := range row {
+		if strings.TrimSpace(cell.value) != "" {
+			n++
+		}
+	}
+	return n
+}
+
+func allText(row []xlsxCell) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell.value) != "" && cell.kind != xlsxText {
+			return false
+		}
+	}
+	return true
+}
+
+// columnTypeHint 列中所有非空单元格都是日期或百分比格式时返回对应的字段类型，否则返回空（按文本推断）
+func columnTypeHint(rows [][]xlsxCell, index int) string {
+	dates, dateTimes, percents, total := 0, 0, 0, 0
+	for _, row := range rows {
+		if index >= len(row) || strings.TrimSpace(row[index].value) == "" {
+			continue
+		}
+		total++
+		switch row[index].kind {
+		case xlsxDate:
+			dates++
+		case xlsxDateTime:
+			dateTimes++
+		case xlsxPercent:
+			percents++
+		}
+	}
+	switch {
+	case total == 0:
+		return ""
+	case percents == total:
+		return valueobject.TypePercent
+	case dates == total:
+		return valueobject.TypeDate
+	case dates+dateTimes == total:
+		return valueobject.TypeDateTime
+	}
+	return ""
+}
+
+// ParseXLSX 解析 XLSX 文件的全部工作表（图表页除外）
+//
+// maxRows 为单次导入的最大数据行数：单个 XML 部件解压后的大小按它估算上限以防止压缩炸弹，
+// 工作表边读边检查行号，超出上限时立即报错，不把整个工作表读入内存
+func ParseXLSX(data []byte, maxRows int) (*Workbook, error) {
+	if !bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return nil, pkgerrors.ErrBadRequest.WithDetails("文件不是有效的 XLSX 格式（不支持旧版 .xls，请另存为 .xlsx）")
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("XLSX 解析失败: %v", err))
+	}
+
+	parser := &xlsxParser{
+		files:       make(map[string]*zip.File, len(archive.File)),
+		maxRows:     maxRows + headerScanRows,
+		maxPartSize: max(uint64(maxRows+headerScanRows)*xlsxRowBytes, minXLSXPartSize),
+	}
+	for _, file := range archive.File {
+		parser.files[strings.TrimPrefix(file.Name, "/")] = file
+	}
+
+	workbook, err := parser.parse()
+	if err != nil {
+		if _, ok := pkgerrors.IsAppError(err); ok {
+			return nil, err
+		}
+		return nil, pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("XLSX 解析失败: %v", err))
+	}
+	if len(workbook.sheets) == 0 {
+		return nil, pkgerrors.ErrBadRequest.WithDetails("文件中没有工作表")
+	}
+	return workbook, nil
+}
+
+// xlsxParser 读取工作簿各部件
+type xlsxParser struct {
+	files         map[string]*zip.File
+	maxRows       int    // 工作表的最大行号（数据行上限加上表头前的标题行）
+	maxPartSize   uint64 // 单个部件解压后的大小上限
+	sharedStrings []string
+	styles        []xlsxCellKind // 按 cellXfs 下标：数字单元格的值类型
+	date1904      bool
+}
+
+type xlsxWorkbookXML struct {
+	Properties struct {
+		Date1904 string `xml:"date1904,attr"`
+	} `xml:"workbookPr"`
+	Sheets []struct {
+		Name  string     `xml:"name,attr"`
+		Attrs []xml.Attr `xml:",any,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationshipsXML struct {
+	Items []struct {
+		ID     string `xml:"Id,attr"`
+		Type   string `xml:"Type,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxStylesXML struct {
+	NumFmts []struct {
+		ID   int    `xml:"numFmtId,attr"`
+		Code string `xml:"formatCode,attr"`
+	} `xml:"numFmts>numFmt"`
+	CellXfs []struct {
+		NumFmtID int `xml:"numFmtId,attr"`
+	} `xml:"cellXfs>xf"`
+}
+
+// xlsxRichText 共享字符串或内联字符串（富文本按片段拼接，忽略注音）
+type xlsxRichText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxRichText) text() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	b.WriteString(t.T)
+	for _, run := range t.Runs {
+		b.WriteString(run.T)
+	}
+	return b.String()
+}
+
+type xlsxRowXML struct {
+	Number int `xml:"r,attr"`
+	Cells  []struct {
+		Ref    string       `xml:"r,attr"`
+		Type   string       `xml:"t,attr"`
+		Style  int          `xml:"s,attr"`
+		Value  string       `xml:"v"`
+		Inline xlsxRichText `xml:"is"`
+	} `xml:"c"`
+}
+
+func (p *xlsxParser) parse() (*Workbook, error) {
+	var workbook xlsxWorkbookXML
+	if err := p.decode("xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+	p.date1904 = workbook.Properties.Date1904 == "1" || workbook.Properties.Date1904 == "true"
+
+	var rels xlsxRelationshipsXML
+	if err := p.decode("xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	targets := make(map[string]string, len(rels.Items))
+	sharedStringsPath, stylesPath := "xl/sharedStrings.xml", "xl/styles.xml"
+	for _, rel := range rels.Items {
+		target := relTarget(rel.Target)
+		switch {
+		case strings.HasSuffix(rel.Type, "/worksheet"):
+			targets[rel.ID] = target
+		case strings.HasSuffix(rel.Type, "/sharedStrings"):
+			sharedStringsPath = target
+		case strings.HasSuffix(rel.Type, "/styles"):
+			stylesPath = target
+		}
+	}
+
+	if err := p.loadSharedStrings(sharedStringsPath); err != nil {
+		return nil, err
+	}
+	if err := p.loadStyles(stylesPath); err != nil {
+		return nil, err
+	}
+
+	result := &Workbook{}
+	for _, entry := range workbook.Sheets {
+		var target string
+		for _, attr := range entry.Attrs {
+			if attr.Name.Local == "id" {
+				target = targets[attr.Value]
+			}
+		}
+		if target == "" {
+			continue // 图表页等非工作表
+		}
+		rows, err := p.readSheet(target)
+		if err != nil {
+			return nil, fmt.Errorf("工作表 %s: %w", entry.Name, err)
+		}
+		result.sheets = append(result.sheets, &xlsxSheet{name: entry.Name, rows: rows})
+	}
+	return result, nil
+}
+
+// relTarget 关系目标转为包内路径（相对路径相对于 xl/）
+func relTarget(target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join("xl", target)
+}
+
+// open 打开包内部件，解压后的内容超过 maxPartSize 时报错
+func (p *xlsxParser) open(name string) (io.ReadCloser, error) {
+	file, ok := p.files[name]
+	if !ok {
+		return nil, fmt.Errorf("缺少 %s", name)
+	}
+	if file.UncompressedSize64 > p.maxPartSize {
+		return nil, pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("XLSX 内容过大: %s", name))
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(rc, int64(p.maxPartSize)), rc}, nil
+}
+
+func (p *xlsxParser) decode(name string, v interface{}) error {
+	rc, err := p.open(name)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return xml.NewDecoder(rc).Decode(v)
+}
+
+// loadSharedStrings 逐条读取共享字符串表（不存在时为空）
+func (p *xlsxParser) loadSharedStrings(name string) error {
+	if _, ok := p.files[name]; !ok {
+		return nil
+	}
+	rc, err := p.open(name)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	decoder := xml.NewDecoder(rc)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "si" {
+			var item xlsxRichText
+			if err := decoder.DecodeElement(&item, &start); err != nil {
+				return err
+			}
+			p.sharedStrings = append(p.sharedStrings, item.text())
+		}
+	}
+}
+
+// loadStyles 读取单元格样式对应的数字格式（不存在时所有数字为普通数字）
+func (p *xlsxParser) loadStyles(name string) error {
+	if _, ok := p.files[name]; !ok {
+		return nil
+	}
+	var styles xlsxStylesXML
+	if err := p.decode(name, &styles); err != nil {
+		return err
+	}
+
+	custom := make(map[int]string, len(styles.NumFmts))
+	for _, numFmt := range styles.NumFmts {
+		custom[numFmt.ID] = numFmt.Code
+	}
+	p.styles = make([]xlsxCellKind, len(styles.CellXfs))
+	for i, xf := range styles.CellXfs {
+		if code, ok := custom[xf.NumFmtID]; ok {
+			p.styles[i] = classifyNumFmt(code)
+		} else {
+			p.styles[i] = builtinNumFmtKind(xf.NumFmtID)
+		}
+	}
+	return nil
+}
+
+// readSheet 流式读取工作表的行（缺失的行以空行补齐），行号超过 maxRows 时报错
+func (p *xlsxParser) readSheet(name string) ([][]xlsxCell, error) {
+	rc, err := p.open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var rows [][]xlsxCell
+	decoder := xml.NewDecoder(rc)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		var row xlsxRowXML
+		if err := decoder.DecodeElement(&row, &start); err != nil {
+			return nil, err
+		}
+		number := row.Number
+		if number <= 0 {
+			number = len(rows) + 1
+		}
+		if number > p.maxRows {
+			return nil, pkgerrors.ErrBadRequest.WithDetails(
+				fmt.Sprintf("工作表行数超过单次导入上限 %d", p.maxRows-headerScanRows))
+		}
+		for len(rows) < number {
+			rows = append(rows, nil)
+		}
+
+		var cells []xlsxCell
+		for i, c := range row.Cells {
+			col := i
+			if c.Ref != "" {
+				if col = columnIndex(c.Ref); col < 0 {
+					return nil, fmt.Errorf("无效的单元格引用 %s", c.Ref)
+				}
+			}
+			cell := p.cellValue(c.Type, c.Style, c.Value, c.Inline)
+			if cell.value == "" {
+				continue
+			}
+			for len(cells) <= col {
+				cells = append(cells, xlsxCell{})
+			}
+			cells[col] = cell
+		}
+		rows[number-1] = cells
+	}
+	return rows, nil
+}
+
+// cellValue 按单元格类型与样式转为文本
+func (p *xlsxParser) cellValue(cellType string, style int, raw string, inline xlsxRichText) xlsxCell {
+	switch cellType {
+	case "s":
+		index, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil || index < 0 || index >= len(p.sharedStrings) {
+			return xlsxCell{}
+		}
+		return xlsxCell{value: p.sharedStrings[index]}
+	case "inlineStr":
+		return xlsxCell{value: inline.text()}
+	case "str":
+		return xlsxCell{value: raw}
+	case "b":
+		return xlsxCell{value: strconv.FormatBool(strings.TrimSpace(raw) == "1"), kind: xlsxBool}
+	case "e":
+		return xlsxCell{} // #N/A 等错误值视为空
+	}
+
+	raw = strings.TrimSpace(raw)
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return xlsxCell{value: raw}
+	}
+	kind := xlsxNumber
+	if style >= 0 && style < len(p.styles) {
+		kind = p.styles[style]
+	}
+	switch kind {
+	case xlsxDate, xlsxDateTime:
+		if f < 0 {
+			return xlsxCell{value: formatNumber(f), kind: xlsxNumber}
+		}
+		t := excelTime(f, p.date1904)
+		if kind == xlsxDate {
+			return xlsxCell{value: t.Format("2006-01-02"), kind: kind}
+		}
+		return xlsxCell{value: t.Format("2006-01-02 15:04:05"), kind: kind}
+	case xlsxPercent:
+		return xlsxCell{value: formatNumber(f*100) + "%", kind: kind}
+	}
+	return xlsxCell{value: formatNumber(f), kind: xlsxNumber}
+}
+
+// columnIndex 单元格引用（如 "AB12"）的列下标（从 0 开始）
+func columnIndex(ref string) int {
+	col, letters := 0, 0
+	for _, r := range ref {
+		if r >= 'a' && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+		letters++
+	}
+	if letters == 0 || letters > 3 {
+		return -1
+	}
+	return col - 1
+}
+
+// excelTime Excel 日期序列号转为时间（1900 日期系统保留 1900-02-29 的历史错误）
+func excelTime(serial float64, date1904 bool) time.Time {
+	base := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	if date1904 {
+		base = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	} else if serial < 61 {
+		base = time.Date(1899, 12, 31, 0, 0, 0, 0, time.UTC)
+	}
+	days := math.Floor(serial)
+	seconds := math.Round((serial - days) * 86400)
+	return base.AddDate(0, 0, int(days)).Add(time.Duration(seconds) * time.Second)
+}
+
+// formatNumber 按 Excel 的 15 位有效数字格式化，避免浮点误差（如 0.30000000000000004）
+func formatNumber(f float64) string {
+	rounded, err := strconv.ParseFloat(strconv.FormatFloat(f, 'g', 15, 64), 64)
+	if err != nil {
+		rounded = f
+	}
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
+
+// builtinNumFmtKind 内置数字格式的值类型
+func builtinNumFmtKind(id int) xlsxCellKind {
+	switch {
+	case id == 9 || id == 10:
+		return xlsxPercent
+	case id >= 14 && id <= 17, id >= 27 && id <= 31, id >= 34 && id <= 36, id >= 50 && id <= 58:
+		return xlsxDate
+	case id >= 18 && id <= 22, id == 32 || id == 33, id == 45 || id == 47:
+		return xlsxDateTime
+	}
+	return xlsxNumber
+}
+
+// classifyNumFmt 自定义数字格式的值类型：只看正数段，忽略引号内文本、转义字符与方括号
+// （颜色、区域）；含 % 为百分比，含时分秒为日期时间，含年月日为日期。
+// [h]、[mm] 等累计时长格式视为普通数字
+func classifyNumFmt(code string) xlsxCellKind {
+	var b strings.Builder
+	inQuotes := false
+	runes := []rune(code)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case inQuotes:
+			inQuotes = r != '"'
+		case r == '"':
+			inQuotes = true
+		case r == '\\' || r == '_' || r == '*':
+			i++
+		case r == '[':
+			end := i + 1
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if content := strings.ToLower(string(runes[i+1 : end])); content != "" && strings.Trim(content, "hms") == "" {
+				return xlsxNumber
+			}
+			i = end
+		case r == ';':
+			i = len(runes)
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	format := strings.ToLower(b.String())
+	switch {
+	case strings.Contains(format, "%"):
+		return xlsxPercent
+	case strings.ContainsAny(format, "hs"):
+		return xlsxDateTime
+	case strings.ContainsAny(format, "ymd"):
+		return xlsxDate
+	}
+	return xlsxNumber
+}
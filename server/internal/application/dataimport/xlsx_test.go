@@ -0,0 +1,169 @@
+package dataimport
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// testStyles 样式下标：1 日期（内置 14）、2 百分比（内置 10）、3 日期时间、4 带单位的数字
+const testStyles = `<?xml version="1.0" encoding="UTF-8"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts count="2"><numFmt numFmtId="164" formatCode="yyyy/m/d h:mm"/><numFmt numFmtId="165" formatCode="#,##0.00&quot;元&quot;"/></numFmts>
+<cellXfs count="5"><xf numFmtId="0"/><xf numFmtId="14"/><xf numFmtId="10"/><xf numFmtId="164"/><xf numFmtId="165"/></cellXfs>
+</styleSheet>`
+
+type testSheet struct {
+	name string
+	rows string // <row> 元素
+}
+
+// buildXLSX 生成最小的 XLSX 文件
+func buildXLSX(t *testing.T, sheets []testSheet, sharedStrings []string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	write := func(name, content string) {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	var entries, rels strings.Builder
+	for i, sheet := range sheets {
+		fmt.Fprintf(&entries, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, html.EscapeString(sheet.name), i+1, i+1)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+		write(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1),
+			`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`+sheet.rows+`</sheetData></worksheet>`)
+	}
+	fmt.Fprintf(&rels, `<Relationship Id="rIdS" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/sharedStrings" Target="sharedStrings.xml"/>`)
+	fmt.Fprintf(&rels, `<Relationship Id="rIdT" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="/xl/styles.xml"/>`)
+
+	write("xl/workbook.xml", `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" `+
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`+entries.String()+`</sheets></workbook>`)
+	write("xl/_rels/workbook.xml.rels", `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+rels.String()+`</Relationships>`)
+	write("xl/styles.xml", testStyles)
+
+	var sst strings.Builder
+	for _, s := range sharedStrings {
+		fmt.Fprintf(&sst, "<si><t>%s</t></si>", html.EscapeString(s))
+	}
+	write("xl/sharedStrings.xml", `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`+sst.String()+`</sst>`)
+
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestParseXLSX_HeaderDetectionAndFormats(t *testing.T) {
+	data := buildXLSX(t, []testSheet{
+		{name: "销售", rows: `
+<row r="1"><c r="A1" t="inlineStr"><is><r><t>2024 </t></r><r><t>销售报表</t></r></is></c></row>
+<row r="3"><c r="A3" t="s"><v>0</v></c><c r="B3" t="s"><v>1</v></c><c r="C3" t="s"><v>2</v></c><c r="D3" t="s"><v>3</v></c></row>
+<row r="4"><c r="A4" s="1"><v>45355</v></c><c r="B4" s="2"><v>0.125</v></c><c r="C4" s="3"><v>45355.5</v></c><c r="D4" s="4"><v>1234.5</v></c></row>
+<row r="5"><c r="A5" s="1"><v>45356</v></c><c r="B5" s="2"><v>1</v></c><c r="C5" s="3"/><c r="D5" t="str"><v>N/A</v></c></row>`},
+		{name: "Data", rows: `
+<row r="1"><c r="A1"><v>1</v></c><c r="B1" t="b"><v>1</v></c></row>
+<row r="2"><c r="A2"><v>0.30000000000000004</v></c><c r="B2" t="b"><v>0</v></c><c r="C2" t="e"><v>#N/A</v></c></row>`},
+	}, []string{"日期", "完成率", "时间", "金额"})
+
+	workbook, err := ParseXLSX(data, DefaultConfig().MaxRows)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"销售", "Data"}, workbook.SheetNames())
+
+	sheet, err := workbook.Sheet("销售", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, sheet.HeaderRow, "跳过标题行")
+	assert.Equal(t, 4, sheet.RowNumber(0))
+	assert.Equal(t, []string{"日期", "完成率", "时间", "金额"}, sheet.Headers)
+	assert.Equal(t, [][]string{
+		{"2024-03-04", "12.5%", "2024-03-04 12:00:00", "1234.5"},
+		{"2024-03-05", "100%", "", "N/A"},
+	}, sheet.Rows)
+	assert.Equal(t, []string{fieldValueObject.TypeDate, fieldValueObject.TypePercent, fieldValueObject.TypeDateTime, ""}, sheet.ColumnTypes)
+
+	sheet, err = workbook.Sheet("Data", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sheet.HeaderRow, "数字行不是表头")
+	assert.Equal(t, []string{"列1", "列2"}, sheet.Headers)
+	assert.Equal(t, [][]string{{"1", "true"}, {"0.3", "false"}}, sheet.Rows)
+
+	headerRow := 1
+	sheet, err = workbook.Sheet("Data", &headerRow)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "true"}, sheet.Headers, "指定表头行")
+	assert.Equal(t, 2, sheet.FirstRow)
+
+	_, err = workbook.Sheet("不存在", nil)
+	assert.Error(t, err)
+}
+
+func TestParseXLSX_Invalid(t *testing.T) {
+	_, err := ParseXLSX([]byte("a,b\n1,2\n"), DefaultConfig().MaxRows)
+	assert.Error(t, err)
+
+	_, err = ParseXLSX(buildXLSX(t, nil, nil), DefaultConfig().MaxRows)
+	assert.Error(t, err, "没有工作表")
+}
+
+func TestParseXLSX_RowLimit(t *testing.T) {
+	var rows strings.Builder
+	for i := 1; i <= 3+headerScanRows; i++ {
+		fmt.Fprintf(&rows, `<row r="%d"><c r="A%d"><v>%d</v></c></row>`, i, i, i)
+	}
+	data := buildXLSX(t, []testSheet{{name: "Data", rows: rows.String()}}, nil)
+
+	workbook, err := ParseXLSX(data, 3)
+	require.NoError(t, err, "标题行不计入上限")
+	assert.Equal(t, []string{"Data"}, workbook.SheetNames())
+
+	_, err = ParseXLSX(data, 2)
+	appErr, ok := pkgerrors.IsAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, "工作表行数超过单次导入上限 2", appErr.Details)
+
+	// 行号跳到很远处时不补齐空行，直接报错
+	data = buildXLSX(t, []testSheet{{name: "Data", rows: `<row r="1048576"><c r="A1048576"><v>1</v></c></row>`}}, nil)
+	_, err = ParseXLSX(data, 100)
+	assert.Error(t, err)
+}
+
+func TestClassifyNumFmt(t *testing.T) {
+	cases := map[string]xlsxCellKind{
+		"0.0%":                       xlsxPercent,
+		"yyyy\"年\"m\"月\"d\"日\"":      xlsxDate,
+		"[$-804]yyyy/mm/dd":          xlsxDate,
+		"hh:mm:ss":                   xlsxDateTime,
+		"[h]:mm:ss":                  xlsxNumber,
+		"#,##0.00_);[Red](#,##0.00)": xlsxNumber,
+		"0.00\"天\"":                  xlsxNumber,
+		"\"ymd\"0":                   xlsxNumber,
+		"[Red]0.00":                  xlsxNumber,
+	}
+	for code, want := range cases {
+		assert.Equal(t, want, classifyNumFmt(code), code)
+	}
+}
+
+func TestExcelTime(t *testing.T) {
+	assert.Equal(t, time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC), excelTime(1, false))
+	assert.Equal(t, time.Date(1900, 2, 28, 0, 0, 0, 0, time.UTC), excelTime(59, false))
+	assert.Equal(t, time.Date(1900, 3, 1, 0, 0, 0, 0, time.UTC), excelTime(61, false))
+	assert.Equal(t, time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC), excelTime(45292.25, false))
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), excelTime(43830, true))
+}
+
+func TestColumnIndex(t *testing.T) {
+	assert.Equal(t, 0, columnIndex("A1"))
+	assert.Equal(t, 27, columnIndex("AB12"))
+	assert.Equal(t, -1, columnIndex("12"))
+}
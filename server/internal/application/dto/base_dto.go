@@ -19,6 +19,8 @@ type UpdateBaseRequest struct {
 type DuplicateBaseRequest struct {
 	Name        string `json:"name" binding:"required,max=100"`
 	Description string `json:"description,omitempty"`
+	WithRecords bool   `json:"withRecords,omitempty"` // 同时复制记录
+	Async       bool   `json:"async,omitempty"`       // 以后台任务复制（记录多的Base自动转为后台任务）
 }
 
 // BaseResponse Base响应（对齐原版）
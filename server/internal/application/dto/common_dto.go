@@ -12,12 +12,13 @@ type PaginationRequest struct {
 
 // PaginationResponse 分页响应
 type PaginationResponse struct {
-	Total       int64 `json:"total"`
-	Page        int   `json:"page"`
-	PageSize    int   `json:"pageSize"`
-	TotalPages  int   `json:"totalPages"`
-	HasNext     bool  `json:"hasNext"`
-	HasPrevious bool  `json:"hasPrevious"`
+	Total       int64  `json:"total"`
+	Page        int    `json:"page"`
+	PageSize    int    `json:"pageSize"`
+	TotalPages  int    `json:"totalPages"`
+	HasNext     bool   `json:"hasNext"`
+	HasPrevious bool   `json:"hasPrevious"`
+	NextCursor  string `json:"nextCursor,omitempty"` // 键集分页的下一页游标
 }
 
 // TimeRange 时间范围
@@ -15,6 +15,7 @@ type CreateFieldRequest struct {
 	Options  map[string]interface{} `json:"options"`
 	Required bool                   `json:"required"`
 	Unique   bool                   `json:"unique"`
+	NotNull  bool                   `json:"notNull"` // 非空约束，所有写入路径都会校验
     // 顶层默认值，兼容 SDK 传参
     DefaultValue interface{}        `json:"defaultValue"`
 }
@@ -22,10 +23,12 @@ type CreateFieldRequest struct {
 // UpdateFieldRequest 更新字段请求
 type UpdateFieldRequest struct {
 	Name        *string                `json:"name"`
+	Type        *string                `json:"type"` // 变更字段类型，已有数据按目标类型转换
 	Description *string                `json:"description"`
 	Options     map[string]interface{} `json:"options"`
 	Required    *bool                  `json:"required"`
-	Unique      *bool                  `json:"unique"`
+	Unique      *bool                  `json:"unique"`  // 开启时校验已有数据，存在重复值则返回冲突的记录ID
+	NotNull     *bool                  `json:"notNull"` // 开启时校验已有数据，存在空值则返回冲突的记录ID
 	// 顶层默认值，兼容 SDK 传参
 	DefaultValue interface{} `json:"defaultValue"`
 }
@@ -39,6 +42,7 @@ type FieldResponse struct {
 	Options     map[string]interface{} `json:"options"`
 	Required    bool                   `json:"required"`
 	Unique      bool                   `json:"unique"`
+	NotNull     bool                   `json:"notNull"`
 	IsPrimary   bool                   `json:"isPrimary"`
 	Description string                 `json:"description"`
 	CreatedAt   time.Time              `json:"createdAt"`
@@ -69,6 +73,7 @@ func FromFieldEntity(field *fieldEntity.Field) *FieldResponse {
 		Options:     fieldOptionsToMap(field.Options()),
 		Required:    field.IsRequired(),
 		Unique:      field.IsUnique(),
+		NotNull:     field.NotNull(),
 		IsPrimary:   field.IsPrimary(),
 		Description: desc,
 		CreatedAt:   field.CreatedAt(),
@@ -181,6 +186,9 @@ func fieldOptionsToMap(options *fieldVO.FieldOptions) map[string]interface{} {
 		if options.Link.ForeignKeyName != "" {
 			linkMap["foreign_key_name"] = options.Link.ForeignKeyName
 		}
+		if options.Link.LookupFieldID != "" {
+			linkMap["lookupFieldId"] = options.Link.LookupFieldID
+		}
 		result["link"] = linkMap
 		
 		if options.Link.BaseID != "" {
@@ -294,6 +302,19 @@ func fieldOptionsToMap(options *fieldVO.FieldOptions) map[string]interface{} {
 	// User 选项
 	if options.User != nil {
 		result["isMultiple"] = options.User.IsMultiple
+		if options.User.DefaultValue != nil {
+			result["defaultValue"] = options.User.DefaultValue
+		}
+	}
+
+	// Text 选项
+	if options.Text != nil && options.Text.DefaultValue != nil {
+		result["defaultValue"] = *options.Text.DefaultValue
+	}
+
+	// 校验规则（表单视图按同一份规则做前端校验）
+	if !options.Validation.IsEmpty() {
+		result["validation"] = options.Validation
 	}
 
 	// Rating 选项
@@ -325,3 +346,30 @@ func FromFieldEntities(fields []*fieldEntity.Field) []*FieldResponse {
 	}
 	return result
 }
+
+// FieldConversionPreviewRequest 字段类型转换预览请求
+type FieldConversionPreviewRequest struct {
+	Type    string                 `json:"type" binding:"required"`
+	Options map[string]interface{} `json:"options"`
+}
+
+// FieldConversionSample 类型转换的示例单元格
+type FieldConversionSample struct {
+	RecordID string      `json:"recordId"`
+	Before   interface{} `json:"before"`
+	After    interface{} `json:"after"`
+	Lost     bool        `json:"lost"` // 非空值转换后为空或丢失部分内容
+}
+
+// FieldConversionPreview 字段类型转换预览（不修改任何数据）
+type FieldConversionPreview struct {
+	FieldID    string                  `json:"fieldId"`
+	FromType   string                  `json:"fromType"`
+	ToType     string                  `json:"toType"`
+	Total      int                     `json:"total"`     // 非空单元格数
+	Converted  int                     `json:"converted"` // 完整转换的单元格数
+	Lost       int                     `json:"lost"`      // 转换后为空或丢失部分内容的单元格数
+	Samples    []FieldConversionSample `json:"samples"`
+	NewChoices []fieldVO.SelectChoice  `json:"newChoices,omitempty"` // 转换为选择字段时自动新增的选项
+	Warnings   []string                `json:"warnings,omitempty"`
+}
@@ -4,12 +4,18 @@ import (
 	"time"
 
 	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
 )
 
 // CreateRecordRequest 创建记录请求
 type CreateRecordRequest struct {
-	TableID string                 `json:"tableId" binding:"required"` // ✅ 统一使用 camelCase
-	Data    map[string]interface{} `json:"data" binding:"required"`
+	TableID  string                 `json:"tableId" binding:"required"` // ✅ 统一使用 camelCase
+	Data     map[string]interface{} `json:"data" binding:"required"`
+	Typecast bool                   `json:"typecast,omitempty"` // 宽松类型转换：字符串转数字/日期，名称转选项/用户/关联记录
+	RecordID string                 `json:"-"`                  // 指定记录ID（ShareDB 创建操作由客户端生成），HTTP 接口不开放
+
+	SkipRecordRules bool `json:"skipRecordRules,omitempty"` // 跳过表级记录校验规则（仅管理员，用于批量迁移）
 }
 
 // UpdateRecordRequest 更新记录请求
@@ -19,8 +25,11 @@ type UpdateRecordRequest struct {
 	Record       *UpdateRecordData `json:"record,omitempty"`       // 嵌套的 record 对象
 
 	// 兼容格式
-	Data    map[string]interface{} `json:"data,omitempty"`    // 直接的数据字段
-	Version *int                   `json:"version,omitempty"` // 可选的版本号，用于乐观锁
+	Data     map[string]interface{} `json:"data,omitempty"`     // 直接的数据字段
+	Version  *int                   `json:"version,omitempty"`  // 可选的版本号，用于乐观锁
+	Typecast bool                   `json:"typecast,omitempty"` // 宽松类型转换
+
+	SkipRecordRules bool `json:"skipRecordRules,omitempty"` // 跳过表级记录校验规则（仅管理员）
 }
 
 // UpdateRecordData 更新记录数据（Teable 格式）
@@ -30,7 +39,11 @@ type UpdateRecordData struct {
 
 // BatchCreateRecordRequest 批量创建记录请求（对齐原版）
 type BatchCreateRecordRequest struct {
-	Records []RecordCreateItem `json:"records" binding:"required,max=1000"` // ✅ 移除 min=1，允许空数组
+	Records   []RecordCreateItem `json:"records" binding:"required,max=1000"` // ✅ 移除 min=1，允许空数组
+	KeyFields []string           `json:"keyFields,omitempty"`                 // 设置时为 upsert 模式：按这些字段（ID或名称）匹配已有记录，匹配则更新，否则创建
+	Typecast  bool               `json:"typecast,omitempty"`                  // 宽松类型转换
+
+	SkipRecordRules bool `json:"skipRecordRules,omitempty"` // 跳过表级记录校验规则（仅管理员，用于批量迁移）
 }
 
 // RecordCreateItem 单条记录创建项
@@ -40,15 +53,29 @@ type RecordCreateItem struct {
 
 // BatchCreateRecordResponse 批量创建记录响应
 type BatchCreateRecordResponse struct {
-	Records      []*RecordResponse `json:"records"`
-	SuccessCount int               `json:"successCount"`
-	FailedCount  int               `json:"failedCount"`
-	Errors       []string          `json:"errors,omitempty"`
+	Records      []*RecordResponse   `json:"records"`
+	SuccessCount int                 `json:"successCount"`
+	FailedCount  int                 `json:"failedCount"`
+	Errors       []string            `json:"errors,omitempty"`
+	CreatedIDs   []string            `json:"createdIds,omitempty"`  // upsert 模式：新建的记录ID
+	UpdatedIDs   []string            `json:"updatedIds,omitempty"`  // upsert 模式：更新的记录ID
+	FieldErrors  []RecordFieldErrors `json:"fieldErrors,omitempty"` // 违反字段校验规则的记录及逐字段详情
+	RuleErrors   []RecordRuleErrors  `json:"ruleErrors,omitempty"`  // 不满足表级校验规则的记录及规则详情
+}
+
+// RecordFieldErrors 批量写入中一条记录违反字段校验规则的详情
+type RecordFieldErrors struct {
+	Index    int                      `json:"index"`              // 记录在请求中的序号（从1开始）
+	RecordID string                   `json:"recordId,omitempty"` // 批量更新时的记录ID
+	Errors   []map[string]interface{} `json:"errors"`             // 每项包含 field_id、field_name、rule、message、value
 }
 
 // BatchUpdateRecordRequest 批量更新记录请求
 type BatchUpdateRecordRequest struct {
-	Records []RecordUpdateItem `json:"records" binding:"required,min=1,max=1000"`
+	Records  []RecordUpdateItem `json:"records" binding:"required,min=1,max=1000"`
+	Typecast bool               `json:"typecast,omitempty"` // 宽松类型转换
+
+	SkipRecordRules bool `json:"skipRecordRules,omitempty"` // 跳过表级记录校验规则（仅管理员，用于批量迁移）
 }
 
 // RecordUpdateItem 单条记录更新项
@@ -59,10 +86,12 @@ type RecordUpdateItem struct {
 
 // BatchUpdateRecordResponse 批量更新记录响应
 type BatchUpdateRecordResponse struct {
-	Records      []*RecordResponse `json:"records"`
-	SuccessCount int               `json:"successCount"`
-	FailedCount  int               `json:"failedCount"`
-	Errors       []string          `json:"errors,omitempty"`
+	Records      []*RecordResponse   `json:"records"`
+	SuccessCount int                 `json:"successCount"`
+	FailedCount  int                 `json:"failedCount"`
+	Errors       []string            `json:"errors,omitempty"`
+	FieldErrors  []RecordFieldErrors `json:"fieldErrors,omitempty"` // 违反字段校验规则的记录及逐字段详情
+	RuleErrors   []RecordRuleErrors  `json:"ruleErrors,omitempty"`  // 不满足表级校验规则的记录及规则详情
 }
 
 // BatchDeleteRecordRequest 批量删除记录请求
@@ -77,6 +106,35 @@ type BatchDeleteRecordResponse struct {
 	Errors       []string `json:"errors,omitempty"`
 }
 
+// UpdateRecordsByFilterRequest 按过滤条件批量更新记录请求
+//
+// 过滤结构与视图过滤一致；指定 viewId 时与视图过滤按 AND 组合
+type UpdateRecordsByFilterRequest struct {
+	Filter      map[string]interface{} `json:"filter,omitempty"`
+	ViewID      string                 `json:"viewId,omitempty"`
+	Fields      map[string]interface{} `json:"fields" binding:"required"` // 字段ID或字段名 -> 新值
+	Typecast    bool                   `json:"typecast,omitempty"`
+	DryRun      bool                   `json:"dryRun,omitempty"`      // 只返回匹配数量，不修改数据
+	MaxAffected int                    `json:"maxAffected,omitempty"` // 安全上限，匹配数超过时拒绝执行（默认10000）
+
+	SkipRecordRules bool `json:"skipRecordRules,omitempty"` // 跳过表级记录校验规则（仅管理员，用于批量迁移）
+}
+
+// DeleteRecordsByFilterRequest 按过滤条件批量删除记录请求
+type DeleteRecordsByFilterRequest struct {
+	Filter      map[string]interface{} `json:"filter,omitempty"`
+	ViewID      string                 `json:"viewId,omitempty"`
+	DryRun      bool                   `json:"dryRun,omitempty"`
+	MaxAffected int                    `json:"maxAffected,omitempty"`
+}
+
+// RecordsByFilterResponse 按过滤条件批量操作响应
+type RecordsByFilterResponse struct {
+	Matched  int64 `json:"matched"`  // 匹配的记录数
+	Affected int   `json:"affected"` // 实际更新/删除的记录数（dryRun 时为0）
+	DryRun   bool  `json:"dryRun"`
+}
+
 // ListRecordFilter 记录列表过滤器
 type ListRecordFilter struct {
 	TableID   *string                `json:"tableId"`
@@ -87,6 +145,20 @@ type ListRecordFilter struct {
 	CreatedAt *time.Time             `json:"createdAt"`
 }
 
+// ListRecordsRequest 记录列表查询请求
+type ListRecordsRequest struct {
+	Limit  int                      `json:"limit"`
+	Offset int                      `json:"offset"`
+	Filter map[string]interface{}   `json:"filter,omitempty"` // 过滤树：{operator, filters, groups}，结构与视图过滤一致
+	Sort   []map[string]interface{} `json:"sort,omitempty"`   // 多键排序：[{fieldId, order}]，结构与视图排序一致
+	ViewID string                   `json:"viewId,omitempty"` // 按视图查询：应用视图的过滤、排序并隐藏视图中隐藏的列
+	Cursor string                   `json:"cursor,omitempty"` // 键集分页游标（上一页返回的 nextCursor），设置时忽略 Offset
+
+	Projection []string `json:"projection,omitempty"` // 字段投影：只返回指定字段ID的列，为空时返回全部字段
+	CellFormat string   `json:"cellFormat,omitempty"` // 单元格输出格式：json（默认，原始值）或 text（显示字符串）
+	Search     string   `json:"search,omitempty"`     // 全文搜索关键词：匹配文本、选项名称与关联标题，支持拼音匹配中文
+}
+
 // RecordResponse 记录响应
 type RecordResponse struct {
 	ID        string                 `json:"id"`
@@ -97,6 +169,8 @@ type RecordResponse struct {
 	CreatedAt time.Time              `json:"createdAt"`
 	UpdatedAt time.Time              `json:"updatedAt"`
 	Version   int                    `json:"version"`
+
+	SearchHits []string `json:"searchHits,omitempty"` // 全文搜索时匹配关键词的字段ID，用于客户端高亮
 }
 
 // RecordListResponse 记录列表响应
@@ -105,6 +179,102 @@ type RecordListResponse struct {
 	Pagination *PaginationResponse `json:"pagination"`
 }
 
+// GroupRecordsRequest 记录分组统计请求
+type GroupRecordsRequest struct {
+	Filter       map[string]interface{}   `json:"filter,omitempty"`       // 过滤树，结构与记录列表一致
+	ViewID       string                   `json:"viewId,omitempty"`       // 按视图统计：合并视图过滤，未指定 groupBy 时使用视图分组
+	GroupBy      []map[string]interface{} `json:"groupBy,omitempty"`      // 分组：[{fieldId, order}]，最多3层，结构与视图分组一致
+	Aggregations []AggregationRequest     `json:"aggregations,omitempty"` // 每个分组计算的聚合
+}
+
+// AggregationRequest 聚合项
+type AggregationRequest struct {
+	FieldID string `json:"fieldId"`
+	Func    string `json:"func"` // 可用函数取决于字段类别，见 RecordStatisticsRequest
+}
+
+// RecordStatisticsRequest 列统计请求
+//
+// Statistics 为字段ID -> 统计函数：
+//   - 所有字段：emptyCount、filledCount、uniqueCount、percentEmpty、percentFilled、percentUnique
+//   - 数字字段：sum、avg、min、max、median
+//   - 日期字段：earliestDate、latestDate
+//   - 复选框：checkedCount、uncheckedCount、percentChecked、percentUnchecked（不支持通用统计）
+type RecordStatisticsRequest struct {
+	Filter     map[string]interface{} `json:"filter,omitempty"` // 过滤树，结构与记录列表一致
+	ViewID     string                 `json:"viewId,omitempty"` // 按视图统计：合并视图过滤
+	Statistics map[string]string      `json:"statistics"`
+}
+
+// FieldStatistic 单列统计结果
+type FieldStatistic struct {
+	Func  string      `json:"func"`
+	Value interface{} `json:"value"` // 占比为 0-100 的百分数；没有记录时部分统计为 null
+}
+
+// RecordStatisticsResponse 列统计响应
+type RecordStatisticsResponse struct {
+	Total      int64                     `json:"total"`      // 过滤后的记录数
+	Statistics map[string]FieldStatistic `json:"statistics"` // 字段ID -> 统计结果
+}
+
+// RecordGroupResponse 分组结果
+type RecordGroupResponse struct {
+	FieldID    string                            `json:"fieldId"`
+	Value      interface{}                       `json:"value"`
+	Count      int64                             `json:"count"`
+	Aggregates map[string]map[string]interface{} `json:"aggregates,omitempty"` // 字段ID -> 聚合函数 -> 结果
+	Children   []*RecordGroupResponse            `json:"children,omitempty"`
+}
+
+// RecordGroupsResponse 分组统计响应
+type RecordGroupsResponse struct {
+	Total      int64                             `json:"total"`
+	Aggregates map[string]map[string]interface{} `json:"aggregates,omitempty"` // 全部过滤结果的聚合
+	Groups     []*RecordGroupResponse            `json:"groups"`
+}
+
+// FromRecordGroupResult 从分组统计结果转换为DTO
+func FromRecordGroupResult(result *recordRepo.RecordGroupResult) *RecordGroupsResponse {
+	return &RecordGroupsResponse{
+		Total:      result.Total,
+		Aggregates: fromGroupAggregates(result.Aggregates),
+		Groups:     fromRecordGroups(result.Groups),
+	}
+}
+
+func fromRecordGroups(groups []*recordRepo.RecordGroup) []*RecordGroupResponse {
+	responses := make([]*RecordGroupResponse, 0, len(groups))
+	for _, group := range groups {
+		resp := &RecordGroupResponse{
+			FieldID:    group.FieldID,
+			Value:      group.Value,
+			Count:      group.Count,
+			Aggregates: fromGroupAggregates(group.Aggregates),
+		}
+		if len(group.Children) > 0 {
+			resp.Children = fromRecordGroups(group.Children)
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func fromGroupAggregates(aggregates map[string]map[recordValueObject.AggregateFunc]interface{}) map[string]map[string]interface{} {
+	if len(aggregates) == 0 {
+		return nil
+	}
+
+	result := make(map[string]map[string]interface{}, len(aggregates))
+	for fieldID, values := range aggregates {
+		result[fieldID] = make(map[string]interface{}, len(values))
+		for fn, value := range values {
+			result[fieldID][string(fn)] = value
+		}
+	}
+	return result
+}
+
 // FromRecordEntity 从Domain实体转换为DTO
 func FromRecordEntity(record *recordEntity.Record) *RecordResponse {
 	if record == nil {
@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/recordrule"
+)
+
+// CreateRecordRuleRequest 创建记录校验规则请求
+type CreateRecordRuleRequest struct {
+	Name       string `json:"name" binding:"required"`
+	Expression string `json:"expression" binding:"required"` // 公式表达式，如 {End} >= {Start}
+	Message    string `json:"message,omitempty"`             // 不通过时的提示，默认使用规则名称
+	Enabled    *bool  `json:"enabled,omitempty"`             // 默认启用
+}
+
+// UpdateRecordRuleRequest 更新记录校验规则请求（只更新传入的字段）
+type UpdateRecordRuleRequest struct {
+	Name       *string `json:"name,omitempty"`
+	Expression *string `json:"expression,omitempty"`
+	Message    *string `json:"message,omitempty"`
+	Enabled    *bool   `json:"enabled,omitempty"`
+}
+
+// RecordRuleResponse 记录校验规则响应
+type RecordRuleResponse struct {
+	ID         string    `json:"id"`
+	TableID    string    `json:"tableId"`
+	Name       string    `json:"name"`
+	Expression string    `json:"expression"`
+	Message    string    `json:"message,omitempty"`
+	Enabled    bool      `json:"enabled"`
+	CreatedBy  string    `json:"createdBy"`
+	UpdatedBy  string    `json:"updatedBy,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// RecordRuleErrors 批量写入中一条记录不满足表级校验规则的详情
+type RecordRuleErrors struct {
+	Index    int                      `json:"index"`              // 记录在请求中的序号（从1开始）
+	RecordID string                   `json:"recordId,omitempty"` // 批量更新时的记录ID
+	Errors   []map[string]interface{} `json:"errors"`             // 每项包含 rule_id、rule_name、message
+}
+
+// FromRecordRule 转换为记录校验规则响应
+func FromRecordRule(rule *recordrule.Rule) *RecordRuleResponse {
+	return &RecordRuleResponse{
+		ID:         rule.ID,
+		TableID:    rule.TableID,
+		Name:       rule.Name,
+		Expression: rule.Expression,
+		Message:    rule.Message,
+		Enabled:    rule.Enabled,
+		CreatedBy:  rule.CreatedBy,
+		UpdatedBy:  rule.LastModifiedBy,
+		CreatedAt:  rule.CreatedTime,
+		UpdatedAt:  rule.LastModifiedTime,
+	}
+}
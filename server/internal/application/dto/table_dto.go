@@ -52,6 +52,30 @@ type DuplicateTableRequest struct {
 	WithData   bool   `json:"withData"`   // 是否复制数据
 	WithViews  bool   `json:"withViews"`  // 是否复制视图
 	WithFields bool   `json:"withFields"` // 是否复制字段配置
+	LinkMode   string `json:"linkMode"`   // 指向其他表格的关联：keep（默认，保留并在对方表格新建对称字段）或 drop（不复制）
+}
+
+// MoveTableRequest 移动表格请求
+type MoveTableRequest struct {
+	BaseID   string `json:"baseId" binding:"required"` // 目标 Base，须与原 Base 在同一空间
+	LinkMode string `json:"linkMode"`                  // 与其他表格的关联：convert（默认，转换为单行文本并保留显示值）或 drop（删除）
+}
+
+// MoveTableFieldChange 移动表格时被转换或删除的字段
+type MoveTableFieldChange struct {
+	TableID    string `json:"tableId"`
+	FieldID    string `json:"fieldId"`
+	Name       string `json:"name"`
+	NewFieldID string `json:"newFieldId,omitempty"` // 转换后的单行文本字段
+}
+
+// MoveTableResponse 移动表格响应
+type MoveTableResponse struct {
+	Table           *TableResponse         `json:"table"`
+	FromBaseID      string                 `json:"fromBaseId"`
+	ConvertedFields []MoveTableFieldChange `json:"convertedFields,omitempty"`
+	RemovedFields   []MoveTableFieldChange `json:"removedFields,omitempty"`
+	Warnings        []string               `json:"warnings,omitempty"` // 引用了被删除字段的公式等需要手动处理的问题
 }
 
 // TableUsageResponse 表用量响应
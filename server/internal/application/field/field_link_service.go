@@ -275,12 +275,24 @@ func (s *FieldLinkService) CreateSymmetricField(
 	}
 	symmetricField.SetOrder(maxOrder + 1)
 
-	// 7. 保存对称字段
+	// 7. 创建对称字段的物理列（Link 字段存储为 JSONB）
+	columnDef := database.ColumnDefinition{
+		Name: symmetricField.DBFieldName().String(),
+		Type: "JSONB",
+	}
+	if err := s.dbProvider.AddColumn(ctx, foreignTable.BaseID(), foreignTableID, columnDef); err != nil {
+		return nil, fmt.Errorf("创建对称字段物理列失败: %w", err)
+	}
+
+	// 8. 保存对称字段（失败时删除物理列）
 	if err := s.fieldRepo.Save(ctx, symmetricField); err != nil {
+		if dropErr := s.dbProvider.DropColumn(ctx, foreignTable.BaseID(), foreignTableID, columnDef.Name); dropErr != nil {
+			logger.Error("回滚删除对称字段物理列失败", logger.ErrorField(dropErr))
+		}
 		return nil, fmt.Errorf("保存对称字段失败: %w", err)
 	}
 
-	// 8. 更新主字段的 SymmetricFieldID
+	// 9. 更新主字段的 SymmetricFieldID
 	mainFieldOptions := mainField.Options()
 	if mainFieldOptions == nil {
 		mainFieldOptions = valueobject.NewFieldOptions()
@@ -291,7 +303,7 @@ func (s *FieldLinkService) CreateSymmetricField(
 	mainFieldOptions.Link.SymmetricFieldID = symmetricField.ID().String()
 	mainField.UpdateOptions(mainFieldOptions)
 
-	// 9. 保存主字段（更新 SymmetricFieldID）
+	// 10. 保存主字段（更新 SymmetricFieldID）
 	if err := s.fieldRepo.Save(ctx, mainField); err != nil {
 		logger.Warn("更新主字段的 SymmetricFieldID 失败",
 			logger.String("main_field_id", mainField.ID().String()),
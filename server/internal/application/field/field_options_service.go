@@ -105,6 +105,40 @@ func (s *FieldOptionsService) ExtractLookupOptionsFromOptions(options map[string
 	return linkFieldID, lookupFieldID
 }
 
+// ExtractLinkOptionsFromOptions 提取Link选项
+// 关联表ID支持 foreignTableId 与 linkedTableId 两种写法，未指定关联表时返回 nil
+func (s *FieldOptionsService) ExtractLinkOptionsFromOptions(options map[string]interface{}) *valueobject.LinkOptions {
+	if options == nil {
+		return nil
+	}
+
+	link := &valueobject.LinkOptions{}
+	if foreignTableID, ok := options["foreignTableId"].(string); ok {
+		link.LinkedTableID = foreignTableID
+	}
+	if linkedTableID, ok := options["linkedTableId"].(string); ok && link.LinkedTableID == "" {
+		link.LinkedTableID = linkedTableID
+	}
+	if link.LinkedTableID == "" {
+		return nil
+	}
+
+	if relationship, ok := options["relationship"].(string); ok {
+		link.Relationship = relationship
+	}
+	if lookupFieldID, ok := options["lookupFieldId"].(string); ok {
+		link.LookupFieldID = lookupFieldID
+	}
+	if isSymmetric, ok := options["isSymmetric"].(bool); ok {
+		link.IsSymmetric = isSymmetric
+	}
+	if allowMultiple, ok := options["allowMultiple"].(bool); ok {
+		link.AllowMultiple = allowMultiple
+	}
+
+	return link
+}
+
 // ApplyCommonFieldOptions 应用通用选项
 // 注意：这是一个简化的实现，完整的实现需要根据字段类型设置不同的选项
 func (s *FieldOptionsService) ApplyCommonFieldOptions(field interface {
@@ -148,28 +182,52 @@ func (s *FieldOptionsService) ApplyCommonFieldOptions(field interface {
 	}
 
 	// 根据字段类型应用特定配置（defaultValue等）
+	// defaultValue 显式传 null 时清除默认值
+	rawDefault, hasDefault := options["defaultValue"]
+	clearDefault := hasDefault && rawDefault == nil
 	fieldType := field.Type().String()
 	switch fieldType {
-	case "number":
+	case "number", "percent", "currency", "rating":
 		if currentOptions.Number == nil {
 			currentOptions.Number = &valueobject.NumberOptions{}
 		}
-		if defaultValue, ok := options["defaultValue"].(float64); ok {
+		if defaultValue, ok := rawDefault.(float64); ok {
 			currentOptions.Number.DefaultValue = &defaultValue
+		} else if clearDefault {
+			currentOptions.Number.DefaultValue = nil
 		}
 	case "singleSelect", "multipleSelect":
 		if currentOptions.Select == nil {
 			currentOptions.Select = &valueobject.SelectOptions{}
 		}
-		if defaultValue, ok := options["defaultValue"]; ok {
-			currentOptions.Select.DefaultValue = defaultValue
+		if hasDefault {
+			currentOptions.Select.DefaultValue = rawDefault
 		}
 	case "date", "datetime":
 		if currentOptions.Date == nil {
 			currentOptions.Date = &valueobject.DateOptions{}
 		}
-		if defaultValue, ok := options["defaultValue"].(string); ok {
+		if defaultValue, ok := rawDefault.(string); ok {
 			currentOptions.Date.DefaultValue = &defaultValue
+		} else if clearDefault {
+			currentOptions.Date.DefaultValue = nil
+		}
+	case "text", "singleLineText", "longText", "email", "url", "phone":
+		if defaultValue, ok := rawDefault.(string); ok {
+			if currentOptions.Text == nil {
+				currentOptions.Text = &valueobject.TextOptions{}
+			}
+			currentOptions.Text.DefaultValue = &defaultValue
+		} else if clearDefault && currentOptions.Text != nil {
+			currentOptions.Text.DefaultValue = nil
+		}
+	case "user":
+		// 用户ID、"me"（当前用户），多用户字段可为数组
+		if hasDefault {
+			if currentOptions.User == nil {
+				currentOptions.User = &valueobject.UserOptions{}
+			}
+			currentOptions.User.DefaultValue = rawDefault
 		}
 	}
 
@@ -236,6 +236,31 @@ func TestFieldOptionsService_ExtractLookupOptionsFromOptions(t *testing.T) {
 	}
 }
 
+func TestFieldOptionsService_ExtractLinkOptionsFromOptions(t *testing.T) {
+	service := NewFieldOptionsService()
+
+	link := service.ExtractLinkOptionsFromOptions(map[string]interface{}{
+		"foreignTableId": "tbl_foreign",
+		"relationship":   "manyOne",
+		"lookupFieldId":  "fld_title",
+		"isSymmetric":    true,
+	})
+	if assert.NotNil(t, link) {
+		assert.Equal(t, "tbl_foreign", link.LinkedTableID)
+		assert.Equal(t, "manyOne", link.Relationship)
+		assert.Equal(t, "fld_title", link.LookupFieldID)
+		assert.True(t, link.IsSymmetric)
+	}
+
+	link = service.ExtractLinkOptionsFromOptions(map[string]interface{}{"linkedTableId": "tbl_other"})
+	if assert.NotNil(t, link) {
+		assert.Equal(t, "tbl_other", link.LinkedTableID)
+	}
+
+	assert.Nil(t, service.ExtractLinkOptionsFromOptions(map[string]interface{}{"relationship": "manyMany"}))
+	assert.Nil(t, service.ExtractLinkOptionsFromOptions(nil))
+}
+
 // fieldOptionsWrapper 包装器，用于适配FieldOptionsService的接口
 type fieldOptionsWrapper struct {
 	field *entity.Field
@@ -349,6 +374,30 @@ func TestFieldOptionsService_ApplyCommonFieldOptions(t *testing.T) {
 				assert.Equal(t, "2024-01-01", *opts.Date.DefaultValue)
 			},
 		},
+		{
+			name: "文本字段应用defaultValue",
+			options: map[string]interface{}{
+				"defaultValue": "待处理",
+			},
+			verify: func(t *testing.T, f *entity.Field) {
+				opts := f.Options()
+				assert.NotNil(t, opts)
+				assert.NotNil(t, opts.Text)
+				assert.Equal(t, "待处理", *opts.Text.DefaultValue)
+			},
+		},
+		{
+			name: "user类型应用defaultValue",
+			options: map[string]interface{}{
+				"defaultValue": "me",
+			},
+			verify: func(t *testing.T, f *entity.Field) {
+				opts := f.Options()
+				assert.NotNil(t, opts)
+				assert.NotNil(t, opts.User)
+				assert.Equal(t, "me", opts.User.DefaultValue)
+			},
+		},
 		{
 			name: "formatting配置包含precision",
 			options: map[string]interface{}{
@@ -393,6 +442,8 @@ func TestFieldOptionsService_ApplyCommonFieldOptions(t *testing.T) {
 				fieldType, _ = valueobject.NewFieldType("singleSelect")
 			} else if strings.Contains(tt.name, "date") {
 				fieldType, _ = valueobject.NewFieldType("date")
+			} else if strings.Contains(tt.name, "user") {
+				fieldType, _ = valueobject.NewFieldType("user")
 			} else {
 				fieldType, _ = valueobject.NewFieldType("singleLineText")
 			}
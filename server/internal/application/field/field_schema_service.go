@@ -2,7 +2,9 @@ package field
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
 	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
@@ -10,8 +12,10 @@ import (
 	tableValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/table/valueobject"
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/schema"
+	pkgDatabase "github.com/easyspace-ai/luckdb/server/pkg/database"
 	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
 	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -184,6 +188,243 @@ func (s *FieldSchemaService) EnsureOrderColumn(ctx context.Context, tableID stri
 	return nil
 }
 
+// 类型转换时临时列与原值备份列名的后缀
+const (
+	conversionColumnSuffix = "__conv"
+	conversionBackupSuffix = "__orig"
+)
+
+// PrepareColumnConversion 创建目标类型的临时列，返回写入转换后的值的列名
+// 转换结果逐批写入临时列，原列在转换完成前保持不变
+func (s *FieldSchemaService) PrepareColumnConversion(ctx context.Context, table *tableEntity.Table, column, toType string) (string, error) {
+	tempColumn := column + conversionColumnSuffix
+	tableID := table.ID().String()
+	// 上次转换中断时可能残留临时列与备份列
+	for _, stale := range []string{tempColumn, column + conversionBackupSuffix} {
+		if err := s.dbProvider.DropColumn(ctx, table.BaseID(), tableID, stale); err != nil {
+			return "", pkgerrors.ErrDatabaseOperation.WithDetails(
+				fmt.Sprintf("清理类型转换临时列失败: %v", err))
+		}
+	}
+	columnDef := database.ColumnDefinition{
+		Name: tempColumn,
+		Type: toType,
+	}
+	if err := s.dbProvider.AddColumn(ctx, table.BaseID(), tableID, columnDef); err != nil {
+		return "", pkgerrors.ErrDatabaseOperation.WithDetails(
+			fmt.Sprintf("创建类型转换临时列失败: %v", err))
+	}
+	return tempColumn, nil
+}
+
+// WriteColumnValues 按记录ID写入一批转换后的值，JSONB 列写入 JSON
+func (s *FieldSchemaService) WriteColumnValues(ctx context.Context, table *tableEntity.Table, column, dbType string, values map[string]interface{}) error {
+	fullTableName := s.dbProvider.GenerateTableName(table.BaseID(), table.ID().String())
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for recordID, value := range values {
+			if value != nil && (dbType == "JSONB" || dbType == "JSON") {
+				data, err := json.Marshal(value)
+				if err != nil {
+					return fmt.Errorf("序列化记录 %s 的值失败: %w", recordID, err)
+				}
+				value = datatypes.JSON(data)
+			}
+			if err := tx.Table(fullTableName).Where("__id = ?", recordID).Update(column, value).Error; err != nil {
+				return fmt.Errorf("写入记录 %s 失败: %w", recordID, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return pkgerrors.ErrDatabaseOperation.WithDetails(
+			fmt.Sprintf("写入转换后的值失败: %v", err))
+	}
+	return nil
+}
+
+// CompleteColumnConversion 用临时列中转换好的值修改原列类型并删除临时列
+// 原值先复制到备份列，字段元数据保存成功后调用 FinishColumnConversion 删除，失败时调用 RestoreColumnConversion 恢复
+func (s *FieldSchemaService) CompleteColumnConversion(ctx context.Context, table *tableEntity.Table, column, tempColumn, fromType, toType string) error {
+	tableID := table.ID().String()
+	backupColumn := column + conversionBackupSuffix
+	if err := s.dbProvider.AddColumn(ctx, table.BaseID(), tableID, database.ColumnDefinition{
+		Name: backupColumn,
+		Type: fromType,
+	}); err != nil {
+		return pkgerrors.ErrDatabaseOperation.WithDetails(
+			fmt.Sprintf("创建类型转换备份列失败: %v", err))
+	}
+	if err := s.dbProvider.AlterColumn(ctx, table.BaseID(), tableID, backupColumn, database.ColumnDefinition{
+		Name:  backupColumn,
+		Type:  fromType,
+		Using: fmt.Sprintf(`"%s"`, column),
+	}); err != nil {
+		s.dropConversionColumn(ctx, table, backupColumn)
+		return pkgerrors.ErrDatabaseOperation.WithDetails(
+			fmt.Sprintf("备份原列失败: %v", err))
+	}
+
+	// 搜索索引随字段类型重建，由调用方在转换完成或恢复后调用 CreateSearchIndexes
+	if err := s.DropSearchIndexes(ctx, table, column); err != nil {
+		s.dropConversionColumn(ctx, table, backupColumn)
+		return pkgerrors.ErrDatabaseOperation.WithDetails(err.Error())
+	}
+
+	columnDef := database.ColumnDefinition{
+		Name:  column,
+		Type:  toType,
+		Using: fmt.Sprintf(`"%s"`, tempColumn),
+	}
+	if err := s.dbProvider.AlterColumn(ctx, table.BaseID(), tableID, column, columnDef); err != nil {
+		s.dropConversionColumn(ctx, table, backupColumn)
+		return pkgerrors.ErrDatabaseOperation.WithDetails(
+			fmt.Sprintf("修改物理表列类型失败: %v", err))
+	}
+	s.dropConversionColumn(ctx, table, tempColumn)
+
+	logger.Info("物理表列类型修改成功",
+		logger.String("table_id", tableID),
+		logger.String("db_field_name", column),
+		logger.String("db_type", toType))
+
+	return nil
+}
+
+// FinishColumnConversion 字段元数据保存成功后删除原值备份列
+func (s *FieldSchemaService) FinishColumnConversion(ctx context.Context, table *tableEntity.Table, column string) {
+	s.dropConversionColumn(ctx, table, column+conversionBackupSuffix)
+}
+
+// RestoreColumnConversion 字段元数据保存失败时用备份列把原列恢复为转换前的类型和值
+func (s *FieldSchemaService) RestoreColumnConversion(ctx context.Context, table *tableEntity.Table, column, fromType string) {
+	backupColumn := column + conversionBackupSuffix
+	columnDef := database.ColumnDefinition{
+		Name:  column,
+		Type:  fromType,
+		Using: fmt.Sprintf(`"%s"`, backupColumn),
+	}
+	if err := s.dbProvider.AlterColumn(ctx, table.BaseID(), table.ID().String(), column, columnDef); err != nil {
+		// 保留备份列以便手动恢复
+		logger.Error("恢复类型转换前的列失败",
+			logger.String("table_id", table.ID().String()),
+			logger.String("column", column),
+			logger.String("backup_column", backupColumn),
+			logger.ErrorField(err))
+		return
+	}
+	s.dropConversionColumn(ctx, table, backupColumn)
+}
+
+// AbortColumnConversion 类型转换失败时删除临时列，原列保持不变
+func (s *FieldSchemaService) AbortColumnConversion(ctx context.Context, table *tableEntity.Table, tempColumn string) {
+	s.dropConversionColumn(ctx, table, tempColumn)
+}
+
+// dropConversionColumn 删除类型转换的临时列或备份列（失败只记录日志）
+func (s *FieldSchemaService) dropConversionColumn(ctx context.Context, table *tableEntity.Table, column string) {
+	if err := s.dbProvider.DropColumn(ctx, table.BaseID(), table.ID().String(), column); err != nil {
+		logger.Warn("删除类型转换临时列失败",
+			logger.String("table_id", table.ID().String()),
+			logger.String("column", column),
+			logger.ErrorField(err))
+	}
+}
+
+// uniqueConstraintName 字段唯一约束名称，与 AddColumn 创建唯一列时的命名一致
+func uniqueConstraintName(baseID, tableID, column string) string {
+	return fmt.Sprintf("%s_%s_%s_unique", baseID, tableID, column)
+}
+
+// SetColumnUnique 添加或删除物理列的唯一约束
+// 添加前将文本列中的空白字符串置为 NULL，与写入时约束字段的空值按 NULL 存储一致
+func (s *FieldSchemaService) SetColumnUnique(ctx context.Context, table *tableEntity.Table, column, dbType string, unique bool) error {
+	baseID := table.BaseID()
+	tableID := table.ID().String()
+	constraintName := uniqueConstraintName(baseID, tableID, column)
+
+	if !unique {
+		if err := s.dbProvider.DropConstraint(ctx, baseID, tableID, constraintName); err != nil {
+			return pkgerrors.ErrDatabaseOperation.WithDetails(
+				fmt.Sprintf("删除唯一约束失败: %v", err))
+		}
+		return nil
+	}
+
+	if isTextColumnType(dbType) {
+		fullTableName := s.dbProvider.GenerateTableName(baseID, tableID)
+		if err := s.db.WithContext(ctx).Table(fullTableName).
+			Where(fmt.Sprintf(`TRIM("%s") = ''`, column)).
+			Update(column, nil).Error; err != nil {
+			return pkgerrors.ErrDatabaseOperation.WithDetails(
+				fmt.Sprintf("清理空白值失败: %v", err))
+		}
+	}
+	if err := s.dbProvider.AddUniqueConstraint(ctx, baseID, tableID, column, constraintName); err != nil {
+		// 检查与添加之间写入了重复值
+		if pkgDatabase.IsConstraintError(err) {
+			return pkgerrors.ErrConstraintConflict.WithDetails(map[string]interface{}{
+				"type":    "unique",
+				"message": "现有数据存在重复值，无法添加唯一约束",
+				"detail":  err.Error(),
+			})
+		}
+		return pkgerrors.ErrDatabaseOperation.WithDetails(
+			fmt.Sprintf("添加唯一约束失败: %v", err))
+	}
+
+	logger.Info("物理表列唯一约束已添加",
+		logger.String("table_id", tableID),
+		logger.String("db_field_name", column),
+		logger.String("constraint", constraintName))
+
+	return nil
+}
+
+// SetColumnNotNull 设置或移除物理列的 NOT NULL 约束
+// SQLite 不支持修改已有列的约束，非空仅由写入前检查保证
+func (s *FieldSchemaService) SetColumnNotNull(ctx context.Context, table *tableEntity.Table, column string, notNull bool) error {
+	if s.dbProvider.DriverName() == "sqlite" {
+		return nil
+	}
+
+	baseID := table.BaseID()
+	tableID := table.ID().String()
+
+	if !notNull {
+		if err := s.dbProvider.DropNotNull(ctx, baseID, tableID, column); err != nil {
+			return pkgerrors.ErrDatabaseOperation.WithDetails(
+				fmt.Sprintf("移除非空约束失败: %v", err))
+		}
+		return nil
+	}
+
+	if err := s.dbProvider.SetNotNull(ctx, baseID, tableID, column); err != nil {
+		// 检查与添加之间写入了空值
+		if pkgDatabase.IsConstraintError(err) {
+			return pkgerrors.ErrConstraintConflict.WithDetails(map[string]interface{}{
+				"type":    "notNull",
+				"message": "现有数据存在空值，无法添加非空约束",
+				"detail":  err.Error(),
+			})
+		}
+		return pkgerrors.ErrDatabaseOperation.WithDetails(
+			fmt.Sprintf("添加非空约束失败: %v", err))
+	}
+
+	logger.Info("物理表列非空约束已添加",
+		logger.String("table_id", tableID),
+		logger.String("db_field_name", column))
+
+	return nil
+}
+
+// isTextColumnType 是否为文本类型的列
+func isTextColumnType(dbType string) bool {
+	dbType = strings.ToUpper(dbType)
+	return dbType == "TEXT" || strings.HasPrefix(dbType, "VARCHAR") || strings.HasPrefix(dbType, "CHARACTER VARYING")
+}
+
 // checkColumnExists 检查列是否存在
 func (s *FieldSchemaService) checkColumnExists(ctx context.Context, baseID, tableName, columnName string) (bool, error) {
 	query := `
@@ -93,6 +93,10 @@ func (m *MockDBProvider) DropPhysicalTable(ctx context.Context, schemaName, tabl
 	return m.Called(ctx, schemaName, tableName).Error(0)
 }
 
+func (m *MockDBProvider) MovePhysicalTable(ctx context.Context, fromSchema, toSchema, tableName string) error {
+	return m.Called(ctx, fromSchema, toSchema, tableName).Error(0)
+}
+
 func (m *MockDBProvider) AddColumn(ctx context.Context, baseID, tableID string, columnDef database.ColumnDefinition) error {
 	args := m.Called(ctx, baseID, tableID, columnDef)
 	return args.Error(0)
@@ -333,3 +337,132 @@ func TestFieldSchemaService_EnsureOrderColumn(t *testing.T) {
 	}
 }
 
+
+func TestFieldSchemaService_ColumnConversion(t *testing.T) {
+	ctx := context.Background()
+	table := createMockTable("tbl_123", "base_123", "test_table")
+
+	t.Run("数据库类型不变时同样经临时列写入", func(t *testing.T) {
+		mockDBProvider := new(MockDBProvider)
+		mockDBProvider.On("DropColumn", mock.Anything, "base_123", "tbl_123", mock.Anything).Return(nil)
+		mockDBProvider.On("AddColumn", mock.Anything, "base_123", "tbl_123",
+			database.ColumnDefinition{Name: "fld_a__conv", Type: "TEXT"}).Return(nil)
+		service := NewFieldSchemaService(new(MockTableRepositoryForSchema), mockDBProvider, nil)
+
+		column, err := service.PrepareColumnConversion(ctx, table, "fld_a", "TEXT")
+		assert.NoError(t, err)
+		assert.Equal(t, "fld_a__conv", column)
+		mockDBProvider.AssertCalled(t, "DropColumn", mock.Anything, "base_123", "tbl_123", "fld_a__conv")
+		mockDBProvider.AssertCalled(t, "DropColumn", mock.Anything, "base_123", "tbl_123", "fld_a__orig")
+	})
+
+	t.Run("备份原列后经临时列修改列类型", func(t *testing.T) {
+		mockDBProvider := new(MockDBProvider)
+		mockDBProvider.On("DropColumn", mock.Anything, "base_123", "tbl_123", mock.Anything).Return(nil)
+		mockDBProvider.On("AddColumn", mock.Anything, "base_123", "tbl_123",
+			database.ColumnDefinition{Name: "fld_a__conv", Type: "NUMERIC"}).Return(nil)
+		mockDBProvider.On("AddColumn", mock.Anything, "base_123", "tbl_123",
+			database.ColumnDefinition{Name: "fld_a__orig", Type: "TEXT"}).Return(nil)
+		mockDBProvider.On("AlterColumn", mock.Anything, "base_123", "tbl_123", "fld_a__orig",
+			database.ColumnDefinition{Name: "fld_a__orig", Type: "TEXT", Using: `"fld_a"`}).Return(nil)
+		mockDBProvider.On("AlterColumn", mock.Anything, "base_123", "tbl_123", "fld_a",
+			database.ColumnDefinition{Name: "fld_a", Type: "NUMERIC", Using: `"fld_a__conv"`}).Return(nil)
+		mockDBProvider.On("DriverName").Return("sqlite")
+		service := NewFieldSchemaService(new(MockTableRepositoryForSchema), mockDBProvider, nil)
+
+		column, err := service.PrepareColumnConversion(ctx, table, "fld_a", "NUMERIC")
+		assert.NoError(t, err)
+		assert.Equal(t, "fld_a__conv", column)
+		assert.NoError(t, service.CompleteColumnConversion(ctx, table, "fld_a", column, "TEXT", "NUMERIC"))
+		mockDBProvider.AssertExpectations(t)
+		// 清理残留的临时列与备份列、删除临时列；备份列保留到字段保存之后
+		mockDBProvider.AssertNumberOfCalls(t, "DropColumn", 3)
+
+		service.FinishColumnConversion(ctx, table, "fld_a")
+		mockDBProvider.AssertNumberOfCalls(t, "DropColumn", 4)
+	})
+
+	t.Run("修改列类型失败时删除备份列，原列不变", func(t *testing.T) {
+		mockDBProvider := new(MockDBProvider)
+		mockDBProvider.On("AddColumn", mock.Anything, "base_123", "tbl_123", mock.Anything).Return(nil)
+		mockDBProvider.On("AlterColumn", mock.Anything, "base_123", "tbl_123", "fld_a__orig", mock.Anything).Return(nil)
+		mockDBProvider.On("AlterColumn", mock.Anything, "base_123", "tbl_123", "fld_a", mock.Anything).
+			Return(errors.New("cannot cast"))
+		mockDBProvider.On("DropColumn", mock.Anything, "base_123", "tbl_123", "fld_a__orig").Return(nil)
+		mockDBProvider.On("DriverName").Return("sqlite")
+		service := NewFieldSchemaService(new(MockTableRepositoryForSchema), mockDBProvider, nil)
+
+		err := service.CompleteColumnConversion(ctx, table, "fld_a", "fld_a__conv", "TEXT", "NUMERIC")
+		assert.Error(t, err)
+		mockDBProvider.AssertExpectations(t)
+		mockDBProvider.AssertNotCalled(t, "DropColumn", mock.Anything, mock.Anything, mock.Anything, "fld_a__conv")
+	})
+
+	t.Run("保存字段失败时用备份列恢复原列", func(t *testing.T) {
+		mockDBProvider := new(MockDBProvider)
+		mockDBProvider.On("AlterColumn", mock.Anything, "base_123", "tbl_123", "fld_a",
+			database.ColumnDefinition{Name: "fld_a", Type: "TEXT", Using: `"fld_a__orig"`}).Return(nil)
+		mockDBProvider.On("DropColumn", mock.Anything, "base_123", "tbl_123", "fld_a__orig").Return(nil)
+		service := NewFieldSchemaService(new(MockTableRepositoryForSchema), mockDBProvider, nil)
+
+		service.RestoreColumnConversion(ctx, table, "fld_a", "TEXT")
+		mockDBProvider.AssertExpectations(t)
+	})
+
+	t.Run("恢复失败时保留备份列", func(t *testing.T) {
+		mockDBProvider := new(MockDBProvider)
+		mockDBProvider.On("AlterColumn", mock.Anything, "base_123", "tbl_123", "fld_a", mock.Anything).
+			Return(errors.New("cannot cast"))
+		service := NewFieldSchemaService(new(MockTableRepositoryForSchema), mockDBProvider, nil)
+
+		service.RestoreColumnConversion(ctx, table, "fld_a", "TEXT")
+		mockDBProvider.AssertNotCalled(t, "DropColumn", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestFieldSchemaService_ColumnConstraints(t *testing.T) {
+	ctx := context.Background()
+	table := createMockTable("tbl_123", "base_123", "test_table")
+
+	t.Run("添加与删除唯一约束", func(t *testing.T) {
+		mockDBProvider := new(MockDBProvider)
+		mockDBProvider.On("AddUniqueConstraint", mock.Anything, "base_123", "tbl_123", "amount", "base_123_tbl_123_amount_unique").Return(nil)
+		mockDBProvider.On("DropConstraint", mock.Anything, "base_123", "tbl_123", "base_123_tbl_123_amount_unique").Return(nil)
+		service := NewFieldSchemaService(new(MockTableRepositoryForSchema), mockDBProvider, nil)
+
+		assert.NoError(t, service.SetColumnUnique(ctx, table, "amount", "NUMERIC", true))
+		assert.NoError(t, service.SetColumnUnique(ctx, table, "amount", "NUMERIC", false))
+		mockDBProvider.AssertExpectations(t)
+	})
+
+	t.Run("添加非空约束", func(t *testing.T) {
+		mockDBProvider := new(MockDBProvider)
+		mockDBProvider.On("DriverName").Return("postgres")
+		mockDBProvider.On("SetNotNull", mock.Anything, "base_123", "tbl_123", "owner").Return(nil)
+		mockDBProvider.On("DropNotNull", mock.Anything, "base_123", "tbl_123", "owner").Return(nil)
+		service := NewFieldSchemaService(new(MockTableRepositoryForSchema), mockDBProvider, nil)
+
+		assert.NoError(t, service.SetColumnNotNull(ctx, table, "owner", true))
+		assert.NoError(t, service.SetColumnNotNull(ctx, table, "owner", false))
+		mockDBProvider.AssertExpectations(t)
+	})
+
+	t.Run("SQLite 跳过非空约束", func(t *testing.T) {
+		mockDBProvider := new(MockDBProvider)
+		mockDBProvider.On("DriverName").Return("sqlite")
+		service := NewFieldSchemaService(new(MockTableRepositoryForSchema), mockDBProvider, nil)
+
+		assert.NoError(t, service.SetColumnNotNull(ctx, table, "owner", true))
+		mockDBProvider.AssertNotCalled(t, "SetNotNull", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("添加约束失败", func(t *testing.T) {
+		mockDBProvider := new(MockDBProvider)
+		mockDBProvider.On("AddUniqueConstraint", mock.Anything, "base_123", "tbl_123", "amount", mock.Anything).
+			Return(errors.New("connection lost"))
+		service := NewFieldSchemaService(new(MockTableRepositoryForSchema), mockDBProvider, nil)
+
+		err := service.SetColumnUnique(ctx, table, "amount", "NUMERIC", true)
+		assert.Error(t, err)
+	})
+}
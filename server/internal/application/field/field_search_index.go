@@ -0,0 +1,105 @@
+package field
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldService "github.com/easyspace-ai/luckdb/server/internal/domain/fields/service"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+	pkgDatabase "github.com/easyspace-ai/luckdb/server/pkg/database"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// searchIndex 记录搜索索引定义
+type searchIndex struct {
+	name string
+	expr string // USING 之后的部分，如 gin ("title" gin_trgm_ops)
+}
+
+// searchIndexesFor 可搜索文本列需要的索引：pg_trgm GIN 索引，长文本另加 tsvector GIN 索引
+// 选项数组与关联标题存储为 JSONB，不建立索引
+//
+// 表达式须与记录搜索编译出的条件一致；已有表的索引由迁移 000015_create_record_search_indexes 创建
+func searchIndexesFor(tableID string, field *entity.Field) []searchIndex {
+	column := field.DBFieldName().String()
+	if !fieldService.IsSearchableField(field) || column == "" || !isTextColumnType(field.DBFieldType()) {
+		return nil
+	}
+
+	quoted := fmt.Sprintf(`"%s"`, column)
+	indexes := []searchIndex{{
+		name: searchIndexName(tableID, column, "trgm"),
+		expr: fmt.Sprintf("gin (%s gin_trgm_ops)", quoted),
+	}}
+	if field.Type().String() == valueobject.TypeLongText {
+		indexes = append(indexes, searchIndex{
+			name: searchIndexName(tableID, column, "tsv"),
+			expr: fmt.Sprintf("gin (to_tsvector('simple', COALESCE(%s, '')))", quoted),
+		})
+	}
+	return indexes
+}
+
+// searchIndexName 索引名（PostgreSQL 标识符最长63字节，用哈希保证长度）
+// 与迁移中 left(md5(table_id || '|' || db_field_name), 16) 的计算方式一致
+func searchIndexName(tableID, column, kind string) string {
+	sum := md5.Sum([]byte(tableID + "|" + column))
+	return fmt.Sprintf("idx_search_%s_%s", kind, hex.EncodeToString(sum[:8]))
+}
+
+// HasSearchIndexes 字段是否需要建立搜索索引
+func HasSearchIndexes(field *entity.Field) bool {
+	return len(searchIndexesFor("", field)) > 0
+}
+
+// CreateSearchIndexes 为新建或转换为文本类型的字段创建搜索索引
+// 使用 CREATE INDEX CONCURRENTLY 不阻塞写入；索引缺失只影响搜索性能，失败时只记录警告
+func (s *FieldSchemaService) CreateSearchIndexes(ctx context.Context, table *tableEntity.Table, field *entity.Field) {
+	tableID := table.ID().String()
+	indexes := searchIndexesFor(tableID, field)
+	if len(indexes) == 0 || s.dbProvider.DriverName() != "postgres" {
+		return
+	}
+
+	fullTableName := fmt.Sprintf(`"%s"."%s"`, table.BaseID(), tableID)
+	create := func() {
+		ctx := context.WithoutCancel(ctx)
+		for _, index := range indexes {
+			sql := fmt.Sprintf(`CREATE INDEX CONCURRENTLY IF NOT EXISTS "%s" ON %s USING %s`,
+				index.name, fullTableName, index.expr)
+			if err := s.db.WithContext(ctx).Exec(sql).Error; err != nil {
+				logger.Warn("创建搜索索引失败",
+					logger.String("table_id", tableID),
+					logger.String("field_id", field.ID().String()),
+					logger.String("index", index.name),
+					logger.ErrorField(err))
+			}
+		}
+	}
+
+	// CONCURRENTLY 不能在事务中执行，且要等待事务持有的表锁，事务中时提交后再创建
+	if pkgDatabase.InTransaction(ctx) {
+		pkgDatabase.AddTxCallback(ctx, create)
+		return
+	}
+	create()
+}
+
+// DropSearchIndexes 删除列的搜索索引（修改列类型前调用，gin_trgm_ops 不支持非文本类型）
+func (s *FieldSchemaService) DropSearchIndexes(ctx context.Context, table *tableEntity.Table, column string) error {
+	if s.dbProvider.DriverName() != "postgres" {
+		return nil
+	}
+
+	for _, kind := range []string{"trgm", "tsv"} {
+		sql := fmt.Sprintf(`DROP INDEX IF EXISTS "%s"."%s"`, table.BaseID(), searchIndexName(table.ID().String(), column, kind))
+		if err := s.db.WithContext(ctx).Exec(sql).Error; err != nil {
+			return fmt.Errorf("删除搜索索引失败: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package field
+
+import (
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSearchIndexTestField(t *testing.T, name, fieldType string) *entity.Field {
+	fieldName, err := valueobject.NewFieldName(name)
+	require.NoError(t, err)
+	ft, err := valueobject.NewFieldType(fieldType)
+	require.NoError(t, err)
+	field, err := entity.NewField("tbl_1", fieldName, ft, "usr_1")
+	require.NoError(t, err)
+	return field
+}
+
+func TestSearchIndexesFor(t *testing.T) {
+	name := newSearchIndexTestField(t, "Name", valueobject.TypeSingleLineText)
+	notes := newSearchIndexTestField(t, "Notes", valueobject.TypeLongText)
+	amount := newSearchIndexTestField(t, "Amount", valueobject.TypeNumber)
+
+	indexes := searchIndexesFor("tbl_1", name)
+	require.Len(t, indexes, 1)
+	assert.Equal(t, `gin ("`+name.DBFieldName().String()+`" gin_trgm_ops)`, indexes[0].expr)
+
+	indexes = searchIndexesFor("tbl_1", notes)
+	require.Len(t, indexes, 2)
+	assert.Equal(t, `gin (to_tsvector('simple', COALESCE("`+notes.DBFieldName().String()+`", '')))`, indexes[1].expr)
+
+	assert.Empty(t, searchIndexesFor("tbl_1", amount))
+}
+
+func TestSearchIndexName(t *testing.T) {
+	// 与迁移中 left(md5('tbl_1|name'), 16) 一致
+	assert.Equal(t, "idx_search_trgm_263bf6ab149cff2d", searchIndexName("tbl_1", "name", "trgm"))
+	assert.NotEqual(t, searchIndexName("tbl_2", "name", "trgm"), searchIndexName("tbl_1", "name", "trgm"))
+	assert.LessOrEqual(t, len(searchIndexName("tbl_1", "name", "trgm")), 63)
+}
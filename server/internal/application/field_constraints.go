@@ -0,0 +1,287 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/validation"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	pkgDatabase "github.com/easyspace-ai/luckdb/server/pkg/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// fieldConstraintConflictLimit 约束冲突时最多返回的记录ID数（空值记录、重复值分组各自计数）
+const fieldConstraintConflictLimit = 100
+
+// takeConstraintOption 兼容在 options 中传入 unique / notNull：取出布尔值并从 options 中删除
+func takeConstraintOption(options map[string]interface{}, key string) *bool {
+	value, ok := options[key].(bool)
+	if !ok {
+		return nil
+	}
+	delete(options, key)
+	return &value
+}
+
+// applyValidationRulesOption 取出 options 中的 validation 并设置字段的校验规则，null 或空对象清除规则
+//
+// 规则只约束之后的写入，不校验已有数据
+func applyValidationRulesOption(field *entity.Field, options map[string]interface{}) error {
+	raw, ok := options["validation"]
+	if !ok {
+		return nil
+	}
+	delete(options, "validation")
+
+	rules, err := validation.ParseValidationRules(field.Type().String(), raw)
+	if err != nil {
+		return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("字段校验规则无效: %v", err))
+	}
+	fieldOptions := field.Options()
+	if fieldOptions == nil {
+		fieldOptions = valueobject.NewFieldOptions()
+	}
+	fieldOptions.Validation = rules
+	if err := field.UpdateOptions(fieldOptions); err != nil {
+		return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("字段选项无效: %v", err))
+	}
+	return nil
+}
+
+// checkConstraintSupport 检查字段类型是否支持开启唯一、非空约束
+func checkConstraintSupport(field *entity.Field, unique, notNull bool) error {
+	if !unique && !notNull {
+		return nil
+	}
+	if field.IsComputed() || field.IsVirtual() {
+		return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("计算字段 %s 不支持唯一或非空约束", field.Name().String()))
+	}
+	switch field.Type().String() {
+	case valueobject.TypeLink:
+		return pkgerrors.ErrValidationFailed.WithDetails("关联字段不支持唯一或非空约束")
+	case valueobject.TypeMultipleSelect, valueobject.TypeAttachment, valueobject.TypeCheckbox:
+		if unique {
+			return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("%s 类型字段不支持唯一约束", field.Type().String()))
+		}
+	}
+	return nil
+}
+
+// changeFieldConstraints 修改字段的唯一、非空约束
+//
+// 开启约束前扫描已有数据，存在重复值或空值时返回 FIELD_CONSTRAINT_CONFLICT 及冲突的记录ID，
+// 不做任何修改；校验通过后修改物理列约束并更新字段实体，由调用方保存
+func (s *FieldService) changeFieldConstraints(ctx context.Context, field *entity.Field, unique, notNull bool) error {
+	if unique == field.IsUnique() && notNull == field.NotNull() {
+		return nil
+	}
+	enableUnique := unique && !field.IsUnique()
+	enableNotNull := notNull && !field.NotNull()
+	if err := checkConstraintSupport(field, enableUnique, enableNotNull); err != nil {
+		return err
+	}
+	if enableUnique || enableNotNull {
+		if err := s.checkConstraintConflicts(ctx, field, enableUnique, enableNotNull); err != nil {
+			return err
+		}
+	}
+
+	table, err := s.tableRepo.GetByID(ctx, field.TableID())
+	if err != nil {
+		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取Table信息失败: %v", err))
+	}
+	if table == nil {
+		return pkgerrors.ErrNotFound.WithDetails("Table不存在")
+	}
+	column := field.DBFieldName().String()
+	if unique != field.IsUnique() {
+		if err := s.schemaService.SetColumnUnique(ctx, table, column, field.DBFieldType(), unique); err != nil {
+			return err
+		}
+	}
+	if notNull != field.NotNull() {
+		if err := s.schemaService.SetColumnNotNull(ctx, table, column, notNull); err != nil {
+			return err
+		}
+	}
+
+	if err := field.SetUnique(unique); err != nil {
+		return pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if err := field.SetNotNull(notNull); err != nil {
+		return pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+
+	logger.Info("字段约束已更新",
+		logger.String("field_id", field.ID().String()),
+		logger.Bool("unique", unique),
+		logger.Bool("not_null", notNull))
+
+	return nil
+}
+
+// applyNewFieldConstraints 为新建字段的物理列添加约束（新列没有数据，无需校验重复值）
+func (s *FieldService) applyNewFieldConstraints(ctx context.Context, field *entity.Field) error {
+	if !field.IsUnique() && !field.NotNull() {
+		return nil
+	}
+	table, err := s.tableRepo.GetByID(ctx, field.TableID())
+	if err != nil {
+		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取Table信息失败: %v", err))
+	}
+	if table == nil {
+		return pkgerrors.ErrNotFound.WithDetails("Table不存在")
+	}
+	column := field.DBFieldName().String()
+	if field.IsUnique() {
+		if err := s.schemaService.SetColumnUnique(ctx, table, column, field.DBFieldType(), true); err != nil {
+			return err
+		}
+	}
+	if field.NotNull() {
+		if err := s.schemaService.SetColumnNotNull(ctx, table, column, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkNewFieldNotNull 新建非空字段时检查表中是否已有记录（已有记录的新列都为空）
+func (s *FieldService) checkNewFieldNotNull(ctx context.Context, tableID string) error {
+	if s.recordRepo == nil {
+		return pkgerrors.ErrInternalServer.WithDetails("未配置记录仓储，无法校验已有数据")
+	}
+	page, err := s.recordRepo.ListPage(ctx, recordRepo.RecordFilter{
+		TableID: &tableID,
+		Limit:   fieldConstraintConflictLimit,
+	})
+	if err != nil {
+		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("读取记录失败: %v", err))
+	}
+	if len(page.Records) == 0 {
+		return nil
+	}
+	recordIDs := make([]string, len(page.Records))
+	for i, record := range page.Records {
+		recordIDs[i] = record.ID().String()
+	}
+	return pkgerrors.ErrConstraintConflict.WithDetails(map[string]interface{}{
+		"type":    "notNull",
+		"message": fmt.Sprintf("表中已有 %d 条记录，新字段的值为空，无法开启非空约束", page.Total),
+		"notNull": map[string]interface{}{
+			"count":     page.Total,
+			"recordIds": recordIDs,
+		},
+	})
+}
+
+// checkConstraintConflicts 扫描已有数据，返回违反约束的记录
+//
+// 非空：值为空的记录；唯一：非空值重复的记录，按值分组。空值不参与唯一性比较
+func (s *FieldService) checkConstraintConflicts(ctx context.Context, field *entity.Field, unique, notNull bool) error {
+	if s.recordRepo == nil {
+		return pkgerrors.ErrInternalServer.WithDetails("未配置记录仓储，无法校验已有数据")
+	}
+
+	fieldID := field.ID().String()
+	emptyIDs := make([]string, 0)
+	emptyCount := 0
+	firstByValue := make(map[string]string) // 值 → 第一条记录ID
+	duplicates := make(map[string][]string) // 重复的值 → 记录ID
+	duplicateOrder := make([]string, 0)     // 重复值出现的顺序
+	values := make(map[string]interface{})  // 重复的值 → 原值（用于返回）
+	duplicateCount := 0
+
+	err := s.scanFieldValues(ctx, field.TableID(), fieldID, func(records []*recordEntity.Record) error {
+		for _, record := range records {
+			recordID := record.ID().String()
+			value, _ := record.Data().Get(fieldID)
+			if pkgDatabase.IsBlankValue(value) {
+				if notNull {
+					emptyCount++
+					if len(emptyIDs) < fieldConstraintConflictLimit {
+						emptyIDs = append(emptyIDs, recordID)
+					}
+				}
+				continue
+			}
+			if !unique {
+				continue
+			}
+			key := constraintValueKey(value)
+			first, seen := firstByValue[key]
+			if !seen {
+				firstByValue[key] = recordID
+				continue
+			}
+			if _, ok := duplicates[key]; !ok {
+				duplicateOrder = append(duplicateOrder, key)
+				values[key] = value
+				duplicates[key] = []string{first}
+				duplicateCount++
+			}
+			if len(duplicates[key]) < fieldConstraintConflictLimit {
+				duplicates[key] = append(duplicates[key], recordID)
+			}
+			duplicateCount++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if emptyCount == 0 && len(duplicateOrder) == 0 {
+		return nil
+	}
+
+	details := map[string]interface{}{
+		"field_id":   fieldID,
+		"field_name": field.Name().String(),
+	}
+	messages := make([]string, 0, 2)
+	if emptyCount > 0 {
+		details["notNull"] = map[string]interface{}{
+			"count":     emptyCount,
+			"recordIds": emptyIDs,
+		}
+		messages = append(messages, fmt.Sprintf("%d 条记录的值为空", emptyCount))
+	}
+	if len(duplicateOrder) > 0 {
+		groups := make([]map[string]interface{}, 0, len(duplicateOrder))
+		for _, key := range duplicateOrder {
+			if len(groups) >= fieldConstraintConflictLimit {
+				break
+			}
+			groups = append(groups, map[string]interface{}{
+				"value":     values[key],
+				"recordIds": duplicates[key],
+			})
+		}
+		details["unique"] = map[string]interface{}{
+			"count":  duplicateCount,
+			"groups": groups,
+		}
+		messages = append(messages, fmt.Sprintf("%d 条记录的值重复", duplicateCount))
+	}
+	details["message"] = fmt.Sprintf("字段【%s】现有数据不满足约束：%s", field.Name().String(), strings.Join(messages, "，"))
+
+	return pkgerrors.ErrConstraintConflict.WithDetails(details)
+}
+
+// constraintValueKey 唯一性比较用的键，与数据库按值比较一致（区分大小写）
+func constraintValueKey(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,547 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldService "github.com/easyspace-ai/luckdb/server/internal/domain/fields/service"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/validation"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+	tableService "github.com/easyspace-ai/luckdb/server/internal/domain/table/service"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/schema"
+	"github.com/easyspace-ai/luckdb/server/pkg/authctx"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+const (
+	fieldConversionChunkSize  = 500 // 类型转换时每批读取、写入的记录数
+	fieldConversionSampleSize = 20  // 转换预览返回的示例单元格数
+)
+
+// FieldLinkDeriver 写入关联单元格的外键并同步对称字段（由 table/service.LinkService 实现）
+type FieldLinkDeriver interface {
+	GetDerivateByLink(ctx context.Context, tableID string, cellContexts []tableService.LinkCellContext) (*tableService.LinkDerivation, error)
+}
+
+// SetRecordRepository 设置记录仓储（字段类型转换时读取已有数据）
+func (s *FieldService) SetRecordRepository(records recordRepo.RecordRepository) {
+	s.recordRepo = records
+}
+
+// SetLinkDeriver 设置关联外键写入器（转换为关联字段时写入外键）
+func (s *FieldService) SetLinkDeriver(linker FieldLinkDeriver) {
+	s.linker = linker
+}
+
+// fieldConversion 一次字段类型转换：from 为转换前的字段，to 为目标类型的字段，二者ID相同
+type fieldConversion struct {
+	from      *entity.Field
+	to        *entity.Field
+	table     *tableEntity.Table
+	converter *fieldService.CellConverter
+}
+
+// PreviewFieldConversion 预览字段类型转换（dry-run）
+//
+// 逐格转换全部记录但不写入：统计非空单元格中会被清空或丢失部分内容的数量，
+// 返回前若干个示例单元格转换前后的显示值，以及转换为选择字段时会自动新增的选项
+func (s *FieldService) PreviewFieldConversion(ctx context.Context, fieldID string, req dto.FieldConversionPreviewRequest) (*dto.FieldConversionPreview, error) {
+	field, err := s.fieldRepo.FindByID(ctx, valueobject.NewFieldID(fieldID))
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找字段失败: %v", err))
+	}
+	if field == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("字段不存在")
+	}
+
+	conv, err := s.prepareFieldConversion(ctx, field, snapshotField(field), req.Type, req.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	existingChoices := make(map[string]bool)
+	if options := conv.to.Options(); options != nil && options.Select != nil {
+		for _, choice := range options.Select.Choices {
+			existingChoices[choice.ID] = true
+		}
+	}
+
+	preview := &dto.FieldConversionPreview{
+		FieldID:  fieldID,
+		FromType: field.Type().String(),
+		ToType:   conv.to.Type().String(),
+		Samples:  []dto.FieldConversionSample{},
+	}
+	err = s.scanFieldValues(ctx, field.TableID(), fieldID, func(records []*recordEntity.Record) error {
+		for _, record := range records {
+			value, _ := record.Data().Get(fieldID)
+			before := fieldService.FormatCellText(conv.from, value)
+			if before == "" {
+				continue
+			}
+			converted, lost := conv.converter.Convert(value)
+			preview.Total++
+			if lost {
+				preview.Lost++
+			} else {
+				preview.Converted++
+			}
+			if len(preview.Samples) < fieldConversionSampleSize {
+				preview.Samples = append(preview.Samples, dto.FieldConversionSample{
+					RecordID: record.ID().String(),
+					Before:   before,
+					After:    fieldService.FormatCellText(conv.to, converted),
+					Lost:     lost,
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if options := conv.to.Options(); options != nil && options.Select != nil {
+		for _, choice := range options.Select.Choices {
+			if !existingChoices[choice.ID] {
+				preview.NewChoices = append(preview.NewChoices, choice)
+			}
+		}
+	}
+	if link := linkOptionsOf(field); link != nil && link.SymmetricFieldID != "" && conv.to.Type().String() != valueobject.TypeLink {
+		preview.Warnings = append(preview.Warnings, "关联表中的对称字段将被删除")
+	}
+	if preview.Lost > 0 {
+		preview.Warnings = append(preview.Warnings, fmt.Sprintf("%d 个单元格无法完整转换，转换后将被清空或丢失部分内容", preview.Lost))
+	}
+	return preview, nil
+}
+
+// convertFieldType 把字段转换为新类型并改写已有数据，由 UpdateField 调用
+//
+// 流程：逐批读取记录并把转换结果写入目标类型的临时列 → 备份原列并用临时列修改原列类型 →
+// 保存字段 → 转换为关联字段时创建对称字段并写入外键，由关联字段转出时删除对称字段与中间表 →
+// 删除备份列。修改列类型之前失败时原列保持不变，之后失败（创建关联 Schema、保存字段、
+// 处理对称字段或外键）时恢复原字段定义，并用备份列恢复原列
+func (s *FieldService) convertFieldType(ctx context.Context, field *entity.Field, newType string, options map[string]interface{}) error {
+	from := snapshotField(field)
+	conv, err := s.prepareFieldConversion(ctx, from, field, newType, options)
+	if err != nil {
+		return err
+	}
+
+	fieldID := field.ID().String()
+	tableID := field.TableID()
+	column := field.DBFieldName().String()
+	dbType := field.DBFieldType()
+	toLink := field.Type().String() == valueobject.TypeLink
+
+	target, err := s.schemaService.PrepareColumnConversion(ctx, conv.table, column, dbType)
+	if err != nil {
+		return err
+	}
+
+	// 每批转换结果立即写入临时列，原列在修改列类型之前保持不变
+	var links []tableService.LinkCellContext
+	converted, lost := 0, 0
+	err = s.scanFieldValues(ctx, tableID, fieldID, func(records []*recordEntity.Record) error {
+		values := make(map[string]interface{}, len(records))
+		for _, record := range records {
+			value, ok := record.Data().Get(fieldID)
+			if !ok || value == nil {
+				continue
+			}
+			newValue, isLost := conv.converter.Convert(value)
+			converted++
+			if isLost {
+				lost++
+			}
+			values[record.ID().String()] = newValue
+			if toLink && newValue != nil {
+				links = append(links, tableService.LinkCellContext{
+					RecordID: record.ID().String(),
+					FieldID:  fieldID,
+					NewValue: newValue,
+				})
+			}
+		}
+		if len(values) == 0 {
+			return nil
+		}
+		return s.schemaService.WriteColumnValues(ctx, conv.table, target, dbType, values)
+	})
+	if err == nil {
+		err = s.schemaService.CompleteColumnConversion(ctx, conv.table, column, target, from.DBFieldType(), dbType)
+	}
+	if err != nil {
+		s.schemaService.AbortColumnConversion(ctx, conv.table, target)
+		s.schemaService.CreateSearchIndexes(ctx, conv.table, from)
+		return err
+	}
+
+	// 列类型已修改，之后失败时用备份列恢复原列
+	if toLink {
+		if err := s.createConvertedLinkSchema(ctx, conv.table, field); err != nil {
+			s.schemaService.RestoreColumnConversion(ctx, conv.table, column, from.DBFieldType())
+			s.schemaService.CreateSearchIndexes(ctx, conv.table, from)
+			return err
+		}
+	}
+
+	if err := s.fieldRepo.Save(ctx, field); err != nil {
+		if toLink {
+			s.discardConvertedLinkSchema(ctx, conv.table, field)
+		}
+		s.schemaService.RestoreColumnConversion(ctx, conv.table, column, from.DBFieldType())
+		s.schemaService.CreateSearchIndexes(ctx, conv.table, from)
+		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存字段失败: %v", err))
+	}
+
+	// 对称字段与外键在删除备份列之前处理，失败时恢复原字段定义与原列
+	var linkErr error
+	if toLink {
+		linkErr = s.finishLinkConversion(ctx, field, links)
+	} else if from.Type().String() == valueobject.TypeLink {
+		linkErr = s.dropConvertedLinkSchema(ctx, conv.table, from)
+	}
+	if linkErr != nil {
+		if toLink {
+			s.discardConvertedLinkSchema(ctx, conv.table, field)
+		}
+		if err := s.fieldRepo.Save(ctx, from); err != nil {
+			logger.Error("类型转换失败后恢复字段定义失败",
+				logger.String("field_id", fieldID),
+				logger.ErrorField(err))
+		}
+		s.schemaService.RestoreColumnConversion(ctx, conv.table, column, from.DBFieldType())
+		s.schemaService.CreateSearchIndexes(ctx, conv.table, from)
+		return linkErr
+	}
+	s.schemaService.FinishColumnConversion(ctx, conv.table, column)
+	s.schemaService.CreateSearchIndexes(ctx, conv.table, field)
+
+	if s.depGraphRepo != nil {
+		if err := s.depGraphRepo.InvalidateCache(ctx, tableID); err != nil {
+			logger.Warn("清除依赖图缓存失败（不影响类型转换）",
+				logger.String("table_id", tableID),
+				logger.ErrorField(err))
+		}
+	}
+
+	logger.Info("字段类型转换完成",
+		logger.String("field_id", fieldID),
+		logger.String("from_type", from.Type().String()),
+		logger.String("to_type", field.Type().String()),
+		logger.Int("cells", converted),
+		logger.Int("lost", lost))
+	return nil
+}
+
+// prepareFieldConversion 校验类型转换并构造目标字段
+//
+// to 修改为目标类型并应用请求中的选项：选择字段沿用原选项或使用请求中的 choices，
+// 关联字段需要指定关联表（foreignTableId），关系默认为 manyMany
+func (s *FieldService) prepareFieldConversion(ctx context.Context, from, to *entity.Field, newType string, options map[string]interface{}) (*fieldConversion, error) {
+	fieldType, err := valueobject.NewFieldType(newType)
+	if err != nil {
+		return nil, pkgerrors.ErrInvalidFieldType.WithDetails(map[string]interface{}{
+			"type":  newType,
+			"error": err.Error(),
+		})
+	}
+	if from.Type().Equals(fieldType) {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("字段已是 %s 类型", newType))
+	}
+	if s.recordRepo == nil {
+		return nil, pkgerrors.ErrInternalServer.WithDetails("未配置记录仓储，无法转换字段数据")
+	}
+	if from.IsUnique() || from.NotNull() {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("字段开启了唯一或非空约束，请先关闭约束再变更类型")
+	}
+	if fieldType.String() == valueobject.TypeLink && from.IsPrimary() {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("主字段不能转换为关联字段")
+	}
+	if from.Type().String() == valueobject.TypeLink {
+		if err := s.checkLinkDependents(ctx, from); err != nil {
+			return nil, err
+		}
+	}
+
+	table, err := s.tableRepo.GetByID(ctx, from.TableID())
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取Table信息失败: %v", err))
+	}
+	if table == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("Table不存在")
+	}
+
+	if err := to.ChangeType(fieldType); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(
+			fmt.Sprintf("不能从 %s 转换为 %s: %v", from.Type().String(), newType, err))
+	}
+
+	fieldOptions := to.Options()
+	if fieldOptions == nil {
+		fieldOptions = valueobject.NewFieldOptions()
+	}
+	fieldOptions.Link = nil
+	// 不适用于新类型的校验规则一并清除（请求中可重新传入 validation）
+	if validation.CheckValidationRules(fieldType.String(), fieldOptions.Validation) != nil {
+		fieldOptions.Validation = nil
+	}
+	isSelect := fieldType.String() == valueobject.TypeSelect || fieldType.String() == valueobject.TypeSingleSelect ||
+		fieldType.String() == valueobject.TypeMultipleSelect
+	if !isSelect {
+		fieldOptions.Select = nil
+	}
+
+	var linkTitles map[string]string
+	switch {
+	case isSelect:
+		if choices := s.optionsService.ExtractChoicesFromOptions(options); choices != nil {
+			fieldOptions.Select = &valueobject.SelectOptions{Choices: choices}
+		} else if fieldOptions.Select == nil {
+			fieldOptions.Select = &valueobject.SelectOptions{Choices: []valueobject.SelectChoice{}}
+		}
+		if prevent, ok := options["preventAutoNewOptions"].(bool); ok {
+			fieldOptions.Select.PreventAutoNewOptions = prevent
+		}
+	case fieldType.String() == valueobject.TypeLink:
+		link := s.optionsService.ExtractLinkOptionsFromOptions(options)
+		if link == nil {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("转换为关联字段需要指定关联表（foreignTableId）")
+		}
+		if link.Relationship == "" {
+			link.Relationship = "manyMany"
+		}
+		foreignTable, err := s.tableRepo.GetByID(ctx, link.LinkedTableID)
+		if err != nil {
+			return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取关联表失败: %v", err))
+		}
+		if foreignTable == nil || foreignTable.BaseID() != table.BaseID() {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("关联表不存在或不在同一 Base: %s", link.LinkedTableID))
+		}
+		fieldOptions.Link = link
+		if linkTitles, err = s.linkTitlesForConversion(ctx, link); err != nil {
+			return nil, err
+		}
+	case fieldType.String() == valueobject.TypeRating:
+		if max, ok := options["max"].(float64); ok && max > 0 {
+			fieldOptions.Rating = &valueobject.RatingOptions{Max: int(max)}
+		}
+	}
+	if err := to.UpdateOptions(fieldOptions); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("字段选项无效: %v", err))
+	}
+	s.optionsService.ApplyCommonFieldOptions(&fieldOptionsWrapper{field: to}, options)
+
+	return &fieldConversion{
+		from:      from,
+		to:        to,
+		table:     table,
+		converter: fieldService.NewCellConverter(from, to, linkTitles),
+	}, nil
+}
+
+// checkLinkDependents 关联字段被查找、汇总或计数字段使用时不能转换
+func (s *FieldService) checkLinkDependents(ctx context.Context, link *entity.Field) error {
+	fields, err := s.fieldRepo.FindByTableID(ctx, link.TableID())
+	if err != nil {
+		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取字段列表失败: %v", err))
+	}
+	for _, field := range fields {
+		if moveDependencyLinkID(field) == link.ID().String() {
+			return pkgerrors.ErrValidationFailed.WithDetails(
+				fmt.Sprintf("字段 %s 依赖该关联字段，请先删除或修改后再转换", field.Name().String()))
+		}
+	}
+	return nil
+}
+
+// linkTitlesForConversion 读取被关联表显示字段的标题：标题 → 记录ID，重名的标题映射为空字符串
+//
+// 显示字段依次取 lookupFieldId、主字段、第一个非计算字段
+func (s *FieldService) linkTitlesForConversion(ctx context.Context, link *valueobject.LinkOptions) (map[string]string, error) {
+	fields, err := s.fieldRepo.FindByTableID(ctx, link.LinkedTableID)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取关联表字段失败: %v", err))
+	}
+
+	var lookup, primary, firstStored *entity.Field
+	for _, field := range fields {
+		if link.LookupFieldID != "" && field.ID().String() == link.LookupFieldID {
+			lookup = field
+			break
+		}
+		if primary == nil && field.IsPrimary() {
+			primary = field
+		}
+		if firstStored == nil && !field.IsComputed() {
+			firstStored = field
+		}
+	}
+	if lookup == nil {
+		lookup = primary
+	}
+	if lookup == nil {
+		lookup = firstStored
+	}
+
+	titles := make(map[string]string)
+	if lookup == nil {
+		return titles, nil
+	}
+	lookupID := lookup.ID().String()
+	err = s.scanFieldValues(ctx, link.LinkedTableID, lookupID, func(records []*recordEntity.Record) error {
+		for _, record := range records {
+			value, _ := record.Data().Get(lookupID)
+			title := fieldService.FormatCellText(lookup, value)
+			if title == "" {
+				continue
+			}
+			if _, exists := titles[title]; exists {
+				titles[title] = ""
+				continue
+			}
+			titles[title] = record.ID().String()
+		}
+		return nil
+	})
+	return titles, err
+}
+
+// createConvertedLinkSchema 为转换后的关联字段创建中间表或外键列
+func (s *FieldService) createConvertedLinkSchema(ctx context.Context, table *tableEntity.Table, field *entity.Field) error {
+	linkOptions := field.Options().Link
+	linkFieldOptions, err := s.linkService.ConvertToLinkFieldOptions(ctx, field.TableID(), linkOptions, field)
+	if err != nil {
+		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("转换 Link 字段选项失败: %v", err))
+	}
+	if err := s.schemaService.CreateLinkFieldSchema(ctx, table, field, linkFieldOptions, linkOptions.AllowMultiple); err != nil {
+		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("创建 Link 字段 Schema 失败: %v", err))
+	}
+	return nil
+}
+
+// finishLinkConversion 创建对称字段并按转换后的单元格写入外键
+//
+// 在删除备份列之前调用，返回错误时由调用方删除已创建的对称字段与关联 Schema 并恢复原列
+func (s *FieldService) finishLinkConversion(ctx context.Context, field *entity.Field, links []tableService.LinkCellContext) error {
+	linkOptions := field.Options().Link
+	if linkOptions.IsSymmetric && linkOptions.SymmetricFieldID == "" {
+		userID, ok := authctx.UserFrom(ctx)
+		if !ok {
+			userID = field.CreatedBy()
+		}
+		if _, err := s.linkService.CreateSymmetricField(ctx, field, linkOptions, userID); err != nil {
+			return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("创建对称字段失败: %v", err))
+		}
+	}
+
+	if s.linker == nil {
+		return nil
+	}
+	for start := 0; start < len(links); start += fieldConversionChunkSize {
+		end := min(start+fieldConversionChunkSize, len(links))
+		if _, err := s.linker.GetDerivateByLink(ctx, field.TableID(), links[start:end]); err != nil {
+			return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("写入关联外键失败: %v", err))
+		}
+	}
+	return nil
+}
+
+// dropConvertedLinkSchema 关联字段转为其他类型后删除对称字段与中间表
+//
+// 删除对称字段失败时返回错误；对称字段删除后中间表已不再使用，删除失败只记录日志。
+// manyOne、oneOne 的外键保存在字段自身的列中，随类型转换一并改写
+func (s *FieldService) dropConvertedLinkSchema(ctx context.Context, table *tableEntity.Table, from *entity.Field) error {
+	linkOptions := linkOptionsOf(from)
+	if linkOptions == nil {
+		return nil
+	}
+	if linkOptions.SymmetricFieldID != "" {
+		if err := s.deleteSymmetricField(ctx, linkOptions.SymmetricFieldID); err != nil {
+			return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("删除对称字段 %s 失败: %v", linkOptions.SymmetricFieldID, err))
+		}
+	}
+
+	linkFieldOptions, err := s.linkService.ConvertToLinkFieldOptions(ctx, from.TableID(), linkOptions, from)
+	if err != nil {
+		return nil
+	}
+	switch linkFieldOptions.GetRelationship() {
+	case "manyMany", "oneMany":
+		schemaCreator := schema.NewLinkFieldSchemaCreator(s.dbProvider, s.db)
+		if err := schemaCreator.DropLinkFieldSchema(ctx, table.BaseID(), from.TableID(), linkOptions.LinkedTableID, linkFieldOptions); err != nil {
+			logger.Warn("类型转换后删除 Link 字段 Schema 失败",
+				logger.String("field_id", from.ID().String()),
+				logger.ErrorField(err))
+		}
+	}
+	return nil
+}
+
+// discardConvertedLinkSchema 转换为关联字段失败时删除已创建的对称字段与关联 Schema
+func (s *FieldService) discardConvertedLinkSchema(ctx context.Context, table *tableEntity.Table, field *entity.Field) {
+	if err := s.dropConvertedLinkSchema(ctx, table, field); err != nil {
+		logger.Warn("回滚类型转换时删除对称字段失败",
+			logger.String("field_id", field.ID().String()),
+			logger.ErrorField(err))
+	}
+}
+
+// scanFieldValues 按键集分页逐批读取表格中某个字段的值
+func (s *FieldService) scanFieldValues(ctx context.Context, tableID, fieldID string, fn func(records []*recordEntity.Record) error) error {
+	filter := recordRepo.RecordFilter{
+		TableID:    &tableID,
+		Projection: []string{fieldID},
+		Limit:      fieldConversionChunkSize,
+	}
+	for {
+		page, err := s.recordRepo.ListPage(ctx, filter)
+		if err != nil {
+			return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("读取记录失败: %v", err))
+		}
+		if err := fn(page.Records); err != nil {
+			return err
+		}
+		if page.NextCursor == nil || len(page.Records) == 0 {
+			return nil
+		}
+		filter.Cursor = page.NextCursor
+	}
+}
+
+// snapshotField 复制字段（选项深拷贝），用作类型转换前的原字段或预览用的目标字段
+func snapshotField(field *entity.Field) *entity.Field {
+	var options *valueobject.FieldOptions
+	if field.Options() != nil {
+		options = valueobject.NewFieldOptions()
+		if data, err := json.Marshal(field.Options()); err == nil {
+			_ = json.Unmarshal(data, options)
+		}
+	}
+	snapshot := entity.ReconstructField(
+		field.ID(), field.TableID(), field.Name(), field.Type(), field.DBFieldName(), field.DBFieldType(),
+		options, field.Order(), field.Version(), field.CreatedBy(), field.CreatedAt(), field.UpdatedAt(),
+	)
+	if field.IsPrimary() {
+		_ = snapshot.SetPrimary(true)
+	}
+	return snapshot
+}
+
+// linkOptionsOf 关联字段的关联选项，其他字段返回 nil
+func linkOptionsOf(field *entity.Field) *valueobject.LinkOptions {
+	if field.Type().String() != valueobject.TypeLink || field.Options() == nil {
+		return nil
+	}
+	return field.Options().Link
+}
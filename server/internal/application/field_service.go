@@ -13,6 +13,7 @@ import (
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/factory"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
 	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
 	tableValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/table/valueobject"
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
@@ -40,6 +41,8 @@ type FieldService struct {
 	tableRepo    tableRepo.TableRepository             // ✅ 表格仓储（获取Base ID）
 	dbProvider   database.DBProvider                   // ✅ 数据库提供者（列管理）
 	db           *gorm.DB                              // ✅ 数据库连接（用于 Link 字段 schema 创建）
+	recordRepo   recordRepo.RecordRepository           // 可选：类型转换时读取已有数据
+	linker       FieldLinkDeriver                      // 可选：转换为关联字段时写入外键
 }
 
 // FieldBroadcaster 字段变更广播器接口
@@ -187,8 +190,22 @@ func (s *FieldService) CreateField(ctx context.Context, req dto.CreateFieldReque
 
 	case "link":
 		// Link 字段需要从 options 中提取 linkedTableID, relationship 等
-		// 先使用通用方法创建字段，然后在 applyCommonFieldOptions 中处理选项
+		// 选项可以放在 options.link 中，也可以直接放在 options 中
 		field, err = s.fieldFactory.CreateFieldWithType(req.TableID, req.Name, req.Type, userID)
+		if err == nil {
+			linkOptions := req.Options
+			if nested, ok := req.Options["link"].(map[string]interface{}); ok {
+				linkOptions = nested
+			}
+			if link := s.optionsService.ExtractLinkOptionsFromOptions(linkOptions); link != nil {
+				options := field.Options()
+				if options == nil {
+					options = valueobject.NewFieldOptions()
+				}
+				options.Link = link
+				field.UpdateOptions(options)
+			}
+		}
 
 	default:
 		// ✅ 使用通用方法创建字段，保留原始类型名称（如 singleLineText, longText, email 等）
@@ -213,13 +230,31 @@ func (s *FieldService) CreateField(ctx context.Context, req dto.CreateFieldReque
 		return nil, pkgerrors.ErrInternalServer.WithDetails(fmt.Sprintf("创建字段失败: %v", err))
 	}
 
-	// 4. 设置可选属性
+	// 4. 设置可选属性（约束也可以放在 options 中传入）
+	if v := takeConstraintOption(req.Options, "unique"); v != nil {
+		req.Unique = *v
+	}
+	if v := takeConstraintOption(req.Options, "notNull"); v != nil {
+		req.NotNull = *v
+	}
+	if err := checkConstraintSupport(field, req.Unique, req.NotNull); err != nil {
+		return nil, err
+	}
+	if req.NotNull {
+		if err := s.checkNewFieldNotNull(ctx, req.TableID); err != nil {
+			return nil, err
+		}
+		field.SetNotNull(true)
+	}
 	if req.Required {
 		field.SetRequired(true)
 	}
 	if req.Unique {
 		field.SetUnique(true)
 	}
+	if err := applyValidationRulesOption(field, req.Options); err != nil {
+		return nil, err
+	}
 
 	// 5. ✨ 应用通用字段配置（defaultValue, showAs, formatting 等）
 	// 顶层 defaultValue 兼容：注入到 options 中
@@ -235,15 +270,87 @@ func (s *FieldService) CreateField(ctx context.Context, req dto.CreateFieldReque
 	wrapper := &fieldOptionsWrapper{field: field}
 	s.optionsService.ApplyCommonFieldOptions(wrapper, req.Options)
 
-	// 6. 循环依赖检测（仅对虚拟字段）
-	if isVirtualFieldType(req.Type) {
-		if err := s.dependencyService.CheckCircularDependency(ctx, req.TableID, field); err != nil {
+	if err := s.saveNewField(ctx, field, userID); err != nil {
+		return nil, err
+	}
+
+	return dto.FromFieldEntity(field), nil
+}
+
+// CreateFieldWithOptions 按完整的字段选项创建字段（用于 Base 导入、复制）
+// 选项中引用的表格、字段需已替换为目标ID；Link 字段的外键、关联表等实现细节由本方法重新生成
+func (s *FieldService) CreateFieldWithOptions(
+	ctx context.Context,
+	tableID, name, fieldType string,
+	options *valueobject.FieldOptions,
+	required, unique, notNull bool,
+	userID string,
+) (*entity.Field, error) {
+	fieldName, err := valueobject.NewFieldName(name)
+	if err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("字段名称无效: %v", err))
+	}
+	exists, err := s.fieldRepo.ExistsByName(ctx, tableID, fieldName, nil)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("检查字段名称失败: %v", err))
+	}
+	if exists {
+		return nil, pkgerrors.ErrConflict.WithMessage(fmt.Sprintf("字段名 '%s' 已存在", name))
+	}
+
+	field, err := s.fieldFactory.CreateFieldWithType(tableID, name, fieldType, userID)
+	if err != nil {
+		return nil, pkgerrors.ErrInvalidFieldType.WithDetails(map[string]interface{}{
+			"type":  fieldType,
+			"error": err.Error(),
+		})
+	}
+	if options != nil {
+		if link := options.Link; link != nil {
+			link.ForeignKeyFieldID = ""
+			link.FkHostTableName = ""
+			link.SelfKeyName = ""
+			link.ForeignKeyName = ""
+		}
+		field.UpdateOptions(options)
+	}
+	if err := checkConstraintSupport(field, unique, notNull); err != nil {
+		return nil, err
+	}
+	if notNull {
+		if err := s.checkNewFieldNotNull(ctx, tableID); err != nil {
 			return nil, err
 		}
+		field.SetNotNull(true)
+	}
+	if required {
+		field.SetRequired(true)
+	}
+	if unique {
+		field.SetUnique(true)
+	}
+
+	if err := s.saveNewField(ctx, field, userID); err != nil {
+		return nil, err
+	}
+	return field, nil
+}
+
+// saveNewField 创建字段的物理列（Link 字段还包括关联 Schema）、保存元数据并广播，
+// Link 字段需要对称字段时自动创建
+func (s *FieldService) saveNewField(ctx context.Context, field *entity.Field, userID string) error {
+	tableID := field.TableID()
+	fieldType := field.Type().String()
+
+	// 6. 循环依赖检测（仅对虚拟字段）
+	if isVirtualFieldType(fieldType) {
+		if err := s.dependencyService.CheckCircularDependency(ctx, tableID, field); err != nil {
+			return err
+		}
 	}
 
 	// 7. 计算字段order值（参考原系统逻辑：查询最大order + 1）
-	maxOrder, err := s.fieldRepo.GetMaxOrder(ctx, req.TableID)
+	maxOrder, err := s.fieldRepo.GetMaxOrder(ctx, tableID)
 	if err != nil {
 		// 如果查询失败，使用-1，这样第一个字段order为0
 		logger.Warn("获取最大order失败，使用默认值-1", logger.ErrorField(err))
@@ -258,7 +365,7 @@ func (s *FieldService) CreateField(ctx context.Context, req dto.CreateFieldReque
 	dbType := field.DBFieldType()
 	
 	// 对于 Link 字段，确保数据库类型为 JSONB
-	if req.Type == "link" || field.Type().String() == "link" {
+	if fieldType == "link" {
 		if dbType != "JSONB" {
 			logger.Error("Link 字段的数据库类型不正确，强制设置为 JSONB",
 				logger.String("field_id", field.ID().String()),
@@ -268,33 +375,48 @@ func (s *FieldService) CreateField(ctx context.Context, req dto.CreateFieldReque
 		}
 	}
 
-	if err := s.schemaService.CreatePhysicalColumn(ctx, req.TableID, dbFieldName, dbType); err != nil {
-		return nil, err
+	if err := s.schemaService.CreatePhysicalColumn(ctx, tableID, dbFieldName, dbType); err != nil {
+		return err
+	}
+
+	// 8.5 添加唯一、非空约束
+	if err := s.applyNewFieldConstraints(ctx, field); err != nil {
+		if rollbackErr := s.schemaService.DropPhysicalColumn(ctx, tableID, dbFieldName); rollbackErr != nil {
+			logger.Error("回滚删除物理表列失败", logger.ErrorField(rollbackErr))
+		}
+		return err
 	}
 
 	// 8.6 ✨ 如果是 Link 字段，创建 Link 字段的数据库 Schema
-	if req.Type == "link" && field.Options() != nil && field.Options().Link != nil {
+	if fieldType == "link" && field.Options() != nil && field.Options().Link != nil {
 		// 获取Table信息
-		table, err := s.tableRepo.GetByID(ctx, req.TableID)
+		table, err := s.tableRepo.GetByID(ctx, tableID)
 		if err != nil {
-			return nil, pkgerrors.ErrDatabaseOperation.WithDetails(
+			return pkgerrors.ErrDatabaseOperation.WithDetails(
 				fmt.Sprintf("获取Table信息失败: %v", err))
 		}
 		if table == nil {
-			return nil, pkgerrors.ErrNotFound.WithDetails("Table不存在")
+			return pkgerrors.ErrNotFound.WithDetails("Table不存在")
 		}
 
 		// 转换 Link 选项
-		linkFieldOptions, err := s.linkService.ConvertToLinkFieldOptions(ctx, req.TableID, field.Options().Link, field)
+		linkFieldOptions, err := s.linkService.ConvertToLinkFieldOptions(ctx, tableID, field.Options().Link, field)
 		if err != nil {
 			// 回滚：删除已创建的物理表列
-			if rollbackErr := s.schemaService.DropPhysicalColumn(ctx, req.TableID, dbFieldName); rollbackErr != nil {
+			if rollbackErr := s.schemaService.DropPhysicalColumn(ctx, tableID, dbFieldName); rollbackErr != nil {
 				logger.Error("回滚删除物理表列失败", logger.ErrorField(rollbackErr))
 			}
-			return nil, pkgerrors.ErrDatabaseOperation.WithDetails(
+			return pkgerrors.ErrDatabaseOperation.WithDetails(
 				fmt.Sprintf("转换 Link 字段选项失败: %v", err))
 		}
 
+		// 回写自动确定的显示字段与外键信息，随字段元数据保存
+		link := field.Options().Link
+		link.LookupFieldID = linkFieldOptions.LookupFieldID
+		link.FkHostTableName = linkFieldOptions.FkHostTableName
+		link.SelfKeyName = linkFieldOptions.SelfKeyName
+		link.ForeignKeyName = linkFieldOptions.ForeignKeyName
+
 		// 确定是否需要 order 列
 		hasOrderColumn := field.Options().Link.AllowMultiple
 
@@ -304,10 +426,10 @@ func (s *FieldService) CreateField(ctx context.Context, req dto.CreateFieldReque
 				logger.String("field_id", field.ID().String()),
 				logger.ErrorField(err))
 			// 回滚：删除已创建的物理表列
-			if rollbackErr := s.schemaService.DropPhysicalColumn(ctx, req.TableID, dbFieldName); rollbackErr != nil {
+			if rollbackErr := s.schemaService.DropPhysicalColumn(ctx, tableID, dbFieldName); rollbackErr != nil {
 				logger.Error("回滚删除物理表列失败", logger.ErrorField(rollbackErr))
 			}
-			return nil, pkgerrors.ErrDatabaseOperation.WithDetails(
+			return pkgerrors.ErrDatabaseOperation.WithDetails(
 				fmt.Sprintf("创建 Link 字段 Schema 失败: %v", err))
 		}
 		logger.Info("✅ Link 字段 Schema 创建成功",
@@ -316,10 +438,10 @@ func (s *FieldService) CreateField(ctx context.Context, req dto.CreateFieldReque
 
 	// 9. 保存字段元数据
 	// ✨ 调试：记录保存前的 Link 字段 Options（特别是 FkHostTableName）
-	if req.Type == "link" && field.Options() != nil && field.Options().Link != nil {
+	if fieldType == "link" && field.Options() != nil && field.Options().Link != nil {
 		logger.Info("CreateField 保存字段前检查 Link Options",
 			logger.String("field_id", field.ID().String()),
-			logger.String("table_id", req.TableID),
+			logger.String("table_id", tableID),
 			logger.String("fk_host_table_name", field.Options().Link.FkHostTableName),
 			logger.String("self_key_name", field.Options().Link.SelfKeyName),
 			logger.String("foreign_key_name", field.Options().Link.ForeignKeyName),
@@ -327,15 +449,15 @@ func (s *FieldService) CreateField(ctx context.Context, req dto.CreateFieldReque
 	}
 	logger.Info("准备保存字段元数据",
 		logger.String("field_id", field.ID().String()),
-		logger.String("table_id", req.TableID),
-		logger.String("name", req.Name),
-		logger.String("type", req.Type),
+		logger.String("table_id", tableID),
+		logger.String("name", field.Name().String()),
+		logger.String("type", fieldType),
 	)
 
 	if err := s.fieldRepo.Save(ctx, field); err != nil {
 		// ❌ 回滚：删除已创建的物理表列
 		if s.tableRepo != nil && s.dbProvider != nil {
-			table, _ := s.tableRepo.GetByID(ctx, req.TableID)
+			table, _ := s.tableRepo.GetByID(ctx, tableID)
 			if table != nil {
 				dbFieldName := field.DBFieldName().String()
 				if rollbackErr := s.dbProvider.DropColumn(ctx, table.BaseID(), table.ID().String(), dbFieldName); rollbackErr != nil {
@@ -349,37 +471,44 @@ func (s *FieldService) CreateField(ctx context.Context, req dto.CreateFieldReque
 
 		logger.Error("保存字段元数据失败",
 			logger.String("field_id", field.ID().String()),
-			logger.String("table_id", req.TableID),
+			logger.String("table_id", tableID),
 			logger.ErrorField(err),
 		)
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存字段失败: %v", err))
+		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存字段失败: %v", err))
 	}
 
 	logger.Info("字段创建成功",
 		logger.String("field_id", field.ID().String()),
-		logger.String("table_id", req.TableID),
-		logger.String("name", req.Name),
-		logger.String("type", req.Type),
+		logger.String("table_id", tableID),
+		logger.String("name", field.Name().String()),
+		logger.String("type", fieldType),
 		logger.Float64("order", nextOrder),
 	)
 
+	// 10. 创建搜索索引（索引缺失只影响搜索性能，不影响字段创建）
+	if fieldService.HasSearchIndexes(field) {
+		if table, err := s.tableRepo.GetByID(ctx, tableID); err == nil && table != nil {
+			s.schemaService.CreateSearchIndexes(ctx, table, field)
+		}
+	}
+
 	// 9. ✨ 更新依赖图（如果是虚拟字段）
 	if s.depGraphRepo != nil && field.IsComputed() {
-		if err := s.depGraphRepo.InvalidateCache(ctx, req.TableID); err != nil {
+		if err := s.depGraphRepo.InvalidateCache(ctx, tableID); err != nil {
 			logger.Warn("清除依赖图缓存失败（不影响字段创建）",
-				logger.String("table_id", req.TableID),
+				logger.String("table_id", tableID),
 				logger.ErrorField(err),
 			)
 		} else {
 			logger.Info("依赖图缓存已清除 ✨",
-				logger.String("table_id", req.TableID),
+				logger.String("table_id", tableID),
 			)
 		}
 	}
 
 	// 10. ✨ 实时推送字段创建事件
 	if s.broadcaster != nil {
-		s.broadcaster.BroadcastFieldCreate(req.TableID, field)
+		s.broadcaster.BroadcastFieldCreate(tableID, field)
 		logger.Info("字段创建事件已广播 ✨",
 			logger.String("field_id", field.ID().String()),
 		)
@@ -387,23 +516,26 @@ func (s *FieldService) CreateField(ctx context.Context, req dto.CreateFieldReque
 
 	// 11. ✨ 如果是 Link 字段且 IsSymmetric=true，自动创建对称字段
 	// 委托给 LinkService
-	if req.Type == "link" && field.Options() != nil && field.Options().Link != nil {
+	if fieldType == "link" && field.Options() != nil && field.Options().Link != nil {
 		linkOptions := field.Options().Link
 		if linkOptions.IsSymmetric && linkOptions.SymmetricFieldID == "" {
-			if _, err := s.linkService.CreateSymmetricField(ctx, field, linkOptions, userID); err != nil {
+			symmetricField, err := s.linkService.CreateSymmetricField(ctx, field, linkOptions, userID)
+			if err != nil {
 				logger.Error("自动创建对称字段失败",
 					logger.String("field_id", field.ID().String()),
-					logger.String("table_id", req.TableID),
+					logger.String("table_id", tableID),
 					logger.ErrorField(err))
 				// ✅ 优化：确保主字段的 SymmetricFieldID 保持为空（如果对称字段创建失败）
 				// 注意：对称字段创建失败不影响主字段的创建，只记录错误
 				// 因为主字段已经保存成功，回滚成本较高
 				// 主字段的 SymmetricFieldID 会在对称字段创建成功后才设置
+			} else if symmetricField != nil && s.broadcaster != nil {
+				s.broadcaster.BroadcastFieldCreate(symmetricField.TableID(), symmetricField)
 			}
 		}
 	}
 
-	return dto.FromFieldEntity(field), nil
+	return nil
 }
 
 // 注意：extractChoicesFromOptions、extractExpressionFromOptions 等方法已迁移到 FieldOptionsService
@@ -465,6 +597,28 @@ func (s *FieldService) UpdateField(ctx context.Context, fieldID string, req dto.
 		logger.String("field_name", field.Name().String()),
 		logger.String("table_id", field.TableID()))
 
+	// 1.0 约束也可以放在 options 中传入
+	if v := takeConstraintOption(req.Options, "unique"); v != nil && req.Unique == nil {
+		req.Unique = v
+	}
+	if v := takeConstraintOption(req.Options, "notNull"); v != nil && req.NotNull == nil {
+		req.NotNull = v
+	}
+
+	// 1.1 变更字段类型：先转换已有数据，其余更新在新类型上继续应用
+	if req.Type != nil && *req.Type != "" && *req.Type != field.Type().String() {
+		if err := s.convertFieldType(ctx, field, *req.Type, req.Options); err != nil {
+			return nil, err
+		}
+		// 选项列表已在转换中应用（含自动新增的选项），不再按请求覆盖
+		delete(req.Options, "choices")
+	}
+
+	// 1.2 字段校验规则（按变更后的类型检查是否适用）
+	if err := applyValidationRulesOption(field, req.Options); err != nil {
+		return nil, err
+	}
+
 	// 2. 更新名称
 	if req.Name != nil && *req.Name != "" {
 		fieldName, err := valueobject.NewFieldName(*req.Name)
@@ -611,12 +765,21 @@ func (s *FieldService) UpdateField(ctx context.Context, fieldID string, req dto.
 		}
 	}
 
-	// 5. 更新约束
+	// 5. 更新约束：开启唯一、非空时先校验已有数据，再修改物理列约束
 	if req.Required != nil {
 		field.SetRequired(*req.Required)
 	}
-	if req.Unique != nil {
-		field.SetUnique(*req.Unique)
+	if req.Unique != nil || req.NotNull != nil {
+		unique, notNull := field.IsUnique(), field.NotNull()
+		if req.Unique != nil {
+			unique = *req.Unique
+		}
+		if req.NotNull != nil {
+			notNull = *req.NotNull
+		}
+		if err := s.changeFieldConstraints(ctx, field, unique, notNull); err != nil {
+			return nil, err
+		}
 	}
 
 	// 6. 循环依赖检测（如果是虚拟字段且Options被更新）
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	fieldService "github.com/easyspace-ai/luckdb/server/internal/application/field"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/calculation/dependency"
 	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/factory"
@@ -257,6 +258,11 @@ func (m *MockDBProvider) DropPhysicalTable(ctx context.Context, baseID, tableID
 	return args.Error(0)
 }
 
+func (m *MockDBProvider) MovePhysicalTable(ctx context.Context, fromSchema, toSchema, tableID string) error {
+	args := m.Called(ctx, fromSchema, toSchema, tableID)
+	return args.Error(0)
+}
+
 func (m *MockDBProvider) AddCheckConstraint(ctx context.Context, schemaName, tableName, constraintName, checkExpression string) error {
 	args := m.Called(ctx, schemaName, tableName, constraintName, checkExpression)
 	return args.Error(0)
@@ -374,6 +380,32 @@ func createDependencyGraphRepository() *dependency.DependencyGraphRepository {
 	return dependency.NewDependencyGraphRepository(mockCache, builder, time.Hour)
 }
 
+// newFieldServiceForTest 按容器的方式组装字段服务及其专门服务（测试辅助函数）
+func newFieldServiceForTest(
+	fieldRepo repository.FieldRepository,
+	depGraphRepo *dependency.DependencyGraphRepository,
+	broadcaster FieldBroadcaster,
+	tableRepo *MockTableRepository,
+	dbProvider database.DBProvider,
+	db *gorm.DB,
+) *FieldService {
+	fieldFactory := factory.NewFieldFactory()
+	return NewFieldService(
+		fieldService.NewFieldCRUDService(fieldRepo, fieldFactory),
+		fieldService.NewFieldOptionsService(),
+		fieldService.NewFieldDependencyService(fieldRepo, depGraphRepo),
+		fieldService.NewFieldSchemaService(tableRepo, dbProvider, db),
+		fieldService.NewFieldLinkService(fieldRepo, tableRepo, fieldFactory, dbProvider, db),
+		fieldFactory,
+		fieldRepo,
+		depGraphRepo,
+		broadcaster,
+		tableRepo,
+		dbProvider,
+		db,
+	)
+}
+
 // createTableWithID 创建带ID的表实体（测试辅助函数）
 func createTableWithID(baseID, tableID, name, userID string) (*tableEntity.Table, error) {
 	tableName, err := tableValueObject.NewTableName(name)
@@ -443,7 +475,7 @@ func TestFieldService_CreateLinkField_WithAutoLookupFieldID(t *testing.T) {
 	depGraphRepo := createDependencyGraphRepository()
 
 	// 创建字段服务
-	fieldService := NewFieldService(
+	fieldService := newFieldServiceForTest(
 		mockFieldRepo,
 		depGraphRepo,
 		mockBroadcaster,
@@ -495,6 +527,7 @@ func TestFieldService_CreateLinkField_WithAutoLookupFieldID(t *testing.T) {
 	mockDBProvider.On("GenerateTableName", baseID, foreignTableID).Return("base_001_table_002")
 	mockDBProvider.On("DriverName").Return("sqlite")
 	mockDBProvider.On("AddColumn", ctx, baseID, mock.Anything, mock.Anything).Return(nil)
+	mockBroadcaster.On("BroadcastFieldCreate", currentTableID, mock.Anything).Return()
 
 	// 创建关联字段请求（不提供 lookupFieldID，应该自动获取）
 	req := dto.CreateFieldRequest{
@@ -551,7 +584,7 @@ func TestFieldService_CreateLinkField_WithProvidedLookupFieldID(t *testing.T) {
 	depGraphRepo := createDependencyGraphRepository()
 
 	// 创建字段服务
-	fieldService := NewFieldService(
+	fieldService := newFieldServiceForTest(
 		mockFieldRepo,
 		depGraphRepo,
 		mockBroadcaster,
@@ -589,6 +622,7 @@ func TestFieldService_CreateLinkField_WithProvidedLookupFieldID(t *testing.T) {
 	mockDBProvider.On("GenerateTableName", baseID, foreignTableID).Return("base_001_table_002")
 	mockDBProvider.On("DriverName").Return("sqlite")
 	mockDBProvider.On("AddColumn", ctx, baseID, mock.Anything, mock.Anything).Return(nil)
+	mockBroadcaster.On("BroadcastFieldCreate", currentTableID, mock.Anything).Return()
 
 	// 创建关联字段请求（提供 lookupFieldID）
 	req := dto.CreateFieldRequest{
@@ -645,7 +679,7 @@ func TestFieldService_GetPrimaryFieldID(t *testing.T) {
 	depGraphRepo := createDependencyGraphRepository()
 
 	// 创建字段服务
-	fieldService := NewFieldService(
+	fieldService := newFieldServiceForTest(
 		mockFieldRepo,
 		depGraphRepo,
 		mockBroadcaster,
@@ -698,7 +732,7 @@ func TestFieldService_GetPrimaryFieldID_NoFields(t *testing.T) {
 	depGraphRepo := createDependencyGraphRepository()
 
 	// 创建字段服务
-	fieldService := NewFieldService(
+	fieldService := newFieldServiceForTest(
 		mockFieldRepo,
 		depGraphRepo,
 		mockBroadcaster,
@@ -742,7 +776,7 @@ func TestFieldService_CreateLinkField_WithSymmetricField(t *testing.T) {
 	depGraphRepo := createDependencyGraphRepository()
 
 	// 创建字段服务
-	fieldService := NewFieldService(
+	fieldService := newFieldServiceForTest(
 		mockFieldRepo,
 		depGraphRepo,
 		mockBroadcaster,
@@ -777,10 +811,7 @@ func TestFieldService_CreateLinkField_WithSymmetricField(t *testing.T) {
 	mockTableRepo.On("GetByID", ctx, currentTableID).Return(currentTable, nil).Times(2) // 创建字段和创建对称字段
 	mockTableRepo.On("GetByID", ctx, foreignTableID).Return(foreignTable, nil).Times(2) // 创建字段和创建对称字段
 
-	// 模拟获取关联表的字段列表（用于自动获取 lookupFieldID）
-	mockFieldRepo.On("FindByTableID", ctx, foreignTableID).Return([]*fieldEntity.Field{
-		lookupField,
-	}, nil)
+	// 请求中已提供 lookupFieldID，不需要查询关联表的字段列表
 
 	// 模拟检查对称字段名称是否存在
 	mockFieldRepo.On("ExistsByName", ctx, foreignTableID, mock.Anything, nil).Return(false, nil).Once()
@@ -857,7 +888,7 @@ func TestFieldService_DeleteLinkField_WithSymmetricField(t *testing.T) {
 	depGraphRepo := createDependencyGraphRepository()
 
 	// 创建字段服务
-	fieldService := NewFieldService(
+	fieldService := newFieldServiceForTest(
 		mockFieldRepo,
 		depGraphRepo,
 		mockBroadcaster,
@@ -420,8 +420,9 @@ func (s *MigrateService) runAutoMigrate(db *gorm.DB) error {
 		&models.UserLastVisit{},
 		// &models.Template{},          // TODO: Template模型待实现
 		// &models.TemplateCategory{},  // TODO: TemplateCategory模型待实现
-		// &models.Task{},              // TODO: Task模型待实现
-		// &models.TaskRun{},           // TODO: TaskRun模型待实现
+		&models.Task{},
+		&models.TaskRun{},
+		&models.RecordRule{},
 		// &models.TaskReference{},     // TODO: TaskReference模型待实现
 		&models.PinResource{},
 		&models.Setting{},
@@ -496,6 +497,11 @@ func (s *MigrateService) runAutoMigrate(db *gorm.DB) error {
 		// 虚拟字段支持
 		&models.FieldDependency{},
 		&models.VirtualFieldCache{},
+
+		// 全局搜索
+		&models.SearchIndex{},
+		&models.SearchSuggestion{},
+		&models.SearchStats{},
 	}
 
 	s.logger.Info("开始迁移模型", zap.Int("model_count", len(allModels)))
@@ -547,6 +553,7 @@ func (s *MigrateService) addSupplementaryIndexes(db *gorm.DB) error {
 		"CREATE INDEX IF NOT EXISTS idx_record_trash_table_record ON record_trash(table_id, record_id)",
 		"CREATE INDEX IF NOT EXISTS idx_attachments_table_field ON attachments_table(table_id, field_id)",
 		"CREATE INDEX IF NOT EXISTS idx_attachments_table_record_field ON attachments_table(record_id, table_id, field_id)",
+		"CREATE INDEX IF NOT EXISTS idx_search_indexes_tsv ON search_indexes USING GIN (to_tsvector('simple', COALESCE(title, '') || ' ' || COALESCE(content, '')))",
 	}
 
 	s.logger.Info("创建补充索引", zap.Int("index_count", len(indexes)))
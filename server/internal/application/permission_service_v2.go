@@ -176,6 +176,17 @@ func (s *PermissionServiceV2) CanDeleteTable(ctx context.Context, userID, tableI
 	return s.Can(ctx, userID, table.BaseID(), entity.ResourceTypeBase, permission.ActionTableDelete)
 }
 
+// CanBypassRecordRules 检查用户能否跳过表级记录校验规则写入（Base 所有者）
+func (s *PermissionServiceV2) CanBypassRecordRules(ctx context.Context, userID, tableID string) bool {
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil || table == nil {
+		return false
+	}
+
+	role, err := s.GetUserRole(ctx, userID, table.BaseID())
+	return err == nil && role == entity.RoleOwner
+}
+
 // ==================== Record权限 ====================
 
 // CanAccessRecord 检查用户是否可以访问Record
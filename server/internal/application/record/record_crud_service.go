@@ -3,6 +3,7 @@ package record
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
 	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
@@ -10,6 +11,7 @@ import (
 	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
 	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
 	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
 )
 
 // RecordCRUDService 记录CRUD服务
@@ -32,6 +34,11 @@ func NewRecordCRUDService(
 
 // CreateRecord 创建记录实体并保存（不含验证、计算、广播）
 func (s *RecordCRUDService) CreateRecord(ctx context.Context, tableID string, recordData map[string]interface{}, userID string) (*entity.Record, error) {
+	return s.CreateRecordWithID(ctx, tableID, "", recordData, userID)
+}
+
+// CreateRecordWithID 使用指定记录ID创建记录（recordID 为空时自动生成），记录ID已存在时返回 RECORD_EXISTS
+func (s *RecordCRUDService) CreateRecordWithID(ctx context.Context, tableID, recordID string, recordData map[string]interface{}, userID string) (*entity.Record, error) {
 	// 检查表是否存在
 	table, err := s.tableRepo.GetByID(ctx, tableID)
 	if err != nil {
@@ -49,14 +56,34 @@ func (s *RecordCRUDService) CreateRecord(ctx context.Context, tableID string, re
 		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("记录数据无效: %v", err))
 	}
 
+	// 指定记录ID时检查格式与是否已存在（保存时已存在的记录会被更新）
+	if recordID != "" {
+		if !strings.HasPrefix(recordID, utils.RecordIDPrefix) || len(recordID) > 30 {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("无效的记录ID: %s", recordID))
+		}
+		existing, err := s.recordRepo.FindByTableAndID(ctx, tableID, valueobject.NewRecordID(recordID))
+		if err != nil {
+			return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找记录失败: %v", err))
+		}
+		if existing != nil {
+			return nil, pkgerrors.ErrRecordExists.WithDetails(map[string]interface{}{
+				"record_id": recordID,
+			})
+		}
+	}
+
 	// 创建记录实体
-	record, err := entity.NewRecord(tableID, data, userID)
+	record, err := entity.NewRecordWithID(valueobject.NewRecordID(recordID), tableID, data, userID)
 	if err != nil {
 		return nil, pkgerrors.ErrInternalServer.WithDetails(fmt.Sprintf("创建记录实体失败: %v", err))
 	}
 
 	// 保存记录
 	if err := s.recordRepo.Save(ctx, record); err != nil {
+		// 约束错误等应用错误保留原错误码
+		if appErr, ok := pkgerrors.IsAppError(err); ok {
+			return nil, appErr
+		}
 		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存记录失败: %v", err))
 	}
 
@@ -108,6 +135,10 @@ func (s *RecordCRUDService) UpdateRecord(ctx context.Context, tableID, recordID
 
 	// 保存记录
 	if err := s.recordRepo.Save(ctx, record); err != nil {
+		// 约束错误等应用错误保留原错误码
+		if appErr, ok := pkgerrors.IsAppError(err); ok {
+			return nil, appErr
+		}
 		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存记录失败: %v", err))
 	}
 
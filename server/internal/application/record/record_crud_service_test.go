@@ -87,6 +87,22 @@ func (m *MockRecordRepository) List(ctx context.Context, filter recordRepo.Recor
 	return args.Get(0).([]*entity.Record), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockRecordRepository) ListPage(ctx context.Context, filter recordRepo.RecordFilter) (*recordRepo.RecordPage, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*recordRepo.RecordPage), args.Error(1)
+}
+
+func (m *MockRecordRepository) GroupBy(ctx context.Context, query recordRepo.RecordGroupQuery) (*recordRepo.RecordGroupResult, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*recordRepo.RecordGroupResult), args.Error(1)
+}
+
 func (m *MockRecordRepository) BatchSave(ctx context.Context, records []*entity.Record) error {
 	args := m.Called(ctx, records)
 	return args.Error(0)
@@ -78,6 +78,22 @@ func (m *MockRecordRepositoryForLink) List(ctx context.Context, filter recordRep
 	return args.Get(0).([]*recordEntity.Record), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockRecordRepositoryForLink) ListPage(ctx context.Context, filter recordRepo.RecordFilter) (*recordRepo.RecordPage, error) {
+	args := m.Called(ctx, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*recordRepo.RecordPage), args.Error(1)
+}
+
+func (m *MockRecordRepositoryForLink) GroupBy(ctx context.Context, query recordRepo.RecordGroupQuery) (*recordRepo.RecordGroupResult, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*recordRepo.RecordGroupResult), args.Error(1)
+}
+
 func (m *MockRecordRepositoryForLink) BatchSave(ctx context.Context, records []*recordEntity.Record) error {
 	args := m.Called(ctx, records)
 	return args.Error(0)
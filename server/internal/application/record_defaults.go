@@ -0,0 +1,90 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/validation"
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// applyFieldDefaults 为创建记录时省略的字段填充默认值，返回填充了默认值的字段ID
+//
+// 只处理请求中完全省略的字段（按字段ID或名称），显式传 null 表示留空，不填充默认值。
+// 用户字段的 "me" 取创建记录的用户；默认用户不存在时跳过该字段。
+// 请求中的值在类型转换时已按字段校验规则检查，填充的默认值在这里检查（如默认日期早于最小日期）
+func (s *RecordService) applyFieldDefaults(ctx context.Context, tableID string, data map[string]interface{}, userID string) ([]string, error) {
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取字段列表失败: %v", err))
+	}
+
+	now := time.Now()
+	applied := make([]string, 0)
+	defaults := make(map[string]interface{})
+	for _, field := range fields {
+		if field.IsComputed() || field.IsVirtual() {
+			continue
+		}
+		fieldID := field.ID().String()
+		if _, ok := data[fieldID]; ok {
+			continue
+		}
+		if _, ok := data[field.Name().String()]; ok {
+			continue
+		}
+
+		var value interface{}
+		var ok bool
+		if field.Type().String() == fieldValueObject.TypeUser {
+			value, ok = s.userDefaultValue(ctx, field, userID)
+		} else {
+			value, ok = validation.DefaultCellValue(field, now)
+		}
+		if !ok {
+			continue
+		}
+		data[fieldID] = value
+		defaults[fieldID] = value
+		applied = append(applied, fieldID)
+	}
+
+	if err := checkFieldRules(fields, defaults); err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// userDefaultValue 用户字段默认值转为用户对象 {id, title, email}
+func (s *RecordService) userDefaultValue(ctx context.Context, field *fieldEntity.Field, userID string) (interface{}, bool) {
+	keys := validation.UserDefaultKeys(field, userID)
+	if len(keys) == 0 || s.typecastService == nil {
+		return nil, false
+	}
+	value, err := s.typecastService.typecastUsers(ctx, field, keys)
+	if err != nil || value == nil {
+		logger.Warn("用户字段默认值无法解析，跳过",
+			logger.String("field_id", field.ID().String()),
+			logger.Strings("users", keys),
+			logger.ErrorField(err))
+		return nil, false
+	}
+	return value, true
+}
+
+// recordDefaultHistory 将创建记录时填充的默认值写入记录历史
+func (s *RecordService) recordDefaultHistory(record *entity.Record, fieldIDs []string, userID string) {
+	if s.historyService == nil || len(fieldIDs) == 0 {
+		return
+	}
+	if err := s.historyService.RecordUpdate(context.Background(), record, fieldIDs, nil, record.Data().ToMap(), userID); err != nil {
+		logger.Warn("写入默认值历史失败",
+			logger.String("record_id", record.ID().String()),
+			logger.ErrorField(err))
+	}
+}
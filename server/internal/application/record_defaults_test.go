@@ -0,0 +1,48 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordService_ApplyFieldDefaults(t *testing.T) {
+	ctx := context.Background()
+	defaultAmount := 5.0
+	amount, err := createFieldWithID("tbl_1", "fld_amount", "Amount", fieldValueObject.TypeNumber, "usr_1")
+	require.NoError(t, err)
+	require.NoError(t, amount.UpdateOptions(&fieldValueObject.FieldOptions{
+		Number:     &fieldValueObject.NumberOptions{DefaultValue: &defaultAmount},
+		Validation: &fieldValueObject.ValidationRules{Max: &fieldValueObject.NumberRule{Value: 3}},
+	}))
+
+	fieldRepo := new(MockFieldRepository)
+	fieldRepo.On("FindByTableID", ctx, "tbl_1").Return([]*fieldEntity.Field{amount}, nil)
+	s := &RecordService{fieldRepo: fieldRepo}
+
+	t.Run("填充的默认值违反校验规则", func(t *testing.T) {
+		data := map[string]interface{}{}
+		_, err := s.applyFieldDefaults(ctx, "tbl_1", data, "usr_1")
+		appErr, ok := pkgerrors.IsAppError(err)
+		require.True(t, ok)
+		assert.Equal(t, pkgerrors.ErrFieldRuleViolation.Code, appErr.Code)
+
+		ruleErrors, ok := fieldRuleErrors(err)
+		require.True(t, ok)
+		require.Len(t, ruleErrors, 1)
+		assert.Equal(t, "fld_amount", ruleErrors[0]["field_id"])
+	})
+
+	t.Run("请求中提供的值不填充默认值", func(t *testing.T) {
+		data := map[string]interface{}{"Amount": 2.0}
+		applied, err := s.applyFieldDefaults(ctx, "tbl_1", data, "usr_1")
+		require.NoError(t, err)
+		assert.Empty(t, applied)
+		assert.Equal(t, map[string]interface{}{"Amount": 2.0}, data)
+	})
+}
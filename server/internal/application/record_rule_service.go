@@ -0,0 +1,326 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/recordrule"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	userRepo "github.com/easyspace-ai/luckdb/server/internal/domain/user/repository"
+	userValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/user/valueobject"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// RecordRuleAuthorizer 记录校验规则的权限检查（由 PermissionServiceV2 实现）
+type RecordRuleAuthorizer interface {
+	// CanManageTableSchema 管理规则需要表格结构的管理权限
+	CanManageTableSchema(ctx context.Context, userID, tableID string) bool
+	// CanBypassRecordRules 跳过规则写入需要表格所在 Base 的所有者角色
+	CanBypassRecordRules(ctx context.Context, userID, tableID string) bool
+}
+
+// RecordRuleService 表级记录校验规则服务
+//
+// 规则用公式语言编写，记录创建、更新在提交前对写入后的整条记录求值，
+// 任一启用的规则结果为假时拒绝写入并返回规则的提示。
+// 系统管理员与 Base 所有者可以在写入请求中设置 skipRecordRules 跳过规则，用于批量迁移历史数据
+type RecordRuleService struct {
+	ruleRepo   recordrule.Repository
+	tableRepo  tableRepo.TableRepository
+	fieldRepo  repository.FieldRepository
+	userRepo   userRepo.UserRepository
+	authorizer RecordRuleAuthorizer // 权限检查（可选）
+}
+
+// NewRecordRuleService 创建记录校验规则服务
+func NewRecordRuleService(
+	ruleRepo recordrule.Repository,
+	tableRepo tableRepo.TableRepository,
+	fieldRepo repository.FieldRepository,
+	userRepo userRepo.UserRepository,
+) *RecordRuleService {
+	return &RecordRuleService{
+		ruleRepo:  ruleRepo,
+		tableRepo: tableRepo,
+		fieldRepo: fieldRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// SetAuthorizer 设置规则管理与跳过规则的权限检查
+func (s *RecordRuleService) SetAuthorizer(authorizer RecordRuleAuthorizer) {
+	s.authorizer = authorizer
+}
+
+// ListRules 获取表格的记录校验规则
+func (s *RecordRuleService) ListRules(ctx context.Context, tableID string) ([]*dto.RecordRuleResponse, error) {
+	if err := s.ensureTable(ctx, tableID); err != nil {
+		return nil, err
+	}
+	rules, err := s.ruleRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取校验规则失败: %v", err))
+	}
+	result := make([]*dto.RecordRuleResponse, 0, len(rules))
+	for _, rule := range rules {
+		result = append(result, dto.FromRecordRule(rule))
+	}
+	return result, nil
+}
+
+// CreateRule 创建记录校验规则，保存前检查表达式语法与引用的字段
+func (s *RecordRuleService) CreateRule(ctx context.Context, tableID string, req dto.CreateRecordRuleRequest, userID string) (*dto.RecordRuleResponse, error) {
+	if err := s.ensureTable(ctx, tableID); err != nil {
+		return nil, err
+	}
+	if err := s.authorizeManage(ctx, tableID, userID); err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("规则名称不能为空")
+	}
+	if err := s.validateExpression(ctx, tableID, req.Expression); err != nil {
+		return nil, err
+	}
+
+	rule := &recordrule.Rule{
+		ID:         utils.GenerateIDWithPrefix(utils.RecordRuleIDPrefix),
+		TableID:    tableID,
+		Name:       name,
+		Expression: strings.TrimSpace(req.Expression),
+		Message:    strings.TrimSpace(req.Message),
+		Enabled:    req.Enabled == nil || *req.Enabled,
+		CreatedBy:  userID,
+	}
+	if err := s.ruleRepo.Create(ctx, rule); err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("创建校验规则失败: %v", err))
+	}
+	return dto.FromRecordRule(rule), nil
+}
+
+// UpdateRule 更新记录校验规则（只更新请求中传入的字段）
+func (s *RecordRuleService) UpdateRule(ctx context.Context, tableID, ruleID string, req dto.UpdateRecordRuleRequest, userID string) (*dto.RecordRuleResponse, error) {
+	rule, err := s.findRule(ctx, tableID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeManage(ctx, tableID, userID); err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails("规则名称不能为空")
+		}
+		rule.Name = name
+	}
+	if req.Expression != nil {
+		if err := s.validateExpression(ctx, tableID, *req.Expression); err != nil {
+			return nil, err
+		}
+		rule.Expression = strings.TrimSpace(*req.Expression)
+	}
+	if req.Message != nil {
+		rule.Message = strings.TrimSpace(*req.Message)
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+	rule.LastModifiedBy = userID
+
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("更新校验规则失败: %v", err))
+	}
+	rule.LastModifiedTime = time.Now()
+	return dto.FromRecordRule(rule), nil
+}
+
+// DeleteRule 删除记录校验规则
+func (s *RecordRuleService) DeleteRule(ctx context.Context, tableID, ruleID, userID string) error {
+	if _, err := s.findRule(ctx, tableID, ruleID); err != nil {
+		return err
+	}
+	if err := s.authorizeManage(ctx, tableID, userID); err != nil {
+		return err
+	}
+	if err := s.ruleRepo.Delete(ctx, ruleID); err != nil {
+		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("删除校验规则失败: %v", err))
+	}
+	return nil
+}
+
+// AuthorizeSkip 检查用户能否跳过记录校验规则：系统管理员或表格所在 Base 的所有者
+func (s *RecordRuleService) AuthorizeSkip(ctx context.Context, tableID, userID string) error {
+	if userID != "" && s.userRepo != nil {
+		if user, err := s.userRepo.FindByID(ctx, userValueObject.NewUserID(userID)); err == nil && user != nil && user.IsAdmin() {
+			return nil
+		}
+	}
+	if userID != "" && s.authorizer != nil && s.authorizer.CanBypassRecordRules(ctx, userID, tableID) {
+		return nil
+	}
+	return pkgerrors.ErrForbidden.WithDetails("只有管理员可以跳过记录校验规则")
+}
+
+// RecordRuleChecker 预先加载了表格启用规则与字段的校验器，批量写入时复用
+//
+// nil 表示表格没有启用的规则，Check 直接通过
+type RecordRuleChecker struct {
+	rules  []*recordrule.Rule
+	fields []*fieldEntity.Field
+}
+
+// NewChecker 加载表格启用的规则与字段，没有启用的规则时返回 nil
+func (s *RecordRuleService) NewChecker(ctx context.Context, tableID string) (*RecordRuleChecker, error) {
+	rules, err := s.ruleRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取校验规则失败: %v", err))
+	}
+	enabled := make([]*recordrule.Rule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Enabled {
+			enabled = append(enabled, rule)
+		}
+	}
+	if len(enabled) == 0 {
+		return nil, nil
+	}
+
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取字段列表失败: %v", err))
+	}
+	return &RecordRuleChecker{rules: enabled, fields: fields}, nil
+}
+
+// Check 对写入后的整条记录数据求值所有启用的规则
+//
+// data 以字段ID（或字段名）为键；不通过时返回 RECORD_RULE_VIOLATION，错误消息为第一条不通过规则的提示，
+// 详情 errors 中每项包含 rule_id、rule_name、message，求值出错的规则同样视为不通过并附带 error
+func (c *RecordRuleChecker) Check(data map[string]interface{}) error {
+	if c == nil {
+		return nil
+	}
+	values := recordrule.Values(c.fields, data)
+
+	violations := make([]map[string]interface{}, 0)
+	for _, rule := range c.rules {
+		passed, evalErr := recordrule.Evaluate(rule.Expression, values)
+		if passed && evalErr == nil {
+			continue
+		}
+		violation := map[string]interface{}{
+			"rule_id":   rule.ID,
+			"rule_name": rule.Name,
+			"message":   rule.ViolationMessage(),
+		}
+		if evalErr != nil {
+			violation["error"] = evalErr.Error()
+		}
+		violations = append(violations, violation)
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return pkgerrors.New(
+		pkgerrors.ErrRecordRuleViolation.Code,
+		violations[0]["message"].(string),
+		pkgerrors.ErrRecordRuleViolation.HTTPStatus,
+	).WithDetails(map[string]interface{}{
+		"errors": violations,
+	})
+}
+
+// RecordCheck 返回表格的记录校验函数（供数据导入等不经过 RecordService 的写入路径使用）
+func (s *RecordRuleService) RecordCheck(ctx context.Context, tableID string) (func(data map[string]interface{}) error, error) {
+	checker, err := s.NewChecker(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+	return checker.Check, nil
+}
+
+// recordRuleErrors 从 RECORD_RULE_VIOLATION 错误中取出逐规则详情
+func recordRuleErrors(err error) ([]map[string]interface{}, bool) {
+	appErr, ok := pkgerrors.IsAppError(err)
+	if !ok || appErr.Code != pkgerrors.ErrRecordRuleViolation.Code {
+		return nil, false
+	}
+	details, _ := appErr.Details.(map[string]interface{})
+	list, ok := details["errors"].([]map[string]interface{})
+	return list, ok
+}
+
+// validateExpression 检查表达式语法，引用的字段必须是表格中的字段名或字段ID
+func (s *RecordRuleService) validateExpression(ctx context.Context, tableID, expression string) error {
+	refs, err := recordrule.CheckExpression(expression)
+	if err != nil {
+		return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("校验规则表达式无效: %v", err))
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取字段列表失败: %v", err))
+	}
+	known := make(map[string]bool, len(fields)*2)
+	for _, field := range fields {
+		known[field.ID().String()] = true
+		known[field.Name().String()] = true
+	}
+	missing := make([]string, 0)
+	for _, ref := range refs {
+		if !known[ref] {
+			missing = append(missing, ref)
+		}
+	}
+	if len(missing) > 0 {
+		return pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("校验规则引用了不存在的字段: %s", strings.Join(missing, ", ")))
+	}
+	return nil
+}
+
+// authorizeManage 检查用户能否管理表格的校验规则
+func (s *RecordRuleService) authorizeManage(ctx context.Context, tableID, userID string) error {
+	if s.authorizer != nil && !s.authorizer.CanManageTableSchema(ctx, userID, tableID) {
+		return pkgerrors.ErrForbidden.WithDetails("没有管理该表格校验规则的权限")
+	}
+	return nil
+}
+
+// findRule 获取属于表格的规则
+func (s *RecordRuleService) findRule(ctx context.Context, tableID, ruleID string) (*recordrule.Rule, error) {
+	rule, err := s.ruleRepo.FindByID(ctx, ruleID)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找校验规则失败: %v", err))
+	}
+	if rule == nil || rule.TableID != tableID {
+		return nil, pkgerrors.ErrNotFound.WithDetails("校验规则不存在")
+	}
+	return rule, nil
+}
+
+func (s *RecordRuleService) ensureTable(ctx context.Context, tableID string) error {
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找表失败: %v", err))
+	}
+	if table == nil {
+		return pkgerrors.ErrTableNotFound.WithDetails(map[string]interface{}{
+			"table_id": tableID,
+		})
+	}
+	return nil
+}
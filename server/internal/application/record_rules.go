@@ -0,0 +1,51 @@
+package application
+
+import (
+	"context"
+
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// skipRecordRulesKey 上下文标记：本次写入已授权跳过表级记录校验规则
+//
+// upsert 等复合写入在内部调用 CreateRecord/UpdateRecord，标记随上下文传递，只需在入口授权一次
+type skipRecordRulesKey struct{}
+
+// SetRecordRuleService 设置表级记录校验规则服务（用于延迟注入）
+func (s *RecordService) SetRecordRuleService(recordRuleService *RecordRuleService) {
+	s.recordRuleService = recordRuleService
+}
+
+// recordRulesContext 请求跳过记录校验规则时检查权限（系统管理员或 Base 所有者），通过后在上下文中标记
+func (s *RecordService) recordRulesContext(ctx context.Context, tableID, userID string, skip bool) (context.Context, error) {
+	if !skip || s.recordRuleService == nil {
+		return ctx, nil
+	}
+	if err := s.recordRuleService.AuthorizeSkip(ctx, tableID, userID); err != nil {
+		return nil, err
+	}
+	logger.Info("跳过表级记录校验规则写入",
+		logger.String("table_id", tableID),
+		logger.String("user_id", userID))
+	return context.WithValue(ctx, skipRecordRulesKey{}, true), nil
+}
+
+// recordRuleChecker 获取表格的记录校验器；已授权跳过或表格没有启用的规则时返回 nil（Check 直接通过）
+func (s *RecordService) recordRuleChecker(ctx context.Context, tableID string) (*RecordRuleChecker, error) {
+	if s.recordRuleService == nil {
+		return nil, nil
+	}
+	if skip, _ := ctx.Value(skipRecordRulesKey{}).(bool); skip {
+		return nil, nil
+	}
+	return s.recordRuleService.NewChecker(ctx, tableID)
+}
+
+// checkRecordRules 提交前对写入后的整条记录数据求值表级校验规则
+func (s *RecordService) checkRecordRules(ctx context.Context, tableID string, data map[string]interface{}) error {
+	checker, err := s.recordRuleChecker(ctx, tableID)
+	if err != nil {
+		return err
+	}
+	return checker.Check(data)
+}
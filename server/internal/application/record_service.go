@@ -2,17 +2,25 @@ package application
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
 	"github.com/easyspace-ai/luckdb/server/internal/application/record"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	fieldService "github.com/easyspace-ai/luckdb/server/internal/domain/fields/service"
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
 	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
 	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
 	tableService "github.com/easyspace-ai/luckdb/server/internal/domain/table/service"
+	viewEntity "github.com/easyspace-ai/luckdb/server/internal/domain/view/entity"
+	viewRepo "github.com/easyspace-ai/luckdb/server/internal/domain/view/repository"
+	viewValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
 	"github.com/easyspace-ai/luckdb/server/internal/events"
 	infraRepository "github.com/easyspace-ai/luckdb/server/internal/infrastructure/repository"
 	"github.com/easyspace-ai/luckdb/server/internal/sharedb"
@@ -60,6 +68,11 @@ type RecordService struct {
 	shareDBService     *sharedb.ShareDBService       // ✨ ShareDB 实时协作服务
 	tableLinkService   *tableService.LinkService     // ✨ Link 字段服务
 	linkTitleUpdateService *LinkTitleUpdateService   // ✨ Link 字段标题更新服务
+	viewRepo           viewRepo.ViewRepository       // 视图仓储（按视图查询记录）
+	cursorSecret       []byte                        // 分页游标签名密钥
+	historyService     *RecordHistoryService         // 记录变更历史
+	batchService       *BatchService                 // 批量操作服务（按过滤条件批量更新）
+	recordRuleService  *RecordRuleService            // 表级记录校验规则
 	logger             *zap.Logger                  // ✨ 日志记录器
 }
 
@@ -116,6 +129,40 @@ func (s *RecordService) SetHookService(hookService *HookService) {
 	s.hookService = hookService
 }
 
+// SetViewRepository 设置视图仓储（用于延迟注入）
+func (s *RecordService) SetViewRepository(viewRepository viewRepo.ViewRepository) {
+	s.viewRepo = viewRepository
+}
+
+// SetCursorSecret 设置分页游标签名的主密钥，实际签名使用从中派生的游标专用密钥
+func (s *RecordService) SetCursorSecret(secret string) {
+	if secret == "" {
+		s.cursorSecret = nil
+		return
+	}
+	s.cursorSecret = valueobject.DeriveCursorKey(secret)
+}
+
+// SetHistoryService 设置记录历史服务（用于延迟注入）
+func (s *RecordService) SetHistoryService(historyService *RecordHistoryService) {
+	s.historyService = historyService
+}
+
+// typecastData typecast=true 时宽松转换记录数据（名称转选项/用户/关联记录等），否则原样返回
+func (s *RecordService) typecastData(ctx context.Context, tableID string, data map[string]interface{}, typecast bool) (map[string]interface{}, error) {
+	if !typecast || s.typecastService == nil {
+		return data, nil
+	}
+	converted, err := s.typecastService.TypecastRecordValues(ctx, tableID, data)
+	if err != nil {
+		if appErr, ok := pkgerrors.IsAppError(err); ok {
+			return nil, appErr
+		}
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("类型转换失败: %v", err))
+	}
+	return converted, nil
+}
+
 // getDBFromRecordRepo 从 RecordRepository 获取数据库连接
 // 处理缓存包装器的情况
 func (s *RecordService) getDBFromRecordRepo() (*gorm.DB, error) {
@@ -131,6 +178,10 @@ func (s *RecordService) getDBFromRecordRepo() (*gorm.DB, error) {
 	if dynamicRepo, ok := s.recordRepo.(*infraRepository.RecordRepositoryDynamic); ok {
 		return dynamicRepo.GetDB(), nil
 	}
+	// 其他提供数据库连接的实现
+	if provider, ok := s.recordRepo.(interface{ GetDB() *gorm.DB }); ok {
+		return provider.GetDB(), nil
+	}
 	return nil, fmt.Errorf("不支持的 RecordRepository 类型")
 }
 
@@ -152,6 +203,12 @@ func (s *RecordService) CreateRecord(ctx context.Context, req dto.CreateRecordRe
 	var record *entity.Record
 	var finalFields map[string]interface{}
 
+	// 请求跳过表级校验规则时先检查权限
+	ctx, err := s.recordRulesContext(ctx, req.TableID, userID, req.SkipRecordRules)
+	if err != nil {
+		return nil, err
+	}
+
 	// ✅ 在事务中执行所有操作
 	// 处理缓存包装器的情况
 	db, err := s.getDBFromRecordRepo()
@@ -160,23 +217,41 @@ func (s *RecordService) CreateRecord(ctx context.Context, req dto.CreateRecordRe
 	}
 
 	err = database.Transaction(ctx, db, nil, func(txCtx context.Context) error {
-		// 1. 数据验证和类型转换（使用验证服务）
-		validatedData, err := s.validationService.ValidateAndTypecast(txCtx, req.TableID, req.Data, false)
+		// 1. 数据验证和类型转换（使用验证服务），typecast=true 时先宽松转换
+		data, err := s.typecastData(txCtx, req.TableID, req.Data, req.Typecast)
+		if err != nil {
+			return err
+		}
+		validatedData, err := s.validationService.ValidateAndTypecast(txCtx, req.TableID, data, req.Typecast)
 		if err != nil {
 			return err // 直接返回错误，保留具体的错误类型
 		}
 
+		// 1.1 填充省略字段的默认值（在必填校验之前，有默认值的必填字段可以省略）
+		defaultFieldIDs, err := s.applyFieldDefaults(txCtx, req.TableID, validatedData, userID)
+		if err != nil {
+			return err
+		}
+
 		// 2. 验证必填字段（使用验证服务）
 		if err := s.validationService.ValidateRequiredFields(txCtx, req.TableID, validatedData); err != nil {
 			return err
 		}
 
 		// 3. 创建记录（使用CRUD服务）
-		record, err = s.crudService.CreateRecord(txCtx, req.TableID, validatedData, userID)
+		record, err = s.crudService.CreateRecordWithID(txCtx, req.TableID, req.RecordID, validatedData, userID)
 		if err != nil {
 			return err
 		}
 
+		// 事务提交后把填充的默认值写入记录历史
+		if len(defaultFieldIDs) > 0 {
+			savedRecord := record
+			database.AddTxCallback(txCtx, func() {
+				s.recordDefaultHistory(savedRecord, defaultFieldIDs, userID)
+			})
+		}
+
 		logger.Info("记录创建成功（事务中）",
 			logger.String("record_id", record.ID().String()),
 			logger.String("table_id", req.TableID))
@@ -193,6 +268,11 @@ func (s *RecordService) CreateRecord(ctx context.Context, req dto.CreateRecordRe
 				logger.String("record_id", record.ID().String()))
 		}
 
+		// 4.1 表级记录校验规则（对包含计算结果的整条记录求值，不通过时回滚事务）
+		if err := s.checkRecordRules(txCtx, req.TableID, record.Data().ToMap()); err != nil {
+			return err
+		}
+
 		// 5. ✅ 收集事件（不立即发送）
 		finalFields = record.Data().ToMap()
 		event := &database.RecordEvent{
@@ -329,6 +409,24 @@ func (s *RecordService) UpdateRecord(ctx context.Context, tableID, recordID stri
 		})
 	}
 
+	// 请求跳过表级校验规则时先检查权限
+	if ctx, err = s.recordRulesContext(ctx, tableID, userID, req.SkipRecordRules); err != nil {
+		return nil, err
+	}
+
+	// typecast=true 时先宽松转换并验证更新数据
+	if req.Typecast {
+		converted, err := s.typecastData(ctx, tableID, updateData, true)
+		if err != nil {
+			return nil, err
+		}
+		if updateData, err = s.typecastService.ValidateAndTypecastRecord(ctx, tableID, converted, true); err != nil {
+			return nil, err
+		}
+	} else if err := s.typecastService.CheckFieldRules(ctx, tableID, updateData); err != nil {
+		return nil, err
+	}
+
 	var record *entity.Record
 	var finalFields map[string]interface{}
 
@@ -498,15 +596,37 @@ func (s *RecordService) UpdateRecord(ctx context.Context, tableID, recordID stri
 				logger.Int("changed_fields", len(changedFieldIDs)))
 		}
 
+		// 7.1 表级记录校验规则（对合并后的整条记录求值）
+		if err := s.checkRecordRules(txCtx, tableID, record.Data().ToMap()); err != nil {
+			return err
+		}
+
 		// 8. 保存（在事务中，包含计算后的字段）
 		// 注意：record.Update()已经递增了版本，但Save会用旧版本做乐观锁检查
 		// 由于UpdateRecord逻辑复杂（包含乐观锁、Link处理、计算等），直接使用recordRepo.Save
 		if err := s.recordRepo.Save(txCtx, record); err != nil {
+			if appErr, ok := pkgerrors.IsAppError(err); ok {
+				return appErr
+			}
 			return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存记录失败: %v", err))
 		}
 
 		logger.Info("记录更新成功（事务中）", logger.String("record_id", recordID))
 
+		// 事务提交后写入字段变更历史
+		if s.historyService != nil && len(changedFieldIDs) > 0 {
+			beforeData := cleanedOldData
+			afterData := record.Data().ToMap()
+			savedRecord := record
+			database.AddTxCallback(txCtx, func() {
+				if err := s.historyService.RecordUpdate(context.Background(), savedRecord, changedFieldIDs, beforeData, afterData, userID); err != nil {
+					logger.Warn("写入记录历史失败",
+						logger.String("record_id", recordID),
+						logger.ErrorField(err))
+				}
+			})
+		}
+
 		// 9. ✅ 收集事件（不立即发送）
 		finalFields = record.Data().ToMap()
 		event := &database.RecordEvent{
@@ -980,28 +1100,84 @@ func (s *RecordService) removeLinkReference(ctx context.Context, tableID, record
 // - convertFieldNamesToIDs -> RecordValidationService
 // - cleanRedundantKeys -> RecordValidationService
 
-// ListRecords 列出表格的所有记录
-func (s *RecordService) ListRecords(ctx context.Context, tableID string, limit, offset int) ([]*dto.RecordResponse, int64, error) {
+// ListRecords 列出表格的记录（支持服务端过滤）
+func (s *RecordService) ListRecords(ctx context.Context, tableID string, req dto.ListRecordsRequest) (*dto.RecordListResponse, error) {
 	// 构建过滤器
 	filter := recordRepo.RecordFilter{
 		TableID: &tableID,
-		Limit:   limit,
-		Offset:  offset,
+		Limit:   req.Limit,
+		Offset:  req.Offset,
 	}
 
-	if filter.Limit == 0 {
+	if filter.Limit <= 0 {
 		filter.Limit = 100 // 默认限制
 	}
 
+	// 解析过滤树
+	if len(req.Filter) > 0 {
+		filterTree, err := viewValueObject.NewFilter(req.Filter)
+		if err != nil {
+			return nil, pkgerrors.ErrInvalidFilter.WithDetails(err.Error())
+		}
+		filter.Filter = filterTree
+	}
+
+	// 解析多键排序
+	if len(req.Sort) > 0 {
+		sort, err := viewValueObject.NewSort(req.Sort)
+		if err != nil {
+			return nil, pkgerrors.ErrInvalidSort.WithDetails(err.Error())
+		}
+		filter.Sort = sort.SortItems
+	}
+
+	// 按视图查询：合并视图的过滤、分组和排序
+	var view *viewEntity.View
+	if req.ViewID != "" {
+		var err error
+		view, err = s.loadTableView(ctx, tableID, req.ViewID)
+		if err != nil {
+			return nil, err
+		}
+		applyViewToRecordFilter(&filter, view)
+	}
+
+	// 解析键集分页游标
+	if req.Cursor != "" {
+		cursor, err := s.decodeRecordCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.Cursor = cursor
+	}
+
+	// 全文搜索（指定投影时只搜索投影字段）
+	searchMatcher := fieldService.NewSearchMatcher(req.Search)
+	if searchMatcher != nil {
+		filter.Search = &recordRepo.RecordSearch{Query: searchMatcher.Query(), FieldIDs: req.Projection}
+	}
+
+	// 字段投影与单元格输出格式
+	filter.Projection = req.Projection
+	cellFormat, err := fieldService.ParseCellFormat(req.CellFormat)
+	if err != nil {
+		return nil, pkgerrors.ErrBadRequest.WithDetails(err.Error())
+	}
+
 	// 查询记录列表
-	records, total, err := s.recordRepo.List(ctx, filter)
+	page, err := s.recordRepo.ListPage(ctx, filter)
 	if err != nil {
-		return nil, 0, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查询记录列表失败: %v", err))
+		if appErr, ok := pkgerrors.IsAppError(err); ok {
+			return nil, appErr
+		}
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查询记录列表失败: %v", err))
 	}
 
 	// ✅ 优化：批量预加载字段，避免N+1查询
 	// 一次性获取所有字段，然后在计算时复用
-	if s.calculationService != nil && len(records) > 0 {
+	// 指定投影时只查询了部分列，计算字段的依赖可能缺失，直接返回已持久化的计算值
+	records := page.Records
+	if s.calculationService != nil && len(records) > 0 && len(filter.Projection) == 0 {
 		logger.Info("开始计算记录列表的虚拟字段",
 			logger.String("table_id", tableID),
 			logger.Int("record_count", len(records)))
@@ -1031,11 +1207,262 @@ func (s *RecordService) ListRecords(ctx context.Context, tableID string, limit,
 	}
 
 	// 转换为 DTO
-	return dto.FromRecordEntities(records), total, nil
+	responses := dto.FromRecordEntities(records)
+	var fields []*fieldEntity.Field
+	if (searchMatcher != nil || cellFormat == fieldService.CellFormatText) && len(responses) > 0 {
+		fields, err = s.fieldRepo.FindByTableID(ctx, tableID)
+		if err != nil {
+			return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取字段列表失败: %v", err))
+		}
+	}
+
+	// 命中字段按原始值计算：须在文本格式化之前，也须在移除隐藏列之前，
+	// 搜索条件覆盖视图隐藏的字段，只在隐藏列中命中的记录同样返回命中字段
+	if searchMatcher != nil {
+		for _, response := range responses {
+			response.SearchHits = searchMatcher.MatchedFields(fields, response.Data)
+		}
+	}
+
+	// 按视图查询时移除隐藏列，指定投影时只保留投影列
+	if view != nil {
+		removeHiddenViewColumns(responses, view)
+	}
+	if len(filter.Projection) > 0 {
+		keepProjectedColumns(responses, filter.Projection)
+	}
+	if cellFormat == fieldService.CellFormatText && len(responses) > 0 {
+		formatRecordCellsAsText(responses, fields)
+	}
+
+	pagination := &dto.PaginationResponse{
+		Total:       page.Total,
+		PageSize:    filter.Limit,
+		HasPrevious: filter.Cursor != nil || filter.Offset > 0,
+	}
+	if filter.Cursor == nil {
+		pagination.Page = filter.Offset/filter.Limit + 1
+		pagination.TotalPages = int((page.Total + int64(filter.Limit) - 1) / int64(filter.Limit))
+	}
+	if page.NextCursor != nil {
+		pagination.HasNext = true
+		if len(s.cursorSecret) > 0 {
+			nextCursor, err := page.NextCursor.Encode(s.cursorSecret)
+			if err != nil {
+				return nil, pkgerrors.ErrInternalServer.WithDetails(fmt.Sprintf("生成分页游标失败: %v", err))
+			}
+			pagination.NextCursor = nextCursor
+		}
+	}
+
+	return &dto.RecordListResponse{Records: responses, Pagination: pagination}, nil
+}
+
+// GroupRecords 按字段分组统计记录（分组头、记录数与每组聚合）
+// 过滤语义与 ListRecords 一致；指定 viewId 时合并视图过滤，未指定 groupBy 时使用视图分组
+func (s *RecordService) GroupRecords(ctx context.Context, tableID string, req dto.GroupRecordsRequest) (*dto.RecordGroupsResponse, error) {
+	query := recordRepo.RecordGroupQuery{
+		Filter: recordRepo.RecordFilter{TableID: &tableID},
+	}
+
+	// 解析过滤树
+	if len(req.Filter) > 0 {
+		filterTree, err := viewValueObject.NewFilter(req.Filter)
+		if err != nil {
+			return nil, pkgerrors.ErrInvalidFilter.WithDetails(err.Error())
+		}
+		query.Filter.Filter = filterTree
+	}
+
+	// 解析分组
+	if len(req.GroupBy) > 0 {
+		group, err := viewValueObject.NewGroup(req.GroupBy)
+		if err != nil {
+			return nil, pkgerrors.ErrInvalidGroup.WithDetails(err.Error())
+		}
+		query.GroupBy = group.GroupItems
+	}
+
+	// 按视图统计
+	if req.ViewID != "" {
+		view, err := s.loadTableView(ctx, tableID, req.ViewID)
+		if err != nil {
+			return nil, err
+		}
+		applyViewToRecordFilter(&query.Filter, view)
+		if len(query.GroupBy) == 0 && !view.Group().IsEmpty() {
+			query.GroupBy = view.Group().GroupItems
+		}
+	}
+
+	for _, agg := range req.Aggregations {
+		query.Aggregations = append(query.Aggregations, recordRepo.AggregateItem{
+			FieldID: agg.FieldID,
+			Func:    valueobject.AggregateFunc(agg.Func),
+		})
+	}
+
+	result, err := s.recordRepo.GroupBy(ctx, query)
+	if err != nil {
+		if appErr, ok := pkgerrors.IsAppError(err); ok {
+			return nil, appErr
+		}
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("分组统计记录失败: %v", err))
+	}
+
+	return dto.FromRecordGroupResult(result), nil
+}
+
+// GetRecordStatistics 统计过滤结果的列汇总值（表格底部统计栏）
+// 统计函数由字段类别决定，不适用的函数返回 INVALID_GROUP
+func (s *RecordService) GetRecordStatistics(ctx context.Context, tableID string, req dto.RecordStatisticsRequest) (*dto.RecordStatisticsResponse, error) {
+	query := recordRepo.RecordGroupQuery{
+		Filter: recordRepo.RecordFilter{TableID: &tableID},
+	}
+
+	if len(req.Filter) > 0 {
+		filterTree, err := viewValueObject.NewFilter(req.Filter)
+		if err != nil {
+			return nil, pkgerrors.ErrInvalidFilter.WithDetails(err.Error())
+		}
+		query.Filter.Filter = filterTree
+	}
+
+	if req.ViewID != "" {
+		view, err := s.loadTableView(ctx, tableID, req.ViewID)
+		if err != nil {
+			return nil, err
+		}
+		applyViewToRecordFilter(&query.Filter, view)
+	}
+
+	for fieldID, fn := range req.Statistics {
+		query.Aggregations = append(query.Aggregations, recordRepo.AggregateItem{
+			FieldID: fieldID,
+			Func:    valueobject.AggregateFunc(fn),
+		})
+	}
+
+	// 不分组：只计算全部过滤结果的聚合
+	result, err := s.recordRepo.GroupBy(ctx, query)
+	if err != nil {
+		if appErr, ok := pkgerrors.IsAppError(err); ok {
+			return nil, appErr
+		}
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("统计记录失败: %v", err))
+	}
+
+	resp := &dto.RecordStatisticsResponse{
+		Total:      result.Total,
+		Statistics: make(map[string]dto.FieldStatistic, len(req.Statistics)),
+	}
+	for fieldID, fn := range req.Statistics {
+		resp.Statistics[fieldID] = dto.FieldStatistic{
+			Func:  fn,
+			Value: result.Aggregates[fieldID][valueobject.AggregateFunc(fn)],
+		}
+	}
+	return resp, nil
+}
+
+// decodeRecordCursor 校验并解码客户端传入的分页游标
+func (s *RecordService) decodeRecordCursor(token string) (*valueobject.RecordCursor, error) {
+	if len(s.cursorSecret) == 0 {
+		return nil, pkgerrors.ErrFeatureNotAvailable.WithDetails("游标分页未配置签名密钥")
+	}
+
+	cursor, err := valueobject.DecodeRecordCursor(token, s.cursorSecret)
+	if err != nil {
+		return nil, pkgerrors.ErrInvalidCursor.WithDetails(err.Error())
+	}
+	return cursor, nil
+}
+
+// loadTableView 加载视图并校验其属于指定表
+func (s *RecordService) loadTableView(ctx context.Context, tableID, viewID string) (*viewEntity.View, error) {
+	if s.viewRepo == nil {
+		return nil, pkgerrors.ErrFeatureNotAvailable.WithDetails("视图仓储未初始化")
+	}
+
+	view, err := s.viewRepo.FindByID(ctx, viewID)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找视图失败: %v", err))
+	}
+	if view == nil || view.IsDeleted() || view.TableID() != tableID {
+		return nil, pkgerrors.ErrViewNotFound.WithDetails(viewID)
+	}
+
+	return view, nil
+}
+
+// applyViewToRecordFilter 将视图配置合并到记录过滤器（规则见 RecordFilter.ApplyView）
+func applyViewToRecordFilter(filter *recordRepo.RecordFilter, view *viewEntity.View) {
+	filter.ApplyView(view.Filter(), view.Sort(), view.Group())
+}
+
+// removeHiddenViewColumns 移除视图中隐藏的列
+func removeHiddenViewColumns(responses []*dto.RecordResponse, view *viewEntity.View) {
+	columnMeta := view.ColumnMeta()
+	if columnMeta.IsEmpty() {
+		return
+	}
+
+	hidden := make([]string, 0)
+	for _, col := range columnMeta.Columns {
+		if !col.Visible {
+			hidden = append(hidden, col.FieldID)
+		}
+	}
+	if len(hidden) == 0 {
+		return
+	}
+
+	for _, resp := range responses {
+		for _, fieldID := range hidden {
+			delete(resp.Data, fieldID)
+		}
+	}
+}
+
+// keepProjectedColumns 只保留投影中的字段
+func keepProjectedColumns(responses []*dto.RecordResponse, projection []string) {
+	keep := make(map[string]bool, len(projection))
+	for _, fieldID := range projection {
+		keep[fieldID] = true
+	}
+
+	for _, resp := range responses {
+		for fieldID := range resp.Data {
+			if !keep[fieldID] {
+				delete(resp.Data, fieldID)
+			}
+		}
+	}
+}
+
+// formatRecordCellsAsText 将单元格值渲染为显示字符串（cellFormat=text）
+func formatRecordCellsAsText(responses []*dto.RecordResponse, fields []*fieldEntity.Field) {
+	fieldsByID := make(map[string]*fieldEntity.Field, len(fields))
+	for _, field := range fields {
+		fieldsByID[field.ID().String()] = field
+	}
+
+	for _, resp := range responses {
+		for fieldID, value := range resp.Data {
+			if field, ok := fieldsByID[fieldID]; ok {
+				resp.Data[fieldID] = fieldService.FormatCellText(field, value)
+			}
+		}
+	}
 }
 
 // BatchCreateRecords 批量创建记录（严格遵守：返回AppError）
 func (s *RecordService) BatchCreateRecords(ctx context.Context, tableID string, req dto.BatchCreateRecordRequest, userID string) (*dto.BatchCreateRecordResponse, error) {
+	// 指定主键字段时走 upsert 模式
+	if len(req.KeyFields) > 0 {
+		return s.UpsertRecords(ctx, tableID, req, userID)
+	}
+
 	// ✅ 允许空数组：直接返回成功响应
 	if len(req.Records) == 0 {
 		return &dto.BatchCreateRecordResponse{
@@ -1046,37 +1473,98 @@ func (s *RecordService) BatchCreateRecords(ctx context.Context, tableID string,
 		}, nil
 	}
 
+	// 请求跳过表级校验规则时先检查权限
+	ctx, err := s.recordRulesContext(ctx, tableID, userID, req.SkipRecordRules)
+	if err != nil {
+		return nil, err
+	}
+	checker, err := s.recordRuleChecker(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+	db, err := s.getDBFromRecordRepo()
+	if err != nil {
+		return nil, pkgerrors.ErrInternalServer.WithDetails(fmt.Sprintf("获取数据库连接失败: %v", err))
+	}
+
 	successRecords := make([]*dto.RecordResponse, 0, len(req.Records))
 	errorsList := make([]string, 0)
+	var fieldErrors []dto.RecordFieldErrors
+	var ruleErrors []dto.RecordRuleErrors
 
 	// 遍历每条记录进行创建
 	for i, item := range req.Records {
 		// ✅ 对齐单条创建逻辑：使用 typecast service 验证和转换数据
-		validatedData, err := s.typecastService.ValidateAndTypecastRecord(ctx, tableID, item.Fields, true)
+		fields, err := s.typecastData(ctx, tableID, item.Fields, req.Typecast)
+		if err != nil {
+			errorsList = append(errorsList, fmt.Sprintf("记录%d类型转换失败: %v", i+1, err))
+			continue
+		}
+		validatedData, err := s.typecastService.ValidateAndTypecastRecord(ctx, tableID, fields, true)
 		if err != nil {
+			if ruleErrors, ok := fieldRuleErrors(err); ok {
+				fieldErrors = append(fieldErrors, dto.RecordFieldErrors{Index: i + 1, Errors: ruleErrors})
+			}
 			errorsList = append(errorsList, fmt.Sprintf("记录%d数据验证失败: %v", i+1, err))
 			continue
 		}
-
-		// 使用CRUD服务创建记录
-		record, err := s.crudService.CreateRecord(ctx, tableID, validatedData, userID)
+		defaultFieldIDs, err := s.applyFieldDefaults(ctx, tableID, validatedData, userID)
 		if err != nil {
-			errorsList = append(errorsList, fmt.Sprintf("记录%d创建失败: %v", i+1, err))
+			if ruleErrors, ok := fieldRuleErrors(err); ok {
+				fieldErrors = append(fieldErrors, dto.RecordFieldErrors{Index: i + 1, Errors: ruleErrors})
+			}
+			errorsList = append(errorsList, fmt.Sprintf("记录%d填充默认值失败: %v", i+1, err))
 			continue
 		}
 
-		// ✨ 自动计算虚拟字段（对齐单条创建逻辑）
-		if s.calculationService != nil {
-			if err := s.calculationService.CalculateRecordFields(ctx, record); err != nil {
-				logger.Warn("记录虚拟字段计算失败（不影响创建）",
-					logger.String("record_id", record.ID().String()),
-					logger.Int("record_index", i+1),
-					logger.ErrorField(err),
-				)
-				// 计算失败不影响记录创建，继续
+		// 每条记录单独事务：创建、计算虚拟字段后求值表级校验规则，不通过时只回滚这一条
+		var record *entity.Record
+		var ruleErr error
+		err = database.Transaction(ctx, db, nil, func(txCtx context.Context) error {
+			// 使用CRUD服务创建记录
+			var err error
+			record, err = s.crudService.CreateRecord(txCtx, tableID, validatedData, userID)
+			if err != nil {
+				return err
+			}
+
+			// ✨ 自动计算虚拟字段（对齐单条创建逻辑）
+			if s.calculationService != nil {
+				if err := s.calculationService.CalculateRecordFields(txCtx, record); err != nil {
+					logger.Warn("记录虚拟字段计算失败（不影响创建）",
+						logger.String("record_id", record.ID().String()),
+						logger.Int("record_index", i+1),
+						logger.ErrorField(err),
+					)
+					// 计算失败不影响记录创建，继续
+				}
 			}
+
+			ruleErr = checker.Check(record.Data().ToMap())
+			return ruleErr
+		})
+		if ruleErr != nil {
+			if errs, ok := recordRuleErrors(ruleErr); ok {
+				ruleErrors = append(ruleErrors, dto.RecordRuleErrors{Index: i + 1, Errors: errs})
+			}
+			errorsList = append(errorsList, fmt.Sprintf("记录%d不满足校验规则: %v", i+1, ruleErr))
+			continue
+		}
+		if err != nil {
+			errorsList = append(errorsList, fmt.Sprintf("记录%d创建失败: %v", i+1, err))
+			continue
 		}
 
+		// 广播创建事件（包含填充的默认值），默认值写入记录历史
+		s.publishRecordEvent(&database.RecordEvent{
+			EventType: "record.create",
+			TID:       tableID,
+			RID:       record.ID().String(),
+			Fields:    record.Data().ToMap(),
+			UserID:    userID,
+		})
+		s.recordDefaultHistory(record, defaultFieldIDs, userID)
+
 		// 添加到成功列表
 		successRecords = append(successRecords, dto.FromRecordEntity(record))
 	}
@@ -1093,14 +1581,315 @@ func (s *RecordService) BatchCreateRecords(ctx context.Context, tableID string,
 		SuccessCount: len(successRecords),
 		FailedCount:  len(errorsList),
 		Errors:       errorsList,
+		FieldErrors:  fieldErrors,
+		RuleErrors:   ruleErrors,
 	}, nil
 }
 
+// upsertRow upsert 模式下的一行输入
+type upsertRow struct {
+	index int
+	data  map[string]interface{} // 验证后的数据（字段ID为键）
+	key   string                 // 主键字段取值组成的匹配键
+}
+
+// UpsertRecords 按主键字段批量 upsert 记录
+//
+// 流程：
+//  1. 逐行验证与类型转换，提取主键字段取值（验证失败或缺少主键值的行计入 errors，不影响其他行）
+//  2. 在事务中按表加咨询锁，串行化同一张表的并发 upsert
+//  3. 一次查询匹配已有记录：匹配的行走 UpdateRecord（版本递增、变更历史、重算），其余走 CreateRecord
+//  4. 同一批次内主键相同的多行：后面的行更新前面创建的记录
+//
+// 写入阶段任一行失败则整批回滚；主键匹配到多条已有记录的行视为错误
+func (s *RecordService) UpsertRecords(ctx context.Context, tableID string, req dto.BatchCreateRecordRequest, userID string) (*dto.BatchCreateRecordResponse, error) {
+	resp := &dto.BatchCreateRecordResponse{
+		Records:    []*dto.RecordResponse{},
+		Errors:     []string{},
+		CreatedIDs: []string{},
+		UpdatedIDs: []string{},
+	}
+	if len(req.Records) == 0 {
+		return resp, nil
+	}
+
+	// 跳过校验规则的标记随上下文传给内部的 CreateRecord/UpdateRecord；任一行不满足规则则整批回滚
+	ctx, err := s.recordRulesContext(ctx, tableID, userID, req.SkipRecordRules)
+	if err != nil {
+		return nil, err
+	}
+
+	keyFields, err := s.resolveUpsertKeyFields(ctx, tableID, req.KeyFields)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 验证每一行并计算匹配键
+	rows := make([]upsertRow, 0, len(req.Records))
+	for i, item := range req.Records {
+		fields, err := s.typecastData(ctx, tableID, item.Fields, req.Typecast)
+		if err != nil {
+			resp.Errors = append(resp.Errors, fmt.Sprintf("记录%d类型转换失败: %v", i+1, err))
+			continue
+		}
+		validatedData, err := s.typecastService.ValidateAndTypecastRecord(ctx, tableID, fields, true)
+		if err != nil {
+			if ruleErrors, ok := fieldRuleErrors(err); ok {
+				resp.FieldErrors = append(resp.FieldErrors, dto.RecordFieldErrors{Index: i + 1, Errors: ruleErrors})
+			}
+			resp.Errors = append(resp.Errors, fmt.Sprintf("记录%d数据验证失败: %v", i+1, err))
+			continue
+		}
+
+		key, ok := upsertKey(keyFields, validatedData)
+		if !ok {
+			resp.Errors = append(resp.Errors, fmt.Sprintf("记录%d缺少主键字段的值", i+1))
+			continue
+		}
+		rows = append(rows, upsertRow{index: i, data: validatedData, key: key})
+	}
+
+	db, err := s.getDBFromRecordRepo()
+	if err != nil {
+		return nil, pkgerrors.ErrInternalServer.WithDetails(fmt.Sprintf("获取数据库连接失败: %v", err))
+	}
+
+	var result *upsertResult
+	err = database.Transaction(ctx, db, nil, func(txCtx context.Context) error {
+		// 2. 表级咨询锁（事务结束自动释放）
+		if db.Dialector.Name() == "postgres" {
+			if err := database.WithTx(txCtx, db).Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "record_upsert:"+tableID).Error; err != nil {
+				return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取 upsert 锁失败: %v", err))
+			}
+		}
+
+		// 3. 匹配已有记录（在事务上下文中读取，与加锁后的写入一致）
+		matched, err := s.findUpsertMatches(txCtx, tableID, keyFields, rows)
+		if err != nil {
+			return err
+		}
+
+		result, err = applyUpsertRows(rows, matched,
+			func(row upsertRow, recordID string) (*dto.RecordResponse, error) {
+				return s.UpdateRecord(txCtx, tableID, recordID, dto.UpdateRecordRequest{Data: row.data}, userID)
+			},
+			func(row upsertRow) (*dto.RecordResponse, error) {
+				return s.CreateRecord(txCtx, dto.CreateRecordRequest{TableID: tableID, Data: row.data}, userID)
+			})
+		return err
+	})
+	if err != nil {
+		logger.Error("批量 upsert 记录失败（事务已回滚）",
+			logger.String("table_id", tableID),
+			logger.ErrorField(err))
+		if appErr, ok := pkgerrors.IsAppError(err); ok {
+			return nil, appErr
+		}
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("批量 upsert 记录失败: %v", err))
+	}
+
+	resp.Records = append(resp.Records, result.records...)
+	resp.CreatedIDs = append(resp.CreatedIDs, result.createdIDs...)
+	resp.UpdatedIDs = append(resp.UpdatedIDs, result.updatedIDs...)
+	resp.Errors = append(resp.Errors, result.errors...)
+	resp.SuccessCount = len(resp.Records)
+	resp.FailedCount = len(resp.Errors)
+
+	logger.Info("批量 upsert 记录完成",
+		logger.String("table_id", tableID),
+		logger.Int("total", len(req.Records)),
+		logger.Int("created", len(resp.CreatedIDs)),
+		logger.Int("updated", len(resp.UpdatedIDs)),
+		logger.Int("failed", resp.FailedCount),
+	)
+
+	return resp, nil
+}
+
+// upsertResult 一批 upsert 的写入结果
+type upsertResult struct {
+	records    []*dto.RecordResponse
+	createdIDs []string
+	updatedIDs []string
+	errors     []string // 主键匹配到多条已有记录的行
+}
+
+// applyUpsertRows 按匹配结果逐行更新或创建记录
+// 同一批次内主键相同的多行：后面的行更新前面创建的记录；任一行写入失败即返回错误（由调用方回滚）
+func applyUpsertRows(
+	rows []upsertRow,
+	matched map[string][]string,
+	update func(row upsertRow, recordID string) (*dto.RecordResponse, error),
+	create func(row upsertRow) (*dto.RecordResponse, error),
+) (*upsertResult, error) {
+	result := &upsertResult{}
+	for _, row := range rows {
+		ids := matched[row.key]
+		if len(ids) > 1 {
+			result.errors = append(result.errors, fmt.Sprintf("记录%d的主键匹配到%d条已有记录", row.index+1, len(ids)))
+			continue
+		}
+
+		if len(ids) == 1 {
+			updated, err := update(row, ids[0])
+			if err != nil {
+				return nil, err
+			}
+			result.records = append(result.records, updated)
+			result.updatedIDs = append(result.updatedIDs, updated.ID)
+			continue
+		}
+
+		created, err := create(row)
+		if err != nil {
+			return nil, err
+		}
+		result.records = append(result.records, created)
+		result.createdIDs = append(result.createdIDs, created.ID)
+		matched[row.key] = []string{created.ID}
+	}
+	return result, nil
+}
+
+// resolveUpsertKeyFields 解析主键字段（字段ID或名称），只允许文本、数字、单选等可按值精确匹配的存储字段
+func (s *RecordService) resolveUpsertKeyFields(ctx context.Context, tableID string, keys []string) ([]*fieldEntity.Field, error) {
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取字段列表失败: %v", err))
+	}
+
+	keyFields := make([]*fieldEntity.Field, 0, len(keys))
+	for _, key := range keys {
+		var found *fieldEntity.Field
+		for _, field := range fields {
+			if field.ID().String() == key || field.Name().String() == key {
+				found = field
+				break
+			}
+		}
+		if found == nil {
+			return nil, pkgerrors.ErrFieldNotFound.WithDetails(map[string]interface{}{
+				"field_key": key,
+				"reason":    "upsert key field not found",
+			})
+		}
+		if !isUpsertKeyFieldType(found.Type().String()) {
+			return nil, pkgerrors.ErrValidationFailed.WithDetails(map[string]interface{}{
+				"field_id":   found.ID().String(),
+				"field_type": found.Type().String(),
+				"reason":     "field type cannot be used as an upsert key",
+			})
+		}
+		keyFields = append(keyFields, found)
+	}
+	return keyFields, nil
+}
+
+// findUpsertMatches 查询主键取值匹配的已有记录（匹配键 -> 记录ID列表）
+// ctx 为 upsert 事务的上下文，记录仓储在事务中使用事务连接读取
+func (s *RecordService) findUpsertMatches(ctx context.Context, tableID string, keyFields []*fieldEntity.Field, rows []upsertRow) (map[string][]string, error) {
+	matched := make(map[string][]string)
+	if len(rows) == 0 {
+		return matched, nil
+	}
+
+	// 构建 (k1 = v1 AND k2 = v2) OR ... 过滤树，同一匹配键只查一次
+	tree := &viewValueObject.Filter{Operator: viewValueObject.FilterOperatorOr}
+	seen := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		if seen[row.key] {
+			continue
+		}
+		seen[row.key] = true
+
+		group := &viewValueObject.Filter{Operator: viewValueObject.FilterOperatorAnd}
+		for _, field := range keyFields {
+			group.Filters = append(group.Filters, viewValueObject.FilterItem{
+				FieldID:  field.ID().String(),
+				Operator: viewValueObject.FilterItemOpIs,
+				Value:    row.data[field.ID().String()],
+			})
+		}
+		tree.Groups = append(tree.Groups, group)
+	}
+
+	projection := make([]string, len(keyFields))
+	for i, field := range keyFields {
+		projection[i] = field.ID().String()
+	}
+
+	existing, _, err := s.recordRepo.List(ctx, recordRepo.RecordFilter{
+		TableID:    &tableID,
+		Filter:     tree,
+		Projection: projection,
+	})
+	if err != nil {
+		if appErr, ok := pkgerrors.IsAppError(err); ok {
+			return nil, appErr
+		}
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("匹配已有记录失败: %v", err))
+	}
+
+	for _, rec := range existing {
+		if key, ok := upsertKey(keyFields, rec.Data().ToMap()); ok {
+			matched[key] = append(matched[key], rec.ID().String())
+		}
+	}
+	return matched, nil
+}
+
+// isUpsertKeyFieldType 可作为 upsert 主键的字段类型
+func isUpsertKeyFieldType(fieldType string) bool {
+	switch fieldType {
+	case fieldValueObject.TypeText, fieldValueObject.TypeSingleLineText, fieldValueObject.TypeLongText,
+		fieldValueObject.TypeEmail, fieldValueObject.TypeURL, fieldValueObject.TypePhone,
+		fieldValueObject.TypeNumber, fieldValueObject.TypeCurrency, fieldValueObject.TypePercent,
+		fieldValueObject.TypeAutoNumber, fieldValueObject.TypeSelect, fieldValueObject.TypeSingleSelect:
+		return true
+	}
+	return false
+}
+
+// upsertKey 由主键字段取值生成匹配键；任一主键值为空时返回 false
+// 数字统一按十进制文本比较，避免 1 与 1.0 被视为不同的键
+func upsertKey(keyFields []*fieldEntity.Field, data map[string]interface{}) (string, bool) {
+	parts := make([]string, len(keyFields))
+	for i, field := range keyFields {
+		switch v := data[field.ID().String()].(type) {
+		case nil:
+			return "", false
+		case string:
+			if v == "" {
+				return "", false
+			}
+			parts[i] = v
+		case float64:
+			parts[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case float32:
+			parts[i] = strconv.FormatFloat(float64(v), 'f', -1, 32)
+		case int, int32, int64:
+			parts[i] = fmt.Sprintf("%d", v)
+		default:
+			parts[i] = fmt.Sprintf("%v", v)
+		}
+	}
+	raw, _ := json.Marshal(parts)
+	return string(raw), true
+}
+
 // BatchUpdateRecords 批量更新记录（严格遵守：返回AppError）
 // ✨ 修复：使用事务并调用 UpdateLinkTitlesForRecord
 func (s *RecordService) BatchUpdateRecords(ctx context.Context, tableID string, req dto.BatchUpdateRecordRequest, userID string) (*dto.BatchUpdateRecordResponse, error) {
 	successRecords := make([]*dto.RecordResponse, 0, len(req.Records))
 	errorsList := make([]string, 0)
+	var fieldErrors []dto.RecordFieldErrors
+	var ruleErrors []dto.RecordRuleErrors
+
+	// 请求跳过表级校验规则时先检查权限
+	ctx, err := s.recordRulesContext(ctx, tableID, userID, req.SkipRecordRules)
+	if err != nil {
+		return nil, err
+	}
 
 	// ✨ 使用事务批量更新，确保每条记录都触发 Link 字段更新
 	// 获取数据库连接（从 recordRepo 获取，支持 CachedRecordRepository）
@@ -1119,7 +1908,12 @@ func (s *RecordService) BatchUpdateRecords(ctx context.Context, tableID string,
 	// ✨ 使用事务批量更新，确保每条记录都触发 Link 字段更新
 	// 注意：批量更新时，即使某些记录失败，也要继续处理其他记录
 	// 因此，我们需要在事务中捕获错误，但不中断事务
-	err := database.Transaction(ctx, db, nil, func(txCtx context.Context) error {
+	err = database.Transaction(ctx, db, nil, func(txCtx context.Context) error {
+		checker, err := s.recordRuleChecker(txCtx, tableID)
+		if err != nil {
+			return err
+		}
+
 		// 遍历每条记录进行更新
 		for i, item := range req.Records {
 			// 查找记录（使用 tableID）
@@ -1142,8 +1936,31 @@ func (s *RecordService) BatchUpdateRecords(ctx context.Context, tableID string,
 			}
 			record := records[0]
 
+			// typecast=true 时先宽松转换并验证，否则只检查字段校验规则
+			fields := item.Fields
+			if req.Typecast {
+				converted, castErr := s.typecastData(txCtx, tableID, item.Fields, true)
+				if castErr == nil {
+					converted, castErr = s.typecastService.ValidateAndTypecastRecord(txCtx, tableID, converted, true)
+				}
+				if castErr != nil {
+					if ruleErrors, ok := fieldRuleErrors(castErr); ok {
+						fieldErrors = append(fieldErrors, dto.RecordFieldErrors{Index: i + 1, RecordID: item.ID, Errors: ruleErrors})
+					}
+					errorsList = append(errorsList, fmt.Sprintf("记录%s类型转换失败: %v", item.ID, castErr))
+					continue
+				}
+				fields = converted
+			} else if ruleErr := s.typecastService.CheckFieldRules(txCtx, tableID, fields); ruleErr != nil {
+				if ruleErrors, ok := fieldRuleErrors(ruleErr); ok {
+					fieldErrors = append(fieldErrors, dto.RecordFieldErrors{Index: i + 1, RecordID: item.ID, Errors: ruleErrors})
+				}
+				errorsList = append(errorsList, fmt.Sprintf("记录%s数据验证失败: %v", item.ID, ruleErr))
+				continue
+			}
+
 			// 创建新数据
-			newData, dataErr := valueobject.NewRecordData(item.Fields)
+			newData, dataErr := valueobject.NewRecordData(fields)
 			if dataErr != nil {
 				logger.Warn("批量更新：记录数据无效",
 					logger.String("table_id", tableID),
@@ -1163,6 +1980,15 @@ func (s *RecordService) BatchUpdateRecords(ctx context.Context, tableID string,
 				continue
 			}
 
+			// 表级记录校验规则（对合并后的整条记录求值，不通过则跳过保存）
+			if checkErr := checker.Check(record.Data().ToMap()); checkErr != nil {
+				if errs, ok := recordRuleErrors(checkErr); ok {
+					ruleErrors = append(ruleErrors, dto.RecordRuleErrors{Index: i + 1, RecordID: item.ID, Errors: errs})
+				}
+				errorsList = append(errorsList, fmt.Sprintf("记录%s不满足校验规则: %v", item.ID, checkErr))
+				continue
+			}
+
 			// 保存（在事务中）
 			// 注意：如果保存失败，这会导致事务回滚，所以我们需要捕获错误
 			if saveErr := s.recordRepo.Save(txCtx, record); saveErr != nil {
@@ -1224,6 +2050,23 @@ func (s *RecordService) BatchUpdateRecords(ctx context.Context, tableID string,
 		logger.Error("批量更新记录事务失败",
 			logger.String("table_id", tableID),
 			logger.ErrorField(err))
+		// 所有记录都因违反字段校验规则失败时返回逐字段详情
+		if len(fieldErrors) > 0 && len(fieldErrors) == len(errorsList) {
+			return nil, pkgerrors.ErrFieldRuleViolation.WithDetails(map[string]interface{}{
+				"message": fmt.Sprintf("%d 条记录的值不满足字段校验规则", len(fieldErrors)),
+				"records": fieldErrors,
+			})
+		}
+		// 所有记录都因不满足表级校验规则失败时返回逐条规则详情
+		if len(ruleErrors) > 0 && len(ruleErrors) == len(errorsList) {
+			return nil, pkgerrors.ErrRecordRuleViolation.WithDetails(map[string]interface{}{
+				"message": fmt.Sprintf("%d 条记录不满足校验规则", len(ruleErrors)),
+				"records": ruleErrors,
+			})
+		}
+		if appErr, ok := pkgerrors.IsAppError(err); ok {
+			return nil, appErr
+		}
 		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("批量更新记录失败: %v", err))
 	}
 
@@ -1239,6 +2082,8 @@ func (s *RecordService) BatchUpdateRecords(ctx context.Context, tableID string,
 		SuccessCount: len(successRecords),
 		FailedCount:  len(errorsList),
 		Errors:       errorsList,
+		FieldErrors:  fieldErrors,
+		RuleErrors:   ruleErrors,
 	}, nil
 }
 
@@ -1422,3 +2267,18 @@ func (s *RecordService) publishRecordEvent(event *database.RecordEvent) {
 func (s *RecordService) SetShareDBService(shareDBService *sharedb.ShareDBService) {
 	s.shareDBService = shareDBService
 }
+
+// CreateRecordFromOp 处理 ShareDB 客户端提交的记录创建操作（实现 sharedb.RecordCreator）
+//
+// 与 REST 创建走同一流程（默认值、必填校验、虚拟字段计算、广播），返回最终写入的字段值
+func (s *RecordService) CreateRecordFromOp(ctx context.Context, tableID, recordID string, fields map[string]interface{}, userID string) (map[string]interface{}, error) {
+	resp, err := s.CreateRecord(ctx, dto.CreateRecordRequest{
+		TableID:  tableID,
+		Data:     fields,
+		RecordID: recordID,
+	}, userID)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
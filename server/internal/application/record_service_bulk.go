@@ -0,0 +1,410 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	viewValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+	"github.com/easyspace-ai/luckdb/server/pkg/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// 按过滤条件批量更新/删除
+//
+// 与 BatchUpdateRecords / BatchDeleteRecords 不同，这里不需要客户端传入记录ID：
+// 服务端按过滤条件分页查出匹配记录，每页在独立事务中处理，
+// 更新通过 BatchService 按字段生成批量 UPDATE，随后重算虚拟字段并在提交后广播
+
+const (
+	// defaultBulkMaxAffected 未指定 maxAffected 时的安全上限
+	defaultBulkMaxAffected = 10000
+	// bulkMaxAffectedLimit maxAffected 允许的最大值
+	bulkMaxAffectedLimit = 500000
+	// bulkPageSize 每页（每个事务）处理的最大记录数
+	bulkPageSize = 500
+)
+
+// SetBatchService 设置批量操作服务（用于延迟注入）
+func (s *RecordService) SetBatchService(batchService *BatchService) {
+	s.batchService = batchService
+}
+
+// UpdateRecordsByFilter 按过滤条件批量更新记录
+//
+// 执行流程：
+//  1. 验证并转换字段补丁（关联字段需要维护对称字段，不支持按过滤更新）
+//  2. 统计匹配数量，超过 maxAffected 时拒绝执行；dryRun 只返回数量
+//  3. 分页处理匹配记录：每页一个事务，BatchService 批量更新后重算受影响的虚拟字段
+//  4. 事务提交后广播记录更新并刷新引用这些记录的关联标题
+func (s *RecordService) UpdateRecordsByFilter(ctx context.Context, tableID string, req dto.UpdateRecordsByFilterRequest, userID string) (*dto.RecordsByFilterResponse, error) {
+	if s.batchService == nil {
+		return nil, pkgerrors.ErrFeatureNotAvailable.WithDetails("批量操作服务未初始化")
+	}
+	if len(req.Fields) == 0 {
+		return nil, pkgerrors.ErrValidationFailed.WithMessage("fields 不能为空")
+	}
+
+	filter, maxAffected, err := s.resolveBulkFilter(ctx, tableID, req.Filter, req.ViewID, req.MaxAffected)
+	if err != nil {
+		return nil, err
+	}
+
+	patch, err := s.resolveBulkPatch(ctx, tableID, req.Fields, req.Typecast)
+	if err != nil {
+		return nil, err
+	}
+
+	// 请求跳过表级校验规则时先检查权限
+	ctx, err = s.recordRulesContext(ctx, tableID, userID, req.SkipRecordRules)
+	if err != nil {
+		return nil, err
+	}
+
+	matched, err := s.countBulkMatches(ctx, filter, maxAffected)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.RecordsByFilterResponse{Matched: matched, DryRun: req.DryRun}
+	if req.DryRun || matched == 0 {
+		return resp, nil
+	}
+
+	checker, err := s.recordRuleChecker(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+
+	changedFieldIDs := make([]string, 0, len(patch))
+	for fieldID := range patch {
+		changedFieldIDs = append(changedFieldIDs, fieldID)
+	}
+	recalculate, err := s.hasVirtualFields(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := s.getDBFromRecordRepo()
+	if err != nil {
+		return nil, pkgerrors.ErrInternalServer.WithDetails(fmt.Sprintf("获取数据库连接失败: %v", err))
+	}
+
+	// 只需要记录ID，投影到补丁字段减少查询列
+	filter.Projection = changedFieldIDs
+	err = s.forEachBulkPage(ctx, filter, maxAffected, func(records []*entity.Record) error {
+		updates := make([]RecordUpdate, len(records))
+		ids := make([]valueobject.RecordID, len(records))
+		for i, record := range records {
+			updates[i] = RecordUpdate{TableID: tableID, RecordID: record.ID().String(), FieldUpdates: patch}
+			ids[i] = record.ID()
+		}
+
+		txErr := database.Transaction(ctx, db, nil, func(txCtx context.Context) error {
+			if err := s.batchService.BatchUpdateRecordsWithStrategy(txCtx, updates, BatchUpdateAllOrNothing); err != nil {
+				if appErr, ok := pkgerrors.IsAppError(err); ok {
+					return appErr
+				}
+				return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("批量更新记录失败: %v", err))
+			}
+
+			updated, err := s.recordRepo.FindByIDs(txCtx, tableID, ids)
+			if err != nil {
+				return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找记录失败: %v", err))
+			}
+
+			for _, record := range updated {
+				if recalculate && s.calculationService != nil {
+					version := record.Version().Value()
+					if err := s.calculationService.CalculateAffectedFields(txCtx, record, changedFieldIDs); err != nil {
+						return err
+					}
+					if record.Version().Value() != version {
+						if err := s.recordRepo.Save(txCtx, record); err != nil {
+							if appErr, ok := pkgerrors.IsAppError(err); ok {
+								return appErr
+							}
+							return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存记录失败: %v", err))
+						}
+					}
+				}
+				// 表级记录校验规则：任一记录不满足时回滚当前页
+				if err := checker.Check(record.Data().ToMap()); err != nil {
+					errs, _ := recordRuleErrors(err)
+					appErr, _ := pkgerrors.IsAppError(err)
+					return appErr.WithDetails(map[string]interface{}{
+						"record_id": record.ID().String(),
+						"errors":    errs,
+					})
+				}
+				s.addBulkUpdateCallbacks(txCtx, tableID, record, userID)
+			}
+			return nil
+		})
+		if txErr != nil {
+			return txErr
+		}
+		resp.Affected += len(records)
+		return nil
+	})
+	if err != nil {
+		logger.Error("按过滤条件批量更新失败",
+			logger.String("table_id", tableID),
+			logger.Int("affected", resp.Affected),
+			logger.ErrorField(err))
+		return nil, bulkError(err, resp.Affected)
+	}
+
+	logger.Info("按过滤条件批量更新完成",
+		logger.String("table_id", tableID),
+		logger.Int64("matched", matched),
+		logger.Int("affected", resp.Affected))
+	return resp, nil
+}
+
+// DeleteRecordsByFilter 按过滤条件批量删除记录
+//
+// 每页一个事务：清理关联引用后删除记录，事务提交后广播删除事件
+func (s *RecordService) DeleteRecordsByFilter(ctx context.Context, tableID string, req dto.DeleteRecordsByFilterRequest) (*dto.RecordsByFilterResponse, error) {
+	filter, maxAffected, err := s.resolveBulkFilter(ctx, tableID, req.Filter, req.ViewID, req.MaxAffected)
+	if err != nil {
+		return nil, err
+	}
+
+	matched, err := s.countBulkMatches(ctx, filter, maxAffected)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.RecordsByFilterResponse{Matched: matched, DryRun: req.DryRun}
+	if req.DryRun || matched == 0 {
+		return resp, nil
+	}
+
+	db, err := s.getDBFromRecordRepo()
+	if err != nil {
+		return nil, pkgerrors.ErrInternalServer.WithDetails(fmt.Sprintf("获取数据库连接失败: %v", err))
+	}
+
+	err = s.forEachBulkPage(ctx, filter, maxAffected, func(records []*entity.Record) error {
+		txErr := database.Transaction(ctx, db, nil, func(txCtx context.Context) error {
+			for _, record := range records {
+				recordID := record.ID().String()
+				if err := s.linkService.CleanupLinkReferences(txCtx, tableID, recordID); err != nil {
+					logger.Warn("清理 Link 字段引用失败（不影响记录删除）",
+						logger.String("table_id", tableID),
+						logger.String("record_id", recordID),
+						logger.ErrorField(err))
+				}
+				if err := s.crudService.DeleteRecord(txCtx, tableID, recordID); err != nil {
+					return err
+				}
+
+				event := &database.RecordEvent{
+					EventType: "record.delete",
+					TID:       tableID,
+					RID:       recordID,
+					Fields:    record.Data().ToMap(),
+				}
+				database.AddEventToTx(txCtx, event)
+				database.AddTxCallback(txCtx, func() {
+					s.publishRecordEvent(event)
+				})
+			}
+			return nil
+		})
+		if txErr != nil {
+			return txErr
+		}
+		resp.Affected += len(records)
+		return nil
+	})
+	if err != nil {
+		logger.Error("按过滤条件批量删除失败",
+			logger.String("table_id", tableID),
+			logger.Int("affected", resp.Affected),
+			logger.ErrorField(err))
+		return nil, bulkError(err, resp.Affected)
+	}
+
+	logger.Info("按过滤条件批量删除完成",
+		logger.String("table_id", tableID),
+		logger.Int64("matched", matched),
+		logger.Int("affected", resp.Affected))
+	return resp, nil
+}
+
+// resolveBulkFilter 解析过滤条件并合并视图过滤；过滤条件和视图至少指定一个
+func (s *RecordService) resolveBulkFilter(ctx context.Context, tableID string, rawFilter map[string]interface{}, viewID string, maxAffected int) (recordRepo.RecordFilter, int, error) {
+	filter := recordRepo.RecordFilter{TableID: &tableID}
+
+	if len(rawFilter) == 0 && viewID == "" {
+		return filter, 0, pkgerrors.ErrInvalidFilter.WithDetails("filter or viewId is required")
+	}
+	if len(rawFilter) > 0 {
+		filterTree, err := viewValueObject.NewFilter(rawFilter)
+		if err != nil {
+			return filter, 0, pkgerrors.ErrInvalidFilter.WithDetails(err.Error())
+		}
+		filter.Filter = filterTree
+	}
+	if viewID != "" {
+		view, err := s.loadTableView(ctx, tableID, viewID)
+		if err != nil {
+			return filter, 0, err
+		}
+		applyViewToRecordFilter(&filter, view)
+		// 排序不影响匹配结果，分页使用默认排序
+		filter.Sort = nil
+	}
+
+	switch {
+	case maxAffected <= 0:
+		maxAffected = defaultBulkMaxAffected
+	case maxAffected > bulkMaxAffectedLimit:
+		return filter, 0, pkgerrors.ErrValidationFailed.WithDetails(map[string]interface{}{
+			"max_affected": maxAffected,
+			"limit":        bulkMaxAffectedLimit,
+		})
+	}
+	return filter, maxAffected, nil
+}
+
+// resolveBulkPatch 验证并转换字段补丁，返回以字段ID为键的数据
+func (s *RecordService) resolveBulkPatch(ctx context.Context, tableID string, fields map[string]interface{}, typecast bool) (map[string]interface{}, error) {
+	data, err := s.typecastData(ctx, tableID, fields, typecast)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := s.typecastService.ValidateAndTypecastRecord(ctx, tableID, data, typecast)
+	if err != nil {
+		return nil, err
+	}
+	if len(patch) == 0 {
+		return nil, pkgerrors.ErrValidationFailed.WithMessage("fields 中没有可写入的字段")
+	}
+
+	tableFields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找字段失败: %v", err))
+	}
+	for _, field := range tableFields {
+		if _, ok := patch[field.ID().String()]; ok && field.Type().String() == fieldValueObject.TypeLink {
+			return nil, pkgerrors.ErrValidationFailed.WithMessage("按过滤条件更新不支持关联字段").WithDetails(map[string]interface{}{
+				"field_id": field.ID().String(),
+			})
+		}
+	}
+	return patch, nil
+}
+
+// countBulkMatches 统计匹配记录数，超过安全上限时返回 TOO_MANY_RECORDS
+func (s *RecordService) countBulkMatches(ctx context.Context, filter recordRepo.RecordFilter, maxAffected int) (int64, error) {
+	filter.Limit = 1
+	page, err := s.recordRepo.ListPage(ctx, filter)
+	if err != nil {
+		if appErr, ok := pkgerrors.IsAppError(err); ok {
+			return 0, appErr
+		}
+		return 0, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("统计匹配记录失败: %v", err))
+	}
+	if page.Total > int64(maxAffected) {
+		return page.Total, pkgerrors.ErrTooManyRecords.WithDetails(map[string]interface{}{
+			"matched":      page.Total,
+			"max_affected": maxAffected,
+		})
+	}
+	return page.Total, nil
+}
+
+// forEachBulkPage 按键集分页遍历匹配记录，最多处理 maxAffected 条
+//
+// 使用默认排序（创建时间）分页，更新后不再匹配或已删除的记录不影响后续页的游标
+func (s *RecordService) forEachBulkPage(ctx context.Context, filter recordRepo.RecordFilter, maxAffected int, fn func(records []*entity.Record) error) error {
+	pageSize := bulkPageSize
+	if s.batchService != nil {
+		if size := s.batchService.GetOptimalBatchSize(maxAffected); size > 0 && size < pageSize {
+			pageSize = size
+		}
+	}
+
+	processed := 0
+	for processed < maxAffected {
+		filter.Limit = min(pageSize, maxAffected-processed)
+		page, err := s.recordRepo.ListPage(ctx, filter)
+		if err != nil {
+			return err
+		}
+		if len(page.Records) == 0 {
+			return nil
+		}
+		if err := fn(page.Records); err != nil {
+			return err
+		}
+		processed += len(page.Records)
+
+		if page.NextCursor == nil {
+			return nil
+		}
+		filter.Cursor = page.NextCursor
+	}
+	return nil
+}
+
+// hasVirtualFields 表中是否有需要重算的虚拟字段
+func (s *RecordService) hasVirtualFields(ctx context.Context, tableID string) (bool, error) {
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return false, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找字段失败: %v", err))
+	}
+	for _, field := range fields {
+		if field.IsVirtual() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// addBulkUpdateCallbacks 收集更新事件，事务提交后广播并刷新关联标题
+func (s *RecordService) addBulkUpdateCallbacks(txCtx context.Context, tableID string, record *entity.Record, userID string) {
+	recordID := record.ID().String()
+	event := &database.RecordEvent{
+		EventType:  "record.update",
+		TID:        tableID,
+		RID:        recordID,
+		Fields:     record.Data().ToMap(),
+		UserID:     userID,
+		OldVersion: record.Version().Value() - 1,
+		NewVersion: record.Version().Value(),
+	}
+	database.AddEventToTx(txCtx, event)
+	database.AddTxCallback(txCtx, func() {
+		s.publishRecordEvent(event)
+	})
+
+	if s.linkTitleUpdateService != nil {
+		database.AddTxCallback(txCtx, func() {
+			if err := s.linkTitleUpdateService.UpdateLinkTitlesForRecord(context.Background(), tableID, recordID, record); err != nil {
+				logger.Error("批量更新时更新 Link 字段标题失败",
+					logger.String("table_id", tableID),
+					logger.String("record_id", recordID),
+					logger.ErrorField(err))
+			}
+		})
+	}
+}
+
+// bulkError 中途失败时在错误详情中带上已处理的记录数（之前的页已提交）
+func bulkError(err error, affected int) error {
+	appErr, ok := pkgerrors.IsAppError(err)
+	if !ok {
+		appErr = pkgerrors.ErrDatabaseOperation.WithDetails(err.Error())
+	}
+	return appErr.WithDetails(map[string]interface{}{
+		"affected": affected,
+		"cause":    appErr.Details,
+	})
+}
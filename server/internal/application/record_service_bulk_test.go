@@ -0,0 +1,322 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// fakeBulkRecordRepo 按记录下标作为游标分页，记录查询条件；FindByIDs 可在第 N 次调用时失败
+type fakeBulkRecordRepo struct {
+	recordRepo.RecordRepository
+	db         *gorm.DB
+	records    []*recordEntity.Record
+	filters    []recordRepo.RecordFilter
+	findCalls  int
+	failOnFind int
+}
+
+func (r *fakeBulkRecordRepo) GetDB() *gorm.DB {
+	return r.db
+}
+
+func (r *fakeBulkRecordRepo) ListPage(ctx context.Context, filter recordRepo.RecordFilter) (*recordRepo.RecordPage, error) {
+	r.filters = append(r.filters, filter)
+
+	start := 0
+	if filter.Cursor != nil {
+		fmt.Sscanf(filter.Cursor.ID, "%d", &start)
+	}
+	end := min(start+filter.Limit, len(r.records))
+	page := &recordRepo.RecordPage{Records: r.records[start:end], Total: int64(len(r.records))}
+	if end < len(r.records) {
+		page.NextCursor = &recordValueObject.RecordCursor{ID: fmt.Sprint(end)}
+	}
+	return page, nil
+}
+
+func (r *fakeBulkRecordRepo) FindByIDs(ctx context.Context, tableID string, ids []recordValueObject.RecordID) ([]*recordEntity.Record, error) {
+	r.findCalls++
+	if r.findCalls == r.failOnFind {
+		return nil, errors.New("find failed")
+	}
+	byID := make(map[string]*recordEntity.Record, len(r.records))
+	for _, record := range r.records {
+		byID[record.ID().String()] = record
+	}
+	records := make([]*recordEntity.Record, 0, len(ids))
+	for _, id := range ids {
+		records = append(records, byID[id.String()])
+	}
+	return records, nil
+}
+
+// bulkTestEnv 物理表在 sqlite 中的批量更新测试环境
+type bulkTestEnv struct {
+	db      *gorm.DB
+	service *RecordService
+	records *fakeBulkRecordRepo
+	status  *fieldEntity.Field
+}
+
+func newBulkTestEnv(t *testing.T, rows int) *bulkTestEnv {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	// 内存库每个连接独立，事务与查询共用一个连接
+	sqlDB.SetMaxOpenConns(1)
+
+	status, err := createFieldWithID("tbl_1", "fld_status", "status", fieldValueObject.TypeSingleLineText, "usr_1")
+	require.NoError(t, err)
+	column := status.DBFieldName().String()
+	require.NoError(t, db.Exec(fmt.Sprintf(`CREATE TABLE tbl_1 (
+		__id TEXT PRIMARY KEY,
+		"%s" TEXT,
+		__last_modified_time DATETIME,
+		__version INTEGER NOT NULL DEFAULT 1
+	)`, column)).Error)
+
+	records := &fakeBulkRecordRepo{db: db}
+	for i := 0; i < rows; i++ {
+		id := fmt.Sprintf("rec_%03d", i)
+		require.NoError(t, db.Exec(fmt.Sprintf(`INSERT INTO tbl_1 (__id, "%s") VALUES (?, 'open')`, column), id).Error)
+		records.records = append(records.records, newUpsertTestRecord(t, id, map[string]interface{}{"fld_status": "open"}))
+	}
+
+	fieldRepo := new(MockFieldRepository)
+	fieldRepo.On("FindByTableID", mock.Anything, "tbl_1").Return([]*fieldEntity.Field{status}, nil)
+	table, err := createTableWithID("bse_1", "tbl_1", "Tasks", "usr_1")
+	require.NoError(t, err)
+	tableRepo := new(MockTableRepository)
+	tableRepo.On("GetByID", mock.Anything, "tbl_1").Return(table, nil)
+	dbProvider := new(MockDBProvider)
+	dbProvider.On("GenerateTableName", "bse_1", "tbl_1").Return("tbl_1")
+	dbProvider.On("DriverName").Return("sqlite")
+
+	service := &RecordService{
+		recordRepo:      records,
+		fieldRepo:       fieldRepo,
+		typecastService: NewTypecastService(fieldRepo),
+		batchService:    NewBatchService(fieldRepo, records, tableRepo, dbProvider, db, nil),
+	}
+	return &bulkTestEnv{db: db, service: service, records: records, status: status}
+}
+
+// statusCounts 按状态统计物理表中的记录数
+func (e *bulkTestEnv) statusCounts(t *testing.T) map[string]int {
+	rows, err := e.db.Raw(fmt.Sprintf(`SELECT "%s", COUNT(*) FROM tbl_1 GROUP BY 1`, e.status.DBFieldName().String())).Rows()
+	require.NoError(t, err)
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var count int
+		require.NoError(t, rows.Scan(&status, &count))
+		counts[status] = count
+	}
+	return counts
+}
+
+func bulkStatusFilter() map[string]interface{} {
+	return map[string]interface{}{
+		"operator": "and",
+		"filters":  []interface{}{map[string]interface{}{"fieldId": "fld_status", "operator": "is", "value": "open"}},
+	}
+}
+
+func TestRecordService_UpdateRecordsByFilter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("dryRun 只返回匹配数量", func(t *testing.T) {
+		env := newBulkTestEnv(t, 3)
+		resp, err := env.service.UpdateRecordsByFilter(ctx, "tbl_1", dto.UpdateRecordsByFilterRequest{
+			Filter: bulkStatusFilter(),
+			Fields: map[string]interface{}{"fld_status": "done"},
+			DryRun: true,
+		}, "usr_1")
+		require.NoError(t, err)
+		assert.True(t, resp.DryRun)
+		assert.Equal(t, int64(3), resp.Matched)
+		assert.Zero(t, resp.Affected)
+		assert.Len(t, env.records.filters, 1, "只统计数量，不分页读取")
+		assert.Equal(t, map[string]int{"open": 3}, env.statusCounts(t))
+	})
+
+	t.Run("匹配数量超过 maxAffected 时拒绝执行", func(t *testing.T) {
+		env := newBulkTestEnv(t, 3)
+		_, err := env.service.UpdateRecordsByFilter(ctx, "tbl_1", dto.UpdateRecordsByFilterRequest{
+			Filter:      bulkStatusFilter(),
+			Fields:      map[string]interface{}{"fld_status": "done"},
+			MaxAffected: 2,
+		}, "usr_1")
+		appErr, ok := pkgerrors.IsAppError(err)
+		require.True(t, ok)
+		assert.Equal(t, "TOO_MANY_RECORDS", appErr.Code)
+		assert.Equal(t, map[string]int{"open": 3}, env.statusCounts(t))
+	})
+
+	t.Run("按键集分页逐页更新", func(t *testing.T) {
+		env := newBulkTestEnv(t, 250)
+		resp, err := env.service.UpdateRecordsByFilter(ctx, "tbl_1", dto.UpdateRecordsByFilterRequest{
+			Filter:      bulkStatusFilter(),
+			Fields:      map[string]interface{}{"fld_status": "done"},
+			MaxAffected: 250,
+		}, "usr_1")
+		require.NoError(t, err)
+		assert.Equal(t, 250, resp.Affected)
+		assert.Equal(t, map[string]int{"done": 250}, env.statusCounts(t))
+
+		// 第一次调用统计数量，之后每页带上一页返回的游标
+		pages := env.records.filters[1:]
+		require.Len(t, pages, 3)
+		assert.Nil(t, pages[0].Cursor)
+		assert.Equal(t, "100", pages[1].Cursor.ID)
+		assert.Equal(t, "200", pages[2].Cursor.ID)
+		assert.Equal(t, 100, pages[0].Limit)
+		assert.Equal(t, 50, pages[2].Limit)
+	})
+
+	t.Run("每页一个事务，失败时只回滚当前页", func(t *testing.T) {
+		env := newBulkTestEnv(t, 250)
+		env.records.failOnFind = 2
+		_, err := env.service.UpdateRecordsByFilter(ctx, "tbl_1", dto.UpdateRecordsByFilterRequest{
+			Filter:      bulkStatusFilter(),
+			Fields:      map[string]interface{}{"fld_status": "done"},
+			MaxAffected: 250,
+		}, "usr_1")
+		appErr, ok := pkgerrors.IsAppError(err)
+		require.True(t, ok)
+		details, _ := appErr.Details.(map[string]interface{})
+		assert.Equal(t, 100, details["affected"])
+		assert.Equal(t, map[string]int{"done": 100, "open": 150}, env.statusCounts(t))
+	})
+}
+
+func TestRecordService_DeleteRecordsByFilter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("dryRun 只返回匹配数量", func(t *testing.T) {
+		env := newBulkTestEnv(t, 3)
+		resp, err := env.service.DeleteRecordsByFilter(ctx, "tbl_1", dto.DeleteRecordsByFilterRequest{
+			Filter: bulkStatusFilter(),
+			DryRun: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), resp.Matched)
+		assert.Zero(t, resp.Affected)
+		assert.Equal(t, map[string]int{"open": 3}, env.statusCounts(t))
+	})
+
+	t.Run("匹配数量超过 maxAffected 时拒绝执行", func(t *testing.T) {
+		env := newBulkTestEnv(t, 3)
+		_, err := env.service.DeleteRecordsByFilter(ctx, "tbl_1", dto.DeleteRecordsByFilterRequest{
+			Filter:      bulkStatusFilter(),
+			MaxAffected: 2,
+		})
+		appErr, ok := pkgerrors.IsAppError(err)
+		require.True(t, ok)
+		assert.Equal(t, "TOO_MANY_RECORDS", appErr.Code)
+	})
+
+	t.Run("过滤条件和视图至少指定一个", func(t *testing.T) {
+		env := newBulkTestEnv(t, 0)
+		_, err := env.service.DeleteRecordsByFilter(ctx, "tbl_1", dto.DeleteRecordsByFilterRequest{})
+		appErr, ok := pkgerrors.IsAppError(err)
+		require.True(t, ok)
+		assert.Equal(t, pkgerrors.ErrInvalidFilter.Code, appErr.Code)
+	})
+}
+
+func TestBatchService_ExecuteBatchUpdate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("按记录写入各自的值并递增版本", func(t *testing.T) {
+		env := newBulkTestEnv(t, 3)
+		err := env.service.batchService.executeBatchUpdate(ctx, "tbl_1", "fld_status", env.status, "tbl_1",
+			map[string]interface{}{"rec_000": "done", "rec_001": "blocked"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"done": 1, "blocked": 1, "open": 1}, env.statusCounts(t))
+
+		var versions []int
+		require.NoError(t, env.db.Raw(`SELECT __version FROM tbl_1 ORDER BY __id`).Scan(&versions).Error)
+		assert.Equal(t, []int{2, 2, 1}, versions)
+	})
+
+	t.Run("非空字段写入空值时拦截", func(t *testing.T) {
+		env := newBulkTestEnv(t, 1)
+		require.NoError(t, env.status.SetNotNull(true))
+		err := env.service.batchService.executeBatchUpdate(ctx, "tbl_1", "fld_status", env.status, "tbl_1",
+			map[string]interface{}{"rec_000": "  "})
+		require.Error(t, err)
+		assert.Equal(t, map[string]int{"open": 1}, env.statusCounts(t))
+	})
+}
+
+func TestBatchService_BatchUpdateSQL(t *testing.T) {
+	s := &BatchService{}
+	clauses := []string{"WHEN __id = $1 THEN $2", "WHEN __id = $3 THEN $4"}
+
+	t.Run("列类型已知时显式转换 CASE 结果", func(t *testing.T) {
+		sql := s.batchUpdateSQL("bse_1.tbl_1", "score", "numeric", clauses, 4, 2)
+		assert.Contains(t, sql, `UPDATE "bse_1"."tbl_1"`)
+		assert.Contains(t, sql, `SET "score" = CAST(CASE WHEN __id = $1 THEN $2 WHEN __id = $3 THEN $4 END AS numeric)`)
+		assert.Contains(t, sql, "WHERE __id IN ($5, $6)")
+		assert.Contains(t, sql, "__version = __version + 1")
+	})
+
+	t.Run("列类型未知时由数据库推断", func(t *testing.T) {
+		sql := s.batchUpdateSQL("tbl_1", "score", "", clauses, 4, 2)
+		assert.Contains(t, sql, `SET "score" = CASE WHEN __id = $1 THEN $2 WHEN __id = $3 THEN $4 END,`)
+		assert.False(t, strings.Contains(sql, "CAST("))
+	})
+}
+
+func TestBatchService_ConvertFieldValue(t *testing.T) {
+	s := &BatchService{}
+	text, err := createFieldWithID("tbl_1", "fld_text", "text", fieldValueObject.TypeSingleLineText, "usr_1")
+	require.NoError(t, err)
+	link, err := createFieldWithID("tbl_1", "fld_link", "link", fieldValueObject.TypeLink, "usr_1")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		value      interface{}
+		field      *fieldEntity.Field
+		jsonColumn bool
+		want       interface{}
+	}{
+		{"空值", nil, text, false, nil},
+		{"文本", "hello", text, false, "hello"},
+		{"布尔转为文本", true, text, false, "true"},
+		{"大数不使用科学计数法", 1e21, text, false, "1000000000000000000000"},
+		{"小数", 12.5, text, false, "12.5"},
+		{"整数", int64(42), text, false, "42"},
+		{"数组序列化为 JSON", []interface{}{"a", "b"}, text, false, `["a","b"]`},
+		{"JSONB 列中的文本序列化为 JSON", "hello", text, true, `"hello"`},
+		{"关联字段总是序列化为 JSON", []interface{}{map[string]interface{}{"id": "rec_1"}}, link, false, `[{"id":"rec_1"}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.convertFieldValue(tt.value, tt.field, tt.jsonColumn)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	viewValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+	"github.com/easyspace-ai/luckdb/server/pkg/database"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRecordRepo 返回预置记录，记录每次查询的上下文与条件
+type fakeRecordRepo struct {
+	recordRepo.RecordRepository
+	records  []*recordEntity.Record
+	contexts []context.Context
+	filters  []recordRepo.RecordFilter
+}
+
+func (r *fakeRecordRepo) List(ctx context.Context, filter recordRepo.RecordFilter) ([]*recordEntity.Record, int64, error) {
+	r.contexts = append(r.contexts, ctx)
+	r.filters = append(r.filters, filter)
+	return r.records, int64(len(r.records)), nil
+}
+
+func newUpsertTestRecord(t *testing.T, id string, data map[string]interface{}) *recordEntity.Record {
+	recordData, err := recordValueObject.NewRecordData(data)
+	require.NoError(t, err)
+	version, err := recordValueObject.NewRecordVersion(1)
+	require.NoError(t, err)
+	return recordEntity.ReconstructRecord(recordValueObject.NewRecordID(id), "tbl_1", recordData, version,
+		"usr_1", "usr_1", time.Now(), time.Now(), nil)
+}
+
+func newUpsertKeyFields(t *testing.T) []*fieldEntity.Field {
+	code, err := createFieldWithID("tbl_1", "fld_code", "Code", fieldValueObject.TypeSingleLineText, "usr_1")
+	require.NoError(t, err)
+	seq, err := createFieldWithID("tbl_1", "fld_seq", "Seq", fieldValueObject.TypeNumber, "usr_1")
+	require.NoError(t, err)
+	return []*fieldEntity.Field{code, seq}
+}
+
+func TestUpsertKey(t *testing.T) {
+	keyFields := newUpsertKeyFields(t)
+
+	t.Run("数字按十进制文本比较", func(t *testing.T) {
+		want, ok := upsertKey(keyFields, map[string]interface{}{"fld_code": "A", "fld_seq": float64(1)})
+		require.True(t, ok)
+		for _, seq := range []interface{}{1, int64(1), float32(1), 1.0} {
+			key, ok := upsertKey(keyFields, map[string]interface{}{"fld_code": "A", "fld_seq": seq})
+			assert.True(t, ok)
+			assert.Equal(t, want, key, "%T", seq)
+		}
+
+		key, _ := upsertKey(keyFields, map[string]interface{}{"fld_code": "A", "fld_seq": 1.5})
+		assert.NotEqual(t, want, key)
+	})
+
+	t.Run("多个主键字段组合不会因拼接而冲突", func(t *testing.T) {
+		a, _ := upsertKey(keyFields, map[string]interface{}{"fld_code": "a|b", "fld_seq": "c"})
+		b, _ := upsertKey(keyFields, map[string]interface{}{"fld_code": "a", "fld_seq": "b|c"})
+		assert.NotEqual(t, a, b)
+	})
+
+	t.Run("缺少主键值", func(t *testing.T) {
+		_, ok := upsertKey(keyFields, map[string]interface{}{"fld_code": "A"})
+		assert.False(t, ok)
+		_, ok = upsertKey(keyFields, map[string]interface{}{"fld_code": "", "fld_seq": 1})
+		assert.False(t, ok)
+	})
+}
+
+func TestRecordService_ResolveUpsertKeyFields(t *testing.T) {
+	ctx := context.Background()
+	code, err := createFieldWithID("tbl_1", "fld_code", "Code", fieldValueObject.TypeSingleLineText, "usr_1")
+	require.NoError(t, err)
+	files, err := createFieldWithID("tbl_1", "fld_files", "Files", fieldValueObject.TypeAttachment, "usr_1")
+	require.NoError(t, err)
+
+	fieldRepo := new(MockFieldRepository)
+	fieldRepo.On("FindByTableID", ctx, "tbl_1").Return([]*fieldEntity.Field{code, files}, nil)
+	s := &RecordService{fieldRepo: fieldRepo}
+
+	t.Run("按字段ID或名称解析", func(t *testing.T) {
+		fields, err := s.resolveUpsertKeyFields(ctx, "tbl_1", []string{"Code"})
+		require.NoError(t, err)
+		require.Len(t, fields, 1)
+		assert.Equal(t, "fld_code", fields[0].ID().String())
+
+		fields, err = s.resolveUpsertKeyFields(ctx, "tbl_1", []string{"fld_code"})
+		require.NoError(t, err)
+		assert.Equal(t, "fld_code", fields[0].ID().String())
+	})
+
+	t.Run("字段不存在", func(t *testing.T) {
+		_, err := s.resolveUpsertKeyFields(ctx, "tbl_1", []string{"Missing"})
+		appErr, ok := pkgerrors.IsAppError(err)
+		require.True(t, ok)
+		assert.Equal(t, "FIELD_NOT_FOUND", appErr.Code)
+	})
+
+	t.Run("字段类型不能作为主键", func(t *testing.T) {
+		_, err := s.resolveUpsertKeyFields(ctx, "tbl_1", []string{"Files"})
+		appErr, ok := pkgerrors.IsAppError(err)
+		require.True(t, ok)
+		assert.Equal(t, "VALIDATION_FAILED", appErr.Code)
+	})
+}
+
+func TestRecordService_FindUpsertMatches(t *testing.T) {
+	keyFields := newUpsertKeyFields(t)
+	rowKey := func(data map[string]interface{}) upsertRow {
+		key, ok := upsertKey(keyFields, data)
+		require.True(t, ok)
+		return upsertRow{data: data, key: key}
+	}
+
+	records := &fakeRecordRepo{records: []*recordEntity.Record{
+		// 从 JSON 读出的数字是 float64
+		newUpsertTestRecord(t, "rec_a", map[string]interface{}{"fld_code": "A", "fld_seq": 1.0}),
+		newUpsertTestRecord(t, "rec_b1", map[string]interface{}{"fld_code": "B", "fld_seq": 2.0}),
+		newUpsertTestRecord(t, "rec_b2", map[string]interface{}{"fld_code": "B", "fld_seq": 2.0}),
+	}}
+	s := &RecordService{recordRepo: records}
+
+	rows := []upsertRow{
+		rowKey(map[string]interface{}{"fld_code": "A", "fld_seq": int64(1)}),
+		rowKey(map[string]interface{}{"fld_code": "A", "fld_seq": 1}),
+		rowKey(map[string]interface{}{"fld_code": "B", "fld_seq": 2}),
+		rowKey(map[string]interface{}{"fld_code": "C", "fld_seq": 3}),
+	}
+	txCtx := database.SetTxContext(context.Background(), &database.TxContext{ID: "tx_test"})
+	matched, err := s.findUpsertMatches(txCtx, "tbl_1", keyFields, rows)
+	require.NoError(t, err)
+
+	t.Run("在事务上下文中读取", func(t *testing.T) {
+		require.Len(t, records.contexts, 1)
+		assert.True(t, database.InTransaction(records.contexts[0]))
+	})
+
+	t.Run("同一批次内相同的主键只查询一次", func(t *testing.T) {
+		filter := records.filters[0]
+		require.NotNil(t, filter.Filter)
+		assert.Equal(t, viewValueObject.FilterOperatorOr, filter.Filter.Operator)
+		assert.Len(t, filter.Filter.Groups, 3)
+		assert.ElementsMatch(t, []string{"fld_code", "fld_seq"}, filter.Projection)
+	})
+
+	t.Run("数字主键与已有记录匹配", func(t *testing.T) {
+		assert.Equal(t, []string{"rec_a"}, matched[rows[0].key])
+	})
+
+	t.Run("主键匹配到多条已有记录", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{"rec_b1", "rec_b2"}, matched[rows[2].key])
+	})
+
+	t.Run("未匹配的主键", func(t *testing.T) {
+		assert.Empty(t, matched[rows[3].key])
+	})
+}
+
+func TestApplyUpsertRows(t *testing.T) {
+	row := func(index int, key string) upsertRow {
+		return upsertRow{index: index, key: key, data: map[string]interface{}{}}
+	}
+
+	var created, updated []string
+	next := 0
+	update := func(r upsertRow, recordID string) (*dto.RecordResponse, error) {
+		updated = append(updated, recordID)
+		return &dto.RecordResponse{ID: recordID}, nil
+	}
+	create := func(r upsertRow) (*dto.RecordResponse, error) {
+		next++
+		id := fmt.Sprintf("rec_new%d", next)
+		created = append(created, id)
+		return &dto.RecordResponse{ID: id}, nil
+	}
+
+	matched := map[string][]string{
+		"a": {"rec_a"},
+		"b": {"rec_b1", "rec_b2"},
+	}
+	result, err := applyUpsertRows([]upsertRow{
+		row(0, "a"), // 匹配到已有记录：更新
+		row(1, "b"), // 匹配到多条：计入错误
+		row(2, "c"), // 未匹配：创建
+		row(3, "c"), // 同批次内重复：更新上一行创建的记录
+	}, matched, update, create)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"rec_new1"}, result.createdIDs)
+	assert.Equal(t, []string{"rec_a", "rec_new1"}, result.updatedIDs)
+	assert.Len(t, result.records, 3)
+	require.Len(t, result.errors, 1)
+	assert.Contains(t, result.errors[0], "记录2")
+	assert.Equal(t, []string{"rec_new1"}, created)
+	assert.Equal(t, []string{"rec_a", "rec_new1"}, updated)
+}
@@ -0,0 +1,49 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	viewEntity "github.com/easyspace-ai/luckdb/server/internal/domain/view/entity"
+	viewValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newColumnTestResponses() []*dto.RecordResponse {
+	return []*dto.RecordResponse{
+		{ID: "rec_1", Data: map[string]interface{}{"fld_name": "A", "fld_secret": "x", "fld_note": "n1"}},
+		{ID: "rec_2", Data: map[string]interface{}{"fld_name": "B", "fld_secret": "y"}},
+	}
+}
+
+func TestRemoveHiddenViewColumns(t *testing.T) {
+	view, err := viewEntity.NewView("tbl_1", "Grid", viewValueObject.ViewTypeGrid, "usr_1")
+	require.NoError(t, err)
+
+	t.Run("没有列配置时保留全部列", func(t *testing.T) {
+		responses := newColumnTestResponses()
+		removeHiddenViewColumns(responses, view)
+		assert.Len(t, responses[0].Data, 3)
+	})
+
+	t.Run("移除隐藏列，未配置的列保留", func(t *testing.T) {
+		require.NoError(t, view.UpdateColumnMeta(&viewValueObject.ColumnMetaList{Columns: []viewValueObject.ColumnMeta{
+			{FieldID: "fld_name", Visible: true},
+			{FieldID: "fld_secret", Visible: false},
+		}}))
+		responses := newColumnTestResponses()
+		removeHiddenViewColumns(responses, view)
+
+		assert.Equal(t, map[string]interface{}{"fld_name": "A", "fld_note": "n1"}, responses[0].Data)
+		assert.Equal(t, map[string]interface{}{"fld_name": "B"}, responses[1].Data)
+	})
+}
+
+func TestKeepProjectedColumns(t *testing.T) {
+	responses := newColumnTestResponses()
+	keepProjectedColumns(responses, []string{"fld_name", "fld_note"})
+
+	assert.Equal(t, map[string]interface{}{"fld_name": "A", "fld_note": "n1"}, responses[0].Data)
+	assert.Equal(t, map[string]interface{}{"fld_name": "B"}, responses[1].Data)
+}
@@ -0,0 +1,117 @@
+package searchindex
+
+import (
+	"strings"
+	"time"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldService "github.com/easyspace-ai/luckdb/server/internal/domain/fields/service"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/search"
+)
+
+// maxRecordContentLength 记录索引内容的最大长度（字符数），超出部分不参与搜索
+const maxRecordContentLength = 10000
+
+// tableScope 表格及其所属 Base/空间，构建索引条目时共用
+type tableScope struct {
+	TableID     string
+	Name        string
+	Description string
+	CreatedBy   string
+	BaseID      string
+	SpaceID     string
+	Fields      []*fieldEntity.Field
+}
+
+// recordFields 参与记录索引的字段：主字段与可搜索字段
+func (s *tableScope) recordFields() []*fieldEntity.Field {
+	fields := make([]*fieldEntity.Field, 0, len(s.Fields))
+	for _, field := range s.Fields {
+		if field.IsDeleted() {
+			continue
+		}
+		if field.IsPrimary() || fieldService.IsSearchableField(field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// field 按ID查找未删除的字段
+func (s *tableScope) field(fieldID string) *fieldEntity.Field {
+	for _, field := range s.Fields {
+		if field.ID().String() == fieldID && !field.IsDeleted() {
+			return field
+		}
+	}
+	return nil
+}
+
+// newTableIndex 表格索引条目（与 RebuildIndex 由元数据表生成的条目一致）
+func newTableIndex(scope *tableScope, now time.Time) *search.SearchIndex {
+	index := search.NewSearchIndex(search.SearchTypeTable, scope.Name, scope.Description)
+	index.SetSource(scope.TableID, string(search.SearchTypeTable), "")
+	index.SetContext(scope.CreatedBy, scope.SpaceID, scope.TableID, "")
+	index.SetBase(scope.BaseID)
+	stamp(index, now)
+	return index
+}
+
+// newFieldIndex 字段索引条目
+func newFieldIndex(scope *tableScope, field *fieldEntity.Field, now time.Time) *search.SearchIndex {
+	description := ""
+	if field.Description() != nil {
+		description = *field.Description()
+	}
+	index := search.NewSearchIndex(search.SearchTypeField, field.Name().String(), description)
+	index.SetSource(field.ID().String(), string(search.SearchTypeField), "")
+	index.SetContext(field.CreatedBy(), scope.SpaceID, scope.TableID, field.ID().String())
+	index.SetBase(scope.BaseID)
+	index.SetMetadata("field_type", field.Type().String())
+	stamp(index, now)
+	return index
+}
+
+// newRecordIndex 记录索引条目
+//
+// 标题取主字段的显示文本（为空时使用记录ID），内容为其余可搜索字段的显示文本（每个字段一行）。
+// 选项存储为ID，按选项名称写入内容，搜索选项名称即可命中记录
+func newRecordIndex(scope *tableScope, fields []*fieldEntity.Field, recordID, createdBy string, data map[string]interface{}, now time.Time) *search.SearchIndex {
+	title := ""
+	lines := make([]string, 0, len(fields))
+	for _, field := range fields {
+		value, ok := data[field.ID().String()]
+		if !ok || value == nil {
+			continue
+		}
+		text := strings.TrimSpace(fieldService.FormatCellText(field, value))
+		switch {
+		case text == "":
+		case field.IsPrimary():
+			title = text
+		case fieldService.IsSearchableField(field):
+			lines = append(lines, text)
+		}
+	}
+	if title == "" {
+		title = recordID
+	}
+
+	content := strings.Join(lines, "\n")
+	if runes := []rune(content); len(runes) > maxRecordContentLength {
+		content = string(runes[:maxRecordContentLength])
+	}
+
+	index := search.NewSearchIndex(search.SearchTypeRecord, title, content)
+	index.SetSource(recordID, string(search.SearchTypeRecord), "")
+	index.SetContext(createdBy, scope.SpaceID, scope.TableID, "")
+	index.SetBase(scope.BaseID)
+	stamp(index, now)
+	return index
+}
+
+// stamp 统一条目的写入时间，重建时据此清理未再写入的旧条目
+func stamp(index *search.SearchIndex, now time.Time) {
+	index.CreatedTime = now
+	index.UpdatedTime = now
+}
@@ -0,0 +1,108 @@
+package searchindex
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/search"
+)
+
+func newTestField(t *testing.T, id, fieldType string, options *valueobject.FieldOptions) *entity.Field {
+	ft, err := valueobject.NewFieldType(fieldType)
+	require.NoError(t, err)
+	name, err := valueobject.NewFieldName(id)
+	require.NoError(t, err)
+	dbFieldName, err := valueobject.NewDBFieldNameFromString(id)
+	require.NoError(t, err)
+	if options == nil {
+		options = valueobject.NewFieldOptions()
+	}
+	return entity.ReconstructField(
+		valueobject.NewFieldID(id), "tbl_1", name, ft, dbFieldName, "TEXT",
+		options, 0, 1, "usr_1", time.Now(), time.Now(),
+	)
+}
+
+func newTestScope(t *testing.T) *tableScope {
+	options := valueobject.NewFieldOptions()
+	options.Select = &valueobject.SelectOptions{Choices: []valueobject.SelectChoice{
+		{ID: "cho_1", Name: "进行中"},
+	}}
+
+	name := newTestField(t, "fld_name", valueobject.TypeSingleLineText, nil)
+	require.NoError(t, name.SetPrimary(true))
+	return &tableScope{
+		TableID: "tbl_1",
+		Name:    "客户",
+		BaseID:  "bse_1",
+		SpaceID: "spc_1",
+		Fields: []*entity.Field{
+			name,
+			newTestField(t, "fld_note", valueobject.TypeLongText, nil),
+			newTestField(t, "fld_status", valueobject.TypeSingleSelect, options),
+			newTestField(t, "fld_amount", valueobject.TypeNumber, nil),
+		},
+	}
+}
+
+func TestNewRecordIndex(t *testing.T) {
+	scope := newTestScope(t)
+	fields := scope.recordFields()
+	assert.Len(t, fields, 3, "数字字段不参与记录索引")
+
+	now := time.Now()
+	index := newRecordIndex(scope, fields, "rec_1", "usr_2", map[string]interface{}{
+		"fld_name":   "张三",
+		"fld_note":   "  VIP 客户  ",
+		"fld_status": "cho_1",
+		"fld_amount": 100,
+	}, now)
+
+	assert.Equal(t, search.SearchTypeRecord, index.Type)
+	assert.Equal(t, "张三", index.Title)
+	assert.Equal(t, "VIP 客户\n进行中", index.Content)
+	assert.Equal(t, "rec_1", index.SourceID)
+	assert.Equal(t, "record", index.SourceType)
+	assert.Equal(t, "usr_2", index.UserID)
+	assert.Equal(t, "spc_1", index.SpaceID)
+	assert.Equal(t, "bse_1", index.BaseID)
+	assert.Equal(t, "tbl_1", index.TableID)
+	assert.Equal(t, now, index.UpdatedTime)
+}
+
+func TestNewRecordIndex_EmptyPrimaryAndLongContent(t *testing.T) {
+	scope := newTestScope(t)
+	index := newRecordIndex(scope, scope.recordFields(), "rec_1", "usr_1", map[string]interface{}{
+		"fld_note": strings.Repeat("长", maxRecordContentLength+10),
+	}, time.Now())
+
+	assert.Equal(t, "rec_1", index.Title)
+	assert.Equal(t, maxRecordContentLength, len([]rune(index.Content)))
+}
+
+func TestNewFieldAndTableIndex(t *testing.T) {
+	scope := newTestScope(t)
+	scope.Description = "客户列表"
+	now := time.Now()
+
+	table := newTableIndex(scope, now)
+	assert.Equal(t, search.SearchTypeTable, table.Type)
+	assert.Equal(t, "客户", table.Title)
+	assert.Equal(t, "客户列表", table.Content)
+	assert.Equal(t, "tbl_1", table.SourceID)
+	assert.Equal(t, "bse_1", table.BaseID)
+
+	field := newFieldIndex(scope, scope.field("fld_status"), now)
+	assert.Equal(t, search.SearchTypeField, field.Type)
+	assert.Equal(t, "fld_status", field.SourceID)
+	assert.Equal(t, "fld_status", field.FieldID)
+	assert.Equal(t, valueobject.TypeSingleSelect, field.Metadata["field_type"])
+
+	assert.Nil(t, scope.field("fld_missing"))
+}
@@ -0,0 +1,533 @@
+package searchindex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	baseRepo "github.com/easyspace-ai/luckdb/server/internal/domain/base/repository"
+	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/search"
+	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/events"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// Config 搜索索引器配置
+type Config struct {
+	// BatchSize 每次刷新处理的最大任务数，队列达到该长度时立即刷新
+	BatchSize int `json:"batch_size"`
+	// FlushInterval 定时刷新间隔
+	FlushInterval time.Duration `json:"flush_interval"`
+	// MaxRetries 单个任务的最大重试次数，超过后删除对应条目
+	MaxRetries int `json:"max_retries"`
+	// RetryBaseDelay 首次重试等待时间，之后按2倍递增
+	RetryBaseDelay time.Duration `json:"retry_base_delay"`
+	// RetryMaxDelay 重试等待时间上限
+	RetryMaxDelay time.Duration `json:"retry_max_delay"`
+	// RecordPageSize 重建表格时每页读取的记录数
+	RecordPageSize int `json:"record_page_size"`
+}
+
+// DefaultConfig 默认配置
+func DefaultConfig() *Config {
+	return &Config{
+		BatchSize:      200,
+		FlushInterval:  500 * time.Millisecond,
+		MaxRetries:     5,
+		RetryBaseDelay: 1 * time.Second,
+		RetryMaxDelay:  1 * time.Minute,
+		RecordPageSize: 500,
+	}
+}
+
+// indexedEventTypes 索引器关心的业务事件
+var indexedEventTypes = []events.BusinessEventType{
+	events.BusinessEventTypeTableCreate,
+	events.BusinessEventTypeTableUpdate,
+	events.BusinessEventTypeTableDelete,
+	events.BusinessEventTypeFieldCreate,
+	events.BusinessEventTypeFieldUpdate,
+	events.BusinessEventTypeFieldDelete,
+	events.BusinessEventTypeRecordCreate,
+	events.BusinessEventTypeRecordUpdate,
+	events.BusinessEventTypeRecordDelete,
+	events.BusinessEventTypeCalculationUpdate,
+}
+
+// Indexer 增量搜索索引器
+//
+// 订阅表格、字段、记录的业务事件，按来源去重后批量写入或删除 search_indexes 条目：
+//   - 记录创建/更新/计算更新：重新读取记录写入条目，记录已不存在时删除
+//   - 字段创建：写入字段条目；字段更新/删除：同时重建所属表格的记录条目（选项名称、可搜索字段可能变化）
+//   - 表格创建/更新：写入表格条目（创建时连同字段）；表格删除：删除其下全部条目
+//
+// 失败的任务按指数退避重试，超过最大重试次数后删除对应条目——过期的结果比没有结果更糟。
+// 事件通道满时业务事件会被丢弃，可通过 RebuildBase 重建整个 Base 的索引
+type Indexer struct {
+	config *Config
+
+	searchRepo search.Repository
+	tableRepo  tableRepo.TableRepository
+	baseRepo   baseRepo.BaseRepository
+	fieldRepo  fieldRepo.FieldRepository
+	recordRepo recordRepo.RecordRepository
+
+	queue *taskQueue
+	wake  chan struct{}
+	jobs  *jobRegistry
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+	mu      sync.Mutex
+}
+
+// NewIndexer 创建搜索索引器
+func NewIndexer(
+	config *Config,
+	searchRepo search.Repository,
+	tableRepo tableRepo.TableRepository,
+	baseRepo baseRepo.BaseRepository,
+	fieldRepo fieldRepo.FieldRepository,
+	recordRepo recordRepo.RecordRepository,
+) *Indexer {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Indexer{
+		config:     config,
+		searchRepo: searchRepo,
+		tableRepo:  tableRepo,
+		baseRepo:   baseRepo,
+		fieldRepo:  fieldRepo,
+		recordRepo: recordRepo,
+		queue:      newTaskQueue(),
+		wake:       make(chan struct{}, 1),
+		jobs:       newJobRegistry(),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Start 订阅业务事件并启动后台刷新
+func (ix *Indexer) Start(subscriber events.BusinessEventSubscriber) error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if ix.started {
+		return nil
+	}
+
+	eventChan, err := subscriber.Subscribe(ix.ctx, indexedEventTypes)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe business events: %w", err)
+	}
+	ix.started = true
+
+	ix.wg.Add(2)
+	go ix.consume(eventChan)
+	go ix.run()
+
+	logger.Info("搜索索引器已启动",
+		logger.Int("batch_size", ix.config.BatchSize),
+		logger.Duration("flush_interval", ix.config.FlushInterval))
+	return nil
+}
+
+// Stop 停止索引器，尽量处理完队列中已到期的任务
+func (ix *Indexer) Stop() {
+	ix.cancel()
+	ix.wg.Wait()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	ix.flush(ctx)
+
+	logger.Info("搜索索引器已停止", logger.Int("pending_tasks", ix.queue.len()))
+}
+
+// consume 接收业务事件并转换为索引任务（只入队，不阻塞事件通道）
+func (ix *Indexer) consume(eventChan <-chan *events.BusinessEvent) {
+	defer ix.wg.Done()
+	for {
+		select {
+		case event, ok := <-eventChan:
+			if !ok {
+				return
+			}
+			for _, t := range tasksForEvent(event) {
+				if ix.queue.push(t) >= ix.config.BatchSize {
+					ix.signal()
+				}
+			}
+		case <-ix.ctx.Done():
+			return
+		}
+	}
+}
+
+// run 定时或队列满时刷新
+func (ix *Indexer) run() {
+	defer ix.wg.Done()
+	ticker := time.NewTicker(ix.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ix.wake:
+		case <-ix.ctx.Done():
+			return
+		}
+		ix.flush(ix.ctx)
+	}
+}
+
+func (ix *Indexer) signal() {
+	select {
+	case ix.wake <- struct{}{}:
+	default:
+	}
+}
+
+// tasksForEvent 业务事件对应的索引任务
+func tasksForEvent(event *events.BusinessEvent) []*task {
+	if event == nil || event.TableID == "" {
+		return nil
+	}
+
+	switch event.Type {
+	case events.BusinessEventTypeRecordCreate, events.BusinessEventTypeRecordUpdate, events.BusinessEventTypeCalculationUpdate:
+		if event.RecordID == "" {
+			return nil
+		}
+		return []*task{{kind: taskKindRecord, op: opUpsert, tableID: event.TableID, sourceID: event.RecordID}}
+	case events.BusinessEventTypeRecordDelete:
+		if event.RecordID == "" {
+			return nil
+		}
+		return []*task{{kind: taskKindRecord, op: opDelete, tableID: event.TableID, sourceID: event.RecordID}}
+
+	case events.BusinessEventTypeFieldCreate:
+		if event.FieldID == "" {
+			return nil
+		}
+		return []*task{{kind: taskKindField, op: opUpsert, tableID: event.TableID, sourceID: event.FieldID}}
+	case events.BusinessEventTypeFieldUpdate, events.BusinessEventTypeFieldDelete:
+		if event.FieldID == "" {
+			return nil
+		}
+		op := opUpsert
+		if event.Type == events.BusinessEventTypeFieldDelete {
+			op = opDelete
+		}
+		return []*task{
+			{kind: taskKindField, op: op, tableID: event.TableID, sourceID: event.FieldID},
+			{kind: taskKindTable, op: opReindex, tableID: event.TableID, sourceID: event.TableID},
+		}
+
+	case events.BusinessEventTypeTableCreate:
+		return []*task{{kind: taskKindTable, op: opReindex, tableID: event.TableID, sourceID: event.TableID}}
+	case events.BusinessEventTypeTableUpdate:
+		// 移到其他 Base 后表格、字段与记录条目的 base_id 都要改写
+		if data, ok := event.Data.(map[string]interface{}); ok && data["from_base_id"] != nil {
+			return []*task{{kind: taskKindTable, op: opReindex, tableID: event.TableID, sourceID: event.TableID}}
+		}
+		return []*task{{kind: taskKindTable, op: opUpsert, tableID: event.TableID, sourceID: event.TableID}}
+	case events.BusinessEventTypeTableDelete:
+		return []*task{{kind: taskKindTable, op: opDelete, tableID: event.TableID, sourceID: event.TableID}}
+	}
+	return nil
+}
+
+// ==================== 刷新 ====================
+
+// flush 处理一批到期任务：表格任务逐个处理，字段与记录按表格分组批量写入
+func (ix *Indexer) flush(ctx context.Context) {
+	tasks := ix.queue.take(time.Now(), ix.config.BatchSize)
+	if len(tasks) == 0 {
+		return
+	}
+
+	var tableTasks []*task
+	upserts := map[taskKind]map[string][]*task{taskKindRecord: {}, taskKindField: {}}
+	deletes := map[taskKind][]*task{}
+	for _, t := range tasks {
+		switch {
+		case t.kind == taskKindTable:
+			tableTasks = append(tableTasks, t)
+		case t.op == opDelete:
+			deletes[t.kind] = append(deletes[t.kind], t)
+		default:
+			upserts[t.kind][t.tableID] = append(upserts[t.kind][t.tableID], t)
+		}
+	}
+
+	scopes := make(map[string]*tableScope)
+	for _, t := range tableTasks {
+		ix.handleResult(ctx, []*task{t}, ix.processTable(ctx, t))
+	}
+	for kind, group := range deletes {
+		ix.handleResult(ctx, group, ix.searchRepo.DeleteIndexesBySources(ctx, string(kind), sourceIDs(group)))
+	}
+	for tableID, group := range upserts[taskKindField] {
+		ix.handleResult(ctx, group, ix.processFields(ctx, scopes, tableID, group))
+	}
+	for tableID, group := range upserts[taskKindRecord] {
+		ix.handleResult(ctx, group, ix.processRecords(ctx, scopes, tableID, group))
+	}
+
+	// 还有到期任务时继续刷新
+	if ix.queue.len() >= ix.config.BatchSize {
+		ix.signal()
+	}
+}
+
+// handleResult 失败的任务退避重试，超过最大重试次数时删除对应条目
+func (ix *Indexer) handleResult(ctx context.Context, tasks []*task, err error) {
+	if err == nil {
+		return
+	}
+	now := time.Now()
+	for _, t := range tasks {
+		if ix.queue.retry(t, now, ix.config) {
+			logger.Warn("搜索索引更新失败，稍后重试",
+				logger.String("kind", string(t.kind)),
+				logger.String("source_id", t.sourceID),
+				logger.Int("attempts", t.attempts),
+				logger.ErrorField(err))
+			continue
+		}
+		ix.giveUp(ctx, t, err)
+	}
+}
+
+// giveUp 放弃任务并删除对应条目，避免搜索返回过期结果
+func (ix *Indexer) giveUp(ctx context.Context, t *task, cause error) {
+	var err error
+	if t.kind == taskKindTable {
+		err = ix.searchRepo.DeleteIndexesByTable(ctx, t.tableID)
+	} else {
+		err = ix.searchRepo.DeleteIndexesBySources(ctx, string(t.kind), []string{t.sourceID})
+	}
+	logger.Error("搜索索引更新多次失败，已放弃并移除条目",
+		logger.String("kind", string(t.kind)),
+		logger.String("source_id", t.sourceID),
+		logger.String("table_id", t.tableID),
+		logger.Int("attempts", t.attempts),
+		logger.ErrorField(cause),
+		logger.Bool("removed", err == nil))
+}
+
+// processTable 处理表格任务
+func (ix *Indexer) processTable(ctx context.Context, t *task) error {
+	if t.op == opDelete {
+		return ix.searchRepo.DeleteIndexesByTable(ctx, t.tableID)
+	}
+
+	scope, err := ix.loadTableScope(ctx, t.tableID)
+	if err != nil {
+		return err
+	}
+	if scope == nil {
+		return ix.searchRepo.DeleteIndexesByTable(ctx, t.tableID)
+	}
+	if t.op == opReindex {
+		_, err := ix.reindexTable(ctx, scope)
+		return err
+	}
+	return ix.searchRepo.UpsertIndexes(ctx, []*search.SearchIndex{newTableIndex(scope, time.Now())})
+}
+
+// processFields 写入同一表格的字段条目，字段已不存在时删除
+func (ix *Indexer) processFields(ctx context.Context, scopes map[string]*tableScope, tableID string, tasks []*task) error {
+	scope, err := ix.cachedTableScope(ctx, scopes, tableID)
+	if err != nil {
+		return err
+	}
+	if scope == nil {
+		return ix.searchRepo.DeleteIndexesBySources(ctx, string(taskKindField), sourceIDs(tasks))
+	}
+
+	now := time.Now()
+	var indexes []*search.SearchIndex
+	var missing []string
+	for _, t := range tasks {
+		if field := scope.field(t.sourceID); field != nil {
+			indexes = append(indexes, newFieldIndex(scope, field, now))
+		} else {
+			missing = append(missing, t.sourceID)
+		}
+	}
+	if err := ix.searchRepo.UpsertIndexes(ctx, indexes); err != nil {
+		return err
+	}
+	return ix.searchRepo.DeleteIndexesBySources(ctx, string(taskKindField), missing)
+}
+
+// processRecords 重新读取同一表格的记录并写入条目，记录已不存在时删除
+func (ix *Indexer) processRecords(ctx context.Context, scopes map[string]*tableScope, tableID string, tasks []*task) error {
+	scope, err := ix.cachedTableScope(ctx, scopes, tableID)
+	if err != nil {
+		return err
+	}
+	ids := sourceIDs(tasks)
+	if scope == nil {
+		return ix.searchRepo.DeleteIndexesBySources(ctx, string(taskKindRecord), ids)
+	}
+
+	recordIDs := make([]valueobject.RecordID, len(ids))
+	for i, id := range ids {
+		recordIDs[i] = valueobject.NewRecordID(id)
+	}
+	records, err := ix.recordRepo.FindByIDs(ctx, tableID, recordIDs)
+	if err != nil {
+		return fmt.Errorf("failed to load records: %w", err)
+	}
+
+	now := time.Now()
+	fields := scope.recordFields()
+	found := make(map[string]bool, len(records))
+	indexes := make([]*search.SearchIndex, 0, len(records))
+	for _, record := range records {
+		if record == nil || record.IsDeleted() {
+			continue
+		}
+		recordID := record.ID().String()
+		found[recordID] = true
+		indexes = append(indexes, newRecordIndex(scope, fields, recordID, record.CreatedBy(), record.Data().ToMap(), now))
+	}
+	if err := ix.searchRepo.UpsertIndexes(ctx, indexes); err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, id := range ids {
+		if !found[id] {
+			missing = append(missing, id)
+		}
+	}
+	return ix.searchRepo.DeleteIndexesBySources(ctx, string(taskKindRecord), missing)
+}
+
+// reindexTable 重建表格、字段与全部记录的条目，并删除该表格内本次未写入的旧条目，返回写入的记录数
+func (ix *Indexer) reindexTable(ctx context.Context, scope *tableScope) (int64, error) {
+	start := time.Now().Truncate(time.Microsecond)
+
+	indexes := []*search.SearchIndex{newTableIndex(scope, start)}
+	for _, field := range scope.Fields {
+		if !field.IsDeleted() {
+			indexes = append(indexes, newFieldIndex(scope, field, start))
+		}
+	}
+	if err := ix.searchRepo.UpsertIndexes(ctx, indexes); err != nil {
+		return 0, err
+	}
+
+	fields := scope.recordFields()
+	projection := make([]string, len(fields))
+	for i, field := range fields {
+		projection[i] = field.ID().String()
+	}
+
+	var indexed int64
+	filter := recordRepo.RecordFilter{TableID: &scope.TableID, Projection: projection, Limit: ix.config.RecordPageSize}
+	for {
+		if err := ctx.Err(); err != nil {
+			return indexed, err
+		}
+		page, err := ix.recordRepo.ListPage(ctx, filter)
+		if err != nil {
+			return indexed, fmt.Errorf("failed to list records: %w", err)
+		}
+
+		now := time.Now()
+		indexes := make([]*search.SearchIndex, 0, len(page.Records))
+		for _, record := range page.Records {
+			indexes = append(indexes, newRecordIndex(scope, fields, record.ID().String(), record.CreatedBy(), record.Data().ToMap(), now))
+		}
+		if err := ix.searchRepo.UpsertIndexes(ctx, indexes); err != nil {
+			return indexed, err
+		}
+		indexed += int64(len(indexes))
+
+		if page.NextCursor == nil {
+			break
+		}
+		filter.Cursor = page.NextCursor
+	}
+
+	if err := ix.searchRepo.DeleteStaleIndexes(ctx, scope.BaseID, scope.TableID, start); err != nil {
+		return indexed, err
+	}
+	return indexed, nil
+}
+
+// cachedTableScope 同一次刷新内复用表格信息
+func (ix *Indexer) cachedTableScope(ctx context.Context, scopes map[string]*tableScope, tableID string) (*tableScope, error) {
+	if scope, ok := scopes[tableID]; ok {
+		return scope, nil
+	}
+	scope, err := ix.loadTableScope(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+	scopes[tableID] = scope
+	return scope, nil
+}
+
+// loadTableScope 加载表格、所属 Base 与字段；表格或 Base 已不存在时返回 nil
+func (ix *Indexer) loadTableScope(ctx context.Context, tableID string) (*tableScope, error) {
+	table, err := ix.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load table: %w", err)
+	}
+	if table == nil || table.IsDeleted() {
+		return nil, nil
+	}
+
+	base, err := ix.baseRepo.FindByID(ctx, table.BaseID())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load base: %w", err)
+	}
+
+	fields, err := ix.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fields: %w", err)
+	}
+
+	description := ""
+	if table.Description() != nil {
+		description = *table.Description()
+	}
+	return &tableScope{
+		TableID:     tableID,
+		Name:        table.Name().String(),
+		Description: description,
+		CreatedBy:   table.CreatedBy(),
+		BaseID:      base.ID,
+		SpaceID:     base.SpaceID,
+		Fields:      fields,
+	}, nil
+}
+
+// sourceIDs 任务的来源ID
+func sourceIDs(tasks []*task) []string {
+	ids := make([]string, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.sourceID
+	}
+	return ids
+}
@@ -0,0 +1,124 @@
+package searchindex
+
+import (
+	"sync"
+	"time"
+)
+
+// taskKind 索引任务的来源类型
+type taskKind string
+
+const (
+	taskKindRecord taskKind = "record"
+	taskKindField  taskKind = "field"
+	taskKindTable  taskKind = "table"
+)
+
+// taskOp 索引任务操作
+type taskOp int
+
+const (
+	opUpsert  taskOp = iota // 写入来源自身的条目
+	opReindex               // 表格：重建表格、字段与全部记录的条目
+	opDelete                // 删除来源的条目（表格为其下全部条目）
+)
+
+// task 待处理的索引任务
+type task struct {
+	kind      taskKind
+	op        taskOp
+	tableID   string
+	sourceID  string
+	attempts  int       // 已失败次数
+	notBefore time.Time // 重试退避：此时间之前不处理
+}
+
+// taskKey 同一来源只保留最新的任务
+type taskKey struct {
+	kind     taskKind
+	sourceID string
+}
+
+func (t *task) key() taskKey {
+	return taskKey{kind: t.kind, sourceID: t.sourceID}
+}
+
+// taskQueue 按来源去重的索引任务队列
+//
+// 同一来源的多次变更合并为一个任务，以最新事件为准；表格的重建任务不会被随后的写入任务降级
+type taskQueue struct {
+	mu      sync.Mutex
+	pending map[taskKey]*task
+}
+
+func newTaskQueue() *taskQueue {
+	return &taskQueue{pending: make(map[taskKey]*task)}
+}
+
+// push 加入任务，返回队列长度
+func (q *taskQueue) push(t *task) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if existing, ok := q.pending[t.key()]; ok && existing.op == opReindex && t.op == opUpsert {
+		t.op = opReindex
+	}
+	q.pending[t.key()] = t
+	return len(q.pending)
+}
+
+// take 取出到期的任务（最多 limit 个）
+func (q *taskQueue) take(now time.Time, limit int) []*task {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	tasks := make([]*task, 0, min(limit, len(q.pending)))
+	for key, t := range q.pending {
+		if len(tasks) >= limit {
+			break
+		}
+		if t.notBefore.After(now) {
+			continue
+		}
+		tasks = append(tasks, t)
+		delete(q.pending, key)
+	}
+	return tasks
+}
+
+// retry 失败的任务按指数退避放回队列；期间同一来源已有新任务时丢弃旧任务。
+// 超过最大重试次数返回 false，由调用方放弃该任务
+func (q *taskQueue) retry(t *task, now time.Time, config *Config) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if existing, ok := q.pending[t.key()]; ok {
+		if existing.op == opUpsert && t.op == opReindex {
+			existing.op = opReindex
+		}
+		return true
+	}
+	t.attempts++
+	if t.attempts > config.MaxRetries {
+		return false
+	}
+	t.notBefore = now.Add(backoff(t.attempts, config))
+	q.pending[t.key()] = t
+	return true
+}
+
+// len 队列中的任务数
+func (q *taskQueue) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// backoff 第 attempts 次失败后的等待时间：RetryBaseDelay * 2^(attempts-1)，不超过 RetryMaxDelay
+func backoff(attempts int, config *Config) time.Duration {
+	delay := config.RetryBaseDelay
+	for i := 1; i < attempts && delay < config.RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, config.RetryMaxDelay)
+}
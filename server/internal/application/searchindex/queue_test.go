@@ -0,0 +1,99 @@
+package searchindex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/easyspace-ai/luckdb/server/internal/events"
+)
+
+func TestTaskQueue_MergeBySource(t *testing.T) {
+	q := newTaskQueue()
+	q.push(&task{kind: taskKindRecord, op: opUpsert, tableID: "tbl_1", sourceID: "rec_1"})
+	q.push(&task{kind: taskKindRecord, op: opDelete, tableID: "tbl_1", sourceID: "rec_1"})
+	q.push(&task{kind: taskKindTable, op: opReindex, tableID: "tbl_1", sourceID: "tbl_1"})
+	assert.Equal(t, 2, q.push(&task{kind: taskKindTable, op: opUpsert, tableID: "tbl_1", sourceID: "tbl_1"}))
+
+	tasks := q.take(time.Now(), 10)
+	require.Len(t, tasks, 2)
+	ops := map[taskKind]taskOp{}
+	for _, task := range tasks {
+		ops[task.kind] = task.op
+	}
+	assert.Equal(t, opDelete, ops[taskKindRecord], "以最新事件为准")
+	assert.Equal(t, opReindex, ops[taskKindTable], "重建任务不被写入任务降级")
+	assert.Equal(t, 0, q.len())
+}
+
+func TestTaskQueue_RetryWithBackoff(t *testing.T) {
+	config := &Config{MaxRetries: 2, RetryBaseDelay: time.Second, RetryMaxDelay: 3 * time.Second}
+	q := newTaskQueue()
+	now := time.Now()
+
+	failed := &task{kind: taskKindRecord, op: opUpsert, tableID: "tbl_1", sourceID: "rec_1"}
+	require.True(t, q.retry(failed, now, config))
+	assert.Empty(t, q.take(now, 10), "退避期间不处理")
+
+	tasks := q.take(now.Add(time.Second), 10)
+	require.Len(t, tasks, 1)
+	require.True(t, q.retry(tasks[0], now, config))
+	assert.Equal(t, now.Add(2*time.Second), tasks[0].notBefore)
+
+	tasks = q.take(now.Add(2*time.Second), 10)
+	require.Len(t, tasks, 1)
+	assert.False(t, q.retry(tasks[0], now, config), "超过最大重试次数")
+	assert.Equal(t, 0, q.len())
+}
+
+func TestTaskQueue_RetryKeepsNewerTask(t *testing.T) {
+	config := DefaultConfig()
+	q := newTaskQueue()
+	q.push(&task{kind: taskKindTable, op: opUpsert, tableID: "tbl_1", sourceID: "tbl_1"})
+
+	failed := &task{kind: taskKindTable, op: opReindex, tableID: "tbl_1", sourceID: "tbl_1"}
+	require.True(t, q.retry(failed, time.Now(), config))
+
+	tasks := q.take(time.Now(), 10)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, opReindex, tasks[0].op)
+	assert.Equal(t, 0, tasks[0].attempts)
+}
+
+func TestBackoff(t *testing.T) {
+	config := &Config{RetryBaseDelay: time.Second, RetryMaxDelay: 5 * time.Second}
+	assert.Equal(t, time.Second, backoff(1, config))
+	assert.Equal(t, 2*time.Second, backoff(2, config))
+	assert.Equal(t, 4*time.Second, backoff(3, config))
+	assert.Equal(t, 5*time.Second, backoff(4, config))
+	assert.Equal(t, 5*time.Second, backoff(50, config))
+}
+
+func TestTasksForEvent(t *testing.T) {
+	tasks := tasksForEvent(&events.BusinessEvent{Type: events.BusinessEventTypeRecordUpdate, TableID: "tbl_1", RecordID: "rec_1"})
+	require.Len(t, tasks, 1)
+	assert.Equal(t, task{kind: taskKindRecord, op: opUpsert, tableID: "tbl_1", sourceID: "rec_1"}, *tasks[0])
+
+	tasks = tasksForEvent(&events.BusinessEvent{Type: events.BusinessEventTypeFieldDelete, TableID: "tbl_1", FieldID: "fld_1"})
+	require.Len(t, tasks, 2)
+	assert.Equal(t, opDelete, tasks[0].op)
+	assert.Equal(t, task{kind: taskKindTable, op: opReindex, tableID: "tbl_1", sourceID: "tbl_1"}, *tasks[1])
+
+	tasks = tasksForEvent(&events.BusinessEvent{Type: events.BusinessEventTypeTableUpdate, TableID: "tbl_1", Data: map[string]interface{}{"base_id": "bse_1"}})
+	require.Len(t, tasks, 1)
+	assert.Equal(t, opUpsert, tasks[0].op)
+
+	tasks = tasksForEvent(&events.BusinessEvent{Type: events.BusinessEventTypeTableUpdate, TableID: "tbl_1",
+		Data: map[string]interface{}{"base_id": "bse_2", "from_base_id": "bse_1"}})
+	require.Len(t, tasks, 1)
+	assert.Equal(t, task{kind: taskKindTable, op: opReindex, tableID: "tbl_1", sourceID: "tbl_1"}, *tasks[0])
+
+	tasks = tasksForEvent(&events.BusinessEvent{Type: events.BusinessEventTypeTableDelete, TableID: "tbl_1"})
+	require.Len(t, tasks, 1)
+	assert.Equal(t, opDelete, tasks[0].op)
+
+	assert.Empty(t, tasksForEvent(&events.BusinessEvent{Type: events.BusinessEventTypeViewUpdate, TableID: "tbl_1"}))
+	assert.Empty(t, tasksForEvent(&events.BusinessEvent{Type: events.BusinessEventTypeRecordCreate, TableID: "tbl_1"}))
+}
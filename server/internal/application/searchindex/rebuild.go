@@ -0,0 +1,244 @@
+package searchindex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// jobRetention 已结束的重建任务保留时长
+const jobRetention = time.Hour
+
+// JobStatus 重建任务状态
+type JobStatus string
+
+const (
+	JobStatusRunning   JobStatus = "running"
+	JobStatusCompleted JobStatus = "completed"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+// RebuildJob Base 索引重建任务及进度
+type RebuildJob struct {
+	ID             string     `json:"id"`
+	BaseID         string     `json:"base_id"`
+	Status         JobStatus  `json:"status"`
+	TotalTables    int        `json:"total_tables"`
+	IndexedTables  int        `json:"indexed_tables"`
+	IndexedRecords int64      `json:"indexed_records"`
+	Progress       float64    `json:"progress"` // 0-100，按已完成的表格数计算
+	CurrentTableID string     `json:"current_table_id,omitempty"`
+	Error          string     `json:"error,omitempty"`
+	RequestedBy    string     `json:"requested_by"`
+	StartedTime    time.Time  `json:"started_time"`
+	FinishedTime   *time.Time `json:"finished_time,omitempty"`
+}
+
+// jobRegistry 内存中的重建任务（进程重启后丢失，任务本身可重复执行）
+type jobRegistry struct {
+	mu         sync.RWMutex
+	jobs       map[string]*RebuildJob
+	requesters map[string]map[string]struct{} // 任务ID -> 发起过该任务的用户
+}
+
+func newJobRegistry() *jobRegistry {
+	return &jobRegistry{
+		jobs:       make(map[string]*RebuildJob),
+		requesters: make(map[string]map[string]struct{}),
+	}
+}
+
+// start 创建任务；同一 Base 已有运行中的任务时返回该任务，并允许该用户查看任务进度
+// RequestedBy 始终为最初发起任务的用户
+func (r *jobRegistry) start(baseID, userID string, now time.Time) (job RebuildJob, created bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, existing := range r.jobs {
+		if existing.Status == JobStatusRunning && existing.BaseID == baseID {
+			r.requesters[id][userID] = struct{}{}
+			return *existing, false
+		}
+		if existing.FinishedTime != nil && now.Sub(*existing.FinishedTime) > jobRetention {
+			delete(r.jobs, id)
+			delete(r.requesters, id)
+		}
+	}
+
+	job = RebuildJob{
+		ID:          utils.GenerateNanoID(16),
+		BaseID:      baseID,
+		Status:      JobStatusRunning,
+		RequestedBy: userID,
+		StartedTime: now,
+	}
+	stored := job
+	r.jobs[job.ID] = &stored
+	r.requesters[job.ID] = map[string]struct{}{userID: {}}
+	return job, true
+}
+
+// update 修改任务状态
+func (r *jobRegistry) update(jobID string, fn func(job *RebuildJob)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if job, ok := r.jobs[jobID]; ok {
+		fn(job)
+	}
+}
+
+// get 获取任务快照；只有发起过该任务的用户可以查看
+func (r *jobRegistry) get(jobID, userID string) (RebuildJob, bool) {
+	if userID == "" {
+		return RebuildJob{}, false
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	job, ok := r.jobs[jobID]
+	if !ok {
+		return RebuildJob{}, false
+	}
+	if _, requested := r.requesters[jobID][userID]; !requested {
+		return RebuildJob{}, false
+	}
+	return *job, true
+}
+
+// RebuildBase 在后台重建 Base 内全部表格、字段与记录的索引，立即返回任务
+//
+// 同一 Base 已有运行中的重建任务时返回该任务。重建期间搜索不中断：条目按来源覆盖写入，
+// 完成后删除本次未写入的旧条目（已删除的表格、字段与记录）
+func (ix *Indexer) RebuildBase(ctx context.Context, baseID, userID string) (*RebuildJob, error) {
+	if userID == "" {
+		return nil, pkgerrors.ErrUnauthorized
+	}
+	if _, err := ix.baseRepo.FindByID(ctx, baseID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkgerrors.ErrNotFound.WithDetails(map[string]interface{}{
+				"resource": "base",
+				"id":       baseID,
+			})
+		}
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(err.Error())
+	}
+
+	job, created := ix.jobs.start(baseID, userID, time.Now())
+	if created {
+		ix.wg.Add(1)
+		go func() {
+			defer ix.wg.Done()
+			ix.runRebuild(ix.ctx, job.ID, baseID)
+		}()
+	}
+	return &job, nil
+}
+
+// GetRebuildJob 获取用户发起的重建任务进度，任务不存在或不是该用户发起时返回 false
+func (ix *Indexer) GetRebuildJob(jobID, userID string) (*RebuildJob, bool) {
+	job, ok := ix.jobs.get(jobID, userID)
+	if !ok {
+		return nil, false
+	}
+	return &job, true
+}
+
+// runRebuild 执行重建任务，单个表格失败时按退避重试
+func (ix *Indexer) runRebuild(ctx context.Context, jobID, baseID string) {
+	start := time.Now().Truncate(time.Microsecond)
+	err := ix.rebuildBase(ctx, jobID, baseID, start)
+
+	finished := time.Now()
+	ix.jobs.update(jobID, func(job *RebuildJob) {
+		job.FinishedTime = &finished
+		job.CurrentTableID = ""
+		if err != nil {
+			job.Status = JobStatusFailed
+			job.Error = err.Error()
+			return
+		}
+		job.Status = JobStatusCompleted
+		job.Progress = 100
+	})
+
+	if err != nil {
+		logger.Error("重建 Base 搜索索引失败",
+			logger.String("job_id", jobID),
+			logger.String("base_id", baseID),
+			logger.ErrorField(err))
+		return
+	}
+	logger.Info("重建 Base 搜索索引完成",
+		logger.String("job_id", jobID),
+		logger.String("base_id", baseID),
+		logger.Duration("elapsed", finished.Sub(start)))
+}
+
+func (ix *Indexer) rebuildBase(ctx context.Context, jobID, baseID string, start time.Time) error {
+	tables, err := ix.tableRepo.GetByBaseID(ctx, baseID)
+	if err != nil {
+		return fmt.Errorf("failed to list tables: %w", err)
+	}
+	ix.jobs.update(jobID, func(job *RebuildJob) { job.TotalTables = len(tables) })
+
+	for i, table := range tables {
+		tableID := table.ID().String()
+		ix.jobs.update(jobID, func(job *RebuildJob) { job.CurrentTableID = tableID })
+
+		indexed, err := ix.rebuildTableWithRetry(ctx, tableID)
+		if err != nil {
+			return fmt.Errorf("table %s: %w", tableID, err)
+		}
+
+		done := i + 1
+		ix.jobs.update(jobID, func(job *RebuildJob) {
+			job.IndexedTables = done
+			job.IndexedRecords += indexed
+			job.Progress = float64(done) * 100 / float64(len(tables))
+		})
+	}
+
+	// 清理已删除表格的条目
+	if err := ix.searchRepo.DeleteStaleIndexes(ctx, baseID, "", start); err != nil {
+		return fmt.Errorf("failed to clean stale indexes: %w", err)
+	}
+	return nil
+}
+
+// rebuildTableWithRetry 重建单个表格，失败时按退避重试
+func (ix *Indexer) rebuildTableWithRetry(ctx context.Context, tableID string) (int64, error) {
+	for attempt := 1; ; attempt++ {
+		scope, err := ix.loadTableScope(ctx, tableID)
+		if err == nil && scope == nil {
+			// 重建期间表格被删除
+			return 0, nil
+		}
+		var indexed int64
+		if err == nil {
+			indexed, err = ix.reindexTable(ctx, scope)
+		}
+		if err == nil || attempt > ix.config.MaxRetries || ctx.Err() != nil {
+			return indexed, err
+		}
+
+		delay := backoff(attempt, ix.config)
+		logger.Warn("重建表格搜索索引失败，稍后重试",
+			logger.String("table_id", tableID),
+			logger.Int("attempts", attempt),
+			logger.Duration("delay", delay),
+			logger.ErrorField(err))
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package searchindex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobRegistry_Access(t *testing.T) {
+	r := newJobRegistry()
+	now := time.Now()
+
+	job, created := r.start("bse_1", "usr_a", now)
+	require.True(t, created)
+	assert.Equal(t, "usr_a", job.RequestedBy)
+
+	t.Run("发起任务的用户可以查看", func(t *testing.T) {
+		got, ok := r.get(job.ID, "usr_a")
+		require.True(t, ok)
+		assert.Equal(t, job.ID, got.ID)
+	})
+
+	t.Run("其他用户与空用户不能查看", func(t *testing.T) {
+		_, ok := r.get(job.ID, "usr_b")
+		assert.False(t, ok)
+		_, ok = r.get(job.ID, "")
+		assert.False(t, ok)
+	})
+
+	t.Run("加入运行中任务的用户可以查看", func(t *testing.T) {
+		joined, created := r.start("bse_1", "usr_b", now)
+		assert.False(t, created)
+		assert.Equal(t, job.ID, joined.ID)
+		assert.Equal(t, "usr_a", joined.RequestedBy)
+
+		_, ok := r.get(job.ID, "usr_b")
+		assert.True(t, ok)
+	})
+
+	t.Run("过期任务连同发起用户一起清理", func(t *testing.T) {
+		finished := now
+		r.update(job.ID, func(job *RebuildJob) {
+			job.Status = JobStatusCompleted
+			job.FinishedTime = &finished
+		})
+		_, created := r.start("bse_2", "usr_c", now.Add(2*jobRetention))
+		require.True(t, created)
+
+		_, ok := r.get(job.ID, "usr_a")
+		assert.False(t, ok)
+		assert.NotContains(t, r.requesters, job.ID)
+	})
+}
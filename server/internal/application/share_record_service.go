@@ -0,0 +1,93 @@
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/share"
+	"github.com/easyspace-ai/luckdb/server/pkg/authctx"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// shareAnonymousUser 匿名提交分享表单时记录的创建人
+const shareAnonymousUser = "anonymous"
+
+// ShareRecordService 分享视图使用的记录服务（实现 share.RecordService）
+type ShareRecordService struct {
+	recordService *RecordService
+	recordRepo    recordRepo.RecordRepository
+}
+
+var _ share.RecordService = (*ShareRecordService)(nil)
+
+// NewShareRecordService 创建分享视图记录服务
+func NewShareRecordService(recordService *RecordService, recordRepo recordRepo.RecordRepository) *ShareRecordService {
+	return &ShareRecordService{
+		recordService: recordService,
+		recordRepo:    recordRepo,
+	}
+}
+
+// CreateRecord 表单提交创建记录，与 REST 创建走同一流程（默认值、必填校验、广播）
+//
+// 登录用户提交时以提交者为创建人（用户字段的“当前用户”默认值取提交者），匿名提交记为 anonymous
+func (s *ShareRecordService) CreateRecord(ctx context.Context, tableID string, fields map[string]interface{}, typecast bool) (string, map[string]interface{}, error) {
+	userID, ok := authctx.UserFrom(ctx)
+	if !ok {
+		userID = shareAnonymousUser
+	}
+	resp, err := s.recordService.CreateRecord(ctx, dto.CreateRecordRequest{
+		TableID:  tableID,
+		Data:     fields,
+		Typecast: typecast,
+	}, userID)
+	if err != nil {
+		return "", nil, err
+	}
+	return resp.ID, resp.Data, nil
+}
+
+// GetRecordsByIDs 批量获取记录
+func (s *ShareRecordService) GetRecordsByIDs(ctx context.Context, tableID string, recordIDs []string) ([]interface{}, error) {
+	ids := make([]valueobject.RecordID, len(recordIDs))
+	for i, id := range recordIDs {
+		ids[i] = valueobject.NewRecordID(id)
+	}
+	records, err := s.recordRepo.FindByIDs(ctx, tableID, ids)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找记录失败: %v", err))
+	}
+	result := make([]interface{}, 0, len(records))
+	for _, record := range records {
+		result = append(result, dto.FromRecordEntity(record))
+	}
+	return result, nil
+}
+
+// GetLinkedRecords 获取关联字段单元格中的记录（{id, title}）
+func (s *ShareRecordService) GetLinkedRecords(ctx context.Context, tableID, recordID, fieldID string) ([]map[string]interface{}, error) {
+	record, err := s.recordRepo.FindByTableAndID(ctx, tableID, valueobject.NewRecordID(recordID))
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找记录失败: %v", err))
+	}
+	if record == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("记录不存在")
+	}
+
+	linked := make([]map[string]interface{}, 0)
+	value, _ := record.Data().Get(fieldID)
+	switch v := value.(type) {
+	case map[string]interface{}:
+		linked = append(linked, v)
+	case []interface{}:
+		for _, item := range v {
+			if m, ok := item.(map[string]interface{}); ok {
+				linked = append(linked, m)
+			}
+		}
+	}
+	return linked, nil
+}
@@ -0,0 +1,593 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldService "github.com/easyspace-ai/luckdb/server/internal/domain/fields/service"
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordEntity "github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	tableEntity "github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/events"
+	"github.com/easyspace-ai/luckdb/server/pkg/authctx"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// 移动表格时处理与其他表格之间关联的方式
+//
+// 移动后仍可解析的关联保留：关联表格已在目标 Base（移动后成为同 Base 关联），
+// 或关联已通过 LinkOptions.BaseID 声明为跨 Base 关联，两侧字段的 BaseID 随之改写；
+// 其余关联的外键列与中间表留在原 Base 的 Schema 中无法解析，
+// 连同依赖它们的查找、汇总与计数字段转换为单行文本或删除
+const (
+	MoveLinkModeConvert = "convert" // 转换为同名单行文本字段，保留单元格的显示值
+	MoveLinkModeDrop    = "drop"    // 直接删除
+)
+
+const moveRecordChunkSize = 500 // 读取、写回转换字段显示值时每批的记录数
+
+// TableMoveAuthorizer 移动表格的权限检查（由 PermissionServiceV2 实现）
+type TableMoveAuthorizer interface {
+	CanDeleteTable(ctx context.Context, userID, tableID string) bool
+	CanCreateTablesInBase(ctx context.Context, userID, baseID string) bool
+}
+
+// moveAffectedTable 移动表格时需要转换或删除字段的表格
+type moveAffectedTable struct {
+	tableID string
+	fields  []*fieldEntity.Field         // 依赖字段在前、关联字段在后，按此顺序删除
+	texts   map[string]map[string]string // recordID -> fieldID -> 显示值（convert 模式）
+}
+
+// moveRelinkedField 移动后保留的关联字段
+type moveRelinkedField struct {
+	field     *fieldEntity.Field
+	baseID    string // 移动后的 LinkOptions.BaseID，关联表格在同一 Base 时为空
+	oldBaseID string
+}
+
+// MoveTable 把表格移到同一空间下的另一个Base
+//
+// 物理表（连同表格内自关联的中间表）整体移到目标 Base 的 Schema，记录、评论与历史随之保留；
+// 视图与分享按表格和视图ID关联，权限继承所在 Base，移动后无需改写。
+// 移动后仍可解析的关联改写两侧的 LinkOptions.BaseID，其余关联按 LinkMode 转换为单行文本或删除，
+// 引用它们的视图配置一并清理；这些改动在物理表移动与元数据保存成功之后进行，
+// 处理失败时把表格移回原 Base 并返回错误
+func (s *TableService) MoveTable(ctx context.Context, tableID string, req dto.MoveTableRequest, userID string) (*dto.MoveTableResponse, error) {
+	// 1. 校验表格、目标 Base 与权限
+	table, err := s.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找表格失败: %v", err))
+	}
+	if table == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("表格不存在")
+	}
+	fromBaseID := table.BaseID()
+	if req.BaseID == fromBaseID {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("表格已在目标Base中")
+	}
+
+	linkMode := req.LinkMode
+	if linkMode == "" {
+		linkMode = MoveLinkModeConvert
+	}
+	if linkMode != MoveLinkModeConvert && linkMode != MoveLinkModeDrop {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("不支持的关联处理方式: %s", req.LinkMode))
+	}
+
+	fromBase, err := s.baseRepo.FindByID(ctx, fromBaseID)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找Base失败: %v", err))
+	}
+	toBase, err := s.baseRepo.FindByID(ctx, req.BaseID)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找目标Base失败: %v", err))
+	}
+	if fromBase == nil || toBase == nil {
+		return nil, pkgerrors.ErrNotFound.WithDetails("Base不存在")
+	}
+	if fromBase.SpaceID != toBase.SpaceID {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails("只能在同一空间的Base之间移动表格")
+	}
+
+	if s.moveAuthorizer != nil &&
+		(!s.moveAuthorizer.CanDeleteTable(ctx, userID, tableID) || !s.moveAuthorizer.CanCreateTablesInBase(ctx, userID, req.BaseID)) {
+		return nil, pkgerrors.ErrForbidden.WithDetails("需要原Base的删除表格权限与目标Base的创建表格权限")
+	}
+
+	exists, err := s.tableRepo.ExistsByNameInBase(ctx, req.BaseID, table.Name(), nil)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("检查表格名称失败: %v", err))
+	}
+	if exists {
+		return nil, pkgerrors.ErrConflict.WithDetails("目标Base中已存在同名表格")
+	}
+
+	// 2. 找出与其他表格之间的关联及依赖字段
+	ownFields, err := s.fieldService.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取字段列表失败: %v", err))
+	}
+	affected, relinked, err := s.collectMoveAffectedFields(ctx, tableID, req.BaseID, ownFields)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = authctx.WithUser(ctx, userID)
+	resp := &dto.MoveTableResponse{FromBaseID: fromBaseID}
+
+	// 3. 读取待转换字段的显示值（只读，对称字段删除后可能已无法渲染）
+	if linkMode == MoveLinkModeConvert {
+		for _, t := range affected {
+			if err := s.snapshotMoveTexts(ctx, t); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// 4. 移动物理表（连同表格内自关联与保留关联的中间表）并更新表格元数据，
+	// 失败时移回物理表，关联字段尚未改动
+	physical := []string{tableID}
+	addJunction := func(field *fieldEntity.Field) {
+		name := movedJunctionTable(field, tableID)
+		if name != "" && !slices.Contains(physical, name) {
+			physical = append(physical, name)
+		}
+	}
+	for _, field := range ownFields {
+		if link := moveLinkOptions(field); link != nil && link.LinkedTableID == tableID {
+			addJunction(field)
+		}
+	}
+	for _, r := range relinked {
+		if r.field.TableID() == tableID {
+			addJunction(r.field)
+		}
+	}
+	var fromDBTableName string
+	if name := table.DBTableName(); name != nil {
+		fromDBTableName = *name
+	}
+	if err := table.MoveToBase(req.BaseID, s.dbProvider.GenerateTableName(req.BaseID, tableID)); err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
+	if err := s.movePhysicalTables(ctx, physical, fromBaseID, req.BaseID); err != nil {
+		return nil, err
+	}
+	if err := s.tableRepo.Save(ctx, table); err != nil {
+		s.restorePhysicalTables(ctx, physical, req.BaseID, fromBaseID)
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存表格失败: %v", err))
+	}
+
+	// 5. 移动成功后再改写保留关联的 BaseID、转换或删除其余关联字段（字段按表格元数据定位物理列），
+	// 失败时把表格移回原 Base；已完成转换或删除的字段无法恢复
+	removed := make(map[string]bool)
+	err = s.relinkMoveFields(ctx, relinked)
+	for i := 0; err == nil && i < len(affected); i++ {
+		err = s.removeMoveFields(ctx, affected[i], linkMode, userID, resp, removed)
+	}
+	if err != nil {
+		logger.Error("移动表格后处理关联字段失败，表格移回原Base",
+			logger.String("table_id", tableID),
+			logger.String("from_base_id", fromBaseID),
+			logger.String("to_base_id", req.BaseID),
+			logger.Strings("removed_fields", slices.Sorted(maps.Keys(removed))),
+			logger.ErrorField(err))
+		s.rollbackMoveTable(ctx, table, physical, fromBaseID, fromDBTableName, req.BaseID, relinked)
+		return nil, err
+	}
+	resp.Warnings = append(resp.Warnings, s.cleanMoveReferences(ctx, affected, removed)...)
+
+	if s.businessEvents != nil {
+		data := map[string]interface{}{"base_id": req.BaseID, "from_base_id": fromBaseID}
+		if err := s.businessEvents.PublishTableEvent(ctx, events.BusinessEventTypeTableUpdate, tableID, data, userID); err != nil {
+			logger.Warn("发布表格业务事件失败",
+				logger.String("event_type", string(events.BusinessEventTypeTableUpdate)),
+				logger.String("table_id", tableID),
+				logger.ErrorField(err))
+		}
+	}
+
+	logger.Info("表格移动成功",
+		logger.String("table_id", tableID),
+		logger.String("from_base_id", fromBaseID),
+		logger.String("to_base_id", req.BaseID),
+		logger.String("link_mode", linkMode),
+		logger.Int("converted_fields", len(resp.ConvertedFields)),
+		logger.Int("removed_fields", len(resp.RemovedFields)))
+
+	resp.Table, err = s.GetTable(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// collectMoveAffectedFields 收集移动表格与其他表格之间的关联字段（两侧都算）：
+// 移动后仍可解析的关联返回新的 BaseID，其余关联连同各表格中依赖它们的查找、汇总与计数字段需要转换或删除
+func (s *TableService) collectMoveAffectedFields(ctx context.Context, tableID, toBaseID string, ownFields []*fieldEntity.Field) ([]*moveAffectedTable, []*moveRelinkedField, error) {
+	type moveLink struct {
+		field   *fieldEntity.Field
+		otherID string // 关联另一侧的表格（不是移动的表格）
+	}
+	var candidates []moveLink
+	for _, field := range ownFields {
+		if link := moveLinkOptions(field); link != nil && link.LinkedTableID != tableID {
+			candidates = append(candidates, moveLink{field: field, otherID: link.LinkedTableID})
+		}
+	}
+	incoming, err := s.fieldService.fieldRepo.FindLinkFieldsToTable(ctx, tableID)
+	if err != nil {
+		return nil, nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找指向表格的关联字段失败: %v", err))
+	}
+	for _, field := range incoming {
+		if field.TableID() != tableID && !field.IsDeleted() {
+			candidates = append(candidates, moveLink{field: field, otherID: field.TableID()})
+		}
+	}
+
+	otherBases := make(map[string]string) // tableID -> 所在 Base
+	for _, c := range candidates {
+		if _, ok := otherBases[c.otherID]; ok {
+			continue
+		}
+		other, err := s.tableRepo.GetByID(ctx, c.otherID)
+		if err != nil {
+			return nil, nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找关联表格失败: %v", err))
+		}
+		otherBases[c.otherID] = ""
+		if other != nil {
+			otherBases[c.otherID] = other.BaseID()
+		}
+	}
+
+	// 关联表格已在目标 Base 或关联声明了 BaseID 时保留，对称字段随主字段一起保留
+	keep := make(map[string]bool, len(candidates))
+	for _, c := range candidates {
+		keep[c.field.ID().String()] = otherBases[c.otherID] == toBaseID || moveLinkOptions(c.field).BaseID != ""
+	}
+	for _, c := range candidates {
+		id, symmetricID := c.field.ID().String(), moveLinkOptions(c.field).SymmetricFieldID
+		if symmetricID != "" && (keep[id] || keep[symmetricID]) {
+			keep[id], keep[symmetricID] = true, true
+		}
+	}
+
+	links := make(map[string][]*fieldEntity.Field) // tableID -> 需要转换或删除的关联字段
+	var order []string
+	var relinked []*moveRelinkedField
+	for _, c := range candidates {
+		if keep[c.field.ID().String()] {
+			relinked = append(relinked, &moveRelinkedField{
+				field:     c.field,
+				baseID:    moveLinkBaseID(c.field, tableID, toBaseID, otherBases[c.otherID]),
+				oldBaseID: moveLinkOptions(c.field).BaseID,
+			})
+			continue
+		}
+		if _, ok := links[c.field.TableID()]; !ok {
+			order = append(order, c.field.TableID())
+		}
+		links[c.field.TableID()] = append(links[c.field.TableID()], c.field)
+	}
+
+	affected := make([]*moveAffectedTable, 0, len(order))
+	for _, id := range order {
+		linkIDs := make(map[string]bool)
+		for _, field := range links[id] {
+			linkIDs[field.ID().String()] = true
+		}
+
+		fields := ownFields
+		if id != tableID {
+			fields, err = s.fieldService.fieldRepo.FindByTableID(ctx, id)
+			if err != nil {
+				return nil, nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取字段列表失败: %v", err))
+			}
+		}
+		t := &moveAffectedTable{tableID: id}
+		for _, field := range fields {
+			if linkIDs[moveDependencyLinkID(field)] {
+				t.fields = append(t.fields, field)
+			}
+		}
+		t.fields = append(t.fields, links[id]...)
+		affected = append(affected, t)
+	}
+	return affected, relinked, nil
+}
+
+// snapshotMoveTexts 按键集分页逐批读取待转换字段的显示值
+func (s *TableService) snapshotMoveTexts(ctx context.Context, t *moveAffectedTable) error {
+	fieldIDs := make([]string, 0, len(t.fields))
+	for _, field := range t.fields {
+		fieldIDs = append(fieldIDs, field.ID().String())
+	}
+	filter := recordRepo.RecordFilter{
+		TableID:    &t.tableID,
+		Projection: fieldIDs,
+		Limit:      moveRecordChunkSize,
+	}
+
+	t.texts = make(map[string]map[string]string)
+	for {
+		page, err := s.recordRepo.ListPage(ctx, filter)
+		if err != nil {
+			return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("读取记录失败: %v", err))
+		}
+		for _, record := range page.Records {
+			for _, field := range t.fields {
+				value, ok := record.Data().Get(field.ID().String())
+				if !ok {
+					continue
+				}
+				if text := fieldService.FormatCellText(field, value); text != "" {
+					if t.texts[record.ID().String()] == nil {
+						t.texts[record.ID().String()] = make(map[string]string)
+					}
+					t.texts[record.ID().String()][field.ID().String()] = text
+				}
+			}
+		}
+		if page.NextCursor == nil || len(page.Records) == 0 {
+			return nil
+		}
+		filter.Cursor = page.NextCursor
+	}
+}
+
+// relinkMoveFields 改写保留关联两侧字段的 LinkOptions.BaseID
+func (s *TableService) relinkMoveFields(ctx context.Context, relinked []*moveRelinkedField) error {
+	for _, r := range relinked {
+		link := moveLinkOptions(r.field)
+		if link.BaseID == r.baseID {
+			continue
+		}
+		link.BaseID = r.baseID
+		if err := s.fieldService.fieldRepo.Save(ctx, r.field); err != nil {
+			return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("更新关联字段失败: %v", err))
+		}
+		if s.fieldService.broadcaster != nil {
+			s.fieldService.broadcaster.BroadcastFieldUpdate(r.field.TableID(), r.field)
+		}
+	}
+	return nil
+}
+
+// removeMoveFields 删除表格中的关联与依赖字段，convert 模式下新建同名单行文本字段并写回显示值
+func (s *TableService) removeMoveFields(ctx context.Context, t *moveAffectedTable, linkMode, userID string, resp *dto.MoveTableResponse, removed map[string]bool) error {
+	converted := make(map[string]string) // 原字段ID -> 单行文本字段ID
+	for _, field := range t.fields {
+		fieldID := field.ID().String()
+		// 一侧的关联字段删除时对称字段被一并删除
+		current, err := s.fieldService.fieldRepo.FindByID(ctx, field.ID())
+		if err != nil {
+			return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找字段失败: %v", err))
+		}
+		if current != nil {
+			if err := s.fieldService.DeleteField(ctx, fieldID); err != nil {
+				return err
+			}
+		}
+		removed[fieldID] = true
+
+		change := dto.MoveTableFieldChange{TableID: t.tableID, FieldID: fieldID, Name: field.Name().String()}
+		if linkMode != MoveLinkModeConvert {
+			resp.RemovedFields = append(resp.RemovedFields, change)
+			continue
+		}
+		created, err := s.fieldService.CreateField(ctx, dto.CreateFieldRequest{
+			TableID: t.tableID,
+			Name:    field.Name().String(),
+			Type:    fieldValueObject.TypeSingleLineText,
+		}, userID)
+		if err != nil {
+			return err
+		}
+		converted[fieldID] = created.ID
+		change.NewFieldID = created.ID
+		resp.ConvertedFields = append(resp.ConvertedFields, change)
+	}
+
+	if len(converted) == 0 || len(t.texts) == 0 {
+		return nil
+	}
+
+	// 只读取有显示值的记录，逐批写回
+	recordIDs := slices.Sorted(maps.Keys(t.texts))
+	for start := 0; start < len(recordIDs); start += moveRecordChunkSize {
+		ids := make([]recordValueObject.RecordID, 0, moveRecordChunkSize)
+		for _, id := range recordIDs[start:min(start+moveRecordChunkSize, len(recordIDs))] {
+			ids = append(ids, recordValueObject.NewRecordID(id))
+		}
+		records, err := s.recordRepo.FindByIDs(ctx, t.tableID, ids)
+		if err != nil {
+			return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("读取记录失败: %v", err))
+		}
+		changed := make([]*recordEntity.Record, 0, len(records))
+		for _, record := range records {
+			texts := t.texts[record.ID().String()]
+			values := make(map[string]interface{}, len(texts))
+			for fieldID, text := range texts {
+				values[converted[fieldID]] = text
+			}
+			data, err := recordValueObject.NewRecordData(values)
+			if err != nil {
+				return pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+			}
+			if err := record.Update(data, userID); err != nil {
+				return pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+			}
+			changed = append(changed, record)
+		}
+		if len(changed) > 0 {
+			if err := s.recordRepo.BatchSave(ctx, changed); err != nil {
+				return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("写入转换后的单元格失败: %v", err))
+			}
+		}
+	}
+	return nil
+}
+
+// cleanMoveReferences 移除视图中对已删除字段的引用，返回引用了这些字段的公式
+func (s *TableService) cleanMoveReferences(ctx context.Context, affected []*moveAffectedTable, removed map[string]bool) []string {
+	removedIDs := make([]string, 0, len(removed))
+	for id := range removed {
+		removedIDs = append(removedIDs, id)
+	}
+
+	var warnings []string
+	for _, t := range affected {
+		if s.viewService != nil {
+			views, err := s.viewService.viewRepo.FindByTableID(ctx, t.tableID)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("表格 %s 的视图未能清理: %v", t.tableID, err))
+			}
+			for _, view := range views {
+				if !view.RemoveFieldReferences(removedIDs...) {
+					continue
+				}
+				if err := s.viewService.viewRepo.Update(ctx, view); err != nil {
+					warnings = append(warnings, fmt.Sprintf("视图 %s 未能清理: %v", view.Name(), err))
+				}
+			}
+		}
+
+		fields, err := s.fieldService.fieldRepo.FindByTableID(ctx, t.tableID)
+		if err != nil {
+			continue
+		}
+		for _, field := range fields {
+			options := field.Options()
+			if options == nil || options.Formula == nil {
+				continue
+			}
+			for _, id := range removedIDs {
+				if strings.Contains(options.Formula.Expression, id) {
+					warnings = append(warnings, fmt.Sprintf("公式字段 %s 引用了已删除的字段，需要手动修改", field.Name().String()))
+					break
+				}
+			}
+		}
+	}
+	return warnings
+}
+
+// movePhysicalTables 依次移动物理表，中途失败时把已移动的表移回
+func (s *TableService) movePhysicalTables(ctx context.Context, tables []string, fromSchema, toSchema string) error {
+	for i, name := range tables {
+		if err := s.dbProvider.MovePhysicalTable(ctx, fromSchema, toSchema, name); err != nil {
+			s.restorePhysicalTables(ctx, tables[:i], toSchema, fromSchema)
+			return pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("移动物理表失败: %v", err))
+		}
+	}
+	return nil
+}
+
+// rollbackMoveTable 关联处理失败时把表格移回原 Base：恢复保留关联的 BaseID、表格元数据与物理表（尽力而为）
+func (s *TableService) rollbackMoveTable(ctx context.Context, table *tableEntity.Table, physical []string, fromBaseID, fromDBTableName, toBaseID string, relinked []*moveRelinkedField) {
+	ctx = context.WithoutCancel(ctx)
+	for _, r := range relinked {
+		link := moveLinkOptions(r.field)
+		if link.BaseID == r.oldBaseID {
+			continue
+		}
+		link.BaseID = r.oldBaseID
+		if err := s.fieldService.fieldRepo.Save(ctx, r.field); err != nil {
+			logger.Error("恢复关联字段失败",
+				logger.String("field_id", r.field.ID().String()),
+				logger.ErrorField(err))
+		}
+	}
+
+	if fromDBTableName == "" {
+		fromDBTableName = s.dbProvider.GenerateTableName(fromBaseID, table.ID().String())
+	}
+	if err := table.MoveToBase(fromBaseID, fromDBTableName); err == nil {
+		if err := s.tableRepo.Save(ctx, table); err != nil {
+			logger.Error("恢复表格元数据失败",
+				logger.String("table_id", table.ID().String()),
+				logger.ErrorField(err))
+		}
+	}
+	s.restorePhysicalTables(ctx, physical, toBaseID, fromBaseID)
+}
+
+// restorePhysicalTables 把物理表移回原 Schema（尽力而为）
+func (s *TableService) restorePhysicalTables(ctx context.Context, tables []string, fromSchema, toSchema string) {
+	ctx = context.WithoutCancel(ctx)
+	for _, name := range tables {
+		if err := s.dbProvider.MovePhysicalTable(ctx, fromSchema, toSchema, name); err != nil {
+			logger.Error("移回物理表失败",
+				logger.String("table", name),
+				logger.String("schema", toSchema),
+				logger.ErrorField(err))
+		}
+	}
+}
+
+// moveLinkOptions 返回关联字段的选项，非关联字段返回 nil
+func moveLinkOptions(field *fieldEntity.Field) *fieldValueObject.LinkOptions {
+	if field.Type().String() != fieldValueObject.TypeLink || field.Options() == nil {
+		return nil
+	}
+	return field.Options().Link
+}
+
+// moveDependencyLinkID 返回查找、汇总或计数字段所依赖的关联字段ID
+func moveDependencyLinkID(field *fieldEntity.Field) string {
+	options := field.Options()
+	switch {
+	case options == nil:
+		return ""
+	case options.Lookup != nil:
+		return options.Lookup.LinkFieldID
+	case options.Rollup != nil:
+		return options.Rollup.LinkFieldID
+	case options.Count != nil:
+		return options.Count.LinkFieldID
+	}
+	return ""
+}
+
+// moveLinkBaseID 移动后关联字段的 LinkOptions.BaseID：关联表格与移动后的表格在同一 Base 时为空，
+// 移动表格一侧的字段指向关联表格所在 Base，另一侧的字段指向目标 Base
+func moveLinkBaseID(field *fieldEntity.Field, tableID, toBaseID, otherBaseID string) string {
+	switch {
+	case otherBaseID == toBaseID:
+		return ""
+	case field.TableID() == tableID:
+		return otherBaseID
+	default:
+		return toBaseID
+	}
+}
+
+// movedJunctionTable 返回随表格移动的中间表名：表格内自关联的中间表，
+// 以及从本表格一侧创建的多对多关联的中间表（建在本表格所在 Base，名称为 link_<本表格>_<关联表格>）；
+// 外键列在表格本身或关联表格中时返回空
+func movedJunctionTable(field *fieldEntity.Field, tableID string) string {
+	link := moveLinkOptions(field)
+	if link == nil || link.FkHostTableName == "" {
+		return ""
+	}
+	name := link.FkHostTableName
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	if name == tableID || name == link.LinkedTableID {
+		return ""
+	}
+	if link.LinkedTableID == tableID || strings.HasPrefix(name, "link_"+tableID+"_") {
+		return name
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+package application
+
+import (
+	"testing"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/factory"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMoveLinkField(t *testing.T, tableID string, link *valueobject.LinkOptions) *fieldEntity.Field {
+	field, err := factory.NewFieldFactory().CreateFieldWithType(tableID, "关联", valueobject.TypeLink, "usr_1")
+	require.NoError(t, err)
+	options := valueobject.NewFieldOptions()
+	options.Link = link
+	field.UpdateOptions(options)
+	return field
+}
+
+func TestMoveLinkBaseID(t *testing.T) {
+	own := newMoveLinkField(t, "tbl_move", &valueobject.LinkOptions{LinkedTableID: "tbl_other"})
+	incoming := newMoveLinkField(t, "tbl_other", &valueobject.LinkOptions{LinkedTableID: "tbl_move"})
+
+	// 关联表格已在目标 Base，移动后成为同 Base 关联
+	assert.Empty(t, moveLinkBaseID(own, "tbl_move", "bse_to", "bse_to"))
+	assert.Empty(t, moveLinkBaseID(incoming, "tbl_move", "bse_to", "bse_to"))
+
+	// 跨 Base 关联：移动表格一侧指向关联表格所在 Base，另一侧指向目标 Base
+	assert.Equal(t, "bse_other", moveLinkBaseID(own, "tbl_move", "bse_to", "bse_other"))
+	assert.Equal(t, "bse_to", moveLinkBaseID(incoming, "tbl_move", "bse_to", "bse_other"))
+}
+
+func TestMovedJunctionTable(t *testing.T) {
+	tests := []struct {
+		name string
+		link *valueobject.LinkOptions
+		want string
+	}{
+		{"自关联中间表", &valueobject.LinkOptions{LinkedTableID: "tbl_move", FkHostTableName: "bse_from.link_tbl_move_tbl_move"}, "link_tbl_move_tbl_move"},
+		{"本表格一侧创建的中间表", &valueobject.LinkOptions{LinkedTableID: "tbl_other", FkHostTableName: "link_tbl_move_tbl_other"}, "link_tbl_move_tbl_other"},
+		{"关联表格一侧创建的中间表", &valueobject.LinkOptions{LinkedTableID: "tbl_other", FkHostTableName: "link_tbl_other_tbl_move"}, ""},
+		{"外键在本表格", &valueobject.LinkOptions{LinkedTableID: "tbl_other", FkHostTableName: "tbl_move"}, ""},
+		{"外键在关联表格", &valueobject.LinkOptions{LinkedTableID: "tbl_other", FkHostTableName: "tbl_other"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field := newMoveLinkField(t, "tbl_move", tt.link)
+			assert.Equal(t, tt.want, movedJunctionTable(field, "tbl_move"))
+		})
+	}
+}
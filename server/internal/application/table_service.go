@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/easyspace-ai/luckdb/server/internal/application/basearchive"
 	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
 	"github.com/easyspace-ai/luckdb/server/internal/application/helpers"
 	baseRepo "github.com/easyspace-ai/luckdb/server/internal/domain/base/repository"
@@ -13,7 +14,9 @@ import (
 	"github.com/easyspace-ai/luckdb/server/internal/domain/table/entity"
 	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/table/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/events"
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
+	"github.com/easyspace-ai/luckdb/server/pkg/authctx"
 	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
 	"github.com/easyspace-ai/luckdb/server/pkg/logger"
 )
@@ -28,6 +31,15 @@ type TableService struct {
 	fieldService *FieldService               // ✅ 添加字段服务依赖
 	viewService  *ViewService                // ✅ 添加视图服务依赖
 	dbProvider   database.DBProvider         // ✅ 数据库提供者（物理表管理）
+
+	businessEvents events.BusinessEventPublisher // 业务事件发布器（可选）
+	duplicator     TableDuplicator               // 表格复制器
+	moveAuthorizer TableMoveAuthorizer           // 移动表格的权限检查（可选）
+}
+
+// TableDuplicator 复制表格（由 basearchive.Service 实现）
+type TableDuplicator interface {
+	DuplicateTable(ctx context.Context, req basearchive.TableDuplicateRequest) (*basearchive.TableDuplicateResult, error)
 }
 
 // NewTableService 创建表格服务
@@ -51,6 +63,39 @@ func NewTableService(
 	}
 }
 
+// SetBusinessEventPublisher 设置业务事件发布器，表格创建、更新、删除后发布 table.* 事件
+func (s *TableService) SetBusinessEventPublisher(publisher events.BusinessEventPublisher) {
+	s.businessEvents = publisher
+}
+
+// SetTableDuplicator 设置表格复制器（复制器依赖本服务创建表格，创建后注入）
+func (s *TableService) SetTableDuplicator(duplicator TableDuplicator) {
+	s.duplicator = duplicator
+}
+
+// SetTableMoveAuthorizer 设置移动表格的权限检查：需要原 Base 的删除表格权限与目标 Base 的创建表格权限
+func (s *TableService) SetTableMoveAuthorizer(authorizer TableMoveAuthorizer) {
+	s.moveAuthorizer = authorizer
+}
+
+// publishTableEvent 发布表格业务事件（只携带 base_id，订阅方按需重新读取表格）
+func (s *TableService) publishTableEvent(ctx context.Context, eventType events.BusinessEventType, tableID, baseID, userID string) {
+	if s.businessEvents == nil {
+		return
+	}
+	if userID == "" {
+		userID, _ = authctx.UserFrom(ctx)
+	}
+
+	data := map[string]interface{}{"base_id": baseID}
+	if err := s.businessEvents.PublishTableEvent(ctx, eventType, tableID, data, userID); err != nil {
+		logger.Warn("发布表格业务事件失败",
+			logger.String("event_type", string(eventType)),
+			logger.String("table_id", tableID),
+			logger.ErrorField(err))
+	}
+}
+
 // CreateTable 创建表格
 // ✅ 对齐 Teable 实现：支持批量创建字段和视图
 // 参考：teable-develop/apps/nestjs-backend/src/features/table/open-api/table-open-api.service.ts
@@ -62,75 +107,13 @@ func (s *TableService) CreateTable(ctx context.Context, req dto.CreateTableReque
 		logger.Int("views_count", len(req.Views)),
 		logger.Int("fields_count", len(req.Fields)))
 
-	// 1. 验证表格名称
-	tableName, err := valueobject.NewTableName(req.Name)
+	table, err := s.createTable(ctx, req, userID)
 	if err != nil {
-		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("表格名称无效: %v", err))
-	}
-
-	// 2. 验证Base是否存在
-	exists, err := s.baseRepo.Exists(ctx, req.BaseID)
-	if err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("验证Base存在性失败: %v", err))
+		return nil, err
 	}
-	if !exists {
-		return nil, pkgerrors.ErrNotFound.WithDetails(map[string]interface{}{
-			"resource": "base",
-			"id":       req.BaseID,
-			"message":  "Base不存在",
-		})
-	}
-
-	// 3. 创建表格实体
-	table, err := entity.NewTable(req.BaseID, tableName, userID)
-	if err != nil {
-		return nil, pkgerrors.ErrInternalServer.WithDetails(fmt.Sprintf("创建表格实体失败: %v", err))
-	}
-
-	// 4. 设置可选属性
-	if req.Description != "" {
-		table.UpdateDescription(req.Description)
-	}
-
-	// 5. ✅ 创建物理表（包含系统字段）
 	tableID := table.ID().String()
-	baseID := req.BaseID
-	dbTableName := s.dbProvider.GenerateTableName(baseID, tableID)
-
-	logger.Info("正在创建物理表",
-		logger.String("table_id", tableID),
-		logger.String("base_id", baseID),
-		logger.String("db_table_name", dbTableName))
-
-	if err := s.dbProvider.CreatePhysicalTable(ctx, baseID, tableID); err != nil {
-		logger.Error("创建物理表失败",
-			logger.String("table_id", tableID),
-			logger.String("db_table_name", dbTableName),
-			logger.ErrorField(err))
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(
-			fmt.Sprintf("创建物理表失败: %v", err))
-	}
-
-	logger.Info("✅ 物理表创建成功",
-		logger.String("table_id", tableID),
-		logger.String("db_table_name", dbTableName))
-
-	// 6. 保存表格元数据（设置DBTableName）
-	table.SetDBTableName(dbTableName)
-
-	tableAgg := aggregate.NewTableAggregate(table)
-	if err := s.tableRepo.Save(ctx, table); err != nil {
-		// ❌ 回滚：删除已创建的物理表
-		if rollbackErr := s.dbProvider.DropPhysicalTable(ctx, baseID, tableID); rollbackErr != nil {
-			logger.Error("回滚删除物理表失败",
-				logger.String("table_id", tableID),
-				logger.ErrorField(rollbackErr))
-		}
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存表格失败: %v", err))
-	}
-
-	// 临时存储聚合根以便未来扩展
-	_ = tableAgg
+	tableName := table.Name()
+	dbTableName := s.dbProvider.GenerateTableName(req.BaseID, tableID)
 
 	// 7. ✅ 批量创建字段（对齐 Teable）
 	createdFieldCount := 0
@@ -231,6 +214,8 @@ func (s *TableService) CreateTable(ctx context.Context, req dto.CreateTableReque
 		logger.Int("field_count", createdFieldCount),
 		logger.Int("view_count", len(createdViews)))
 
+	s.publishTableEvent(ctx, events.BusinessEventTypeTableCreate, tableID, req.BaseID, userID)
+
 	// 返回响应，包含 defaultViewId
 	response := dto.FromTableEntity(table)
 	response.DefaultViewID = defaultViewID
@@ -238,6 +223,96 @@ func (s *TableService) CreateTable(ctx context.Context, req dto.CreateTableReque
 	return response, nil
 }
 
+// createTable 创建表格实体与物理表并保存元数据（不含字段和视图）
+func (s *TableService) createTable(ctx context.Context, req dto.CreateTableRequest, userID string) (*entity.Table, error) {
+	// 1. 验证表格名称
+	tableName, err := valueobject.NewTableName(req.Name)
+	if err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("表格名称无效: %v", err))
+	}
+
+	// 2. 验证Base是否存在
+	exists, err := s.baseRepo.Exists(ctx, req.BaseID)
+	if err != nil {
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("验证Base存在性失败: %v", err))
+	}
+	if !exists {
+		return nil, pkgerrors.ErrNotFound.WithDetails(map[string]interface{}{
+			"resource": "base",
+			"id":       req.BaseID,
+			"message":  "Base不存在",
+		})
+	}
+
+	// 3. 创建表格实体
+	table, err := entity.NewTable(req.BaseID, tableName, userID)
+	if err != nil {
+		return nil, pkgerrors.ErrInternalServer.WithDetails(fmt.Sprintf("创建表格实体失败: %v", err))
+	}
+
+	// 4. 设置可选属性
+	if req.Description != "" {
+		table.UpdateDescription(req.Description)
+	}
+
+	// 5. ✅ 创建物理表（包含系统字段）
+	tableID := table.ID().String()
+	baseID := req.BaseID
+	dbTableName := s.dbProvider.GenerateTableName(baseID, tableID)
+
+	logger.Info("正在创建物理表",
+		logger.String("table_id", tableID),
+		logger.String("base_id", baseID),
+		logger.String("db_table_name", dbTableName))
+
+	if err := s.dbProvider.CreatePhysicalTable(ctx, baseID, tableID); err != nil {
+		logger.Error("创建物理表失败",
+			logger.String("table_id", tableID),
+			logger.String("db_table_name", dbTableName),
+			logger.ErrorField(err))
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(
+			fmt.Sprintf("创建物理表失败: %v", err))
+	}
+
+	logger.Info("✅ 物理表创建成功",
+		logger.String("table_id", tableID),
+		logger.String("db_table_name", dbTableName))
+
+	// 6. 保存表格元数据（设置DBTableName）
+	table.SetDBTableName(dbTableName)
+
+	tableAgg := aggregate.NewTableAggregate(table)
+	if err := s.tableRepo.Save(ctx, table); err != nil {
+		// ❌ 回滚：删除已创建的物理表
+		if rollbackErr := s.dbProvider.DropPhysicalTable(ctx, baseID, tableID); rollbackErr != nil {
+			logger.Error("回滚删除物理表失败",
+				logger.String("table_id", tableID),
+				logger.ErrorField(rollbackErr))
+		}
+		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存表格失败: %v", err))
+	}
+
+	// 临时存储聚合根以便未来扩展
+	_ = tableAgg
+
+	return table, nil
+}
+
+// CreateEmptyTable 创建不含字段和视图的表格（用于 Base 导入，字段与视图随后按原配置创建）
+func (s *TableService) CreateEmptyTable(ctx context.Context, baseID, name, description, userID string) (*dto.TableResponse, error) {
+	table, err := s.createTable(ctx, dto.CreateTableRequest{
+		Name:        name,
+		Description: description,
+		BaseID:      baseID,
+	}, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publishTableEvent(ctx, events.BusinessEventTypeTableCreate, table.ID().String(), baseID, userID)
+	return dto.FromTableEntity(table), nil
+}
+
 // GetTable 获取表格详情
 func (s *TableService) GetTable(ctx context.Context, tableID string) (*dto.TableResponse, error) {
 	table, err := s.tableRepo.GetByID(ctx, tableID)
@@ -295,6 +370,7 @@ func (s *TableService) UpdateTable(ctx context.Context, tableID string, req dto.
 	}
 
 	logger.Info("表格更新成功", logger.String("table_id", tableID))
+	s.publishTableEvent(ctx, events.BusinessEventTypeTableUpdate, tableID, table.BaseID(), "")
 
 	return dto.FromTableEntity(table), nil
 }
@@ -340,6 +416,7 @@ func (s *TableService) DeleteTable(ctx context.Context, tableID string) error {
 	logger.Info("✅ 表格删除成功（含物理表）",
 		logger.String("table_id", tableID),
 		logger.String("base_id", baseID))
+	s.publishTableEvent(ctx, events.BusinessEventTypeTableDelete, tableID, baseID, "")
 
 	return nil
 }
@@ -408,12 +485,19 @@ func (s *TableService) RenameTable(ctx context.Context, tableID string, req dto.
 	logger.Info("表格重命名成功",
 		logger.String("table_id", tableID),
 		logger.String("new_name", newName.String()))
+	s.publishTableEvent(ctx, events.BusinessEventTypeTableUpdate, tableID, table.BaseID(), "")
 
 	return dto.FromTableEntity(table), nil
 }
 
 // DuplicateTable 复制表
+// 字段、视图与记录由表格复制器按原配置重建：自关联指向副本，查找、汇总与公式引用改写为副本中的字段，
+// 指向其他表格的关联按 linkMode 保留（在对方表格中新建对称字段）或丢弃
 func (s *TableService) DuplicateTable(ctx context.Context, tableID string, req dto.DuplicateTableRequest, userID string) (*dto.TableResponse, error) {
+	if s.duplicator == nil {
+		return nil, pkgerrors.ErrInternalServer.WithDetails("表格复制功能未启用")
+	}
+
 	// 1. 查找原表格
 	originalTable, err := s.tableRepo.GetByID(ctx, tableID)
 	if err != nil {
@@ -423,11 +507,15 @@ func (s *TableService) DuplicateTable(ctx context.Context, tableID string, req d
 		return nil, pkgerrors.ErrNotFound.WithDetails("原表格不存在")
 	}
 
-	// 2. 验证新名称
+	// 2. 验证新名称与关联处理方式
 	newName, err := valueobject.NewTableName(req.Name)
 	if err != nil {
 		return nil, pkgerrors.ErrValidationFailed.WithDetails(fmt.Sprintf("表格名称无效: %v", err))
 	}
+	linkMode, err := basearchive.ParseLinkMode(req.LinkMode)
+	if err != nil {
+		return nil, pkgerrors.ErrValidationFailed.WithDetails(err.Error())
+	}
 
 	// 3. 检查名称是否重复
 	exists, err := s.tableRepo.ExistsByNameInBase(ctx, originalTable.BaseID(), newName, nil)
@@ -438,115 +526,35 @@ func (s *TableService) DuplicateTable(ctx context.Context, tableID string, req d
 		return nil, pkgerrors.ErrConflict.WithDetails("表格名称已存在")
 	}
 
-	// 4. 创建新表格实体
-	newTable, err := entity.NewTable(originalTable.BaseID(), newName, userID)
+	// 4. 复制表格（失败时复制器删除已创建的表格与对称字段）
+	result, err := s.duplicator.DuplicateTable(ctx, basearchive.TableDuplicateRequest{
+		TableID:     tableID,
+		Name:        newName.String(),
+		WithFields:  req.WithFields,
+		WithViews:   req.WithViews,
+		WithRecords: req.WithData,
+		LinkMode:    linkMode,
+		UserID:      userID,
+	})
 	if err != nil {
-		return nil, pkgerrors.ErrInternalServer.WithDetails(fmt.Sprintf("创建新表格实体失败: %v", err))
-	}
-
-	// 5. 复制描述
-	if originalTable.Description() != nil {
-		newTable.UpdateDescription(*originalTable.Description())
-	}
-
-	// 6. 创建物理表
-	newTableID := newTable.ID().String()
-	baseID := originalTable.BaseID()
-	dbTableName := s.dbProvider.GenerateTableName(baseID, newTableID)
-
-	if err := s.dbProvider.CreatePhysicalTable(ctx, baseID, newTableID); err != nil {
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("创建物理表失败: %v", err))
-	}
-
-	// 7. 设置物理表名并保存表格
-	newTable.SetDBTableName(dbTableName)
-	if err := s.tableRepo.Save(ctx, newTable); err != nil {
-		// 回滚：删除已创建的物理表
-		if rollbackErr := s.dbProvider.DropPhysicalTable(ctx, baseID, newTableID); rollbackErr != nil {
-			logger.Error("回滚删除物理表失败", logger.ErrorField(rollbackErr))
-		}
-		return nil, pkgerrors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存新表格失败: %v", err))
-	}
-
-	// 8. 复制字段（如果需要）
-	if req.WithFields && s.fieldService != nil {
-		fields, err := s.fieldService.ListFields(ctx, tableID)
-		if err != nil {
-			logger.Warn("获取原表格字段失败", logger.ErrorField(err))
-		} else {
-			for _, field := range fields {
-				fieldReq := dto.CreateFieldRequest{
-					TableID:  newTableID,
-					Name:     field.Name,
-					Type:     field.Type,
-					Required: field.Required,
-					Unique:   field.Unique,
-					Options:  field.Options,
-				}
-				if _, err := s.fieldService.CreateField(ctx, fieldReq, userID); err != nil {
-					logger.Warn("复制字段失败",
-						logger.String("field_name", field.Name),
-						logger.ErrorField(err))
-				}
-			}
-		}
+		return nil, err
 	}
-
-	// 9. 复制视图（如果需要）
-	if req.WithViews && s.viewService != nil {
-		views, err := s.viewService.ListViewsByTable(ctx, tableID)
-		if err != nil {
-			logger.Warn("获取原表格视图失败", logger.ErrorField(err))
-		} else {
-			for _, view := range views {
-				// 类型断言 ColumnMeta
-				var columnMeta []map[string]interface{}
-				if view.ColumnMeta != nil {
-					if cm, ok := view.ColumnMeta.([]map[string]interface{}); ok {
-						columnMeta = cm
-					}
-				}
-
-				viewReq := dto.CreateViewRequest{
-					TableID:     newTableID,
-					Name:        view.Name + " (副本)",
-					Type:        view.Type,
-					Description: view.Description,
-					ColumnMeta:  columnMeta,
-				}
-				if _, err := s.viewService.CreateView(ctx, viewReq, userID); err != nil {
-					logger.Warn("复制视图失败",
-						logger.String("view_name", view.Name),
-						logger.ErrorField(err))
-				}
-			}
-		}
-	}
-
-	// 10. 复制数据（如果需要）
-	if req.WithData {
-		if err := s.copyTableData(ctx, tableID, newTableID); err != nil {
-			logger.Error("复制表格数据失败",
-				logger.String("source_table_id", tableID),
-				logger.String("target_table_id", newTableID),
-				logger.ErrorField(err))
-			// 数据复制失败不影响表格创建，只记录错误
-		} else {
-			logger.Info("表格数据复制成功",
-				logger.String("source_table_id", tableID),
-				logger.String("target_table_id", newTableID))
-		}
+	for _, warning := range result.Warnings {
+		logger.Warn("复制表格时跳过",
+			logger.String("new_table_id", result.TableID),
+			logger.String("warning", warning))
 	}
 
 	logger.Info("表格复制成功",
 		logger.String("original_table_id", tableID),
-		logger.String("new_table_id", newTableID),
+		logger.String("new_table_id", result.TableID),
 		logger.String("new_name", newName.String()),
-		logger.Bool("with_data", req.WithData),
-		logger.Bool("with_views", req.WithViews),
-		logger.Bool("with_fields", req.WithFields))
+		logger.String("link_mode", string(linkMode)),
+		logger.Int("fields", result.Fields),
+		logger.Int("views", result.Views),
+		logger.Int("records", result.Records))
 
-	return dto.FromTableEntity(newTable), nil
+	return s.GetTable(ctx, result.TableID)
 }
 
 // GetTableUsage 获取表用量信息
@@ -605,47 +613,6 @@ func (s *TableService) GetTableUsage(ctx context.Context, tableID string) (*dto.
 	return usage, nil
 }
 
-// copyTableData 复制表格数据
-func (s *TableService) copyTableData(ctx context.Context, sourceTableID, targetTableID string) error {
-	// 1. 获取源表格的所有记录
-	records, err := s.recordRepo.FindByTableID(ctx, sourceTableID) // 获取所有记录
-	if err != nil {
-		return fmt.Errorf("获取源表格记录失败: %w", err)
-	}
-
-	if len(records) == 0 {
-		logger.Info("源表格无数据，跳过复制", logger.String("source_table_id", sourceTableID))
-		return nil
-	}
-
-	// 2. 批量插入到目标表格
-	batchSize := 100 // 批量大小
-	for i := 0; i < len(records); i += batchSize {
-		end := i + batchSize
-		if end > len(records) {
-			end = len(records)
-		}
-
-		batch := records[i:end]
-		if err := s.recordRepo.BatchSave(ctx, batch); err != nil {
-			return fmt.Errorf("批量插入记录失败 (批次 %d-%d): %w", i, end-1, err)
-		}
-
-		logger.Debug("复制记录批次完成",
-			logger.String("target_table_id", targetTableID),
-			logger.Int("batch_start", i),
-			logger.Int("batch_end", end-1),
-			logger.Int("batch_size", len(batch)))
-	}
-
-	logger.Info("表格数据复制完成",
-		logger.String("source_table_id", sourceTableID),
-		logger.String("target_table_id", targetTableID),
-		logger.Int("total_records", len(records)))
-
-	return nil
-}
-
 // calculateTableStorageSize 计算表格存储大小
 func (s *TableService) calculateTableStorageSize(ctx context.Context, tableID string) (int64, error) {
 	// 使用估算方法计算存储大小
@@ -3,11 +3,20 @@ package application
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/validation"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	recordValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	userEntity "github.com/easyspace-ai/luckdb/server/internal/domain/user/entity"
+	userRepo "github.com/easyspace-ai/luckdb/server/internal/domain/user/repository"
+	userValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/user/valueobject"
+	viewValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
 	"github.com/easyspace-ai/luckdb/server/pkg/errors"
 	"github.com/easyspace-ai/luckdb/server/pkg/logger"
 )
@@ -15,8 +24,10 @@ import (
 // TypecastService 类型转换服务
 // 参考旧系统: TypeCastAndValidate (teable-develop/apps/nestjs-backend/src/features/record/typecast.validate.ts)
 type TypecastService struct {
-	fieldRepo repository.FieldRepository
-	factory   *validation.ValidatorFactory
+	fieldRepo  repository.FieldRepository
+	factory    *validation.ValidatorFactory
+	userRepo   userRepo.UserRepository     // 可选：用户字段按邮箱/名称转换
+	recordRepo recordRepo.RecordRepository // 可选：关联字段按标题转换
 }
 
 // NewTypecastService 创建类型转换服务
@@ -126,6 +137,11 @@ func (s *TypecastService) ValidateAndTypecastRecord(
 		}
 	}
 
+	// 4. 按字段配置的校验规则检查（宽松模式同样报错，不做修复）
+	if err := checkFieldRules(fields, result); err != nil {
+		return nil, err
+	}
+
 	logger.Info("验证和类型转换完成",
 		logger.Int("input_fields", len(data)),
 		logger.Int("output_fields", len(result)))
@@ -133,6 +149,65 @@ func (s *TypecastService) ValidateAndTypecastRecord(
 	return result, nil
 }
 
+// CheckFieldRules 按字段配置的校验规则检查记录数据（键为字段ID或名称），用于不经过类型转换的写入
+func (s *TypecastService) CheckFieldRules(ctx context.Context, tableID string, data map[string]interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return errors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取字段列表失败: %v", err))
+	}
+	return checkFieldRules(fields, data)
+}
+
+// fieldRuleErrors 取出违反校验规则错误中的逐字段详情，用于批量写入的响应
+func fieldRuleErrors(err error) ([]map[string]interface{}, bool) {
+	appErr, ok := errors.IsAppError(err)
+	if !ok || appErr.Code != errors.ErrFieldRuleViolation.Code {
+		return nil, false
+	}
+	details, _ := appErr.Details.(map[string]interface{})
+	list, ok := details["errors"].([]map[string]interface{})
+	return list, ok
+}
+
+// checkFieldRules 检查数据中每个字段的值，所有违反规则的字段一起返回
+//
+// 错误详情 errors 中每项包含 field_id、field_name、rule（违反的规则）、message（自定义或默认提示）和 value
+func checkFieldRules(fields []*entity.Field, data map[string]interface{}) error {
+	now := time.Now()
+	violations := make([]map[string]interface{}, 0)
+	for _, field := range fields {
+		if field.IsComputed() {
+			continue
+		}
+		value, ok := data[field.ID().String()]
+		if !ok {
+			value, ok = data[field.Name().String()]
+		}
+		if !ok {
+			continue
+		}
+		if v := validation.CheckRules(field, value, now); v != nil {
+			violations = append(violations, map[string]interface{}{
+				"field_id":   field.ID().String(),
+				"field_name": field.Name().String(),
+				"rule":       v.Rule,
+				"message":    v.Message,
+				"value":      value,
+			})
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return errors.ErrFieldRuleViolation.WithDetails(map[string]interface{}{
+		"message": fmt.Sprintf("%d 个字段的值不满足校验规则", len(violations)),
+		"errors":  violations,
+	})
+}
+
 // ValidateFieldValue 验证单个字段值
 func (s *TypecastService) ValidateFieldValue(
 	ctx context.Context,
@@ -223,3 +298,307 @@ func (s *TypecastService) convertValidationError(
 		})
 	}
 }
+
+// SetReferenceRepositories 设置宽松转换用到的用户、记录仓储（用户、关联字段按名称查找）
+func (s *TypecastService) SetReferenceRepositories(users userRepo.UserRepository, records recordRepo.RecordRepository) {
+	s.userRepo = users
+	s.recordRepo = records
+}
+
+// TypecastRecordValues 宽松转换记录数据（typecast=true）
+//
+// 在常规验证之前运行，把松散输入转换为字段的存储格式，返回以字段ID为键的数据：
+//   - 数字：字符串去掉千分位、货币符号，"50%" 转为 0.5
+//   - 日期：常见日期写法、Unix 时间戳
+//   - 单选/多选：选项名称转为选项ID，未知名称自动创建选项（SelectOptions.PreventAutoNewOptions 时报错）
+//   - 用户：邮箱、用户名或用户ID 转为用户对象
+//   - 关联：被关联表主字段（或 lookupFieldId）的文本转为 {id, title}
+//
+// 存在不存在的字段时返回 FIELD_NOT_EXISTS 并列出全部不存在的字段；计算字段会被跳过，其余类型原样交给验证器处理
+//
+// 参考旧系统: TypeCastAndValidate.typecastCellValuesWithField
+func (s *TypecastService) TypecastRecordValues(
+	ctx context.Context,
+	tableID string,
+	data map[string]interface{},
+) (map[string]interface{}, error) {
+	fields, err := s.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, fmt.Errorf("获取字段列表失败: %w", err)
+	}
+
+	fieldMapByID := make(map[string]*entity.Field, len(fields))
+	fieldMapByName := make(map[string]*entity.Field, len(fields))
+	for _, field := range fields {
+		fieldMapByID[field.ID().String()] = field
+		fieldMapByName[field.Name().String()] = field
+	}
+
+	// 先检查字段是否都存在，写入时不静默丢弃拼错的字段，也不在报错前自动创建选项
+	unknown := make([]string, 0)
+	for fieldKey := range data {
+		if fieldMapByID[fieldKey] == nil && fieldMapByName[fieldKey] == nil {
+			unknown = append(unknown, fieldKey)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, errors.ErrFieldNotExists.WithDetails(map[string]interface{}{
+			"fields":   unknown,
+			"table_id": tableID,
+		})
+	}
+
+	result := make(map[string]interface{}, len(data))
+	changedFields := make(map[string]*entity.Field)
+
+	for fieldKey, value := range data {
+		field, exists := fieldMapByID[fieldKey]
+		if !exists {
+			field = fieldMapByName[fieldKey]
+		}
+		if field.IsComputed() {
+			continue
+		}
+
+		fieldID := field.ID().String()
+		if value == nil {
+			result[fieldID] = nil
+			continue
+		}
+
+		switch field.Type().String() {
+		case valueobject.TypeNumber, valueobject.TypeRating, valueobject.TypePercent,
+			valueobject.TypeCurrency, valueobject.TypeDuration:
+			if num, ok := validation.CoerceNumber(value); ok {
+				value = num
+			}
+		case valueobject.TypeDate, valueobject.TypeDateTime:
+			if t, ok := validation.CoerceDate(value); ok {
+				value = t
+			}
+		case valueobject.TypeSelect, valueobject.TypeSingleSelect, valueobject.TypeMultipleSelect:
+			multiple := field.Type().String() == valueobject.TypeMultipleSelect
+			ids, created, unknown := validation.ResolveSelectChoices(field, validation.TypecastStrings(value, multiple))
+			if len(unknown) > 0 {
+				return nil, errors.ErrInvalidFieldValue.WithDetails(map[string]interface{}{
+					"field":   field.Name().String(),
+					"value":   unknown,
+					"error":   "选项不存在且字段禁止自动添加新选项",
+					"choices": field.Options().Select.Choices,
+				})
+			}
+			if len(created) > 0 {
+				changedFields[fieldID] = field
+			}
+			value = selectCellValue(ids, multiple)
+		case valueobject.TypeUser:
+			if value, err = s.typecastUsers(ctx, field, value); err != nil {
+				return nil, err
+			}
+		case valueobject.TypeLink:
+			if value, err = s.typecastLinks(ctx, field, value); err != nil {
+				return nil, err
+			}
+		}
+
+		result[fieldID] = value
+	}
+
+	// 持久化自动创建的选项
+	for _, field := range changedFields {
+		if err := s.fieldRepo.Save(ctx, field); err != nil {
+			return nil, errors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("保存自动创建的选项失败: %v", err))
+		}
+		logger.Info("typecast 自动创建选项",
+			logger.String("table_id", tableID),
+			logger.String("field_id", field.ID().String()))
+	}
+
+	return result, nil
+}
+
+// selectCellValue 选项ID转为单元格值（单选为字符串，多选为数组）
+func selectCellValue(ids []string, multiple bool) interface{} {
+	if len(ids) == 0 {
+		return nil
+	}
+	if !multiple {
+		return ids[0]
+	}
+	values := make([]interface{}, len(ids))
+	for i, id := range ids {
+		values[i] = id
+	}
+	return values
+}
+
+// typecastUsers 将邮箱、用户名或用户ID转为用户对象 {id, title, email}
+func (s *TypecastService) typecastUsers(ctx context.Context, field *entity.Field, value interface{}) (interface{}, error) {
+	keys := validation.TypecastStrings(value, true)
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	if s.userRepo == nil {
+		return value, nil
+	}
+
+	users := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		user, err := s.findUser(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, errors.ErrInvalidFieldValue.WithDetails(map[string]interface{}{
+				"field": field.Name().String(),
+				"value": key,
+				"error": "找不到匹配的用户",
+			})
+		}
+		users = append(users, map[string]interface{}{
+			"id":    user.ID().String(),
+			"title": user.Name(),
+			"email": user.Email().String(),
+		})
+	}
+
+	if options := field.Options(); options != nil && options.User != nil && options.User.IsMultiple {
+		return users, nil
+	}
+	return users[0], nil
+}
+
+// findUser 按用户ID、邮箱、用户名依次查找；用户名有多个同名用户时视为不匹配
+func (s *TypecastService) findUser(ctx context.Context, key string) (*userEntity.User, error) {
+	if strings.HasPrefix(key, "usr") {
+		if user, err := s.userRepo.FindByID(ctx, userValueObject.NewUserID(key)); err == nil && user != nil {
+			return user, nil
+		}
+	}
+	if email, err := userValueObject.NewEmail(key); err == nil {
+		user, err := s.userRepo.FindByEmail(ctx, email)
+		if err != nil {
+			return nil, errors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找用户失败: %v", err))
+		}
+		return user, nil
+	}
+
+	candidates, _, err := s.userRepo.List(ctx, userRepo.UserFilter{Name: &key, Limit: 20})
+	if err != nil {
+		return nil, errors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找用户失败: %v", err))
+	}
+	var found *userEntity.User
+	for _, candidate := range candidates {
+		if candidate.Name() != key {
+			continue
+		}
+		if found != nil {
+			return nil, nil
+		}
+		found = candidate
+	}
+	return found, nil
+}
+
+// typecastLinks 将被关联记录的标题（或记录ID）转为 {id, title}
+func (s *TypecastService) typecastLinks(ctx context.Context, field *entity.Field, value interface{}) (interface{}, error) {
+	options := field.Options()
+	if options == nil || options.Link == nil || options.Link.LinkedTableID == "" || s.recordRepo == nil {
+		return value, nil
+	}
+	link := options.Link
+
+	titles := validation.TypecastStrings(value, false)
+	if len(titles) == 0 {
+		return nil, nil
+	}
+
+	lookupField, err := s.linkLookupField(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]interface{}, 0, len(titles))
+	for _, title := range titles {
+		id, err := s.findLinkedRecord(ctx, link.LinkedTableID, lookupField, title)
+		if err != nil {
+			return nil, err
+		}
+		if id == "" {
+			return nil, errors.ErrInvalidFieldValue.WithDetails(map[string]interface{}{
+				"field": field.Name().String(),
+				"value": title,
+				"error": "找不到匹配的关联记录",
+			})
+		}
+		links = append(links, map[string]interface{}{"id": id, "title": title})
+	}
+
+	multiple := link.AllowMultiple || link.Relationship == "many_to_many" || link.Relationship == "one_to_many"
+	if multiple {
+		return links, nil
+	}
+	return links[0], nil
+}
+
+// linkLookupField 被关联表的显示字段：lookupFieldId，否则主字段，否则第一个非虚拟字段
+func (s *TypecastService) linkLookupField(ctx context.Context, link *valueobject.LinkOptions) (*entity.Field, error) {
+	fields, err := s.fieldRepo.FindByTableID(ctx, link.LinkedTableID)
+	if err != nil {
+		return nil, errors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("获取关联表字段失败: %v", err))
+	}
+
+	var primary, firstStored *entity.Field
+	for _, field := range fields {
+		if link.LookupFieldID != "" && field.ID().String() == link.LookupFieldID {
+			return field, nil
+		}
+		if primary == nil && field.IsPrimary() {
+			primary = field
+		}
+		if firstStored == nil && !field.IsComputed() {
+			firstStored = field
+		}
+	}
+	if primary != nil {
+		return primary, nil
+	}
+	return firstStored, nil
+}
+
+// findLinkedRecord 按显示字段的值查找被关联记录；找不到时尝试把值当作记录ID，多条匹配视为不匹配
+func (s *TypecastService) findLinkedRecord(ctx context.Context, tableID string, lookupField *entity.Field, title string) (string, error) {
+	if lookupField != nil {
+		records, _, err := s.recordRepo.List(ctx, recordRepo.RecordFilter{
+			TableID: &tableID,
+			Filter: &viewValueObject.Filter{
+				Operator: viewValueObject.FilterOperatorAnd,
+				Filters: []viewValueObject.FilterItem{{
+					FieldID:  lookupField.ID().String(),
+					Operator: viewValueObject.FilterItemOpIs,
+					Value:    title,
+				}},
+			},
+			Projection: []string{lookupField.ID().String()},
+			Limit:      2,
+		})
+		if err != nil {
+			return "", errors.ErrDatabaseOperation.WithDetails(fmt.Sprintf("查找关联记录失败: %v", err))
+		}
+		if len(records) == 1 {
+			return records[0].ID().String(), nil
+		}
+		if len(records) > 1 {
+			return "", nil
+		}
+	}
+
+	if strings.HasPrefix(title, "rec") {
+		records, err := s.recordRepo.FindByIDs(ctx, tableID, []recordValueObject.RecordID{recordValueObject.NewRecordID(title)})
+		if err == nil && len(records) == 1 {
+			return title, nil
+		}
+	}
+	return "", nil
+}
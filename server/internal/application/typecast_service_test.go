@@ -0,0 +1,41 @@
+package application
+
+import (
+	"context"
+	"testing"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypecastService_TypecastRecordValues(t *testing.T) {
+	ctx := context.Background()
+	amount, err := createFieldWithID("tbl_1", "fld_amount", "Amount", fieldValueObject.TypeNumber, "usr_1")
+	require.NoError(t, err)
+
+	fieldRepo := new(MockFieldRepository)
+	fieldRepo.On("FindByTableID", ctx, "tbl_1").Return([]*fieldEntity.Field{amount}, nil)
+	s := NewTypecastService(fieldRepo)
+
+	t.Run("按字段ID或名称转换", func(t *testing.T) {
+		result, err := s.TypecastRecordValues(ctx, "tbl_1", map[string]interface{}{"Amount": "12.5"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"fld_amount": 12.5}, result)
+	})
+
+	t.Run("字段不存在时列出全部不存在的字段", func(t *testing.T) {
+		_, err := s.TypecastRecordValues(ctx, "tbl_1", map[string]interface{}{
+			"Amount": 1,
+			"Amuont": 2,
+			"fld_gone": 3,
+		})
+		appErr, ok := pkgerrors.IsAppError(err)
+		require.True(t, ok)
+		assert.Equal(t, "FIELD_NOT_EXISTS", appErr.Code)
+		details, _ := appErr.Details.(map[string]interface{})
+		assert.Equal(t, []string{"Amuont", "fld_gone"}, details["fields"])
+	})
+}
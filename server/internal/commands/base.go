@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/basearchive"
+	"github.com/easyspace-ai/luckdb/server/internal/config"
+	"github.com/easyspace-ai/luckdb/server/internal/container"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+// NewBaseCmd 创建 Base 管理命令
+func NewBaseCmd(configPath *string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "base",
+		Short: "Base 归档导出与导入",
+		Long: `Base 归档导出与导入
+
+归档为 zip 文件，包含 manifest.json（表格、字段、视图与关联关系）、
+records/<tableId>.jsonl（记录）与 attachments/（附件文件）。
+导入时在目标空间下新建 Base，所有 ID 重新生成，关联、查找、汇总与公式引用随之改写。`,
+		Example: `  # 导出 Base
+  luckdb base export bse_xxx -o sales.luckdb.zip
+
+  # 导入到空间
+  luckdb base import sales.luckdb.zip --space spc_xxx --user usr_xxx`,
+	}
+
+	cmd.AddCommand(newBaseExportCmd(configPath))
+	cmd.AddCommand(newBaseImportCmd(configPath))
+
+	return cmd
+}
+
+// newBaseExportCmd 创建 export 命令
+func newBaseExportCmd(configPath *string) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "export <baseId>",
+		Short: "导出 Base 归档",
+		Long:  "把 Base 的结构、记录与附件文件导出为 zip 归档，未指定输出文件时以 Base 名称命名",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withContainer(func(ctx context.Context, cont *container.Container) error {
+				service := cont.BaseArchiveService()
+				baseID := args[0]
+				if output == "" {
+					name, err := service.FileName(ctx, baseID)
+					if err != nil {
+						return err
+					}
+					output = name
+				}
+
+				file, err := os.Create(output)
+				if err != nil {
+					return fmt.Errorf("创建输出文件失败: %w", err)
+				}
+				manifest, err := service.Export(ctx, baseID, file)
+				if closeErr := file.Close(); err == nil {
+					err = closeErr
+				}
+				if err != nil {
+					os.Remove(output)
+					return fmt.Errorf("导出失败: %w", err)
+				}
+
+				records := 0
+				for _, table := range manifest.Tables {
+					records += table.RecordCount
+				}
+				fmt.Printf("✅ 已导出 %s: %d 个表格, %d 条记录, %d 个附件 -> %s\n",
+					manifest.Base.Name, len(manifest.Tables), records, len(manifest.Attachments), output)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "输出文件路径")
+
+	return cmd
+}
+
+// newBaseImportCmd 创建 import 命令
+func newBaseImportCmd(configPath *string) *cobra.Command {
+	var spaceID, userID, name string
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "导入 Base 归档",
+		Long:  "在目标空间下按归档新建 Base，--user 指定的用户成为新 Base 与记录的创建者",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withContainer(func(ctx context.Context, cont *container.Container) error {
+				file, err := os.Open(args[0])
+				if err != nil {
+					return fmt.Errorf("打开归档文件失败: %w", err)
+				}
+				defer file.Close()
+				info, err := file.Stat()
+				if err != nil {
+					return fmt.Errorf("读取归档文件失败: %w", err)
+				}
+
+				result, err := cont.BaseArchiveService().Import(ctx, file, info.Size(), basearchive.ImportRequest{
+					SpaceID: spaceID,
+					Name:    name,
+					UserID:  userID,
+				})
+				if err != nil {
+					return fmt.Errorf("导入失败: %w", err)
+				}
+
+				fmt.Printf("✅ 已导入 %s (%s): %d 个表格, %d 个字段, %d 个视图, %d 条记录, %d 个附件\n",
+					result.Name, result.BaseID, result.Tables, result.Fields, result.Views, result.Records, result.Attachments)
+				for _, warning := range result.Warnings {
+					fmt.Printf("⚠️  %s\n", warning)
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&spaceID, "space", "", "目标空间ID")
+	cmd.Flags().StringVar(&userID, "user", "", "导入者用户ID")
+	cmd.Flags().StringVar(&name, "name", "", "新 Base 名称（默认沿用归档中的名称）")
+	cmd.MarkFlagRequired("space")
+	cmd.MarkFlagRequired("user")
+
+	return cmd
+}
+
+// withContainer 加载配置并初始化依赖注入容器后执行命令
+func withContainer(fn func(ctx context.Context, cont *container.Container) error) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("加载配置失败: %w", err)
+	}
+	if err := logger.Init(logger.LoggerConfig{
+		Level:      cfg.Logger.Level,
+		Format:     cfg.Logger.Format,
+		OutputPath: cfg.Logger.OutputPath,
+	}); err != nil {
+		return fmt.Errorf("初始化日志失败: %w", err)
+	}
+
+	cont := container.NewContainer(cfg)
+	if err := cont.Initialize(); err != nil {
+		return fmt.Errorf("初始化容器失败: %w", err)
+	}
+	defer cont.Close()
+
+	return fn(context.Background(), cont)
+}
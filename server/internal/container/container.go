@@ -11,6 +11,10 @@ import (
 	"github.com/easyspace-ai/luckdb/server/internal/application"
 	fieldService "github.com/easyspace-ai/luckdb/server/internal/application/field"
 	recordService "github.com/easyspace-ai/luckdb/server/internal/application/record"
+	"github.com/easyspace-ai/luckdb/server/internal/application/basearchive"
+	"github.com/easyspace-ai/luckdb/server/internal/application/dataexport"
+	"github.com/easyspace-ai/luckdb/server/internal/application/dataimport"
+	"github.com/easyspace-ai/luckdb/server/internal/application/searchindex"
 	"github.com/easyspace-ai/luckdb/server/internal/config"
 	"github.com/easyspace-ai/luckdb/server/internal/events"
 	infraCache "github.com/easyspace-ai/luckdb/server/internal/infrastructure/cache"
@@ -22,6 +26,7 @@ import (
 
 	// 领域层仓储接口
 	attachmentRepo "github.com/easyspace-ai/luckdb/server/internal/domain/attachment"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/search"
 	baseRepo "github.com/easyspace-ai/luckdb/server/internal/domain/base/repository"
 	collaboratorRepo "github.com/easyspace-ai/luckdb/server/internal/domain/collaborator/repository"
 	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
@@ -69,6 +74,7 @@ type Container struct {
 	viewRepository         viewRepo.ViewRepository
 	attachmentRepository   attachmentRepo.Repository
 	uploadTokenRepository  attachmentRepo.UploadTokenRepository
+	searchRepository       search.Repository
 
 	// 应用服务层
 	errorService        *application.ErrorService // 统一错误处理服务 ✨
@@ -85,6 +91,12 @@ type Container struct {
 	recordService       *application.RecordService
 	viewService         *application.ViewService
 	attachmentService   attachmentRepo.Service
+	searchService       search.Service       // 全局搜索服务 ✨
+	searchIndexer       *searchindex.Indexer // 增量搜索索引器
+	importService       *dataimport.Service  // 数据导入服务
+	exportService       *dataexport.Service  // 数据导出服务
+	baseArchiveService  *basearchive.Service // Base 归档导出与导入服务
+	recordRuleService   *application.RecordRuleService // 表级记录校验规则服务
 
 	// Record专门服务 ✨
 	recordCRUDService      *recordService.RecordCRUDService
@@ -292,6 +304,8 @@ func (c *Container) initRepositories() {
 	// 重新初始化附件仓储以注入 tokenRepo
 	c.attachmentRepository = repository.NewAttachmentRepository(db, c.uploadTokenRepository)
 
+	// 全局搜索仓储
+	c.searchRepository = repository.NewSearchRepository(db)
 }
 
 // initServices 初始化所有应用服务（完美架构）
@@ -398,6 +412,8 @@ func (c *Container) initServices() {
 		c.viewService, // ✅ 注入ViewService
 		c.dbProvider,  // ✅ 注入DBProvider
 	)
+	c.tableService.SetBusinessEventPublisher(c.businessEventManager)
+	c.tableService.SetTableMoveAuthorizer(c.permissionServiceV2)
 
 	// 16. ✨ 初始化模块化计算服务（重构后的架构）
 	c.initCalculationServices()
@@ -423,6 +439,10 @@ func (c *Container) initServices() {
 		c.dbProvider,   // ✨ 添加数据库提供者
 	)
 
+	// 字段类型转换：读取已有数据，转换为关联字段时写入外键
+	c.fieldService.SetRecordRepository(c.recordRepository)
+	c.fieldService.SetLinkDeriver(c.linkService)
+
 	// ✨ Link 字段标题更新服务（在RecordService之前初始化）
 	// 使用适配器将实际仓储接口适配为 link.LinkService 所需的接口
 	linkCalcFieldRepo := linkService.NewLinkFieldRepositoryAdapter(c.fieldRepository)
@@ -443,6 +463,7 @@ func (c *Container) initServices() {
 
 	// ✅ Phase 2: 类型转换服务
 	typecastService := application.NewTypecastService(c.fieldRepository)
+	typecastService.SetReferenceRepositories(c.userRepository, c.recordRepository) // typecast：用户、关联字段按名称转换
 
 	// ✨ 初始化Record专门服务
 	// 注意：typecastService实现了recordService.TypecastService接口
@@ -465,9 +486,94 @@ func (c *Container) initServices() {
 		c.linkService,          // ✨ 注入 Link 字段服务
 		linkTitleUpdateService, // ✨ 注入 Link 字段标题更新服务
 	)
+	c.recordService.SetViewRepository(c.viewRepository) // 支持按视图查询记录
+	c.recordService.SetCursorSecret(c.cfg.JWT.Secret)  // 分页游标签名（派生独立密钥）
+	c.recordService.SetHistoryService(application.NewRecordHistoryService(c.db.GetDB(), c.fieldRepository)) // 记录变更历史
+	c.recordService.SetBatchService(c.batchService)                                                         // 按过滤条件批量更新
+
+	// 表级记录校验规则：写入提交前求值，管理员可跳过
+	c.recordRuleService = application.NewRecordRuleService(
+		repository.NewRecordRuleRepository(c.db.GetDB()),
+		c.tableRepository,
+		c.fieldRepository,
+		c.userRepository,
+	)
+	c.recordRuleService.SetAuthorizer(c.permissionServiceV2)
+	c.recordService.SetRecordRuleService(c.recordRuleService)
 
 	// ✅ 初始化附件服务
 	c.initAttachmentService()
+
+	// 全局搜索服务
+	c.searchService = search.NewService(c.searchRepository, logger.Logger)
+
+	// 增量搜索索引器：订阅表格、字段、记录事件，保持 search_indexes 与数据同步
+	c.searchIndexer = searchindex.NewIndexer(
+		nil,
+		c.searchRepository,
+		c.tableRepository,
+		c.baseRepository,
+		c.fieldRepository,
+		c.recordRepository,
+	)
+	if c.businessEventManager != nil {
+		if err := c.searchIndexer.Start(c.businessEventManager); err != nil {
+			logger.Error("启动搜索索引器失败", logger.ErrorField(err))
+		}
+	}
+
+	// 数据导入服务：按批经 BatchService 写入，进度保存在 task / task_run
+	c.importService = dataimport.NewService(
+		nil,
+		c.tableService,
+		c.tableRepository,
+		c.fieldRepository,
+		typecastService,
+		c.batchService,
+		repository.NewTaskRepository(c.db.GetDB()),
+	)
+	c.importService.SetBusinessEventPublisher(c.businessEventManager)
+	c.importService.SetRecordCalculator(c.calculationService)
+	c.importService.SetRecordRules(c.recordRuleService)
+
+	// 数据导出服务：大表在后台导出，结果文件保存到本地存储的 exports 目录
+	uploadPath := c.cfg.Storage.Local.UploadPath
+	if uploadPath == "" {
+		uploadPath = "./uploads" // 默认值
+	}
+	fileStorage := storage.NewEnhancedLocalStorage(attachmentRepo.LocalStorageConfig{BasePath: uploadPath})
+	c.exportService = dataexport.NewService(
+		nil,
+		c.recordRepository,
+		c.fieldRepository,
+		c.tableRepository,
+		c.viewRepository,
+		repository.NewTaskRepository(c.db.GetDB()),
+		fileStorage,
+	)
+
+	// Base 归档服务：附件文件与附件服务共用上传目录
+	c.baseArchiveService = basearchive.NewService(
+		nil,
+		c.baseRepository,
+		c.tableRepository,
+		c.fieldRepository,
+		c.viewRepository,
+		c.recordRepository,
+		fileStorage,
+		c.attachmentRepository,
+		repository.NewTaskRepository(c.db.GetDB()),
+		c.baseService,
+		c.tableService,
+		c.fieldService,
+		c.viewService,
+		c.batchService,
+	)
+	c.baseArchiveService.SetBusinessEventPublisher(c.businessEventManager)
+	c.baseArchiveService.SetRecordCalculator(c.calculationService)
+	c.baseArchiveService.SetLinkDeriver(c.linkService)
+	c.baseService.SetBaseDuplicator(c.baseArchiveService)
+	c.tableService.SetTableDuplicator(c.baseArchiveService)
 }
 
 // initRecordServices 初始化Record专门服务
@@ -675,6 +781,20 @@ func (c *Container) initCalculationServices() {
 func (c *Container) Close() {
 	logger.Info("正在关闭容器资源...")
 
+	// 0. 停止搜索索引器（处理完已到期的索引任务）与进行中的导入、导出、复制
+	if c.searchIndexer != nil {
+		c.searchIndexer.Stop()
+	}
+	if c.importService != nil {
+		c.importService.Stop()
+	}
+	if c.exportService != nil {
+		c.exportService.Stop()
+	}
+	if c.baseArchiveService != nil {
+		c.baseArchiveService.Stop()
+	}
+
 	// 1. 首先关闭业务事件管理器（停止Redis订阅）
 	if c.businessEventManager != nil {
 		c.businessEventManager.Shutdown()
@@ -844,6 +964,36 @@ func (c *Container) AttachmentService() attachmentRepo.Service {
 	return c.attachmentService
 }
 
+// SearchService 获取全局搜索服务 ✨
+func (c *Container) SearchService() search.Service {
+	return c.searchService
+}
+
+// SearchIndexer 获取搜索索引器
+func (c *Container) SearchIndexer() *searchindex.Indexer {
+	return c.searchIndexer
+}
+
+// ImportService 获取数据导入服务
+func (c *Container) ImportService() *dataimport.Service {
+	return c.importService
+}
+
+// ExportService 获取数据导出服务
+func (c *Container) ExportService() *dataexport.Service {
+	return c.exportService
+}
+
+// BaseArchiveService 获取 Base 归档服务
+func (c *Container) BaseArchiveService() *basearchive.Service {
+	return c.baseArchiveService
+}
+
+// RecordRuleService 获取表级记录校验规则服务
+func (c *Container) RecordRuleService() *application.RecordRuleService {
+	return c.recordRuleService
+}
+
 // CalculationService 获取计算服务 ✨
 func (c *Container) CalculationService() *application.CalculationService {
 	return c.calculationService
@@ -1144,6 +1294,9 @@ func (c *Container) initShareDB(logger *zap.Logger) {
 			broadcaster := application.NewRecordBroadcaster(shareDBService)
 			c.recordService.SetBroadcaster(broadcaster)
 			logger.Info("✅ RecordBroadcaster 已设置")
+
+			// 客户端提交的记录创建操作由 RecordService 处理（默认值、校验、广播）
+			shareDBService.SetRecordCreator(c.recordService)
 		}
 	}
 
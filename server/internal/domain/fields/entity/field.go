@@ -335,7 +335,6 @@ func (f *Field) SetRequired(required bool) error {
 	}
 
 	f.isRequired = required
-	f.notNull = required // 必填字段自动设置非空
 	f.updatedAt = time.Now()
 
 	return nil
@@ -362,6 +361,28 @@ func (f *Field) SetUnique(unique bool) error {
 	return nil
 }
 
+// SetNotNull 设置是否非空
+// 与必填不同，非空约束作用于所有写入路径，由物理列约束保证
+func (f *Field) SetNotNull(notNull bool) error {
+	if f.IsDeleted() {
+		return fields.ErrCannotModifyDeletedField
+	}
+
+	// 虚拟字段不能设置非空约束
+	if f.IsVirtual() && notNull {
+		return fields.NewDomainError(
+			"VIRTUAL_FIELD_CANNOT_BE_NOT_NULL",
+			"virtual field cannot have not-null constraint",
+			nil,
+		)
+	}
+
+	f.notNull = notNull
+	f.updatedAt = time.Now()
+
+	return nil
+}
+
 // SetPrimary 设置为主键
 func (f *Field) SetPrimary(primary bool) error {
 	if f.IsDeleted() {
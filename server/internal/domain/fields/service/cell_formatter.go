@@ -0,0 +1,336 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+// CellFormat 单元格输出格式
+type CellFormat string
+
+const (
+	CellFormatJSON CellFormat = "json" // 原始 JSON 值（默认）
+	CellFormatText CellFormat = "text" // 显示字符串
+)
+
+// ParseCellFormat 解析单元格输出格式，空字符串视为 json
+func ParseCellFormat(s string) (CellFormat, error) {
+	switch CellFormat(strings.ToLower(s)) {
+	case "", CellFormatJSON:
+		return CellFormatJSON, nil
+	case CellFormatText:
+		return CellFormatText, nil
+	}
+	return "", fmt.Errorf("unsupported cell format: %s, must be 'json' or 'text'", s)
+}
+
+// FormatCellText 将单元格值渲染为显示字符串
+//
+// 渲染规则（参考 Teable cellValue2String）：
+//   - 数字：按 FormattingOptions / NumberOptions 的精度、千分位、百分比、货币
+//   - 日期：按 DateFormat/TimeFormat/TimeZone 格式化
+//   - 单选/多选：选项名称（单元格存选项ID时映射为名称）
+//   - 用户/关联/附件：显示名、主字段标题或文件名，多个值以 ", " 连接
+//   - 空值返回空字符串
+func FormatCellText(field *entity.Field, value interface{}) string {
+	if value == nil {
+		return ""
+	}
+
+	switch field.Type().String() {
+	case valueobject.TypeNumber, valueobject.TypePercent, valueobject.TypeCurrency,
+		valueobject.TypeRating, valueobject.TypeRollup, valueobject.TypeCount, valueobject.TypeAutoNumber:
+		return joinCellValues(value, func(v interface{}) string { return formatNumberCell(field, v) })
+	case valueobject.TypeDate, valueobject.TypeDateTime, valueobject.TypeCreatedTime,
+		valueobject.TypeModifiedTime:
+		return joinCellValues(value, func(v interface{}) string { return formatDateCell(field, v) })
+	case valueobject.TypeCheckbox, valueobject.TypeBoolean:
+		if b, ok := value.(bool); ok {
+			return strconv.FormatBool(b)
+		}
+	case valueobject.TypeSelect, valueobject.TypeSingleSelect, valueobject.TypeMultipleSelect:
+		return joinCellValues(value, func(v interface{}) string { return selectChoiceName(field, v) })
+	case valueobject.TypeFormula:
+		if _, ok := toFloat(value); ok && cellFormatting(field) != nil {
+			return formatNumberCell(field, value)
+		}
+	}
+
+	return joinCellValues(value, objectTitle)
+}
+
+// cellFormatting 获取字段的通用格式化配置
+func cellFormatting(field *entity.Field) *valueobject.FormattingOptions {
+	options := field.Options()
+	if options == nil {
+		return nil
+	}
+	switch {
+	case options.Formatting != nil:
+		return options.Formatting
+	case options.Formula != nil && options.Formula.Formatting != nil:
+		return options.Formula.Formatting
+	case options.Rollup != nil && options.Rollup.Formatting != nil:
+		return options.Rollup.Formatting
+	case options.Lookup != nil && options.Lookup.Formatting != nil:
+		return options.Lookup.Formatting
+	}
+	return nil
+}
+
+// joinCellValues 对数组逐项渲染并以 ", " 连接
+func joinCellValues(value interface{}, format func(interface{}) string) string {
+	items, ok := value.([]interface{})
+	if !ok {
+		return format(value)
+	}
+
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		if text := format(item); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// objectTitle 渲染对象单元格（用户/关联/附件等）
+func objectTitle(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		for _, key := range []string{"title", "name", "email", "id"} {
+			if s, ok := v[key].(string); ok && s != "" {
+				return s
+			}
+		}
+		raw, _ := json.Marshal(v)
+		return string(raw)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// selectChoiceName 将选项ID映射为名称
+func selectChoiceName(field *entity.Field, value interface{}) string {
+	s, ok := value.(string)
+	if !ok {
+		return objectTitle(value)
+	}
+	if options := field.Options(); options != nil && options.Select != nil {
+		for _, choice := range options.Select.Choices {
+			if choice.ID == s {
+				return choice.Name
+			}
+		}
+	}
+	return s
+}
+
+// ==================== 数字 ====================
+
+func formatNumberCell(field *entity.Field, value interface{}) string {
+	num, ok := toFloat(value)
+	if !ok {
+		return objectTitle(value)
+	}
+
+	precision := -1
+	showCommas := false
+	format := ""
+	currency := ""
+
+	if options := field.Options(); options != nil && options.Number != nil {
+		if options.Number.Precision != nil {
+			precision = *options.Number.Precision
+		}
+		showCommas = options.Number.ShowCommas
+		format = options.Number.Format
+		currency = options.Number.Currency
+	}
+	if formatting := cellFormatting(field); formatting != nil {
+		if formatting.Precision != nil {
+			precision = *formatting.Precision
+		}
+		showCommas = showCommas || formatting.ShowCommas
+		if formatting.Type != "" {
+			format = formatting.Type
+		}
+		if formatting.Currency != "" {
+			currency = formatting.Currency
+		}
+	}
+	if format == "" {
+		switch field.Type().String() {
+		case valueobject.TypePercent:
+			format = "percent"
+		case valueobject.TypeCurrency:
+			format = "currency"
+		}
+	}
+
+	switch format {
+	case "percent":
+		if precision < 0 {
+			precision = 2
+		}
+		return formatDecimal(num*100, precision, showCommas) + "%"
+	case "currency":
+		if precision < 0 {
+			precision = 2
+		}
+		if currency == "" {
+			currency = "USD"
+		}
+		return currency + " " + formatDecimal(num, precision, showCommas)
+	}
+	return formatDecimal(num, precision, showCommas)
+}
+
+// formatDecimal 按精度格式化（precision < 0 表示不限制），可选千分位
+func formatDecimal(num float64, precision int, showCommas bool) string {
+	text := strconv.FormatFloat(num, 'f', precision, 64)
+	if !showCommas {
+		return text
+	}
+
+	sign := ""
+	if strings.HasPrefix(text, "-") {
+		sign, text = "-", text[1:]
+	}
+	intPart, fracPart, hasFrac := strings.Cut(text, ".")
+
+	var b strings.Builder
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	if hasFrac {
+		return sign + b.String() + "." + fracPart
+	}
+	return sign + b.String()
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, !math.IsNaN(v)
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// ==================== 日期 ====================
+
+// dateTokenReplacer 将 dayjs 风格的格式（YYYY-MM-DD HH:mm）转换为 Go 布局
+var dateTokenReplacer = strings.NewReplacer(
+	"YYYY", "2006",
+	"YY", "06",
+	"MM", "01",
+	"DD", "02",
+	"HH", "15",
+	"hh", "03",
+	"mm", "04",
+	"ss", "05",
+	"A", "PM",
+)
+
+func formatDateCell(field *entity.Field, value interface{}) string {
+	t, ok := toTime(value)
+	if !ok {
+		return objectTitle(value)
+	}
+
+	dateFormat := "YYYY-MM-DD"
+	timeFormat := ""
+	timeZone := ""
+
+	if options := field.Options(); options != nil && options.Date != nil {
+		if options.Date.Format != "" {
+			dateFormat = options.Date.Format
+		}
+		if options.Date.IncludeTime {
+			timeFormat = "HH:mm"
+			if options.Date.TimeFormat == "12h" {
+				timeFormat = "hh:mm A"
+			}
+		}
+		timeZone = options.Date.TimeZone
+	}
+	if formatting := cellFormatting(field); formatting != nil {
+		if formatting.DateFormat != "" {
+			dateFormat = formatting.DateFormat
+		}
+		if formatting.TimeFormat != "" {
+			timeFormat = formatting.TimeFormat
+			switch timeFormat {
+			case "24h":
+				timeFormat = "HH:mm"
+			case "12h":
+				timeFormat = "hh:mm A"
+			case "none", "None":
+				timeFormat = ""
+			}
+		}
+		if formatting.TimeZone != "" {
+			timeZone = formatting.TimeZone
+		}
+	}
+
+	if timeZone != "" {
+		if loc, err := time.LoadLocation(timeZone); err == nil {
+			t = t.In(loc)
+		}
+	}
+
+	layout := dateTokenReplacer.Replace(dateFormat)
+	if timeFormat != "" {
+		layout += " " + dateTokenReplacer.Replace(timeFormat)
+	}
+	return t.Format(layout)
+}
+
+func toTime(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case *time.Time:
+		if v != nil {
+			return *v, true
+		}
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"} {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
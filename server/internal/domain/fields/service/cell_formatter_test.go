@@ -0,0 +1,75 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+func newFormatterTestField(fieldType string, options *valueobject.FieldOptions) *entity.Field {
+	ft, _ := valueobject.NewFieldType(fieldType)
+	name, _ := valueobject.NewFieldName("field")
+	dbFieldName, _ := valueobject.NewDBFieldNameFromString("field")
+	if options == nil {
+		options = valueobject.NewFieldOptions()
+	}
+	return entity.ReconstructField(
+		valueobject.NewFieldID("fld_test"), "tbl_test", name, ft, dbFieldName, "TEXT",
+		options, 0, 1, "usr_test", time.Now(), time.Now(),
+	)
+}
+
+func TestFormatCellText_Number(t *testing.T) {
+	precision := 2
+	options := valueobject.NewFieldOptions()
+	options.Number = &valueobject.NumberOptions{Precision: &precision, ShowCommas: true}
+	field := newFormatterTestField(valueobject.TypeNumber, options)
+
+	assert.Equal(t, "1,234,567.89", FormatCellText(field, 1234567.891))
+	assert.Equal(t, "-1,000.00", FormatCellText(field, -1000.0))
+	assert.Equal(t, "", FormatCellText(field, nil))
+
+	percent := newFormatterTestField(valueobject.TypePercent, nil)
+	assert.Equal(t, "12.50%", FormatCellText(percent, 0.125))
+}
+
+func TestFormatCellText_Date(t *testing.T) {
+	options := valueobject.NewFieldOptions()
+	options.Formatting = &valueobject.FormattingOptions{DateFormat: "YYYY/MM/DD", TimeFormat: "HH:mm", TimeZone: "Asia/Shanghai"}
+	field := newFormatterTestField(valueobject.TypeDate, options)
+
+	assert.Equal(t, "2024/03/01 18:30", FormatCellText(field, "2024-03-01T10:30:00Z"))
+	assert.Equal(t, "2024-03-01", FormatCellText(newFormatterTestField(valueobject.TypeDate, nil), "2024-03-01T10:30:00Z"))
+}
+
+func TestFormatCellText_SelectAndObjects(t *testing.T) {
+	field := newFormatterTestField(valueobject.TypeMultipleSelect,
+		valueobject.NewFieldOptions().WithSelect([]valueobject.SelectChoice{{ID: "cho_a", Name: "Alpha"}}))
+	assert.Equal(t, "Alpha, Beta", FormatCellText(field, []interface{}{"cho_a", "Beta"}))
+
+	user := newFormatterTestField(valueobject.TypeUser, nil)
+	assert.Equal(t, "Alice, bob@example.com", FormatCellText(user, []interface{}{
+		map[string]interface{}{"id": "usr_1", "title": "Alice"},
+		map[string]interface{}{"id": "usr_2", "email": "bob@example.com"},
+	}))
+
+	link := newFormatterTestField(valueobject.TypeLink, nil)
+	assert.Equal(t, "Project X", FormatCellText(link, map[string]interface{}{"id": "rec_1", "title": "Project X"}))
+}
+
+func TestParseCellFormat(t *testing.T) {
+	format, err := ParseCellFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, CellFormatJSON, format)
+
+	format, err = ParseCellFormat("TEXT")
+	assert.NoError(t, err)
+	assert.Equal(t, CellFormatText, format)
+
+	_, err = ParseCellFormat("csv")
+	assert.Error(t, err)
+}
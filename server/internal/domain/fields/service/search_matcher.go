@@ -0,0 +1,205 @@
+package service
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mozillazg/go-pinyin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+// 表内全文搜索
+//
+// 可搜索字段：文本（text/singleLineText/longText/email/url/phone）、单选/多选（按选项名称）、关联（按标题）。
+// 关键词按不区分大小写的子串匹配；关键词是拼音时（如 "zhangsan"）同时匹配对应读音的汉字（如 "张三"）
+
+const (
+	// maxPinyinQueryLength 参与拼音匹配的关键词最大长度（字母数）
+	maxPinyinQueryLength = 24
+	// maxPinyinSegmentations 拼音切分方案上限（如 "xian" 可切分为 "xian" 或 "xi an"）
+	maxPinyinSegmentations = 8
+)
+
+var (
+	pinyinIndexOnce sync.Once
+	pinyinIndex     map[string][]rune // 拼音音节（不带声调）-> 汉字
+	maxSyllableLen  int
+)
+
+// loadPinyinIndex 由 go-pinyin 字典构建音节到汉字的反向索引（只收录 CJK 基本区，含多音字）
+func loadPinyinIndex() {
+	pinyinIndexOnce.Do(func() {
+		args := pinyin.NewArgs()
+		args.Style = pinyin.Normal
+		args.Heteronym = true
+
+		index := make(map[string][]rune)
+		for code := range pinyin.PinyinDict {
+			if code < 0x4E00 || code > 0x9FFF {
+				continue
+			}
+			for _, syllable := range pinyin.SinglePinyin(rune(code), args) {
+				index[syllable] = append(index[syllable], rune(code))
+				if len(syllable) > maxSyllableLen {
+					maxSyllableLen = len(syllable)
+				}
+			}
+		}
+		for syllable := range index {
+			chars := index[syllable]
+			sort.Slice(chars, func(i, j int) bool { return chars[i] < chars[j] })
+		}
+		pinyinIndex = index
+	})
+}
+
+// SearchMatcher 搜索关键词匹配器
+type SearchMatcher struct {
+	query         string
+	lower         string
+	pinyinPattern string
+	pinyinRegexp  *regexp.Regexp
+}
+
+// NewSearchMatcher 创建搜索匹配器，关键词为空时返回 nil
+func NewSearchMatcher(query string) *SearchMatcher {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+
+	m := &SearchMatcher{query: query, lower: strings.ToLower(query)}
+	if pattern := PinyinPattern(query); pattern != "" {
+		if re, err := regexp.Compile(pattern); err == nil {
+			m.pinyinPattern = pattern
+			m.pinyinRegexp = re
+		}
+	}
+	return m
+}
+
+// Query 搜索关键词
+func (m *SearchMatcher) Query() string {
+	return m.query
+}
+
+// PinyinPattern 拼音关键词对应的汉字正则（PostgreSQL 与 Go 正则通用），关键词不是拼音时为空
+func (m *SearchMatcher) PinyinPattern() string {
+	return m.pinyinPattern
+}
+
+// MatchText 文本是否包含关键词（不区分大小写）或关键词拼音对应的汉字
+func (m *SearchMatcher) MatchText(text string) bool {
+	if text == "" {
+		return false
+	}
+	if strings.Contains(strings.ToLower(text), m.lower) {
+		return true
+	}
+	return m.pinyinRegexp != nil && m.pinyinRegexp.MatchString(text)
+}
+
+// MatchChoiceIDs 名称匹配关键词的选项ID
+func (m *SearchMatcher) MatchChoiceIDs(field *entity.Field) []string {
+	options := field.Options()
+	if options == nil || options.Select == nil {
+		return nil
+	}
+
+	var ids []string
+	for _, choice := range options.Select.Choices {
+		if m.MatchText(choice.Name) {
+			ids = append(ids, choice.ID)
+		}
+	}
+	return ids
+}
+
+// MatchedFields 记录中匹配关键词的可搜索字段ID（按字段顺序），用于客户端高亮
+func (m *SearchMatcher) MatchedFields(fields []*entity.Field, data map[string]interface{}) []string {
+	var matched []string
+	for _, field := range fields {
+		if !IsSearchableField(field) {
+			continue
+		}
+		value, ok := data[field.ID().String()]
+		if !ok || value == nil {
+			continue
+		}
+		if m.MatchText(FormatCellText(field, value)) {
+			matched = append(matched, field.ID().String())
+		}
+	}
+	return matched
+}
+
+// IsSearchableField 字段是否参与全文搜索
+func IsSearchableField(field *entity.Field) bool {
+	if field == nil || field.IsDeleted() {
+		return false
+	}
+	switch field.Type().String() {
+	case valueobject.TypeText, valueobject.TypeSingleLineText, valueobject.TypeLongText,
+		valueobject.TypeEmail, valueobject.TypeURL, valueobject.TypePhone,
+		valueobject.TypeSelect, valueobject.TypeSingleSelect, valueobject.TypeMultipleSelect,
+		valueobject.TypeLink:
+		return true
+	}
+	return false
+}
+
+// PinyinPattern 将拼音关键词转换为汉字正则
+//
+// 关键词须全部由字母组成（可用空格分隔音节）并能完整切分为音节，否则返回空字符串。
+// 每个音节转换为同音汉字的字符组，多种切分方案以 | 组合：
+// "zhangsan" -> (?:[张章...][三叁...])
+func PinyinPattern(query string) string {
+	letters := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(query), " ", ""))
+	if len(letters) < 2 || len(letters) > maxPinyinQueryLength {
+		return ""
+	}
+	for _, r := range letters {
+		if r < 'a' || r > 'z' {
+			return ""
+		}
+	}
+
+	loadPinyinIndex()
+	segmentations := segmentPinyin(letters, nil, nil)
+	if len(segmentations) == 0 {
+		return ""
+	}
+
+	alternatives := make([]string, 0, len(segmentations))
+	for _, syllables := range segmentations {
+		var b strings.Builder
+		for _, syllable := range syllables {
+			b.WriteByte('[')
+			b.WriteString(string(pinyinIndex[syllable]))
+			b.WriteByte(']')
+		}
+		alternatives = append(alternatives, b.String())
+	}
+	return "(?:" + strings.Join(alternatives, "|") + ")"
+}
+
+// segmentPinyin 枚举字母串切分为拼音音节的方案（最多 maxPinyinSegmentations 种）
+func segmentPinyin(rest string, prefix []string, result [][]string) [][]string {
+	if rest == "" {
+		return append(result, append([]string(nil), prefix...))
+	}
+	// 优先尝试较长的音节，常见切分排在前面
+	for n := min(maxSyllableLen, len(rest)); n >= 1; n-- {
+		if len(result) >= maxPinyinSegmentations {
+			break
+		}
+		if _, ok := pinyinIndex[rest[:n]]; ok {
+			result = segmentPinyin(rest[n:], append(prefix, rest[:n]), result)
+		}
+	}
+	return result
+}
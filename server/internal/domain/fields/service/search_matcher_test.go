@@ -0,0 +1,62 @@
+package service
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+func TestPinyinPattern(t *testing.T) {
+	pattern := PinyinPattern("zhangsan")
+	require.NotEmpty(t, pattern)
+
+	re := regexp.MustCompile(pattern)
+	assert.True(t, re.MatchString("张三"))
+	assert.True(t, re.MatchString("客户：张三丰"))
+	assert.False(t, re.MatchString("李四"))
+
+	// 空格分隔音节、大小写不敏感
+	assert.True(t, regexp.MustCompile(PinyinPattern("Zhang San")).MatchString("张三"))
+	// 多种切分方案："xian" 既可以是 "先" 也可以是 "西安"
+	re = regexp.MustCompile(PinyinPattern("xian"))
+	assert.True(t, re.MatchString("先"))
+	assert.True(t, re.MatchString("西安"))
+
+	assert.Empty(t, PinyinPattern("张三"))
+	assert.Empty(t, PinyinPattern("abc123"))
+	assert.Empty(t, PinyinPattern("zzzz"))
+}
+
+func TestSearchMatcher(t *testing.T) {
+	assert.Nil(t, NewSearchMatcher("  "))
+
+	m := NewSearchMatcher("ACME")
+	assert.True(t, m.MatchText("acme corp"))
+	assert.False(t, m.MatchText("other"))
+
+	m = NewSearchMatcher("zhangsan")
+	assert.NotEmpty(t, m.PinyinPattern())
+	assert.True(t, m.MatchText("张三"))
+	assert.True(t, m.MatchText("ZhangSan"))
+}
+
+func TestSearchMatcher_MatchedFields(t *testing.T) {
+	options := valueobject.NewFieldOptions()
+	options.Select = &valueobject.SelectOptions{Choices: []valueobject.SelectChoice{
+		{ID: "cho_1", Name: "进行中"},
+		{ID: "cho_2", Name: "已完成"},
+	}}
+	status := newFormatterTestField(valueobject.TypeSingleSelect, options)
+	amount := newFormatterTestField(valueobject.TypeNumber, nil)
+
+	m := NewSearchMatcher("wancheng")
+	assert.Equal(t, []string{"cho_2"}, m.MatchChoiceIDs(status))
+	assert.Equal(t, []string{"fld_test"}, m.MatchedFields([]*entity.Field{status, amount}, map[string]interface{}{"fld_test": "cho_2"}))
+	assert.Empty(t, m.MatchedFields([]*entity.Field{status}, map[string]interface{}{"fld_test": "cho_1"}))
+	assert.False(t, IsSearchableField(amount))
+}
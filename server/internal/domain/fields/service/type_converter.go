@@ -0,0 +1,214 @@
+package service
+
+import (
+	"math"
+	"strings"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/validation"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+// defaultRatingMax 评分字段未配置最大值时的默认星数
+const defaultRatingMax = 5
+
+// checkboxWords 文本转为复选框时可识别的写法
+var checkboxWords = map[string]bool{
+	"true": true, "yes": true, "y": true, "1": true, "on": true, "checked": true, "x": true, "✓": true, "是": true, "对": true,
+	"false": false, "no": false, "n": false, "0": false, "off": false, "unchecked": false, "否": false, "错": false,
+}
+
+// CellConverter 字段类型转换时逐格改写单元格值
+//
+// from 与 to 是同一字段转换前后的两个实例。转换为选择字段时，未匹配的选项名称按
+// PreventAutoNewOptions 追加到 to 的选项中，由调用方持久化
+type CellConverter struct {
+	from       *entity.Field
+	to         *entity.Field
+	linkTitles map[string]string
+}
+
+// NewCellConverter 创建单元格转换器
+//
+// linkTitles 仅在转换为关联字段时使用：被关联表显示字段的标题 → 记录ID，重名的标题映射为空字符串
+func NewCellConverter(from, to *entity.Field, linkTitles map[string]string) *CellConverter {
+	return &CellConverter{from: from, to: to, linkTitles: linkTitles}
+}
+
+// Convert 转换单元格值
+//
+// lost 为 true 表示非空的原值转换后为空，或丢失了部分内容（多选转单选、关联标题不匹配、评分取整等）
+func (c *CellConverter) Convert(value interface{}) (converted interface{}, lost bool) {
+	if isEmptyCell(value) {
+		return nil, false
+	}
+
+	switch c.to.Type().String() {
+	case valueobject.TypeText, valueobject.TypeSingleLineText, valueobject.TypeLongText,
+		valueobject.TypeEmail, valueobject.TypeURL, valueobject.TypePhone:
+		if text := FormatCellText(c.from, value); text != "" {
+			return text, false
+		}
+		return nil, true
+	case valueobject.TypeNumber, valueobject.TypePercent, valueobject.TypeCurrency,
+		valueobject.TypeDuration:
+		if num, ok := c.number(value); ok {
+			return num, false
+		}
+		return nil, true
+	case valueobject.TypeRating:
+		return c.rating(value)
+	case valueobject.TypeDate, valueobject.TypeDateTime:
+		if t, ok := validation.CoerceDate(value); ok {
+			return t, false
+		}
+		if t, ok := validation.CoerceDate(FormatCellText(c.from, value)); ok {
+			return t, false
+		}
+		return nil, true
+	case valueobject.TypeCheckbox, valueobject.TypeBoolean:
+		checked, ok := c.checkbox(value)
+		if !ok {
+			return nil, true
+		}
+		if !checked {
+			return nil, false // 未勾选存为空
+		}
+		return true, false
+	case valueobject.TypeSelect, valueobject.TypeSingleSelect, valueobject.TypeMultipleSelect:
+		return c.selectValue(value)
+	case valueobject.TypeLink:
+		return c.linkValue(value)
+	}
+	return value, false
+}
+
+// number 转为数字：复选框为 1/0，其余先按原值、再按显示字符串解析
+func (c *CellConverter) number(value interface{}) (float64, bool) {
+	if !isSelectField(c.from) {
+		if num, ok := validation.CoerceNumber(value); ok {
+			return num, true
+		}
+	}
+	return validation.CoerceNumber(FormatCellText(c.from, value))
+}
+
+// rating 转为评分：四舍五入并截断到 [1, max]，小于 1 视为空
+func (c *CellConverter) rating(value interface{}) (interface{}, bool) {
+	num, ok := c.number(value)
+	if !ok {
+		return nil, true
+	}
+	max := defaultRatingMax
+	if options := c.to.Options(); options != nil && options.Rating != nil && options.Rating.Max > 0 {
+		max = options.Rating.Max
+	}
+	rating := math.Min(math.Round(num), float64(max))
+	if rating < 1 {
+		return nil, true
+	}
+	return rating, rating != num
+}
+
+// checkbox 转为勾选状态：数字非零为勾选，文本按 checkboxWords 识别
+func (c *CellConverter) checkbox(value interface{}) (bool, bool) {
+	switch v := value.(type) {
+	case bool:
+		return v, true
+	case string:
+		checked, ok := checkboxWords[strings.ToLower(strings.TrimSpace(v))]
+		if ok {
+			return checked, true
+		}
+	}
+	if num, ok := validation.CoerceNumber(value); ok {
+		return num != 0, true
+	}
+	return false, false
+}
+
+// selectValue 转为选项ID：选择字段之间按原选项名称匹配，其余按显示字符串匹配（多选按逗号拆分）
+func (c *CellConverter) selectValue(value interface{}) (interface{}, bool) {
+	multiple := c.to.Type().String() == valueobject.TypeMultipleSelect
+
+	var names []string
+	if isSelectField(c.from) {
+		for _, id := range validation.TypecastStrings(value, false) {
+			names = append(names, selectChoiceName(c.from, id))
+		}
+	} else {
+		names = validation.TypecastStrings(FormatCellText(c.from, value), multiple)
+	}
+
+	ids, _, unknown := validation.ResolveSelectChoices(c.to, names)
+	lost := len(unknown) > 0
+	if len(ids) == 0 {
+		return nil, true
+	}
+	if !multiple {
+		return ids[0], lost || len(ids) > 1
+	}
+	values := make([]interface{}, len(ids))
+	for i, id := range ids {
+		values[i] = id
+	}
+	return values, lost
+}
+
+// linkValue 转为关联：显示字符串按被关联表显示字段的标题匹配记录，未匹配或重名的标题丢弃
+func (c *CellConverter) linkValue(value interface{}) (interface{}, bool) {
+	multiple := isMultipleLink(c.to)
+	titles := validation.TypecastStrings(FormatCellText(c.from, value), multiple)
+
+	links := make([]interface{}, 0, len(titles))
+	lost := false
+	for _, title := range titles {
+		id := c.linkTitles[title]
+		if id == "" {
+			lost = true
+			continue
+		}
+		links = append(links, map[string]interface{}{"id": id, "title": title})
+	}
+	if len(links) == 0 {
+		return nil, true
+	}
+	if !multiple {
+		return links[0], lost || len(links) > 1
+	}
+	return links, lost
+}
+
+// isEmptyCell 空值、空字符串与空数组视为空单元格
+func isEmptyCell(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+func isSelectField(field *entity.Field) bool {
+	switch field.Type().String() {
+	case valueobject.TypeSelect, valueobject.TypeSingleSelect, valueobject.TypeMultipleSelect:
+		return true
+	}
+	return false
+}
+
+// isMultipleLink 关联字段是否允许多条记录
+func isMultipleLink(field *entity.Field) bool {
+	options := field.Options()
+	if options == nil || options.Link == nil {
+		return false
+	}
+	switch options.Link.Relationship {
+	case "manyMany", "oneMany", "many_to_many", "one_to_many":
+		return true
+	}
+	return options.Link.AllowMultiple
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+func TestCellConverter_TextAndNumber(t *testing.T) {
+	text := newFormatterTestField(valueobject.TypeSingleLineText, nil)
+	number := newFormatterTestField(valueobject.TypeNumber, nil)
+
+	toNumber := NewCellConverter(text, number, nil)
+	value, lost := toNumber.Convert("1,234.5")
+	assert.Equal(t, 1234.5, value)
+	assert.False(t, lost)
+
+	value, lost = toNumber.Convert("abc")
+	assert.Nil(t, value)
+	assert.True(t, lost)
+
+	value, lost = toNumber.Convert("")
+	assert.Nil(t, value)
+	assert.False(t, lost)
+
+	percent := newFormatterTestField(valueobject.TypePercent, nil)
+	value, lost = NewCellConverter(percent, text, nil).Convert(0.125)
+	assert.Equal(t, "12.50%", value)
+	assert.False(t, lost)
+}
+
+func TestCellConverter_Rating(t *testing.T) {
+	number := newFormatterTestField(valueobject.TypeNumber, nil)
+	options := valueobject.NewFieldOptions()
+	options.Rating = &valueobject.RatingOptions{Max: 5}
+	rating := newFormatterTestField(valueobject.TypeRating, options)
+	converter := NewCellConverter(number, rating, nil)
+
+	value, lost := converter.Convert(4.0)
+	assert.Equal(t, 4.0, value)
+	assert.False(t, lost)
+
+	value, lost = converter.Convert(3.6)
+	assert.Equal(t, 4.0, value)
+	assert.True(t, lost)
+
+	value, lost = converter.Convert(12.0)
+	assert.Equal(t, 5.0, value)
+	assert.True(t, lost)
+
+	value, lost = converter.Convert(0.0)
+	assert.Nil(t, value)
+	assert.True(t, lost)
+}
+
+func TestCellConverter_DateAndCheckbox(t *testing.T) {
+	text := newFormatterTestField(valueobject.TypeText, nil)
+	date := newFormatterTestField(valueobject.TypeDate, nil)
+
+	value, lost := NewCellConverter(text, date, nil).Convert("2024/03/01")
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), value)
+	assert.False(t, lost)
+
+	_, lost = NewCellConverter(text, date, nil).Convert("next week")
+	assert.True(t, lost)
+
+	checkbox := newFormatterTestField(valueobject.TypeCheckbox, nil)
+	converter := NewCellConverter(text, checkbox, nil)
+	value, lost = converter.Convert("Yes")
+	assert.Equal(t, true, value)
+	assert.False(t, lost)
+	value, lost = converter.Convert("否")
+	assert.Nil(t, value)
+	assert.False(t, lost)
+	_, lost = converter.Convert("maybe")
+	assert.True(t, lost)
+}
+
+func TestCellConverter_Select(t *testing.T) {
+	choices := []valueobject.SelectChoice{{ID: "cho_a", Name: "Alpha"}, {ID: "cho_b", Name: "Beta"}}
+	multiple := newFormatterTestField(valueobject.TypeMultipleSelect, valueobject.NewFieldOptions().WithSelect(choices))
+	single := newFormatterTestField(valueobject.TypeSingleSelect, valueobject.NewFieldOptions().WithSelect(choices))
+
+	value, lost := NewCellConverter(multiple, single, nil).Convert([]interface{}{"cho_b", "cho_a"})
+	assert.Equal(t, "cho_b", value)
+	assert.True(t, lost)
+
+	value, lost = NewCellConverter(single, multiple, nil).Convert("cho_a")
+	assert.Equal(t, []interface{}{"cho_a"}, value)
+	assert.False(t, lost)
+
+	text := newFormatterTestField(valueobject.TypeText, nil)
+	target := newFormatterTestField(valueobject.TypeMultipleSelect, valueobject.NewFieldOptions().WithSelect(choices))
+	value, lost = NewCellConverter(text, target, nil).Convert("beta, Gamma")
+	require.IsType(t, []interface{}{}, value)
+	ids := value.([]interface{})
+	require.Len(t, ids, 2)
+	assert.Equal(t, "cho_b", ids[0])
+	assert.False(t, lost)
+	require.Len(t, target.Options().Select.Choices, 3)
+	assert.Equal(t, "Gamma", target.Options().Select.Choices[2].Name)
+	assert.Equal(t, target.Options().Select.Choices[2].ID, ids[1])
+}
+
+func TestCellConverter_Link(t *testing.T) {
+	text := newFormatterTestField(valueobject.TypeText, nil)
+	options := valueobject.NewFieldOptions()
+	options.Link = &valueobject.LinkOptions{LinkedTableID: "tbl_other", Relationship: "manyMany"}
+	link := newFormatterTestField(valueobject.TypeLink, options)
+	titles := map[string]string{"Alice": "rec_a", "Bob": "rec_b", "Twin": ""}
+
+	value, lost := NewCellConverter(text, link, titles).Convert("Alice, Bob")
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"id": "rec_a", "title": "Alice"},
+		map[string]interface{}{"id": "rec_b", "title": "Bob"},
+	}, value)
+	assert.False(t, lost)
+
+	value, lost = NewCellConverter(text, link, titles).Convert("Alice, Twin, Carol")
+	assert.Len(t, value, 1)
+	assert.True(t, lost)
+
+	value, lost = NewCellConverter(link, text, nil).Convert([]interface{}{
+		map[string]interface{}{"id": "rec_a", "title": "Alice"},
+	})
+	assert.Equal(t, "Alice", value)
+	assert.False(t, lost)
+}
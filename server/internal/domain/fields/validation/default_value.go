@@ -0,0 +1,83 @@
+package validation
+
+import (
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+// 字段默认值
+// 参考旧系统: field default value —— 创建记录时，请求中省略的字段按字段选项中的 defaultValue 填充
+
+// DefaultCellValue 计算字段的静态默认值（数字、日期、单选/多选、文本）
+//
+// 日期默认值 "now" 取 now；选择字段的默认值按选项ID或名称匹配，已不存在的选项被忽略。
+// 用户字段的默认值需要查询用户，由调用方处理；没有默认值时返回 false
+func DefaultCellValue(field *entity.Field, now time.Time) (interface{}, bool) {
+	options := field.Options()
+	if options == nil || field.IsComputed() {
+		return nil, false
+	}
+
+	switch field.Type().String() {
+	case valueobject.TypeNumber, valueobject.TypePercent, valueobject.TypeCurrency, valueobject.TypeRating:
+		if options.Number != nil && options.Number.DefaultValue != nil {
+			return *options.Number.DefaultValue, true
+		}
+	case valueobject.TypeDate, valueobject.TypeDateTime:
+		if options.Date == nil || options.Date.DefaultValue == nil {
+			return nil, false
+		}
+		defaultValue := strings.TrimSpace(*options.Date.DefaultValue)
+		if strings.EqualFold(defaultValue, valueobject.DateDefaultNow) {
+			return now, true
+		}
+		if t, ok := CoerceDate(defaultValue); ok {
+			return t, true
+		}
+	case valueobject.TypeSelect, valueobject.TypeSingleSelect, valueobject.TypeMultipleSelect:
+		if options.Select == nil || options.Select.DefaultValue == nil {
+			return nil, false
+		}
+		multiple := field.Type().String() == valueobject.TypeMultipleSelect
+		ids := make([]interface{}, 0)
+		for _, name := range TypecastStrings(options.Select.DefaultValue, false) {
+			if id, ok := matchSelectChoice(options.Select.Choices, name); ok {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			return nil, false
+		}
+		if !multiple {
+			return ids[0], true
+		}
+		return ids, true
+	case valueobject.TypeText, valueobject.TypeSingleLineText, valueobject.TypeLongText,
+		valueobject.TypeEmail, valueobject.TypeURL, valueobject.TypePhone:
+		if options.Text != nil && options.Text.DefaultValue != nil && *options.Text.DefaultValue != "" {
+			return *options.Text.DefaultValue, true
+		}
+	}
+	return nil, false
+}
+
+// UserDefaultKeys 用户字段默认值中的用户ID列表，"me" 替换为 currentUserID（为空时忽略）
+func UserDefaultKeys(field *entity.Field, currentUserID string) []string {
+	options := field.Options()
+	if options == nil || options.User == nil || options.User.DefaultValue == nil {
+		return nil
+	}
+	keys := make([]string, 0)
+	for _, key := range TypecastStrings(options.User.DefaultValue, false) {
+		if key == valueobject.UserDefaultCurrentUser {
+			key = currentUserID
+		}
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
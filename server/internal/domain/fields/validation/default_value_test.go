@@ -0,0 +1,79 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+func newDefaultTestField(t *testing.T, fieldType string, options *valueobject.FieldOptions) *entity.Field {
+	ft, err := valueobject.NewFieldType(fieldType)
+	require.NoError(t, err)
+	name, err := valueobject.NewFieldName("Field " + fieldType)
+	require.NoError(t, err)
+	field, err := entity.NewField("tbl_test", name, ft, "usr_test")
+	require.NoError(t, err)
+	require.NoError(t, field.UpdateOptions(options))
+	return field
+}
+
+func TestDefaultCellValue(t *testing.T) {
+	now := time.Date(2024, 3, 5, 8, 30, 0, 0, time.UTC)
+	amount := 10.5
+	nowText := "now"
+	fixedDate := "2024-01-02"
+	text := "待处理"
+	empty := ""
+
+	cases := []struct {
+		name      string
+		fieldType string
+		options   *valueobject.FieldOptions
+		want      interface{}
+		ok        bool
+	}{
+		{"数字", valueobject.TypeNumber, &valueobject.FieldOptions{Number: &valueobject.NumberOptions{DefaultValue: &amount}}, 10.5, true},
+		{"日期取当前时间", valueobject.TypeDate, &valueobject.FieldOptions{Date: &valueobject.DateOptions{DefaultValue: &nowText}}, now, true},
+		{"固定日期", valueobject.TypeDate, &valueobject.FieldOptions{Date: &valueobject.DateOptions{DefaultValue: &fixedDate}}, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), true},
+		{"单选按名称匹配", valueobject.TypeSingleSelect, &valueobject.FieldOptions{Select: &valueobject.SelectOptions{
+			Choices:      []valueobject.SelectChoice{{ID: "cho_todo", Name: "Todo"}, {ID: "cho_done", Name: "Done"}},
+			DefaultValue: "Done",
+		}}, "cho_done", true},
+		{"多选忽略不存在的选项", valueobject.TypeMultipleSelect, &valueobject.FieldOptions{Select: &valueobject.SelectOptions{
+			Choices:      []valueobject.SelectChoice{{ID: "cho_a", Name: "A"}, {ID: "cho_b", Name: "B"}},
+			DefaultValue: []interface{}{"B", "Removed", "cho_a"},
+		}}, []interface{}{"cho_b", "cho_a"}, true},
+		{"单选默认值已删除", valueobject.TypeSingleSelect, &valueobject.FieldOptions{Select: &valueobject.SelectOptions{
+			Choices:      []valueobject.SelectChoice{{ID: "cho_todo", Name: "Todo"}},
+			DefaultValue: "Gone",
+		}}, nil, false},
+		{"文本", valueobject.TypeSingleLineText, &valueobject.FieldOptions{Text: &valueobject.TextOptions{DefaultValue: &text}}, "待处理", true},
+		{"空文本不算默认值", valueobject.TypeLongText, &valueobject.FieldOptions{Text: &valueobject.TextOptions{DefaultValue: &empty}}, nil, false},
+		{"未配置默认值", valueobject.TypeNumber, &valueobject.FieldOptions{Number: &valueobject.NumberOptions{}}, nil, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			field := newDefaultTestField(t, tc.fieldType, tc.options)
+			got, ok := DefaultCellValue(field, now)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestUserDefaultKeys(t *testing.T) {
+	field := newDefaultTestField(t, valueobject.TypeUser, &valueobject.FieldOptions{User: &valueobject.UserOptions{
+		IsMultiple:   true,
+		DefaultValue: []interface{}{"me", "usr_fixed"},
+	}})
+	assert.Equal(t, []string{"usr_current", "usr_fixed"}, UserDefaultKeys(field, "usr_current"))
+	assert.Equal(t, []string{"usr_fixed"}, UserDefaultKeys(field, ""), "没有当前用户时忽略 me")
+
+	plain := newDefaultTestField(t, valueobject.TypeUser, &valueobject.FieldOptions{User: &valueobject.UserOptions{}})
+	assert.Empty(t, UserDefaultKeys(plain, "usr_current"))
+}
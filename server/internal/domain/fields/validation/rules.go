@@ -0,0 +1,394 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+// 字段值校验规则
+// 在类型校验之外按字段配置的规则（正则、长度、域名、整数、数值和日期范围）校验单元格值，
+// 所有写入路径共用，表单视图通过字段选项读取同一份规则
+
+// 规则名称（违反规则时返回给调用方）
+const (
+	RulePattern        = "pattern"
+	RuleMinLength      = "minLength"
+	RuleMaxLength      = "maxLength"
+	RuleAllowedDomains = "allowedDomains"
+	RuleInteger        = "integer"
+	RuleMin            = "min"
+	RuleMax            = "max"
+	RuleMinDate        = "minDate"
+	RuleMaxDate        = "maxDate"
+)
+
+// RuleViolation 违反的校验规则
+type RuleViolation struct {
+	Rule    string
+	Message string
+}
+
+// ruleFieldKinds 规则适用的字段类别
+var ruleFieldKinds = map[string]string{
+	RulePattern:        "text",
+	RuleMinLength:      "text",
+	RuleMaxLength:      "text",
+	RuleAllowedDomains: "domain",
+	RuleInteger:        "number",
+	RuleMin:            "number",
+	RuleMax:            "number",
+	RuleMinDate:        "date",
+	RuleMaxDate:        "date",
+}
+
+// ruleFieldKind 字段类型所属的规则类别
+func ruleFieldKind(fieldType string) string {
+	switch fieldType {
+	case valueobject.TypeText, valueobject.TypeSingleLineText, valueobject.TypeLongText, valueobject.TypePhone:
+		return "text"
+	case valueobject.TypeEmail, valueobject.TypeURL:
+		return "domain"
+	case valueobject.TypeNumber, valueobject.TypePercent, valueobject.TypeCurrency:
+		return "number"
+	case valueobject.TypeDate, valueobject.TypeDateTime:
+		return "date"
+	}
+	return ""
+}
+
+// ruleSupported 字段类型是否支持该规则（邮箱、URL 同时支持文本规则）
+func ruleSupported(fieldType, rule string) bool {
+	kind := ruleFieldKind(fieldType)
+	want := ruleFieldKinds[rule]
+	return kind == want || (kind == "domain" && want == "text")
+}
+
+// ParseValidationRules 解析字段选项中的 validation 配置并检查规则是否适用于字段类型
+//
+// 每条规则既可以写成 {"value": ..., "message": ...}，也可以直接写值（如 "maxLength": 20、"integer": true）。
+// raw 为 nil 或没有任何规则时返回 nil，表示清除规则
+func ParseValidationRules(fieldType string, raw interface{}) (*valueobject.ValidationRules, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	rawMap, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("validation 必须是对象")
+	}
+
+	normalized := make(map[string]interface{}, len(rawMap))
+	for name, value := range rawMap {
+		if _, known := ruleFieldKinds[name]; !known {
+			return nil, fmt.Errorf("未知的校验规则: %s", name)
+		}
+		switch v := value.(type) {
+		case nil:
+			continue
+		case bool:
+			// 只有 integer 规则可以写成布尔值，false 表示不启用
+			if name != RuleInteger {
+				return nil, fmt.Errorf("校验规则 %s 的值无效", name)
+			}
+			if v {
+				normalized[name] = map[string]interface{}{}
+			}
+		case map[string]interface{}:
+			normalized[name] = v
+		default:
+			normalized[name] = map[string]interface{}{"value": v}
+		}
+	}
+
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("校验规则无效: %v", err)
+	}
+	rules := &valueobject.ValidationRules{}
+	if err := json.Unmarshal(data, rules); err != nil {
+		return nil, fmt.Errorf("校验规则无效: %v", err)
+	}
+	if rules.IsEmpty() {
+		return nil, nil
+	}
+	if err := CheckValidationRules(fieldType, rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// CheckValidationRules 检查规则配置：规则适用于字段类型、正则可编译、范围上下限有效，并规范化域名（小写、去掉首尾的点）
+func CheckValidationRules(fieldType string, rules *valueobject.ValidationRules) error {
+	if rules.IsEmpty() {
+		return nil
+	}
+	for _, name := range configuredRules(rules) {
+		if !ruleSupported(fieldType, name) {
+			return fmt.Errorf("%s 类型字段不支持校验规则 %s", fieldType, name)
+		}
+	}
+
+	if rules.Pattern != nil {
+		if rules.Pattern.Value == "" {
+			return fmt.Errorf("pattern 不能为空")
+		}
+		if _, err := regexp.Compile(rules.Pattern.Value); err != nil {
+			return fmt.Errorf("pattern 不是有效的正则表达式: %v", err)
+		}
+	}
+	if rules.MinLength != nil && rules.MinLength.Value < 0 {
+		return fmt.Errorf("minLength 不能小于 0")
+	}
+	if rules.MaxLength != nil && rules.MaxLength.Value < 1 {
+		return fmt.Errorf("maxLength 必须大于 0")
+	}
+	if rules.MinLength != nil && rules.MaxLength != nil && rules.MinLength.Value > rules.MaxLength.Value {
+		return fmt.Errorf("minLength 不能大于 maxLength")
+	}
+	if rules.AllowedDomains != nil {
+		domains := make([]string, 0, len(rules.AllowedDomains.Value))
+		for _, domain := range rules.AllowedDomains.Value {
+			domain = strings.Trim(strings.ToLower(strings.TrimSpace(domain)), ".")
+			if domain != "" {
+				domains = append(domains, domain)
+			}
+		}
+		if len(domains) == 0 {
+			return fmt.Errorf("allowedDomains 至少需要一个域名")
+		}
+		rules.AllowedDomains.Value = domains
+	}
+	if rules.Min != nil && rules.Max != nil && rules.Min.Value > rules.Max.Value {
+		return fmt.Errorf("min 不能大于 max")
+	}
+
+	var minDate, maxDate time.Time
+	var minFixed, maxFixed bool
+	if rules.MinDate != nil {
+		t, fixed, err := parseRuleDate(rules.MinDate.Value)
+		if err != nil {
+			return fmt.Errorf("minDate %v", err)
+		}
+		minDate, minFixed = t, fixed
+	}
+	if rules.MaxDate != nil {
+		t, fixed, err := parseRuleDate(rules.MaxDate.Value)
+		if err != nil {
+			return fmt.Errorf("maxDate %v", err)
+		}
+		maxDate, maxFixed = t, fixed
+	}
+	if minFixed && maxFixed && minDate.After(maxDate) {
+		return fmt.Errorf("minDate 不能晚于 maxDate")
+	}
+	return nil
+}
+
+// configuredRules 已配置的规则名称
+func configuredRules(rules *valueobject.ValidationRules) []string {
+	names := make([]string, 0)
+	if rules.Pattern != nil {
+		names = append(names, RulePattern)
+	}
+	if rules.MinLength != nil {
+		names = append(names, RuleMinLength)
+	}
+	if rules.MaxLength != nil {
+		names = append(names, RuleMaxLength)
+	}
+	if rules.AllowedDomains != nil {
+		names = append(names, RuleAllowedDomains)
+	}
+	if rules.Integer != nil {
+		names = append(names, RuleInteger)
+	}
+	if rules.Min != nil {
+		names = append(names, RuleMin)
+	}
+	if rules.Max != nil {
+		names = append(names, RuleMax)
+	}
+	if rules.MinDate != nil {
+		names = append(names, RuleMinDate)
+	}
+	if rules.MaxDate != nil {
+		names = append(names, RuleMaxDate)
+	}
+	return names
+}
+
+// parseRuleDate 解析日期规则的值，"now" 返回 fixed=false
+func parseRuleDate(value string) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, valueobject.DateDefaultNow) {
+		return time.Time{}, false, nil
+	}
+	t, ok := CoerceDate(value)
+	if !ok {
+		return time.Time{}, false, fmt.Errorf("不是有效的日期: %s", value)
+	}
+	return t, true, nil
+}
+
+// CheckRules 按字段配置的规则校验单元格值（已经过类型校验/转换），通过时返回 nil
+//
+// 空值、不适用于字段类型的规则会被跳过；多条规则不满足时只返回第一条
+func CheckRules(field *entity.Field, value interface{}, now time.Time) *RuleViolation {
+	options := field.Options()
+	if options == nil || options.Validation.IsEmpty() || isBlankRuleValue(value) {
+		return nil
+	}
+	rules := options.Validation
+	fieldType := field.Type().String()
+
+	switch ruleFieldKind(fieldType) {
+	case "text", "domain":
+		text, ok := value.(string)
+		if !ok {
+			text = fmt.Sprint(value)
+		}
+		if v := checkTextRules(rules, text); v != nil {
+			return v
+		}
+		if ruleFieldKind(fieldType) == "domain" && rules.AllowedDomains != nil {
+			return checkDomainRule(rules.AllowedDomains, fieldType, text)
+		}
+	case "number":
+		num, ok := CoerceNumber(value)
+		if !ok {
+			return nil
+		}
+		return checkNumberRules(rules, num)
+	case "date":
+		t, ok := CoerceDate(value)
+		if !ok {
+			return nil
+		}
+		return checkDateRules(rules, t, now, fieldType == valueobject.TypeDate)
+	}
+	return nil
+}
+
+func checkTextRules(rules *valueobject.ValidationRules, text string) *RuleViolation {
+	length := utf8.RuneCountInString(text)
+	if rules.MinLength != nil && length < rules.MinLength.Value {
+		return violation(RuleMinLength, rules.MinLength.Message, fmt.Sprintf("长度不能少于 %d 个字符", rules.MinLength.Value))
+	}
+	if rules.MaxLength != nil && length > rules.MaxLength.Value {
+		return violation(RuleMaxLength, rules.MaxLength.Message, fmt.Sprintf("长度不能超过 %d 个字符", rules.MaxLength.Value))
+	}
+	if rules.Pattern != nil {
+		re, err := regexp.Compile(rules.Pattern.Value)
+		if err == nil && !re.MatchString(text) {
+			return violation(RulePattern, rules.Pattern.Message, "格式不正确")
+		}
+	}
+	return nil
+}
+
+func checkDomainRule(rule *valueobject.DomainRule, fieldType, text string) *RuleViolation {
+	host := ""
+	if fieldType == valueobject.TypeEmail {
+		if at := strings.LastIndex(text, "@"); at >= 0 {
+			host = text[at+1:]
+		}
+	} else {
+		raw := strings.TrimSpace(text)
+		if !strings.Contains(raw, "://") {
+			raw = "http://" + raw
+		}
+		if u, err := url.Parse(raw); err == nil {
+			host = u.Hostname()
+		}
+	}
+	host = strings.Trim(strings.ToLower(strings.TrimSpace(host)), ".")
+	for _, domain := range rule.Value {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return nil
+		}
+	}
+	return violation(RuleAllowedDomains, rule.Message, fmt.Sprintf("域名必须是 %s 之一", strings.Join(rule.Value, "、")))
+}
+
+func checkNumberRules(rules *valueobject.ValidationRules, num float64) *RuleViolation {
+	if rules.Integer != nil && num != math.Trunc(num) {
+		return violation(RuleInteger, rules.Integer.Message, "必须是整数")
+	}
+	if rules.Min != nil && num < rules.Min.Value {
+		return violation(RuleMin, rules.Min.Message, fmt.Sprintf("不能小于 %s", formatRuleNumber(rules.Min.Value)))
+	}
+	if rules.Max != nil && num > rules.Max.Value {
+		return violation(RuleMax, rules.Max.Message, fmt.Sprintf("不能大于 %s", formatRuleNumber(rules.Max.Value)))
+	}
+	return nil
+}
+
+// checkDateRules 校验日期范围，dateOnly 时按日（UTC）比较
+func checkDateRules(rules *valueobject.ValidationRules, t, now time.Time, dateOnly bool) *RuleViolation {
+	bound := func(rule *valueobject.DateRule) time.Time {
+		b, fixed, _ := parseRuleDate(rule.Value)
+		if !fixed {
+			b = now
+		}
+		if dateOnly {
+			b = truncateDay(b)
+		}
+		return b
+	}
+	if dateOnly {
+		t = truncateDay(t)
+	}
+	if rules.MinDate != nil {
+		if lower := bound(rules.MinDate); t.Before(lower) {
+			return violation(RuleMinDate, rules.MinDate.Message, fmt.Sprintf("不能早于 %s", formatRuleDate(lower, dateOnly)))
+		}
+	}
+	if rules.MaxDate != nil {
+		if upper := bound(rules.MaxDate); t.After(upper) {
+			return violation(RuleMaxDate, rules.MaxDate.Message, fmt.Sprintf("不能晚于 %s", formatRuleDate(upper, dateOnly)))
+		}
+	}
+	return nil
+}
+
+func violation(rule, message, defaultMessage string) *RuleViolation {
+	if message == "" {
+		message = defaultMessage
+	}
+	return &RuleViolation{Rule: rule, Message: message}
+}
+
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func formatRuleDate(t time.Time, dateOnly bool) string {
+	if dateOnly {
+		return t.Format("2006-01-02")
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatRuleNumber(num float64) string {
+	return strconv.FormatFloat(num, 'f', -1, 64)
+}
+
+func isBlankRuleValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	}
+	return false
+}
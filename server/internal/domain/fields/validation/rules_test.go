@@ -0,0 +1,130 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+func TestParseValidationRules(t *testing.T) {
+	rules, err := ParseValidationRules(valueobject.TypeSingleLineText, map[string]interface{}{
+		"pattern":   map[string]interface{}{"value": `^[A-Z]{3}-\d+$`, "message": "编号格式为 ABC-123"},
+		"maxLength": float64(20),
+	})
+	require.NoError(t, err)
+	require.NotNil(t, rules.Pattern)
+	assert.Equal(t, "编号格式为 ABC-123", rules.Pattern.Message)
+	assert.Equal(t, 20, rules.MaxLength.Value)
+
+	rules, err = ParseValidationRules(valueobject.TypeEmail, map[string]interface{}{
+		"allowedDomains": []interface{}{" Example.COM ", ""},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"example.com"}, rules.AllowedDomains.Value)
+
+	rules, err = ParseValidationRules(valueobject.TypeNumber, map[string]interface{}{"integer": true, "min": float64(0)})
+	require.NoError(t, err)
+	assert.NotNil(t, rules.Integer)
+	assert.Equal(t, float64(0), rules.Min.Value)
+
+	rules, err = ParseValidationRules(valueobject.TypeNumber, map[string]interface{}{"integer": false})
+	require.NoError(t, err)
+	assert.Nil(t, rules, "没有启用任何规则时清除")
+
+	invalid := []struct {
+		name      string
+		fieldType string
+		raw       interface{}
+	}{
+		{"未知规则", valueobject.TypeSingleLineText, map[string]interface{}{"unknown": 1}},
+		{"正则无效", valueobject.TypeSingleLineText, map[string]interface{}{"pattern": "("}},
+		{"类型不支持", valueobject.TypeNumber, map[string]interface{}{"maxLength": float64(3)}},
+		{"文本字段不支持域名", valueobject.TypeSingleLineText, map[string]interface{}{"allowedDomains": []interface{}{"a.com"}}},
+		{"长度上下限颠倒", valueobject.TypeLongText, map[string]interface{}{"minLength": float64(5), "maxLength": float64(2)}},
+		{"数值上下限颠倒", valueobject.TypeNumber, map[string]interface{}{"min": float64(5), "max": float64(2)}},
+		{"日期无效", valueobject.TypeDate, map[string]interface{}{"minDate": "someday"}},
+		{"不是对象", valueobject.TypeNumber, "integer"},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseValidationRules(tc.fieldType, tc.raw)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCheckRules(t *testing.T) {
+	now := time.Date(2024, 3, 5, 8, 30, 0, 0, time.UTC)
+
+	cases := []struct {
+		name      string
+		fieldType string
+		rules     *valueobject.ValidationRules
+		value     interface{}
+		rule      string
+		message   string
+	}{
+		{"正则通过", valueobject.TypeSingleLineText, &valueobject.ValidationRules{
+			Pattern: &valueobject.PatternRule{Value: `^[A-Z]{3}-\d+$`},
+		}, "ABC-12", "", ""},
+		{"正则不通过使用自定义提示", valueobject.TypeSingleLineText, &valueobject.ValidationRules{
+			Pattern: &valueobject.PatternRule{Value: `^[A-Z]{3}-\d+$`, Message: "编号格式为 ABC-123"},
+		}, "abc", RulePattern, "编号格式为 ABC-123"},
+		{"长度按字符计", valueobject.TypeLongText, &valueobject.ValidationRules{
+			MaxLength: &valueobject.LengthRule{Value: 2},
+		}, "中文字", RuleMaxLength, "长度不能超过 2 个字符"},
+		{"最小长度", valueobject.TypeSingleLineText, &valueobject.ValidationRules{
+			MinLength: &valueobject.LengthRule{Value: 3},
+		}, "ab", RuleMinLength, "长度不能少于 3 个字符"},
+		{"空值不校验", valueobject.TypeSingleLineText, &valueobject.ValidationRules{
+			MinLength: &valueobject.LengthRule{Value: 3},
+		}, "", "", ""},
+		{"邮箱子域名", valueobject.TypeEmail, &valueobject.ValidationRules{
+			AllowedDomains: &valueobject.DomainRule{Value: []string{"example.com"}},
+		}, "a@mail.Example.com", "", ""},
+		{"邮箱域名不允许", valueobject.TypeEmail, &valueobject.ValidationRules{
+			AllowedDomains: &valueobject.DomainRule{Value: []string{"example.com"}, Message: "请使用公司邮箱"},
+		}, "a@badexample.com", RuleAllowedDomains, "请使用公司邮箱"},
+		{"URL 域名", valueobject.TypeURL, &valueobject.ValidationRules{
+			AllowedDomains: &valueobject.DomainRule{Value: []string{"example.com"}},
+		}, "https://docs.example.com/a", "", ""},
+		{"URL 域名不允许", valueobject.TypeURL, &valueobject.ValidationRules{
+			AllowedDomains: &valueobject.DomainRule{Value: []string{"example.com"}},
+		}, "evil.com/example.com", RuleAllowedDomains, "域名必须是 example.com 之一"},
+		{"整数", valueobject.TypeNumber, &valueobject.ValidationRules{
+			Integer: &valueobject.IntegerRule{},
+		}, 1.5, RuleInteger, "必须是整数"},
+		{"最大值", valueobject.TypeCurrency, &valueobject.ValidationRules{
+			Max: &valueobject.NumberRule{Value: 99.5},
+		}, float64(100), RuleMax, "不能大于 99.5"},
+		{"最小值通过", valueobject.TypeNumber, &valueobject.ValidationRules{
+			Min: &valueobject.NumberRule{Value: 0},
+		}, float64(0), "", ""},
+		{"日期不能早于今天（按日比较）", valueobject.TypeDate, &valueobject.ValidationRules{
+			MinDate: &valueobject.DateRule{Value: "now"},
+		}, time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), "", ""},
+		{"日期早于下限", valueobject.TypeDate, &valueobject.ValidationRules{
+			MinDate: &valueobject.DateRule{Value: "now"},
+		}, "2024-03-04", RuleMinDate, "不能早于 2024-03-05"},
+		{"日期晚于上限", valueobject.TypeDateTime, &valueobject.ValidationRules{
+			MaxDate: &valueobject.DateRule{Value: "2024-12-31", Message: "活动已结束"},
+		}, "2025-01-01", RuleMaxDate, "活动已结束"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			field := newDefaultTestField(t, tc.fieldType, &valueobject.FieldOptions{Validation: tc.rules})
+			got := CheckRules(field, tc.value, now)
+			if tc.rule == "" {
+				assert.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			assert.Equal(t, tc.rule, got.Rule)
+			assert.Equal(t, tc.message, got.Message)
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+// 宽松类型转换（typecast=true）
+// 参考旧系统: typecast.validate.ts —— 导入表格、LLM 写入时输入通常是松散的字符串
+
+// choiceIDPrefix 自动创建选项的ID前缀
+const choiceIDPrefix = "cho"
+
+// numberReplacer 去掉数字字符串中的千分位、空白和常见货币符号
+var numberReplacer = strings.NewReplacer(",", "", " ", "", " ", "", "$", "", "¥", "", "￥", "", "€", "", "£", "")
+
+// CoerceNumber 将松散输入转换为数字
+//
+// 支持 "1,234.5"、"$ 12"、"¥8" 等写法；带百分号的 "50%" 按百分数转换为 0.5
+func CoerceNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	case string:
+		s := numberReplacer.Replace(strings.TrimSpace(v))
+		percent := strings.HasSuffix(s, "%")
+		f, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+		if err != nil {
+			return 0, false
+		}
+		if percent {
+			f /= 100
+		}
+		return f, true
+	}
+	return 0, false
+}
+
+// typecastDateLayouts 宽松日期格式（按常见程度排序）
+var typecastDateLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006/01/02",
+	"2006.01.02",
+	"2006年01月02日",
+	"2006年1月2日",
+	"01/02/2006",
+	"1/2/2006",
+	"02-01-2006",
+	"Jan 2, 2006",
+	"2 Jan 2006",
+}
+
+// CoerceDate 将松散输入转换为时间
+//
+// 字符串按 typecastDateLayouts 依次尝试；数字视为 Unix 时间戳（大于 1e11 视为毫秒）
+func CoerceDate(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		s := strings.TrimSpace(v)
+		for _, layout := range typecastDateLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, true
+			}
+		}
+		if ts, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return unixTime(ts), true
+		}
+	case float64:
+		return unixTime(int64(v)), true
+	case int64:
+		return unixTime(v), true
+	case int:
+		return unixTime(int64(v)), true
+	}
+	return time.Time{}, false
+}
+
+func unixTime(ts int64) time.Time {
+	if ts > 1e11 || ts < -1e11 {
+		return time.UnixMilli(ts).UTC()
+	}
+	return time.Unix(ts, 0).UTC()
+}
+
+// TypecastStrings 将单个值或数组展开为非空字符串列表
+//
+// splitComma 为 true 时单个字符串按逗号拆分（多选字段的 "A, B" 写法）
+func TypecastStrings(value interface{}, splitComma bool) []string {
+	var raw []interface{}
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		raw = v
+	case []string:
+		for _, s := range v {
+			raw = append(raw, s)
+		}
+	case string:
+		if splitComma {
+			for _, part := range strings.Split(v, ",") {
+				raw = append(raw, part)
+			}
+		} else {
+			raw = []interface{}{v}
+		}
+	default:
+		raw = []interface{}{v}
+	}
+
+	result := make([]string, 0, len(raw))
+	for _, item := range raw {
+		var s string
+		switch v := item.(type) {
+		case nil:
+			continue
+		case string:
+			s = v
+		case map[string]interface{}:
+			// 已是对象格式（{id, title} / {id, name}）时优先取 id
+			if id, ok := v["id"].(string); ok {
+				s = id
+			} else if title, ok := v["title"].(string); ok {
+				s = title
+			} else if name, ok := v["name"].(string); ok {
+				s = name
+			}
+		default:
+			s = fmt.Sprintf("%v", v)
+		}
+		if s = strings.TrimSpace(s); s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// ResolveSelectChoices 将选项名称（或ID）解析为选项ID
+//
+// 匹配顺序：选项ID > 选项名称 > 忽略大小写的名称。未匹配的名称在允许时创建新选项
+// （PreventAutoNewOptions 关闭时），新选项追加到字段配置并通过 created 返回，由调用方持久化；
+// 不允许创建时通过 unknown 返回
+func ResolveSelectChoices(field *entity.Field, names []string) (ids []string, created []valueobject.SelectChoice, unknown []string) {
+	options := field.Options()
+	if options == nil {
+		options = valueobject.NewFieldOptions()
+	}
+	if options.Select == nil {
+		options.Select = &valueobject.SelectOptions{}
+	}
+	selectOptions := options.Select
+
+	for _, name := range names {
+		if id, ok := matchSelectChoice(selectOptions.Choices, name); ok {
+			ids = append(ids, id)
+			continue
+		}
+		if selectOptions.PreventAutoNewOptions {
+			unknown = append(unknown, name)
+			continue
+		}
+
+		choice := valueobject.SelectChoice{
+			ID:   utils.GenerateIDWithPrefix(choiceIDPrefix),
+			Name: name,
+		}
+		selectOptions.Choices = append(selectOptions.Choices, choice)
+		created = append(created, choice)
+		ids = append(ids, choice.ID)
+	}
+
+	if len(created) > 0 {
+		if err := field.UpdateOptions(options); err != nil {
+			// 字段不可修改（如已删除）时不创建选项
+			for _, choice := range created {
+				unknown = append(unknown, choice.Name)
+			}
+			return nil, nil, unknown
+		}
+	}
+	return ids, created, unknown
+}
+
+func matchSelectChoice(choices []valueobject.SelectChoice, name string) (string, bool) {
+	for _, choice := range choices {
+		if choice.ID == name {
+			return choice.ID, true
+		}
+	}
+	for _, choice := range choices {
+		if choice.Name == name {
+			return choice.ID, true
+		}
+	}
+	for _, choice := range choices {
+		if strings.EqualFold(choice.Name, name) {
+			return choice.ID, true
+		}
+	}
+	return "", false
+}
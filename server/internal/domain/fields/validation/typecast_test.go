@@ -0,0 +1,104 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+func newSelectField(t *testing.T, preventAutoNew bool) *entity.Field {
+	fieldType, err := valueobject.NewFieldType(valueobject.TypeSingleSelect)
+	require.NoError(t, err)
+	name, err := valueobject.NewFieldName("Status")
+	require.NoError(t, err)
+
+	field, err := entity.NewField("tbl_test", name, fieldType, "usr_test")
+	require.NoError(t, err)
+
+	options := valueobject.NewFieldOptions()
+	options.Select = &valueobject.SelectOptions{
+		Choices: []valueobject.SelectChoice{
+			{ID: "cho_todo", Name: "Todo"},
+			{ID: "cho_done", Name: "Done"},
+		},
+		PreventAutoNewOptions: preventAutoNew,
+	}
+	require.NoError(t, field.UpdateOptions(options))
+	return field
+}
+
+func TestCoerceNumber(t *testing.T) {
+	cases := map[interface{}]float64{
+		"1,234.5": 1234.5,
+		" $ 12 ":  12,
+		"¥8":      8,
+		"50%":     0.5,
+		int64(3):  3,
+		true:      1,
+	}
+	for input, want := range cases {
+		got, ok := CoerceNumber(input)
+		assert.True(t, ok, "input %v", input)
+		assert.Equal(t, want, got, "input %v", input)
+	}
+
+	_, ok := CoerceNumber("abc")
+	assert.False(t, ok)
+	_, ok = CoerceNumber([]interface{}{1})
+	assert.False(t, ok)
+}
+
+func TestCoerceDate(t *testing.T) {
+	want := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	for _, input := range []interface{}{"2024-03-05", "2024/03/05", "2024年3月5日", "3/5/2024", "Mar 5, 2024", float64(want.Unix()), want.UnixMilli()} {
+		got, ok := CoerceDate(input)
+		require.True(t, ok, "input %v", input)
+		assert.True(t, want.Equal(got), "input %v: got %v", input, got)
+	}
+
+	_, ok := CoerceDate("next tuesday")
+	assert.False(t, ok)
+}
+
+func TestTypecastStrings(t *testing.T) {
+	assert.Equal(t, []string{"A", "B"}, TypecastStrings("A, B,", true))
+	assert.Equal(t, []string{"A, B"}, TypecastStrings("A, B", false))
+	assert.Equal(t, []string{"rec_1", "Acme", "3"}, TypecastStrings([]interface{}{
+		map[string]interface{}{"id": "rec_1", "title": "x"},
+		map[string]interface{}{"title": "Acme"},
+		3,
+		nil,
+	}, false))
+	assert.Nil(t, TypecastStrings(nil, true))
+}
+
+func TestResolveSelectChoices(t *testing.T) {
+	field := newSelectField(t, false)
+
+	ids, created, unknown := ResolveSelectChoices(field, []string{"cho_done", "Todo", "todo", "Blocked"})
+	require.Len(t, created, 1)
+	assert.Empty(t, unknown)
+	assert.Equal(t, []string{"cho_done", "cho_todo", "cho_todo", created[0].ID}, ids)
+	assert.Equal(t, "Blocked", created[0].Name)
+	assert.Len(t, field.Options().Select.Choices, 3)
+
+	// 新选项再次出现时复用
+	ids, created, _ = ResolveSelectChoices(field, []string{"Blocked"})
+	assert.Empty(t, created)
+	assert.Equal(t, []string{field.Options().Select.Choices[2].ID}, ids)
+}
+
+func TestResolveSelectChoicesPreventAutoNew(t *testing.T) {
+	field := newSelectField(t, true)
+
+	ids, created, unknown := ResolveSelectChoices(field, []string{"Done", "Blocked"})
+	assert.Equal(t, []string{"cho_done"}, ids)
+	assert.Empty(t, created)
+	assert.Equal(t, []string{"Blocked"}, unknown)
+	assert.Len(t, field.Options().Select.Choices, 2)
+}
@@ -42,6 +42,12 @@ type FieldOptions struct {
 	// Rating 选项
 	Rating *RatingOptions
 
+	// Text 选项（文本类字段）
+	Text *TextOptions
+
+	// Validation 字段值校验规则（文本、数字、日期字段）
+	Validation *ValidationRules
+
 	// 通用配置（可选，某些字段类型会使用）
 	ShowAs     *ShowAsOptions     `json:"showAs,omitempty"`
 	Formatting *FormattingOptions `json:"formatting,omitempty"`
@@ -188,9 +194,77 @@ type ButtonOptions struct {
 	Config map[string]interface{} `json:"config,omitempty"` // 动作配置
 }
 
+// UserDefaultCurrentUser 用户字段默认值：创建记录的当前用户
+const UserDefaultCurrentUser = "me"
+
+// DateDefaultNow 日期字段默认值：创建记录的时间
+const DateDefaultNow = "now"
+
 // UserOptions User字段选项
 type UserOptions struct {
-	IsMultiple bool `json:"is_multiple"` // 是否允许多用户
+	IsMultiple   bool        `json:"is_multiple"`            // 是否允许多用户
+	DefaultValue interface{} `json:"defaultValue,omitempty"` // 默认值：用户ID、"me"（当前用户），多用户字段可为数组
+}
+
+// TextOptions 文本字段选项
+type TextOptions struct {
+	DefaultValue *string `json:"defaultValue,omitempty"` // 静态默认值
+}
+
+// ValidationRules 字段值校验规则
+//
+// 每条规则可配置自定义错误提示 message，未配置时使用默认提示。空值不参与校验（由必填、非空约束负责）
+type ValidationRules struct {
+	Pattern        *PatternRule `json:"pattern,omitempty"`        // 正则表达式（文本类字段）
+	MinLength      *LengthRule  `json:"minLength,omitempty"`      // 最小长度，按字符计（文本类字段）
+	MaxLength      *LengthRule  `json:"maxLength,omitempty"`      // 最大长度，按字符计（文本类字段）
+	AllowedDomains *DomainRule  `json:"allowedDomains,omitempty"` // 允许的域名，包含子域名（邮箱、URL 字段）
+	Integer        *IntegerRule `json:"integer,omitempty"`        // 只允许整数（数字类字段）
+	Min            *NumberRule  `json:"min,omitempty"`            // 最小值（数字类字段）
+	Max            *NumberRule  `json:"max,omitempty"`            // 最大值（数字类字段）
+	MinDate        *DateRule    `json:"minDate,omitempty"`        // 最早日期（日期字段）
+	MaxDate        *DateRule    `json:"maxDate,omitempty"`        // 最晚日期（日期字段）
+}
+
+// IsEmpty 是否没有配置任何规则
+func (r *ValidationRules) IsEmpty() bool {
+	return r == nil || (r.Pattern == nil && r.MinLength == nil && r.MaxLength == nil && r.AllowedDomains == nil &&
+		r.Integer == nil && r.Min == nil && r.Max == nil && r.MinDate == nil && r.MaxDate == nil)
+}
+
+// PatternRule 正则规则
+type PatternRule struct {
+	Value   string `json:"value"`
+	Message string `json:"message,omitempty"`
+}
+
+// LengthRule 长度规则
+type LengthRule struct {
+	Value   int    `json:"value"`
+	Message string `json:"message,omitempty"`
+}
+
+// DomainRule 域名白名单规则
+type DomainRule struct {
+	Value   []string `json:"value"`
+	Message string   `json:"message,omitempty"`
+}
+
+// IntegerRule 整数规则
+type IntegerRule struct {
+	Message string `json:"message,omitempty"`
+}
+
+// NumberRule 数值范围规则
+type NumberRule struct {
+	Value   float64 `json:"value"`
+	Message string  `json:"message,omitempty"`
+}
+
+// DateRule 日期范围规则，Value 为日期字符串或 "now"（写入时的当前时间）
+type DateRule struct {
+	Value   string `json:"value"`
+	Message string `json:"message,omitempty"`
 }
 
 // RatingOptions Rating字段选项
@@ -230,76 +230,61 @@ func isComputedType(fieldType string) bool {
 	return computedTypes[fieldType]
 }
 
+// 可以互相转换的存储类型分组
+var (
+	textTypes   = []string{TypeText, TypeSingleLineText, TypeLongText, TypeEmail, TypeURL, TypePhone}
+	numberTypes = []string{TypeNumber, TypePercent, TypeCurrency, TypeRating, TypeDuration}
+	dateTypes   = []string{TypeDate, TypeDateTime}
+	boolTypes   = []string{TypeBoolean, TypeCheckbox}
+	selectTypes = []string{TypeSelect, TypeSingleSelect, TypeMultipleSelect}
+)
+
 // compatibilityMatrix 字段类型兼容性矩阵
-// true 表示可以从源类型转换为目标类型
-var compatibilityMatrix = map[string]map[string]bool{
-	TypeText: {
-		TypeNumber:   true,
-		TypeEmail:    true,
-		TypeURL:      true,
-		TypePhone:    true,
-		TypeDate:     true,
-		TypeDateTime: true,
-	},
-	TypeNumber: {
-		TypeText:     true,
-		TypePercent:  true,
-		TypeCurrency: true,
-		TypeRating:   true,
-		TypeDuration: true,
-	},
-	TypeDate: {
-		TypeText:     true,
-		TypeDateTime: true,
-	},
-	TypeDateTime: {
-		TypeText: true,
-		TypeDate: true,
-	},
-	TypeBoolean: {
-		TypeText:     true,
-		TypeCheckbox: true,
-	},
-	TypeCheckbox: {
-		TypeBoolean: true,
-		TypeText:    true,
-	},
-	TypeSelect: {
-		TypeText:           true,
-		TypeMultipleSelect: false, // 单选转多选需要特殊处理
-	},
-	TypeSingleSelect: {
-		TypeText:           true,
-		TypeMultipleSelect: false, // 单选转多选需要特殊处理
-	},
-	TypeMultipleSelect: {
-		TypeText:   true,
-		TypeSelect: false, // 多选转单选可能丢失数据
-	},
-	TypeEmail: {
-		TypeText: true,
-		TypeURL:  true,
-	},
-	TypeURL: {
-		TypeText: true,
-	},
-	TypePhone: {
-		TypeText: true,
-	},
-	TypeRating: {
-		TypeNumber: true,
-		TypeText:   true,
-	},
-	TypePercent: {
-		TypeNumber: true,
-		TypeText:   true,
-	},
-	TypeCurrency: {
-		TypeNumber: true,
-		TypeText:   true,
-	},
-	TypeDuration: {
-		TypeNumber: true,
-		TypeText:   true,
-	},
+// true 表示可以从源类型转换为目标类型，已有数据由类型转换流程逐格改写，无法转换的单元格清空
+var compatibilityMatrix = buildCompatibilityMatrix()
+
+// buildCompatibilityMatrix 按类型分组生成兼容性矩阵
+//
+//   - 任意存储类型都可以转为文本（取单元格的显示字符串）
+//   - 文本可以转为数字、日期、复选框、选择与关联（解析字符串，关联按主字段标题匹配）
+//   - 数字类型之间、日期类型之间、复选框类型之间、单选与多选之间可以互转
+//   - 数字与复选框、数字与选择可以互转
+func buildCompatibilityMatrix() map[string]map[string]bool {
+	matrix := make(map[string]map[string]bool)
+	allow := func(from, to []string) {
+		for _, f := range from {
+			if matrix[f] == nil {
+				matrix[f] = make(map[string]bool)
+			}
+			for _, t := range to {
+				if f != t {
+					matrix[f][t] = true
+				}
+			}
+		}
+	}
+
+	stored := [][]string{textTypes, numberTypes, dateTypes, boolTypes, selectTypes,
+		{TypeLink, TypeUser, TypeAttachment}}
+	for _, group := range stored {
+		allow(group, textTypes)
+	}
+
+	allow(textTypes, numberTypes)
+	allow(textTypes, dateTypes)
+	allow(textTypes, boolTypes)
+	allow(textTypes, selectTypes)
+	allow(textTypes, []string{TypeLink})
+
+	allow(numberTypes, numberTypes)
+	allow(dateTypes, dateTypes)
+	allow(boolTypes, boolTypes)
+	allow(selectTypes, selectTypes)
+
+	allow(numberTypes, boolTypes)
+	allow(boolTypes, numberTypes)
+	allow(numberTypes, selectTypes)
+	allow(selectTypes, numberTypes)
+
+	return matrix
 }
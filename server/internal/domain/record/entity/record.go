@@ -57,6 +57,23 @@ func NewRecord(
 	}, nil
 }
 
+// NewRecordWithID 使用指定ID创建新记录（实时协作中由客户端生成记录ID）
+func NewRecordWithID(
+	id valueobject.RecordID,
+	tableID string,
+	data valueobject.RecordData,
+	createdBy string,
+) (*Record, error) {
+	r, err := NewRecord(tableID, data, createdBy)
+	if err != nil {
+		return nil, err
+	}
+	if !id.IsEmpty() {
+		r.id = id
+	}
+	return r, nil
+}
+
 // ReconstructRecord 重建记录（从数据库加载）
 func ReconstructRecord(
 	id valueobject.RecordID,
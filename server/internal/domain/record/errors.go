@@ -24,6 +24,9 @@ var (
 	ErrVersionConflict = errors.New("version conflict (optimistic lock failed)")
 	ErrInvalidVersion  = errors.New("invalid record version")
 
+	// 分页游标错误
+	ErrInvalidCursor = errors.New("invalid record cursor")
+
 	// 批量操作错误
 	ErrBatchOperationFailed = errors.New("batch operation failed")
 	ErrPartialSuccess       = errors.New("partial success in batch operation")
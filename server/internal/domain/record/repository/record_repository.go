@@ -5,6 +5,7 @@ import (
 
 	"github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	viewValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
 )
 
 // RecordRepository 记录仓储接口
@@ -40,6 +41,12 @@ type RecordRepository interface {
 	// List 列出记录（支持过滤和分页）
 	List(ctx context.Context, filter RecordFilter) ([]*entity.Record, int64, error)
 
+	// ListPage 列出记录并返回下一页游标（键集分页）
+	ListPage(ctx context.Context, filter RecordFilter) (*RecordPage, error)
+
+	// GroupBy 按字段分组统计记录（过滤语义与 List 一致，最多3层分组）
+	GroupBy(ctx context.Context, query RecordGroupQuery) (*RecordGroupResult, error)
+
 	// BatchSave 批量保存记录
 	BatchSave(ctx context.Context, records []*entity.Record) error
 
@@ -73,10 +80,87 @@ type RecordFilter struct {
 	CreatedBy    *string
 	UpdatedBy    *string
 	IsDeleted    *bool
-	FieldFilters map[string]interface{} // 字段过滤条件
-	OrderBy      string                 // created_at, updated_at, field_name
-	OrderDir     string                 // asc, desc
+	FieldFilters map[string]interface{}     // 字段过滤条件（字段ID或名称 -> 值，按 is 语义匹配）
+	Filter       *viewValueObject.Filter    // 嵌套 and/or 过滤树（与视图过滤结构一致）
+	Sort         []viewValueObject.SortItem // 多键排序（字段ID或名称 + 方向），按字段类型语义排序，空值在后
+	Projection   []string                   // 只查询这些字段ID的列，为空时查询全部字段
 	Limit        int
 	Offset       int
-	Cursor       string                 // ✅ 优化：游标分页（基于 __auto_number）
+	Cursor       *valueobject.RecordCursor // 键集分页游标（排序键取值 + __id），设置时忽略 Offset
+	Search       *RecordSearch             // 全文搜索，与过滤条件按 AND 组合
+}
+
+// ApplyView 将视图配置合并到过滤器
+//   - 过滤：视图过滤与已有过滤按 AND 组合
+//   - 排序：分组字段优先，其次为已有排序（未指定时使用视图排序）
+func (f *RecordFilter) ApplyView(filter *viewValueObject.Filter, sort *viewValueObject.Sort, group *viewValueObject.Group) {
+	if !filter.IsEmpty() {
+		if f.Filter.IsEmpty() {
+			f.Filter = filter
+		} else {
+			f.Filter = &viewValueObject.Filter{
+				Operator: viewValueObject.FilterOperatorAnd,
+				Groups:   []*viewValueObject.Filter{filter, f.Filter},
+			}
+		}
+	}
+
+	sortItems := make([]viewValueObject.SortItem, 0)
+	if !group.IsEmpty() {
+		for _, item := range group.GroupItems {
+			order := item.Order
+			if order == "" {
+				order = viewValueObject.SortOrderAsc
+			}
+			sortItems = append(sortItems, viewValueObject.SortItem{FieldID: item.FieldID, Order: order})
+		}
+	}
+	if len(f.Sort) > 0 {
+		sortItems = append(sortItems, f.Sort...)
+	} else if !sort.IsEmpty() {
+		sortItems = append(sortItems, sort.SortItems...)
+	}
+	f.Sort = sortItems
+}
+
+// RecordSearch 表内全文搜索条件
+type RecordSearch struct {
+	Query    string   // 关键词：文本子串、选项名称、关联标题，拼音关键词同时匹配中文
+	FieldIDs []string // 只搜索这些字段，为空时搜索全部可搜索字段
+}
+
+// RecordPage 记录分页结果
+type RecordPage struct {
+	Records    []*entity.Record
+	Total      int64                     // 过滤后的总数（不含游标条件）
+	NextCursor *valueobject.RecordCursor // 下一页游标，没有更多记录时为 nil
+}
+
+// AggregateItem 聚合项
+type AggregateItem struct {
+	FieldID string
+	Func    valueobject.AggregateFunc
+}
+
+// RecordGroupQuery 分组统计查询
+type RecordGroupQuery struct {
+	Filter       RecordFilter                // 过滤条件（只使用过滤相关字段，忽略排序与分页）
+	GroupBy      []viewValueObject.GroupItem // 分组字段（按层级顺序，最多3层）
+	Aggregations []AggregateItem             // 每个分组及全表计算的聚合
+}
+
+// RecordGroup 分组结果
+type RecordGroup struct {
+	FieldID    string
+	Value      interface{} // 分组值（空值分组为 nil）
+	Count      int64
+	Aggregates map[string]map[valueobject.AggregateFunc]interface{} // 字段ID -> 聚合函数 -> 结果
+	Children   []*RecordGroup                                       // 下一层分组
+}
+
+// RecordGroupResult 分组统计结果
+type RecordGroupResult struct {
+	Total      int64
+	Aggregates map[string]map[valueobject.AggregateFunc]interface{} // 全部过滤结果的聚合
+	Groups     []*RecordGroup
 }
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	viewValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+)
+
+func statusFilter(value string) *viewValueObject.Filter {
+	return &viewValueObject.Filter{
+		Operator: viewValueObject.FilterOperatorAnd,
+		Filters:  []viewValueObject.FilterItem{{FieldID: "fld_status", Operator: viewValueObject.FilterItemOpIs, Value: value}},
+	}
+}
+
+func TestRecordFilter_ApplyView(t *testing.T) {
+	viewSort := &viewValueObject.Sort{SortItems: []viewValueObject.SortItem{
+		{FieldID: "fld_due", Order: viewValueObject.SortOrderDesc},
+	}}
+	group := &viewValueObject.Group{GroupItems: []viewValueObject.GroupItem{
+		{FieldID: "fld_owner"},
+		{FieldID: "fld_stage", Order: viewValueObject.SortOrderDesc},
+	}}
+
+	t.Run("视图过滤与请求过滤按 AND 组合", func(t *testing.T) {
+		viewFilter := statusFilter("open")
+		requestFilter := statusFilter("urgent")
+		f := RecordFilter{Filter: requestFilter}
+		f.ApplyView(viewFilter, nil, nil)
+
+		require.NotNil(t, f.Filter)
+		assert.Equal(t, viewValueObject.FilterOperatorAnd, f.Filter.Operator)
+		assert.Empty(t, f.Filter.Filters)
+		assert.Equal(t, []*viewValueObject.Filter{viewFilter, requestFilter}, f.Filter.Groups)
+	})
+
+	t.Run("只有一方有过滤时直接使用", func(t *testing.T) {
+		viewFilter := statusFilter("open")
+		f := RecordFilter{}
+		f.ApplyView(viewFilter, nil, nil)
+		assert.Same(t, viewFilter, f.Filter)
+
+		requestFilter := statusFilter("urgent")
+		f = RecordFilter{Filter: requestFilter}
+		f.ApplyView(nil, nil, nil)
+		assert.Same(t, requestFilter, f.Filter)
+	})
+
+	t.Run("分组字段排在最前，未指定方向时升序", func(t *testing.T) {
+		f := RecordFilter{}
+		f.ApplyView(nil, viewSort, group)
+		assert.Equal(t, []viewValueObject.SortItem{
+			{FieldID: "fld_owner", Order: viewValueObject.SortOrderAsc},
+			{FieldID: "fld_stage", Order: viewValueObject.SortOrderDesc},
+			{FieldID: "fld_due", Order: viewValueObject.SortOrderDesc},
+		}, f.Sort)
+	})
+
+	t.Run("请求排序替换视图排序，仍在分组之后", func(t *testing.T) {
+		f := RecordFilter{Sort: []viewValueObject.SortItem{{FieldID: "fld_name", Order: viewValueObject.SortOrderAsc}}}
+		f.ApplyView(nil, viewSort, group)
+		assert.Equal(t, []viewValueObject.SortItem{
+			{FieldID: "fld_owner", Order: viewValueObject.SortOrderAsc},
+			{FieldID: "fld_stage", Order: viewValueObject.SortOrderDesc},
+			{FieldID: "fld_name", Order: viewValueObject.SortOrderAsc},
+		}, f.Sort)
+	})
+}
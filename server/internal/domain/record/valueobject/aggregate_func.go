@@ -0,0 +1,97 @@
+package valueobject
+
+import (
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+// AggregateFunc 聚合/列统计函数
+type AggregateFunc string
+
+const (
+	// 通用（所有字段类型）
+	AggregateEmptyCount    AggregateFunc = "emptyCount"    // 空值数量
+	AggregateFilledCount   AggregateFunc = "filledCount"   // 非空值数量
+	AggregateUniqueCount   AggregateFunc = "uniqueCount"   // 去重后的非空值数量
+	AggregatePercentEmpty  AggregateFunc = "percentEmpty"  // 空值占比（0-100）
+	AggregatePercentFilled AggregateFunc = "percentFilled" // 非空值占比（0-100）
+	AggregatePercentUnique AggregateFunc = "percentUnique" // 去重值占比（0-100）
+
+	// 数字
+	AggregateSum    AggregateFunc = "sum"
+	AggregateAvg    AggregateFunc = "avg"
+	AggregateMin    AggregateFunc = "min"
+	AggregateMax    AggregateFunc = "max"
+	AggregateMedian AggregateFunc = "median"
+
+	// 日期
+	AggregateEarliestDate AggregateFunc = "earliestDate"
+	AggregateLatestDate   AggregateFunc = "latestDate"
+
+	// 复选框
+	AggregateCheckedCount     AggregateFunc = "checkedCount"
+	AggregateUncheckedCount   AggregateFunc = "uncheckedCount"
+	AggregatePercentChecked   AggregateFunc = "percentChecked"
+	AggregatePercentUnchecked AggregateFunc = "percentUnchecked"
+)
+
+var (
+	commonAggregateFuncs = []AggregateFunc{
+		AggregateEmptyCount, AggregateFilledCount, AggregateUniqueCount,
+		AggregatePercentEmpty, AggregatePercentFilled, AggregatePercentUnique,
+	}
+	numberAggregateFuncs = []AggregateFunc{
+		AggregateSum, AggregateAvg, AggregateMin, AggregateMax, AggregateMedian,
+	}
+	dateAggregateFuncs = []AggregateFunc{
+		AggregateEarliestDate, AggregateLatestDate,
+	}
+	checkboxAggregateFuncs = []AggregateFunc{
+		AggregateCheckedCount, AggregateUncheckedCount, AggregatePercentChecked, AggregatePercentUnchecked,
+	}
+)
+
+// IsValid 检查聚合函数是否受支持
+func (f AggregateFunc) IsValid() bool {
+	for _, group := range [][]AggregateFunc{commonAggregateFuncs, numberAggregateFuncs, dateAggregateFuncs, checkboxAggregateFuncs} {
+		for _, fn := range group {
+			if fn == f {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SupportsFieldType 检查聚合函数是否适用于字段类型
+func (f AggregateFunc) SupportsFieldType(ft fieldValueObject.FieldType) bool {
+	for _, fn := range AggregateFuncsFor(ft) {
+		if fn == f {
+			return true
+		}
+	}
+	return false
+}
+
+// AggregateFuncsFor 字段类型可用的聚合函数
+//
+// 按字段类别划分：
+//   - 基础 / 计算 / 系统字段：数字类型（含 rollup、count）可用求和、平均、最值、中位数；
+//     日期类型可用最早/最晚日期；复选框只有勾选统计；其余只有通用统计
+//   - 选项、关联、附件、AI 字段：只有通用统计（空值、非空、去重及其占比）
+func AggregateFuncsFor(ft fieldValueObject.FieldType) []AggregateFunc {
+	switch ft.Category() {
+	case fieldValueObject.CategoryBasic, fieldValueObject.CategoryComputed, fieldValueObject.CategorySystem:
+		switch ft.String() {
+		case fieldValueObject.TypeNumber, fieldValueObject.TypeRating, fieldValueObject.TypePercent,
+			fieldValueObject.TypeCurrency, fieldValueObject.TypeDuration, fieldValueObject.TypeAutoNumber,
+			fieldValueObject.TypeRollup, fieldValueObject.TypeCount:
+			return append(append([]AggregateFunc{}, commonAggregateFuncs...), numberAggregateFuncs...)
+		case fieldValueObject.TypeDate, fieldValueObject.TypeDateTime,
+			fieldValueObject.TypeCreatedTime, fieldValueObject.TypeModifiedTime:
+			return append(append([]AggregateFunc{}, commonAggregateFuncs...), dateAggregateFuncs...)
+		case fieldValueObject.TypeCheckbox, fieldValueObject.TypeBoolean:
+			return append([]AggregateFunc{}, checkboxAggregateFuncs...)
+		}
+	}
+	return append([]AggregateFunc{}, commonAggregateFuncs...)
+}
@@ -0,0 +1,40 @@
+package valueobject
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+func TestAggregateFuncsFor(t *testing.T) {
+	fieldType := func(value string) fieldValueObject.FieldType {
+		ft, err := fieldValueObject.NewFieldType(value)
+		require.NoError(t, err)
+		return ft
+	}
+
+	number := fieldType(fieldValueObject.TypeNumber)
+	assert.True(t, AggregateMedian.SupportsFieldType(number))
+	assert.True(t, AggregatePercentEmpty.SupportsFieldType(number))
+	assert.False(t, AggregateLatestDate.SupportsFieldType(number))
+
+	rollup := fieldType(fieldValueObject.TypeRollup)
+	assert.True(t, AggregateSum.SupportsFieldType(rollup))
+
+	createdTime := fieldType(fieldValueObject.TypeCreatedTime)
+	assert.True(t, AggregateEarliestDate.SupportsFieldType(createdTime))
+	assert.False(t, AggregateSum.SupportsFieldType(createdTime))
+
+	checkbox := fieldType(fieldValueObject.TypeCheckbox)
+	assert.True(t, AggregatePercentChecked.SupportsFieldType(checkbox))
+	assert.False(t, AggregateUniqueCount.SupportsFieldType(checkbox))
+
+	link := fieldType(fieldValueObject.TypeLink)
+	assert.Equal(t, commonAggregateFuncs, AggregateFuncsFor(link))
+	assert.False(t, AggregateMax.SupportsFieldType(link))
+
+	assert.False(t, AggregateFunc("mode").IsValid())
+}
@@ -0,0 +1,71 @@
+package valueobject
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record"
+)
+
+// cursorKeyLabel 游标签名密钥的派生标签
+const cursorKeyLabel = "luckdb/record-cursor/v1"
+
+// DeriveCursorKey 从服务端主密钥派生游标签名密钥：HMAC-SHA256(主密钥, 用途标签)
+// 游标与 JWT 不共用签名密钥，客户端可见的游标签名不会成为 JWT 密钥的签名样本
+func DeriveCursorKey(secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(cursorKeyLabel))
+	return mac.Sum(nil)
+}
+
+// RecordCursor 键集分页游标值对象
+// 保存上一页最后一条记录的排序键取值与 __id，签名后以不透明字符串交给客户端
+type RecordCursor struct {
+	SortKey string    `json:"k"`  // 排序配置指纹，排序变化后旧游标失效
+	Values  []*string `json:"v"`  // 排序键取值（文本形式，nil 表示 NULL）
+	ID      string    `json:"id"` // 记录ID（排序键相同时的稳定次序）
+}
+
+// Encode 编码并签名游标：base64url(payload) + "." + base64url(HMAC-SHA256)
+func (c *RecordCursor) Encode(secret []byte) (string, error) {
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + signCursor(encoded, secret), nil
+}
+
+// DecodeRecordCursor 校验签名并解码游标
+func DecodeRecordCursor(token string, secret []byte) (*RecordCursor, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || encoded == "" {
+		return nil, record.ErrInvalidCursor
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(signCursor(encoded, secret))) {
+		return nil, record.ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, record.ErrInvalidCursor
+	}
+
+	var cursor RecordCursor
+	if err := json.Unmarshal(payload, &cursor); err != nil || cursor.ID == "" {
+		return nil, record.ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+func signCursor(encoded string, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,57 @@
+package valueobject
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record"
+)
+
+func TestRecordCursor_RoundTrip(t *testing.T) {
+	secret := []byte("cursor-secret")
+	amount := "42.5"
+	cursor := &RecordCursor{SortKey: "fld_amount:desc", Values: []*string{&amount, nil}, ID: "rec_1"}
+
+	token, err := cursor.Encode(secret)
+	require.NoError(t, err)
+
+	decoded, err := DecodeRecordCursor(token, secret)
+	require.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+}
+
+func TestRecordCursor_RejectsTampering(t *testing.T) {
+	secret := []byte("cursor-secret")
+	token, err := (&RecordCursor{SortKey: "k", ID: "rec_1"}).Encode(secret)
+	require.NoError(t, err)
+
+	_, err = DecodeRecordCursor(token, []byte("other-secret"))
+	assert.ErrorIs(t, err, record.ErrInvalidCursor)
+
+	forged, err := (&RecordCursor{SortKey: "k", ID: "rec_2"}).Encode([]byte("other-secret"))
+	require.NoError(t, err)
+	payload, _, _ := strings.Cut(forged, ".")
+	_, signature, _ := strings.Cut(token, ".")
+	_, err = DecodeRecordCursor(payload+"."+signature, secret)
+	assert.ErrorIs(t, err, record.ErrInvalidCursor)
+
+	_, err = DecodeRecordCursor("not-a-cursor", secret)
+	assert.ErrorIs(t, err, record.ErrInvalidCursor)
+}
+
+func TestDeriveCursorKey(t *testing.T) {
+	key := DeriveCursorKey("jwt-secret")
+	assert.Len(t, key, 32)
+	assert.Equal(t, key, DeriveCursorKey("jwt-secret"))
+	assert.NotEqual(t, []byte("jwt-secret"), key)
+	assert.NotEqual(t, key, DeriveCursorKey("other-secret"))
+
+	// 用主密钥直接签名的游标不被接受
+	token, err := (&RecordCursor{SortKey: "k", ID: "rec_1"}).Encode([]byte("jwt-secret"))
+	require.NoError(t, err)
+	_, err = DecodeRecordCursor(token, key)
+	assert.ErrorIs(t, err, record.ErrInvalidCursor)
+}
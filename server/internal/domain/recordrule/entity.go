@@ -0,0 +1,28 @@
+package recordrule
+
+import "time"
+
+// Rule 表级记录校验规则
+//
+// Expression 使用公式语言编写，引用字段用 {字段名} 或 {字段ID}，
+// 记录写入前求值，结果为假时拒绝写入并返回 Message
+type Rule struct {
+	ID               string
+	TableID          string
+	Name             string
+	Expression       string
+	Message          string
+	Enabled          bool
+	CreatedBy        string
+	LastModifiedBy   string
+	CreatedTime      time.Time
+	LastModifiedTime time.Time
+}
+
+// ViolationMessage 规则不通过时返回的提示，未设置时使用规则名称
+func (r *Rule) ViolationMessage() string {
+	if r.Message != "" {
+		return r.Message
+	}
+	return "不满足校验规则：" + r.Name
+}
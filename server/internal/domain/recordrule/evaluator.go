@@ -0,0 +1,236 @@
+package recordrule
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/calculation/formula"
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/validation"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+// booleanCallPattern 公式语法中 TRUE/FALSE 是字面量，兼容 TRUE()/FALSE() 的写法
+var booleanCallPattern = regexp.MustCompile(`(?i)\b(TRUE|FALSE)\s*\(\s*\)`)
+
+// CheckExpression 检查表达式语法，返回引用的字段（花括号内的字段名或字段ID，去重）
+func CheckExpression(expression string) ([]string, error) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, fmt.Errorf("表达式不能为空")
+	}
+	normalized, refs := scanExpression(expression)
+
+	// 用空记录试算：只拦截语法错误与未知函数，取值相关的错误（如除以0）留到写入时判断
+	result, err := evaluate(normalized, map[string]interface{}{})
+	if err != nil {
+		return nil, err
+	}
+	if msg, ok := formulaError(result); ok && strings.HasPrefix(msg, "Unknown function") {
+		return nil, fmt.Errorf("%s", msg)
+	}
+	return refs, nil
+}
+
+// Evaluate 对记录取值求值规则表达式，返回是否通过
+//
+// 结果为 FALSE 或 0 时不通过；结果为空（如 IF 条件不成立且没有 else 分支）视为规则不适用，算通过
+func Evaluate(expression string, values map[string]interface{}) (bool, error) {
+	normalized, _ := scanExpression(expression)
+	result, err := evaluate(normalized, values)
+	if err != nil {
+		return false, err
+	}
+	if msg, ok := formulaError(result); ok {
+		return false, fmt.Errorf("%s", msg)
+	}
+
+	switch {
+	case result == nil || result.IsNull():
+		return true, nil
+	case result.Type == formula.CellValueTypeBoolean:
+		return result.AsBoolean(), nil
+	case result.Type == formula.CellValueTypeNumber:
+		return result.AsNumber() != 0, nil
+	default:
+		return true, nil
+	}
+}
+
+// Values 把记录数据转换为求值用的字段取值，同时以字段ID和字段名称为键
+//
+// 表格的每个字段都会出现：空值统一为 ""，便于 {Owner} != "" 之类的判断；
+// 选项ID转换为选项名称，用户与关联字段取标题，日期统一为 RFC3339（UTC）以便比较
+func Values(fields []*fieldEntity.Field, data map[string]interface{}) map[string]interface{} {
+	values := make(map[string]interface{}, len(fields)*2)
+	for _, field := range fields {
+		fieldID := field.ID().String()
+		name := field.Name().String()
+
+		// 未转换的更新数据以字段名为键，优先于存储中以字段ID为键的旧值
+		raw, ok := data[name]
+		if !ok {
+			raw = data[fieldID]
+		}
+		value := cellValue(field, raw)
+		values[fieldID] = value
+		values[name] = value
+	}
+	return values
+}
+
+// evaluate 调用公式求值，防止求值过程中的 panic 影响写入流程
+func evaluate(expression string, values map[string]interface{}) (result *formula.TypedValue, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("表达式求值失败: %v", r)
+		}
+	}()
+	return formula.Evaluate(expression, values, nil, "UTC")
+}
+
+// formulaError 公式函数以 "#ERROR" 开头的字符串返回错误
+func formulaError(result *formula.TypedValue) (string, bool) {
+	if result == nil || result.Type != formula.CellValueTypeString {
+		return "", false
+	}
+	str, ok := result.Value.(string)
+	if !ok || !strings.HasPrefix(str, "#ERROR") {
+		return "", false
+	}
+	msg := strings.TrimSpace(strings.TrimLeft(strings.TrimPrefix(str, "#ERROR"), ":!"))
+	if msg == "" {
+		msg = "表达式求值失败"
+	}
+	return msg, true
+}
+
+// scanExpression 规范化表达式并提取字段引用
+//
+// 字符串字面量与 {字段} 引用原样保留，其余部分把 TRUE()/FALSE() 替换为字面量
+func scanExpression(expression string) (string, []string) {
+	var out, code strings.Builder
+	refs := make([]string, 0)
+	seen := make(map[string]bool)
+	flush := func() {
+		out.WriteString(booleanCallPattern.ReplaceAllString(code.String(), "$1"))
+		code.Reset()
+	}
+
+	runes := []rune(expression)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '"', '\'':
+			flush()
+			j := i + 1
+			for j < len(runes) && runes[j] != r {
+				if runes[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(runes) {
+				j = len(runes) - 1
+			}
+			out.WriteString(string(runes[i : j+1]))
+			i = j
+		case '{':
+			flush()
+			j := i + 1
+			for j < len(runes) && runes[j] != '}' {
+				j++
+			}
+			if j >= len(runes) {
+				j = len(runes) - 1
+			} else if ref := string(runes[i+1 : j]); ref != "" && !seen[ref] {
+				seen[ref] = true
+				refs = append(refs, ref)
+			}
+			out.WriteString(string(runes[i : j+1]))
+			i = j
+		default:
+			code.WriteRune(r)
+		}
+	}
+	flush()
+	return out.String(), refs
+}
+
+// cellValue 单元格值转换为公式可比较的取值
+func cellValue(field *fieldEntity.Field, raw interface{}) interface{} {
+	if isBlank(raw) {
+		return ""
+	}
+
+	switch field.Type().String() {
+	case valueobject.TypeSingleSelect, valueobject.TypeMultipleSelect, valueobject.TypeSelect:
+		return mapValues(raw, func(v interface{}) interface{} { return choiceName(field, v) })
+	case valueobject.TypeUser, valueobject.TypeCreatedBy, valueobject.TypeLastModifiedBy, valueobject.TypeLink:
+		return mapValues(raw, referenceTitle)
+	case valueobject.TypeDate, valueobject.TypeDateTime, valueobject.TypeCreatedTime, valueobject.TypeLastModifiedTime:
+		if t, ok := validation.CoerceDate(raw); ok {
+			return t.UTC().Format(time.RFC3339)
+		}
+	}
+	return raw
+}
+
+// mapValues 对单值或多值逐项转换，多值只有一项时按单值处理
+func mapValues(raw interface{}, fn func(interface{}) interface{}) interface{} {
+	items, ok := raw.([]interface{})
+	if !ok {
+		return fn(raw)
+	}
+	if len(items) == 1 {
+		return fn(items[0])
+	}
+	result := make([]interface{}, len(items))
+	for i, item := range items {
+		result[i] = fn(item)
+	}
+	return result
+}
+
+// choiceName 选项ID转换为选项名称，找不到时原样返回
+func choiceName(field *fieldEntity.Field, value interface{}) interface{} {
+	id, ok := value.(string)
+	options := field.Options()
+	if !ok || options == nil || options.Select == nil {
+		return value
+	}
+	for _, choice := range options.Select.Choices {
+		if choice.ID == id {
+			return choice.Name
+		}
+	}
+	return value
+}
+
+// referenceTitle 用户、关联对象取显示标题
+func referenceTitle(value interface{}) interface{} {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	for _, key := range []string{"title", "name", "email", "id"} {
+		if s, ok := obj[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func isBlank(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	case []string:
+		return len(v) == 0
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package recordrule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+)
+
+func newRuleTestField(t *testing.T, name, fieldType string, options *valueobject.FieldOptions) *entity.Field {
+	ft, err := valueobject.NewFieldType(fieldType)
+	require.NoError(t, err)
+	fieldName, err := valueobject.NewFieldName(name)
+	require.NoError(t, err)
+	field, err := entity.NewField("tbl_test", fieldName, ft, "usr_test")
+	require.NoError(t, err)
+	if options != nil {
+		require.NoError(t, field.UpdateOptions(options))
+	}
+	return field
+}
+
+func TestCheckExpression(t *testing.T) {
+	refs, err := CheckExpression(`IF({Status}="Done", {Owner}!="", TRUE())`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Status", "Owner"}, refs)
+
+	refs, err = CheckExpression(`{End} >= {Start} && {End} != "{Start}"`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"End", "Start"}, refs, "字符串中的花括号不是字段引用")
+
+	for _, expr := range []string{"", "{End} >=", "NOPE({End})"} {
+		_, err := CheckExpression(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	status := newRuleTestField(t, "Status", valueobject.TypeSingleSelect, &valueobject.FieldOptions{Select: &valueobject.SelectOptions{
+		Choices: []valueobject.SelectChoice{{ID: "cho_todo", Name: "Todo"}, {ID: "cho_done", Name: "Done"}},
+	}})
+	owner := newRuleTestField(t, "Owner", valueobject.TypeUser, nil)
+	start := newRuleTestField(t, "Start", valueobject.TypeDate, nil)
+	end := newRuleTestField(t, "End", valueobject.TypeDate, nil)
+	fields := []*entity.Field{status, owner, start, end}
+
+	ownerRule := `IF({Status}="Done", {Owner}!="", TRUE())`
+	dateRule := `{End} >= {Start}`
+
+	cases := []struct {
+		name   string
+		expr   string
+		data   map[string]interface{}
+		passed bool
+	}{
+		{"选项ID按名称比较且缺少负责人", ownerRule, map[string]interface{}{status.ID().String(): "cho_done"}, false},
+		{"有负责人", ownerRule, map[string]interface{}{
+			status.ID().String(): "cho_done",
+			owner.ID().String():  map[string]interface{}{"id": "usr_1", "title": "Alice"},
+		}, true},
+		{"状态不是完成", ownerRule, map[string]interface{}{status.ID().String(): "cho_todo"}, true},
+		{"按字段名传入的新值优先", ownerRule, map[string]interface{}{
+			status.ID().String(): "cho_todo",
+			"Status":             "Done",
+		}, false},
+		{"结束日期不早于开始日期", dateRule, map[string]interface{}{
+			start.ID().String(): "2024-03-01",
+			end.ID().String():   time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		}, true},
+		{"结束日期早于开始日期", dateRule, map[string]interface{}{
+			start.ID().String(): "2024-03-02T00:00:00Z",
+			end.ID().String():   "2024-03-01",
+		}, false},
+		{"允许留空", `OR({End}="", {End} >= {Start})`, map[string]interface{}{start.ID().String(): "2024-03-02"}, true},
+		{"IF 未命中视为通过", `IF({Status}="Done", FALSE)`, map[string]interface{}{}, true},
+		{"数字结果", `0`, map[string]interface{}{}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			passed, err := Evaluate(tc.expr, Values(fields, tc.data))
+			require.NoError(t, err)
+			assert.Equal(t, tc.passed, passed)
+		})
+	}
+
+	_, err := Evaluate(`1/0`, Values(fields, nil))
+	assert.Error(t, err)
+}
@@ -0,0 +1,17 @@
+package recordrule
+
+import "context"
+
+// Repository 记录校验规则仓储接口
+type Repository interface {
+	// Create 创建规则
+	Create(ctx context.Context, rule *Rule) error
+	// Update 更新规则的名称、表达式、提示与启用状态
+	Update(ctx context.Context, rule *Rule) error
+	// Delete 删除规则
+	Delete(ctx context.Context, id string) error
+	// FindByID 获取规则，不存在时返回 nil
+	FindByID(ctx context.Context, id string) (*Rule, error)
+	// FindByTableID 获取表格的所有规则（按创建时间排序）
+	FindByTableID(ctx context.Context, tableID string) ([]*Rule, error)
+}
@@ -12,6 +12,7 @@ type SearchType string
 const (
 	SearchTypeGlobal     SearchType = "global"     // 全局搜索
 	SearchTypeSpace      SearchType = "space"      // 空间内搜索
+	SearchTypeBase       SearchType = "base"       // Base搜索
 	SearchTypeTable      SearchType = "table"      // 表格内搜索
 	SearchTypeRecord     SearchType = "record"     // 记录搜索
 	SearchTypeField      SearchType = "field"      // 字段搜索
@@ -57,6 +58,7 @@ type SearchRequest struct {
 	SourceType  string                 `json:"source_type,omitempty"`
 	UserID      string                 `json:"user_id,omitempty"`
 	SpaceID     string                 `json:"space_id,omitempty"`
+	BaseID      string                 `json:"base_id,omitempty"`
 	TableID     string                 `json:"table_id,omitempty"`
 	FieldIDs    []string               `json:"field_ids,omitempty"`
 	Filters     map[string]interface{} `json:"filters,omitempty"`
@@ -66,6 +68,9 @@ type SearchRequest struct {
 	PageSize    int                    `json:"page_size,omitempty"`
 	Highlight   bool                   `json:"highlight,omitempty"`
 	Suggestions bool                   `json:"suggestions,omitempty"`
+
+	// ViewerID 当前用户，由服务端根据认证信息填充：结果只包含其可访问的空间和 Base
+	ViewerID string `json:"-"`
 }
 
 // SearchResponse 搜索响应
@@ -95,6 +100,7 @@ type SearchIndex struct {
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
 	UserID      string                 `json:"user_id,omitempty"`
 	SpaceID     string                 `json:"space_id,omitempty"`
+	BaseID      string                 `json:"base_id,omitempty"`
 	TableID     string                 `json:"table_id,omitempty"`
 	FieldID     string                 `json:"field_id,omitempty"`
 	Permissions []string               `json:"permissions,omitempty"`
@@ -163,6 +169,12 @@ func (s *SearchIndex) SetContext(userID, spaceID, tableID, fieldID string) {
 	s.UpdatedTime = time.Now()
 }
 
+// SetBase 设置所属 Base
+func (s *SearchIndex) SetBase(baseID string) {
+	s.BaseID = baseID
+	s.UpdatedTime = time.Now()
+}
+
 // AddPermission 添加权限
 func (s *SearchIndex) AddPermission(permission string) {
 	for _, p := range s.Permissions {
@@ -244,12 +256,16 @@ type AdvancedSearchRequest struct {
 	SourceType string         `json:"source_type,omitempty"`
 	UserID     string         `json:"user_id,omitempty"`
 	SpaceID    string         `json:"space_id,omitempty"`
+	BaseID     string         `json:"base_id,omitempty"`
 	TableID    string         `json:"table_id,omitempty"`
 	FieldIDs   []string       `json:"field_ids,omitempty"`
 	Page       int            `json:"page,omitempty"`
 	PageSize   int            `json:"page_size,omitempty"`
 	Highlight  bool           `json:"highlight,omitempty"`
 	Facets     []string       `json:"facets,omitempty"`
+
+	// ViewerID 当前用户，由服务端根据认证信息填充：结果只包含其可访问的空间和 Base
+	ViewerID string `json:"-"`
 }
 
 // SearchIndexRequest 创建搜索索引请求
@@ -264,6 +280,7 @@ type SearchIndexRequest struct {
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
 	UserID      string                 `json:"user_id,omitempty"`
 	SpaceID     string                 `json:"space_id,omitempty"`
+	BaseID      string                 `json:"base_id,omitempty"`
 	TableID     string                 `json:"table_id,omitempty"`
 	FieldID     string                 `json:"field_id,omitempty"`
 	Permissions []string               `json:"permissions,omitempty"`
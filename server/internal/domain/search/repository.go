@@ -2,32 +2,43 @@ package search
 
 import (
 	"context"
+	"time"
 )
 
 // Repository 搜索仓储接口
 type Repository interface {
 	// Index management
-	// CreateIndex 创建搜索索引
-	CreateIndex(ctx context.Context, index *SearchIndex) error
+	// 以下方法的 viewerID 非空时只作用于该用户可访问的条目（与 Search 的访问控制一致），为空时不限制（内部调用）
+	// CreateIndex 创建搜索索引，条目不在 viewerID 可访问范围内时返回 ErrForbidden
+	CreateIndex(ctx context.Context, index *SearchIndex, viewerID string) error
 	// GetIndex 获取搜索索引
-	GetIndex(ctx context.Context, id string) (*SearchIndex, error)
+	GetIndex(ctx context.Context, id, viewerID string) (*SearchIndex, error)
 	// UpdateIndex 更新搜索索引
-	UpdateIndex(ctx context.Context, index *SearchIndex) error
+	UpdateIndex(ctx context.Context, index *SearchIndex, viewerID string) error
 	// DeleteIndex 删除搜索索引
-	DeleteIndex(ctx context.Context, id string) error
+	DeleteIndex(ctx context.Context, id, viewerID string) error
 	// DeleteIndexesBySource 根据来源删除搜索索引
-	DeleteIndexesBySource(ctx context.Context, sourceID, sourceType string) error
+	DeleteIndexesBySource(ctx context.Context, sourceID, sourceType, viewerID string) error
 	// ListIndexes 列出搜索索引
-	ListIndexes(ctx context.Context, searchType *SearchType, sourceID, sourceType string, page, pageSize int) ([]*SearchIndex, int64, error)
+	ListIndexes(ctx context.Context, searchType *SearchType, sourceID, sourceType, viewerID string, page, pageSize int) ([]*SearchIndex, int64, error)
+	// UpsertIndexes 按来源（source_type + source_id）批量写入或覆盖搜索索引
+	UpsertIndexes(ctx context.Context, indexes []*SearchIndex) error
+	// DeleteIndexesBySources 批量删除同一来源类型的搜索索引
+	DeleteIndexesBySources(ctx context.Context, sourceType string, sourceIDs []string) error
+	// DeleteIndexesByTable 删除表格自身及其字段、记录等全部搜索索引
+	DeleteIndexesByTable(ctx context.Context, tableID string) error
+	// DeleteStaleIndexes 删除 Base（tableID 非空时为该表格）内 before 之后未再写入的搜索索引，
+	// 用于重建后清理来源已不存在的条目
+	DeleteStaleIndexes(ctx context.Context, baseID, tableID string, before time.Time) error
 
 	// Search operations
 	// Search 搜索
 	Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error)
 	// AdvancedSearch 高级搜索
 	AdvancedSearch(ctx context.Context, req *AdvancedSearchRequest) (*SearchResponse, error)
-	// SearchSuggestions 搜索建议
-	SearchSuggestions(ctx context.Context, query string, limit int) ([]*SearchSuggestion, error)
-	// GetPopularQueries 获取热门查询
+	// SearchSuggestions 搜索建议（标题匹配关键词的条目，viewerID 非空时只包含其可访问的条目）
+	SearchSuggestions(ctx context.Context, query, viewerID string, limit int) ([]*SearchSuggestion, error)
+	// GetPopularQueries 获取热门查询（全部用户的查询历史）
 	GetPopularQueries(ctx context.Context, limit int) ([]*SearchSuggestion, error)
 
 	// Statistics
@@ -9,12 +9,14 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/easyspace-ai/luckdb/server/pkg/authctx"
 	pkgErrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
 )
 
 // Service 搜索服务接口
 type Service interface {
 	// Index management
+	// 索引管理只作用于当前用户（认证上下文中的用户）可访问的空间和 Base 内的条目
 	// CreateIndex 创建搜索索引
 	CreateIndex(ctx context.Context, req *SearchIndexRequest) (*SearchIndex, error)
 	// GetIndex 获取搜索索引
@@ -77,6 +79,7 @@ func (s *service) CreateIndex(ctx context.Context, req *SearchIndexRequest) (*Se
 
 	// 设置上下文
 	index.SetContext(req.UserID, req.SpaceID, req.TableID, req.FieldID)
+	index.SetBase(req.BaseID)
 
 	// 添加关键词
 	if req.Keywords != nil {
@@ -107,7 +110,10 @@ func (s *service) CreateIndex(ctx context.Context, req *SearchIndexRequest) (*Se
 	// 自动提取关键词
 	s.extractKeywords(index)
 
-	if err := s.repo.CreateIndex(ctx, index); err != nil {
+	if err := s.repo.CreateIndex(ctx, index, viewerFrom(ctx)); err != nil {
+		if appErr, ok := pkgErrors.IsAppError(err); ok {
+			return nil, appErr
+		}
 		s.logger.Error("Failed to create search index",
 			zap.String("type", string(req.Type)),
 			zap.String("source_id", req.SourceID),
@@ -121,7 +127,7 @@ func (s *service) CreateIndex(ctx context.Context, req *SearchIndexRequest) (*Se
 
 // GetIndex 获取搜索索引
 func (s *service) GetIndex(ctx context.Context, id string) (*SearchIndex, error) {
-	index, err := s.repo.GetIndex(ctx, id)
+	index, err := s.repo.GetIndex(ctx, id, viewerFrom(ctx))
 	if err != nil {
 		if errors.Is(err, pkgErrors.ErrNotFound) {
 			return nil, pkgErrors.ErrNotFound.WithDetails("Search index not found")
@@ -137,7 +143,8 @@ func (s *service) GetIndex(ctx context.Context, id string) (*SearchIndex, error)
 
 // UpdateIndex 更新搜索索引
 func (s *service) UpdateIndex(ctx context.Context, id string, req *UpdateSearchIndexRequest) (*SearchIndex, error) {
-	index, err := s.repo.GetIndex(ctx, id)
+	viewerID := viewerFrom(ctx)
+	index, err := s.repo.GetIndex(ctx, id, viewerID)
 	if err != nil {
 		if errors.Is(err, pkgErrors.ErrNotFound) {
 			return nil, pkgErrors.ErrNotFound.WithDetails("Search index not found")
@@ -176,7 +183,7 @@ func (s *service) UpdateIndex(ctx context.Context, id string, req *UpdateSearchI
 	// 重新提取关键词
 	s.extractKeywords(index)
 
-	if err := s.repo.UpdateIndex(ctx, index); err != nil {
+	if err := s.repo.UpdateIndex(ctx, index, viewerID); err != nil {
 		s.logger.Error("Failed to update search index",
 			zap.String("id", id),
 			zap.Error(err))
@@ -188,7 +195,7 @@ func (s *service) UpdateIndex(ctx context.Context, id string, req *UpdateSearchI
 
 // DeleteIndex 删除搜索索引
 func (s *service) DeleteIndex(ctx context.Context, id string) error {
-	if err := s.repo.DeleteIndex(ctx, id); err != nil {
+	if err := s.repo.DeleteIndex(ctx, id, viewerFrom(ctx)); err != nil {
 		if errors.Is(err, pkgErrors.ErrNotFound) {
 			return pkgErrors.ErrNotFound.WithDetails("Search index not found")
 		}
@@ -203,7 +210,7 @@ func (s *service) DeleteIndex(ctx context.Context, id string) error {
 
 // DeleteIndexesBySource 根据来源删除搜索索引
 func (s *service) DeleteIndexesBySource(ctx context.Context, sourceID, sourceType string) error {
-	if err := s.repo.DeleteIndexesBySource(ctx, sourceID, sourceType); err != nil {
+	if err := s.repo.DeleteIndexesBySource(ctx, sourceID, sourceType, viewerFrom(ctx)); err != nil {
 		s.logger.Error("Failed to delete search indexes by source",
 			zap.String("source_id", sourceID),
 			zap.String("source_type", sourceType),
@@ -216,7 +223,7 @@ func (s *service) DeleteIndexesBySource(ctx context.Context, sourceID, sourceTyp
 
 // ListIndexes 列出搜索索引
 func (s *service) ListIndexes(ctx context.Context, searchType *SearchType, sourceID, sourceType string, page, pageSize int) ([]*SearchIndex, int64, error) {
-	indexes, total, err := s.repo.ListIndexes(ctx, searchType, sourceID, sourceType, page, pageSize)
+	indexes, total, err := s.repo.ListIndexes(ctx, searchType, sourceID, sourceType, viewerFrom(ctx), page, pageSize)
 	if err != nil {
 		s.logger.Error("Failed to list search indexes",
 			zap.String("source_id", sourceID),
@@ -228,6 +235,12 @@ func (s *service) ListIndexes(ctx context.Context, searchType *SearchType, sourc
 	return indexes, total, nil
 }
 
+// viewerFrom 认证上下文中的当前用户，内部调用（无认证信息）时为空，不限制访问范围
+func viewerFrom(ctx context.Context) string {
+	viewerID, _ := authctx.UserFrom(ctx)
+	return viewerID
+}
+
 // Search 搜索
 func (s *service) Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
 	startTime := time.Now()
@@ -255,9 +268,17 @@ func (s *service) Search(ctx context.Context, req *SearchRequest) (*SearchRespon
 	// 预处理查询
 	req.Query = s.preprocessQuery(req.Query)
 
+	// 结果限定在当前用户可访问的空间和 Base 内
+	if viewerID, ok := authctx.UserFrom(ctx); ok {
+		req.ViewerID = viewerID
+	}
+
 	// 执行搜索
 	response, err := s.repo.Search(ctx, req)
 	if err != nil {
+		if appErr, ok := pkgErrors.IsAppError(err); ok {
+			return nil, appErr
+		}
 		s.logger.Error("Failed to search",
 			zap.String("query", req.Query),
 			zap.String("type", string(req.Type)),
@@ -304,9 +325,17 @@ func (s *service) AdvancedSearch(ctx context.Context, req *AdvancedSearchRequest
 		req.Queries[i] = s.preprocessQuery(query)
 	}
 
+	// 结果限定在当前用户可访问的空间和 Base 内
+	if viewerID, ok := authctx.UserFrom(ctx); ok {
+		req.ViewerID = viewerID
+	}
+
 	// 执行高级搜索
 	response, err := s.repo.AdvancedSearch(ctx, req)
 	if err != nil {
+		if appErr, ok := pkgErrors.IsAppError(err); ok {
+			return nil, appErr
+		}
 		s.logger.Error("Failed to perform advanced search",
 			zap.Strings("queries", req.Queries),
 			zap.String("type", string(req.Type)),
@@ -336,7 +365,7 @@ func (s *service) SearchSuggestions(ctx context.Context, query string, limit int
 	// 预处理查询
 	query = s.preprocessQuery(query)
 
-	suggestions, err := s.repo.SearchSuggestions(ctx, query, limit)
+	suggestions, err := s.repo.SearchSuggestions(ctx, query, viewerFrom(ctx), limit)
 	if err != nil {
 		s.logger.Error("Failed to get search suggestions",
 			zap.String("query", query),
@@ -377,8 +406,15 @@ func (s *service) GetSearchStats(ctx context.Context) (*SearchStats, error) {
 // RebuildIndex 重建索引
 func (s *service) RebuildIndex(ctx context.Context, searchType *SearchType) error {
 	if err := s.repo.RebuildIndex(ctx, searchType); err != nil {
+		if appErr, ok := pkgErrors.IsAppError(err); ok {
+			return appErr
+		}
+		typeName := "all"
+		if searchType != nil {
+			typeName = string(*searchType)
+		}
 		s.logger.Error("Failed to rebuild search index",
-			zap.String("type", string(*searchType)),
+			zap.String("type", typeName),
 			zap.Error(err))
 		return pkgErrors.ErrInternalServer.WithDetails(err.Error())
 	}
@@ -66,7 +66,8 @@ type FieldService interface {
 
 // RecordService 记录服务接口
 type RecordService interface {
-	CreateRecord(ctx context.Context, tableID string, fields map[string]interface{}) (string, error)
+	// CreateRecord 创建记录，返回记录ID与最终写入的字段值（包含填充的默认值）
+	CreateRecord(ctx context.Context, tableID string, fields map[string]interface{}, typecast bool) (string, map[string]interface{}, error)
 	GetRecordsByIDs(ctx context.Context, tableID string, recordIDs []string) ([]interface{}, error)
 	GetLinkedRecords(ctx context.Context, tableID, recordID, fieldID string) ([]map[string]interface{}, error)
 }
@@ -312,7 +313,7 @@ func (s *service) SubmitForm(ctx context.Context, shareID string, req *ShareForm
 
 	// 实现表单提交逻辑（参考 teable-develop）
 	// 调用记录服务创建新记录
-	recordID, err := s.recordService.CreateRecord(ctx, shareView.TableID, req.Fields)
+	recordID, fields, err := s.recordService.CreateRecord(ctx, shareView.TableID, req.Fields, req.Typecast)
 	if err != nil {
 		s.logger.Error("Failed to create record via share",
 			logger.String("share_id", shareID),
@@ -323,7 +324,7 @@ func (s *service) SubmitForm(ctx context.Context, shareID string, req *ShareForm
 
 	response := &ShareFormSubmitResponse{
 		RecordID: recordID,
-		Fields:   req.Fields,
+		Fields:   fields,
 	}
 
 	s.logger.Info("Form submitted via share",
@@ -196,6 +196,27 @@ func (t *Table) SetDBTableName(dbTableName string) {
 	t.updatedAt = time.Now()
 }
 
+// MoveToBase 把表格移到同一空间下的另一个Base，物理表名随之变更
+func (t *Table) MoveToBase(baseID, dbTableName string) error {
+	if t.IsDeleted() {
+		return table.ErrCannotModifyDeletedTable
+	}
+	if baseID == "" {
+		return table.NewDomainError(
+			"INVALID_BASE_ID",
+			"base id cannot be empty",
+			nil,
+		)
+	}
+
+	t.baseID = baseID
+	t.dbTableName = &dbTableName
+	t.updatedAt = time.Now()
+	t.incrementVersion()
+
+	return nil
+}
+
 // SoftDelete 软删除表格
 func (t *Table) SoftDelete() error {
 	if t.IsDeleted() {
@@ -0,0 +1,46 @@
+package task
+
+import "time"
+
+// Status 任务（及其运行）的状态
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+)
+
+// IsFinished 是否已结束
+func (s Status) IsFinished() bool {
+	return s == StatusCompleted || s == StatusFailed
+}
+
+// Task 后台任务（导入、导出等）
+//
+// Snapshot 保存发起任务时的参数（JSON），由任务类型自行解释
+type Task struct {
+	ID               string
+	Type             string
+	Status           Status
+	Snapshot         string
+	CreatedBy        string
+	CreatedTime      time.Time
+	LastModifiedTime time.Time
+}
+
+// Run 任务的一次执行
+//
+// Snapshot 保存执行进度与结果报告（JSON），Spent 为耗时（毫秒）
+type Run struct {
+	ID               string
+	TaskID           string
+	Status           Status
+	Snapshot         string
+	Spent            *int
+	ErrorMsg         string
+	StartedTime      *time.Time
+	CreatedTime      time.Time
+	LastModifiedTime time.Time
+}
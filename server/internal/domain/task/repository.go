@@ -0,0 +1,20 @@
+package task
+
+import "context"
+
+// Repository 后台任务仓储接口
+type Repository interface {
+	// CreateTask 创建任务
+	CreateTask(ctx context.Context, task *Task) error
+	// UpdateTask 更新任务状态与快照
+	UpdateTask(ctx context.Context, task *Task) error
+	// FindTaskByID 获取任务，不存在时返回 nil
+	FindTaskByID(ctx context.Context, id string) (*Task, error)
+
+	// CreateRun 创建任务运行记录
+	CreateRun(ctx context.Context, run *Run) error
+	// UpdateRun 更新任务运行的状态、进度与耗时
+	UpdateRun(ctx context.Context, run *Run) error
+	// FindLatestRun 获取任务最近一次运行，不存在时返回 nil
+	FindLatestRun(ctx context.Context, taskID string) (*Run, error)
+}
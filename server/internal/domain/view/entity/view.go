@@ -448,6 +448,44 @@ func (v *View) GetAllFieldIDs() []string {
 	return fieldIDs
 }
 
+// RemoveFieldReferences 字段被删除后移除列配置、过滤、排序与分组中对这些字段的引用
+// 属于系统清理，不受视图锁定限制；返回视图是否有变化
+func (v *View) RemoveFieldReferences(fieldIDs ...string) bool {
+	referenced := make(map[string]bool)
+	for _, fieldID := range v.GetAllFieldIDs() {
+		referenced[fieldID] = true
+	}
+
+	changed := false
+	for _, fieldID := range fieldIDs {
+		if !referenced[fieldID] {
+			continue
+		}
+		changed = true
+		v.columnMeta.RemoveColumn(fieldID)
+		v.filter.RemoveFilterItems(fieldID)
+		v.sort.RemoveSortItem(fieldID)
+		v.group.RemoveGroupItem(fieldID)
+	}
+	if !changed {
+		return false
+	}
+
+	if v.filter.IsEmpty() {
+		v.filter = nil
+	}
+	if v.sort.IsEmpty() {
+		v.sort = nil
+	}
+	if v.group.IsEmpty() {
+		v.group = nil
+	}
+	v.updatedAt = time.Now()
+	v.version++
+
+	return true
+}
+
 // Clone 克隆视图（用于复制）
 func (v *View) Clone(newName string, createdBy string) (*View, error) {
 	return &View{
@@ -44,10 +44,15 @@ const (
 	FilterItemOpIsNotExactly FilterItemOperator = "isNotExactly" // 不完全匹配
 )
 
+// MaxFilterDepth 过滤组最大嵌套层级
+const MaxFilterDepth = 3
+
 // Filter 过滤器值对象
+// 支持 and/or 嵌套：Filters 为叶子条件，Groups 为子过滤组，两者按 Operator 组合
 type Filter struct {
-	Operator FilterOperator `json:"operator"` // and 或 or
-	Filters  []FilterItem   `json:"filters"`  // 过滤项列表
+	Operator FilterOperator `json:"operator"`         // and 或 or
+	Filters  []FilterItem   `json:"filters"`          // 过滤项列表
+	Groups   []*Filter      `json:"groups,omitempty"` // 嵌套过滤组
 }
 
 // FilterItem 过滤项
@@ -84,17 +89,26 @@ func NewFilter(data map[string]interface{}) (*Filter, error) {
 
 // Validate 验证过滤器
 func (f *Filter) Validate() error {
+	return f.validate(1)
+}
+
+// validate 递归验证过滤器及其子过滤组
+func (f *Filter) validate(depth int) error {
 	if f == nil {
 		return nil
 	}
 
+	if depth > MaxFilterDepth {
+		return fmt.Errorf("filter nesting exceeds max depth %d", MaxFilterDepth)
+	}
+
 	// 验证操作符
 	if f.Operator != FilterOperatorAnd && f.Operator != FilterOperatorOr {
 		return fmt.Errorf("invalid filter operator: %s", f.Operator)
 	}
 
 	// 验证过滤项
-	if len(f.Filters) == 0 {
+	if len(f.Filters) == 0 && len(f.Groups) == 0 {
 		return fmt.Errorf("filter must have at least one filter item")
 	}
 
@@ -104,6 +118,15 @@ func (f *Filter) Validate() error {
 		}
 	}
 
+	for i, group := range f.Groups {
+		if group == nil {
+			return fmt.Errorf("filter group at index %d is empty", i)
+		}
+		if err := group.validate(depth + 1); err != nil {
+			return fmt.Errorf("invalid filter group at index %d: %w", i, err)
+		}
+	}
+
 	return nil
 }
 
@@ -176,15 +199,62 @@ func (f *Filter) ToMap() map[string]interface{} {
 		}
 	}
 
-	return map[string]interface{}{
+	result := map[string]interface{}{
 		"operator": f.Operator,
 		"filters":  filters,
 	}
+
+	if len(f.Groups) > 0 {
+		groups := make([]map[string]interface{}, 0, len(f.Groups))
+		for _, group := range f.Groups {
+			if group != nil {
+				groups = append(groups, group.ToMap())
+			}
+		}
+		result["groups"] = groups
+	}
+
+	return result
 }
 
 // IsEmpty 检查过滤器是否为空
 func (f *Filter) IsEmpty() bool {
-	return f == nil || len(f.Filters) == 0
+	if f == nil {
+		return true
+	}
+	if len(f.Filters) > 0 {
+		return false
+	}
+	for _, group := range f.Groups {
+		if !group.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// RemoveFilterItems 递归移除指定字段的过滤项，移除后为空的子过滤组一并去掉
+func (f *Filter) RemoveFilterItems(fieldID string) {
+	if f == nil {
+		return
+	}
+
+	items := make([]FilterItem, 0, len(f.Filters))
+	for _, item := range f.Filters {
+		if item.FieldID != fieldID {
+			items = append(items, item)
+		}
+	}
+	f.Filters = items
+
+	groups := make([]*Filter, 0, len(f.Groups))
+	for _, group := range f.Groups {
+		group.RemoveFilterItems(fieldID)
+		if !group.IsEmpty() {
+			groups = append(groups, group)
+		}
+	}
+	f.Groups = groups
 }
 
 // GetFieldIDs 获取所有涉及的字段ID
@@ -195,13 +265,25 @@ func (f *Filter) GetFieldIDs() []string {
 
 	fieldIDs := make([]string, 0, len(f.Filters))
 	seen := make(map[string]bool)
+	f.collectFieldIDs(&fieldIDs, seen)
+
+	return fieldIDs
+}
+
+// collectFieldIDs 递归收集字段ID（去重）
+func (f *Filter) collectFieldIDs(fieldIDs *[]string, seen map[string]bool) {
+	if f == nil {
+		return
+	}
 
 	for _, item := range f.Filters {
 		if !seen[item.FieldID] {
-			fieldIDs = append(fieldIDs, item.FieldID)
+			*fieldIDs = append(*fieldIDs, item.FieldID)
 			seen[item.FieldID] = true
 		}
 	}
 
-	return fieldIDs
+	for _, group := range f.Groups {
+		group.collectFieldIDs(fieldIDs, seen)
+	}
 }
@@ -8,19 +8,20 @@ import (
 
 // SearchIndex 搜索索引模型
 type SearchIndex struct {
-	ID          string    `gorm:"primaryKey;type:varchar(20)" json:"id"`
+	ID          string    `gorm:"primaryKey;type:varchar(64)" json:"id"`
 	Type        string    `gorm:"type:varchar(50);not null;index" json:"type"`
 	Title       string    `gorm:"type:varchar(500);not null;index" json:"title"`
 	Content     string    `gorm:"type:text;not null" json:"content"`
 	Keywords    string    `gorm:"type:json" json:"keywords"` // JSON格式存储
-	SourceID    string    `gorm:"type:varchar(20);not null;index" json:"source_id"`
-	SourceType  string    `gorm:"type:varchar(50);not null;index" json:"source_type"`
+	SourceID    string    `gorm:"type:varchar(64);not null;uniqueIndex:uq_search_indexes_source,priority:2" json:"source_id"`
+	SourceType  string    `gorm:"type:varchar(50);not null;uniqueIndex:uq_search_indexes_source,priority:1" json:"source_type"`
 	SourceURL   string    `gorm:"type:varchar(1000)" json:"source_url"`
 	Metadata    string    `gorm:"type:json" json:"metadata"` // JSON格式存储
-	UserID      string    `gorm:"type:varchar(20);index" json:"user_id"`
-	SpaceID     string    `gorm:"type:varchar(20);index" json:"space_id"`
-	TableID     string    `gorm:"type:varchar(20);index" json:"table_id"`
-	FieldID     string    `gorm:"type:varchar(20);index" json:"field_id"`
+	UserID      string    `gorm:"type:varchar(64);index" json:"user_id"`
+	SpaceID     string    `gorm:"type:varchar(64);index" json:"space_id"`
+	BaseID      string    `gorm:"type:varchar(64);index" json:"base_id"`
+	TableID     string    `gorm:"type:varchar(64);index" json:"table_id"`
+	FieldID     string    `gorm:"type:varchar(64);index" json:"field_id"`
 	Permissions string    `gorm:"type:json" json:"permissions"` // JSON格式存储
 	Tags        string    `gorm:"type:json" json:"tags"`        // JSON格式存储
 	CreatedTime time.Time `gorm:"autoCreateTime;index" json:"created_time"`
@@ -38,7 +39,7 @@ type SearchSuggestion struct {
 	Query       string    `gorm:"type:varchar(500);not null;uniqueIndex" json:"query"`
 	Count       int64     `gorm:"not null;default:1;index" json:"count"`
 	Type        string    `gorm:"type:varchar(50);index" json:"type"`
-	SourceID    string    `gorm:"type:varchar(20);index" json:"source_id"`
+	SourceID    string    `gorm:"type:varchar(64);index" json:"source_id"`
 	SourceType  string    `gorm:"type:varchar(50);index" json:"source_type"`
 	CreatedTime time.Time `gorm:"autoCreateTime" json:"created_time"`
 	UpdatedTime time.Time `gorm:"autoUpdateTime" json:"updated_time"`
@@ -49,7 +50,7 @@ func (SearchSuggestion) TableName() string {
 	return "search_suggestions"
 }
 
-// SearchStats 搜索统计模型
+// SearchStats 搜索统计模型（按 ID 区分计数器：total、type:<类型>、scope:<范围>）
 type SearchStats struct {
 	ID               string    `gorm:"primaryKey;type:varchar(20)" json:"id"`
 	TotalSearches    int64     `gorm:"not null;default:0" json:"total_searches"`
@@ -59,3 +59,22 @@ type VirtualFieldCache struct {
 func (VirtualFieldCache) TableName() string {
 	return "virtual_field_cache"
 }
+
+// RecordRule 记录校验规则模型
+type RecordRule struct {
+	ID               string    `gorm:"primaryKey;type:varchar(30)" json:"id"`
+	TableID          string    `gorm:"column:table_id;type:varchar(50);not null;index" json:"table_id"`
+	Name             string    `gorm:"column:name;type:varchar(255);not null" json:"name"`
+	Expression       string    `gorm:"column:expression;type:text;not null" json:"expression"`
+	Message          *string   `gorm:"column:message;type:text" json:"message"`
+	Enabled          bool      `gorm:"column:enabled;not null" json:"enabled"`
+	CreatedTime      time.Time `gorm:"autoCreateTime;column:created_time" json:"created_time"`
+	LastModifiedTime time.Time `gorm:"autoUpdateTime;column:last_modified_time" json:"last_modified_time"`
+	CreatedBy        string    `gorm:"column:created_by;type:varchar(50);not null" json:"created_by"`
+	LastModifiedBy   *string   `gorm:"column:last_modified_by;type:varchar(50)" json:"last_modified_by"`
+}
+
+// TableName 指定表名
+func (RecordRule) TableName() string {
+	return "record_rule"
+}
@@ -160,6 +160,18 @@ func (p *PostgresProvider) DropPhysicalTable(ctx context.Context, schemaName, ta
 	return nil
 }
 
+// MovePhysicalTable 把物理表移到另一个Schema，索引、约束与列拥有的序列随表移动
+func (p *PostgresProvider) MovePhysicalTable(ctx context.Context, fromSchema, toSchema, tableName string) error {
+	fullTableName := fmt.Sprintf("%s.%s", p.quoteIdentifier(fromSchema), p.quoteIdentifier(tableName))
+	sql := fmt.Sprintf("ALTER TABLE %s SET SCHEMA %s", fullTableName, p.quoteIdentifier(toSchema))
+
+	if err := p.db.WithContext(ctx).Exec(sql).Error; err != nil {
+		return fmt.Errorf("移动物理表失败: %w", err)
+	}
+
+	return nil
+}
+
 // ==================== 列管理 ====================
 
 // AddColumn 添加列到物理表
@@ -208,6 +220,9 @@ func (p *PostgresProvider) AlterColumn(ctx context.Context, schemaName, tableNam
 			quotedColumn,
 			newDef.Type,
 		)
+		if newDef.Using != "" {
+			sql += " USING " + newDef.Using
+		}
 		if err := p.db.WithContext(ctx).Exec(sql).Error; err != nil {
 			return fmt.Errorf("修改列类型失败: %w", err)
 		}
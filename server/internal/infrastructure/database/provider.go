@@ -30,6 +30,11 @@ type DBProvider interface {
 	// DropPhysicalTable 删除物理表
 	DropPhysicalTable(ctx context.Context, schemaName, tableName string) error
 
+	// MovePhysicalTable 把物理表（连同数据、索引与序列）移到另一个Schema
+	// PostgreSQL: ALTER TABLE ... SET SCHEMA
+	// SQLite: 重命名为目标前缀的表名
+	MovePhysicalTable(ctx context.Context, fromSchema, toSchema, tableName string) error
+
 	// ==================== 列管理（字段作为列）====================
 
 	// AddColumn 添加列到物理表
@@ -87,6 +92,9 @@ type ColumnDefinition struct {
 	// Type 数据库类型（VARCHAR(255), INTEGER, TIMESTAMP, JSONB等）
 	Type string
 
+	// Using 修改列类型时的取值表达式（PostgreSQL USING，例如从已转换好的临时列取值），仅 AlterColumn 使用
+	Using string
+
 	// NotNull 是否NOT NULL约束
 	NotNull bool
 
@@ -104,6 +104,20 @@ func (s *SQLiteProvider) DropPhysicalTable(ctx context.Context, schemaName, tabl
 	return nil
 }
 
+// MovePhysicalTable 把物理表重命名为目标前缀的表名
+func (s *SQLiteProvider) MovePhysicalTable(ctx context.Context, fromSchema, toSchema, tableName string) error {
+	sql := fmt.Sprintf("ALTER TABLE %s RENAME TO %s",
+		s.quoteIdentifier(s.GenerateTableName(fromSchema, tableName)),
+		s.quoteIdentifier(s.GenerateTableName(toSchema, tableName)),
+	)
+
+	if err := s.db.WithContext(ctx).Exec(sql).Error; err != nil {
+		return fmt.Errorf("移动物理表失败: %w", err)
+	}
+
+	return nil
+}
+
 // ==================== 列管理 ====================
 
 // AddColumn 添加列到物理表
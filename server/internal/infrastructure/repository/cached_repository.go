@@ -679,6 +679,16 @@ func (r *CachedRecordRepository) List(ctx context.Context, filter recordRepo.Rec
 	return records, total, nil
 }
 
+// ListPage 键集分页查询（同 List，不使用缓存）
+func (r *CachedRecordRepository) ListPage(ctx context.Context, filter recordRepo.RecordFilter) (*recordRepo.RecordPage, error) {
+	return r.repo.ListPage(ctx, filter)
+}
+
+// GroupBy 分组统计（不使用缓存）
+func (r *CachedRecordRepository) GroupBy(ctx context.Context, query recordRepo.RecordGroupQuery) (*recordRepo.RecordGroupResult, error) {
+	return r.repo.GroupBy(ctx, query)
+}
+
 // 实现其他接口方法（直接委托给底层repo）
 func (r *CachedRecordRepository) FindByID(ctx context.Context, id recordValueobject.RecordID) (*recordEntity.Record, error) {
 	return r.repo.FindByID(ctx, id)
@@ -44,9 +44,19 @@ func (r *FieldRepositoryImpl) Save(ctx context.Context, field *entity.Field) err
 	}
 
 	// 更新现有字段
+	if err := r.db.WithContext(ctx).Model(&models.Field{}).
+		Where("id = ?", dbField.ID).
+		Updates(dbField).Error; err != nil {
+		return err
+	}
+
+	// Updates 会忽略零值，约束标记需要显式写入才能关闭
 	return r.db.WithContext(ctx).Model(&models.Field{}).
 		Where("id = ?", dbField.ID).
-		Updates(dbField).Error
+		Updates(map[string]interface{}{
+			"is_required": dbField.IsRequired,
+			"is_unique":   dbField.IsUnique,
+		}).Error
 }
 
 // FindByID 根据ID查找字段
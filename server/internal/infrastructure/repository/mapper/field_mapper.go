@@ -178,6 +178,9 @@ func ToFieldEntity(dbField *models.Field) (*entity.Field, error) {
 	// 设置约束
 	field.SetRequired(dbField.IsRequired)
 	field.SetUnique(dbField.IsUnique)
+	if dbField.NotNull != nil {
+		field.SetNotNull(*dbField.NotNull)
+	}
 
 	return field, nil
 }
@@ -228,10 +231,11 @@ func ToFieldModel(field *entity.Field) (*models.Field, error) {
 	isRequired := field.IsRequired()
 	isUnique := field.IsUnique()
 	isPrimary := field.IsPrimary()
+	isNotNull := field.NotNull()
 
 	// 初始化布尔指针字段为 false（参考原版所有布尔字段都需要设置）
 	falseVal := false
-	notNull := &falseVal
+	notNull := &isNotNull
 	isLookup := &falseVal
 	isMultipleCellValue := &falseVal
 	hasError := &falseVal
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	pkgDatabase "github.com/easyspace-ai/luckdb/server/pkg/database"
+)
+
+// applyFieldConstraints 写入物理表前处理字段的唯一、非空约束
+//
+// 约束字段的空值（空字符串、空数组、JSON null）统一按 NULL 写入：唯一约束不会因多个空值冲突，
+// 非空约束也不会被空字符串绕过。非空字段为空时直接返回 FIELD_REQUIRED，
+// 不依赖数据库（SQLite 无法为已有列添加 NOT NULL）
+func applyFieldConstraints(fields []*fieldEntity.Field, data map[string]interface{}) error {
+	for _, field := range fields {
+		if !field.NotNull() && !field.IsUnique() {
+			continue
+		}
+		column := field.DBFieldName().String()
+		if !pkgDatabase.IsBlankValue(data[column]) {
+			continue
+		}
+		data[column] = nil
+		if field.NotNull() {
+			return pkgDatabase.NotNullViolationError(field, nil)
+		}
+	}
+	return nil
+}
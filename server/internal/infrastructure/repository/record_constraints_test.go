@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+func TestApplyFieldConstraints(t *testing.T) {
+	email := newFilterTestField(t, "fld_email", "email", fieldValueObject.TypeEmail)
+	require.NoError(t, email.SetUnique(true))
+	owner := newFilterTestField(t, "fld_owner", "owner", fieldValueObject.TypeText)
+	require.NoError(t, owner.SetNotNull(true))
+	tags := newFilterTestField(t, "fld_tags", "tags", fieldValueObject.TypeMultipleSelect)
+	note := newFilterTestField(t, "fld_note", "note", fieldValueObject.TypeText)
+	fields := []*fieldEntity.Field{email, owner, tags, note}
+
+	data := map[string]interface{}{
+		"email": "  ",
+		"owner": "Alice",
+		"tags":  datatypes.JSON("[]"),
+		"note":  "",
+	}
+	require.NoError(t, applyFieldConstraints(fields, data))
+	assert.Nil(t, data["email"], "唯一字段的空字符串按 NULL 写入")
+	assert.Equal(t, "Alice", data["owner"])
+	assert.Equal(t, datatypes.JSON("[]"), data["tags"], "无约束字段保持原值")
+	assert.Equal(t, "", data["note"])
+
+	data["owner"] = ""
+	err := applyFieldConstraints(fields, data)
+	require.Error(t, err)
+	appErr, ok := pkgerrors.IsAppError(err)
+	require.True(t, ok)
+	assert.Equal(t, "FIELD_REQUIRED", appErr.Code)
+	details := appErr.Details.(map[string]interface{})
+	assert.Equal(t, "notNull", details["type"])
+	assert.Equal(t, "fld_owner", details["field_id"])
+}
@@ -0,0 +1,835 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	viewValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+	"github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// filterColumnKind 过滤列的存储语义（决定生成的 SQL 形态）
+type filterColumnKind int
+
+const (
+	filterKindText        filterColumnKind = iota // TEXT/VARCHAR：文本、单选、公式等
+	filterKindNumber                              // NUMERIC/INTEGER：数字、评分、汇总等
+	filterKindDate                                // TIMESTAMP/DATE
+	filterKindBoolean                             // BOOLEAN
+	filterKindStringArray                         // JSONB 字符串数组：多选
+	filterKindObjectArray                         // JSONB 对象数组：用户、关联、附件（带 id/title）
+	filterKindJSON                                // 其他 JSONB：lookup 等
+)
+
+// recordFilterCompiler 记录过滤编译器
+// 将视图过滤树（and/or 嵌套）编译为针对物理表 "baseID"."tableID" 的参数化 SQL（PostgreSQL）
+//
+// 设计考量：
+//   - 所有用户输入均以参数传递，列名来自字段元数据并加引号
+//   - 按字段类型生成 SQL：select 按名称匹配、user/link 按 JSONB 中的 id 匹配、日期按天区间比较
+//   - 空值的过滤项（如 hasAnyOf 传入空数组）视为不生效，与前端视图行为一致
+type recordFilterCompiler struct {
+	fieldsByID   map[string]*fieldEntity.Field
+	fieldsByName map[string]*fieldEntity.Field
+	now          time.Time
+}
+
+// newRecordFilterCompiler 创建过滤编译器
+func newRecordFilterCompiler(fields []*fieldEntity.Field) *recordFilterCompiler {
+	c := &recordFilterCompiler{
+		fieldsByID:   make(map[string]*fieldEntity.Field, len(fields)),
+		fieldsByName: make(map[string]*fieldEntity.Field, len(fields)),
+		now:          time.Now(),
+	}
+	for _, field := range fields {
+		c.fieldsByID[field.ID().String()] = field
+		c.fieldsByName[field.Name().String()] = field
+	}
+	return c
+}
+
+// Compile 编译过滤树，返回 WHERE 片段与参数；过滤树为空时返回空字符串
+func (c *recordFilterCompiler) Compile(filter *viewValueObject.Filter) (string, []interface{}, error) {
+	if filter.IsEmpty() {
+		return "", nil, nil
+	}
+	if err := filter.Validate(); err != nil {
+		return "", nil, errors.ErrInvalidFilter.WithDetails(err.Error())
+	}
+	return c.compileGroup(filter)
+}
+
+// CompileFieldFilters 编译简单字段过滤（字段ID或名称 -> 值），各条件之间为 and
+func (c *recordFilterCompiler) CompileFieldFilters(fieldFilters map[string]interface{}) (string, []interface{}, error) {
+	if len(fieldFilters) == 0 {
+		return "", nil, nil
+	}
+
+	// 固定顺序，保证生成的 SQL 稳定（便于执行计划缓存和日志排查）
+	keys := make([]string, 0, len(fieldFilters))
+	for key := range fieldFilters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	filter := &viewValueObject.Filter{Operator: viewValueObject.FilterOperatorAnd}
+	for _, key := range keys {
+		value := fieldFilters[key]
+		item := viewValueObject.FilterItem{FieldID: key, Operator: viewValueObject.FilterItemOpIs, Value: value}
+		if value == nil {
+			item.Operator = viewValueObject.FilterItemOpIsEmpty
+		}
+		filter.Filters = append(filter.Filters, item)
+	}
+
+	return c.Compile(filter)
+}
+
+// compileGroup 编译过滤组（递归）
+func (c *recordFilterCompiler) compileGroup(filter *viewValueObject.Filter) (string, []interface{}, error) {
+	parts := make([]string, 0, len(filter.Filters)+len(filter.Groups))
+	args := make([]interface{}, 0)
+
+	for _, item := range filter.Filters {
+		sql, itemArgs, err := c.compileItem(item)
+		if err != nil {
+			return "", nil, err
+		}
+		if sql == "" {
+			continue
+		}
+		parts = append(parts, sql)
+		args = append(args, itemArgs...)
+	}
+
+	for _, group := range filter.Groups {
+		if group.IsEmpty() {
+			continue
+		}
+		sql, groupArgs, err := c.compileGroup(group)
+		if err != nil {
+			return "", nil, err
+		}
+		if sql == "" {
+			continue
+		}
+		parts = append(parts, sql)
+		args = append(args, groupArgs...)
+	}
+
+	if len(parts) == 0 {
+		return "", nil, nil
+	}
+
+	joiner := " AND "
+	if filter.Operator == viewValueObject.FilterOperatorOr {
+		joiner = " OR "
+	}
+
+	return "(" + strings.Join(parts, joiner) + ")", args, nil
+}
+
+// compileItem 编译单个过滤项
+func (c *recordFilterCompiler) compileItem(item viewValueObject.FilterItem) (string, []interface{}, error) {
+	field := c.resolveField(item.FieldID)
+	if field == nil {
+		return "", nil, errors.ErrFieldNotFound.WithDetails(map[string]interface{}{
+			"field_id": item.FieldID,
+			"reason":   "filter references unknown field",
+		})
+	}
+
+	dbFieldName := field.DBFieldName().String()
+	if dbFieldName == "" {
+		return "", nil, errors.ErrInvalidFilter.WithDetails(fmt.Sprintf("field %s has no physical column", item.FieldID))
+	}
+	column := quotePGIdentifier(dbFieldName)
+
+	var (
+		sql  string
+		args []interface{}
+		err  error
+	)
+
+	switch kind := filterKindOf(field); kind {
+	case filterKindNumber:
+		sql, args, err = c.compileNumber(column, item)
+	case filterKindDate:
+		sql, args, err = c.compileDate(column, item)
+	case filterKindBoolean:
+		sql, args, err = c.compileBoolean(column, item)
+	case filterKindStringArray:
+		sql, args, err = c.compileStringArray(column, item)
+	case filterKindObjectArray:
+		sql, args, err = c.compileObjectArray(column, field, item)
+	case filterKindJSON:
+		sql, args, err = c.compileJSON(column, item)
+	default:
+		sql, args, err = c.compileText(column, item)
+	}
+
+	if err != nil {
+		return "", nil, errors.ErrInvalidFilter.WithDetails(map[string]interface{}{
+			"field_id": item.FieldID,
+			"operator": item.Operator,
+			"error":    err.Error(),
+		})
+	}
+
+	return sql, args, nil
+}
+
+// resolveField 通过字段ID或名称查找字段
+func (c *recordFilterCompiler) resolveField(key string) *fieldEntity.Field {
+	if field, ok := c.fieldsByID[key]; ok {
+		return field
+	}
+	return c.fieldsByName[key]
+}
+
+// ==================== 文本 / 单选 ====================
+
+func (c *recordFilterCompiler) compileText(column string, item viewValueObject.FilterItem) (string, []interface{}, error) {
+	switch item.Operator {
+	case viewValueObject.FilterItemOpIsEmpty:
+		return fmt.Sprintf("(%s IS NULL OR %s = '')", column, column), nil, nil
+	case viewValueObject.FilterItemOpIsNotEmpty:
+		return fmt.Sprintf("(%s IS NOT NULL AND %s <> '')", column, column), nil, nil
+	case viewValueObject.FilterItemOpHasAnyOf:
+		values := filterStringList(item.Value)
+		if len(values) == 0 {
+			return "", nil, nil
+		}
+		return fmt.Sprintf("%s IN ?", column), []interface{}{values}, nil
+	case viewValueObject.FilterItemOpHasNoneOf:
+		values := filterStringList(item.Value)
+		if len(values) == 0 {
+			return "", nil, nil
+		}
+		return fmt.Sprintf("(%s IS NULL OR %s NOT IN ?)", column, column), []interface{}{values}, nil
+	}
+
+	value, ok := filterScalarString(item.Value)
+	if !ok {
+		return "", nil, fmt.Errorf("expected a text value, got %T", item.Value)
+	}
+
+	switch item.Operator {
+	case viewValueObject.FilterItemOpIs, viewValueObject.FilterItemOpIsExactly:
+		return fmt.Sprintf("%s = ?", column), []interface{}{value}, nil
+	case viewValueObject.FilterItemOpIsNot, viewValueObject.FilterItemOpIsNotExactly:
+		return fmt.Sprintf("(%s IS NULL OR %s <> ?)", column, column), []interface{}{value}, nil
+	case viewValueObject.FilterItemOpContains:
+		return fmt.Sprintf("%s ILIKE ?", column), []interface{}{likePattern(value)}, nil
+	case viewValueObject.FilterItemOpNotContains:
+		return fmt.Sprintf("(%s IS NULL OR %s NOT ILIKE ?)", column, column), []interface{}{likePattern(value)}, nil
+	case viewValueObject.FilterItemOpGreater:
+		return fmt.Sprintf("%s > ?", column), []interface{}{value}, nil
+	case viewValueObject.FilterItemOpGreaterEqual:
+		return fmt.Sprintf("%s >= ?", column), []interface{}{value}, nil
+	case viewValueObject.FilterItemOpLess:
+		return fmt.Sprintf("%s < ?", column), []interface{}{value}, nil
+	case viewValueObject.FilterItemOpLessEqual:
+		return fmt.Sprintf("%s <= ?", column), []interface{}{value}, nil
+	}
+
+	return "", nil, fmt.Errorf("operator %s is not supported for text fields", item.Operator)
+}
+
+// ==================== 数字 ====================
+
+func (c *recordFilterCompiler) compileNumber(column string, item viewValueObject.FilterItem) (string, []interface{}, error) {
+	switch item.Operator {
+	case viewValueObject.FilterItemOpIsEmpty:
+		return fmt.Sprintf("%s IS NULL", column), nil, nil
+	case viewValueObject.FilterItemOpIsNotEmpty:
+		return fmt.Sprintf("%s IS NOT NULL", column), nil, nil
+	case viewValueObject.FilterItemOpHasAnyOf, viewValueObject.FilterItemOpHasNoneOf:
+		values, err := filterNumberList(item.Value)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(values) == 0 {
+			return "", nil, nil
+		}
+		if item.Operator == viewValueObject.FilterItemOpHasNoneOf {
+			return fmt.Sprintf("(%s IS NULL OR %s NOT IN ?)", column, column), []interface{}{values}, nil
+		}
+		return fmt.Sprintf("%s IN ?", column), []interface{}{values}, nil
+	}
+
+	value, err := filterNumber(item.Value)
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch item.Operator {
+	case viewValueObject.FilterItemOpIs, viewValueObject.FilterItemOpIsExactly:
+		return fmt.Sprintf("%s = ?", column), []interface{}{value}, nil
+	case viewValueObject.FilterItemOpIsNot, viewValueObject.FilterItemOpIsNotExactly:
+		return fmt.Sprintf("(%s IS NULL OR %s <> ?)", column, column), []interface{}{value}, nil
+	case viewValueObject.FilterItemOpGreater:
+		return fmt.Sprintf("%s > ?", column), []interface{}{value}, nil
+	case viewValueObject.FilterItemOpGreaterEqual:
+		return fmt.Sprintf("%s >= ?", column), []interface{}{value}, nil
+	case viewValueObject.FilterItemOpLess:
+		return fmt.Sprintf("%s < ?", column), []interface{}{value}, nil
+	case viewValueObject.FilterItemOpLessEqual:
+		return fmt.Sprintf("%s <= ?", column), []interface{}{value}, nil
+	}
+
+	return "", nil, fmt.Errorf("operator %s is not supported for number fields", item.Operator)
+}
+
+// ==================== 日期 ====================
+
+func (c *recordFilterCompiler) compileDate(column string, item viewValueObject.FilterItem) (string, []interface{}, error) {
+	switch item.Operator {
+	case viewValueObject.FilterItemOpIsEmpty:
+		return fmt.Sprintf("%s IS NULL", column), nil, nil
+	case viewValueObject.FilterItemOpIsNotEmpty:
+		return fmt.Sprintf("%s IS NOT NULL", column), nil, nil
+	case viewValueObject.FilterItemOpIsWithin:
+		start, end, err := c.dateWithinRange(item.Value)
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("(%s >= ? AND %s < ?)", column, column), []interface{}{start, end}, nil
+	}
+
+	// 其余操作符以“某一天”为比较单位：[start, end)
+	start, end, err := c.dateDayRange(item.Value)
+	if err != nil {
+		return "", nil, err
+	}
+
+	switch item.Operator {
+	case viewValueObject.FilterItemOpIs, viewValueObject.FilterItemOpIsExactly:
+		return fmt.Sprintf("(%s >= ? AND %s < ?)", column, column), []interface{}{start, end}, nil
+	case viewValueObject.FilterItemOpIsNot, viewValueObject.FilterItemOpIsNotExactly:
+		return fmt.Sprintf("(%s IS NULL OR %s < ? OR %s >= ?)", column, column, column), []interface{}{start, end}, nil
+	case viewValueObject.FilterItemOpIsBefore, viewValueObject.FilterItemOpLess:
+		return fmt.Sprintf("%s < ?", column), []interface{}{start}, nil
+	case viewValueObject.FilterItemOpIsAfter, viewValueObject.FilterItemOpGreater:
+		return fmt.Sprintf("%s >= ?", column), []interface{}{end}, nil
+	case viewValueObject.FilterItemOpGreaterEqual:
+		return fmt.Sprintf("%s >= ?", column), []interface{}{start}, nil
+	case viewValueObject.FilterItemOpLessEqual:
+		return fmt.Sprintf("%s < ?", column), []interface{}{end}, nil
+	}
+
+	return "", nil, fmt.Errorf("operator %s is not supported for date fields", item.Operator)
+}
+
+// dateDayRange 解析日期过滤值为当天区间 [start, end)
+// 支持：日期字符串（2006-01-02 / RFC3339）或 {mode, exactDate, numberOfDays, timeZone}
+func (c *recordFilterCompiler) dateDayRange(value interface{}) (time.Time, time.Time, error) {
+	loc := time.UTC
+	mode := "exactDate"
+	exactDate := ""
+	numberOfDays := 0
+
+	switch v := value.(type) {
+	case string:
+		exactDate = v
+	case time.Time:
+		exactDate = v.Format(time.RFC3339)
+	case map[string]interface{}:
+		if tz, ok := v["timeZone"].(string); ok && tz != "" {
+			l, err := time.LoadLocation(tz)
+			if err != nil {
+				return time.Time{}, time.Time{}, fmt.Errorf("invalid timeZone: %s", tz)
+			}
+			loc = l
+		}
+		if m, ok := v["mode"].(string); ok && m != "" {
+			mode = m
+		}
+		if d, ok := v["exactDate"].(string); ok {
+			exactDate = d
+		}
+		if n, err := filterNumber(v["numberOfDays"]); err == nil {
+			numberOfDays = int(n)
+		}
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("expected a date value, got %T", value)
+	}
+
+	today := startOfDay(c.now.In(loc))
+	var day time.Time
+
+	switch mode {
+	case "today":
+		day = today
+	case "tomorrow":
+		day = today.AddDate(0, 0, 1)
+	case "yesterday":
+		day = today.AddDate(0, 0, -1)
+	case "oneWeekAgo":
+		day = today.AddDate(0, 0, -7)
+	case "oneWeekFromNow":
+		day = today.AddDate(0, 0, 7)
+	case "oneMonthAgo":
+		day = today.AddDate(0, -1, 0)
+	case "oneMonthFromNow":
+		day = today.AddDate(0, 1, 0)
+	case "numberOfDaysAgo":
+		day = today.AddDate(0, 0, -numberOfDays)
+	case "numberOfDaysFromNow":
+		day = today.AddDate(0, 0, numberOfDays)
+	case "exactDate":
+		t, err := parseFilterDate(exactDate, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		day = startOfDay(t.In(loc))
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("unsupported date mode: %s", mode)
+	}
+
+	return day.UTC(), day.AddDate(0, 0, 1).UTC(), nil
+}
+
+// dateWithinRange 解析 isWithin 的区间
+// 支持：{mode: pastWeek|pastMonth|pastYear|nextWeek|nextMonth|nextYear|pastNumberOfDays|nextNumberOfDays}
+// 或显式区间 [start, end] / {start, end}
+func (c *recordFilterCompiler) dateWithinRange(value interface{}) (time.Time, time.Time, error) {
+	loc := time.UTC
+
+	switch v := value.(type) {
+	case []interface{}:
+		if len(v) != 2 {
+			return time.Time{}, time.Time{}, fmt.Errorf("isWithin range must have exactly 2 dates")
+		}
+		return c.explicitDateRange(v[0], v[1], loc)
+	case map[string]interface{}:
+		if tz, ok := v["timeZone"].(string); ok && tz != "" {
+			l, err := time.LoadLocation(tz)
+			if err != nil {
+				return time.Time{}, time.Time{}, fmt.Errorf("invalid timeZone: %s", tz)
+			}
+			loc = l
+		}
+		if _, ok := v["start"]; ok {
+			return c.explicitDateRange(v["start"], v["end"], loc)
+		}
+
+		mode, _ := v["mode"].(string)
+		numberOfDays := 0
+		if n, err := filterNumber(v["numberOfDays"]); err == nil {
+			numberOfDays = int(n)
+		}
+
+		today := startOfDay(c.now.In(loc))
+		tomorrow := today.AddDate(0, 0, 1)
+
+		var start, end time.Time
+		switch mode {
+		case "pastWeek":
+			start, end = today.AddDate(0, 0, -7), tomorrow
+		case "pastMonth":
+			start, end = today.AddDate(0, -1, 0), tomorrow
+		case "pastYear":
+			start, end = today.AddDate(-1, 0, 0), tomorrow
+		case "nextWeek":
+			start, end = today, tomorrow.AddDate(0, 0, 7)
+		case "nextMonth":
+			start, end = today, tomorrow.AddDate(0, 1, 0)
+		case "nextYear":
+			start, end = today, tomorrow.AddDate(1, 0, 0)
+		case "pastNumberOfDays":
+			start, end = today.AddDate(0, 0, -numberOfDays), tomorrow
+		case "nextNumberOfDays":
+			start, end = today, tomorrow.AddDate(0, 0, numberOfDays)
+		default:
+			return time.Time{}, time.Time{}, fmt.Errorf("unsupported isWithin mode: %s", mode)
+		}
+		return start.UTC(), end.UTC(), nil
+	}
+
+	return time.Time{}, time.Time{}, fmt.Errorf("expected an isWithin range, got %T", value)
+}
+
+// explicitDateRange 解析显式日期区间（包含结束当天）
+func (c *recordFilterCompiler) explicitDateRange(startValue, endValue interface{}, loc *time.Location) (time.Time, time.Time, error) {
+	startStr, ok1 := startValue.(string)
+	endStr, ok2 := endValue.(string)
+	if !ok1 || !ok2 {
+		return time.Time{}, time.Time{}, fmt.Errorf("isWithin range bounds must be date strings")
+	}
+	start, err := parseFilterDate(startStr, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end, err := parseFilterDate(endStr, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return startOfDay(start.In(loc)).UTC(), startOfDay(end.In(loc)).AddDate(0, 0, 1).UTC(), nil
+}
+
+// ==================== 布尔 ====================
+
+func (c *recordFilterCompiler) compileBoolean(column string, item viewValueObject.FilterItem) (string, []interface{}, error) {
+	switch item.Operator {
+	case viewValueObject.FilterItemOpIsEmpty:
+		return fmt.Sprintf("(%s IS NULL OR %s = FALSE)", column, column), nil, nil
+	case viewValueObject.FilterItemOpIsNotEmpty:
+		return fmt.Sprintf("%s = TRUE", column), nil, nil
+	case viewValueObject.FilterItemOpIs, viewValueObject.FilterItemOpIsNot:
+		value, err := filterBool(item.Value)
+		if err != nil {
+			return "", nil, err
+		}
+		if item.Operator == viewValueObject.FilterItemOpIsNot {
+			value = !value
+		}
+		return fmt.Sprintf("COALESCE(%s, FALSE) = ?", column), []interface{}{value}, nil
+	}
+
+	return "", nil, fmt.Errorf("operator %s is not supported for checkbox fields", item.Operator)
+}
+
+// ==================== JSONB 字符串数组（多选）====================
+
+func (c *recordFilterCompiler) compileStringArray(column string, item viewValueObject.FilterItem) (string, []interface{}, error) {
+	arr := jsonbArrayExpr(column)
+
+	switch item.Operator {
+	case viewValueObject.FilterItemOpIsEmpty:
+		return fmt.Sprintf("jsonb_array_length(%s) = 0", arr), nil, nil
+	case viewValueObject.FilterItemOpIsNotEmpty:
+		return fmt.Sprintf("jsonb_array_length(%s) > 0", arr), nil, nil
+	case viewValueObject.FilterItemOpContains, viewValueObject.FilterItemOpNotContains:
+		value, ok := filterScalarString(item.Value)
+		if !ok {
+			return "", nil, fmt.Errorf("expected a text value, got %T", item.Value)
+		}
+		sql := fmt.Sprintf("EXISTS (SELECT 1 FROM jsonb_array_elements_text(%s) AS e(v) WHERE e.v ILIKE ?)", arr)
+		if item.Operator == viewValueObject.FilterItemOpNotContains {
+			sql = "NOT " + sql
+		}
+		return sql, []interface{}{likePattern(value)}, nil
+	}
+
+	values := filterStringList(item.Value)
+	if len(values) == 0 {
+		return "", nil, nil
+	}
+
+	textArray, textArgs := textArrayExpr(values)
+
+	switch item.Operator {
+	case viewValueObject.FilterItemOpIs, viewValueObject.FilterItemOpHasAnyOf:
+		return fmt.Sprintf("jsonb_exists_any(%s, %s)", arr, textArray), textArgs, nil
+	case viewValueObject.FilterItemOpIsNot, viewValueObject.FilterItemOpHasNoneOf:
+		return fmt.Sprintf("NOT jsonb_exists_any(%s, %s)", arr, textArray), textArgs, nil
+	case viewValueObject.FilterItemOpHasAllOf:
+		return fmt.Sprintf("jsonb_exists_all(%s, %s)", arr, textArray), textArgs, nil
+	case viewValueObject.FilterItemOpIsExactly, viewValueObject.FilterItemOpIsNotExactly:
+		raw, _ := json.Marshal(values)
+		sql := fmt.Sprintf("(%s @> ?::jsonb AND %s <@ ?::jsonb)", arr, arr)
+		if item.Operator == viewValueObject.FilterItemOpIsNotExactly {
+			sql = "NOT " + sql
+		}
+		return sql, []interface{}{string(raw), string(raw)}, nil
+	}
+
+	return "", nil, fmt.Errorf("operator %s is not supported for multiple select fields", item.Operator)
+}
+
+// ==================== JSONB 对象数组（用户 / 关联 / 附件）====================
+
+func (c *recordFilterCompiler) compileObjectArray(column string, field *fieldEntity.Field, item viewValueObject.FilterItem) (string, []interface{}, error) {
+	arr := jsonbArrayExpr(column)
+
+	// 用户、关联以 title 展示；附件以 name 展示
+	titleExpr := "COALESCE(e.v->>'title', e.v->>'name', e.v->>'email')"
+
+	switch item.Operator {
+	case viewValueObject.FilterItemOpIsEmpty:
+		return fmt.Sprintf("jsonb_array_length(%s) = 0", arr), nil, nil
+	case viewValueObject.FilterItemOpIsNotEmpty:
+		return fmt.Sprintf("jsonb_array_length(%s) > 0", arr), nil, nil
+	case viewValueObject.FilterItemOpContains, viewValueObject.FilterItemOpNotContains:
+		value, ok := filterScalarString(item.Value)
+		if !ok {
+			return "", nil, fmt.Errorf("expected a text value, got %T", item.Value)
+		}
+		sql := fmt.Sprintf("EXISTS (SELECT 1 FROM jsonb_array_elements(%s) AS e(v) WHERE %s ILIKE ?)", arr, titleExpr)
+		if item.Operator == viewValueObject.FilterItemOpNotContains {
+			sql = "NOT " + sql
+		}
+		return sql, []interface{}{likePattern(value)}, nil
+	}
+
+	ids := filterIDList(item.Value)
+	if len(ids) == 0 {
+		return "", nil, nil
+	}
+
+	anyOf := fmt.Sprintf("EXISTS (SELECT 1 FROM jsonb_array_elements(%s) AS e(v) WHERE e.v->>'id' IN ?)", arr)
+
+	switch item.Operator {
+	case viewValueObject.FilterItemOpIs, viewValueObject.FilterItemOpHasAnyOf:
+		return anyOf, []interface{}{ids}, nil
+	case viewValueObject.FilterItemOpIsNot, viewValueObject.FilterItemOpHasNoneOf:
+		return "NOT " + anyOf, []interface{}{ids}, nil
+	case viewValueObject.FilterItemOpHasAllOf:
+		return fmt.Sprintf("%s @> ?::jsonb", arr), []interface{}{idContainmentJSON(ids)}, nil
+	case viewValueObject.FilterItemOpIsExactly, viewValueObject.FilterItemOpIsNotExactly:
+		sql := fmt.Sprintf("(%s @> ?::jsonb AND jsonb_array_length(%s) = ?)", arr, arr)
+		if item.Operator == viewValueObject.FilterItemOpIsNotExactly {
+			sql = "NOT " + sql
+		}
+		return sql, []interface{}{idContainmentJSON(ids), len(ids)}, nil
+	}
+
+	return "", nil, fmt.Errorf("operator %s is not supported for %s fields", item.Operator, field.Type().String())
+}
+
+// ==================== 其他 JSONB（lookup 等）====================
+
+func (c *recordFilterCompiler) compileJSON(column string, item viewValueObject.FilterItem) (string, []interface{}, error) {
+	arr := jsonbArrayExpr(column)
+
+	switch item.Operator {
+	case viewValueObject.FilterItemOpIsEmpty:
+		return fmt.Sprintf("jsonb_array_length(%s) = 0", arr), nil, nil
+	case viewValueObject.FilterItemOpIsNotEmpty:
+		return fmt.Sprintf("jsonb_array_length(%s) > 0", arr), nil, nil
+	case viewValueObject.FilterItemOpContains, viewValueObject.FilterItemOpNotContains:
+		value, ok := filterScalarString(item.Value)
+		if !ok {
+			return "", nil, fmt.Errorf("expected a text value, got %T", item.Value)
+		}
+		if item.Operator == viewValueObject.FilterItemOpNotContains {
+			return fmt.Sprintf("(%s IS NULL OR %s::text NOT ILIKE ?)", column, column), []interface{}{likePattern(value)}, nil
+		}
+		return fmt.Sprintf("%s::text ILIKE ?", column), []interface{}{likePattern(value)}, nil
+	}
+
+	return "", nil, fmt.Errorf("operator %s is not supported for lookup fields", item.Operator)
+}
+
+// ==================== 辅助函数 ====================
+
+// filterKindOf 根据字段类型确定过滤语义
+func filterKindOf(field *fieldEntity.Field) filterColumnKind {
+	switch field.Type().String() {
+	case fieldValueObject.TypeNumber, fieldValueObject.TypeRating, fieldValueObject.TypePercent,
+		fieldValueObject.TypeCurrency, fieldValueObject.TypeDuration, fieldValueObject.TypeRollup,
+		fieldValueObject.TypeCount, fieldValueObject.TypeAutoNumber:
+		return filterKindNumber
+	case fieldValueObject.TypeDate, fieldValueObject.TypeDateTime,
+		fieldValueObject.TypeCreatedTime, fieldValueObject.TypeModifiedTime:
+		return filterKindDate
+	case fieldValueObject.TypeCheckbox, fieldValueObject.TypeBoolean:
+		return filterKindBoolean
+	case fieldValueObject.TypeMultipleSelect:
+		return filterKindStringArray
+	case fieldValueObject.TypeUser, fieldValueObject.TypeLink, fieldValueObject.TypeAttachment:
+		return filterKindObjectArray
+	case fieldValueObject.TypeLookup:
+		return filterKindJSON
+	}
+
+	// 兜底：根据物理列类型判断
+	switch strings.ToUpper(field.DBFieldType()) {
+	case "JSONB", "JSON":
+		return filterKindJSON
+	case "NUMERIC", "INTEGER", "BIGINT", "SERIAL":
+		return filterKindNumber
+	case "TIMESTAMP", "DATE":
+		return filterKindDate
+	case "BOOLEAN":
+		return filterKindBoolean
+	}
+	return filterKindText
+}
+
+// jsonbArrayExpr 将 JSONB 列规整为数组表达式（单个对象包装为数组，NULL 视为空数组）
+func jsonbArrayExpr(column string) string {
+	return fmt.Sprintf(
+		"(CASE WHEN %[1]s IS NULL OR jsonb_typeof(%[1]s) = 'null' THEN '[]'::jsonb WHEN jsonb_typeof(%[1]s) = 'array' THEN %[1]s ELSE jsonb_build_array(%[1]s) END)",
+		column,
+	)
+}
+
+// quotePGIdentifier 引用 PostgreSQL 标识符
+func quotePGIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// textArrayExpr 生成 ARRAY[?, ?]::text[] 表达式
+// 逐个元素占位：gorm 会把切片参数展开为 ('a','b')，不能直接放进 ARRAY[...]
+func textArrayExpr(values []string) (string, []interface{}) {
+	placeholders := make([]string, len(values))
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		placeholders[i] = "?"
+		args[i] = v
+	}
+	return "ARRAY[" + strings.Join(placeholders, ", ") + "]::text[]", args
+}
+
+// likePattern 生成 ILIKE 模糊匹配模式（转义通配符）
+func likePattern(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + replacer.Replace(value) + "%"
+}
+
+// idContainmentJSON 生成 [{"id": "..."}] 形式的 JSONB 包含条件
+func idContainmentJSON(ids []string) string {
+	items := make([]map[string]string, len(ids))
+	for i, id := range ids {
+		items[i] = map[string]string{"id": id}
+	}
+	raw, _ := json.Marshal(items)
+	return string(raw)
+}
+
+// filterScalarString 将标量值转换为字符串
+func filterScalarString(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case []interface{}:
+		if len(v) == 1 {
+			return filterScalarString(v[0])
+		}
+	}
+	return "", false
+}
+
+// filterStringList 将值转换为字符串列表（单值视为单元素列表）
+func filterStringList(value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []string:
+		return v
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := filterScalarString(item); ok && s != "" {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	if s, ok := filterScalarString(value); ok && s != "" {
+		return []string{s}
+	}
+	return nil
+}
+
+// filterIDList 提取ID列表：支持 "id"、["id"]、{id: ...}、[{id: ...}]
+func filterIDList(value interface{}) []string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if id, ok := v["id"].(string); ok && id != "" {
+			return []string{id}
+		}
+		return nil
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			result = append(result, filterIDList(item)...)
+		}
+		return result
+	}
+	return filterStringList(value)
+}
+
+// filterNumber 将值转换为数字
+func filterNumber(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case json.Number:
+		return v.Float64()
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number: %s", v)
+		}
+		return n, nil
+	}
+	return 0, fmt.Errorf("expected a number, got %T", value)
+}
+
+// filterNumberList 将值转换为数字列表
+func filterNumberList(value interface{}) ([]float64, error) {
+	items, ok := value.([]interface{})
+	if !ok {
+		n, err := filterNumber(value)
+		if err != nil {
+			return nil, err
+		}
+		return []float64{n}, nil
+	}
+
+	result := make([]float64, 0, len(items))
+	for _, item := range items {
+		n, err := filterNumber(item)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, n)
+	}
+	return result, nil
+}
+
+// filterBool 将值转换为布尔值
+func filterBool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, fmt.Errorf("invalid boolean: %s", v)
+		}
+		return b, nil
+	case float64:
+		return v != 0, nil
+	}
+	return false, fmt.Errorf("expected a boolean, got %T", value)
+}
+
+// parseFilterDate 解析过滤用日期字符串
+func parseFilterDate(value string, loc *time.Location) (time.Time, error) {
+	layouts := []string{time.RFC3339Nano, time.RFC3339, "2006-01-02 15:04:05", "2006-01-02", "2006/01/02"}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date: %s", value)
+}
+
+// startOfDay 获取当天零点
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	viewValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+)
+
+func newFilterTestField(t *testing.T, id, dbName, fieldType string) *fieldEntity.Field {
+	t.Helper()
+
+	name, err := fieldValueObject.NewFieldName(id)
+	require.NoError(t, err)
+	ft, err := fieldValueObject.NewFieldType(fieldType)
+	require.NoError(t, err)
+	dbFieldName, err := fieldValueObject.NewDBFieldNameFromString(dbName)
+	require.NoError(t, err)
+
+	created, err := fieldEntity.NewField("tbl_test", name, ft, "usr_test")
+	require.NoError(t, err)
+
+	return fieldEntity.ReconstructField(
+		fieldValueObject.NewFieldID(id),
+		"tbl_test",
+		name,
+		ft,
+		dbFieldName,
+		created.DBFieldType(),
+		fieldValueObject.NewFieldOptions(),
+		0,
+		1,
+		"usr_test",
+		time.Now(),
+		time.Now(),
+	)
+}
+
+func newTestFilterCompiler(t *testing.T) *recordFilterCompiler {
+	fields := []*fieldEntity.Field{
+		newFilterTestField(t, "fld_name", "name", fieldValueObject.TypeText),
+		newFilterTestField(t, "fld_amount", "amount", fieldValueObject.TypeNumber),
+		newFilterTestField(t, "fld_due", "due", fieldValueObject.TypeDate),
+		newFilterTestField(t, "fld_tags", "tags", fieldValueObject.TypeMultipleSelect),
+		newFilterTestField(t, "fld_owner", "owner", fieldValueObject.TypeUser),
+		newFilterTestField(t, "fld_done", "done", fieldValueObject.TypeCheckbox),
+	}
+	c := newRecordFilterCompiler(fields)
+	c.now = time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	return c
+}
+
+func TestRecordFilterCompiler_NestedGroups(t *testing.T) {
+	c := newTestFilterCompiler(t)
+
+	filter := &viewValueObject.Filter{
+		Operator: viewValueObject.FilterOperatorAnd,
+		Filters: []viewValueObject.FilterItem{
+			{FieldID: "fld_name", Operator: viewValueObject.FilterItemOpContains, Value: "50%_off"},
+		},
+		Groups: []*viewValueObject.Filter{
+			{
+				Operator: viewValueObject.FilterOperatorOr,
+				Filters: []viewValueObject.FilterItem{
+					{FieldID: "fld_amount", Operator: viewValueObject.FilterItemOpGreater, Value: "10"},
+					{FieldID: "fld_done", Operator: viewValueObject.FilterItemOpIs, Value: true},
+				},
+			},
+		},
+	}
+
+	sql, args, err := c.Compile(filter)
+	require.NoError(t, err)
+	assert.Equal(t, `("name" ILIKE ? AND ("amount" > ? OR COALESCE("done", FALSE) = ?))`, sql)
+	assert.Equal(t, []interface{}{`%50\%\_off%`, float64(10), true}, args)
+}
+
+func TestRecordFilterCompiler_FieldTypes(t *testing.T) {
+	c := newTestFilterCompiler(t)
+
+	tests := []struct {
+		name     string
+		item     viewValueObject.FilterItem
+		contains string
+		args     int
+	}{
+		{
+			name:     "multiple select hasAnyOf",
+			item:     viewValueObject.FilterItem{FieldID: "fld_tags", Operator: viewValueObject.FilterItemOpHasAnyOf, Value: []interface{}{"a", "b"}},
+			contains: "ARRAY[?, ?]::text[])",
+			args:     2,
+		},
+		{
+			name:     "user hasAnyOf by id",
+			item:     viewValueObject.FilterItem{FieldID: "fld_owner", Operator: viewValueObject.FilterItemOpHasAnyOf, Value: []interface{}{map[string]interface{}{"id": "usr_1"}}},
+			contains: "e.v->>'id' IN ?",
+			args:     1,
+		},
+		{
+			name:     "date is exact day",
+			item:     viewValueObject.FilterItem{FieldID: "fld_due", Operator: viewValueObject.FilterItemOpIs, Value: "2024-03-01"},
+			contains: `("due" >= ? AND "due" < ?)`,
+			args:     2,
+		},
+		{
+			name:     "date isWithin past week",
+			item:     viewValueObject.FilterItem{FieldID: "fld_due", Operator: viewValueObject.FilterItemOpIsWithin, Value: map[string]interface{}{"mode": "pastWeek"}},
+			contains: `("due" >= ? AND "due" < ?)`,
+			args:     2,
+		},
+		{
+			name:     "text isEmpty",
+			item:     viewValueObject.FilterItem{FieldID: "fld_name", Operator: viewValueObject.FilterItemOpIsEmpty},
+			contains: `("name" IS NULL OR "name" = '')`,
+			args:     0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := c.compileItem(tt.item)
+			require.NoError(t, err)
+			assert.Contains(t, sql, tt.contains)
+			assert.Len(t, args, tt.args)
+		})
+	}
+}
+
+func TestRecordFilterCompiler_DateRange(t *testing.T) {
+	c := newTestFilterCompiler(t)
+
+	start, end, err := c.dateWithinRange(map[string]interface{}{"mode": "pastWeek"})
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC), end)
+}
+
+func TestRecordFilterCompiler_Errors(t *testing.T) {
+	c := newTestFilterCompiler(t)
+
+	_, _, err := c.compileItem(viewValueObject.FilterItem{FieldID: "fld_missing", Operator: viewValueObject.FilterItemOpIs, Value: "x"})
+	assert.Error(t, err)
+
+	_, _, err = c.compileItem(viewValueObject.FilterItem{FieldID: "fld_amount", Operator: viewValueObject.FilterItemOpIs, Value: "abc"})
+	assert.Error(t, err)
+
+	_, _, err = c.compileItem(viewValueObject.FilterItem{FieldID: "fld_done", Operator: viewValueObject.FilterItemOpContains, Value: "x"})
+	assert.Error(t, err)
+}
+
+func TestRecordFilterCompiler_EmptyValuesAreIgnored(t *testing.T) {
+	c := newTestFilterCompiler(t)
+
+	sql, args, err := c.Compile(&viewValueObject.Filter{
+		Operator: viewValueObject.FilterOperatorAnd,
+		Filters: []viewValueObject.FilterItem{
+			{FieldID: "fld_tags", Operator: viewValueObject.FilterItemOpHasAnyOf, Value: []interface{}{}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, sql)
+	assert.Empty(t, args)
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	viewValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+	"github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// maxRecordGroupLevels 分组最多3层（与视图分组一致）
+const maxRecordGroupLevels = 3
+
+// recordGroupKey 编译后的分组键
+type recordGroupKey struct {
+	FieldID string
+	Expr    string        // GROUP BY 表达式（空字符串、JSON null 与空数组归入空值分组）
+	Order   recordSortKey // 分组排序（与记录排序语义一致，空值分组在后）
+}
+
+// recordAggregate 编译后的聚合表达式
+type recordAggregate struct {
+	FieldID string
+	Func    valueobject.AggregateFunc
+	Expr    string
+	Args    []interface{}
+}
+
+// CompileGroupKeys 编译分组键；分组字段只能来自字段元数据，未知字段返回 INVALID_GROUP
+func (c *recordFilterCompiler) CompileGroupKeys(items []viewValueObject.GroupItem) ([]recordGroupKey, error) {
+	if len(items) > maxRecordGroupLevels {
+		return nil, errors.ErrInvalidGroup.WithDetails(fmt.Sprintf("group can have at most %d levels, got %d", maxRecordGroupLevels, len(items)))
+	}
+
+	keys := make([]recordGroupKey, 0, len(items))
+	for _, item := range items {
+		if err := item.Validate(); err != nil {
+			return nil, errors.ErrInvalidGroup.WithDetails(err.Error())
+		}
+
+		field, column, err := c.groupField(item.FieldID, "group references unknown field")
+		if err != nil {
+			return nil, err
+		}
+
+		order, err := c.sortKey(item.FieldID)
+		if err != nil {
+			return nil, err
+		}
+		order.Desc = item.Order == viewValueObject.SortOrderDesc
+
+		keys = append(keys, recordGroupKey{
+			FieldID: field.ID().String(),
+			Expr:    groupValueExpr(field, column),
+			Order:   order,
+		})
+	}
+
+	return keys, nil
+}
+
+// CompileAggregates 编译聚合表达式
+//
+// 可用的聚合函数由字段类别决定（见 AggregateFuncsFor）：
+//   - 通用：空值/非空/去重数量及其占比，空值判断与过滤的 isEmpty 一致，数组按整个单元格去重
+//   - 数字：求和、平均、最值、中位数
+//   - 日期：最早/最晚日期
+//   - 复选框：勾选/未勾选数量及其占比
+//
+// 占比以 0-100 的百分数返回（保留两位小数），没有记录时为 NULL
+func (c *recordFilterCompiler) CompileAggregates(items []recordRepo.AggregateItem) ([]recordAggregate, error) {
+	aggregates := make([]recordAggregate, 0, len(items))
+
+	for _, item := range items {
+		if !item.Func.IsValid() {
+			return nil, errors.ErrInvalidGroup.WithDetails(map[string]interface{}{
+				"field_id": item.FieldID,
+				"func":     item.Func,
+				"reason":   "unsupported aggregate function",
+			})
+		}
+
+		field, column, err := c.groupField(item.FieldID, "aggregate references unknown field")
+		if err != nil {
+			return nil, err
+		}
+		if !item.Func.SupportsFieldType(field.Type()) {
+			return nil, errors.ErrInvalidGroup.WithDetails(map[string]interface{}{
+				"field_id":   item.FieldID,
+				"func":       item.Func,
+				"field_type": field.Type().String(),
+				"allowed":    valueobject.AggregateFuncsFor(field.Type()),
+				"reason":     "aggregate function is not supported for this field type",
+			})
+		}
+
+		agg := recordAggregate{FieldID: field.ID().String(), Func: item.Func}
+
+		switch item.Func {
+		case valueobject.AggregateSum:
+			agg.Expr = fmt.Sprintf("SUM(%s)", column)
+		case valueobject.AggregateAvg:
+			agg.Expr = fmt.Sprintf("AVG(%s)", column)
+		case valueobject.AggregateMin, valueobject.AggregateEarliestDate:
+			agg.Expr = fmt.Sprintf("MIN(%s)", column)
+		case valueobject.AggregateMax, valueobject.AggregateLatestDate:
+			agg.Expr = fmt.Sprintf("MAX(%s)", column)
+		case valueobject.AggregateMedian:
+			agg.Expr = fmt.Sprintf("percentile_cont(0.5) WITHIN GROUP (ORDER BY %s)", column)
+		case valueobject.AggregateUniqueCount:
+			agg.Expr = fmt.Sprintf("COUNT(DISTINCT %s)", groupValueExpr(field, column))
+		case valueobject.AggregatePercentUnique:
+			agg.Expr = percentExpr(fmt.Sprintf("COUNT(DISTINCT %s)", groupValueExpr(field, column)))
+		case valueobject.AggregateCheckedCount:
+			agg.Expr = fmt.Sprintf("COUNT(*) FILTER (WHERE %s = TRUE)", column)
+		case valueobject.AggregateUncheckedCount:
+			agg.Expr = fmt.Sprintf("COUNT(*) FILTER (WHERE %s IS NOT TRUE)", column)
+		case valueobject.AggregatePercentChecked:
+			agg.Expr = percentExpr(fmt.Sprintf("COUNT(*) FILTER (WHERE %s = TRUE)", column))
+		case valueobject.AggregatePercentUnchecked:
+			agg.Expr = percentExpr(fmt.Sprintf("COUNT(*) FILTER (WHERE %s IS NOT TRUE)", column))
+		default:
+			// 空值类统计复用过滤的 isEmpty 条件
+			cond, args, err := c.compileItem(viewValueObject.FilterItem{
+				FieldID:  field.ID().String(),
+				Operator: viewValueObject.FilterItemOpIsEmpty,
+			})
+			if err != nil {
+				return nil, err
+			}
+			emptyCount := fmt.Sprintf("COUNT(*) FILTER (WHERE %s)", cond)
+			filledCount := fmt.Sprintf("(COUNT(*) - %s)", emptyCount)
+
+			switch item.Func {
+			case valueobject.AggregateEmptyCount:
+				agg.Expr = emptyCount
+			case valueobject.AggregateFilledCount:
+				agg.Expr = filledCount
+			case valueobject.AggregatePercentEmpty:
+				agg.Expr = percentExpr(emptyCount)
+			case valueobject.AggregatePercentFilled:
+				agg.Expr = percentExpr(filledCount)
+			}
+			agg.Args = args
+		}
+
+		aggregates = append(aggregates, agg)
+	}
+
+	return aggregates, nil
+}
+
+// percentExpr 计数占过滤后记录数的百分比（0-100，两位小数）
+func percentExpr(countExpr string) string {
+	return fmt.Sprintf("ROUND(100.0 * %s / NULLIF(COUNT(*), 0), 2)", countExpr)
+}
+
+// groupField 解析分组或聚合引用的字段及其物理列
+func (c *recordFilterCompiler) groupField(fieldKey, reason string) (*fieldEntity.Field, string, error) {
+	field := c.resolveField(fieldKey)
+	if field == nil || field.DBFieldName().String() == "" {
+		return nil, "", errors.ErrInvalidGroup.WithDetails(map[string]interface{}{
+			"field_id": fieldKey,
+			"reason":   reason,
+		})
+	}
+	return field, quotePGIdentifier(field.DBFieldName().String()), nil
+}
+
+// groupValueExpr 分组取值表达式：空字符串、JSON null 与空数组视为空值
+func groupValueExpr(field *fieldEntity.Field, column string) string {
+	switch filterKindOf(field) {
+	case filterKindNumber, filterKindDate:
+		return column
+	case filterKindBoolean:
+		return fmt.Sprintf("COALESCE(%s, FALSE)", column)
+	case filterKindStringArray, filterKindObjectArray, filterKindJSON:
+		return fmt.Sprintf("NULLIF(NULLIF(%s, 'null'::jsonb), '[]'::jsonb)", column)
+	}
+	return fmt.Sprintf("NULLIF(%s::text, '')", column)
+}
+
+// groupOrderExpr 分组排序表达式：排序键由分组列决定，组内取值唯一，用聚合取出
+func groupOrderExpr(key recordSortKey) string {
+	fn := "MIN"
+	if key.CastType == "boolean" {
+		fn = "BOOL_OR"
+	}
+	dir := "ASC"
+	if key.Desc {
+		dir = "DESC"
+	}
+	return fmt.Sprintf("%s(%s) %s NULLS LAST", fn, key.Expr, dir)
+}
+
+// decodeJSONText 解析 to_jsonb(...)::text 查询结果
+func decodeJSONText(raw interface{}) interface{} {
+	var data []byte
+	switch v := raw.(type) {
+	case nil:
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return v
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return string(data)
+	}
+	return value
+}
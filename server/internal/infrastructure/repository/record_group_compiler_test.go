@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	viewValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+)
+
+func TestRecordFilterCompiler_CompileGroupKeys(t *testing.T) {
+	c := newTestSortCompiler(t)
+
+	keys, err := c.CompileGroupKeys([]viewValueObject.GroupItem{
+		{FieldID: "fld_status", Order: viewValueObject.SortOrderAsc},
+		{FieldID: "fld_owner", Order: viewValueObject.SortOrderDesc},
+		{FieldID: "fld_amount", Order: viewValueObject.SortOrderAsc},
+	})
+	require.NoError(t, err)
+	require.Len(t, keys, 3)
+
+	assert.Equal(t, "fld_status", keys[0].FieldID)
+	assert.Equal(t, `NULLIF("status"::text, '')`, keys[0].Expr)
+	assert.Contains(t, groupOrderExpr(keys[0].Order), "MIN(COALESCE(array_position(")
+	assert.Equal(t, `NULLIF(NULLIF("owner", 'null'::jsonb), '[]'::jsonb)`, keys[1].Expr)
+	assert.Contains(t, groupOrderExpr(keys[1].Order), "DESC NULLS LAST")
+	assert.Equal(t, `"amount"`, keys[2].Expr)
+}
+
+func TestRecordFilterCompiler_CompileGroupKeysErrors(t *testing.T) {
+	c := newTestSortCompiler(t)
+
+	_, err := c.CompileGroupKeys([]viewValueObject.GroupItem{
+		{FieldID: "fld_missing", Order: viewValueObject.SortOrderAsc},
+	})
+	assert.Error(t, err)
+
+	_, err = c.CompileGroupKeys([]viewValueObject.GroupItem{
+		{FieldID: "fld_name", Order: viewValueObject.SortOrderAsc},
+		{FieldID: "fld_amount", Order: viewValueObject.SortOrderAsc},
+		{FieldID: "fld_status", Order: viewValueObject.SortOrderAsc},
+		{FieldID: "fld_owner", Order: viewValueObject.SortOrderAsc},
+	})
+	assert.Error(t, err)
+}
+
+func TestRecordFilterCompiler_CompileAggregates(t *testing.T) {
+	c := newTestSortCompiler(t)
+
+	aggregates, err := c.CompileAggregates([]recordRepo.AggregateItem{
+		{FieldID: "fld_amount", Func: valueobject.AggregateSum},
+		{FieldID: "fld_amount", Func: valueobject.AggregateAvg},
+		{FieldID: "fld_amount", Func: valueobject.AggregateMedian},
+		{FieldID: "fld_name", Func: valueobject.AggregateEmptyCount},
+		{FieldID: "fld_name", Func: valueobject.AggregatePercentFilled},
+		{FieldID: "fld_owner", Func: valueobject.AggregateUniqueCount},
+	})
+	require.NoError(t, err)
+
+	exprs := make([]string, len(aggregates))
+	for i, agg := range aggregates {
+		exprs[i] = agg.Expr
+	}
+	assert.Equal(t, []string{
+		`SUM("amount")`,
+		`AVG("amount")`,
+		`percentile_cont(0.5) WITHIN GROUP (ORDER BY "amount")`,
+		`COUNT(*) FILTER (WHERE ("name" IS NULL OR "name" = ''))`,
+		`ROUND(100.0 * (COUNT(*) - COUNT(*) FILTER (WHERE ("name" IS NULL OR "name" = ''))) / NULLIF(COUNT(*), 0), 2)`,
+		`COUNT(DISTINCT NULLIF(NULLIF("owner", 'null'::jsonb), '[]'::jsonb))`,
+	}, exprs)
+
+	_, err = c.CompileAggregates([]recordRepo.AggregateItem{{FieldID: "fld_name", Func: valueobject.AggregateSum}})
+	assert.Error(t, err)
+
+	_, err = c.CompileAggregates([]recordRepo.AggregateItem{{FieldID: "fld_amount", Func: "mode"}})
+	assert.Error(t, err)
+
+	_, err = c.CompileAggregates([]recordRepo.AggregateItem{{FieldID: "fld_status", Func: valueobject.AggregateEarliestDate}})
+	assert.Error(t, err)
+}
+
+func TestGroupAggregates(t *testing.T) {
+	aggregates := []recordAggregate{
+		{FieldID: "fld_amount", Func: valueobject.AggregateSum},
+		{FieldID: "fld_amount", Func: valueobject.AggregateMin},
+	}
+	row := map[string]interface{}{"__count": int64(3), "__agg_0": "12.5", "__agg_1": nil}
+
+	assert.Equal(t, int64(3), groupCount(row))
+	assert.Equal(t, map[string]map[valueobject.AggregateFunc]interface{}{
+		"fld_amount": {valueobject.AggregateSum: 12.5, valueobject.AggregateMin: nil},
+	}, groupAggregates(row, aggregates))
+	assert.Equal(t, "Todo", decodeJSONText(`"Todo"`))
+}
@@ -9,10 +9,19 @@ import (
 	"github.com/easyspace-ai/luckdb/server/internal/domain/record/entity"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	viewValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/repository/mapper"
 )
 
+// legacyRecordSortColumns 记录元数据表允许排序的列（排序键 -> 列名）
+var legacyRecordSortColumns = map[string]string{
+	"__created_time":       "created_time",
+	"__last_modified_time": "last_modified_time",
+	"created_at":           "created_time",
+	"updated_at":           "last_modified_time",
+}
+
 // RecordRepositoryImpl 记录仓储实现
 type RecordRepositoryImpl struct {
 	db *gorm.DB
@@ -152,6 +161,24 @@ func (r *RecordRepositoryImpl) Exists(ctx context.Context, id valueobject.Record
 	return count > 0, err
 }
 
+// ListPage 列出记录（元数据表不支持键集游标，按偏移分页）
+func (r *RecordRepositoryImpl) ListPage(ctx context.Context, filter repository.RecordFilter) (*repository.RecordPage, error) {
+	if filter.Cursor != nil {
+		return nil, fmt.Errorf("cursor pagination is not supported")
+	}
+
+	records, total, err := r.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &repository.RecordPage{Records: records, Total: total}, nil
+}
+
+// GroupBy 分组统计（元数据表不支持）
+func (r *RecordRepositoryImpl) GroupBy(ctx context.Context, query repository.RecordGroupQuery) (*repository.RecordGroupResult, error) {
+	return nil, fmt.Errorf("group by is not supported")
+}
+
 // List 列出记录
 func (r *RecordRepositoryImpl) List(ctx context.Context, filter repository.RecordFilter) ([]*entity.Record, int64, error) {
 	query := r.db.WithContext(ctx).Model(&models.Record{}).
@@ -174,14 +201,21 @@ func (r *RecordRepositoryImpl) List(ctx context.Context, filter repository.Recor
 		return nil, 0, fmt.Errorf("failed to count records: %w", err)
 	}
 
-	// 排序
-	if filter.OrderBy != "" {
+	// 排序（仅支持系统时间列，字段排序见 RecordRepositoryDynamic）
+	orderCount := 0
+	for _, item := range filter.Sort {
+		column, ok := legacyRecordSortColumns[item.FieldID]
+		if !ok {
+			continue
+		}
 		orderDir := "ASC"
-		if filter.OrderDir == "desc" {
+		if item.Order == viewValueObject.SortOrderDesc {
 			orderDir = "DESC"
 		}
-		query = query.Order(fmt.Sprintf("%s %s", filter.OrderBy, orderDir))
-	} else {
+		query = query.Order(fmt.Sprintf("%s %s", column, orderDir))
+		orderCount++
+	}
+	if orderCount == 0 {
 		query = query.Order("created_time DESC")
 	}
 
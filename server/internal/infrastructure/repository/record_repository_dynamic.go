@@ -5,11 +5,12 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"strconv"
+	"strings"
 	"time"
 
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
@@ -18,6 +19,7 @@ import (
 	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
 	tableRepo "github.com/easyspace-ai/luckdb/server/internal/domain/table/repository"
+	viewValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
 	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database"
 	pkgDatabase "github.com/easyspace-ai/luckdb/server/pkg/database"
 	"github.com/easyspace-ai/luckdb/server/pkg/errors"
@@ -588,6 +590,11 @@ func (r *RecordRepositoryDynamic) Save(ctx context.Context, record *entity.Recor
 			logger.Any("converted_value", convertedValue))
 	}
 
+	// ✅ 字段约束：空值按 NULL 写入，非空字段为空时拦截
+	if err := applyFieldConstraints(fields, data); err != nil {
+		return err
+	}
+
 	// ✅ 添加详细日志：最终保存的数据（使用 Info 级别以便调试）
 	logger.Info("准备保存到数据库的数据",
 		logger.String("record_id", record.ID().String()),
@@ -792,38 +799,53 @@ func (r *RecordRepositoryDynamic) FindWithVersion(ctx context.Context, tableID s
 	return record, nil
 }
 
+// defaultRecordSort 未指定排序时的默认次序（按创建时间倒序，使用索引）
+var defaultRecordSort = []viewValueObject.SortItem{
+	{FieldID: "__created_time", Order: viewValueObject.SortOrderDesc},
+}
+
 // List 查询记录列表（带过滤条件和分页）
 func (r *RecordRepositoryDynamic) List(ctx context.Context, filter recordRepo.RecordFilter) ([]*entity.Record, int64, error) {
+	page, err := r.ListPage(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return page.Records, page.Total, nil
+}
+
+// ListPage 查询记录列表并生成下一页游标
+//
+// 分页方式：
+//   - 键集分页：filter.Cursor 记录上一页最后一条的排序键取值与 __id，条件为"排序位置在游标之后"，
+//     与排序配置无关地保持稳定，深分页不需要扫描偏移量
+//   - 偏移分页：未传游标时兼容 Offset
+//
+// 排序键末尾总是追加 __id，保证次序确定
+func (r *RecordRepositoryDynamic) ListPage(ctx context.Context, filter recordRepo.RecordFilter) (*recordRepo.RecordPage, error) {
 	// 1. 提取 tableID
 	if filter.TableID == nil {
-		return nil, 0, fmt.Errorf("TableID is required")
+		return nil, fmt.Errorf("TableID is required")
 	}
 	tableID := *filter.TableID
 
-	// 2. 统计总数
-	total, err := r.CountByTableID(ctx, tableID)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	// 3. 获取 Table 信息
+	// 2. 获取 Table 信息
 	table, err := r.tableRepo.GetByID(ctx, tableID)
 	if err != nil {
-		return nil, 0, fmt.Errorf("获取Table信息失败: %w", err)
+		return nil, fmt.Errorf("获取Table信息失败: %w", err)
 	}
 	if table == nil {
-		return nil, 0, fmt.Errorf("Table不存在: %s", tableID)
+		return nil, errors.ErrTableNotFound.WithDetails(tableID)
 	}
 
 	baseID := table.BaseID()
 
-	// 4. 获取字段列表
+	// 3. 获取字段列表
 	fields, err := r.fieldRepo.FindByTableID(ctx, tableID)
 	if err != nil {
-		return nil, 0, fmt.Errorf("获取字段列表失败: %w", err)
+		return nil, fmt.Errorf("获取字段列表失败: %w", err)
 	}
 
-	// 5. ✅ 从物理表查询（带分页和过滤）
+	// 4. ✅ 从物理表查询（带分页和过滤）
 	// 使用完整表名（包含schema）："baseID"."tableID"
 	fullTableName := r.dbProvider.GenerateTableName(baseID, tableID)
 
@@ -838,61 +860,69 @@ func (r *RecordRepositoryDynamic) List(ctx context.Context, filter recordRepo.Re
 		"__version",
 	}
 
-	// 选择所有字段的数据库列（包括虚拟字段的计算结果列）
+	// 选择字段的数据库列（包括虚拟字段的计算结果列），指定 Projection 时只选择其中的字段
 	// 过滤掉空字符串，避免 SQL 语法错误
-	for _, field := range fields {
+	selectFields, err := projectFields(fields, filter.Projection)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range selectFields {
 		dbFieldName := field.DBFieldName().String()
 		if dbFieldName != "" {
-			selectCols = append(selectCols, dbFieldName)
+			selectCols = append(selectCols, quotePGIdentifier(dbFieldName))
 		}
 	}
 
-	// 构建查询
-	query := r.db.WithContext(ctx).
-		Table(fullTableName).
-		Select(selectCols)
+	// 构建查询并应用过滤条件（在事务中时使用事务连接，读到事务内已写入的记录）
+	query, err := r.applyRecordFilter(pkgDatabase.WithTx(ctx, r.db).WithContext(ctx).Table(fullTableName), filter, fields)
+	if err != nil {
+		return nil, err
+	}
 
-	// 应用过滤条件
-	if filter.CreatedBy != nil {
-		query = query.Where("__created_by = ?", *filter.CreatedBy)
+	// 5. 统计过滤后的总数（不含游标条件）
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("统计记录数量失败: %w", err)
 	}
-	if filter.UpdatedBy != nil {
-		query = query.Where("__last_modified_by = ?", *filter.UpdatedBy)
+
+	// 6. 编译排序键（字段排序依赖 PostgreSQL 的 JSONB/数组函数）
+	sortItems := filter.Sort
+	if len(sortItems) == 0 {
+		sortItems = defaultRecordSort
+	} else if r.dbProvider.DriverName() != "postgres" {
+		return nil, errors.ErrFeatureNotAvailable.WithDetails("字段排序仅支持 PostgreSQL")
 	}
 
-	// ✅ 优化：应用排序（使用索引优化）
-	if filter.OrderBy != "" {
-		orderDir := "ASC"
-		if filter.OrderDir == "desc" {
-			orderDir = "DESC"
-		}
-		// 如果使用游标分页，优先使用 __auto_number 排序（性能更好）
-		if filter.Cursor != "" {
-			query = query.Order(fmt.Sprintf("__auto_number %s", orderDir))
-		} else {
-			query = query.Order(fmt.Sprintf("%s %s", filter.OrderBy, orderDir))
-		}
-	} else {
-		// 默认按创建时间倒序（使用索引）
-		// ✅ 优化：如果使用游标分页，使用 __auto_number 排序（性能更好）
-		if filter.Cursor != "" {
-			query = query.Order("__auto_number ASC")
-		} else {
-			query = query.Order("__created_time DESC")
-		}
+	sortKeys, err := newRecordFilterCompiler(fields).CompileSortKeys(sortItems)
+	if err != nil {
+		return nil, err
 	}
 
-	// ✅ 优化：使用游标分页代替偏移分页（提高大偏移量查询性能）
-	if filter.Cursor != "" {
-		// 使用游标分页（基于 __auto_number）
-		cursorID, err := strconv.ParseInt(filter.Cursor, 10, 64)
-		if err == nil {
-			query = query.Where("__auto_number > ?", cursorID)
-		} else {
-			logger.Warn("游标解析失败，使用偏移分页",
-				logger.String("cursor", filter.Cursor),
-				logger.ErrorField(err))
+	// 排序键取值以文本形式一并查询，用于生成下一页游标
+	selectSQL := strings.Join(selectCols, ", ")
+	var selectArgs []interface{}
+	for i, key := range sortKeys {
+		selectSQL += fmt.Sprintf(", CAST(%s AS TEXT) AS %s", key.Expr, sortValueColumn(i))
+		selectArgs = append(selectArgs, key.Args...)
+	}
+	query = query.Select(selectSQL, selectArgs...)
+
+	orderSQL, orderArgs := orderBySQL(sortKeys)
+	query = query.Order(clause.OrderBy{
+		Expression: clause.Expr{SQL: orderSQL + ", __id ASC", Vars: orderArgs, WithoutParentheses: true},
+	})
+
+	// ✅ 优化：使用键集分页代替偏移分页（提高大偏移量查询性能）
+	fingerprint := sortFingerprint(sortItems)
+	if filter.Cursor != nil {
+		if filter.Cursor.SortKey != fingerprint {
+			return nil, errors.ErrInvalidCursor.WithDetails("cursor does not match the current sort")
 		}
+		cond, condArgs, err := keysetCondition(sortKeys, filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(clause.Expr{SQL: cond, Vars: condArgs, WithoutParentheses: true})
 	} else if filter.Offset > 0 {
 		// 如果偏移量过大（> 1000），建议使用游标分页
 		if filter.Offset > 1000 {
@@ -903,20 +933,21 @@ func (r *RecordRepositoryDynamic) List(ctx context.Context, filter recordRepo.Re
 		query = query.Offset(filter.Offset)
 	}
 
-	// 应用分页限制
+	// 应用分页限制（多查询一条记录，用于判断是否有下一页）
 	if filter.Limit > 0 {
-		// ✅ 优化：游标分页时多查询一条记录，用于判断是否有下一页
-		if filter.Cursor != "" {
-			query = query.Limit(filter.Limit + 1)
-		} else {
-			query = query.Limit(filter.Limit)
-		}
+		query = query.Limit(filter.Limit + 1)
 	}
 
 	// 查询记录列表
 	var results []map[string]interface{}
 	if err := query.Find(&results).Error; err != nil {
-		return nil, 0, fmt.Errorf("从物理表查询列表失败: %w", err)
+		return nil, fmt.Errorf("从物理表查询列表失败: %w", err)
+	}
+
+	page := &recordRepo.RecordPage{Total: total}
+	if filter.Limit > 0 && len(results) > filter.Limit {
+		results = results[:filter.Limit]
+		page.NextCursor = recordCursorFromRow(results[len(results)-1], len(sortKeys), fingerprint)
 	}
 
 	logger.Info("✅ 记录列表查询成功（物理表，分页+过滤）",
@@ -925,22 +956,294 @@ func (r *RecordRepositoryDynamic) List(ctx context.Context, filter recordRepo.Re
 		logger.Int("offset", filter.Offset),
 		logger.Int("limit", filter.Limit),
 		logger.Int("count", len(results)),
-		logger.Int64("total", total))
+		logger.Int64("total", total),
+		logger.Bool("has_next", page.NextCursor != nil))
 
 	// 7. 转换为 Domain 实体列表
-	records := make([]*entity.Record, 0, len(results))
+	page.Records = make([]*entity.Record, 0, len(results))
 	for _, result := range results {
-		record, err := r.toDomainEntity(result, fields, tableID)
+		for i := range sortKeys {
+			delete(result, sortValueColumn(i))
+		}
+		record, err := r.toDomainEntity(result, selectFields, tableID)
 		if err != nil {
 			logger.Warn("转换记录失败，跳过",
 				logger.String("record_id", fmt.Sprintf("%v", result["__id"])),
 				logger.ErrorField(err))
 			continue
 		}
-		records = append(records, record)
+		page.Records = append(page.Records, record)
 	}
 
-	return records, total, nil
+	return page, nil
+}
+
+// GroupBy 按字段分组统计记录（GROUP BY 下推到数据库，过滤语义与 ListPage 一致）
+// 每层分组单独查询一次（GROUP BY 前 N 个分组键），再按分组值路径组装成树；另查询一次全表聚合
+func (r *RecordRepositoryDynamic) GroupBy(ctx context.Context, groupQuery recordRepo.RecordGroupQuery) (*recordRepo.RecordGroupResult, error) {
+	filter := groupQuery.Filter
+	if filter.TableID == nil {
+		return nil, fmt.Errorf("TableID is required")
+	}
+	tableID := *filter.TableID
+
+	if r.dbProvider.DriverName() != "postgres" {
+		return nil, errors.ErrFeatureNotAvailable.WithDetails("分组统计仅支持 PostgreSQL")
+	}
+
+	table, err := r.tableRepo.GetByID(ctx, tableID)
+	if err != nil {
+		return nil, fmt.Errorf("获取Table信息失败: %w", err)
+	}
+	if table == nil {
+		return nil, errors.ErrTableNotFound.WithDetails(tableID)
+	}
+
+	fields, err := r.fieldRepo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return nil, fmt.Errorf("获取字段列表失败: %w", err)
+	}
+
+	compiler := newRecordFilterCompiler(fields)
+	groupKeys, err := compiler.CompileGroupKeys(groupQuery.GroupBy)
+	if err != nil {
+		return nil, err
+	}
+	aggregates, err := compiler.CompileAggregates(groupQuery.Aggregations)
+	if err != nil {
+		return nil, err
+	}
+
+	fullTableName := r.dbProvider.GenerateTableName(table.BaseID(), tableID)
+	baseQuery, err := r.applyRecordFilter(r.db.WithContext(ctx).Table(fullTableName), filter, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	// 聚合列：COUNT(*) + 各聚合项（统一转为 JSON 文本，保留数字/日期类型）
+	aggSQL := "COUNT(*) AS __count"
+	var aggArgs []interface{}
+	for i, agg := range aggregates {
+		aggSQL += fmt.Sprintf(", to_jsonb(%s)::text AS __agg_%d", agg.Expr, i)
+		aggArgs = append(aggArgs, agg.Args...)
+	}
+
+	// 1. 全部过滤结果的聚合
+	var totals []map[string]interface{}
+	if err := baseQuery.Session(&gorm.Session{}).Select(aggSQL, aggArgs...).Find(&totals).Error; err != nil {
+		return nil, fmt.Errorf("分组统计查询失败: %w", err)
+	}
+	result := &recordRepo.RecordGroupResult{Groups: make([]*recordRepo.RecordGroup, 0)}
+	if len(totals) > 0 {
+		result.Total = groupCount(totals[0])
+		result.Aggregates = groupAggregates(totals[0], aggregates)
+	}
+
+	// 2. 逐层分组查询
+	parents := map[string]*recordRepo.RecordGroup{}
+	for level := range groupKeys {
+		keys := groupKeys[:level+1]
+
+		selectParts := make([]string, 0, len(keys)+1)
+		groupParts := make([]string, 0, len(keys))
+		orderParts := make([]string, 0, len(keys))
+		var orderArgs []interface{}
+		for i, key := range keys {
+			selectParts = append(selectParts, fmt.Sprintf("to_jsonb(%s)::text AS __group_%d", key.Expr, i))
+			groupParts = append(groupParts, key.Expr)
+			orderParts = append(orderParts, groupOrderExpr(key.Order))
+			orderArgs = append(orderArgs, key.Order.Args...)
+		}
+		selectParts = append(selectParts, aggSQL)
+
+		var rows []map[string]interface{}
+		err := baseQuery.Session(&gorm.Session{}).
+			Select(strings.Join(selectParts, ", "), aggArgs...).
+			Group(strings.Join(groupParts, ", ")).
+			Order(clause.OrderBy{
+				Expression: clause.Expr{SQL: strings.Join(orderParts, ", "), Vars: orderArgs, WithoutParentheses: true},
+			}).
+			Find(&rows).Error
+		if err != nil {
+			return nil, fmt.Errorf("分组统计查询失败: %w", err)
+		}
+
+		levelGroups := make(map[string]*recordRepo.RecordGroup, len(rows))
+		for _, row := range rows {
+			path := make([]interface{}, len(keys))
+			for i := range keys {
+				path[i] = row[fmt.Sprintf("__group_%d", i)]
+			}
+
+			group := &recordRepo.RecordGroup{
+				FieldID:    keys[level].FieldID,
+				Value:      decodeJSONText(path[level]),
+				Count:      groupCount(row),
+				Aggregates: groupAggregates(row, aggregates),
+			}
+			levelGroups[groupPathKey(path)] = group
+
+			if level == 0 {
+				result.Groups = append(result.Groups, group)
+			} else if parent, ok := parents[groupPathKey(path[:level])]; ok {
+				parent.Children = append(parent.Children, group)
+			}
+		}
+		parents = levelGroups
+	}
+
+	logger.Info("✅ 记录分组统计成功",
+		logger.String("table_id", tableID),
+		logger.Int("levels", len(groupKeys)),
+		logger.Int("aggregates", len(aggregates)),
+		logger.Int64("total", result.Total))
+
+	return result, nil
+}
+
+// groupPathKey 分组值路径（各层分组值的 JSON 文本）
+func groupPathKey(path []interface{}) string {
+	raw, _ := json.Marshal(path)
+	return string(raw)
+}
+
+// groupCount 读取分组查询结果中的记录数
+func groupCount(row map[string]interface{}) int64 {
+	switch v := row["__count"].(type) {
+	case int64:
+		return v
+	case int32:
+		return int64(v)
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	}
+	return 0
+}
+
+// groupAggregates 读取分组查询结果中的聚合值（字段ID -> 聚合函数 -> 结果）
+func groupAggregates(row map[string]interface{}, aggregates []recordAggregate) map[string]map[valueobject.AggregateFunc]interface{} {
+	if len(aggregates) == 0 {
+		return nil
+	}
+
+	values := make(map[string]map[valueobject.AggregateFunc]interface{})
+	for i, agg := range aggregates {
+		if values[agg.FieldID] == nil {
+			values[agg.FieldID] = make(map[valueobject.AggregateFunc]interface{})
+		}
+		values[agg.FieldID][agg.Func] = decodeJSONText(row[fmt.Sprintf("__agg_%d", i)])
+	}
+	return values
+}
+
+// projectFields 按字段ID投影字段列表；projection 为空时返回全部字段
+func projectFields(fields []*fieldEntity.Field, projection []string) ([]*fieldEntity.Field, error) {
+	if len(projection) == 0 {
+		return fields, nil
+	}
+
+	byID := make(map[string]*fieldEntity.Field, len(fields))
+	for _, field := range fields {
+		byID[field.ID().String()] = field
+	}
+
+	projected := make([]*fieldEntity.Field, 0, len(projection))
+	seen := make(map[string]bool, len(projection))
+	for _, fieldID := range projection {
+		field, ok := byID[fieldID]
+		if !ok {
+			return nil, errors.ErrFieldNotFound.WithDetails(map[string]interface{}{
+				"field_id": fieldID,
+				"reason":   "projection references unknown field",
+			})
+		}
+		if !seen[fieldID] {
+			seen[fieldID] = true
+			projected = append(projected, field)
+		}
+	}
+	return projected, nil
+}
+
+// sortValueColumn 排序键取值的结果列名
+func sortValueColumn(i int) string {
+	return fmt.Sprintf("__sort_%d", i)
+}
+
+// recordCursorFromRow 根据一页最后一条记录生成游标
+func recordCursorFromRow(row map[string]interface{}, keyCount int, fingerprint string) *valueobject.RecordCursor {
+	cursor := &valueobject.RecordCursor{
+		SortKey: fingerprint,
+		Values:  make([]*string, keyCount),
+		ID:      fmt.Sprintf("%v", row["__id"]),
+	}
+	for i := 0; i < keyCount; i++ {
+		switch v := row[sortValueColumn(i)].(type) {
+		case nil:
+		case string:
+			cursor.Values[i] = &v
+		case []byte:
+			text := string(v)
+			cursor.Values[i] = &text
+		default:
+			text := fmt.Sprintf("%v", v)
+			cursor.Values[i] = &text
+		}
+	}
+	return cursor
+}
+
+// applyRecordFilter 将 RecordFilter 中的过滤条件应用到物理表查询
+// 过滤树与字段过滤均编译为参数化 SQL（见 recordFilterCompiler）
+func (r *RecordRepositoryDynamic) applyRecordFilter(
+	query *gorm.DB,
+	filter recordRepo.RecordFilter,
+	fields []*fieldEntity.Field,
+) (*gorm.DB, error) {
+	if filter.CreatedBy != nil {
+		query = query.Where("__created_by = ?", *filter.CreatedBy)
+	}
+	if filter.UpdatedBy != nil {
+		query = query.Where("__last_modified_by = ?", *filter.UpdatedBy)
+	}
+
+	if len(filter.FieldFilters) == 0 && filter.Filter.IsEmpty() && filter.Search == nil {
+		return query, nil
+	}
+
+	if r.dbProvider.DriverName() != "postgres" {
+		return nil, errors.ErrFeatureNotAvailable.WithDetails("字段过滤仅支持 PostgreSQL")
+	}
+
+	compiler := newRecordFilterCompiler(fields)
+
+	fieldSQL, fieldArgs, err := compiler.CompileFieldFilters(filter.FieldFilters)
+	if err != nil {
+		return nil, err
+	}
+	if fieldSQL != "" {
+		query = query.Where(fieldSQL, fieldArgs...)
+	}
+
+	treeSQL, treeArgs, err := compiler.Compile(filter.Filter)
+	if err != nil {
+		return nil, err
+	}
+	if treeSQL != "" {
+		query = query.Where(treeSQL, treeArgs...)
+	}
+
+	searchSQL, searchArgs, err := compiler.CompileSearch(filter.Search)
+	if err != nil {
+		return nil, err
+	}
+	if searchSQL != "" {
+		query = query.Where(searchSQL, searchArgs...)
+	}
+
+	return query, nil
 }
 
 // NextID 生成下一个记录ID
@@ -1292,12 +1595,18 @@ func (r *RecordRepositoryDynamic) BatchCreate(ctx context.Context, records []*en
 				value, _ := recordData.Get(fieldID)
 				data[dbFieldName] = r.convertValueForDB(field, value)
 			}
+			if err := applyFieldConstraints(fields, data); err != nil {
+				return err
+			}
 
 			dataList = append(dataList, data)
 		}
 
 		// 3.3 批量插入物理表（使用 CreateInBatches 提高性能）
 		if err := tx.Table(fullTableName).CreateInBatches(dataList, 500).Error; err != nil {
+			if pkgDatabase.IsConstraintError(err) {
+				return pkgDatabase.HandleBatchConstraintError(err, tableID, r.fieldRepo, ctx)
+			}
 			return fmt.Errorf("批量插入物理表失败: %w", err)
 		}
 
@@ -1327,6 +1636,10 @@ func (r *RecordRepositoryDynamic) BatchUpdate(ctx context.Context, records []*en
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		for _, record := range records {
 			if err := r.Save(ctx, record); err != nil {
+				// 约束错误等应用错误原样返回，保留错误码
+				if _, ok := errors.IsAppError(err); ok {
+					return err
+				}
 				return fmt.Errorf("批量更新记录 %s 失败: %w", record.ID().String(), err)
 			}
 		}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/recordrule"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// RecordRuleRepository 记录校验规则仓储实现（record_rule 表）
+type RecordRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewRecordRuleRepository 创建记录校验规则仓储
+func NewRecordRuleRepository(db *gorm.DB) recordrule.Repository {
+	return &RecordRuleRepository{db: db}
+}
+
+// Create 创建规则
+func (r *RecordRuleRepository) Create(ctx context.Context, rule *recordrule.Rule) error {
+	model := toRecordRuleModel(rule)
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		return err
+	}
+	rule.CreatedTime = model.CreatedTime
+	rule.LastModifiedTime = model.LastModifiedTime
+	return nil
+}
+
+// Update 更新规则的名称、表达式、提示与启用状态
+func (r *RecordRuleRepository) Update(ctx context.Context, rule *recordrule.Rule) error {
+	return r.db.WithContext(ctx).Model(&models.RecordRule{}).
+		Where("id = ?", rule.ID).
+		Updates(map[string]interface{}{
+			"name":             rule.Name,
+			"expression":       rule.Expression,
+			"message":          nullableString(rule.Message),
+			"enabled":          rule.Enabled,
+			"last_modified_by": nullableString(rule.LastModifiedBy),
+		}).Error
+}
+
+// Delete 删除规则
+func (r *RecordRuleRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.RecordRule{}).Error
+}
+
+// FindByID 获取规则，不存在时返回 nil
+func (r *RecordRuleRepository) FindByID(ctx context.Context, id string) (*recordrule.Rule, error) {
+	var model models.RecordRule
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return fromRecordRuleModel(&model), nil
+}
+
+// FindByTableID 获取表格的所有规则（按创建时间排序）
+func (r *RecordRuleRepository) FindByTableID(ctx context.Context, tableID string) ([]*recordrule.Rule, error) {
+	var list []models.RecordRule
+	err := r.db.WithContext(ctx).
+		Where("table_id = ?", tableID).
+		Order("created_time ASC, id ASC").
+		Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]*recordrule.Rule, 0, len(list))
+	for i := range list {
+		rules = append(rules, fromRecordRuleModel(&list[i]))
+	}
+	return rules, nil
+}
+
+func toRecordRuleModel(rule *recordrule.Rule) *models.RecordRule {
+	return &models.RecordRule{
+		ID:             rule.ID,
+		TableID:        rule.TableID,
+		Name:           rule.Name,
+		Expression:     rule.Expression,
+		Message:        nullableString(rule.Message),
+		Enabled:        rule.Enabled,
+		CreatedBy:      rule.CreatedBy,
+		LastModifiedBy: nullableString(rule.LastModifiedBy),
+	}
+}
+
+func fromRecordRuleModel(model *models.RecordRule) *recordrule.Rule {
+	rule := &recordrule.Rule{
+		ID:               model.ID,
+		TableID:          model.TableID,
+		Name:             model.Name,
+		Expression:       model.Expression,
+		Enabled:          model.Enabled,
+		CreatedBy:        model.CreatedBy,
+		CreatedTime:      model.CreatedTime,
+		LastModifiedTime: model.LastModifiedTime,
+	}
+	if model.Message != nil {
+		rule.Message = *model.Message
+	}
+	if model.LastModifiedBy != nil {
+		rule.LastModifiedBy = *model.LastModifiedBy
+	}
+	return rule
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldService "github.com/easyspace-ai/luckdb/server/internal/domain/fields/service"
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+	"github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// CompileSearch 编译全文搜索条件，各可搜索字段之间为 OR
+//
+// 按字段类型生成 SQL：
+//   - 文本：ILIKE 子串匹配（pg_trgm 索引），长文本另外匹配 tsvector（simple 分词）
+//   - 单选/多选：选项值子串匹配，或存储的选项ID属于名称匹配的选项
+//   - 关联：JSONB 中 title 子串匹配
+//
+// 关键词是拼音时，文本与关联标题同时按同音汉字正则（~）匹配
+func (c *recordFilterCompiler) CompileSearch(search *recordRepo.RecordSearch) (string, []interface{}, error) {
+	if search == nil {
+		return "", nil, nil
+	}
+	matcher := fieldService.NewSearchMatcher(search.Query)
+	if matcher == nil {
+		return "", nil, nil
+	}
+
+	fields, err := c.searchFields(search.FieldIDs)
+	if err != nil {
+		return "", nil, err
+	}
+
+	parts := make([]string, 0, len(fields))
+	args := make([]interface{}, 0)
+	for _, field := range fields {
+		sql, fieldArgs := c.searchFieldCondition(field, matcher)
+		parts = append(parts, sql)
+		args = append(args, fieldArgs...)
+	}
+	if len(parts) == 0 {
+		// 没有可搜索字段时不匹配任何记录
+		return "FALSE", nil, nil
+	}
+	return "(" + strings.Join(parts, " OR ") + ")", args, nil
+}
+
+// searchFields 解析参与搜索的字段；指定字段时只保留其中可搜索的字段，未知字段返回 INVALID_FILTER
+func (c *recordFilterCompiler) searchFields(fieldKeys []string) ([]*fieldEntity.Field, error) {
+	var candidates []*fieldEntity.Field
+	if len(fieldKeys) == 0 {
+		for _, field := range c.fieldsByID {
+			candidates = append(candidates, field)
+		}
+	} else {
+		for _, key := range fieldKeys {
+			field := c.resolveField(key)
+			if field == nil {
+				return nil, errors.ErrInvalidFilter.WithDetails(map[string]interface{}{
+					"field_id": key,
+					"reason":   "search references unknown field",
+				})
+			}
+			candidates = append(candidates, field)
+		}
+	}
+
+	fields := make([]*fieldEntity.Field, 0, len(candidates))
+	for _, field := range candidates {
+		if fieldService.IsSearchableField(field) && field.DBFieldName().String() != "" {
+			fields = append(fields, field)
+		}
+	}
+	// 固定顺序，保证生成的 SQL 稳定
+	sort.Slice(fields, func(i, j int) bool { return fields[i].ID().String() < fields[j].ID().String() })
+	return fields, nil
+}
+
+// searchFieldCondition 单个字段的搜索条件
+func (c *recordFilterCompiler) searchFieldCondition(field *fieldEntity.Field, matcher *fieldService.SearchMatcher) (string, []interface{}) {
+	column := quotePGIdentifier(field.DBFieldName().String())
+	pattern := likePattern(matcher.Query())
+	pinyinPattern := matcher.PinyinPattern()
+
+	switch filterKindOf(field) {
+	case filterKindStringArray:
+		conds := []string{"e.v ILIKE ?"}
+		args := []interface{}{pattern}
+		if ids := matcher.MatchChoiceIDs(field); len(ids) > 0 {
+			conds = append(conds, "e.v IN ?")
+			args = append(args, ids)
+		}
+		if pinyinPattern != "" {
+			conds = append(conds, "e.v ~ ?")
+			args = append(args, pinyinPattern)
+		}
+		return fmt.Sprintf("EXISTS (SELECT 1 FROM jsonb_array_elements_text(%s) AS e(v) WHERE %s)",
+			jsonbArrayExpr(column), strings.Join(conds, " OR ")), args
+
+	case filterKindObjectArray:
+		conds := []string{"e.v->>'title' ILIKE ?"}
+		args := []interface{}{pattern}
+		if pinyinPattern != "" {
+			conds = append(conds, "e.v->>'title' ~ ?")
+			args = append(args, pinyinPattern)
+		}
+		return fmt.Sprintf("EXISTS (SELECT 1 FROM jsonb_array_elements(%s) AS e(v) WHERE %s)",
+			jsonbArrayExpr(column), strings.Join(conds, " OR ")), args
+	}
+
+	text := column
+	if filterKindOf(field) != filterKindText {
+		text = column + "::text"
+	}
+	conds := []string{fmt.Sprintf("%s ILIKE ?", text)}
+	args := []interface{}{pattern}
+	if field.Type().String() == fieldValueObject.TypeLongText {
+		conds = append(conds, fmt.Sprintf("%s @@ plainto_tsquery('simple', ?)", tsvectorExpr(column)))
+		args = append(args, matcher.Query())
+	}
+	if ids := matcher.MatchChoiceIDs(field); len(ids) > 0 {
+		conds = append(conds, fmt.Sprintf("%s IN ?", text))
+		args = append(args, ids)
+	}
+	if pinyinPattern != "" {
+		conds = append(conds, fmt.Sprintf("%s ~ ?", text))
+		args = append(args, pinyinPattern)
+	}
+	return "(" + strings.Join(conds, " OR ") + ")", args
+}
+
+// tsvectorExpr 长文本的 tsvector 表达式（与字段搜索索引的表达式保持一致）
+func tsvectorExpr(column string) string {
+	return fmt.Sprintf("to_tsvector('simple', COALESCE(%s, ''))", column)
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	recordRepo "github.com/easyspace-ai/luckdb/server/internal/domain/record/repository"
+)
+
+func TestRecordFilterCompiler_CompileSearch(t *testing.T) {
+	c := newTestSortCompiler(t)
+
+	sql, args, err := c.CompileSearch(&recordRepo.RecordSearch{Query: "done"})
+	require.NoError(t, err)
+	assert.Equal(t,
+		`(("name" ILIKE ?) OR EXISTS (SELECT 1 FROM jsonb_array_elements(`+jsonbArrayExpr(`"project"`)+`) AS e(v) WHERE e.v->>'title' ILIKE ?) OR `+
+			`("status" ILIKE ? OR "status" IN ?))`,
+		sql)
+	assert.Equal(t, []interface{}{"%done%", "%done%", "%done%", []string{"cho_done"}}, args)
+}
+
+func TestRecordFilterCompiler_CompileSearchPinyin(t *testing.T) {
+	c := newTestSortCompiler(t)
+
+	sql, args, err := c.CompileSearch(&recordRepo.RecordSearch{Query: "zhangsan", FieldIDs: []string{"fld_name", "fld_amount"}})
+	require.NoError(t, err)
+	assert.Equal(t, `(("name" ILIKE ? OR "name" ~ ?))`, sql)
+	require.Len(t, args, 2)
+	assert.Contains(t, args[1], "张")
+
+	_, _, err = c.CompileSearch(&recordRepo.RecordSearch{Query: "x", FieldIDs: []string{"fld_missing"}})
+	assert.Error(t, err)
+}
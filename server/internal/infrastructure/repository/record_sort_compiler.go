@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	viewValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+	"github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+// systemSortColumns 允许直接排序的系统列（键为排序项中的 fieldId）
+// 物理表的时间系统列为 TIMESTAMP（不带时区，见 PostgresProvider.CreatePhysicalTable），
+// 游标取值由 CAST(列 AS TEXT) 得到，按 timestamp 回绑可精确还原到微秒，不受会话时区影响
+var systemSortColumns = map[string]recordSortKey{
+	"__auto_number":        {Expr: "__auto_number", CastType: "bigint"},
+	"__created_time":       {Expr: "__created_time", CastType: "timestamp"},
+	"__last_modified_time": {Expr: "__last_modified_time", CastType: "timestamp"},
+	"created_at":           {Expr: "__created_time", CastType: "timestamp"},
+	"updated_at":           {Expr: "__last_modified_time", CastType: "timestamp"},
+}
+
+// recordSortKey 编译后的排序键
+type recordSortKey struct {
+	Expr     string        // 排序表达式
+	Args     []interface{} // 表达式参数
+	Desc     bool          // 是否降序（空值始终在后）
+	CastType string        // 游标取值（文本）回绑时转换的 SQL 类型
+}
+
+// CompileSort 编译多键排序，返回 ORDER BY 片段与参数；排序项为空时返回空字符串
+//
+// 排序语义按字段类型决定：
+//   - 单选/多选：按选项在字段配置中的顺序（多选取第一个选项）
+//   - 用户：按显示名；关联：按主字段标题（单元格 JSONB 中的 title）
+//   - 其余类型按列值排序；所有类型的空值均排在最后
+//
+// 列名只来自字段元数据或系统列白名单，未知字段返回 INVALID_SORT
+func (c *recordFilterCompiler) CompileSort(items []viewValueObject.SortItem) (string, []interface{}, error) {
+	keys, err := c.CompileSortKeys(items)
+	if err != nil {
+		return "", nil, err
+	}
+	sql, args := orderBySQL(keys)
+	return sql, args, nil
+}
+
+// CompileSortKeys 编译多键排序为排序键列表（供 ORDER BY 与键集游标共用）
+func (c *recordFilterCompiler) CompileSortKeys(items []viewValueObject.SortItem) ([]recordSortKey, error) {
+	keys := make([]recordSortKey, 0, len(items))
+
+	for _, item := range items {
+		if err := item.Validate(); err != nil {
+			return nil, errors.ErrInvalidSort.WithDetails(err.Error())
+		}
+
+		key, err := c.sortKey(item.FieldID)
+		if err != nil {
+			return nil, err
+		}
+		key.Desc = item.Order == viewValueObject.SortOrderDesc
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// sortKey 生成单个排序键的排序表达式
+func (c *recordFilterCompiler) sortKey(fieldKey string) (recordSortKey, error) {
+	if key, ok := systemSortColumns[fieldKey]; ok {
+		return key, nil
+	}
+
+	field := c.resolveField(fieldKey)
+	if field == nil || field.DBFieldName().String() == "" {
+		return recordSortKey{}, errors.ErrInvalidSort.WithDetails(map[string]interface{}{
+			"field_id": fieldKey,
+			"reason":   "sort references unknown field",
+		})
+	}
+	column := quotePGIdentifier(field.DBFieldName().String())
+
+	switch field.Type().String() {
+	case fieldValueObject.TypeSelect, fieldValueObject.TypeSingleSelect:
+		return selectChoiceOrderKey(column, field), nil
+	case fieldValueObject.TypeMultipleSelect:
+		return selectChoiceOrderKey(fmt.Sprintf("(%s->>0)", jsonbArrayExpr(column)), field), nil
+	case fieldValueObject.TypeUser:
+		first := fmt.Sprintf("(%s->0)", jsonbArrayExpr(column))
+		return recordSortKey{
+			Expr:     fmt.Sprintf("NULLIF(COALESCE(%[1]s->>'title', %[1]s->>'name', %[1]s->>'email'), '')", first),
+			CastType: "text",
+		}, nil
+	case fieldValueObject.TypeLink:
+		return recordSortKey{
+			Expr:     fmt.Sprintf("NULLIF((%s->0)->>'title', '')", jsonbArrayExpr(column)),
+			CastType: "text",
+		}, nil
+	}
+
+	switch filterKindOf(field) {
+	case filterKindNumber:
+		return recordSortKey{Expr: column, CastType: "numeric"}, nil
+	case filterKindDate:
+		castType := "timestamp"
+		if dbType := strings.ToLower(field.DBFieldType()); dbType == "timestamptz" || dbType == "date" {
+			castType = dbType
+		}
+		return recordSortKey{Expr: column, CastType: castType}, nil
+	case filterKindBoolean:
+		return recordSortKey{Expr: fmt.Sprintf("COALESCE(%s, FALSE)", column), CastType: "boolean"}, nil
+	case filterKindStringArray, filterKindObjectArray, filterKindJSON:
+		return recordSortKey{Expr: fmt.Sprintf("NULLIF(%s::text, 'null')", column), CastType: "text"}, nil
+	}
+	return recordSortKey{Expr: fmt.Sprintf("NULLIF(%s::text, '')", column), CastType: "text"}, nil
+}
+
+// orderBySQL 生成 ORDER BY 片段（空值在后）
+func orderBySQL(keys []recordSortKey) (string, []interface{}) {
+	parts := make([]string, 0, len(keys))
+	args := make([]interface{}, 0)
+	for _, key := range keys {
+		dir := "ASC"
+		if key.Desc {
+			dir = "DESC"
+		}
+		parts = append(parts, fmt.Sprintf("%s %s NULLS LAST", key.Expr, dir))
+		args = append(args, key.Args...)
+	}
+	return strings.Join(parts, ", "), args
+}
+
+// keysetCondition 生成键集分页条件：排序位置严格位于游标之后的记录
+// 按字典序展开：(k1 在后) OR (k1 相等 AND k2 在后) OR ... OR (全部相等 AND __id > 游标ID)
+// 排序均为空值在后：游标值为 NULL 时该键不存在"之后"的非空值
+func keysetCondition(keys []recordSortKey, cursor *valueobject.RecordCursor) (string, []interface{}, error) {
+	if len(cursor.Values) != len(keys) {
+		return "", nil, errors.ErrInvalidCursor.WithDetails("cursor does not match the current sort")
+	}
+
+	var (
+		disjuncts []string
+		args      []interface{}
+		equals    []string
+		eqArgs    []interface{}
+	)
+
+	for i, key := range keys {
+		value := cursor.Values[i]
+
+		if value != nil {
+			op := ">"
+			if key.Desc {
+				op = "<"
+			}
+			after := fmt.Sprintf("(%[1]s %[2]s CAST(? AS %[3]s) OR %[1]s IS NULL)", key.Expr, op, key.CastType)
+			disjuncts = append(disjuncts, strings.Join(append(append([]string{}, equals...), after), " AND "))
+			args = append(args, eqArgs...)
+			args = append(args, key.Args...)
+			args = append(args, *value)
+			args = append(args, key.Args...)
+
+			equals = append(equals, fmt.Sprintf("%s = CAST(? AS %s)", key.Expr, key.CastType))
+			eqArgs = append(eqArgs, key.Args...)
+			eqArgs = append(eqArgs, *value)
+		} else {
+			equals = append(equals, fmt.Sprintf("%s IS NULL", key.Expr))
+			eqArgs = append(eqArgs, key.Args...)
+		}
+	}
+
+	disjuncts = append(disjuncts, strings.Join(append(append([]string{}, equals...), "__id > ?"), " AND "))
+	args = append(args, eqArgs...)
+	args = append(args, cursor.ID)
+
+	return "(" + strings.Join(disjuncts, ") OR (") + ")", args, nil
+}
+
+// sortFingerprint 排序配置指纹（写入游标，排序变化时旧游标失效）
+func sortFingerprint(items []viewValueObject.SortItem) string {
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = item.FieldID + ":" + string(item.Order)
+	}
+	return strings.Join(parts, ",")
+}
+
+// selectChoiceOrderKey 按选项顺序排序；单元格可能存选项名称或选项ID，两者都参与匹配
+// 不在选项列表中的值（如已删除的选项）返回 NULL，排在最后
+func selectChoiceOrderKey(valueExpr string, field *fieldEntity.Field) recordSortKey {
+	var choices []fieldValueObject.SelectChoice
+	if options := field.Options(); options != nil && options.Select != nil {
+		choices = options.Select.Choices
+	}
+	if len(choices) == 0 {
+		return recordSortKey{Expr: fmt.Sprintf("NULLIF(%s, '')", valueExpr), CastType: "text"}
+	}
+
+	names := make([]string, len(choices))
+	ids := make([]string, len(choices))
+	for i, choice := range choices {
+		names[i] = choice.Name
+		ids[i] = choice.ID
+	}
+
+	namesExpr, args := textArrayExpr(names)
+	idsExpr, idArgs := textArrayExpr(ids)
+	args = append(args, idArgs...)
+
+	return recordSortKey{
+		Expr: fmt.Sprintf("COALESCE(array_position(%s, %s), array_position(%s, %s))",
+			namesExpr, valueExpr, idsExpr, valueExpr),
+		Args:     args,
+		CastType: "integer",
+	}
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
+	fieldValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/fields/valueobject"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/record/valueobject"
+	viewValueObject "github.com/easyspace-ai/luckdb/server/internal/domain/view/valueobject"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+)
+
+func init() {
+	// 关联字段的 NewField 会写日志
+	if logger.Logger == nil {
+		logger.Init(logger.LoggerConfig{
+			Level:      "error",
+			Format:     "console",
+			OutputPath: "stdout",
+		})
+	}
+}
+
+func newTestSortCompiler(t *testing.T) *recordFilterCompiler {
+	status := newFilterTestField(t, "fld_status", "status", fieldValueObject.TypeSingleSelect)
+	status.Options().WithSelect([]fieldValueObject.SelectChoice{
+		{ID: "cho_todo", Name: "Todo"},
+		{ID: "cho_done", Name: "Done"},
+	})
+
+	fields := []*fieldEntity.Field{
+		newFilterTestField(t, "fld_name", "name", fieldValueObject.TypeText),
+		newFilterTestField(t, "fld_amount", "amount", fieldValueObject.TypeNumber),
+		newFilterTestField(t, "fld_owner", "owner", fieldValueObject.TypeUser),
+		newFilterTestField(t, "fld_project", "project", fieldValueObject.TypeLink),
+		status,
+	}
+	return newRecordFilterCompiler(fields)
+}
+
+func TestRecordFilterCompiler_CompileSort(t *testing.T) {
+	c := newTestSortCompiler(t)
+
+	sql, args, err := c.CompileSort([]viewValueObject.SortItem{
+		{FieldID: "fld_status", Order: viewValueObject.SortOrderAsc},
+		{FieldID: "fld_amount", Order: viewValueObject.SortOrderDesc},
+		{FieldID: "__created_time", Order: viewValueObject.SortOrderAsc},
+	})
+	require.NoError(t, err)
+	assert.Equal(t,
+		`COALESCE(array_position(ARRAY[?, ?]::text[], "status"), array_position(ARRAY[?, ?]::text[], "status")) ASC NULLS LAST, `+
+			`"amount" DESC NULLS LAST, __created_time ASC NULLS LAST`,
+		sql)
+	assert.Equal(t, []interface{}{"Todo", "Done", "cho_todo", "cho_done"}, args)
+}
+
+func TestRecordFilterCompiler_CompileSortByTitle(t *testing.T) {
+	c := newTestSortCompiler(t)
+
+	sql, args, err := c.CompileSort([]viewValueObject.SortItem{
+		{FieldID: "fld_owner", Order: viewValueObject.SortOrderAsc},
+		{FieldID: "fld_project", Order: viewValueObject.SortOrderAsc},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, sql, "->>'name'")
+	assert.Contains(t, sql, "->>'title', '') ASC NULLS LAST")
+	assert.Empty(t, args)
+}
+
+func TestRecordFilterCompiler_CompileSortRejectsUnknownColumns(t *testing.T) {
+	c := newTestSortCompiler(t)
+
+	_, _, err := c.CompileSort([]viewValueObject.SortItem{
+		{FieldID: "name; DROP TABLE x", Order: viewValueObject.SortOrderAsc},
+	})
+	assert.Error(t, err)
+
+	_, _, err = c.CompileSort([]viewValueObject.SortItem{
+		{FieldID: "fld_name", Order: "sideways"},
+	})
+	assert.Error(t, err)
+}
+
+func TestKeysetCondition(t *testing.T) {
+	c := newTestSortCompiler(t)
+
+	keys, err := c.CompileSortKeys([]viewValueObject.SortItem{
+		{FieldID: "fld_amount", Order: viewValueObject.SortOrderDesc},
+		{FieldID: "fld_name", Order: viewValueObject.SortOrderAsc},
+	})
+	require.NoError(t, err)
+
+	amount := "10"
+	sql, args, err := keysetCondition(keys, &valueobject.RecordCursor{Values: []*string{&amount, nil}, ID: "rec_1"})
+	require.NoError(t, err)
+	assert.Equal(t,
+		`(("amount" < CAST(? AS numeric) OR "amount" IS NULL)) OR `+
+			`("amount" = CAST(? AS numeric) AND NULLIF("name"::text, '') IS NULL AND __id > ?)`,
+		sql)
+	assert.Equal(t, []interface{}{"10", "10", "rec_1"}, args)
+
+	_, _, err = keysetCondition(keys, &valueobject.RecordCursor{Values: []*string{&amount}, ID: "rec_1"})
+	assert.Error(t, err)
+}
+
+func TestKeysetCondition_TimeColumns(t *testing.T) {
+	due := newFilterTestField(t, "fld_due", "due", fieldValueObject.TypeDateTime)
+	day := newFilterTestField(t, "fld_day", "day", fieldValueObject.TypeDate)
+	c := newRecordFilterCompiler([]*fieldEntity.Field{due, day})
+
+	keys, err := c.CompileSortKeys([]viewValueObject.SortItem{
+		{FieldID: "__created_time", Order: viewValueObject.SortOrderDesc},
+		{FieldID: "fld_due", Order: viewValueObject.SortOrderAsc},
+		{FieldID: "fld_day", Order: viewValueObject.SortOrderAsc},
+	})
+	require.NoError(t, err)
+	// 回绑类型与物理列类型一致：系统时间列与日期时间字段为 TIMESTAMP，日期字段为 DATE
+	assert.Equal(t, "timestamp", keys[0].CastType)
+	assert.Equal(t, strings.ToLower(due.DBFieldType()), keys[1].CastType)
+	assert.Equal(t, strings.ToLower(day.DBFieldType()), keys[2].CastType)
+
+	created := "2025-03-01 08:30:00.123456"
+	sql, args, err := keysetCondition(keys[:1], &valueobject.RecordCursor{Values: []*string{&created}, ID: "rec_1"})
+	require.NoError(t, err)
+	assert.Equal(t,
+		`((__created_time < CAST(? AS timestamp) OR __created_time IS NULL)) OR `+
+			`(__created_time = CAST(? AS timestamp) AND __id > ?)`,
+		sql)
+	assert.Equal(t, []interface{}{created, created, "rec_1"}, args)
+}
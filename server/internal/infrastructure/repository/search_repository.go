@@ -0,0 +1,1191 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	collaboratorEntity "github.com/easyspace-ai/luckdb/server/internal/domain/collaborator/entity"
+	fieldService "github.com/easyspace-ai/luckdb/server/internal/domain/fields/service"
+	"github.com/easyspace-ai/luckdb/server/internal/domain/search"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	"github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/utils"
+)
+
+const (
+	// searchDocumentExpr 全文检索文档（与 idx_search_indexes_tsv 索引表达式保持一致）
+	searchDocumentExpr = "to_tsvector('simple', COALESCE(search_indexes.title, '') || ' ' || COALESCE(search_indexes.content, ''))"
+	// maxSearchPageSize 单页最大结果数
+	maxSearchPageSize = 100
+	// searchRebuildBatchSize 重建索引时每批写入的条目数
+	searchRebuildBatchSize = 500
+	// searchSnippetRadius 高亮片段中命中位置前后保留的字符数
+	searchSnippetRadius = 40
+	// maxSuggestionQueryLength 记录为搜索建议的查询词最大长度（字符数）
+	maxSuggestionQueryLength = 200
+	// staleSuggestionAge 只搜索过一次且超过该时长未再出现的查询词在优化索引时清理
+	staleSuggestionAge = 90 * 24 * time.Hour
+)
+
+// searchFilterColumns 允许用于过滤、排序与分面的列
+var searchFilterColumns = map[string]string{
+	"type":         "search_indexes.type",
+	"source_id":    "search_indexes.source_id",
+	"source_type":  "search_indexes.source_type",
+	"user_id":      "search_indexes.user_id",
+	"space_id":     "search_indexes.space_id",
+	"base_id":      "search_indexes.base_id",
+	"table_id":     "search_indexes.table_id",
+	"field_id":     "search_indexes.field_id",
+	"title":        "search_indexes.title",
+	"created_time": "search_indexes.created_time",
+	"updated_time": "search_indexes.updated_time",
+}
+
+// searchSourceQueries 可由元数据表重建的索引类型及其来源查询
+// 每行对应一条索引：来源ID、标题、内容及所属空间/Base/表格/字段
+var searchSourceQueries = map[search.SearchType]string{
+	search.SearchTypeSpace: `SELECT s.id AS source_id, s.name AS title, COALESCE(s.description, '') AS content,
+		s.created_by AS user_id, s.id AS space_id, '' AS base_id, '' AS table_id, '' AS field_id, '' AS field_type
+		FROM space s WHERE s.deleted_time IS NULL`,
+	search.SearchTypeBase: `SELECT b.id AS source_id, b.name AS title, COALESCE(b.description, '') AS content,
+		b.created_by AS user_id, b.space_id, b.id AS base_id, '' AS table_id, '' AS field_id, '' AS field_type
+		FROM base b WHERE b.deleted_time IS NULL`,
+	search.SearchTypeTable: `SELECT t.id AS source_id, t.name AS title, COALESCE(t.description, '') AS content,
+		t.created_by AS user_id, b.space_id, t.base_id, t.id AS table_id, '' AS field_id, '' AS field_type
+		FROM table_meta t JOIN base b ON b.id = t.base_id
+		WHERE t.deleted_time IS NULL AND b.deleted_time IS NULL`,
+	search.SearchTypeField: `SELECT f.id AS source_id, f.name AS title, COALESCE(f.description, '') AS content,
+		f.created_by AS user_id, b.space_id, t.base_id, f.table_id, f.id AS field_id, f.type AS field_type
+		FROM field f JOIN table_meta t ON t.id = f.table_id JOIN base b ON b.id = t.base_id
+		WHERE f.deleted_time IS NULL AND t.deleted_time IS NULL AND b.deleted_time IS NULL`,
+}
+
+// indexedSourceTypes 由搜索索引器维护的来源类型（Base 内重建只清理这些来源）
+var indexedSourceTypes = []string{
+	string(search.SearchTypeBase),
+	string(search.SearchTypeTable),
+	string(search.SearchTypeField),
+	string(search.SearchTypeRecord),
+}
+
+// searchUpsertColumns 按来源 upsert 时覆盖的列
+var searchUpsertColumns = []string{
+	"type", "title", "content", "metadata", "user_id",
+	"space_id", "base_id", "table_id", "field_id", "updated_time",
+}
+
+// rebuildableSearchTypes 重建全部索引时的顺序
+var rebuildableSearchTypes = []search.SearchType{
+	search.SearchTypeSpace,
+	search.SearchTypeBase,
+	search.SearchTypeTable,
+	search.SearchTypeField,
+}
+
+// SearchRepositoryImpl 全局搜索仓储实现（PostgreSQL 全文检索）
+//
+// 匹配：标题与内容的 simple 分词 tsvector 命中，或标题/内容/关键词子串命中（pg_trgm 索引，中文无需分词），
+// 关键词是拼音时标题同时按同音汉字匹配。得分 = ts_rank + 标题命中加权
+type SearchRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewSearchRepository 创建搜索仓储
+func NewSearchRepository(db *gorm.DB) search.Repository {
+	return &SearchRepositoryImpl{db: db}
+}
+
+// searchIndexHit 带得分的搜索结果行
+type searchIndexHit struct {
+	models.SearchIndex
+	Score float64 `gorm:"column:score"`
+}
+
+// searchSourceRow 重建索引时的来源行
+type searchSourceRow struct {
+	SourceID  string
+	Title     string
+	Content   string
+	UserID    string
+	SpaceID   string
+	BaseID    string
+	TableID   string
+	FieldID   string
+	FieldType string
+}
+
+// ==================== 索引管理 ====================
+
+// CreateIndex 创建搜索索引（viewerID 非空时条目必须在其可访问的空间、Base 内或属于本人，否则回滚）
+func (r *SearchRepositoryImpl) CreateIndex(ctx context.Context, index *search.SearchIndex, viewerID string) error {
+	model, err := toSearchIndexModel(index)
+	if err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+		if viewerID == "" {
+			return nil
+		}
+		var count int64
+		if err := viewerScope(tx.Model(&models.SearchIndex{}).Where("id = ?", model.ID), viewerID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return errors.ErrForbidden.WithDetails("无权在该空间或 Base 下创建搜索索引")
+		}
+		return nil
+	})
+}
+
+// GetIndex 获取搜索索引
+func (r *SearchRepositoryImpl) GetIndex(ctx context.Context, id, viewerID string) (*search.SearchIndex, error) {
+	var model models.SearchIndex
+	err := viewerScope(r.db.WithContext(ctx).Where("id = ?", id), viewerID).First(&model).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get search index: %w", err)
+	}
+	return toSearchIndexEntity(&model), nil
+}
+
+// UpdateIndex 更新搜索索引
+func (r *SearchRepositoryImpl) UpdateIndex(ctx context.Context, index *search.SearchIndex, viewerID string) error {
+	model, err := toSearchIndexModel(index)
+	if err != nil {
+		return err
+	}
+	result := viewerScope(r.db.WithContext(ctx).Model(&models.SearchIndex{}).Where("id = ?", index.ID), viewerID).
+		Select("title", "content", "keywords", "source_url", "metadata", "permissions", "tags", "updated_time").
+		Updates(model)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+// DeleteIndex 删除搜索索引
+func (r *SearchRepositoryImpl) DeleteIndex(ctx context.Context, id, viewerID string) error {
+	result := viewerScope(r.db.WithContext(ctx).Where("id = ?", id), viewerID).Delete(&models.SearchIndex{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+// DeleteIndexesBySource 根据来源删除搜索索引
+func (r *SearchRepositoryImpl) DeleteIndexesBySource(ctx context.Context, sourceID, sourceType, viewerID string) error {
+	query := r.db.WithContext(ctx).Where("source_id = ? AND source_type = ?", sourceID, sourceType)
+	return viewerScope(query, viewerID).Delete(&models.SearchIndex{}).Error
+}
+
+// UpsertIndexes 按来源批量写入或覆盖搜索索引（已存在的条目保留原ID与创建时间）
+func (r *SearchRepositoryImpl) UpsertIndexes(ctx context.Context, indexes []*search.SearchIndex) error {
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	// 同一来源只保留最后一条，避免同一语句内重复冲突
+	batch := make([]*models.SearchIndex, 0, len(indexes))
+	positions := make(map[string]int, len(indexes))
+	for _, index := range indexes {
+		model, err := toSearchIndexModel(index)
+		if err != nil {
+			return err
+		}
+		key := model.SourceType + "\x00" + model.SourceID
+		if i, ok := positions[key]; ok {
+			batch[i] = model
+			continue
+		}
+		positions[key] = len(batch)
+		batch = append(batch, model)
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for start := 0; start < len(batch); start += searchRebuildBatchSize {
+			end := min(start+searchRebuildBatchSize, len(batch))
+			if err := upsertSearchIndexModels(tx, batch[start:end]); err != nil {
+				return fmt.Errorf("failed to upsert search indexes: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteIndexesBySources 批量删除同一来源类型的搜索索引
+func (r *SearchRepositoryImpl) DeleteIndexesBySources(ctx context.Context, sourceType string, sourceIDs []string) error {
+	if len(sourceIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Where("source_type = ? AND source_id IN ?", sourceType, sourceIDs).
+		Delete(&models.SearchIndex{}).Error
+}
+
+// DeleteIndexesByTable 删除表格自身及其字段、记录等全部搜索索引
+func (r *SearchRepositoryImpl) DeleteIndexesByTable(ctx context.Context, tableID string) error {
+	return r.db.WithContext(ctx).
+		Where("table_id = ?", tableID).
+		Delete(&models.SearchIndex{}).Error
+}
+
+// DeleteStaleIndexes 删除 Base（或其中一个表格）内 before 之后未再写入的搜索索引
+//
+// 只清理索引器维护的来源类型（Base、表格、字段、记录），手动创建的其他条目不受影响
+func (r *SearchRepositoryImpl) DeleteStaleIndexes(ctx context.Context, baseID, tableID string, before time.Time) error {
+	query := r.db.WithContext(ctx).
+		Where("base_id = ? AND source_type IN ? AND updated_time < ?", baseID, indexedSourceTypes, before)
+	if tableID != "" {
+		query = query.Where("table_id = ?", tableID)
+	}
+	return query.Delete(&models.SearchIndex{}).Error
+}
+
+// ListIndexes 列出搜索索引（按更新时间倒序）
+func (r *SearchRepositoryImpl) ListIndexes(ctx context.Context, searchType *search.SearchType, sourceID, sourceType, viewerID string, page, pageSize int) ([]*search.SearchIndex, int64, error) {
+	query := viewerScope(r.db.WithContext(ctx).Model(&models.SearchIndex{}), viewerID)
+	if searchType != nil && *searchType != "" {
+		query = query.Where("type = ?", string(*searchType))
+	}
+	if sourceID != "" {
+		query = query.Where("source_id = ?", sourceID)
+	}
+	if sourceType != "" {
+		query = query.Where("source_type = ?", sourceType)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count search indexes: %w", err)
+	}
+
+	page, pageSize = normalizeSearchPage(page, pageSize)
+	var rows []models.SearchIndex
+	if err := query.Order("updated_time DESC, id").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&rows).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list search indexes: %w", err)
+	}
+
+	indexes := make([]*search.SearchIndex, len(rows))
+	for i := range rows {
+		indexes[i] = toSearchIndexEntity(&rows[i])
+	}
+	return indexes, total, nil
+}
+
+// ==================== 搜索 ====================
+
+// Search 搜索
+func (r *SearchRepositoryImpl) Search(ctx context.Context, req *search.SearchRequest) (*search.SearchResponse, error) {
+	cond := &searchCondition{}
+	cond.addMatch(req.Query, req.Scope)
+	cond.addContext(searchContext{
+		Type:       req.Type,
+		SourceID:   req.SourceID,
+		SourceType: req.SourceType,
+		UserID:     req.UserID,
+		SpaceID:    req.SpaceID,
+		BaseID:     req.BaseID,
+		TableID:    req.TableID,
+		FieldIDs:   req.FieldIDs,
+		ViewerID:   req.ViewerID,
+	})
+	if err := cond.addEqualityFilters(req.Filters); err != nil {
+		return nil, err
+	}
+
+	order, err := searchOrder(req.SortBy, req.SortOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := r.runSearch(ctx, cond, []string{req.Query}, order, req.Page, req.PageSize, req.Highlight)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Suggestions {
+		suggestions, err := r.SearchSuggestions(ctx, req.Query, req.ViewerID, 5)
+		if err == nil {
+			for _, suggestion := range suggestions {
+				response.Suggestions = append(response.Suggestions, suggestion.Query)
+			}
+		}
+	}
+	return response, nil
+}
+
+// AdvancedSearch 高级搜索：多个关键词须同时命中，支持运算符过滤、多键排序与分面统计
+func (r *SearchRepositoryImpl) AdvancedSearch(ctx context.Context, req *search.AdvancedSearchRequest) (*search.SearchResponse, error) {
+	cond := &searchCondition{}
+	queries := make([]string, 0, len(req.Queries))
+	for _, query := range req.Queries {
+		if query == "" {
+			continue
+		}
+		cond.addMatch(query, req.Scope)
+		queries = append(queries, query)
+	}
+	if len(queries) == 0 {
+		return nil, errors.ErrBadRequest.WithDetails("搜索关键词不能为空")
+	}
+	cond.addContext(searchContext{
+		Type:       req.Type,
+		SourceID:   req.SourceID,
+		SourceType: req.SourceType,
+		UserID:     req.UserID,
+		SpaceID:    req.SpaceID,
+		BaseID:     req.BaseID,
+		TableID:    req.TableID,
+		FieldIDs:   req.FieldIDs,
+		ViewerID:   req.ViewerID,
+	})
+	for _, filter := range req.Filters {
+		if err := cond.addFilter(filter); err != nil {
+			return nil, err
+		}
+	}
+
+	orders := make([]string, 0, len(req.Sorts)+1)
+	for _, sort := range req.Sorts {
+		order, err := searchOrder(sort.Field, sort.Order)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	if len(orders) == 0 {
+		orders = append(orders, "score DESC")
+	}
+
+	response, err := r.runSearch(ctx, cond, queries, strings.Join(orders, ", "), req.Page, req.PageSize, req.Highlight)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Facets) > 0 {
+		facets, err := r.searchFacets(ctx, cond, req.Facets)
+		if err != nil {
+			return nil, err
+		}
+		response.Facets = facets
+	}
+	return response, nil
+}
+
+// runSearch 执行搜索查询：统计总数、按得分排序分页并转换为结果
+func (r *SearchRepositoryImpl) runSearch(ctx context.Context, cond *searchCondition, queries []string, order string, page, pageSize int, highlight bool) (*search.SearchResponse, error) {
+	query := cond.apply(r.db.WithContext(ctx).Model(&models.SearchIndex{}))
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	page, pageSize = normalizeSearchPage(page, pageSize)
+	scoreSQL, scoreArgs := searchScoreExpr(queries)
+	var hits []searchIndexHit
+	if err := query.Select("search_indexes.*, ("+scoreSQL+") AS score", scoreArgs...).
+		Order(order + ", search_indexes.updated_time DESC, search_indexes.id").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&hits).Error; err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+
+	var highlighter *regexp.Regexp
+	if highlight {
+		highlighter = searchHighlighter(queries)
+	}
+	results := make([]*search.SearchResult, len(hits))
+	for i := range hits {
+		results[i] = toSearchResult(&hits[i], highlighter)
+	}
+
+	return &search.SearchResponse{
+		Results:    results,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: int((total + int64(pageSize) - 1) / int64(pageSize)),
+	}, nil
+}
+
+// searchFacets 按列分组统计命中数
+func (r *SearchRepositoryImpl) searchFacets(ctx context.Context, cond *searchCondition, facets []string) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(facets))
+	for _, facet := range facets {
+		column, ok := searchFilterColumns[facet]
+		if !ok || facet == "title" || strings.HasSuffix(facet, "_time") {
+			return nil, errors.ErrBadRequest.WithDetails(fmt.Sprintf("不支持的分面字段: %s", facet))
+		}
+
+		var rows []struct {
+			Value string
+			Count int64
+		}
+		query := cond.apply(r.db.WithContext(ctx).Model(&models.SearchIndex{}))
+		if err := query.Select("COALESCE(" + column + ", '') AS value, COUNT(*) AS count").
+			Group("value").Order("count DESC").
+			Scan(&rows).Error; err != nil {
+			return nil, fmt.Errorf("failed to count search facet %s: %w", facet, err)
+		}
+
+		counts := make(map[string]int64, len(rows))
+		for _, row := range rows {
+			counts[row.Value] = row.Count
+		}
+		result[facet] = counts
+	}
+	return result, nil
+}
+
+// SearchSuggestions 搜索建议：标题以关键词开头或包含关键词的条目，按标题合并后以开头匹配优先、次数倒序
+// 查询历史属于全部用户，不用于建议；viewerID 非空时只统计其可访问的条目（与搜索的访问控制相同）
+func (r *SearchRepositoryImpl) SearchSuggestions(ctx context.Context, query, viewerID string, limit int) ([]*search.SearchSuggestion, error) {
+	var rows []struct {
+		Title string
+		Type  string
+		Count int64
+	}
+	err := viewerScope(r.db.WithContext(ctx).Model(&models.SearchIndex{}), viewerID).
+		Select("search_indexes.title AS title, MIN(search_indexes.type) AS type, COUNT(*) AS count").
+		Where("search_indexes.title ILIKE ?", likePattern(query)).
+		Group("search_indexes.title").
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "CASE WHEN search_indexes.title ILIKE ? THEN 0 ELSE 1 END, count DESC, title",
+			Vars:               []interface{}{strings.TrimPrefix(likePattern(query), "%")},
+			WithoutParentheses: true,
+		}}).
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get search suggestions: %w", err)
+	}
+
+	suggestions := make([]*search.SearchSuggestion, len(rows))
+	for i, row := range rows {
+		suggestions[i] = &search.SearchSuggestion{
+			Query: row.Title,
+			Count: row.Count,
+			Type:  search.SearchType(row.Type),
+		}
+	}
+	return suggestions, nil
+}
+
+// GetPopularQueries 获取热门查询
+func (r *SearchRepositoryImpl) GetPopularQueries(ctx context.Context, limit int) ([]*search.SearchSuggestion, error) {
+	var rows []models.SearchSuggestion
+	if err := r.db.WithContext(ctx).
+		Order("count DESC, updated_time DESC").
+		Limit(limit).
+		Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get popular queries: %w", err)
+	}
+	return toSearchSuggestions(rows), nil
+}
+
+// ==================== 统计 ====================
+
+// GetSearchStats 获取搜索统计
+func (r *SearchRepositoryImpl) GetSearchStats(ctx context.Context) (*search.SearchStats, error) {
+	stats := &search.SearchStats{
+		SearchByType:   make(map[search.SearchType]int64),
+		SearchByScope:  make(map[search.SearchScope]int64),
+		TopResults:     []*search.SearchResult{},
+		RecentSearches: []*search.SearchRequest{},
+	}
+
+	var counters []models.SearchStats
+	if err := r.db.WithContext(ctx).Find(&counters).Error; err != nil {
+		return nil, fmt.Errorf("failed to get search stats: %w", err)
+	}
+	for _, counter := range counters {
+		switch {
+		case counter.ID == "total":
+			stats.TotalSearches = counter.TotalSearches
+			stats.AverageQueryTime = counter.AverageQueryTime
+		case strings.HasPrefix(counter.ID, "type:"):
+			stats.SearchByType[search.SearchType(strings.TrimPrefix(counter.ID, "type:"))] = counter.TotalSearches
+		case strings.HasPrefix(counter.ID, "scope:"):
+			stats.SearchByScope[search.SearchScope(strings.TrimPrefix(counter.ID, "scope:"))] = counter.TotalSearches
+		}
+	}
+
+	if err := r.db.WithContext(ctx).Model(&models.SearchIndex{}).Count(&stats.TotalIndexes).Error; err != nil {
+		return nil, fmt.Errorf("failed to count search indexes: %w", err)
+	}
+
+	popular, err := r.GetPopularQueries(ctx, 10)
+	if err != nil {
+		return nil, err
+	}
+	stats.PopularQueries = popular
+	return stats, nil
+}
+
+// IncrementSearchCount 增加搜索计数：累加查询词的搜索建议次数，以及总数/类型/范围计数器
+func (r *SearchRepositoryImpl) IncrementSearchCount(ctx context.Context, query string, searchType search.SearchType, scope search.SearchScope) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if query = truncateRunes(query, maxSuggestionQueryLength); query != "" {
+			suggestion := &models.SearchSuggestion{
+				ID:          utils.GenerateNanoID(10),
+				Query:       query,
+				Count:       1,
+				Type:        string(searchType),
+				CreatedTime: now,
+				UpdatedTime: now,
+			}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "query"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"count":        gorm.Expr("search_suggestions.count + 1"),
+					"type":         string(searchType),
+					"updated_time": now,
+				}),
+			}).Create(suggestion).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, id := range []string{"total", "type:" + string(searchType), "scope:" + string(scope)} {
+			// 计数器ID列宽20，忽略客户端传入的超长类型
+			if len(id) > 20 {
+				continue
+			}
+			counter := &models.SearchStats{ID: id, TotalSearches: 1, LastUpdated: now}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "id"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"total_searches": gorm.Expr("search_stats.total_searches + 1"),
+					"last_updated":   now,
+				}),
+			}).Create(counter).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ==================== 索引维护 ====================
+
+// RebuildIndex 由元数据表重建空间、Base、表格、字段索引；searchType 为空时重建全部
+//
+// 按来源 upsert 后删除本次未出现的旧条目，重建期间搜索不中断
+func (r *SearchRepositoryImpl) RebuildIndex(ctx context.Context, searchType *search.SearchType) error {
+	types := rebuildableSearchTypes
+	if searchType != nil && *searchType != "" {
+		if _, ok := searchSourceQueries[*searchType]; !ok {
+			return errors.ErrBadRequest.WithDetails(fmt.Sprintf("不支持重建该类型的索引: %s", *searchType))
+		}
+		types = []search.SearchType{*searchType}
+	}
+
+	for _, t := range types {
+		if err := r.rebuildType(ctx, t); err != nil {
+			return fmt.Errorf("failed to rebuild %s indexes: %w", t, err)
+		}
+	}
+	return nil
+}
+
+// rebuildType 重建单个类型的索引
+func (r *SearchRepositoryImpl) rebuildType(ctx context.Context, searchType search.SearchType) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var rows []searchSourceRow
+		if err := tx.Raw(searchSourceQueries[searchType]).Scan(&rows).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		batch := make([]*models.SearchIndex, 0, searchRebuildBatchSize)
+		flush := func() error {
+			err := upsertSearchIndexModels(tx, batch)
+			batch = batch[:0]
+			return err
+		}
+
+		for _, row := range rows {
+			index := search.NewSearchIndex(searchType, row.Title, row.Content)
+			index.SetSource(row.SourceID, string(searchType), "")
+			index.SetContext(row.UserID, row.SpaceID, row.TableID, row.FieldID)
+			index.SetBase(row.BaseID)
+			if row.FieldType != "" {
+				index.SetMetadata("field_type", row.FieldType)
+			}
+			index.CreatedTime = now
+			index.UpdatedTime = now
+
+			model, err := toSearchIndexModel(index)
+			if err != nil {
+				return err
+			}
+			batch = append(batch, model)
+			if len(batch) >= searchRebuildBatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+		if err := flush(); err != nil {
+			return err
+		}
+
+		// 删除来源已不存在的旧条目
+		return tx.Where("source_type = ? AND updated_time < ?", string(searchType), now).
+			Delete(&models.SearchIndex{}).Error
+	})
+}
+
+// upsertSearchIndexModels 按来源（source_type + source_id）写入或覆盖一批索引
+func upsertSearchIndexModels(tx *gorm.DB, batch []*models.SearchIndex) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "source_type"}, {Name: "source_id"}},
+		DoUpdates: clause.AssignmentColumns(searchUpsertColumns),
+	}).Create(&batch).Error
+}
+
+// OptimizeIndex 清理来源已删除的索引和过期的搜索建议，并更新统计信息
+func (r *SearchRepositoryImpl) OptimizeIndex(ctx context.Context) error {
+	db := r.db.WithContext(ctx)
+	orphanQueries := []string{
+		`DELETE FROM search_indexes si WHERE si.source_type = 'space'
+			AND NOT EXISTS (SELECT 1 FROM space s WHERE s.id = si.source_id AND s.deleted_time IS NULL)`,
+		`DELETE FROM search_indexes si WHERE si.source_type = 'base'
+			AND NOT EXISTS (SELECT 1 FROM base b WHERE b.id = si.source_id AND b.deleted_time IS NULL)`,
+		`DELETE FROM search_indexes si WHERE si.source_type = 'table'
+			AND NOT EXISTS (SELECT 1 FROM table_meta t WHERE t.id = si.source_id AND t.deleted_time IS NULL)`,
+		`DELETE FROM search_indexes si WHERE si.source_type = 'field'
+			AND NOT EXISTS (SELECT 1 FROM field f WHERE f.id = si.source_id AND f.deleted_time IS NULL)`,
+		// 其他来源（记录、评论、附件等）随所属表格删除
+		`DELETE FROM search_indexes si WHERE si.source_type NOT IN ('space', 'base', 'table', 'field')
+			AND COALESCE(si.table_id, '') <> ''
+			AND NOT EXISTS (SELECT 1 FROM table_meta t WHERE t.id = si.table_id AND t.deleted_time IS NULL)`,
+	}
+	for _, sql := range orphanQueries {
+		if err := db.Exec(sql).Error; err != nil {
+			return fmt.Errorf("failed to clean orphan search indexes: %w", err)
+		}
+	}
+
+	if err := db.Where("count <= 1 AND updated_time < ?", time.Now().Add(-staleSuggestionAge)).
+		Delete(&models.SearchSuggestion{}).Error; err != nil {
+		return fmt.Errorf("failed to clean stale search suggestions: %w", err)
+	}
+
+	for _, table := range []string{"search_indexes", "search_suggestions"} {
+		if err := db.Exec("ANALYZE " + table).Error; err != nil {
+			return fmt.Errorf("failed to analyze %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// GetIndexStats 获取索引统计：总数、各类型数量、最近更新时间与存储大小
+func (r *SearchRepositoryImpl) GetIndexStats(ctx context.Context) (map[string]interface{}, error) {
+	db := r.db.WithContext(ctx)
+
+	var byType []struct {
+		Type  string
+		Count int64
+	}
+	if err := db.Model(&models.SearchIndex{}).
+		Select("type, COUNT(*) AS count").Group("type").
+		Scan(&byType).Error; err != nil {
+		return nil, fmt.Errorf("failed to count search indexes by type: %w", err)
+	}
+
+	var total int64
+	counts := make(map[string]int64, len(byType))
+	for _, row := range byType {
+		counts[row.Type] = row.Count
+		total += row.Count
+	}
+
+	var lastUpdated *time.Time
+	if err := db.Model(&models.SearchIndex{}).Select("MAX(updated_time)").Scan(&lastUpdated).Error; err != nil {
+		return nil, fmt.Errorf("failed to get search index update time: %w", err)
+	}
+
+	var sizeBytes int64
+	if err := db.Raw("SELECT pg_total_relation_size('search_indexes')").Scan(&sizeBytes).Error; err != nil {
+		return nil, fmt.Errorf("failed to get search index size: %w", err)
+	}
+
+	return map[string]interface{}{
+		"total_indexes": total,
+		"by_type":       counts,
+		"last_updated":  lastUpdated,
+		"size_bytes":    sizeBytes,
+	}, nil
+}
+
+// ==================== 查询条件 ====================
+
+// searchContext 搜索范围限定
+type searchContext struct {
+	Type       search.SearchType
+	SourceID   string
+	SourceType string
+	UserID     string
+	SpaceID    string
+	BaseID     string
+	TableID    string
+	FieldIDs   []string
+	ViewerID   string
+}
+
+// searchCondition 搜索 WHERE 条件（各条件之间为 AND）
+type searchCondition struct {
+	clauses []string
+	args    [][]interface{}
+}
+
+// add 追加一个条件
+func (c *searchCondition) add(sql string, args ...interface{}) {
+	c.clauses = append(c.clauses, sql)
+	c.args = append(c.args, args)
+}
+
+// apply 将条件应用到查询
+func (c *searchCondition) apply(db *gorm.DB) *gorm.DB {
+	for i, sql := range c.clauses {
+		db = db.Where(sql, c.args[i]...)
+	}
+	return db
+}
+
+// addMatch 关键词匹配条件
+func (c *searchCondition) addMatch(query string, scope search.SearchScope) {
+	pattern := likePattern(query)
+	pinyinPattern := fieldService.PinyinPattern(query)
+
+	titleConds := []string{"search_indexes.title ILIKE ?"}
+	titleArgs := []interface{}{pattern}
+	if pinyinPattern != "" {
+		titleConds = append(titleConds, "search_indexes.title ~ ?")
+		titleArgs = append(titleArgs, pinyinPattern)
+	}
+
+	switch scope {
+	case search.SearchScopeTitle:
+		c.add("("+strings.Join(titleConds, " OR ")+")", titleArgs...)
+		return
+	case search.SearchScopeContent:
+		c.add("search_indexes.content ILIKE ?", pattern)
+		return
+	case search.SearchScopeMetadata:
+		c.add("search_indexes.metadata::text ILIKE ?", pattern)
+		return
+	case search.SearchScopeComments:
+		c.add("search_indexes.type = ?", string(search.SearchTypeComment))
+	case search.SearchScopeAttachments:
+		c.add("search_indexes.type = ?", string(search.SearchTypeAttachment))
+	}
+
+	conds := append([]string{searchDocumentExpr + " @@ plainto_tsquery('simple', ?)"}, titleConds...)
+	conds = append(conds, "search_indexes.content ILIKE ?", "search_indexes.keywords::text ILIKE ?")
+	args := append([]interface{}{query}, titleArgs...)
+	args = append(args, pattern, pattern)
+	c.add("("+strings.Join(conds, " OR ")+")", args...)
+}
+
+// addContext 范围限定条件与访问控制
+func (c *searchCondition) addContext(sc searchContext) {
+	if sc.Type != "" && sc.Type != search.SearchTypeGlobal {
+		c.add("search_indexes.type = ?", string(sc.Type))
+	}
+	if sc.SourceID != "" {
+		c.add("search_indexes.source_id = ?", sc.SourceID)
+	}
+	if sc.SourceType != "" {
+		c.add("search_indexes.source_type = ?", sc.SourceType)
+	}
+	if sc.UserID != "" {
+		c.add("search_indexes.user_id = ?", sc.UserID)
+	}
+	if sc.SpaceID != "" {
+		c.add("search_indexes.space_id = ?", sc.SpaceID)
+	}
+	if sc.BaseID != "" {
+		c.add("search_indexes.base_id = ?", sc.BaseID)
+	}
+	if sc.TableID != "" {
+		c.add("search_indexes.table_id = ?", sc.TableID)
+	}
+	if len(sc.FieldIDs) > 0 {
+		c.add("search_indexes.field_id IN ?", sc.FieldIDs)
+	}
+	if sc.ViewerID != "" {
+		c.addAccess(sc.ViewerID)
+	}
+}
+
+// addAccess 只保留用户可访问的条目：
+// 所属空间由其创建或为其协作者（空间成员可访问空间内所有 Base），所属 Base 由其创建或为其协作者，
+// 或不属于任何空间/Base 的个人条目
+func (c *searchCondition) addAccess(viewerID string) {
+	spaceType := string(collaboratorEntity.ResourceTypeSpace)
+	baseType := string(collaboratorEntity.ResourceTypeBase)
+	userType := string(collaboratorEntity.PrincipalTypeUser)
+	c.add(`(search_indexes.space_id IN (
+			SELECT id FROM space WHERE created_by = ? AND deleted_time IS NULL
+			UNION SELECT resource_id FROM collaborators WHERE principal_id = ? AND principal_type = ? AND resource_type = ?)
+		OR search_indexes.base_id IN (
+			SELECT id FROM base WHERE created_by = ? AND deleted_time IS NULL
+			UNION SELECT resource_id FROM collaborators WHERE principal_id = ? AND principal_type = ? AND resource_type = ?)
+		OR (COALESCE(search_indexes.space_id, '') = '' AND COALESCE(search_indexes.base_id, '') = '' AND search_indexes.user_id = ?))`,
+		viewerID, viewerID, userType, spaceType,
+		viewerID, viewerID, userType, baseType,
+		viewerID)
+}
+
+// viewerScope viewerID 非空时只保留其可访问的条目（与搜索的访问控制相同）
+func viewerScope(db *gorm.DB, viewerID string) *gorm.DB {
+	if viewerID == "" {
+		return db
+	}
+	cond := &searchCondition{}
+	cond.addAccess(viewerID)
+	return cond.apply(db)
+}
+
+// addEqualityFilters 键值过滤（值为数组时匹配其中任意一个）
+func (c *searchCondition) addEqualityFilters(filters map[string]interface{}) error {
+	for key, value := range filters {
+		operator := "eq"
+		if _, ok := value.([]interface{}); ok {
+			operator = "in"
+		}
+		if err := c.addFilter(search.SearchFilter{Field: key, Operator: operator, Value: value}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addFilter 运算符过滤
+func (c *searchCondition) addFilter(filter search.SearchFilter) error {
+	column, ok := searchFilterColumns[filter.Field]
+	if !ok {
+		return errors.ErrBadRequest.WithDetails(fmt.Sprintf("不支持的过滤字段: %s", filter.Field))
+	}
+
+	switch filter.Operator {
+	case "eq", "":
+		c.add(column+" = ?", filter.Value)
+	case "ne":
+		c.add(column+" <> ?", filter.Value)
+	case "gt":
+		c.add(column+" > ?", filter.Value)
+	case "gte":
+		c.add(column+" >= ?", filter.Value)
+	case "lt":
+		c.add(column+" < ?", filter.Value)
+	case "lte":
+		c.add(column+" <= ?", filter.Value)
+	case "in", "nin":
+		values, ok := filter.Value.([]interface{})
+		if !ok || len(values) == 0 {
+			return errors.ErrBadRequest.WithDetails(fmt.Sprintf("过滤字段 %s 的 %s 运算需要非空数组", filter.Field, filter.Operator))
+		}
+		if filter.Operator == "in" {
+			c.add(column+" IN ?", values)
+		} else {
+			c.add(column+" NOT IN ?", values)
+		}
+	case "like":
+		c.add(column+" ILIKE ?", likePattern(fmt.Sprint(filter.Value)))
+	case "regex":
+		c.add(column+" ~ ?", fmt.Sprint(filter.Value))
+	default:
+		return errors.ErrBadRequest.WithDetails(fmt.Sprintf("不支持的过滤运算符: %s", filter.Operator))
+	}
+	return nil
+}
+
+// searchScoreExpr 得分表达式：全文检索相关度，标题包含关键词加 1，标题与关键词完全相同再加 2
+func searchScoreExpr(queries []string) (string, []interface{}) {
+	parts := make([]string, 0, len(queries))
+	args := make([]interface{}, 0, len(queries)*3)
+	for _, query := range queries {
+		parts = append(parts, "ts_rank("+searchDocumentExpr+", plainto_tsquery('simple', ?))"+
+			" + CASE WHEN search_indexes.title ILIKE ? THEN 1 ELSE 0 END"+
+			" + CASE WHEN lower(search_indexes.title) = lower(?) THEN 2 ELSE 0 END")
+		args = append(args, query, likePattern(query), query)
+	}
+	return strings.Join(parts, " + "), args
+}
+
+// searchOrder 排序子句，sortBy 为 score 或允许的列
+func searchOrder(sortBy, sortOrder string) (string, error) {
+	direction := "DESC"
+	switch strings.ToLower(sortOrder) {
+	case "asc":
+		direction = "ASC"
+	case "desc", "":
+	default:
+		return "", errors.ErrBadRequest.WithDetails(fmt.Sprintf("不支持的排序方向: %s", sortOrder))
+	}
+
+	if sortBy == "" || sortBy == "score" {
+		return "score " + direction, nil
+	}
+	column, ok := searchFilterColumns[sortBy]
+	if !ok {
+		return "", errors.ErrBadRequest.WithDetails(fmt.Sprintf("不支持的排序字段: %s", sortBy))
+	}
+	return column + " " + direction, nil
+}
+
+// normalizeSearchPage 规范化分页参数
+func normalizeSearchPage(page, pageSize int) (int, int) {
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > maxSearchPageSize {
+		pageSize = maxSearchPageSize
+	}
+	return page, pageSize
+}
+
+// ==================== 高亮 ====================
+
+// searchHighlighter 匹配任一关键词（不区分大小写）或其拼音对应汉字的正则
+func searchHighlighter(queries []string) *regexp.Regexp {
+	alternatives := make([]string, 0, len(queries))
+	for _, query := range queries {
+		alternatives = append(alternatives, regexp.QuoteMeta(query))
+		if pattern := fieldService.PinyinPattern(query); pattern != "" {
+			alternatives = append(alternatives, pattern)
+		}
+	}
+	re, err := regexp.Compile("(?i)" + strings.Join(alternatives, "|"))
+	if err != nil {
+		return nil
+	}
+	return re
+}
+
+// highlightTitle 用 <em> 标记标题中的全部命中
+func highlightTitle(re *regexp.Regexp, title string) (string, bool) {
+	if !re.MatchString(title) {
+		return "", false
+	}
+	return re.ReplaceAllString(title, "<em>$0</em>"), true
+}
+
+// highlightSnippet 截取内容中首个命中附近的片段并标记命中
+func highlightSnippet(re *regexp.Regexp, content string) (string, bool) {
+	loc := re.FindStringIndex(content)
+	if loc == nil {
+		return "", false
+	}
+
+	runes := []rune(content)
+	start := len([]rune(content[:loc[0]]))
+	end := start + len([]rune(content[loc[0]:loc[1]]))
+	from := max(start-searchSnippetRadius, 0)
+	to := min(end+searchSnippetRadius, len(runes))
+
+	snippet := re.ReplaceAllString(string(runes[from:to]), "<em>$0</em>")
+	if from > 0 {
+		snippet = "..." + snippet
+	}
+	if to < len(runes) {
+		snippet += "..."
+	}
+	return snippet, true
+}
+
+// ==================== 转换 ====================
+
+// toSearchIndexModel 领域实体转数据库模型
+func toSearchIndexModel(index *search.SearchIndex) (*models.SearchIndex, error) {
+	keywords, err := marshalSearchJSON(index.Keywords, "[]")
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := marshalSearchJSON(index.Metadata, "{}")
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := marshalSearchJSON(index.Permissions, "[]")
+	if err != nil {
+		return nil, err
+	}
+	tags, err := marshalSearchJSON(index.Tags, "[]")
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SearchIndex{
+		ID:          index.ID,
+		Type:        string(index.Type),
+		Title:       truncateRunes(index.Title, 500),
+		Content:     index.Content,
+		Keywords:    keywords,
+		SourceID:    index.SourceID,
+		SourceType:  index.SourceType,
+		SourceURL:   index.SourceURL,
+		Metadata:    metadata,
+		UserID:      index.UserID,
+		SpaceID:     index.SpaceID,
+		BaseID:      index.BaseID,
+		TableID:     index.TableID,
+		FieldID:     index.FieldID,
+		Permissions: permissions,
+		Tags:        tags,
+		CreatedTime: index.CreatedTime,
+		UpdatedTime: index.UpdatedTime,
+	}, nil
+}
+
+// toSearchIndexEntity 数据库模型转领域实体
+func toSearchIndexEntity(model *models.SearchIndex) *search.SearchIndex {
+	index := &search.SearchIndex{
+		ID:          model.ID,
+		Type:        search.SearchType(model.Type),
+		Title:       model.Title,
+		Content:     model.Content,
+		Keywords:    []string{},
+		SourceID:    model.SourceID,
+		SourceType:  model.SourceType,
+		SourceURL:   model.SourceURL,
+		Metadata:    map[string]interface{}{},
+		UserID:      model.UserID,
+		SpaceID:     model.SpaceID,
+		BaseID:      model.BaseID,
+		TableID:     model.TableID,
+		FieldID:     model.FieldID,
+		Permissions: []string{},
+		Tags:        []string{},
+		CreatedTime: model.CreatedTime,
+		UpdatedTime: model.UpdatedTime,
+	}
+	unmarshalSearchJSON(model.Keywords, &index.Keywords)
+	unmarshalSearchJSON(model.Metadata, &index.Metadata)
+	unmarshalSearchJSON(model.Permissions, &index.Permissions)
+	unmarshalSearchJSON(model.Tags, &index.Tags)
+	return index
+}
+
+// toSearchResult 搜索结果行转结果；元数据中补充所属空间/Base/表格/字段，便于客户端跳转
+func toSearchResult(hit *searchIndexHit, highlighter *regexp.Regexp) *search.SearchResult {
+	index := toSearchIndexEntity(&hit.SearchIndex)
+	metadata := index.Metadata
+	for key, value := range map[string]string{
+		"space_id": index.SpaceID,
+		"base_id":  index.BaseID,
+		"table_id": index.TableID,
+		"field_id": index.FieldID,
+	} {
+		if value != "" {
+			metadata[key] = value
+		}
+	}
+
+	result := &search.SearchResult{
+		ID:          index.ID,
+		Type:        index.Type,
+		Title:       index.Title,
+		Content:     index.Content,
+		Score:       hit.Score,
+		SourceID:    index.SourceID,
+		SourceType:  index.SourceType,
+		SourceURL:   index.SourceURL,
+		Metadata:    metadata,
+		CreatedTime: index.CreatedTime,
+		UpdatedTime: index.UpdatedTime,
+	}
+
+	if highlighter != nil {
+		highlight := make(map[string]interface{})
+		if title, ok := highlightTitle(highlighter, index.Title); ok {
+			highlight["title"] = title
+		}
+		if snippet, ok := highlightSnippet(highlighter, index.Content); ok {
+			highlight["content"] = snippet
+		}
+		if len(highlight) > 0 {
+			result.Highlight = highlight
+		}
+	}
+	return result
+}
+
+// toSearchSuggestions 搜索建议模型转领域实体
+func toSearchSuggestions(rows []models.SearchSuggestion) []*search.SearchSuggestion {
+	suggestions := make([]*search.SearchSuggestion, len(rows))
+	for i, row := range rows {
+		suggestions[i] = &search.SearchSuggestion{
+			ID:          row.ID,
+			Query:       row.Query,
+			Count:       row.Count,
+			Type:        search.SearchType(row.Type),
+			SourceID:    row.SourceID,
+			SourceType:  row.SourceType,
+			CreatedTime: row.CreatedTime,
+			UpdatedTime: row.UpdatedTime,
+		}
+	}
+	return suggestions
+}
+
+// marshalSearchJSON 序列化 JSON 列，空值使用默认值
+func marshalSearchJSON(value interface{}, empty string) (string, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal search index column: %w", err)
+	}
+	if string(raw) == "null" {
+		return empty, nil
+	}
+	return string(raw), nil
+}
+
+// unmarshalSearchJSON 反序列化 JSON 列，内容无效时保持默认值
+func unmarshalSearchJSON(raw string, target interface{}) {
+	if raw == "" {
+		return
+	}
+	_ = json.Unmarshal([]byte(raw), target)
+}
+
+// truncateRunes 按字符截断
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/search"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+	"github.com/easyspace-ai/luckdb/server/pkg/errors"
+)
+
+func TestSearchCondition_Match(t *testing.T) {
+	cond := &searchCondition{}
+	cond.addMatch("50%", search.SearchScopeAll)
+	require.Len(t, cond.clauses, 1)
+	assert.Contains(t, cond.clauses[0], "@@ plainto_tsquery('simple', ?)")
+	assert.NotContains(t, cond.clauses[0], " ~ ?")
+	assert.Equal(t, []interface{}{"50%", `%50\%%`, `%50\%%`, `%50\%%`}, cond.args[0])
+
+	// 拼音关键词同时按同音汉字匹配标题
+	cond = &searchCondition{}
+	cond.addMatch("kehu", search.SearchScopeTitle)
+	require.Len(t, cond.clauses, 1)
+	assert.Equal(t, "(search_indexes.title ILIKE ? OR search_indexes.title ~ ?)", cond.clauses[0])
+	assert.Contains(t, cond.args[0][1], "客")
+}
+
+func TestSearchCondition_ContextAndAccess(t *testing.T) {
+	cond := &searchCondition{}
+	cond.addContext(searchContext{Type: search.SearchTypeGlobal, BaseID: "bse_1", ViewerID: "usr_1"})
+	require.Len(t, cond.clauses, 2)
+	assert.Equal(t, "search_indexes.base_id = ?", cond.clauses[0])
+	assert.Contains(t, cond.clauses[1], "FROM collaborators")
+	assert.Equal(t, []interface{}{"usr_1", "usr_1", "user", "space", "usr_1", "usr_1", "user", "base", "usr_1"}, cond.args[1])
+}
+
+func TestSearchCondition_Filters(t *testing.T) {
+	cond := &searchCondition{}
+	require.NoError(t, cond.addFilter(search.SearchFilter{Field: "type", Operator: "in", Value: []interface{}{"table", "field"}}))
+	require.NoError(t, cond.addEqualityFilters(map[string]interface{}{"space_id": "spc_1"}))
+	assert.Equal(t, []string{"search_indexes.type IN ?", "search_indexes.space_id = ?"}, cond.clauses)
+
+	assert.Error(t, cond.addFilter(search.SearchFilter{Field: "content; DROP TABLE x", Operator: "eq", Value: "x"}))
+	assert.Error(t, cond.addFilter(search.SearchFilter{Field: "type", Operator: "between", Value: "x"}))
+	assert.Error(t, cond.addFilter(search.SearchFilter{Field: "type", Operator: "in", Value: "table"}))
+}
+
+func TestSearchOrder(t *testing.T) {
+	order, err := searchOrder("", "")
+	require.NoError(t, err)
+	assert.Equal(t, "score DESC", order)
+
+	order, err = searchOrder("updated_time", "asc")
+	require.NoError(t, err)
+	assert.Equal(t, "search_indexes.updated_time ASC", order)
+
+	_, err = searchOrder("content", "asc")
+	assert.Error(t, err)
+	_, err = searchOrder("title", "sideways")
+	assert.Error(t, err)
+}
+
+func TestSearchHighlight(t *testing.T) {
+	re := searchHighlighter([]string{"acme"})
+	require.NotNil(t, re)
+
+	title, ok := highlightTitle(re, "ACME Corp")
+	require.True(t, ok)
+	assert.Equal(t, "<em>ACME</em> Corp", title)
+
+	content := strings.Repeat("前", 60) + "acme" + strings.Repeat("后", 60)
+	snippet, ok := highlightSnippet(re, content)
+	require.True(t, ok)
+	assert.Equal(t, "..."+strings.Repeat("前", 40)+"<em>acme</em>"+strings.Repeat("后", 40)+"...", snippet)
+
+	_, ok = highlightSnippet(re, "nothing here")
+	assert.False(t, ok)
+}
+
+func TestSearchIndexModelRoundTrip(t *testing.T) {
+	index := search.NewSearchIndex(search.SearchTypeTable, "客户", "客户列表")
+	index.SetSource("tbl_1", "table", "")
+	index.SetContext("usr_1", "spc_1", "tbl_1", "")
+	index.SetBase("bse_1")
+	index.SetMetadata("icon", "👤")
+	index.Keywords = nil
+
+	model, err := toSearchIndexModel(index)
+	require.NoError(t, err)
+	assert.Equal(t, "[]", model.Keywords)
+	assert.Equal(t, "bse_1", model.BaseID)
+
+	restored := toSearchIndexEntity(model)
+	assert.Equal(t, index.Metadata, restored.Metadata)
+	assert.Equal(t, "bse_1", restored.BaseID)
+	assert.Empty(t, restored.Keywords)
+}
+
+func TestSearchIndexManagement_ViewerScope(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.SearchIndex{}))
+	for _, stmt := range []string{
+		"CREATE TABLE space (id TEXT, created_by TEXT, deleted_time DATETIME)",
+		"CREATE TABLE base (id TEXT, created_by TEXT, deleted_time DATETIME)",
+		"CREATE TABLE collaborators (resource_id TEXT, resource_type TEXT, principal_id TEXT, principal_type TEXT)",
+		"INSERT INTO space (id, created_by) VALUES ('spc_a', 'usr_a'), ('spc_b', 'usr_b')",
+	} {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+	repo := NewSearchRepository(db)
+	ctx := context.Background()
+
+	newIndex := func(sourceID, spaceID string) *search.SearchIndex {
+		index := search.NewSearchIndex(search.SearchTypeTable, "客户 "+sourceID, "")
+		index.SetSource(sourceID, "table", "")
+		index.SetContext("", spaceID, sourceID, "")
+		return index
+	}
+	own := newIndex("tbl_a", "spc_a")
+	other := newIndex("tbl_b", "spc_b")
+	require.NoError(t, repo.CreateIndex(ctx, own, "usr_a"))
+	require.NoError(t, repo.CreateIndex(ctx, other, ""), "内部调用不限制")
+
+	err = repo.CreateIndex(ctx, newIndex("tbl_x", "spc_b"), "usr_a")
+	appErr, ok := errors.IsAppError(err)
+	require.True(t, ok, "不能在其他用户的空间下创建条目")
+	assert.Equal(t, errors.ErrForbidden.Code, appErr.Code)
+
+	list, total, err := repo.ListIndexes(ctx, nil, "", "", "usr_a", 1, 20)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, list, 1)
+	assert.Equal(t, own.ID, list[0].ID)
+
+	_, err = repo.GetIndex(ctx, other.ID, "usr_a")
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+	other.Title = "改名"
+	assert.ErrorIs(t, repo.UpdateIndex(ctx, other, "usr_a"), errors.ErrNotFound)
+	assert.ErrorIs(t, repo.DeleteIndex(ctx, other.ID, "usr_a"), errors.ErrNotFound)
+	require.NoError(t, repo.DeleteIndexesBySource(ctx, "tbl_b", "table", "usr_a"))
+
+	got, err := repo.GetIndex(ctx, other.ID, "usr_b")
+	require.NoError(t, err, "其他用户的条目未被修改或删除")
+	assert.Equal(t, "客户 tbl_b", got.Title)
+}
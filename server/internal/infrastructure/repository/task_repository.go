@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/easyspace-ai/luckdb/server/internal/domain/task"
+	"github.com/easyspace-ai/luckdb/server/internal/infrastructure/database/models"
+)
+
+// TaskRepository 后台任务仓储实现（task / task_run 表）
+type TaskRepository struct {
+	db *gorm.DB
+}
+
+// NewTaskRepository 创建后台任务仓储
+func NewTaskRepository(db *gorm.DB) task.Repository {
+	return &TaskRepository{db: db}
+}
+
+// CreateTask 创建任务
+func (r *TaskRepository) CreateTask(ctx context.Context, t *task.Task) error {
+	model := toTaskModel(t)
+	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
+		return err
+	}
+	t.CreatedTime = model.CreatedTime
+	t.LastModifiedTime = model.LastModifiedTime
+	return nil
+}
+
+// UpdateTask 更新任务状态与快照
+func (r *TaskRepository) UpdateTask(ctx context.Context, t *task.Task) error {
+	return r.db.WithContext(ctx).Model(&models.Task{}).
+		Where("id = ?", t.ID).
+		Updates(map[string]interface{}{
+			"status":   string(t.Status),
+			"snapshot": nullableString(t.Snapshot),
+		}).Error
+}
+
+// FindTaskByID 获取任务，不存在时返回 nil
+func (r *TaskRepository) FindTaskByID(ctx context.Context, id string) (*task.Task, error) {
+	var model models.Task
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return fromTaskModel(&model), nil
+}
+
+// CreateRun 创建任务运行记录
+func (r *TaskRepository) CreateRun(ctx context.Context, run *task.Run) error {
+	model := toTaskRunModel(run)
+	if err := r.db.WithContext(ctx).Omit("Task").Create(model).Error; err != nil {
+		return err
+	}
+	run.CreatedTime = model.CreatedTime
+	run.LastModifiedTime = model.LastModifiedTime
+	return nil
+}
+
+// UpdateRun 更新任务运行的状态、进度与耗时
+func (r *TaskRepository) UpdateRun(ctx context.Context, run *task.Run) error {
+	return r.db.WithContext(ctx).Model(&models.TaskRun{}).
+		Where("id = ?", run.ID).
+		Updates(map[string]interface{}{
+			"status":       string(run.Status),
+			"snapshot":     run.Snapshot,
+			"spent":        run.Spent,
+			"error_msg":    nullableString(run.ErrorMsg),
+			"started_time": run.StartedTime,
+		}).Error
+}
+
+// FindLatestRun 获取任务最近一次运行，不存在时返回 nil
+func (r *TaskRepository) FindLatestRun(ctx context.Context, taskID string) (*task.Run, error) {
+	var model models.TaskRun
+	err := r.db.WithContext(ctx).
+		Where("task_id = ?", taskID).
+		Order("created_time DESC").
+		First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return fromTaskRunModel(&model), nil
+}
+
+func toTaskModel(t *task.Task) *models.Task {
+	return &models.Task{
+		ID:        t.ID,
+		Type:      t.Type,
+		Status:    string(t.Status),
+		Snapshot:  nullableString(t.Snapshot),
+		CreatedBy: t.CreatedBy,
+	}
+}
+
+func fromTaskModel(model *models.Task) *task.Task {
+	t := &task.Task{
+		ID:               model.ID,
+		Type:             model.Type,
+		Status:           task.Status(model.Status),
+		CreatedBy:        model.CreatedBy,
+		CreatedTime:      model.CreatedTime,
+		LastModifiedTime: model.LastModifiedTime,
+	}
+	if model.Snapshot != nil {
+		t.Snapshot = *model.Snapshot
+	}
+	return t
+}
+
+func toTaskRunModel(run *task.Run) *models.TaskRun {
+	return &models.TaskRun{
+		ID:          run.ID,
+		TaskID:      run.TaskID,
+		Status:      string(run.Status),
+		Snapshot:    run.Snapshot,
+		Spent:       run.Spent,
+		ErrorMsg:    nullableString(run.ErrorMsg),
+		StartedTime: run.StartedTime,
+	}
+}
+
+func fromTaskRunModel(model *models.TaskRun) *task.Run {
+	run := &task.Run{
+		ID:               model.ID,
+		TaskID:           model.TaskID,
+		Status:           task.Status(model.Status),
+		Snapshot:         model.Snapshot,
+		Spent:            model.Spent,
+		StartedTime:      model.StartedTime,
+		CreatedTime:      model.CreatedTime,
+		LastModifiedTime: model.LastModifiedTime,
+	}
+	if model.ErrorMsg != nil {
+		run.ErrorMsg = *model.ErrorMsg
+	}
+	return run
+}
+
+// nullableString 空字符串存为 NULL
+func nullableString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,97 @@
+package http
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/basearchive"
+	"github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// maxArchiveFileSize Base 归档上传大小上限（含附件文件）
+const maxArchiveFileSize = 1 << 30
+
+// BaseArchiveHandler Base 归档导出与导入HTTP处理器
+type BaseArchiveHandler struct {
+	archiveService *basearchive.Service
+}
+
+// NewBaseArchiveHandler 创建 Base 归档处理器
+func NewBaseArchiveHandler(archiveService *basearchive.Service) *BaseArchiveHandler {
+	return &BaseArchiveHandler{
+		archiveService: archiveService,
+	}
+}
+
+// ExportBase 导出 Base 归档
+// @Summary 导出 Base 归档
+// @Description 把 Base 的表格、字段、视图、关联关系、记录与附件文件打包为 zip 归档流式返回
+// @Tags Base归档
+// @Produce application/zip
+// @Param baseId path string true "Base ID"
+// @Success 200 {file} file "归档文件"
+// @Failure 404 {object} response.Response "Base不存在"
+// @Router /api/v1/bases/{baseId}/archive [get]
+func (h *BaseArchiveHandler) ExportBase(c *gin.Context) {
+	baseID := c.Param("baseId")
+	fileName, err := h.archiveService.FileName(c.Request.Context(), baseID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	setAttachmentHeaders(c, fileName, "application/zip")
+	if _, err := h.archiveService.Export(c.Request.Context(), baseID, c.Writer); err != nil {
+		// 响应头已发送，只能中断输出并记录日志
+		logger.Error("导出Base归档失败",
+			logger.String("base_id", baseID),
+			logger.ErrorField(err))
+		c.Abort()
+	}
+}
+
+// ImportBase 导入 Base 归档
+// @Summary 导入 Base 归档
+// @Description 在空间下按归档新建 Base，表格、字段、视图与记录使用新 ID，关联、查找、汇总与公式引用随之改写
+// @Tags Base归档
+// @Accept multipart/form-data
+// @Produce json
+// @Param spaceId path string true "空间ID"
+// @Param file formData file true "归档文件"
+// @Param name formData string false "新 Base 名称，默认沿用归档中的名称"
+// @Success 200 {object} response.Response{data=basearchive.ImportResult} "导入成功"
+// @Failure 400 {object} response.Response "归档无效"
+// @Router /api/v1/spaces/{spaceId}/bases/import [post]
+func (h *BaseArchiveHandler) ImportBase(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, errors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		response.Error(c, errors.ErrBadRequest.WithDetails("File is required"))
+		return
+	}
+	defer file.Close()
+	if header.Size > maxArchiveFileSize {
+		response.Error(c, errors.ErrBadRequest.WithDetails(fmt.Sprintf("文件大小超过上限 %d MB", maxArchiveFileSize>>20)))
+		return
+	}
+
+	result, err := h.archiveService.Import(c.Request.Context(), file, header.Size, basearchive.ImportRequest{
+		SpaceID: c.Param("spaceId"),
+		Name:    c.PostForm("name"),
+		UserID:  userID,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result, "导入Base成功")
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"github.com/easyspace-ai/luckdb/server/internal/application"
+	"github.com/easyspace-ai/luckdb/server/internal/application/basearchive"
 	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
 	"github.com/easyspace-ai/luckdb/server/pkg/authctx"
 	"github.com/easyspace-ai/luckdb/server/pkg/errors"
@@ -12,15 +13,17 @@ import (
 
 // BaseHandler Base HTTP处理器（严格遵守API标准）
 type BaseHandler struct {
-	service      *application.BaseService
-	tableService *application.TableService
+	service        *application.BaseService
+	tableService   *application.TableService
+	archiveService *basearchive.Service
 }
 
 // NewBaseHandler 创建Base处理器
-func NewBaseHandler(service *application.BaseService, tableService *application.TableService) *BaseHandler {
+func NewBaseHandler(service *application.BaseService, tableService *application.TableService, archiveService *basearchive.Service) *BaseHandler {
 	return &BaseHandler{
-		service:      service,
-		tableService: tableService,
+		service:        service,
+		tableService:   tableService,
+		archiveService: archiveService,
 	}
 }
 
@@ -153,13 +156,8 @@ func (h *BaseHandler) GetBaseTables(c *gin.Context) {
 // POST /api/v1/bases/:baseId/duplicate
 // ✅ 严格使用 response.Success
 //
-// TODO: 需要实现Base复制功能
-// 实现步骤：
-//  1. 在BaseService中添加DuplicateBase方法
-//  2. 复制Base的基本信息（名称、描述等）
-//  3. 复制Base下的所有Table（调用TableService）
-//  4. 复制每个Table下的Fields（调用FieldService）
-//  5. 可选：复制Records数据
+// 复制所有表格、字段与视图，withRecords=true 时同时复制记录。
+// async=true 或记录数超过上限时转为后台任务，返回复制任务（经 GET /api/v1/base-duplicates/:taskId 查询进度）
 func (h *BaseHandler) DuplicateBase(c *gin.Context) {
 	baseID := c.Param("baseId")
 
@@ -171,23 +169,70 @@ func (h *BaseHandler) DuplicateBase(c *gin.Context) {
 	}
 
 	// 2. 获取用户ID
-	_, exists := authctx.UserFrom(c.Request.Context())
+	userID, exists := authctx.UserFrom(c.Request.Context())
 	if !exists {
 		response.Error(c, errors.ErrUnauthorized)
 		return
 	}
 
-	// 3. 调用服务
-	result, err := h.service.DuplicateBase(c.Request.Context(), baseID, &req)
+	// 3. 记录多的Base在后台复制
+	plan, err := h.archiveService.PrepareDuplicate(c.Request.Context(), basearchive.DuplicateRequest{
+		BaseID:      baseID,
+		Name:        req.Name,
+		WithRecords: req.WithRecords,
+		UserID:      userID,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if req.Async || h.archiveService.ShouldDuplicateInBackground(plan) {
+		status, err := h.archiveService.StartDuplicateTask(c.Request.Context(), plan)
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+		response.Success(c, status, "Base复制任务已开始")
+		return
+	}
+
+	// 4. 同步复制
+	duplicated, err := h.archiveService.Duplicate(c.Request.Context(), plan)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	result, err := h.service.GetBase(c.Request.Context(), duplicated.BaseID)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
-	// 4. 返回成功响应
+	// 5. 返回成功响应
 	response.Success(c, result, "Base复制成功")
 }
 
+// GetDuplicateTask 获取Base复制任务
+// GET /api/v1/base-duplicates/:taskId
+// ✅ 严格使用 response.Success
+//
+// 返回复制阶段与进度，完成后返回新Base的ID（只能查看自己发起的任务）
+func (h *BaseHandler) GetDuplicateTask(c *gin.Context) {
+	userID, exists := authctx.UserFrom(c.Request.Context())
+	if !exists {
+		response.Error(c, errors.ErrUnauthorized)
+		return
+	}
+
+	status, err := h.archiveService.GetDuplicateTask(c.Request.Context(), c.Param("taskId"), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, status, "获取Base复制任务成功")
+}
+
 // GetBaseCollaborators 获取Base协作者列表
 // GET /api/v1/bases/:baseId/collaborators
 // ✅ 严格使用 response.Success
@@ -0,0 +1,168 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dataexport"
+	"github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// ExportHandler 数据导出HTTP处理器
+type ExportHandler struct {
+	exportService *dataexport.Service
+}
+
+// NewExportHandler 创建数据导出处理器
+func NewExportHandler(exportService *dataexport.Service) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// ExportTable 导出表格或视图
+// @Summary 导出表格
+// @Description 按视图的过滤、排序与隐藏列导出为 CSV 或 XLSX，单元格为显示文本。
+// @Description 行数不超过流式上限时直接流式返回文件；async=true 或行数超过上限时转为后台任务，返回导出任务
+// @Tags 数据导出
+// @Produce text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,json
+// @Param tableId path string true "表格ID"
+// @Param format query string false "导出格式：csv（默认）或 xlsx"
+// @Param viewId query string false "视图ID"
+// @Param includeAttachmentUrls query bool false "附件单元格附带文件链接"
+// @Param async query bool false "以后台任务导出"
+// @Success 200 {file} file "导出文件"
+// @Success 200 {object} response.Response{data=dataexport.TaskStatus} "导出任务已开始"
+// @Router /api/v1/tables/{tableId}/export [get]
+func (h *ExportHandler) ExportTable(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, errors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	format, err := dataexport.ParseFormat(c.Query("format"))
+	if err != nil {
+		response.Error(c, errors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+	includeURLs, _ := strconv.ParseBool(c.Query("includeAttachmentUrls"))
+	async, _ := strconv.ParseBool(c.Query("async"))
+
+	plan, err := h.exportService.Prepare(c.Request.Context(), dataexport.ExportRequest{
+		TableID:               c.Param("tableId"),
+		ViewID:                c.Query("viewId"),
+		Format:                format,
+		IncludeAttachmentURLs: includeURLs,
+		BaseURL:               requestBaseURL(c),
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if async || h.exportService.ShouldRunInBackground(plan) {
+		status, err := h.exportService.StartTask(c.Request.Context(), plan, userID)
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+		response.Success(c, status, "导出任务已开始")
+		return
+	}
+
+	setAttachmentHeaders(c, plan.FileName, format.ContentType())
+	rows, err := h.exportService.Write(c.Request.Context(), plan, c.Writer)
+	if err != nil {
+		// 响应头已发送，只能中断输出并记录日志
+		logger.Error("导出表格失败",
+			logger.String("table_id", plan.Request.TableID),
+			logger.Int("rows", rows),
+			logger.ErrorField(err))
+		c.Abort()
+	}
+}
+
+// GetExportTask 获取导出任务状态
+// @Summary 获取导出任务
+// @Description 获取后台导出任务的状态与进度，完成后返回下载地址（只能查看自己发起的任务）
+// @Tags 数据导出
+// @Produce json
+// @Param taskId path string true "任务ID"
+// @Success 200 {object} response.Response{data=dataexport.TaskStatus} "获取成功"
+// @Failure 404 {object} response.Response "任务不存在"
+// @Router /api/v1/exports/{taskId} [get]
+func (h *ExportHandler) GetExportTask(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, errors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	status, err := h.exportService.GetTask(c.Request.Context(), c.Param("taskId"), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	if status.Size > 0 {
+		status.DownloadURL = fmt.Sprintf("/api/v1/exports/%s/download", status.ID)
+	}
+
+	response.Success(c, status, "获取导出任务成功")
+}
+
+// DownloadExport 下载导出结果
+// @Summary 下载导出文件
+// @Description 下载已完成的后台导出任务生成的文件
+// @Tags 数据导出
+// @Produce application/octet-stream
+// @Param taskId path string true "任务ID"
+// @Success 200 {file} file "导出文件"
+// @Failure 404 {object} response.Response "任务不存在"
+// @Router /api/v1/exports/{taskId}/download [get]
+func (h *ExportHandler) DownloadExport(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, errors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	result, fileName, err := h.exportService.OpenResult(c.Request.Context(), c.Param("taskId"), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	defer result.Reader.Close()
+
+	setAttachmentHeaders(c, fileName, result.ContentType)
+	c.Header("Content-Length", strconv.FormatInt(result.Size, 10))
+	if _, err := io.Copy(c.Writer, result.Reader); err != nil {
+		logger.Error("下载导出文件失败", logger.String("task_id", c.Param("taskId")), logger.ErrorField(err))
+	}
+}
+
+// setAttachmentHeaders 设置文件下载响应头（文件名按 RFC 5987 编码以支持中文）
+func setAttachmentHeaders(c *gin.Context, fileName, contentType string) {
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="export"; filename*=UTF-8''%s`, url.PathEscape(fileName)))
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Content-Type-Options", "nosniff")
+}
+
+// requestBaseURL 当前请求的协议与主机，用于把附件相对链接转为绝对链接
+func requestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host
+}
@@ -87,6 +87,25 @@ func (h *FieldHandler) UpdateField(c *gin.Context) {
 	response.Success(c, resp, "更新字段成功")
 }
 
+// PreviewFieldConversion 预览字段类型转换（不修改数据）
+func (h *FieldHandler) PreviewFieldConversion(c *gin.Context) {
+	fieldID := c.Param("fieldId")
+
+	var req dto.FieldConversionPreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	resp, err := h.fieldService.PreviewFieldConversion(c.Request.Context(), fieldID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, resp, "获取字段类型转换预览成功")
+}
+
 // DeleteField 删除字段
 func (h *FieldHandler) DeleteField(c *gin.Context) {
 	fieldID := c.Param("fieldId")
@@ -0,0 +1,284 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application/dataimport"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// maxImportFileSize 导入文件大小上限
+const maxImportFileSize = 50 << 20
+
+// ImportHandler 数据导入HTTP处理器
+type ImportHandler struct {
+	importService *dataimport.Service
+}
+
+// NewImportHandler 创建数据导入处理器
+func NewImportHandler(importService *dataimport.Service) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+	}
+}
+
+// PreviewCSV 预览 CSV 文件
+// @Summary 预览 CSV 导入
+// @Description 识别编码与分隔符，返回列（含推断的字段类型）与前若干行
+// @Tags 数据导入
+// @Accept multipart/form-data
+// @Produce json
+// @Param baseId path string true "Base ID"
+// @Param file formData file true "CSV 文件"
+// @Param encoding formData string false "文件编码（默认自动识别）"
+// @Param delimiter formData string false "分隔符（默认自动识别）"
+// @Param hasHeader formData bool false "第一行是否为表头（默认 true）"
+// @Success 200 {object} response.Response{data=dataimport.Preview} "预览成功"
+// @Router /api/v1/bases/{baseId}/imports/csv/preview [post]
+func (h *ImportHandler) PreviewCSV(c *gin.Context) {
+	data, _, err := readImportFile(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	opts, err := parseImportOptions(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	preview, err := h.importService.PreviewCSV(data, opts)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, preview, "预览成功")
+}
+
+// ImportCSV 导入 CSV 文件
+// @Summary 导入 CSV
+// @Description 导入到新建表格（不传 tableId）或已有表格，后台按批写入，返回导入任务
+// @Tags 数据导入
+// @Accept multipart/form-data
+// @Produce json
+// @Param baseId path string true "Base ID"
+// @Param file formData file true "CSV 文件"
+// @Param tableId formData string false "导入到已有表格"
+// @Param tableName formData string false "新建表格名称（默认取文件名）"
+// @Param columns formData string false "列映射 JSON 数组：[{index, name, type, fieldId}]"
+// @Param encoding formData string false "文件编码（默认自动识别）"
+// @Param delimiter formData string false "分隔符（默认自动识别）"
+// @Param hasHeader formData bool false "第一行是否为表头（默认 true）"
+// @Param skipRecordRules formData bool false "跳过表级记录校验规则（仅管理员）"
+// @Success 200 {object} response.Response{data=dataimport.TaskStatus} "导入任务已开始"
+// @Router /api/v1/bases/{baseId}/imports/csv [post]
+func (h *ImportHandler) ImportCSV(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	data, fileName, err := readImportFile(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	opts, err := parseImportOptions(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	skipRules, err := parseSkipRecordRules(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	req := dataimport.ImportRequest{
+		BaseID:    c.Param("baseId"),
+		TableID:   c.PostForm("tableId"),
+		TableName: c.PostForm("tableName"),
+		FileName:  fileName,
+		Options:   opts,
+
+		SkipRecordRules: skipRules,
+	}
+	if columns := c.PostForm("columns"); columns != "" {
+		if err := json.Unmarshal([]byte(columns), &req.Columns); err != nil {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("columns 格式错误: %v", err)))
+			return
+		}
+	}
+
+	status, err := h.importService.ImportCSV(c.Request.Context(), req, data, userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, status, "导入任务已开始")
+}
+
+// PreviewXLSX 预览 XLSX 文件
+// @Summary 预览 XLSX 导入
+// @Description 返回每个工作表识别出的表头行、列（含推断的字段类型）与前若干行
+// @Tags 数据导入
+// @Accept multipart/form-data
+// @Produce json
+// @Param baseId path string true "Base ID"
+// @Param file formData file true "XLSX 文件"
+// @Success 200 {object} response.Response{data=dataimport.WorkbookPreview} "预览成功"
+// @Router /api/v1/bases/{baseId}/imports/xlsx/preview [post]
+func (h *ImportHandler) PreviewXLSX(c *gin.Context) {
+	data, _, err := readImportFile(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	preview, err := h.importService.PreviewXLSX(data)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, preview, "预览成功")
+}
+
+// ImportXLSX 导入 XLSX 文件
+// @Summary 导入 XLSX
+// @Description 每个工作表导入到新建表格（不传 tableId）或已有表格，各自创建导入任务；不传 sheets 时导入所有非空工作表
+// @Tags 数据导入
+// @Accept multipart/form-data
+// @Produce json
+// @Param baseId path string true "Base ID"
+// @Param file formData file true "XLSX 文件"
+// @Param sheets formData string false "工作表设置 JSON 数组：[{name, tableId, tableName, columns, headerRow}]"
+// @Param skipRecordRules formData bool false "跳过表级记录校验规则（仅管理员）"
+// @Success 200 {object} response.Response{data=[]dataimport.SheetTask} "导入任务已开始"
+// @Router /api/v1/bases/{baseId}/imports/xlsx [post]
+func (h *ImportHandler) ImportXLSX(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	data, fileName, err := readImportFile(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	skipRules, err := parseSkipRecordRules(c)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	req := dataimport.XLSXImportRequest{
+		BaseID:          c.Param("baseId"),
+		FileName:        fileName,
+		SkipRecordRules: skipRules,
+	}
+	if sheets := c.PostForm("sheets"); sheets != "" {
+		if err := json.Unmarshal([]byte(sheets), &req.Sheets); err != nil {
+			response.Error(c, pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("sheets 格式错误: %v", err)))
+			return
+		}
+	}
+
+	tasks, err := h.importService.ImportXLSX(c.Request.Context(), req, data, userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, tasks, "导入任务已开始")
+}
+
+// GetImportTask 获取导入任务进度与错误报告
+// @Summary 获取导入任务
+// @Description 获取导入任务的状态、进度与逐行错误报告（只能查看自己发起的任务）
+// @Tags 数据导入
+// @Produce json
+// @Param taskId path string true "任务ID"
+// @Success 200 {object} response.Response{data=dataimport.TaskStatus} "获取成功"
+// @Failure 404 {object} response.Response "任务不存在"
+// @Router /api/v1/imports/{taskId} [get]
+func (h *ImportHandler) GetImportTask(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	status, err := h.importService.GetTask(c.Request.Context(), c.Param("taskId"), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, status, "获取导入任务成功")
+}
+
+// readImportFile 读取上传的文件（表单字段 file）
+func readImportFile(c *gin.Context) ([]byte, string, error) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		return nil, "", pkgerrors.ErrBadRequest.WithDetails("File is required")
+	}
+	defer file.Close()
+
+	if header.Size > maxImportFileSize {
+		return nil, "", pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("文件大小超过上限 %d MB", maxImportFileSize>>20))
+	}
+	data, err := io.ReadAll(io.LimitReader(file, maxImportFileSize+1))
+	if err != nil {
+		return nil, "", pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("读取文件失败: %v", err))
+	}
+	if len(data) > maxImportFileSize {
+		return nil, "", pkgerrors.ErrBadRequest.WithDetails(fmt.Sprintf("文件大小超过上限 %d MB", maxImportFileSize>>20))
+	}
+	return data, header.Filename, nil
+}
+
+// parseImportOptions 读取解析选项（表单字段 encoding、delimiter、hasHeader）
+func parseImportOptions(c *gin.Context) (dataimport.ParseOptions, error) {
+	opts := dataimport.ParseOptions{
+		Encoding:  c.PostForm("encoding"),
+		Delimiter: c.PostForm("delimiter"),
+	}
+	if raw := c.PostForm("hasHeader"); raw != "" {
+		hasHeader, err := strconv.ParseBool(raw)
+		if err != nil {
+			return opts, pkgerrors.ErrBadRequest.WithDetails("hasHeader 必须是布尔值")
+		}
+		opts.HasHeader = &hasHeader
+	}
+	return opts, nil
+}
+
+// parseSkipRecordRules 读取表单字段 skipRecordRules
+func parseSkipRecordRules(c *gin.Context) (bool, error) {
+	raw := c.PostForm("skipRecordRules")
+	if raw == "" {
+		return false, nil
+	}
+	skip, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, pkgerrors.ErrBadRequest.WithDetails("skipRecordRules 必须是布尔值")
+	}
+	return skip, nil
+}
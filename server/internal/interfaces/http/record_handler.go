@@ -2,6 +2,7 @@ package http
 
 import (
 	"context"
+	"encoding/json"
 	"strconv"
 	"strings"
 	"time"
@@ -58,6 +59,7 @@ func (h *RecordHandler) CreateRecord(c *gin.Context) {
 		response.Error(c, errors.ErrBadRequest.WithDetails(err.Error()))
 		return
 	}
+	req.Typecast = req.Typecast || queryTypecast(c)
 
 	logger.Info("JSON绑定成功",
 		logger.String("table_id", req.TableID),
@@ -94,6 +96,12 @@ func (h *RecordHandler) CreateRecord(c *gin.Context) {
 	response.Success(c, resp, "创建记录成功")
 }
 
+// queryTypecast 解析 ?typecast=true（也可在请求体中传 typecast）
+func queryTypecast(c *gin.Context) bool {
+	typecast, _ := strconv.ParseBool(c.Query("typecast"))
+	return typecast
+}
+
 // GetRecord 获取记录详情
 func (h *RecordHandler) GetRecord(c *gin.Context) {
 	tableID := c.Param("tableId")
@@ -138,6 +146,7 @@ func (h *RecordHandler) UpdateRecord(c *gin.Context) {
 		response.Error(c, errors.ErrBadRequest.WithDetails(err.Error()))
 		return
 	}
+	req.Typecast = req.Typecast || queryTypecast(c)
 
 	userID := c.GetString("user_id")
 	if userID == "" {
@@ -183,6 +192,7 @@ func (h *RecordHandler) DeleteRecord(c *gin.Context) {
 
 // BatchCreateRecords 批量创建记录
 // POST /api/v1/tables/:tableId/records/batch
+// 请求体带 keyFields 时按主键字段 upsert：匹配到已有记录则更新，否则创建
 // ✅ 严格使用 response.Success
 func (h *RecordHandler) BatchCreateRecords(c *gin.Context) {
 	tableID := c.Param("tableId")
@@ -193,6 +203,7 @@ func (h *RecordHandler) BatchCreateRecords(c *gin.Context) {
 		response.Error(c, errors.ErrBadRequest.WithDetails(err.Error()))
 		return
 	}
+	req.Typecast = req.Typecast || queryTypecast(c)
 
 	// 2. 获取用户ID
 	userID := c.GetString("user_id")
@@ -212,6 +223,54 @@ func (h *RecordHandler) BatchCreateRecords(c *gin.Context) {
 	response.Success(c, resp, "批量创建记录成功")
 }
 
+// GroupRecords 分组统计记录（分组头、记录数与每组聚合）
+// POST /api/v1/tables/:tableId/records/group
+// Body: {"filter": {...}, "viewId": "viw_xxx", "groupBy": [{"fieldId","order"}], "aggregations": [{"fieldId","func"}]}
+func (h *RecordHandler) GroupRecords(c *gin.Context) {
+	tableID := c.Param("tableId")
+
+	var req dto.GroupRecordsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+	if req.ViewID == "" {
+		req.ViewID = c.Query("viewId")
+	}
+
+	resp, err := h.recordService.GroupRecords(c.Request.Context(), tableID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, resp, "分组统计成功")
+}
+
+// GetRecordStatistics 列统计（表格底部统计栏）
+// POST /api/v1/tables/:tableId/records/statistics
+// Body: {"filter": {...}, "viewId": "viw_xxx", "statistics": {"fld_xxx": "sum", "fld_yyy": "percentEmpty"}}
+func (h *RecordHandler) GetRecordStatistics(c *gin.Context) {
+	tableID := c.Param("tableId")
+
+	var req dto.RecordStatisticsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+	if req.ViewID == "" {
+		req.ViewID = c.Query("viewId")
+	}
+
+	resp, err := h.recordService.GetRecordStatistics(c.Request.Context(), tableID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, resp, "列统计成功")
+}
+
 // BatchUpdateRecords 批量更新记录
 // PATCH /api/v1/records/batch
 // ✅ 严格使用 response.Success
@@ -229,6 +288,7 @@ func (h *RecordHandler) BatchUpdateRecords(c *gin.Context) {
 		response.Error(c, errors.ErrBadRequest.WithDetails(err.Error()))
 		return
 	}
+	req.Typecast = req.Typecast || queryTypecast(c)
 
 	// 3. 获取用户ID
 	userID := c.GetString("user_id")
@@ -277,7 +337,57 @@ func (h *RecordHandler) BatchDeleteRecords(c *gin.Context) {
 	response.Success(c, resp, "批量删除记录成功")
 }
 
-// ListRecords 列出表格的所有记录
+// UpdateRecordsByFilter 按过滤条件批量更新记录
+// PATCH /api/v1/tables/:tableId/records/by-filter
+// 请求体：{"filter": {...}, "viewId": "...", "fields": {...}, "dryRun": true, "maxAffected": 10000}
+func (h *RecordHandler) UpdateRecordsByFilter(c *gin.Context) {
+	tableID := c.Param("tableId")
+
+	var req dto.UpdateRecordsByFilterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+	req.Typecast = req.Typecast || queryTypecast(c)
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		response.Error(c, errors.ErrUnauthorized.WithDetails("未授权"))
+		return
+	}
+
+	resp, err := h.recordService.UpdateRecordsByFilter(c.Request.Context(), tableID, req, userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, resp, "按条件批量更新记录成功")
+}
+
+// DeleteRecordsByFilter 按过滤条件批量删除记录
+// DELETE /api/v1/tables/:tableId/records/by-filter
+// 请求体：{"filter": {...}, "viewId": "...", "dryRun": true, "maxAffected": 10000}
+func (h *RecordHandler) DeleteRecordsByFilter(c *gin.Context) {
+	tableID := c.Param("tableId")
+
+	var req dto.DeleteRecordsByFilterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, errors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	resp, err := h.recordService.DeleteRecordsByFilter(c.Request.Context(), tableID, req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, resp, "按条件批量删除记录成功")
+}
+
+// ListRecords 列出表格的记录（支持 viewId、filter、sort、cursor、projection、cellFormat、search 查询参数）
+// GET /api/v1/tables/:tableId/records?viewId=<viewId>&filter=<json>&sort=<json>&cursor=<nextCursor>&projection=<fld_a,fld_b>&cellFormat=<json|text>&search=<keyword>
 func (h *RecordHandler) ListRecords(c *gin.Context) {
 	tableID := c.Param("tableId")
 
@@ -308,26 +418,58 @@ func (h *RecordHandler) ListRecords(c *gin.Context) {
         }
     }
 
+	listReq := dto.ListRecordsRequest{
+		Limit:  limit,
+		Offset: offset,
+		ViewID: c.Query("viewId"),
+		Cursor: c.Query("cursor"),
+
+		CellFormat: c.Query("cellFormat"),
+		Search:     c.Query("search"),
+	}
+
+	// 解析字段投影：?projection=fld_a,fld_b 或重复参数 ?projection=fld_a&projection=fld_b
+	for _, raw := range c.QueryArray("projection") {
+		for _, fieldID := range strings.Split(raw, ",") {
+			if fieldID = strings.TrimSpace(fieldID); fieldID != "" {
+				listReq.Projection = append(listReq.Projection, fieldID)
+			}
+		}
+	}
+
+	// 解析过滤树：?filter={"operator":"and","filters":[...],"groups":[...]}
+	if rawFilter := c.Query("filter"); rawFilter != "" {
+		if err := json.Unmarshal([]byte(rawFilter), &listReq.Filter); err != nil {
+			response.Error(c, errors.ErrInvalidFilter.WithDetails(err.Error()))
+			return
+		}
+	}
+
+	// 解析多键排序：?sort=[{"fieldId":"fld_xxx","order":"desc"},...]
+	if rawSort := c.Query("sort"); rawSort != "" {
+		if err := json.Unmarshal([]byte(rawSort), &listReq.Sort); err != nil {
+			response.Error(c, errors.ErrInvalidSort.WithDetails(err.Error()))
+			return
+		}
+	}
+
 	// 调用 Service 获取记录列表和总数
-	records, total, err := h.recordService.ListRecords(c.Request.Context(), tableID, limit, offset)
+	result, err := h.recordService.ListRecords(c.Request.Context(), tableID, listReq)
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
 
-	// 计算总页数
-    totalPages := int((total + int64(limit) - 1) / int64(limit))
-    page := (offset / limit) + 1
-
 	// 使用分页响应（Records 是唯一需要分页的资源）
 	pagination := response.Pagination{
-		Page:       page,
+		Page:       result.Pagination.Page,
 		Limit:      limit,
-		Total:      int(total),
-		TotalPages: totalPages,
+		Total:      int(result.Pagination.Total),
+		TotalPages: result.Pagination.TotalPages,
+		NextCursor: result.Pagination.NextCursor,
 	}
 
-	response.PaginatedSuccess(c, records, pagination, "获取记录列表成功")
+	response.PaginatedSuccess(c, result.Records, pagination, "获取记录列表成功")
 }
 
 // ==================== 辅助方法 ====================
@@ -0,0 +1,147 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/easyspace-ai/luckdb/server/internal/application"
+	"github.com/easyspace-ai/luckdb/server/internal/application/dto"
+	pkgerrors "github.com/easyspace-ai/luckdb/server/pkg/errors"
+	"github.com/easyspace-ai/luckdb/server/pkg/logger"
+	"github.com/easyspace-ai/luckdb/server/pkg/response"
+)
+
+// RecordRuleHandler 表级记录校验规则HTTP处理器
+type RecordRuleHandler struct {
+	ruleService *application.RecordRuleService
+}
+
+// NewRecordRuleHandler 创建记录校验规则处理器
+func NewRecordRuleHandler(ruleService *application.RecordRuleService) *RecordRuleHandler {
+	return &RecordRuleHandler{
+		ruleService: ruleService,
+	}
+}
+
+// ListRecordRules 列出表格的记录校验规则
+// @Summary 列出记录校验规则
+// @Tags 记录校验规则
+// @Produce json
+// @Param tableId path string true "表格ID"
+// @Success 200 {object} response.Response{data=[]dto.RecordRuleResponse} "获取成功"
+// @Router /api/v1/tables/{tableId}/record-rules [get]
+func (h *RecordRuleHandler) ListRecordRules(c *gin.Context) {
+	rules, err := h.ruleService.ListRules(c.Request.Context(), c.Param("tableId"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, rules, "获取记录校验规则成功")
+}
+
+// CreateRecordRule 创建记录校验规则
+// @Summary 创建记录校验规则
+// @Description 规则是使用公式语言的表达式（如 {End} >= {Start}），记录写入前求值，结果为假时拒绝写入
+// @Tags 记录校验规则
+// @Accept json
+// @Produce json
+// @Param tableId path string true "表格ID"
+// @Param request body dto.CreateRecordRuleRequest true "规则"
+// @Success 200 {object} response.Response{data=dto.RecordRuleResponse} "创建成功"
+// @Failure 400 {object} response.Response "表达式无效"
+// @Router /api/v1/tables/{tableId}/record-rules [post]
+func (h *RecordRuleHandler) CreateRecordRule(c *gin.Context) {
+	tableID := c.Param("tableId")
+
+	var req dto.CreateRecordRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	userID := getUserIDFromContext(c)
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("用户未认证"))
+		return
+	}
+
+	rule, err := h.ruleService.CreateRule(c.Request.Context(), tableID, req, userID)
+	if err != nil {
+		logger.Error("创建记录校验规则失败",
+			logger.String("table_id", tableID),
+			logger.ErrorField(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, rule, "记录校验规则创建成功")
+}
+
+// UpdateRecordRule 更新记录校验规则
+// @Summary 更新记录校验规则
+// @Tags 记录校验规则
+// @Accept json
+// @Produce json
+// @Param tableId path string true "表格ID"
+// @Param ruleId path string true "规则ID"
+// @Param request body dto.UpdateRecordRuleRequest true "要更新的字段"
+// @Success 200 {object} response.Response{data=dto.RecordRuleResponse} "更新成功"
+// @Router /api/v1/tables/{tableId}/record-rules/{ruleId} [patch]
+func (h *RecordRuleHandler) UpdateRecordRule(c *gin.Context) {
+	tableID := c.Param("tableId")
+	ruleID := c.Param("ruleId")
+
+	var req dto.UpdateRecordRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	userID := getUserIDFromContext(c)
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("用户未认证"))
+		return
+	}
+
+	rule, err := h.ruleService.UpdateRule(c.Request.Context(), tableID, ruleID, req, userID)
+	if err != nil {
+		logger.Error("更新记录校验规则失败",
+			logger.String("table_id", tableID),
+			logger.String("rule_id", ruleID),
+			logger.ErrorField(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, rule, "记录校验规则更新成功")
+}
+
+// DeleteRecordRule 删除记录校验规则
+// @Summary 删除记录校验规则
+// @Tags 记录校验规则
+// @Produce json
+// @Param tableId path string true "表格ID"
+// @Param ruleId path string true "规则ID"
+// @Success 200 {object} response.Response "删除成功"
+// @Router /api/v1/tables/{tableId}/record-rules/{ruleId} [delete]
+func (h *RecordRuleHandler) DeleteRecordRule(c *gin.Context) {
+	tableID := c.Param("tableId")
+	ruleID := c.Param("ruleId")
+
+	userID := getUserIDFromContext(c)
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("用户未认证"))
+		return
+	}
+
+	if err := h.ruleService.DeleteRule(c.Request.Context(), tableID, ruleID, userID); err != nil {
+		logger.Error("删除记录校验规则失败",
+			logger.String("table_id", tableID),
+			logger.String("rule_id", ruleID),
+			logger.ErrorField(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, nil, "记录校验规则删除成功")
+}
@@ -54,6 +54,7 @@ func SetupRoutes(router *gin.Engine, cont *container.Container, staticFiles embe
 
 		// 记录相关路由
 		setupRecordRoutes(authRequired, cont)
+		setupRecordRuleRoutes(authRequired, cont)
 
 		// 视图相关路由
 		setupViewRoutes(authRequired, cont)
@@ -61,6 +62,14 @@ func SetupRoutes(router *gin.Engine, cont *container.Container, staticFiles embe
 		// 附件相关路由 ✨
 		setupAttachmentRoutes(authRequired, cont)
 
+		// 全局搜索路由 ✨
+		setupSearchRoutes(authRequired, cont)
+
+		// 数据导入路由
+		setupImportRoutes(authRequired, cont)
+		setupExportRoutes(authRequired, cont)
+		setupBaseArchiveRoutes(authRequired, cont)
+
 	}
 
 	// WebSocket 路由（需要认证）✨
@@ -117,8 +126,9 @@ func setupSpaceRoutes(rg *gin.RouterGroup, cont *container.Container) {
 
 // setupBaseRoutes 设置Base路由（对齐原版）
 func setupBaseRoutes(rg *gin.RouterGroup, cont *container.Container) {
-	handler := NewBaseHandler(cont.BaseService(), cont.TableService())
+	handler := NewBaseHandler(cont.BaseService(), cont.TableService(), cont.BaseArchiveService())
 	collabHandler := NewCollaboratorHandler(cont.CollaboratorService())
+	permissionMiddleware := middleware.NewPermissionMiddleware(cont.PermissionServiceV2())
 
 	// Space下的Base
 	spaces := rg.Group("/spaces")
@@ -136,7 +146,7 @@ func setupBaseRoutes(rg *gin.RouterGroup, cont *container.Container) {
 
 		// Base子资源
 		// Note: GET /:baseId/tables 由TableHandler处理（避免重复注册）
-		bases.POST("/:baseId/duplicate", handler.DuplicateBase)
+		bases.POST("/:baseId/duplicate", permissionMiddleware.RequireBaseAccess(), handler.DuplicateBase)
 		bases.GET("/:baseId/permission", handler.GetBasePermission)
 
 		// Base协作者管理 ✨
@@ -145,6 +155,9 @@ func setupBaseRoutes(rg *gin.RouterGroup, cont *container.Container) {
 		bases.PATCH("/:baseId/collaborators/:collaboratorId", collabHandler.UpdateBaseCollaborator)
 		bases.DELETE("/:baseId/collaborators/:collaboratorId", collabHandler.RemoveBaseCollaborator)
 	}
+
+	// Base复制任务
+	rg.GET("/base-duplicates/:taskId", handler.GetDuplicateTask)
 }
 
 // setupTableRoutes 设置表格路由
@@ -168,6 +181,7 @@ func setupTableRoutes(rg *gin.RouterGroup, cont *container.Container) {
 		// 表管理路由
 		tables.PUT("/:tableId/rename", handler.RenameTable)          // 重命名表
 		tables.POST("/:tableId/duplicate", handler.DuplicateTable)   // 复制表
+		tables.POST("/:tableId/move", handler.MoveTable)             // 移动到同一空间的其他Base
 		tables.GET("/:tableId/usage", handler.GetTableUsage)         // 获取表用量
 		tables.GET("/:tableId/menu", handler.GetTableManagementMenu) // 获取表管理菜单
 	}
@@ -189,6 +203,7 @@ func setupFieldRoutes(rg *gin.RouterGroup, cont *container.Container) {
 	{
 		fields.GET("/:fieldId", handler.GetField)
 		fields.PATCH("/:fieldId", handler.UpdateField) // ✅ 部分更新使用PATCH
+		fields.POST("/:fieldId/conversion-preview", handler.PreviewFieldConversion)
 		fields.DELETE("/:fieldId", handler.DeleteField)
 	}
 }
@@ -209,6 +224,8 @@ func setupRecordRoutes(rg *gin.RouterGroup, cont *container.Container) {
 		tables.GET("/:tableId/records", handler.ListRecords)
 		tables.POST("/:tableId/records", handler.CreateRecord)
 		tables.POST("/:tableId/records/batch", handler.BatchCreateRecords)
+		tables.POST("/:tableId/records/group", handler.GroupRecords) // 分组统计
+		tables.POST("/:tableId/records/statistics", handler.GetRecordStatistics) // 列统计
 
 		// 单条记录操作（需要 tableId 和 recordId）
 		tables.GET("/:tableId/records/:recordId", handler.GetRecord)
@@ -218,6 +235,8 @@ func setupRecordRoutes(rg *gin.RouterGroup, cont *container.Container) {
 		// 批量操作
 		tables.PATCH("/:tableId/records/batch", handler.BatchUpdateRecords)
 		tables.DELETE("/:tableId/records/batch", handler.BatchDeleteRecords)
+		tables.PATCH("/:tableId/records/by-filter", handler.UpdateRecordsByFilter)  // 按过滤条件批量更新
+		tables.DELETE("/:tableId/records/by-filter", handler.DeleteRecordsByFilter) // 按过滤条件批量删除
 	}
 
 	// 记录路由（保留旧路由以兼容，但标记为废弃）
@@ -235,6 +254,20 @@ func setupRecordRoutes(rg *gin.RouterGroup, cont *container.Container) {
 	}
 }
 
+// setupRecordRuleRoutes 设置表级记录校验规则路由
+func setupRecordRuleRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewRecordRuleHandler(cont.RecordRuleService())
+	permissionMiddleware := middleware.NewPermissionMiddleware(cont.PermissionServiceV2())
+
+	tables := rg.Group("/tables")
+	{
+		tables.GET("/:tableId/record-rules", permissionMiddleware.RequireTableAccess(), handler.ListRecordRules)
+		tables.POST("/:tableId/record-rules", permissionMiddleware.RequireTableAccess(), handler.CreateRecordRule)
+		tables.PATCH("/:tableId/record-rules/:ruleId", permissionMiddleware.RequireTableAccess(), handler.UpdateRecordRule)
+		tables.DELETE("/:tableId/record-rules/:ruleId", permissionMiddleware.RequireTableAccess(), handler.DeleteRecordRule)
+	}
+}
+
 // setupUserRoutes 设置用户路由
 func setupUserRoutes(rg *gin.RouterGroup, cont *container.Container) {
 	handler := NewUserHandler(cont.UserService())
@@ -352,6 +385,90 @@ func setupAttachmentRoutes(rg *gin.RouterGroup, cont *container.Container) {
 	}
 }
 
+// setupSearchRoutes 设置全局搜索路由（结果限定在当前用户可访问的空间和 Base 内）
+func setupSearchRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewSearchHandler(cont.SearchService(), cont.SearchIndexer(), logger.Logger)
+	permissionMiddleware := middleware.NewPermissionMiddleware(cont.PermissionServiceV2())
+
+	searchGroup := rg.Group("/search")
+	{
+		searchGroup.GET("", handler.Search)
+		searchGroup.POST("/advanced", handler.AdvancedSearch)
+		searchGroup.GET("/suggestions", handler.SearchSuggestions)
+		// 热门查询与搜索统计汇总全部用户的查询历史，仅系统管理员
+		searchGroup.GET("/popular", permissionMiddleware.RequireSystemAdmin(), handler.GetPopularQueries)
+		searchGroup.GET("/stats", permissionMiddleware.RequireSystemAdmin(), handler.GetSearchStats)
+
+		// 索引管理（条目读写限定在可访问的空间和 Base 内；全局重建、优化与统计仅系统管理员）
+		indexes := searchGroup.Group("/indexes")
+		{
+			indexes.GET("", handler.ListIndexes)
+			indexes.POST("", handler.CreateIndex)
+			indexes.GET("/stats", permissionMiddleware.RequireSystemAdmin(), handler.GetIndexStats)
+			indexes.POST("/rebuild", permissionMiddleware.RequireSystemAdmin(), handler.RebuildIndex)
+			indexes.POST("/optimize", permissionMiddleware.RequireSystemAdmin(), handler.OptimizeIndex)
+			indexes.POST("/bases/:baseId/rebuild", permissionMiddleware.RequireBaseAccess(), handler.RebuildBaseIndex)
+			indexes.GET("/jobs/:jobId", handler.GetRebuildJob)
+			indexes.DELETE("/by-source", handler.DeleteIndexesBySource)
+			indexes.GET("/:id", handler.GetIndex)
+			indexes.PUT("/:id", handler.UpdateIndex)
+			indexes.DELETE("/:id", handler.DeleteIndex)
+		}
+	}
+}
+
+// setupImportRoutes 设置数据导入路由
+func setupImportRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewImportHandler(cont.ImportService())
+	permissionMiddleware := middleware.NewPermissionMiddleware(cont.PermissionServiceV2())
+
+	bases := rg.Group("/bases")
+	{
+		bases.POST("/:baseId/imports/csv/preview", permissionMiddleware.RequireBaseAccess(), handler.PreviewCSV)
+		bases.POST("/:baseId/imports/csv", permissionMiddleware.RequireBaseAccess(), handler.ImportCSV)
+		bases.POST("/:baseId/imports/xlsx/preview", permissionMiddleware.RequireBaseAccess(), handler.PreviewXLSX)
+		bases.POST("/:baseId/imports/xlsx", permissionMiddleware.RequireBaseAccess(), handler.ImportXLSX)
+	}
+
+	imports := rg.Group("/imports")
+	{
+		imports.GET("/:taskId", handler.GetImportTask)
+	}
+}
+
+// setupExportRoutes 设置数据导出路由
+func setupExportRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewExportHandler(cont.ExportService())
+	permissionMiddleware := middleware.NewPermissionMiddleware(cont.PermissionServiceV2())
+
+	tables := rg.Group("/tables")
+	{
+		tables.GET("/:tableId/export", permissionMiddleware.RequireTableAccess(), handler.ExportTable)
+	}
+
+	exports := rg.Group("/exports")
+	{
+		exports.GET("/:taskId", handler.GetExportTask)
+		exports.GET("/:taskId/download", handler.DownloadExport)
+	}
+}
+
+// setupBaseArchiveRoutes 设置Base归档导出与导入路由
+func setupBaseArchiveRoutes(rg *gin.RouterGroup, cont *container.Container) {
+	handler := NewBaseArchiveHandler(cont.BaseArchiveService())
+	permissionMiddleware := middleware.NewPermissionMiddleware(cont.PermissionServiceV2())
+
+	spaces := rg.Group("/spaces")
+	{
+		spaces.POST("/:spaceId/bases/import", permissionMiddleware.RequireSpaceAccess(), handler.ImportBase)
+	}
+
+	bases := rg.Group("/bases")
+	{
+		bases.GET("/:baseId/archive", permissionMiddleware.RequireBaseAccess(), handler.ExportBase)
+	}
+}
+
 // setupWebSocketRoutes 设置WebSocket路由 ✨
 // 旧 WebSocket 路由已移除
 
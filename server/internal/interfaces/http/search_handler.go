@@ -6,7 +6,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
+	"github.com/easyspace-ai/luckdb/server/internal/application/searchindex"
 	"github.com/easyspace-ai/luckdb/server/internal/domain/search"
+	"github.com/easyspace-ai/luckdb/server/pkg/authctx"
 	"github.com/easyspace-ai/luckdb/server/pkg/errors"
 	"github.com/easyspace-ai/luckdb/server/pkg/response"
 )
@@ -14,16 +16,18 @@ import (
 // SearchHandler 搜索处理器
 type SearchHandler struct {
 	service search.Service
+	indexer *searchindex.Indexer
 	logger  *zap.Logger
 }
 
 // NewSearchHandler 创建搜索处理器
-func NewSearchHandler(service search.Service, logger *zap.Logger) *SearchHandler {
+func NewSearchHandler(service search.Service, indexer *searchindex.Indexer, logger *zap.Logger) *SearchHandler {
 	if logger == nil {
 		logger, _ = zap.NewProduction()
 	}
 	return &SearchHandler{
 		service: service,
+		indexer: indexer,
 		logger:  logger,
 	}
 }
@@ -41,6 +45,7 @@ func NewSearchHandler(service search.Service, logger *zap.Logger) *SearchHandler
 // @Param source_type query string false "来源类型"
 // @Param user_id query string false "用户ID"
 // @Param space_id query string false "空间ID"
+// @Param base_id query string false "Base ID"
 // @Param table_id query string false "表格ID"
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(20)
@@ -73,6 +78,7 @@ func (h *SearchHandler) Search(c *gin.Context) {
 	req.SourceType = c.Query("source_type")
 	req.UserID = c.Query("user_id")
 	req.SpaceID = c.Query("space_id")
+	req.BaseID = c.Query("base_id")
 	req.TableID = c.Query("table_id")
 
 	// 分页参数
@@ -142,7 +148,7 @@ func (h *SearchHandler) AdvancedSearch(c *gin.Context) {
 
 // SearchSuggestions 搜索建议
 // @Summary 搜索建议
-// @Description 获取搜索建议
+// @Description 获取标题匹配关键词、且在当前用户可访问的空间和 Base 内的搜索建议
 // @Tags 搜索管理
 // @Accept json
 // @Produce json
@@ -180,7 +186,7 @@ func (h *SearchHandler) SearchSuggestions(c *gin.Context) {
 
 // GetPopularQueries 获取热门查询
 // @Summary 获取热门查询
-// @Description 获取热门搜索查询
+// @Description 获取热门搜索查询（仅系统管理员）
 // @Tags 搜索管理
 // @Accept json
 // @Produce json
@@ -208,7 +214,7 @@ func (h *SearchHandler) GetPopularQueries(c *gin.Context) {
 
 // GetSearchStats 获取搜索统计
 // @Summary 获取搜索统计
-// @Description 获取搜索系统统计信息
+// @Description 获取搜索系统统计信息（仅系统管理员）
 // @Tags 搜索管理
 // @Accept json
 // @Produce json
@@ -393,7 +399,7 @@ func (h *SearchHandler) DeleteIndexesBySource(c *gin.Context) {
 
 // ListIndexes 列出搜索索引
 // @Summary 列出搜索索引
-// @Description 获取搜索索引列表
+// @Description 获取当前用户可访问的空间和 Base 内的搜索索引列表
 // @Tags 搜索索引管理
 // @Accept json
 // @Produce json
@@ -453,7 +459,7 @@ func (h *SearchHandler) ListIndexes(c *gin.Context) {
 
 // RebuildIndex 重建索引
 // @Summary 重建索引
-// @Description 重建搜索索引
+// @Description 重建全部搜索索引（仅系统管理员）
 // @Tags 搜索索引管理
 // @Accept json
 // @Produce json
@@ -471,7 +477,7 @@ func (h *SearchHandler) RebuildIndex(c *gin.Context) {
 	err := h.service.RebuildIndex(c.Request.Context(), searchType)
 	if err != nil {
 		h.logger.Error("Failed to rebuild search index",
-			zap.String("type", string(*searchType)),
+			zap.String("type", c.Query("type")),
 			zap.Error(err))
 		response.Error(c, err)
 		return
@@ -480,9 +486,65 @@ func (h *SearchHandler) RebuildIndex(c *gin.Context) {
 	response.SuccessWithMessage(c, nil, "搜索索引重建成功")
 }
 
+// RebuildBaseIndex 重建 Base 索引
+// @Summary 重建 Base 索引
+// @Description 在后台重建 Base 内全部表格、字段与记录的搜索索引，返回任务及进度
+// @Tags 搜索索引管理
+// @Accept json
+// @Produce json
+// @Param baseId path string true "Base ID"
+// @Success 200 {object} response.Response{data=searchindex.RebuildJob} "重建任务已开始"
+// @Failure 404 {object} response.Response "Base不存在"
+// @Failure 500 {object} response.Response "服务器内部错误"
+// @Router /api/search/indexes/bases/{baseId}/rebuild [post]
+func (h *SearchHandler) RebuildBaseIndex(c *gin.Context) {
+	userID, exists := authctx.UserFrom(c.Request.Context())
+	if !exists || userID == "" {
+		response.Error(c, errors.ErrUnauthorized)
+		return
+	}
+
+	job, err := h.indexer.RebuildBase(c.Request.Context(), c.Param("baseId"), userID)
+	if err != nil {
+		h.logger.Error("Failed to start base search index rebuild",
+			zap.String("base_id", c.Param("baseId")),
+			zap.Error(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, job, "搜索索引重建任务已开始")
+}
+
+// GetRebuildJob 获取索引重建任务进度
+// @Summary 获取索引重建任务进度
+// @Description 获取 Base 索引重建任务的状态与进度（只能查看自己发起或加入的任务）
+// @Tags 搜索索引管理
+// @Accept json
+// @Produce json
+// @Param jobId path string true "任务ID"
+// @Success 200 {object} response.Response{data=searchindex.RebuildJob} "获取成功"
+// @Failure 404 {object} response.Response "任务不存在"
+// @Router /api/search/indexes/jobs/{jobId} [get]
+func (h *SearchHandler) GetRebuildJob(c *gin.Context) {
+	userID, exists := authctx.UserFrom(c.Request.Context())
+	if !exists || userID == "" {
+		response.Error(c, errors.ErrUnauthorized)
+		return
+	}
+
+	job, ok := h.indexer.GetRebuildJob(c.Param("jobId"), userID)
+	if !ok {
+		response.Error(c, errors.ErrNotFound.WithDetails("重建任务不存在"))
+		return
+	}
+
+	response.Success(c, job, "获取重建任务成功")
+}
+
 // OptimizeIndex 优化索引
 // @Summary 优化索引
-// @Description 优化搜索索引
+// @Description 优化搜索索引（仅系统管理员）
 // @Tags 搜索索引管理
 // @Accept json
 // @Produce json
@@ -502,7 +564,7 @@ func (h *SearchHandler) OptimizeIndex(c *gin.Context) {
 
 // GetIndexStats 获取索引统计
 // @Summary 获取索引统计
-// @Description 获取搜索索引统计信息
+// @Description 获取搜索索引统计信息（仅系统管理员）
 // @Tags 搜索索引管理
 // @Accept json
 // @Produce json
@@ -174,11 +174,46 @@ func (h *TableHandler) DuplicateTable(c *gin.Context) {
 		logger.String("new_name", req.Name),
 		logger.Bool("with_data", req.WithData),
 		logger.Bool("with_views", req.WithViews),
-		logger.Bool("with_fields", req.WithFields))
+		logger.Bool("with_fields", req.WithFields),
+		logger.String("link_mode", req.LinkMode))
 
 	response.Success(c, table, "表格复制成功")
 }
 
+// MoveTable 把表格移到同一空间下的另一个Base
+// POST /api/v1/tables/:tableId/move
+func (h *TableHandler) MoveTable(c *gin.Context) {
+	tableID := c.Param("tableId")
+	if tableID == "" {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails("表格ID不能为空"))
+		return
+	}
+
+	var req dto.MoveTableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, pkgerrors.ErrBadRequest.WithDetails(err.Error()))
+		return
+	}
+
+	userID := getUserIDFromContext(c)
+	if userID == "" {
+		response.Error(c, pkgerrors.ErrUnauthorized.WithDetails("用户未认证"))
+		return
+	}
+
+	result, err := h.tableService.MoveTable(c.Request.Context(), tableID, req, userID)
+	if err != nil {
+		logger.Error("移动表失败",
+			logger.String("table_id", tableID),
+			logger.String("base_id", req.BaseID),
+			logger.ErrorField(err))
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result, "表格移动成功")
+}
+
 // GetTableUsage 获取表用量信息
 // GET /api/v1/tables/:tableId/usage
 func (h *TableHandler) GetTableUsage(c *gin.Context) {
@@ -257,7 +292,7 @@ func (h *TableHandler) GetTableManagementMenu(c *gin.Context) {
 				"icon":    "copy",
 			},
 			"move": gin.H{
-				"enabled": false, // 暂时禁用
+				"enabled": true,
 				"label":   "移动至",
 				"icon":    "move",
 			},
@@ -182,3 +182,16 @@ func (m *PermissionMiddleware) RequireOwner() gin.HandlerFunc {
 func (m *PermissionMiddleware) RequireEditor() gin.HandlerFunc {
 	return m.RequireSpaceRole(entity.RoleEditor)
 }
+
+// RequireSystemAdmin 要求系统管理员（JWT 认证中间件写入的 is_admin 声明）
+func (m *PermissionMiddleware) RequireSystemAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !c.GetBool("is_admin") {
+			response.Error(c, errors.ErrForbidden.WithDetails("system admin required"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
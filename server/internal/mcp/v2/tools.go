@@ -178,6 +178,7 @@ func (m *MCPServerV2) registerRecordTools() error {
 			mcp.WithDescription("Create a record"),
 			mcp.WithString("tableId", mcp.Required()),
 			mcp.WithObject("data", mcp.Required()),
+			mcp.WithBoolean("typecast", mcp.Description("Coerce loosely typed values: strings to numbers/dates, choice names, user emails/names and linked record titles to ids")),
 		),
 		m.handleRecordCreate,
 	)
@@ -199,6 +200,7 @@ func (m *MCPServerV2) registerRecordTools() error {
 			mcp.WithString("tableId", mcp.Required()),
 			mcp.WithString("recordId", mcp.Required()),
 			mcp.WithObject("data", mcp.Required()),
+			mcp.WithBoolean("typecast", mcp.Description("Coerce loosely typed values: strings to numbers/dates, choice names, user emails/names and linked record titles to ids")),
 		),
 		m.handleRecordUpdate,
 	)
@@ -220,10 +222,114 @@ func (m *MCPServerV2) registerRecordTools() error {
 			mcp.WithString("tableId", mcp.Required()),
 			mcp.WithNumber("pageSize"),
 			mcp.WithNumber("page"),
+			mcp.WithString("viewId",
+				mcp.Description("Apply the view's filter, sort and hidden columns"),
+			),
+			mcp.WithString("cursor",
+				mcp.Description("nextCursor from the previous page; keyset pagination that keeps the sort order stable"),
+			),
+			mcp.WithObject("filter",
+				mcp.Description(`Filter tree: {"operator":"and|or","filters":[{"fieldId":"fld_xxx","operator":"is|contains|isGreater|isWithin|hasAnyOf|isEmpty|...","value":...}],"groups":[<nested filter>]}`),
+			),
+			mcp.WithArray("sort",
+				mcp.Description(`Sort keys applied in order: [{"fieldId":"fld_xxx","order":"asc|desc"}]`),
+				mcp.Items(map[string]any{"type": "object"}),
+			),
+			mcp.WithArray("projection",
+				mcp.Description("Field IDs to return; omit to return every field"),
+				mcp.WithStringItems(),
+			),
+			mcp.WithString("cellFormat",
+				mcp.Description("json returns raw cell values, text returns display strings (formatted dates, select names, user names, link titles)"),
+				mcp.Enum("json", "text"),
+			),
+			mcp.WithString("search",
+				mcp.Description("Full-text keyword over text, select and link fields; pinyin also matches Chinese text. Each record lists matched field IDs in searchHits"),
+			),
 		),
 		m.handleRecordList,
 	)
 
+	// record.group
+	m.server.AddTool(
+		mcp.NewTool("record.group",
+			mcp.WithDescription("Group records by up to three fields and return group headers with counts and per-group aggregates"),
+			mcp.WithString("tableId", mcp.Required()),
+			mcp.WithString("viewId",
+				mcp.Description("Apply the view's filter; the view's group config is used when groupBy is omitted"),
+			),
+			mcp.WithObject("filter",
+				mcp.Description("Filter tree, same structure as record.list"),
+			),
+			mcp.WithArray("groupBy",
+				mcp.Description(`Group levels in order (max 3): [{"fieldId":"fld_xxx","order":"asc|desc"}]`),
+				mcp.Items(map[string]any{"type": "object"}),
+			),
+			mcp.WithArray("aggregations",
+				mcp.Description(`Aggregates per group: [{"fieldId":"fld_xxx","func":"..."}]; functions are the same as record.statistics`),
+				mcp.Items(map[string]any{"type": "object"}),
+			),
+		),
+		m.handleRecordGroup,
+	)
+
+	// record.statistics
+	m.server.AddTool(
+		mcp.NewTool("record.statistics",
+			mcp.WithDescription("Summary statistics of columns over a filtered record set"),
+			mcp.WithString("tableId", mcp.Required()),
+			mcp.WithString("viewId",
+				mcp.Description("Apply the view's filter"),
+			),
+			mcp.WithObject("filter",
+				mcp.Description("Filter tree, same structure as record.list"),
+			),
+			mcp.WithObject("statistics", mcp.Required(),
+				mcp.Description(`Field ID to function, e.g. {"fld_amount":"sum"}. All fields: emptyCount, filledCount, uniqueCount, percentEmpty, percentFilled, percentUnique; `+
+					`numbers: sum, avg, min, max, median; dates: earliestDate, latestDate; checkboxes: checkedCount, uncheckedCount, percentChecked, percentUnchecked`),
+			),
+		),
+		m.handleRecordStatistics,
+	)
+
+	// record.updateByFilter
+	m.server.AddTool(
+		mcp.NewTool("record.updateByFilter",
+			mcp.WithDescription("Set the same field values on every record matching a filter; use dryRun first to see how many records match"),
+			mcp.WithString("tableId", mcp.Required()),
+			mcp.WithString("viewId",
+				mcp.Description("Only records in the view (combined with filter by AND)"),
+			),
+			mcp.WithObject("filter",
+				mcp.Description("Filter tree, same structure as record.list"),
+			),
+			mcp.WithObject("data", mcp.Required(),
+				mcp.Description("Field ID or name to new value; link fields are not supported"),
+			),
+			mcp.WithBoolean("typecast", mcp.Description("Coerce loosely typed values, same as record.update")),
+			mcp.WithBoolean("dryRun", mcp.Description("Only count matching records without changing anything")),
+			mcp.WithNumber("maxAffected", mcp.Description("Refuse to run when more records match (default 10000)")),
+		),
+		m.handleRecordUpdateByFilter,
+	)
+
+	// record.deleteByFilter
+	m.server.AddTool(
+		mcp.NewTool("record.deleteByFilter",
+			mcp.WithDescription("Delete every record matching a filter; use dryRun first to see how many records match"),
+			mcp.WithString("tableId", mcp.Required()),
+			mcp.WithString("viewId",
+				mcp.Description("Only records in the view (combined with filter by AND)"),
+			),
+			mcp.WithObject("filter",
+				mcp.Description("Filter tree, same structure as record.list"),
+			),
+			mcp.WithBoolean("dryRun", mcp.Description("Only count matching records without deleting anything")),
+			mcp.WithNumber("maxAffected", mcp.Description("Refuse to run when more records match (default 10000)")),
+		),
+		m.handleRecordDeleteByFilter,
+	)
+
 	return nil
 }
 
@@ -531,8 +637,13 @@ func (m *MCPServerV2) handleRecordCreate(ctx context.Context, req mcp.CallToolRe
 		return mcp.NewToolResultError("tableId and data are required"), nil
 	}
 
-	createReq := dto.CreateRecordRequest{TableID: tableID, Data: data}
-	result, err := m.cont.RecordService().CreateRecord(ctx, createReq, "mcp")
+	// 使用 API Key 对应的用户创建记录（用户字段的“当前用户”默认值取该用户）
+	userID, ok := getUserIDFromContext(ctx)
+	if !ok {
+		userID = "mcp"
+	}
+	createReq := dto.CreateRecordRequest{TableID: tableID, Data: data, Typecast: mcp.ParseBoolean(req, "typecast", false)}
+	result, err := m.cont.RecordService().CreateRecord(ctx, createReq, userID)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to create record: %v", err)), nil
 	}
@@ -565,7 +676,7 @@ func (m *MCPServerV2) handleRecordUpdate(ctx context.Context, req mcp.CallToolRe
 		return mcp.NewToolResultError("tableId, recordId and data are required"), nil
 	}
 
-	updateReq := dto.UpdateRecordRequest{Data: data}
+	updateReq := dto.UpdateRecordRequest{Data: data, Typecast: mcp.ParseBoolean(req, "typecast", false)}
 	result, err := m.cont.RecordService().UpdateRecord(ctx, tableID, recordID, updateReq, "mcp")
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to update record: %v", err)), nil
@@ -594,6 +705,13 @@ func (m *MCPServerV2) handleRecordList(ctx context.Context, req mcp.CallToolRequ
 	tableID := mcp.ParseString(req, "tableId", "")
 	pageSize := mcp.ParseInt(req, "pageSize", 10)
 	page := mcp.ParseInt(req, "page", 0)
+	filter := mcp.ParseStringMap(req, "filter", nil)
+	sort := parseObjectList(req, "sort")
+	viewID := mcp.ParseString(req, "viewId", "")
+	cursor := mcp.ParseString(req, "cursor", "")
+	projection := parseStringList(req, "projection")
+	cellFormat := mcp.ParseString(req, "cellFormat", "")
+	search := mcp.ParseString(req, "search", "")
 
 	if tableID == "" {
 		return mcp.NewToolResultError("tableId is required"), nil
@@ -605,14 +723,124 @@ func (m *MCPServerV2) handleRecordList(ctx context.Context, req mcp.CallToolRequ
 	}
 	offset := page * limit
 
-	items, total, err := m.cont.RecordService().ListRecords(ctx, tableID, limit, offset)
+	listReq := dto.ListRecordsRequest{
+		Limit:      limit,
+		Offset:     offset,
+		Filter:     filter,
+		Sort:       sort,
+		ViewID:     viewID,
+		Cursor:     cursor,
+		Projection: projection,
+		CellFormat: cellFormat,
+		Search:     search,
+	}
+	listResult, err := m.cont.RecordService().ListRecords(ctx, tableID, listReq)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to list records: %v", err)), nil
 	}
 
 	result := map[string]interface{}{
-		"items": items,
-		"total": total,
+		"items": listResult.Records,
+		"total": listResult.Pagination.Total,
+	}
+	if listResult.Pagination.NextCursor != "" {
+		result["nextCursor"] = listResult.Pagination.NextCursor
+	}
+
+	return mcp.NewToolResultText(marshalJSON(result)), nil
+}
+
+func (m *MCPServerV2) handleRecordGroup(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	tableID := mcp.ParseString(req, "tableId", "")
+	if tableID == "" {
+		return mcp.NewToolResultError("tableId is required"), nil
+	}
+
+	groupReq := dto.GroupRecordsRequest{
+		Filter:  mcp.ParseStringMap(req, "filter", nil),
+		ViewID:  mcp.ParseString(req, "viewId", ""),
+		GroupBy: parseObjectList(req, "groupBy"),
+	}
+	for _, item := range parseObjectList(req, "aggregations") {
+		fieldID, _ := item["fieldId"].(string)
+		fn, _ := item["func"].(string)
+		groupReq.Aggregations = append(groupReq.Aggregations, dto.AggregationRequest{FieldID: fieldID, Func: fn})
+	}
+
+	result, err := m.cont.RecordService().GroupRecords(ctx, tableID, groupReq)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to group records: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(marshalJSON(result)), nil
+}
+
+func (m *MCPServerV2) handleRecordStatistics(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	tableID := mcp.ParseString(req, "tableId", "")
+	if tableID == "" {
+		return mcp.NewToolResultError("tableId is required"), nil
+	}
+
+	statsReq := dto.RecordStatisticsRequest{
+		Filter:     mcp.ParseStringMap(req, "filter", nil),
+		ViewID:     mcp.ParseString(req, "viewId", ""),
+		Statistics: make(map[string]string),
+	}
+	for fieldID, fn := range mcp.ParseStringMap(req, "statistics", nil) {
+		if name, ok := fn.(string); ok {
+			statsReq.Statistics[fieldID] = name
+		}
+	}
+	if len(statsReq.Statistics) == 0 {
+		return mcp.NewToolResultError("statistics is required"), nil
+	}
+
+	result, err := m.cont.RecordService().GetRecordStatistics(ctx, tableID, statsReq)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to compute statistics: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(marshalJSON(result)), nil
+}
+
+func (m *MCPServerV2) handleRecordUpdateByFilter(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	tableID := mcp.ParseString(req, "tableId", "")
+	data := mcp.ParseStringMap(req, "data", nil)
+	if tableID == "" || len(data) == 0 {
+		return mcp.NewToolResultError("tableId and data are required"), nil
+	}
+
+	updateReq := dto.UpdateRecordsByFilterRequest{
+		Filter:      mcp.ParseStringMap(req, "filter", nil),
+		ViewID:      mcp.ParseString(req, "viewId", ""),
+		Fields:      data,
+		Typecast:    mcp.ParseBoolean(req, "typecast", false),
+		DryRun:      mcp.ParseBoolean(req, "dryRun", false),
+		MaxAffected: mcp.ParseInt(req, "maxAffected", 0),
+	}
+	result, err := m.cont.RecordService().UpdateRecordsByFilter(ctx, tableID, updateReq, "mcp")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to update records: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(marshalJSON(result)), nil
+}
+
+func (m *MCPServerV2) handleRecordDeleteByFilter(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	tableID := mcp.ParseString(req, "tableId", "")
+	if tableID == "" {
+		return mcp.NewToolResultError("tableId is required"), nil
+	}
+
+	deleteReq := dto.DeleteRecordsByFilterRequest{
+		Filter:      mcp.ParseStringMap(req, "filter", nil),
+		ViewID:      mcp.ParseString(req, "viewId", ""),
+		DryRun:      mcp.ParseBoolean(req, "dryRun", false),
+		MaxAffected: mcp.ParseInt(req, "maxAffected", 0),
+	}
+	result, err := m.cont.RecordService().DeleteRecordsByFilter(ctx, tableID, deleteReq)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to delete records: %v", err)), nil
 	}
 
 	return mcp.NewToolResultText(marshalJSON(result)), nil
@@ -620,6 +848,38 @@ func (m *MCPServerV2) handleRecordList(ctx context.Context, req mcp.CallToolRequ
 
 // ==================== 辅助函数 ====================
 
+// parseObjectList 解析对象数组参数（忽略非对象元素）
+func parseObjectList(req mcp.CallToolRequest, key string) []map[string]interface{} {
+	raw, ok := mcp.ParseArgument(req, key, nil).([]interface{})
+	if !ok {
+		return nil
+	}
+
+	items := make([]map[string]interface{}, 0, len(raw))
+	for _, v := range raw {
+		if item, ok := v.(map[string]interface{}); ok {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseStringList 解析字符串数组参数（忽略非字符串与空元素）
+func parseStringList(req mcp.CallToolRequest, key string) []string {
+	raw, ok := mcp.ParseArgument(req, key, nil).([]interface{})
+	if !ok {
+		return nil
+	}
+
+	items := make([]string, 0, len(raw))
+	for _, v := range raw {
+		if item, ok := v.(string); ok && item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // getUserIDFromContext 从上下文获取用户ID
 func getUserIDFromContext(ctx context.Context) (string, bool) {
 	// 尝试从 context 值获取
@@ -83,3 +83,9 @@ type Service interface {
 	// Shutdown 关闭服务
 	Shutdown() error
 }
+
+// RecordCreator 处理客户端提交的记录创建操作
+// 由记录服务实现：填充默认值、校验并持久化，返回最终写入的字段值；创建事件由记录服务广播
+type RecordCreator interface {
+	CreateRecordFromOp(ctx context.Context, tableID, recordID string, fields map[string]interface{}, userID string) (map[string]interface{}, error)
+}
@@ -32,6 +32,7 @@ type ShareDBService struct {
 	documents      sync.Map // document ID -> *Document
 	eventHook      *TransactionHook
 	eventConverter *OpsToEventsConverter
+	recordCreator  RecordCreator // 处理记录创建操作（可选）
 	errorHandler   *errors.ErrorHandler
 	perfMonitor    *monitoring.PerformanceMonitor
 	perfMiddleware *monitoring.PerformanceMiddleware
@@ -88,6 +89,11 @@ func (s *ShareDBService) SetEventManager(eventManager *events.BusinessEventManag
 	s.logger.Info("✅ ShareDB 事件管理器已设置")
 }
 
+// SetRecordCreator 设置记录创建处理器，未设置时拒绝客户端的创建操作
+func (s *ShareDBService) SetRecordCreator(recordCreator RecordCreator) {
+	s.recordCreator = recordCreator
+}
+
 // AddMiddleware 添加中间件
 func (s *ShareDBService) AddMiddleware(middleware Middleware) {
 	s.mu.Lock()
@@ -720,6 +726,11 @@ func (s *ShareDBService) doHandleOperation(conn *websocket.Conn, connection *Con
 		zap.Int64("version", msg.Version),
 		zap.Int("opCount", len(msg.Op)))
 
+	// 创建文档操作
+	if msg.Create != nil {
+		return s.handleCreate(conn, connection, msg)
+	}
+
 	// 基本验证
 	if len(msg.Op) == 0 {
 		s.logger.Error("操作列表为空")
@@ -769,6 +780,56 @@ func (s *ShareDBService) doHandleOperation(conn *websocket.Conn, connection *Con
 	return s.sendMessage(conn, response)
 }
 
+// handleCreate 处理记录创建操作
+//
+// 只支持记录集合（rec_<tableId>），客户端生成的文档ID作为记录ID。记录服务填充默认值后写入，
+// 确认消息中带回最终的字段值，其他客户端通过记录服务的创建事件收到同样的数据
+func (s *ShareDBService) handleCreate(conn *websocket.Conn, connection *Connection, msg *Message) error {
+	if !strings.HasPrefix(msg.Collection, "rec_") {
+		return s.sendError(conn, msg, errors.NewShareDBError(errors.ErrOperationInvalid, "only record documents can be created"))
+	}
+	if s.recordCreator == nil {
+		return s.sendError(conn, msg, errors.NewShareDBError(errors.ErrOperationRejected, "record creation is not supported"))
+	}
+	tableID := strings.TrimPrefix(msg.Collection, "rec_")
+
+	// 快照格式为 { data: { fieldId: value } }，也兼容 { fields: {...} } 或直接传字段
+	fields := make(map[string]interface{})
+	if data, ok := msg.Create.Data.(map[string]interface{}); ok {
+		fields = data
+		if inner, ok := data["data"].(map[string]interface{}); ok {
+			fields = inner
+		} else if inner, ok := data["fields"].(map[string]interface{}); ok {
+			fields = inner
+		}
+	}
+
+	created, err := s.recordCreator.CreateRecordFromOp(s.ctx, tableID, msg.DocID, fields, connection.UserID)
+	if err != nil {
+		s.logger.Warn("创建记录失败",
+			zap.String("collection", msg.Collection),
+			zap.String("docID", msg.DocID),
+			zap.Error(err))
+		return s.sendError(conn, msg, errors.NewShareDBError(errors.ErrRecordInvalid, err.Error()))
+	}
+
+	createType := msg.Create.Type
+	if createType == "" {
+		createType = "json0"
+	}
+	response := &Message{
+		Action:     "op",
+		Collection: msg.Collection,
+		DocID:      msg.DocID,
+		Version:    msg.Version,
+		Create: &CreateData{
+			Type: createType,
+			Data: map[string]interface{}{"data": created},
+		},
+	}
+	return s.sendMessage(conn, response)
+}
+
 // handlePresence 处理在线状态
 func (s *ShareDBService) handlePresence(conn *websocket.Conn, connection *Connection, msg *Message) error {
 	// 解析在线状态数据
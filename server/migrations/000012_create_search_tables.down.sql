@@ -0,0 +1,6 @@
+-- 回滚：删除全局搜索相关表
+-- 迁移：000012_create_search_tables
+
+DROP TABLE IF EXISTS search_stats;
+DROP TABLE IF EXISTS search_suggestions;
+DROP TABLE IF EXISTS search_indexes;
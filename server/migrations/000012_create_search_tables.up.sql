@@ -0,0 +1,66 @@
+-- 全局搜索：索引条目、搜索建议与搜索统计
+-- 迁移：000012_create_search_tables
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE TABLE IF NOT EXISTS search_indexes (
+    id VARCHAR(64) PRIMARY KEY,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(500) NOT NULL,
+    content TEXT NOT NULL DEFAULT '',
+    keywords JSON,
+    source_id VARCHAR(64) NOT NULL,
+    source_type VARCHAR(50) NOT NULL,
+    source_url VARCHAR(1000),
+    metadata JSON,
+    user_id VARCHAR(64),
+    space_id VARCHAR(64),
+    base_id VARCHAR(64),
+    table_id VARCHAR(64),
+    field_id VARCHAR(64),
+    permissions JSON,
+    tags JSON,
+    created_time TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_time TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- 每个来源只有一条索引（重建与增量更新按来源 upsert）
+CREATE UNIQUE INDEX IF NOT EXISTS uq_search_indexes_source ON search_indexes(source_type, source_id);
+CREATE INDEX IF NOT EXISTS idx_search_indexes_type ON search_indexes(type);
+CREATE INDEX IF NOT EXISTS idx_search_indexes_space_id ON search_indexes(space_id);
+CREATE INDEX IF NOT EXISTS idx_search_indexes_base_id ON search_indexes(base_id);
+CREATE INDEX IF NOT EXISTS idx_search_indexes_table_id ON search_indexes(table_id);
+CREATE INDEX IF NOT EXISTS idx_search_indexes_user_id ON search_indexes(user_id);
+
+-- 全文检索（simple 分词）与子串匹配（pg_trgm，中文无需分词）
+CREATE INDEX IF NOT EXISTS idx_search_indexes_tsv ON search_indexes
+    USING GIN (to_tsvector('simple', COALESCE(title, '') || ' ' || COALESCE(content, '')));
+CREATE INDEX IF NOT EXISTS idx_search_indexes_title_trgm ON search_indexes USING GIN (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_search_indexes_content_trgm ON search_indexes USING GIN (content gin_trgm_ops);
+
+CREATE TABLE IF NOT EXISTS search_suggestions (
+    id VARCHAR(20) PRIMARY KEY,
+    query VARCHAR(500) NOT NULL,
+    count BIGINT NOT NULL DEFAULT 1,
+    type VARCHAR(50),
+    source_id VARCHAR(64),
+    source_type VARCHAR(50),
+    created_time TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_time TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_search_suggestions_query ON search_suggestions(query);
+CREATE INDEX IF NOT EXISTS idx_search_suggestions_count ON search_suggestions(count);
+CREATE INDEX IF NOT EXISTS idx_search_suggestions_query_trgm ON search_suggestions USING GIN (query gin_trgm_ops);
+
+CREATE TABLE IF NOT EXISTS search_stats (
+    id VARCHAR(20) PRIMARY KEY,
+    total_searches BIGINT NOT NULL DEFAULT 0,
+    total_indexes BIGINT NOT NULL DEFAULT 0,
+    average_query_time DOUBLE PRECISION NOT NULL DEFAULT 0,
+    last_updated TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE search_indexes IS '全局搜索索引（空间、Base、表格、字段、记录等）';
+COMMENT ON TABLE search_suggestions IS '搜索建议（按查询词计数）';
+COMMENT ON TABLE search_stats IS '搜索统计计数器（total、type:<类型>、scope:<范围>）';
@@ -0,0 +1,5 @@
+-- 回滚：删除后台任务相关表
+-- 迁移：000013_create_task_tables
+
+DROP TABLE IF EXISTS task_run;
+DROP TABLE IF EXISTS task;
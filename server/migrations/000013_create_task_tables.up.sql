@@ -0,0 +1,33 @@
+-- 后台任务（导入、导出等）及其运行记录
+-- 迁移：000013_create_task_tables
+
+CREATE TABLE IF NOT EXISTS task (
+    id VARCHAR(30) PRIMARY KEY,
+    type VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    snapshot TEXT,
+    created_time TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_modified_time TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(30) NOT NULL,
+    last_modified_by VARCHAR(50)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_created_by ON task(created_by);
+CREATE INDEX IF NOT EXISTS idx_task_type_status ON task(type, status);
+
+CREATE TABLE IF NOT EXISTS task_run (
+    id VARCHAR(30) PRIMARY KEY,
+    task_id VARCHAR(30) NOT NULL REFERENCES task(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    snapshot TEXT NOT NULL,
+    spent INTEGER,
+    error_msg TEXT,
+    started_time TIMESTAMPTZ,
+    created_time TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_modified_time TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_run_task_id ON task_run(task_id, created_time);
+
+COMMENT ON TABLE task IS '后台任务（snapshot 为发起参数）';
+COMMENT ON TABLE task_run IS '任务运行记录（snapshot 为进度与结果报告，spent 为耗时毫秒）';
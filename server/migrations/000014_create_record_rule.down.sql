@@ -0,0 +1,4 @@
+-- 回滚：删除记录校验规则表
+-- 迁移：000014_create_record_rule
+
+DROP TABLE IF EXISTS record_rule;
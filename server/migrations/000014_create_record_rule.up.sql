@@ -0,0 +1,19 @@
+-- 表级记录校验规则（公式表达式，记录写入前求值）
+-- 迁移：000014_create_record_rule
+
+CREATE TABLE IF NOT EXISTS record_rule (
+    id VARCHAR(30) PRIMARY KEY,
+    table_id VARCHAR(50) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    expression TEXT NOT NULL,
+    message TEXT,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_time TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_modified_time TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by VARCHAR(50) NOT NULL,
+    last_modified_by VARCHAR(50)
+);
+
+CREATE INDEX IF NOT EXISTS idx_record_rule_table_id ON record_rule(table_id, created_time);
+
+COMMENT ON TABLE record_rule IS '记录校验规则（expression 结果为假时拒绝写入，返回 message）';
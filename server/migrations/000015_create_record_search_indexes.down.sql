@@ -0,0 +1,15 @@
+-- 回滚：删除记录全文搜索索引（pg_trgm 扩展由 000012 创建，保留）
+-- 迁移：000015_create_record_search_indexes
+
+DO $$
+DECLARE
+    idx RECORD;
+BEGIN
+    FOR idx IN
+        SELECT schemaname, indexname
+        FROM pg_indexes
+        WHERE indexname LIKE 'idx\_search\_trgm\_%' OR indexname LIKE 'idx\_search\_tsv\_%'
+    LOOP
+        EXECUTE format('DROP INDEX IF EXISTS %I.%I', idx.schemaname, idx.indexname);
+    END LOOP;
+END $$;
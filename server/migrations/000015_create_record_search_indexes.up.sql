@@ -0,0 +1,36 @@
+-- 记录全文搜索索引：已有表中可搜索文本列的 pg_trgm GIN 索引，长文本另加 tsvector GIN 索引
+-- 迁移：000015_create_record_search_indexes
+--
+-- 之后新建或转换为文本类型的字段由字段服务在修改表结构时创建索引（FieldSchemaService.CreateSearchIndexes），
+-- 索引名与表达式须与其保持一致：idx_search_<trgm|tsv>_<left(md5(table_id || '|' || db_field_name), 16)>
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+DO $$
+DECLARE
+    f RECORD;
+    physical_table TEXT;
+    name_hash TEXT;
+BEGIN
+    FOR f IN
+        SELECT t.base_id, fd.table_id, fd.db_field_name, fd.type
+        FROM field fd
+        JOIN table_meta t ON t.id = fd.table_id AND t.deleted_time IS NULL
+        JOIN information_schema.columns c
+            ON c.table_schema = t.base_id AND c.table_name = fd.table_id AND c.column_name = fd.db_field_name
+        WHERE fd.deleted_time IS NULL
+            AND fd.db_field_name <> ''
+            AND fd.type IN ('text', 'singleLineText', 'longText', 'email', 'url', 'phone', 'select', 'singleSelect')
+            AND c.data_type IN ('text', 'character varying')
+    LOOP
+        physical_table := format('%I.%I', f.base_id, f.table_id);
+        name_hash := left(md5(f.table_id || '|' || f.db_field_name), 16);
+
+        EXECUTE format('CREATE INDEX IF NOT EXISTS %I ON %s USING gin (%I gin_trgm_ops)',
+            'idx_search_trgm_' || name_hash, physical_table, f.db_field_name);
+        IF f.type = 'longText' THEN
+            EXECUTE format('CREATE INDEX IF NOT EXISTS %I ON %s USING gin (to_tsvector(''simple'', COALESCE(%I, '''')))',
+                'idx_search_tsv_' || name_hash, physical_table, f.db_field_name);
+        END IF;
+    END LOOP;
+END $$;
@@ -8,6 +8,7 @@ import (
 
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/mattn/go-sqlite3"
+	"gorm.io/datatypes"
 
 	fieldEntity "github.com/easyspace-ai/luckdb/server/internal/domain/fields/entity"
 	fieldRepo "github.com/easyspace-ai/luckdb/server/internal/domain/fields/repository"
@@ -116,19 +117,8 @@ func handleSQLiteError(sqliteErr sqlite3.Error, tableID string, fieldRepo fieldR
 		fieldName := extractFieldNameFromSQLiteError(sqliteErr.Error())
 		field := findFieldByDBName(fieldName, tableID, fieldRepo, ctx)
 
-		if field != nil {
-			return errors.ErrValidationFailed.WithDetails(map[string]interface{}{
-				"type":       "unique",
-				"message":    fmt.Sprintf("字段【%s】的值重复，请修改后重试", field.Name().String()),
-				"field_id":   field.ID().String(),
-				"field_name": field.Name().String(),
-			})
-		}
-
-		return errors.ErrValidationFailed.WithDetails(map[string]interface{}{
-			"type":    "unique",
-			"message": "字段值重复",
-			"detail":  sqliteErr.Error(),
+		return UniqueViolationError(field, nil, map[string]interface{}{
+			"detail": sqliteErr.Error(),
 		})
 
 	case SqliteConstraintNotNull:
@@ -136,19 +126,8 @@ func handleSQLiteError(sqliteErr sqlite3.Error, tableID string, fieldRepo fieldR
 		fieldName := extractFieldNameFromSQLiteError(sqliteErr.Error())
 		field := findFieldByDBName(fieldName, tableID, fieldRepo, ctx)
 
-		if field != nil {
-			return errors.ErrValidationFailed.WithDetails(map[string]interface{}{
-				"type":       "notNull",
-				"message":    fmt.Sprintf("字段【%s】不能为空", field.Name().String()),
-				"field_id":   field.ID().String(),
-				"field_name": field.Name().String(),
-			})
-		}
-
-		return errors.ErrValidationFailed.WithDetails(map[string]interface{}{
-			"type":    "notNull",
-			"message": "必填字段不能为空",
-			"detail":  sqliteErr.Error(),
+		return NotNullViolationError(field, map[string]interface{}{
+			"detail": sqliteErr.Error(),
 		})
 
 	case SqliteBusy:
@@ -168,27 +147,20 @@ func handleSQLiteError(sqliteErr sqlite3.Error, tableID string, fieldRepo fieldR
 
 // handleUniqueViolation 处理唯一性约束违反
 func handleUniqueViolation(pgErr *pgconn.PgError, tableID string, fieldRepo fieldRepo.FieldRepository, ctx context.Context) error {
-	// 从约束名称中提取字段信息
-	fieldName := extractFieldNameFromConstraint(pgErr.ConstraintName)
+	// 优先从错误详情中提取列名和重复值（约束名称可能被截断），再回退到约束名称
+	fieldName, value := extractKeyFromDetail(pgErr.Detail)
+	if fieldName == "" {
+		fieldName = extractFieldNameFromConstraint(pgErr.ConstraintName)
+	}
 
 	// 查询字段信息
 	field := findFieldByDBName(fieldName, tableID, fieldRepo, ctx)
 
-	if field != nil {
-		return errors.ErrValidationFailed.WithDetails(map[string]interface{}{
-			"type":       "unique",
-			"message":    fmt.Sprintf("字段【%s】的值重复，请修改后重试", field.Name().String()),
-			"field_id":   field.ID().String(),
-			"field_name": field.Name().String(),
-			"constraint": pgErr.ConstraintName,
-			"detail":     pgErr.Detail,
-		})
+	var duplicate interface{}
+	if value != "" {
+		duplicate = value
 	}
-
-	// 无法识别字段，返回通用错误
-	return errors.ErrValidationFailed.WithDetails(map[string]interface{}{
-		"type":       "unique",
-		"message":    "字段值重复，请检查并修改",
+	return UniqueViolationError(field, duplicate, map[string]interface{}{
 		"constraint": pgErr.ConstraintName,
 		"detail":     pgErr.Detail,
 	})
@@ -196,28 +168,57 @@ func handleUniqueViolation(pgErr *pgconn.PgError, tableID string, fieldRepo fiel
 
 // handleNotNullViolation 处理非空约束违反
 func handleNotNullViolation(pgErr *pgconn.PgError, tableID string, fieldRepo fieldRepo.FieldRepository, ctx context.Context) error {
-	// 从错误消息中提取字段名称
-	fieldName := extractFieldNameFromMessage(pgErr.Message)
+	// 优先使用错误中的列名，再从错误消息中提取
+	fieldName := pgErr.ColumnName
+	if fieldName == "" {
+		fieldName = extractFieldNameFromMessage(pgErr.Message)
+	}
 
 	// 查询字段信息
 	field := findFieldByDBName(fieldName, tableID, fieldRepo, ctx)
 
+	return NotNullViolationError(field, map[string]interface{}{
+		"detail": pgErr.Message,
+	})
+}
+
+// UniqueViolationError 构造字段值重复错误（DUPLICATE_VALUE）
+// field 为空表示无法识别违反约束的字段；value 为重复的值（未知时为 nil）
+func UniqueViolationError(field *fieldEntity.Field, value interface{}, extra map[string]interface{}) error {
+	details := map[string]interface{}{
+		"type":    "unique",
+		"message": "字段值重复，请检查并修改",
+	}
 	if field != nil {
-		return errors.ErrValidationFailed.WithDetails(map[string]interface{}{
-			"type":       "notNull",
-			"message":    fmt.Sprintf("字段【%s】不能为空", field.Name().String()),
-			"field_id":   field.ID().String(),
-			"field_name": field.Name().String(),
-			"detail":     pgErr.Detail,
-		})
+		details["message"] = fmt.Sprintf("字段【%s】的值重复，请修改后重试", field.Name().String())
+		details["field_id"] = field.ID().String()
+		details["field_name"] = field.Name().String()
+	}
+	if value != nil {
+		details["value"] = value
 	}
+	for k, v := range extra {
+		details[k] = v
+	}
+	return errors.ErrDuplicateValue.WithDetails(details)
+}
 
-	// 无法识别字段，返回通用错误
-	return errors.ErrValidationFailed.WithDetails(map[string]interface{}{
+// NotNullViolationError 构造字段为空错误（FIELD_REQUIRED）
+// 写入前的非空检查与数据库非空约束共用，field 为空表示无法识别违反约束的字段
+func NotNullViolationError(field *fieldEntity.Field, extra map[string]interface{}) error {
+	details := map[string]interface{}{
 		"type":    "notNull",
 		"message": "必填字段不能为空",
-		"detail":  pgErr.Message,
-	})
+	}
+	if field != nil {
+		details["message"] = fmt.Sprintf("字段【%s】不能为空", field.Name().String())
+		details["field_id"] = field.ID().String()
+		details["field_name"] = field.Name().String()
+	}
+	for k, v := range extra {
+		details[k] = v
+	}
+	return errors.ErrFieldRequired.WithDetails(details)
 }
 
 // handleForeignKeyViolation 处理外键约束违反
@@ -270,6 +271,45 @@ func extractFieldNameFromConstraint(constraintName string) string {
 	return ""
 }
 
+// IsBlankValue 单元格或写入数据库的值是否为空（nil、空白字符串、空数组或 JSON null）
+// 唯一、非空约束字段的空值统一按 NULL 写入
+func IsBlankValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case datatypes.JSON:
+		return isBlankJSON(v)
+	case []byte:
+		return isBlankJSON(v)
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// isBlankJSON JSON 编码的值是否为空
+func isBlankJSON(data []byte) bool {
+	switch strings.TrimSpace(string(data)) {
+	case "", "null", "[]", `""`:
+		return true
+	}
+	return false
+}
+
+// uniqueDetailPattern PostgreSQL 唯一约束错误详情: Key (column)=(value) already exists.
+var uniqueDetailPattern = regexp.MustCompile(`^Key \("?([^")]+)"?\)=\((.*)\) already exists`)
+
+// extractKeyFromDetail 从唯一约束错误详情中提取列名和重复值
+func extractKeyFromDetail(detail string) (string, string) {
+	matches := uniqueDetailPattern.FindStringSubmatch(detail)
+	if len(matches) < 3 {
+		return "", ""
+	}
+	return matches[1], matches[2]
+}
+
 // extractFieldNameFromMessage 从错误消息中提取字段名
 // PostgreSQL 格式: column "field_name" violates not-null constraint
 func extractFieldNameFromMessage(message string) string {
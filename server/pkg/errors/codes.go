@@ -29,6 +29,15 @@ const (
 	CodeDuplicateValue    = 400109 // 重复值
 	CodeInvalidPattern    = 400110 // 格式不匹配
 	CodeFieldNotExists    = 400111 // 字段不存在于表中
+	CodeInvalidFilter     = 400112 // 过滤条件无效
+	CodeInvalidSort       = 400113 // 排序条件无效
+	CodeInvalidCursor     = 400114 // 分页游标无效
+	CodeInvalidGroup      = 400115 // 分组或聚合条件无效
+	CodeTooManyRecords    = 400116 // 匹配记录数超过安全上限
+
+	// 新增: 校验规则 (4001xx)
+	CodeFieldRuleViolation  = 400117 // 字段值不满足校验规则
+	CodeRecordRuleViolation = 400118 // 记录不满足表级校验规则
 
 	CodeUnauthorized       = 401000
 	CodeInvalidToken       = 401001
@@ -49,9 +58,10 @@ const (
 
 	CodeConflict = 409001
 	// 新增: 资源冲突细分 (409xxx)
-	CodeDuplicateField  = 409101 // 字段名重复
-	CodeDuplicateRecord = 409102 // 记录重复
-	CodeDuplicateView   = 409103 // 视图名重复
+	CodeDuplicateField     = 409101 // 字段名重复
+	CodeDuplicateRecord    = 409102 // 记录重复
+	CodeDuplicateView      = 409103 // 视图名重复
+	CodeConstraintConflict = 409104 // 现有数据不满足字段约束
 
 	CodeTooManyReq = 429001
 )
@@ -178,11 +188,21 @@ var stringToNumeric = map[string]int{
 	"DUPLICATE_VALUE":     CodeDuplicateValue,
 	"INVALID_PATTERN":     CodeInvalidPattern,
 	"FIELD_NOT_EXISTS":    CodeFieldNotExists,
+	"INVALID_FILTER":      CodeInvalidFilter,
+	"INVALID_SORT":        CodeInvalidSort,
+	"INVALID_CURSOR":      CodeInvalidCursor,
+	"INVALID_GROUP":       CodeInvalidGroup,
+	"TOO_MANY_RECORDS":    CodeTooManyRecords,
+
+	// 新增: 校验规则
+	"FIELD_RULE_VIOLATION":  CodeFieldRuleViolation,
+	"RECORD_RULE_VIOLATION": CodeRecordRuleViolation,
 
 	// 新增: 资源冲突
-	"DUPLICATE_FIELD":  CodeDuplicateField,
-	"DUPLICATE_RECORD": CodeDuplicateRecord,
-	"DUPLICATE_VIEW":   CodeDuplicateView,
+	"DUPLICATE_FIELD":           CodeDuplicateField,
+	"DUPLICATE_RECORD":          CodeDuplicateRecord,
+	"DUPLICATE_VIEW":            CodeDuplicateView,
+	"FIELD_CONSTRAINT_CONFLICT": CodeConstraintConflict,
 
 	// 业务
 	"OPERATION_NOT_ALLOWED": CodeForbidden,
@@ -1,6 +1,7 @@
 package errors
 
 import (
+	stderrors "errors"
 	"fmt"
 	"net/http"
 )
@@ -107,6 +108,11 @@ var (
 	ErrRecordNotFound    = New("RECORD_NOT_FOUND", "记录不存在", http.StatusNotFound)
 	ErrRecordExists      = New("RECORD_EXISTS", "记录已存在", http.StatusConflict)
 	ErrInvalidRecordData = New("INVALID_RECORD_DATA", "记录数据格式错误", http.StatusBadRequest)
+	ErrInvalidFilter     = New("INVALID_FILTER", "过滤条件无效", http.StatusBadRequest)
+	ErrInvalidSort       = New("INVALID_SORT", "排序条件无效", http.StatusBadRequest)
+	ErrInvalidCursor     = New("INVALID_CURSOR", "分页游标无效或已过期", http.StatusBadRequest)
+	ErrInvalidGroup      = New("INVALID_GROUP", "分组或聚合条件无效", http.StatusBadRequest)
+	ErrTooManyRecords    = New("TOO_MANY_RECORDS", "匹配的记录数超过安全上限", http.StatusBadRequest)
 
 	// 视图相关错误
 	ErrViewNotFound    = New("VIEW_NOT_FOUND", "视图不存在", http.StatusNotFound)
@@ -149,22 +155,25 @@ var (
 	ErrResourceExists   = New("RESOURCE_EXISTS", "资源已存在", http.StatusConflict)
 
 	// 新增: 字段验证错误
-	ErrFieldRequired     = New("FIELD_REQUIRED", "必填字段不能为空", http.StatusBadRequest)
-	ErrInvalidFieldValue = New("INVALID_FIELD_VALUE", "字段值无效", http.StatusBadRequest)
-	ErrFieldTypeMismatch = New("FIELD_TYPE_MISMATCH", "字段值类型不匹配", http.StatusBadRequest)
-	ErrFieldTooLong      = New("FIELD_TOO_LONG", "字段长度超出限制", http.StatusBadRequest)
-	ErrFieldOutOfRange   = New("FIELD_OUT_OF_RANGE", "字段值超出范围", http.StatusBadRequest)
-	ErrInvalidEmail      = New("INVALID_EMAIL", "邮箱格式不正确", http.StatusBadRequest)
-	ErrInvalidURL        = New("INVALID_URL", "URL格式不正确", http.StatusBadRequest)
-	ErrInvalidPhone      = New("INVALID_PHONE", "手机号格式不正确", http.StatusBadRequest)
-	ErrDuplicateValue    = New("DUPLICATE_VALUE", "字段值重复", http.StatusBadRequest)
-	ErrInvalidPattern    = New("INVALID_PATTERN", "格式不匹配", http.StatusBadRequest)
-	ErrFieldNotExists    = New("FIELD_NOT_EXISTS", "字段不存在", http.StatusBadRequest)
+	ErrFieldRequired       = New("FIELD_REQUIRED", "必填字段不能为空", http.StatusBadRequest)
+	ErrInvalidFieldValue   = New("INVALID_FIELD_VALUE", "字段值无效", http.StatusBadRequest)
+	ErrFieldTypeMismatch   = New("FIELD_TYPE_MISMATCH", "字段值类型不匹配", http.StatusBadRequest)
+	ErrFieldTooLong        = New("FIELD_TOO_LONG", "字段长度超出限制", http.StatusBadRequest)
+	ErrFieldOutOfRange     = New("FIELD_OUT_OF_RANGE", "字段值超出范围", http.StatusBadRequest)
+	ErrInvalidEmail        = New("INVALID_EMAIL", "邮箱格式不正确", http.StatusBadRequest)
+	ErrInvalidURL          = New("INVALID_URL", "URL格式不正确", http.StatusBadRequest)
+	ErrInvalidPhone        = New("INVALID_PHONE", "手机号格式不正确", http.StatusBadRequest)
+	ErrDuplicateValue      = New("DUPLICATE_VALUE", "字段值重复", http.StatusBadRequest)
+	ErrInvalidPattern      = New("INVALID_PATTERN", "格式不匹配", http.StatusBadRequest)
+	ErrFieldNotExists      = New("FIELD_NOT_EXISTS", "字段不存在", http.StatusBadRequest)
+	ErrFieldRuleViolation  = New("FIELD_RULE_VIOLATION", "字段值不满足校验规则", http.StatusBadRequest)
+	ErrRecordRuleViolation = New("RECORD_RULE_VIOLATION", "记录不满足校验规则", http.StatusBadRequest)
 
 	// 新增: 资源冲突错误
-	ErrDuplicateField  = New("DUPLICATE_FIELD", "字段名已存在", http.StatusConflict)
-	ErrDuplicateRecord = New("DUPLICATE_RECORD", "记录已存在", http.StatusConflict)
-	ErrDuplicateView   = New("DUPLICATE_VIEW", "视图名已存在", http.StatusConflict)
+	ErrDuplicateField     = New("DUPLICATE_FIELD", "字段名已存在", http.StatusConflict)
+	ErrDuplicateRecord    = New("DUPLICATE_RECORD", "记录已存在", http.StatusConflict)
+	ErrDuplicateView      = New("DUPLICATE_VIEW", "视图名已存在", http.StatusConflict)
+	ErrConstraintConflict = New("FIELD_CONSTRAINT_CONFLICT", "现有数据不满足字段约束", http.StatusConflict)
 
 	// 业务逻辑错误
 	ErrOperationNotAllowed = New("OPERATION_NOT_ALLOWED", "不允许此操作", http.StatusForbidden)
@@ -205,9 +214,10 @@ func Wrap(err error, code, message string, httpStatus int) *AppError {
 	return appErr
 }
 
-// IsAppError 检查是否为应用错误
+// IsAppError 检查是否为应用错误（包括经 fmt.Errorf("%w") 包装的应用错误）
 func IsAppError(err error) (*AppError, bool) {
-	if appErr, ok := err.(*AppError); ok {
+	var appErr *AppError
+	if stderrors.As(err, &appErr) {
 		return appErr, true
 	}
 	return nil, false
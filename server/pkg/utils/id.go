@@ -31,6 +31,9 @@ const (
 	AttachmentIDPrefix = "att"
 	TokenIDPrefix      = "tkn"
 	SessionIDPrefix    = "ses"
+	TaskIDPrefix       = "tsk"
+	TaskRunIDPrefix    = "tsr"
+	RecordRuleIDPrefix = "rrl"
 )
 
 // IDGenerator ID生成器接口